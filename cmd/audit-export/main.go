@@ -0,0 +1,77 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"flag"
+	"io"
+	"log"
+	"os"
+	"time"
+
+	"github.com/PabloPavan/jaiu/internal/adapter/postgres"
+	"github.com/PabloPavan/jaiu/internal/service"
+)
+
+func main() {
+	var (
+		from    = flag.String("from", "", "Data inicial (YYYY-MM-DD)")
+		to      = flag.String("to", "", "Data final inclusiva (YYYY-MM-DD)")
+		format  = flag.String("format", "csv", "Formato (csv/ndjson)")
+		output  = flag.String("out", "", "Arquivo de saida (padrao: stdout)")
+		timeout = flag.Duration("timeout", 10*time.Minute, "Tempo maximo da exportacao")
+	)
+	flag.Parse()
+
+	if *from == "" || *to == "" {
+		log.Fatal("from and to are required")
+	}
+	start, err := time.ParseInLocation("2006-01-02", *from, time.Local)
+	if err != nil {
+		log.Fatalf("invalid from date: %v", err)
+	}
+	end, err := time.ParseInLocation("2006-01-02", *to, time.Local)
+	if err != nil {
+		log.Fatalf("invalid to date: %v", err)
+	}
+	exportFormat, err := service.ParseAuditExportFormat(*format)
+	if err != nil {
+		log.Fatal("invalid format: use csv or ndjson")
+	}
+
+	databaseURL := os.Getenv("DATABASE_URL")
+	if databaseURL == "" {
+		log.Fatal("DATABASE_URL is not set")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), *timeout)
+	defer cancel()
+
+	pool, err := postgres.NewPool(ctx, databaseURL)
+	if err != nil {
+		log.Fatalf("connect db failed: %v", err)
+	}
+	defer pool.Close()
+
+	var w io.Writer = os.Stdout
+	if *output != "" {
+		file, err := os.Create(*output)
+		if err != nil {
+			log.Fatalf("create output failed: %v", err)
+		}
+		defer file.Close()
+		w = file
+	}
+	buffered := bufio.NewWriter(w)
+
+	exporter := service.NewAuditExporter(postgres.NewAuditRepository(pool))
+	count, err := exporter.Export(ctx, buffered, exportFormat, start, end.AddDate(0, 0, 1))
+	if err != nil {
+		log.Fatalf("export audit events failed: %v", err)
+	}
+	if err := buffered.Flush(); err != nil {
+		log.Fatalf("write output failed: %v", err)
+	}
+
+	log.Printf("audit events exported: %d (%s to %s)", count, *from, *to)
+}
@@ -0,0 +1,41 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"log"
+	"os"
+	"time"
+
+	"github.com/PabloPavan/jaiu/internal/adapter/postgres"
+)
+
+func main() {
+	timeout := flag.Duration("timeout", 10*time.Minute, "Tempo maximo da verificacao")
+	flag.Parse()
+
+	databaseURL := os.Getenv("DATABASE_URL")
+	if databaseURL == "" {
+		log.Fatal("DATABASE_URL is not set")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), *timeout)
+	defer cancel()
+
+	pool, err := postgres.NewPool(ctx, databaseURL)
+	if err != nil {
+		log.Fatalf("connect db failed: %v", err)
+	}
+	defer pool.Close()
+
+	report, err := postgres.NewAuditRepository(pool).VerifyChain(ctx)
+	if err != nil {
+		log.Fatalf("verify audit chain failed: %v", err)
+	}
+
+	if report.Broken {
+		log.Printf("audit chain broken at seq %d (event %q): %s", report.BrokenSeq, report.BrokenID, report.Reason)
+		os.Exit(1)
+	}
+	log.Printf("audit chain ok: %d events checked, head seq %d, %d legacy events without chain", report.Checked, report.HeadSeq, report.Legacy)
+}
@@ -31,13 +31,19 @@ func main() {
 	}
 
 	cfg := app.Config{
-		Addr:           envOr("ADDR", ":8080"),
-		DatabaseURL:    os.Getenv("DATABASE_URL"),
-		RedisAddr:      os.Getenv("REDIS_ADDR"),
-		RedisPassword:  os.Getenv("REDIS_PASSWORD"),
-		RedisDB:        envInt("REDIS_DB", 0),
-		ImageUploadDir: os.Getenv("IMAGE_UPLOAD_DIR"),
-		Context:        ctx,
+		Addr:              envOr("ADDR", ":8080"),
+		DatabaseURL:       os.Getenv("DATABASE_URL"),
+		RedisAddr:         os.Getenv("REDIS_ADDR"),
+		RedisPassword:     os.Getenv("REDIS_PASSWORD"),
+		RedisDB:           envInt("REDIS_DB", 0),
+		ImageUploadDir:    os.Getenv("IMAGE_UPLOAD_DIR"),
+		SessionTTL:        envDuration("SESSION_MAX_LIFETIME", 0),
+		SessionIdleTTL:    envDuration("SESSION_IDLE_TIMEOUT", 0),
+		BaseURL:           os.Getenv("APP_BASE_URL"),
+		NotificationFile:  os.Getenv("NOTIFICATION_FILE"),
+		MFARequireAdmin:   envBool("MFA_REQUIRE_ADMIN", false),
+		StudentViewWindow: envDuration("STUDENT_VIEW_AUDIT_WINDOW", 0),
+		Context:           ctx,
 	}
 
 	application, err := app.New(cfg)
@@ -103,3 +109,23 @@ func envInt(key string, fallback int) int {
 	}
 	return fallback
 }
+
+func envBool(key string, fallback bool) bool {
+	if value := os.Getenv(key); value != "" {
+		parsed, err := strconv.ParseBool(value)
+		if err == nil {
+			return parsed
+		}
+	}
+	return fallback
+}
+
+func envDuration(key string, fallback time.Duration) time.Duration {
+	if value := os.Getenv(key); value != "" {
+		parsed, err := time.ParseDuration(value)
+		if err == nil {
+			return parsed
+		}
+	}
+	return fallback
+}
@@ -25,10 +25,10 @@ INSERT INTO payments (id, subscription_id, paid_at, amount_cents, method, refere
 VALUES
   ('55555555-5555-5555-5555-555555555555', '33333333-3333-3333-3333-333333333333', '2024-01-02T10:00:00Z', 1000, 'cash', 'ref-1', 'note-1', 'confirmed', 'full', 0, 'idem-1');
 
-INSERT INTO billing_periods (id, subscription_id, period_start, period_end, amount_due_cents, amount_paid_cents, status)
+INSERT INTO billing_periods (id, subscription_id, period_start, period_end, amount_due_cents, amount_paid_cents, status, due_date)
 VALUES
-  ('66666666-6666-6666-6666-666666666666', '33333333-3333-3333-3333-333333333333', '2024-01-01', '2024-01-31', 1000, 1000, 'paid'),
-  ('88888888-8888-8888-8888-888888888888', '33333333-3333-3333-3333-333333333333', '2024-02-01', '2024-02-29', 1000, 0, 'open');
+  ('66666666-6666-6666-6666-666666666666', '33333333-3333-3333-3333-333333333333', '2024-01-01', '2024-01-31', 1000, 1000, 'paid', '2024-01-01'),
+  ('88888888-8888-8888-8888-888888888888', '33333333-3333-3333-3333-333333333333', '2024-02-01', '2024-02-29', 1000, 0, 'open', '2024-02-01');
 
 INSERT INTO payment_allocations (payment_id, billing_period_id, amount_cents)
 VALUES ('55555555-5555-5555-5555-555555555555', '66666666-6666-6666-6666-666666666666', 1000);
@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS password_reset_tokens;
+
+ALTER TABLE users
+  DROP COLUMN IF EXISTS must_change_password;
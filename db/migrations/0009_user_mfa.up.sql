@@ -0,0 +1,24 @@
+CREATE TABLE user_mfa (
+  user_id uuid PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+  secret text NOT NULL,
+  enabled_at timestamptz,
+  last_used_step bigint NOT NULL DEFAULT 0,
+  created_at timestamptz NOT NULL DEFAULT now(),
+  updated_at timestamptz NOT NULL DEFAULT now()
+);
+
+CREATE TABLE user_recovery_codes (
+  id uuid PRIMARY KEY DEFAULT gen_random_uuid(),
+  user_id uuid NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+  code_hash text NOT NULL,
+  used_at timestamptz,
+  created_at timestamptz NOT NULL DEFAULT now(),
+  UNIQUE (user_id, code_hash)
+);
+
+CREATE INDEX user_recovery_codes_user_idx ON user_recovery_codes (user_id);
+
+CREATE TRIGGER user_mfa_updated_at
+  BEFORE UPDATE ON user_mfa
+  FOR EACH ROW
+  EXECUTE FUNCTION set_updated_at();
@@ -0,0 +1 @@
+DROP INDEX IF EXISTS audit_events_created_at_idx;
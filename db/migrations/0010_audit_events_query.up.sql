@@ -0,0 +1 @@
+CREATE INDEX IF NOT EXISTS audit_events_created_at_idx ON audit_events (created_at);
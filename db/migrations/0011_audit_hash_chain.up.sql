@@ -0,0 +1,15 @@
+ALTER TABLE audit_events
+  ADD COLUMN seq bigint,
+  ADD COLUMN prev_hash bytea,
+  ADD COLUMN hash bytea;
+
+CREATE UNIQUE INDEX audit_events_seq_idx ON audit_events (seq);
+
+CREATE TABLE audit_chain_head (
+  id smallint PRIMARY KEY DEFAULT 1 CHECK (id = 1),
+  seq bigint NOT NULL DEFAULT 0,
+  hash bytea NOT NULL DEFAULT ''::bytea,
+  updated_at timestamptz NOT NULL DEFAULT now()
+);
+
+INSERT INTO audit_chain_head (id) VALUES (1);
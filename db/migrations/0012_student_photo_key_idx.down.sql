@@ -0,0 +1 @@
+DROP INDEX IF EXISTS students_photo_object_key_idx;
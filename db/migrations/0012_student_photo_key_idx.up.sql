@@ -0,0 +1 @@
+CREATE INDEX IF NOT EXISTS students_photo_object_key_idx ON students (photo_object_key);
@@ -0,0 +1,8 @@
+ALTER TABLE billing_periods DROP COLUMN IF EXISTS fee_waived_at;
+ALTER TABLE billing_periods DROP COLUMN IF EXISTS fee_accrued_on;
+ALTER TABLE billing_periods DROP COLUMN IF EXISTS fee_cents;
+
+ALTER TABLE plans DROP CONSTRAINT IF EXISTS plans_late_interest_bps_check;
+ALTER TABLE plans DROP CONSTRAINT IF EXISTS plans_late_fee_bps_check;
+ALTER TABLE plans DROP COLUMN IF EXISTS late_interest_bps;
+ALTER TABLE plans DROP COLUMN IF EXISTS late_fee_bps;
@@ -0,0 +1,9 @@
+ALTER TABLE plans ADD COLUMN late_fee_bps integer NOT NULL DEFAULT 0;
+ALTER TABLE plans ADD COLUMN late_interest_bps integer NOT NULL DEFAULT 0;
+
+ALTER TABLE plans ADD CONSTRAINT plans_late_fee_bps_check CHECK (late_fee_bps BETWEEN 0 AND 10000);
+ALTER TABLE plans ADD CONSTRAINT plans_late_interest_bps_check CHECK (late_interest_bps BETWEEN 0 AND 10000);
+
+ALTER TABLE billing_periods ADD COLUMN fee_cents bigint NOT NULL DEFAULT 0;
+ALTER TABLE billing_periods ADD COLUMN fee_accrued_on date;
+ALTER TABLE billing_periods ADD COLUMN fee_waived_at timestamptz;
@@ -0,0 +1,9 @@
+ALTER TABLE billing_periods DROP COLUMN IF EXISTS discount_cents;
+
+ALTER TABLE subscriptions DROP COLUMN IF EXISTS discount_periods;
+ALTER TABLE subscriptions DROP COLUMN IF EXISTS discount_duration;
+ALTER TABLE subscriptions DROP COLUMN IF EXISTS discount_value;
+ALTER TABLE subscriptions DROP COLUMN IF EXISTS discount_kind;
+ALTER TABLE subscriptions DROP COLUMN IF EXISTS coupon_id;
+
+DROP TABLE IF EXISTS coupons;
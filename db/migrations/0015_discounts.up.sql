@@ -0,0 +1,27 @@
+CREATE TABLE coupons (
+  id uuid PRIMARY KEY DEFAULT gen_random_uuid(),
+  code text NOT NULL,
+  description text,
+  discount_kind text NOT NULL CHECK (discount_kind IN ('percent', 'fixed')),
+  discount_value bigint NOT NULL CHECK (discount_value > 0),
+  discount_duration text NOT NULL CHECK (discount_duration IN ('once', 'repeating', 'forever')),
+  discount_periods integer NOT NULL DEFAULT 0 CHECK (discount_periods >= 0),
+  active boolean NOT NULL DEFAULT true,
+  created_at timestamptz NOT NULL DEFAULT now(),
+  updated_at timestamptz NOT NULL DEFAULT now()
+);
+
+CREATE UNIQUE INDEX coupons_code_idx ON coupons (lower(code));
+
+CREATE TRIGGER coupons_updated_at
+  BEFORE UPDATE ON coupons
+  FOR EACH ROW
+  EXECUTE FUNCTION set_updated_at();
+
+ALTER TABLE subscriptions ADD COLUMN coupon_id uuid REFERENCES coupons(id) ON DELETE SET NULL;
+ALTER TABLE subscriptions ADD COLUMN discount_kind text CHECK (discount_kind IN ('percent', 'fixed'));
+ALTER TABLE subscriptions ADD COLUMN discount_value bigint NOT NULL DEFAULT 0;
+ALTER TABLE subscriptions ADD COLUMN discount_duration text CHECK (discount_duration IN ('once', 'repeating', 'forever'));
+ALTER TABLE subscriptions ADD COLUMN discount_periods integer NOT NULL DEFAULT 0;
+
+ALTER TABLE billing_periods ADD COLUMN discount_cents bigint NOT NULL DEFAULT 0;
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS subscriptions_previous_idx;
+
+ALTER TABLE subscriptions DROP COLUMN IF EXISTS previous_subscription_id;
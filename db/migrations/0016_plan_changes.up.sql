@@ -0,0 +1,3 @@
+ALTER TABLE subscriptions ADD COLUMN previous_subscription_id uuid REFERENCES subscriptions(id) ON DELETE SET NULL;
+
+CREATE INDEX subscriptions_previous_idx ON subscriptions (previous_subscription_id);
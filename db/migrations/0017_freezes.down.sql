@@ -0,0 +1,6 @@
+ALTER TABLE billing_periods DROP COLUMN IF EXISTS frozen_days;
+ALTER TABLE billing_periods DROP COLUMN IF EXISTS shift_days;
+
+DROP TABLE IF EXISTS subscription_freezes;
+
+ALTER TABLE plans DROP COLUMN IF EXISTS max_freeze_days;
@@ -0,0 +1,16 @@
+ALTER TABLE plans ADD COLUMN max_freeze_days integer NOT NULL DEFAULT 0 CHECK (max_freeze_days >= 0);
+
+CREATE TABLE subscription_freezes (
+  id uuid PRIMARY KEY DEFAULT gen_random_uuid(),
+  subscription_id uuid NOT NULL REFERENCES subscriptions(id),
+  start_date date NOT NULL,
+  end_date date NOT NULL,
+  reason text NOT NULL,
+  created_at timestamptz NOT NULL DEFAULT now(),
+  CHECK (end_date >= start_date)
+);
+
+CREATE INDEX subscription_freezes_subscription_idx ON subscription_freezes (subscription_id, start_date);
+
+ALTER TABLE billing_periods ADD COLUMN shift_days integer NOT NULL DEFAULT 0;
+ALTER TABLE billing_periods ADD COLUMN frozen_days integer NOT NULL DEFAULT 0;
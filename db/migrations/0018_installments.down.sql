@@ -0,0 +1,5 @@
+ALTER TABLE billing_periods DROP CONSTRAINT IF EXISTS billing_periods_installment_check;
+ALTER TABLE billing_periods DROP COLUMN IF EXISTS installments;
+ALTER TABLE billing_periods DROP COLUMN IF EXISTS installment;
+
+ALTER TABLE plans DROP COLUMN IF EXISTS installments;
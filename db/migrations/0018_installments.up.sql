@@ -0,0 +1,5 @@
+ALTER TABLE plans ADD COLUMN installments integer NOT NULL DEFAULT 1 CHECK (installments BETWEEN 1 AND 12);
+
+ALTER TABLE billing_periods ADD COLUMN installment integer NOT NULL DEFAULT 1;
+ALTER TABLE billing_periods ADD COLUMN installments integer NOT NULL DEFAULT 1;
+ALTER TABLE billing_periods ADD CONSTRAINT billing_periods_installment_check CHECK (installment BETWEEN 1 AND installments);
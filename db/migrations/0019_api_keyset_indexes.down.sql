@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS billing_periods_subscription_created_idx;
+DROP INDEX IF EXISTS payments_subscription_created_idx;
+DROP INDEX IF EXISTS subscription_freezes_subscription_created_idx;
+DROP INDEX IF EXISTS subscriptions_student_created_idx;
+DROP INDEX IF EXISTS plans_active_created_idx;
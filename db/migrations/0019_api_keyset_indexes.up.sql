@@ -0,0 +1,5 @@
+CREATE INDEX plans_active_created_idx ON plans (created_at, id) WHERE active;
+CREATE INDEX subscriptions_student_created_idx ON subscriptions (student_id, created_at, id);
+CREATE INDEX subscription_freezes_subscription_created_idx ON subscription_freezes (subscription_id, created_at, id);
+CREATE INDEX payments_subscription_created_idx ON payments (subscription_id, created_at, id);
+CREATE INDEX billing_periods_subscription_created_idx ON billing_periods (subscription_id, created_at, id);
@@ -0,0 +1 @@
+ALTER TABLE billing_periods DROP COLUMN IF EXISTS interest_cent_days;
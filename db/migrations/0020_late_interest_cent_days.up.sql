@@ -0,0 +1 @@
+ALTER TABLE billing_periods ADD COLUMN interest_cent_days bigint NOT NULL DEFAULT 0;
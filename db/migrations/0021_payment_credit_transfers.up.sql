@@ -0,0 +1,8 @@
+CREATE TABLE payment_credit_transfers (
+  payment_id uuid NOT NULL REFERENCES payments(id) ON DELETE CASCADE,
+  subscription_id uuid NOT NULL REFERENCES subscriptions(id) ON DELETE CASCADE,
+  amount_cents bigint NOT NULL CHECK (amount_cents > 0),
+  credit_cents bigint NOT NULL DEFAULT 0 CHECK (credit_cents BETWEEN 0 AND amount_cents),
+  created_at timestamptz NOT NULL DEFAULT now(),
+  PRIMARY KEY (payment_id, subscription_id)
+);
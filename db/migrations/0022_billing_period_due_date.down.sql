@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS billing_periods_open_due_date_idx;
+ALTER TABLE billing_periods DROP COLUMN IF EXISTS due_date;
@@ -0,0 +1,38 @@
+ALTER TABLE billing_periods ADD COLUMN due_date date;
+
+-- Preenche as competencias existentes com a regra de periodDueDate; daqui em
+-- diante o vencimento e gravado pela aplicacao.
+UPDATE billing_periods
+SET due_date = d.due_date
+FROM (
+  SELECT bp.id,
+    CASE WHEN c.this_due >= a.anchor THEN c.this_due ELSE c.next_due END
+      + bp.shift_days + bp.frozen_days AS due_date
+  FROM billing_periods bp
+  JOIN subscriptions s ON s.id = bp.subscription_id
+  CROSS JOIN LATERAL (
+    SELECT bp.period_start - bp.shift_days AS anchor,
+      COALESCE(NULLIF(s.payment_day, 0), EXTRACT(DAY FROM s.start_date)::int) AS payment_day
+  ) a
+  CROSS JOIN LATERAL (
+    SELECT date_trunc('month', a.anchor)::date AS this_month,
+      (date_trunc('month', a.anchor) + INTERVAL '1 month')::date AS next_month
+  ) m
+  CROSS JOIN LATERAL (
+    SELECT make_date(
+        EXTRACT(YEAR FROM m.this_month)::int,
+        EXTRACT(MONTH FROM m.this_month)::int,
+        LEAST(a.payment_day, EXTRACT(DAY FROM m.next_month - 1)::int)
+      ) AS this_due,
+      make_date(
+        EXTRACT(YEAR FROM m.next_month)::int,
+        EXTRACT(MONTH FROM m.next_month)::int,
+        LEAST(a.payment_day, EXTRACT(DAY FROM (m.next_month + INTERVAL '1 month')::date - 1)::int)
+      ) AS next_due
+  ) c
+) d
+WHERE billing_periods.id = d.id;
+
+ALTER TABLE billing_periods ALTER COLUMN due_date SET NOT NULL;
+
+CREATE INDEX billing_periods_open_due_date_idx ON billing_periods (due_date) WHERE status IN ('open', 'partial');
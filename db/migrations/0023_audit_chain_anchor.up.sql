@@ -0,0 +1,16 @@
+CREATE TABLE audit_chain_anchor (
+  id smallint PRIMARY KEY DEFAULT 1 CHECK (id = 1),
+  seq bigint NOT NULL DEFAULT 0,
+  hash bytea NOT NULL DEFAULT ''::bytea,
+  updated_at timestamptz NOT NULL DEFAULT now()
+);
+
+-- Cadeias ja podadas pela retencao passam a ser verificadas a partir do
+-- primeiro evento que restou; sem eventos, a ancora e o proprio topo.
+INSERT INTO audit_chain_anchor (id, seq, hash)
+SELECT 1, first.seq - 1, COALESCE(first.prev_hash, ''::bytea)
+FROM (SELECT seq, prev_hash FROM audit_events WHERE seq IS NOT NULL ORDER BY seq LIMIT 1) first
+UNION ALL
+SELECT 1, h.seq, h.hash
+FROM audit_chain_head h
+WHERE h.id = 1 AND NOT EXISTS (SELECT 1 FROM audit_events WHERE seq IS NOT NULL);
@@ -0,0 +1 @@
+DROP INDEX IF EXISTS students_created_idx;
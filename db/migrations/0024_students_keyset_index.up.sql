@@ -0,0 +1 @@
+CREATE INDEX students_created_idx ON students (created_at, id);
@@ -0,0 +1,48 @@
+-- name: CreateAPIToken :one
+INSERT INTO api_tokens (
+  user_id,
+  name,
+  token_prefix,
+  token_hash,
+  scopes,
+  expires_at,
+  created_by
+) VALUES (
+  $1, $2, $3, $4, $5, $6, $7
+)
+RETURNING *;
+
+-- name: GetAPITokenByHash :one
+SELECT *
+FROM api_tokens
+WHERE token_hash = $1;
+
+-- name: ListAPITokens :many
+SELECT
+  t.id,
+  t.user_id,
+  t.name,
+  t.token_prefix,
+  t.token_hash,
+  t.scopes,
+  t.expires_at,
+  t.last_used_at,
+  t.revoked_at,
+  t.created_by,
+  t.created_at,
+  u.name AS user_name,
+  u.email AS user_email
+FROM api_tokens t
+JOIN users u ON u.id = t.user_id
+ORDER BY t.revoked_at IS NOT NULL, t.created_at DESC;
+
+-- name: RevokeAPIToken :execrows
+UPDATE api_tokens
+SET revoked_at = $2
+WHERE id = $1
+  AND revoked_at IS NULL;
+
+-- name: TouchAPIToken :exec
+UPDATE api_tokens
+SET last_used_at = $2
+WHERE id = $1;
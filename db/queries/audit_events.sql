@@ -0,0 +1,118 @@
+-- name: SearchAuditEvents :many
+SELECT *
+FROM audit_events
+WHERE (sqlc.narg(actor_id)::uuid IS NULL OR actor_id = sqlc.narg(actor_id)::uuid)
+  AND (sqlc.arg(entity_type)::text = '' OR entity_type = sqlc.arg(entity_type)::text)
+  AND (sqlc.narg(entity_id)::uuid IS NULL OR entity_id = sqlc.narg(entity_id)::uuid)
+  AND (sqlc.arg(action_prefix)::text = '' OR starts_with(action, sqlc.arg(action_prefix)::text))
+  AND (sqlc.narg(created_from)::timestamptz IS NULL OR created_at >= sqlc.narg(created_from)::timestamptz)
+  AND (sqlc.narg(created_to)::timestamptz IS NULL OR created_at < sqlc.narg(created_to)::timestamptz)
+ORDER BY created_at DESC, id DESC
+LIMIT sqlc.arg(row_limit) OFFSET sqlc.arg(row_offset);
+
+-- name: CountAuditEvents :one
+SELECT COUNT(*)
+FROM audit_events
+WHERE (sqlc.narg(actor_id)::uuid IS NULL OR actor_id = sqlc.narg(actor_id)::uuid)
+  AND (sqlc.arg(entity_type)::text = '' OR entity_type = sqlc.arg(entity_type)::text)
+  AND (sqlc.narg(entity_id)::uuid IS NULL OR entity_id = sqlc.narg(entity_id)::uuid)
+  AND (sqlc.arg(action_prefix)::text = '' OR starts_with(action, sqlc.arg(action_prefix)::text))
+  AND (sqlc.narg(created_from)::timestamptz IS NULL OR created_at >= sqlc.narg(created_from)::timestamptz)
+  AND (sqlc.narg(created_to)::timestamptz IS NULL OR created_at < sqlc.narg(created_to)::timestamptz);
+
+-- name: LockAuditChainHead :one
+SELECT seq, hash FROM audit_chain_head WHERE id = 1 FOR UPDATE;
+
+-- name: GetAuditChainHead :one
+SELECT seq, hash FROM audit_chain_head WHERE id = 1;
+
+-- name: GetAuditChainAnchor :one
+SELECT seq, hash FROM audit_chain_anchor WHERE id = 1;
+
+-- name: LockAuditChainAnchor :one
+SELECT seq, hash FROM audit_chain_anchor WHERE id = 1 FOR UPDATE;
+
+-- name: SetAuditChainAnchor :exec
+UPDATE audit_chain_anchor SET seq = $1, hash = $2, updated_at = now() WHERE id = 1;
+
+-- name: InsertChainedAuditEvent :one
+INSERT INTO audit_events (
+  actor_id,
+  actor_role,
+  action,
+  entity_type,
+  entity_id,
+  metadata,
+  ip,
+  user_agent,
+  seq,
+  prev_hash
+) VALUES (
+  $1, $2, $3, $4, $5, $6, $7, $8, $9, $10
+)
+RETURNING id, metadata::text AS metadata_text, created_at;
+
+-- name: SetAuditEventHash :exec
+UPDATE audit_events SET hash = $2 WHERE id = $1;
+
+-- name: AdvanceAuditChainHead :exec
+UPDATE audit_chain_head SET seq = $1, hash = $2, updated_at = now() WHERE id = 1;
+
+-- name: ListAuditChain :many
+SELECT id, actor_id, actor_role, action, entity_type, entity_id, metadata::text AS metadata_text, ip, user_agent, created_at, seq, prev_hash, hash
+FROM audit_events
+WHERE seq > $1
+ORDER BY seq
+LIMIT $2;
+
+-- name: CountUnchainedAuditEvents :one
+SELECT COUNT(*) FROM audit_events WHERE seq IS NULL;
+
+-- name: GetOldestAuditEventAt :one
+SELECT MIN(created_at)::timestamptz AS oldest FROM audit_events;
+
+-- name: ListAuditEventsRange :many
+SELECT *
+FROM audit_events
+WHERE created_at >= sqlc.arg(created_from)::timestamptz
+  AND created_at < sqlc.arg(created_to)::timestamptz
+  AND (sqlc.narg(after_created_at)::timestamptz IS NULL
+    OR (created_at, id) > (sqlc.narg(after_created_at)::timestamptz, sqlc.narg(after_id)::uuid))
+ORDER BY created_at, id
+LIMIT sqlc.arg(row_limit);
+
+-- name: GetAuditChainPrefixEnd :one
+SELECT LEAST(
+  sqlc.arg(max_seq)::bigint,
+  COALESCE(
+    (SELECT MIN(seq) - 1 FROM audit_events WHERE seq IS NOT NULL AND created_at >= sqlc.arg(created_to)::timestamptz),
+    sqlc.arg(max_seq)::bigint
+  )
+)::bigint AS prefix_end;
+
+-- name: GetAuditEventHashBySeq :one
+SELECT hash FROM audit_events WHERE seq = $1;
+
+-- name: DeleteAuditChainPrefix :execrows
+DELETE FROM audit_events WHERE seq <= $1;
+
+-- name: DeleteUnchainedAuditEventsRange :execrows
+DELETE FROM audit_events
+WHERE seq IS NULL
+  AND created_at >= sqlc.arg(created_from)::timestamptz
+  AND created_at < sqlc.arg(created_to)::timestamptz;
+
+-- name: SummarizeAuditAccess :many
+SELECT
+  actor_id,
+  actor_role,
+  COUNT(*) AS views,
+  array_remove(array_agg(DISTINCT metadata->>'source'), NULL)::text[] AS sources,
+  MIN(created_at)::timestamptz AS first_at,
+  MAX(created_at)::timestamptz AS last_at
+FROM audit_events
+WHERE action = sqlc.arg(action)
+  AND entity_type = sqlc.arg(entity_type)
+  AND entity_id = sqlc.arg(entity_id)
+GROUP BY actor_id, actor_role
+ORDER BY last_at DESC;
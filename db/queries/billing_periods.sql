@@ -5,9 +5,14 @@ INSERT INTO billing_periods (
   period_end,
   amount_due_cents,
   amount_paid_cents,
-  status
+  status,
+  discount_cents,
+  shift_days,
+  installment,
+  installments,
+  due_date
 ) VALUES (
-  $1, $2, $3, $4, $5, $6
+  $1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11
 )
 RETURNING *;
 
@@ -16,6 +21,17 @@ UPDATE billing_periods
 SET
   amount_paid_cents = $2,
   status = $3,
+  fee_cents = $4,
+  fee_accrued_on = $5,
+  fee_waived_at = $6,
+  period_end = $7,
+  amount_due_cents = $8,
+  discount_cents = $9,
+  period_start = $10,
+  shift_days = $11,
+  frozen_days = $12,
+  interest_cent_days = $13,
+  due_date = $14,
   updated_at = now()
 WHERE id = $1
 RETURNING *;
@@ -23,6 +39,15 @@ RETURNING *;
 -- name: ListBillingPeriodsBySubscription :many
 SELECT * FROM billing_periods WHERE subscription_id = $1 ORDER BY period_start;
 
+-- name: ListBillingPeriodsBySubscriptionPage :many
+SELECT *
+FROM billing_periods
+WHERE subscription_id = sqlc.arg(subscription_id)
+  AND (sqlc.narg(after_created_at)::timestamptz IS NULL
+    OR (created_at, id) > (sqlc.narg(after_created_at)::timestamptz, sqlc.narg(after_id)::uuid))
+ORDER BY created_at, id
+LIMIT sqlc.arg(row_limit);
+
 -- name: ListOpenBillingPeriodsBySubscription :many
 SELECT *
 FROM billing_periods
@@ -0,0 +1,30 @@
+-- name: CreateCoupon :one
+INSERT INTO coupons (
+  code,
+  description,
+  discount_kind,
+  discount_value,
+  discount_duration,
+  discount_periods,
+  active
+) VALUES (
+  $1, $2, $3, $4, $5, $6, $7
+)
+RETURNING *;
+
+-- name: SetCouponActive :one
+UPDATE coupons
+SET
+  active = $2,
+  updated_at = now()
+WHERE id = $1
+RETURNING *;
+
+-- name: GetCoupon :one
+SELECT * FROM coupons WHERE id = $1 LIMIT 1;
+
+-- name: GetCouponByCode :one
+SELECT * FROM coupons WHERE lower(code) = lower($1) LIMIT 1;
+
+-- name: ListCoupons :many
+SELECT * FROM coupons ORDER BY active DESC, code;
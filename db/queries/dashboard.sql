@@ -0,0 +1,22 @@
+-- name: CountActiveStudents :one
+SELECT COUNT(*)::bigint AS total
+FROM students
+WHERE status = 'active';
+
+-- name: CountOverdueSubscriptions :one
+SELECT COUNT(DISTINCT bp.subscription_id)::bigint AS total
+FROM billing_periods bp
+JOIN subscriptions s ON s.id = bp.subscription_id
+WHERE bp.status = 'overdue'
+  AND s.status = 'active';
+
+-- name: OutstandingDueByPeriod :one
+-- due_date e gravado pela cobranca (periodDueDate) a cada alteracao da
+-- competencia.
+SELECT COALESCE(SUM(bp.amount_due_cents + bp.fee_cents - bp.amount_paid_cents), 0)::bigint AS total_cents
+FROM billing_periods bp
+JOIN subscriptions s ON s.id = bp.subscription_id
+WHERE bp.status IN ('open', 'partial')
+  AND s.status = 'active'
+  AND bp.due_date >= $1::date
+  AND bp.due_date < $2::date;
@@ -7,3 +7,11 @@ SELECT * FROM payment_allocations WHERE payment_id = $1 ORDER BY created_at;
 
 -- name: DeletePaymentAllocationsByPayment :exec
 DELETE FROM payment_allocations WHERE payment_id = $1;
+
+-- name: ListPaymentAllocationsByBillingPeriod :many
+SELECT * FROM payment_allocations WHERE billing_period_id = $1 ORDER BY created_at;
+
+-- name: UpdatePaymentAllocationAmount :exec
+UPDATE payment_allocations
+SET amount_cents = $3
+WHERE payment_id = $1 AND billing_period_id = $2;
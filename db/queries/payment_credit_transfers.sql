@@ -0,0 +1,9 @@
+-- name: CreatePaymentCreditTransfer :exec
+INSERT INTO payment_credit_transfers (payment_id, subscription_id, amount_cents, credit_cents)
+VALUES ($1, $2, $3, $4);
+
+-- name: ListPaymentCreditTransfersByPayment :many
+SELECT * FROM payment_credit_transfers WHERE payment_id = $1 ORDER BY created_at;
+
+-- name: DeletePaymentCreditTransfersByPayment :exec
+DELETE FROM payment_credit_transfers WHERE payment_id = $1;
@@ -39,8 +39,27 @@ SELECT * FROM payments WHERE idempotency_key = $1 LIMIT 1;
 -- name: ListPaymentsBySubscription :many
 SELECT * FROM payments WHERE subscription_id = $1 ORDER BY paid_at DESC;
 
+-- name: ListPaymentsBySubscriptionPage :many
+SELECT *
+FROM payments
+WHERE subscription_id = sqlc.arg(subscription_id)
+  AND (sqlc.narg(after_created_at)::timestamptz IS NULL
+    OR (created_at, id) > (sqlc.narg(after_created_at)::timestamptz, sqlc.narg(after_id)::uuid))
+ORDER BY created_at, id
+LIMIT sqlc.arg(row_limit);
+
 -- name: ListPaymentsByPeriod :many
 SELECT *
 FROM payments
 WHERE paid_at >= $1 AND paid_at < $2
 ORDER BY paid_at DESC;
+
+-- name: ListPaymentsByPeriodPage :many
+SELECT *
+FROM payments
+WHERE paid_at >= sqlc.arg(paid_from)::timestamptz
+  AND paid_at < sqlc.arg(paid_to)::timestamptz
+  AND (sqlc.narg(after_created_at)::timestamptz IS NULL
+    OR (created_at, id) > (sqlc.narg(after_created_at)::timestamptz, sqlc.narg(after_id)::uuid))
+ORDER BY created_at, id
+LIMIT sqlc.arg(row_limit);
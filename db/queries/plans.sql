@@ -4,9 +4,13 @@ INSERT INTO plans (
   duration_days,
   price_cents,
   active,
-  description
+  description,
+  late_fee_bps,
+  late_interest_bps,
+  max_freeze_days,
+  installments
 ) VALUES (
-  $1, $2, $3, $4, $5
+  $1, $2, $3, $4, $5, $6, $7, $8, $9
 )
 RETURNING *;
 
@@ -18,6 +22,10 @@ SET
   price_cents = $4,
   active = $5,
   description = $6,
+  late_fee_bps = $7,
+  late_interest_bps = $8,
+  max_freeze_days = $9,
+  installments = $10,
   updated_at = now()
 WHERE id = $1
 RETURNING *;
@@ -27,3 +35,12 @@ SELECT * FROM plans WHERE id = $1 LIMIT 1;
 
 -- name: ListActivePlans :many
 SELECT * FROM plans WHERE active = true ORDER BY name;
+
+-- name: ListActivePlansPage :many
+SELECT *
+FROM plans
+WHERE active = true
+  AND (sqlc.narg(after_created_at)::timestamptz IS NULL
+    OR (created_at, id) > (sqlc.narg(after_created_at)::timestamptz, sqlc.narg(after_id)::uuid))
+ORDER BY created_at, id
+LIMIT sqlc.arg(row_limit);
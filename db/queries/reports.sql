@@ -2,7 +2,19 @@
 SELECT
   $1::timestamptz AS start,
   $2::timestamptz AS end,
-  COALESCE(SUM(amount_cents), 0)::bigint AS total_cents
+  COALESCE(SUM(amount_cents), 0)::bigint AS total_cents,
+  (
+    SELECT COALESCE(SUM(bp.amount_due_cents + bp.discount_cents), 0)
+    FROM billing_periods bp
+    WHERE bp.period_start >= $1::date
+      AND bp.period_start < $2::date
+  )::bigint AS gross_billed_cents,
+  (
+    SELECT COALESCE(SUM(bp.discount_cents), 0)
+    FROM billing_periods bp
+    WHERE bp.period_start >= $1::date
+      AND bp.period_start < $2::date
+  )::bigint AS discount_cents
 FROM payments
 WHERE paid_at >= $1
   AND paid_at < $2
@@ -19,9 +31,13 @@ SELECT
   s.id AS subscription_id,
   s.student_id,
   s.plan_id,
+  st.full_name AS student_name,
+  p.name AS plan_name,
   s.end_date,
   GREATEST(0, DATE_PART('day', $1::date - s.end_date))::int AS days_overdue
 FROM subscriptions s
+JOIN students st ON st.id = s.student_id
+JOIN plans p ON p.id = s.plan_id
 WHERE s.status = 'active'
   AND s.end_date < $1::date
 ORDER BY s.end_date;
@@ -31,8 +47,12 @@ SELECT
   s.id AS subscription_id,
   s.student_id,
   s.plan_id,
+  st.full_name AS student_name,
+  p.name AS plan_name,
   s.end_date
 FROM subscriptions s
+JOIN students st ON st.id = s.student_id
+JOIN plans p ON p.id = s.plan_id
 WHERE s.status = 'active'
   AND s.end_date BETWEEN $1::date AND $2::date
 ORDER BY s.end_date;
@@ -35,6 +35,9 @@ RETURNING *;
 -- name: GetStudent :one
 SELECT * FROM students WHERE id = $1 LIMIT 1;
 
+-- name: GetStudentIDByPhotoObjectKey :one
+SELECT id FROM students WHERE photo_object_key = $1 LIMIT 1;
+
 -- name: SearchStudents :many
 SELECT *
 FROM students
@@ -43,6 +46,16 @@ WHERE ($1 = '' OR full_name ILIKE '%' || $1 || '%' OR phone ILIKE '%' || $1 || '
 ORDER BY full_name
 LIMIT $3 OFFSET $4;
 
+-- name: SearchStudentsPage :many
+SELECT *
+FROM students
+WHERE (sqlc.arg(query)::text = '' OR full_name ILIKE '%' || sqlc.arg(query)::text || '%' OR phone ILIKE '%' || sqlc.arg(query)::text || '%' OR cpf ILIKE '%' || sqlc.arg(query)::text || '%')
+  AND (COALESCE(array_length(sqlc.arg(statuses)::student_status[], 1), 0) = 0 OR status = ANY(sqlc.arg(statuses)::student_status[]))
+  AND (sqlc.narg(after_created_at)::timestamptz IS NULL
+    OR (created_at, id) > (sqlc.narg(after_created_at)::timestamptz, sqlc.narg(after_id)::uuid))
+ORDER BY created_at, id
+LIMIT sqlc.arg(row_limit);
+
 -- name: CountStudents :one
 SELECT COUNT(*) FROM students
 WHERE ($1 = '' OR full_name ILIKE '%' || $1 || '%' OR phone ILIKE '%' || $1 || '%' OR cpf ILIKE '%' || $1 || '%')
@@ -0,0 +1,25 @@
+-- name: CreateSubscriptionFreeze :one
+INSERT INTO subscription_freezes (
+  subscription_id,
+  start_date,
+  end_date,
+  reason
+) VALUES (
+  $1, $2, $3, $4
+)
+RETURNING *;
+
+-- name: ListSubscriptionFreezes :many
+SELECT *
+FROM subscription_freezes
+WHERE subscription_id = $1
+ORDER BY start_date;
+
+-- name: ListSubscriptionFreezesPage :many
+SELECT *
+FROM subscription_freezes
+WHERE subscription_id = sqlc.arg(subscription_id)
+  AND (sqlc.narg(after_created_at)::timestamptz IS NULL
+    OR (created_at, id) > (sqlc.narg(after_created_at)::timestamptz, sqlc.narg(after_id)::uuid))
+ORDER BY created_at, id
+LIMIT sqlc.arg(row_limit);
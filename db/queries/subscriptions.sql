@@ -7,9 +7,15 @@ INSERT INTO subscriptions (
   status,
   price_cents,
   payment_day,
-  auto_renew
+  auto_renew,
+  coupon_id,
+  discount_kind,
+  discount_value,
+  discount_duration,
+  discount_periods,
+  previous_subscription_id
 ) VALUES (
-  $1, $2, $3, $4, $5, $6, $7, $8
+  $1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14
 )
 RETURNING *;
 
@@ -22,6 +28,11 @@ SET
   price_cents = $5,
   payment_day = $6,
   auto_renew = $7,
+  coupon_id = $8,
+  discount_kind = $9,
+  discount_value = $10,
+  discount_duration = $11,
+  discount_periods = $12,
   updated_at = now()
 WHERE id = $1
 RETURNING *;
@@ -32,6 +43,15 @@ SELECT * FROM subscriptions WHERE id = $1 LIMIT 1;
 -- name: ListSubscriptionsByStudent :many
 SELECT * FROM subscriptions WHERE student_id = $1 ORDER BY start_date DESC;
 
+-- name: ListSubscriptionsByStudentPage :many
+SELECT *
+FROM subscriptions
+WHERE student_id = sqlc.arg(student_id)
+  AND (sqlc.narg(after_created_at)::timestamptz IS NULL
+    OR (created_at, id) > (sqlc.narg(after_created_at)::timestamptz, sqlc.narg(after_id)::uuid))
+ORDER BY created_at, id
+LIMIT sqlc.arg(row_limit);
+
 -- name: ListSubscriptionsByPlan :many
 SELECT * FROM subscriptions WHERE plan_id = $1 ORDER BY start_date DESC;
 
@@ -42,9 +62,32 @@ WHERE status = 'active'
   AND end_date BETWEEN $1 AND $2
 ORDER BY end_date;
 
+-- name: ListSubscriptionsDueBetweenPage :many
+SELECT *
+FROM subscriptions
+WHERE status = 'active'
+  AND end_date BETWEEN sqlc.arg(due_from)::date AND sqlc.arg(due_to)::date
+  AND (sqlc.narg(after_created_at)::timestamptz IS NULL
+    OR (created_at, id) > (sqlc.narg(after_created_at)::timestamptz, sqlc.narg(after_id)::uuid))
+ORDER BY created_at, id
+LIMIT sqlc.arg(row_limit);
+
 -- name: ListAutoRenewSubscriptions :many
 SELECT *
 FROM subscriptions
 WHERE status = 'active'
   AND auto_renew = true
 ORDER BY start_date;
+
+-- name: ListSubscriptionsWithOpenPeriods :many
+SELECT s.*
+FROM subscriptions s
+WHERE s.status = 'active'
+  AND EXISTS (
+    SELECT 1
+    FROM billing_periods bp
+    WHERE bp.subscription_id = s.id
+      AND bp.status IN ('open', 'partial', 'overdue')
+      AND bp.fee_waived_at IS NULL
+  )
+ORDER BY s.start_date;
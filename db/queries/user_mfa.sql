@@ -0,0 +1,56 @@
+-- name: GetUserMFA :one
+SELECT * FROM user_mfa WHERE user_id = $1 LIMIT 1;
+
+-- name: UpsertUserMFASecret :one
+INSERT INTO user_mfa (
+  user_id,
+  secret
+) VALUES (
+  $1, $2
+)
+ON CONFLICT (user_id)
+DO UPDATE SET
+  secret = EXCLUDED.secret,
+  enabled_at = NULL,
+  last_used_step = 0
+RETURNING *;
+
+-- name: EnableUserMFA :one
+UPDATE user_mfa
+SET
+  enabled_at = $2,
+  last_used_step = $3
+WHERE user_id = $1
+  AND enabled_at IS NULL
+RETURNING *;
+
+-- name: AdvanceUserMFAStep :execrows
+UPDATE user_mfa
+SET last_used_step = $2
+WHERE user_id = $1
+  AND last_used_step < $2;
+
+-- name: DeleteUserMFA :exec
+WITH deleted_codes AS (
+  DELETE FROM user_recovery_codes WHERE user_recovery_codes.user_id = $1
+)
+DELETE FROM user_mfa WHERE user_mfa.user_id = $1;
+
+-- name: ReplaceRecoveryCodes :exec
+WITH deleted_codes AS (
+  DELETE FROM user_recovery_codes WHERE user_recovery_codes.user_id = sqlc.arg(user_id)
+)
+INSERT INTO user_recovery_codes (user_id, code_hash)
+SELECT sqlc.arg(user_id), unnest(sqlc.arg(code_hashes)::text[]);
+
+-- name: ConsumeRecoveryCode :execrows
+UPDATE user_recovery_codes
+SET used_at = $3
+WHERE user_id = $1
+  AND code_hash = $2
+  AND used_at IS NULL;
+
+-- name: CountRecoveryCodes :one
+SELECT count(*) FROM user_recovery_codes
+WHERE user_id = $1
+  AND used_at IS NULL;
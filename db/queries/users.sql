@@ -4,11 +4,46 @@ INSERT INTO users (
   email,
   password_hash,
   role,
-  active
+  active,
+  must_change_password
 ) VALUES (
-  $1, $2, $3, $4, $5
+  $1, $2, $3, $4, $5, $6
 )
 RETURNING *;
 
 -- name: GetUserByEmail :one
 SELECT * FROM users WHERE email = $1 LIMIT 1;
+
+-- name: GetUser :one
+SELECT * FROM users WHERE id = $1 LIMIT 1;
+
+-- name: ListUsers :many
+SELECT * FROM users ORDER BY name;
+
+-- name: UpdateUser :one
+UPDATE users
+SET
+  name = $2,
+  email = $3,
+  role = $4,
+  active = $5,
+  must_change_password = $6,
+  updated_at = now()
+WHERE id = $1
+RETURNING *;
+
+-- name: SetUserActive :one
+UPDATE users
+SET
+  active = $2,
+  updated_at = now()
+WHERE id = $1
+RETURNING *;
+
+-- name: UpdateUserPassword :exec
+UPDATE users
+SET
+  password_hash = $2,
+  must_change_password = $3,
+  updated_at = now()
+WHERE id = $1;
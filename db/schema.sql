@@ -31,6 +31,23 @@ CREATE TABLE plans (
   price_cents bigint NOT NULL,
   active boolean NOT NULL DEFAULT true,
   description text,
+  late_fee_bps integer NOT NULL DEFAULT 0 CONSTRAINT plans_late_fee_bps_check CHECK (late_fee_bps BETWEEN 0 AND 10000),
+  late_interest_bps integer NOT NULL DEFAULT 0 CONSTRAINT plans_late_interest_bps_check CHECK (late_interest_bps BETWEEN 0 AND 10000),
+  max_freeze_days integer NOT NULL DEFAULT 0 CHECK (max_freeze_days >= 0),
+  installments integer NOT NULL DEFAULT 1 CHECK (installments BETWEEN 1 AND 12),
+  created_at timestamptz NOT NULL DEFAULT now(),
+  updated_at timestamptz NOT NULL DEFAULT now()
+);
+
+CREATE TABLE coupons (
+  id uuid PRIMARY KEY DEFAULT gen_random_uuid(),
+  code text NOT NULL,
+  description text,
+  discount_kind text NOT NULL CHECK (discount_kind IN ('percent', 'fixed')),
+  discount_value bigint NOT NULL CHECK (discount_value > 0),
+  discount_duration text NOT NULL CHECK (discount_duration IN ('once', 'repeating', 'forever')),
+  discount_periods integer NOT NULL DEFAULT 0 CHECK (discount_periods >= 0),
+  active boolean NOT NULL DEFAULT true,
   created_at timestamptz NOT NULL DEFAULT now(),
   updated_at timestamptz NOT NULL DEFAULT now()
 );
@@ -45,6 +62,12 @@ CREATE TABLE subscriptions (
   price_cents bigint NOT NULL,
   payment_day integer NOT NULL,
   auto_renew boolean NOT NULL DEFAULT false,
+  coupon_id uuid REFERENCES coupons(id) ON DELETE SET NULL,
+  discount_kind text CHECK (discount_kind IN ('percent', 'fixed')),
+  discount_value bigint NOT NULL DEFAULT 0,
+  discount_duration text CHECK (discount_duration IN ('once', 'repeating', 'forever')),
+  discount_periods integer NOT NULL DEFAULT 0,
+  previous_subscription_id uuid REFERENCES subscriptions(id) ON DELETE SET NULL,
   created_at timestamptz NOT NULL DEFAULT now(),
   updated_at timestamptz NOT NULL DEFAULT now()
 );
@@ -75,10 +98,31 @@ CREATE TABLE billing_periods (
   period_start date NOT NULL,
   period_end date NOT NULL,
   amount_due_cents bigint NOT NULL,
+  discount_cents bigint NOT NULL DEFAULT 0,
   amount_paid_cents bigint NOT NULL DEFAULT 0,
   status billing_period_status NOT NULL DEFAULT 'open',
+  fee_cents bigint NOT NULL DEFAULT 0,
+  fee_accrued_on date,
+  fee_waived_at timestamptz,
+  shift_days integer NOT NULL DEFAULT 0,
+  frozen_days integer NOT NULL DEFAULT 0,
+  installment integer NOT NULL DEFAULT 1,
+  installments integer NOT NULL DEFAULT 1,
+  interest_cent_days bigint NOT NULL DEFAULT 0,
+  due_date date NOT NULL,
   created_at timestamptz NOT NULL DEFAULT now(),
-  updated_at timestamptz NOT NULL DEFAULT now()
+  updated_at timestamptz NOT NULL DEFAULT now(),
+  CONSTRAINT billing_periods_installment_check CHECK (installment BETWEEN 1 AND installments)
+);
+
+CREATE TABLE subscription_freezes (
+  id uuid PRIMARY KEY DEFAULT gen_random_uuid(),
+  subscription_id uuid NOT NULL REFERENCES subscriptions(id),
+  start_date date NOT NULL,
+  end_date date NOT NULL,
+  reason text NOT NULL,
+  created_at timestamptz NOT NULL DEFAULT now(),
+  CHECK (end_date >= start_date)
 );
 
 CREATE TABLE payment_allocations (
@@ -89,6 +133,15 @@ CREATE TABLE payment_allocations (
   PRIMARY KEY (payment_id, billing_period_id)
 );
 
+CREATE TABLE payment_credit_transfers (
+  payment_id uuid NOT NULL REFERENCES payments(id) ON DELETE CASCADE,
+  subscription_id uuid NOT NULL REFERENCES subscriptions(id) ON DELETE CASCADE,
+  amount_cents bigint NOT NULL CHECK (amount_cents > 0),
+  credit_cents bigint NOT NULL DEFAULT 0 CHECK (credit_cents BETWEEN 0 AND amount_cents),
+  created_at timestamptz NOT NULL DEFAULT now(),
+  PRIMARY KEY (payment_id, subscription_id)
+);
+
 CREATE TABLE audit_events (
   id uuid PRIMARY KEY DEFAULT gen_random_uuid(),
   actor_id uuid,
@@ -99,9 +152,30 @@ CREATE TABLE audit_events (
   metadata jsonb NOT NULL DEFAULT '{}'::jsonb,
   ip text,
   user_agent text,
-  created_at timestamptz NOT NULL DEFAULT now()
+  created_at timestamptz NOT NULL DEFAULT now(),
+  seq bigint,
+  prev_hash bytea,
+  hash bytea
+);
+
+CREATE TABLE audit_chain_head (
+  id smallint PRIMARY KEY DEFAULT 1 CHECK (id = 1),
+  seq bigint NOT NULL DEFAULT 0,
+  hash bytea NOT NULL DEFAULT ''::bytea,
+  updated_at timestamptz NOT NULL DEFAULT now()
+);
+
+INSERT INTO audit_chain_head (id) VALUES (1);
+
+CREATE TABLE audit_chain_anchor (
+  id smallint PRIMARY KEY DEFAULT 1 CHECK (id = 1),
+  seq bigint NOT NULL DEFAULT 0,
+  hash bytea NOT NULL DEFAULT ''::bytea,
+  updated_at timestamptz NOT NULL DEFAULT now()
 );
 
+INSERT INTO audit_chain_anchor (id) VALUES (1);
+
 CREATE TABLE users (
   id uuid PRIMARY KEY DEFAULT gen_random_uuid(),
   name text NOT NULL,
@@ -110,37 +184,100 @@ CREATE TABLE users (
   role user_role NOT NULL,
   active boolean NOT NULL DEFAULT true,
   created_at timestamptz NOT NULL DEFAULT now(),
+  updated_at timestamptz NOT NULL DEFAULT now(),
+  must_change_password boolean NOT NULL DEFAULT false
+);
+
+CREATE TABLE password_reset_tokens (
+  id uuid PRIMARY KEY DEFAULT gen_random_uuid(),
+  user_id uuid NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+  token_hash text NOT NULL UNIQUE,
+  expires_at timestamptz NOT NULL,
+  used_at timestamptz,
+  created_at timestamptz NOT NULL DEFAULT now()
+);
+
+CREATE TABLE user_mfa (
+  user_id uuid PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+  secret text NOT NULL,
+  enabled_at timestamptz,
+  last_used_step bigint NOT NULL DEFAULT 0,
+  created_at timestamptz NOT NULL DEFAULT now(),
   updated_at timestamptz NOT NULL DEFAULT now()
 );
 
+CREATE TABLE user_recovery_codes (
+  id uuid PRIMARY KEY DEFAULT gen_random_uuid(),
+  user_id uuid NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+  code_hash text NOT NULL,
+  used_at timestamptz,
+  created_at timestamptz NOT NULL DEFAULT now(),
+  UNIQUE (user_id, code_hash)
+);
+
+CREATE TABLE api_tokens (
+  id uuid PRIMARY KEY DEFAULT gen_random_uuid(),
+  user_id uuid NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+  name text NOT NULL,
+  token_prefix text NOT NULL,
+  token_hash text NOT NULL UNIQUE,
+  scopes text[] NOT NULL DEFAULT '{}',
+  expires_at timestamptz,
+  last_used_at timestamptz,
+  revoked_at timestamptz,
+  created_by uuid REFERENCES users(id) ON DELETE SET NULL,
+  created_at timestamptz NOT NULL DEFAULT now()
+);
+
 CREATE INDEX students_full_name_idx ON students (full_name);
 CREATE INDEX students_phone_idx ON students (phone);
 CREATE INDEX students_cpf_idx ON students (cpf);
 CREATE INDEX students_status_idx ON students (status);
+CREATE INDEX students_photo_object_key_idx ON students (photo_object_key);
+CREATE INDEX students_created_idx ON students (created_at, id);
 
 CREATE INDEX plans_active_idx ON plans (active);
+CREATE INDEX plans_active_created_idx ON plans (created_at, id) WHERE active;
 
 CREATE INDEX subscriptions_student_idx ON subscriptions (student_id);
 CREATE INDEX subscriptions_status_idx ON subscriptions (status);
 CREATE INDEX subscriptions_end_date_idx ON subscriptions (end_date);
 CREATE INDEX subscriptions_auto_renew_idx ON subscriptions (status, auto_renew);
+CREATE INDEX subscriptions_previous_idx ON subscriptions (previous_subscription_id);
+CREATE INDEX subscriptions_student_created_idx ON subscriptions (student_id, created_at, id);
 
 CREATE INDEX payments_subscription_idx ON payments (subscription_id);
 CREATE INDEX payments_paid_at_idx ON payments (paid_at);
+CREATE INDEX payments_subscription_created_idx ON payments (subscription_id, created_at, id);
 
 CREATE INDEX billing_periods_subscription_idx ON billing_periods (subscription_id);
 CREATE INDEX billing_periods_status_idx ON billing_periods (status);
 CREATE INDEX billing_periods_period_end_idx ON billing_periods (period_end);
 CREATE UNIQUE INDEX billing_periods_subscription_period_start_idx ON billing_periods (subscription_id, period_start);
+CREATE INDEX billing_periods_subscription_created_idx ON billing_periods (subscription_id, created_at, id);
+CREATE INDEX billing_periods_open_due_date_idx ON billing_periods (due_date) WHERE status IN ('open', 'partial');
+
+CREATE INDEX subscription_freezes_subscription_idx ON subscription_freezes (subscription_id, start_date);
+CREATE INDEX subscription_freezes_subscription_created_idx ON subscription_freezes (subscription_id, created_at, id);
 
 CREATE INDEX payment_allocations_payment_idx ON payment_allocations (payment_id);
 CREATE INDEX payment_allocations_period_idx ON payment_allocations (billing_period_id);
 
 CREATE INDEX audit_events_actor_idx ON audit_events (actor_id, created_at);
 CREATE INDEX audit_events_entity_idx ON audit_events (entity_type, entity_id, created_at);
+CREATE INDEX audit_events_created_at_idx ON audit_events (created_at);
+CREATE UNIQUE INDEX audit_events_seq_idx ON audit_events (seq);
 
 CREATE INDEX users_active_idx ON users (active);
 
+CREATE INDEX password_reset_tokens_user_idx ON password_reset_tokens (user_id);
+
+CREATE INDEX user_recovery_codes_user_idx ON user_recovery_codes (user_id);
+
+CREATE INDEX api_tokens_user_idx ON api_tokens (user_id);
+
+CREATE UNIQUE INDEX coupons_code_idx ON coupons (lower(code));
+
 CREATE OR REPLACE FUNCTION set_updated_at() RETURNS trigger AS $$
 BEGIN
   NEW.updated_at = now();
@@ -158,6 +295,11 @@ CREATE TRIGGER plans_updated_at
   FOR EACH ROW
   EXECUTE FUNCTION set_updated_at();
 
+CREATE TRIGGER coupons_updated_at
+  BEFORE UPDATE ON coupons
+  FOR EACH ROW
+  EXECUTE FUNCTION set_updated_at();
+
 CREATE TRIGGER subscriptions_updated_at
   BEFORE UPDATE ON subscriptions
   FOR EACH ROW
@@ -177,3 +319,8 @@ CREATE TRIGGER users_updated_at
   BEFORE UPDATE ON users
   FOR EACH ROW
   EXECUTE FUNCTION set_updated_at();
+
+CREATE TRIGGER user_mfa_updated_at
+  BEFORE UPDATE ON user_mfa
+  FOR EACH ROW
+  EXECUTE FUNCTION set_updated_at();
@@ -0,0 +1,39 @@
+package memory
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// AccessDedupStore guarda em memoria as chaves de acesso ja registradas. E
+// usado quando o Redis nao esta configurado e vale apenas para a instancia atual.
+type AccessDedupStore struct {
+	mu      sync.Mutex
+	expires map[string]time.Time
+	now     func() time.Time
+}
+
+func NewAccessDedupStore() *AccessDedupStore {
+	return &AccessDedupStore{
+		expires: make(map[string]time.Time),
+		now:     time.Now,
+	}
+}
+
+func (s *AccessDedupStore) Claim(ctx context.Context, key string, window time.Duration) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	if expiresAt, ok := s.expires[key]; ok && now.Before(expiresAt) {
+		return false, nil
+	}
+	for existing, expiresAt := range s.expires {
+		if !now.Before(expiresAt) {
+			delete(s.expires, existing)
+		}
+	}
+	s.expires[key] = now.Add(window)
+	return true, nil
+}
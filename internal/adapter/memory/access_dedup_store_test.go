@@ -0,0 +1,34 @@
+package memory
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+// Testa que a chave so e reivindicada uma vez dentro da janela.
+func TestAccessDedupStore(t *testing.T) {
+	now := time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC)
+	store := NewAccessDedupStore()
+	store.now = func() time.Time { return now }
+	ctx := context.Background()
+
+	claimed, err := store.Claim(ctx, "user-1:stu-1", 10*time.Minute)
+	if err != nil || !claimed {
+		t.Fatalf("expected first claim, got %v err=%v", claimed, err)
+	}
+	claimed, _ = store.Claim(ctx, "user-1:stu-1", 10*time.Minute)
+	if claimed {
+		t.Fatalf("expected duplicate claim to be rejected")
+	}
+	claimed, _ = store.Claim(ctx, "user-2:stu-1", 10*time.Minute)
+	if !claimed {
+		t.Fatalf("expected other actor to claim")
+	}
+
+	now = now.Add(10 * time.Minute)
+	claimed, _ = store.Claim(ctx, "user-1:stu-1", 10*time.Minute)
+	if !claimed {
+		t.Fatalf("expected claim after window")
+	}
+}
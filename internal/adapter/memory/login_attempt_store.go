@@ -0,0 +1,115 @@
+package memory
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/PabloPavan/jaiu/internal/ports"
+)
+
+// LoginAttemptStore mantem as tentativas de login em memoria. E usado quando o
+// Redis nao esta configurado e vale apenas para a instancia atual.
+type LoginAttemptStore struct {
+	mu      sync.Mutex
+	entries map[string]*loginAttemptEntry
+	now     func() time.Time
+}
+
+type loginAttemptEntry struct {
+	failures         int
+	failuresExpireAt time.Time
+	lockouts         int
+	lockoutsExpireAt time.Time
+	lockedUntil      time.Time
+}
+
+func NewLoginAttemptStore() *LoginAttemptStore {
+	return &LoginAttemptStore{
+		entries: make(map[string]*loginAttemptEntry),
+		now:     time.Now,
+	}
+}
+
+func (s *LoginAttemptStore) Get(ctx context.Context, key string) (ports.LoginAttempts, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entry := s.entry(key, false)
+	if entry == nil {
+		return ports.LoginAttempts{}, nil
+	}
+
+	attempts := ports.LoginAttempts{
+		Failures: entry.failures,
+		Lockouts: entry.lockouts,
+	}
+	if s.now().Before(entry.lockedUntil) {
+		attempts.LockedUntil = entry.lockedUntil
+	}
+	return attempts, nil
+}
+
+func (s *LoginAttemptStore) RegisterFailure(ctx context.Context, key string, window time.Duration) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entry := s.entry(key, true)
+	if entry.failures == 0 && window > 0 {
+		entry.failuresExpireAt = s.now().Add(window)
+	}
+	entry.failures++
+	return entry.failures, nil
+}
+
+func (s *LoginAttemptStore) Lock(ctx context.Context, key string, until time.Time, memory time.Duration) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if !s.now().Before(until) {
+		return nil
+	}
+
+	entry := s.entry(key, true)
+	entry.lockedUntil = until
+	entry.failures = 0
+	entry.failuresExpireAt = time.Time{}
+	entry.lockouts++
+	if memory > 0 {
+		entry.lockoutsExpireAt = s.now().Add(memory)
+	}
+	return nil
+}
+
+func (s *LoginAttemptStore) Reset(ctx context.Context, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.entries, key)
+	return nil
+}
+
+// entry retorna o registro da chave descartando contadores expirados.
+func (s *LoginAttemptStore) entry(key string, create bool) *loginAttemptEntry {
+	now := s.now()
+	entry, ok := s.entries[key]
+	if ok {
+		if !entry.failuresExpireAt.IsZero() && !now.Before(entry.failuresExpireAt) {
+			entry.failures = 0
+			entry.failuresExpireAt = time.Time{}
+		}
+		if !entry.lockoutsExpireAt.IsZero() && !now.Before(entry.lockoutsExpireAt) {
+			entry.lockouts = 0
+			entry.lockoutsExpireAt = time.Time{}
+		}
+		if entry.failures == 0 && entry.lockouts == 0 && !now.Before(entry.lockedUntil) {
+			delete(s.entries, key)
+			entry, ok = nil, false
+		}
+	}
+	if !ok && create {
+		entry = &loginAttemptEntry{}
+		s.entries[key] = entry
+	}
+	return entry
+}
@@ -0,0 +1,57 @@
+package memory
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+// Testa contagem de falhas, bloqueio e expiracao dos contadores em memoria.
+func TestLoginAttemptStore(t *testing.T) {
+	now := time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC)
+	store := NewLoginAttemptStore()
+	store.now = func() time.Time { return now }
+	ctx := context.Background()
+
+	for i := 1; i <= 3; i++ {
+		count, err := store.RegisterFailure(ctx, "email:a@b.com", time.Minute)
+		if err != nil {
+			t.Fatalf("register failure: %v", err)
+		}
+		if count != i {
+			t.Fatalf("expected %d failures, got %d", i, count)
+		}
+	}
+
+	now = now.Add(2 * time.Minute)
+	attempts, err := store.Get(ctx, "email:a@b.com")
+	if err != nil {
+		t.Fatalf("get: %v", err)
+	}
+	if attempts.Failures != 0 {
+		t.Fatalf("expected failures to expire, got %d", attempts.Failures)
+	}
+
+	until := now.Add(5 * time.Minute)
+	if err := store.Lock(ctx, "email:a@b.com", until, time.Hour); err != nil {
+		t.Fatalf("lock: %v", err)
+	}
+	attempts, _ = store.Get(ctx, "email:a@b.com")
+	if !attempts.LockedUntil.Equal(until) || attempts.Lockouts != 1 {
+		t.Fatalf("expected lock until %v with 1 lockout, got %#v", until, attempts)
+	}
+
+	now = now.Add(10 * time.Minute)
+	attempts, _ = store.Get(ctx, "email:a@b.com")
+	if !attempts.LockedUntil.IsZero() || attempts.Lockouts != 1 {
+		t.Fatalf("expected lock to end and lockout to be remembered, got %#v", attempts)
+	}
+
+	if err := store.Reset(ctx, "email:a@b.com"); err != nil {
+		t.Fatalf("reset: %v", err)
+	}
+	attempts, _ = store.Get(ctx, "email:a@b.com")
+	if attempts.Lockouts != 0 || attempts.Failures != 0 {
+		t.Fatalf("expected reset attempts, got %#v", attempts)
+	}
+}
@@ -0,0 +1,95 @@
+package memory
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"sync"
+	"time"
+
+	"github.com/PabloPavan/jaiu/internal/ports"
+)
+
+// LoginChallengeStore mantem os desafios de segundo fator em memoria. E usado
+// quando o Redis nao esta configurado e vale apenas para a instancia atual.
+type LoginChallengeStore struct {
+	mu         sync.Mutex
+	challenges map[string]ports.LoginChallenge
+	now        func() time.Time
+}
+
+func NewLoginChallengeStore() *LoginChallengeStore {
+	return &LoginChallengeStore{
+		challenges: make(map[string]ports.LoginChallenge),
+		now:        time.Now,
+	}
+}
+
+func (s *LoginChallengeStore) Create(ctx context.Context, challenge ports.LoginChallenge) (string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	token := base64.RawURLEncoding.EncodeToString(buf)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	if challenge.ExpiresAt.IsZero() {
+		challenge.ExpiresAt = now.Add(5 * time.Minute)
+	}
+	challenge.Failures = 0
+	for key, existing := range s.challenges {
+		if !now.Before(existing.ExpiresAt) {
+			delete(s.challenges, key)
+		}
+	}
+	s.challenges[token] = challenge
+	return token, nil
+}
+
+func (s *LoginChallengeStore) Get(ctx context.Context, token string) (ports.LoginChallenge, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	challenge, ok := s.lookup(token)
+	if !ok {
+		return ports.LoginChallenge{}, ports.ErrNotFound
+	}
+	return challenge, nil
+}
+
+func (s *LoginChallengeStore) RegisterFailure(ctx context.Context, token string) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	challenge, ok := s.lookup(token)
+	if !ok {
+		return 0, ports.ErrNotFound
+	}
+	challenge.Failures++
+	s.challenges[token] = challenge
+	return challenge.Failures, nil
+}
+
+func (s *LoginChallengeStore) Delete(ctx context.Context, token string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.challenges, token)
+	return nil
+}
+
+// lookup retorna o desafio do token descartando-o quando expirado.
+func (s *LoginChallengeStore) lookup(token string) (ports.LoginChallenge, bool) {
+	challenge, ok := s.challenges[token]
+	if !ok {
+		return ports.LoginChallenge{}, false
+	}
+	if !s.now().Before(challenge.ExpiresAt) {
+		delete(s.challenges, token)
+		return ports.LoginChallenge{}, false
+	}
+	return challenge, true
+}
@@ -0,0 +1,49 @@
+package memory
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/PabloPavan/jaiu/internal/ports"
+)
+
+// Testa criacao, contagem de falhas e expiracao dos desafios em memoria.
+func TestLoginChallengeStore(t *testing.T) {
+	now := time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC)
+	store := NewLoginChallengeStore()
+	store.now = func() time.Time { return now }
+	ctx := context.Background()
+
+	token, err := store.Create(ctx, ports.LoginChallenge{UserID: "user-1", ExpiresAt: now.Add(5 * time.Minute)})
+	if err != nil {
+		t.Fatalf("create: %v", err)
+	}
+
+	for i := 1; i <= 2; i++ {
+		failures, err := store.RegisterFailure(ctx, token)
+		if err != nil {
+			t.Fatalf("register failure: %v", err)
+		}
+		if failures != i {
+			t.Fatalf("expected %d failures, got %d", i, failures)
+		}
+	}
+
+	challenge, err := store.Get(ctx, token)
+	if err != nil {
+		t.Fatalf("get: %v", err)
+	}
+	if challenge.UserID != "user-1" || challenge.Failures != 2 {
+		t.Fatalf("unexpected challenge: %#v", challenge)
+	}
+
+	now = now.Add(6 * time.Minute)
+	if _, err := store.Get(ctx, token); !errors.Is(err, ports.ErrNotFound) {
+		t.Fatalf("expected not found after expiration, got %v", err)
+	}
+	if _, err := store.RegisterFailure(ctx, token); !errors.Is(err, ports.ErrNotFound) {
+		t.Fatalf("expected not found on failure after expiration, got %v", err)
+	}
+}
@@ -0,0 +1,43 @@
+package notifier
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/PabloPavan/jaiu/internal/ports"
+)
+
+// FileNotifier acrescenta as notificacoes em um arquivo texto, uma por bloco.
+type FileNotifier struct {
+	path string
+	mu   sync.Mutex
+	now  func() time.Time
+}
+
+func NewFileNotifier(path string) *FileNotifier {
+	return &FileNotifier{path: path, now: time.Now}
+}
+
+func (n *FileNotifier) Notify(ctx context.Context, notification ports.Notification) error {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	file, err := os.OpenFile(n.path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o600)
+	if err != nil {
+		return err
+	}
+
+	_, err = fmt.Fprintf(file, "Date: %s\nTo: %s\nSubject: %s\n\n%s\n\n",
+		n.now().Format(time.RFC3339),
+		notification.To,
+		notification.Subject,
+		notification.Body,
+	)
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	return err
+}
@@ -0,0 +1,42 @@
+package notifier
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/PabloPavan/jaiu/internal/ports"
+)
+
+// Testa que o FileNotifier acrescenta as notificacoes no arquivo.
+func TestFileNotifierAppends(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "notifications.log")
+	notifier := NewFileNotifier(path)
+	notifier.now = func() time.Time { return time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC) }
+
+	for _, subject := range []string{"Primeira", "Segunda"} {
+		err := notifier.Notify(context.Background(), ports.Notification{
+			To:      "user@example.com",
+			Subject: subject,
+			Body:    "Corpo",
+		})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("read file: %v", err)
+	}
+	text := string(content)
+	if !strings.Contains(text, "Subject: Primeira") || !strings.Contains(text, "Subject: Segunda") {
+		t.Fatalf("expected both notifications, got %q", text)
+	}
+	if !strings.Contains(text, "Date: 2024-01-02T03:04:05Z\nTo: user@example.com") {
+		t.Fatalf("expected header lines, got %q", text)
+	}
+}
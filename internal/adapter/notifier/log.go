@@ -0,0 +1,25 @@
+package notifier
+
+import (
+	"context"
+
+	"github.com/PabloPavan/jaiu/internal/observability"
+	"github.com/PabloPavan/jaiu/internal/ports"
+)
+
+// LogNotifier escreve as notificacoes no log da aplicacao. Serve para
+// desenvolvimento local, quando nao ha envio de email configurado.
+type LogNotifier struct{}
+
+func NewLogNotifier() *LogNotifier {
+	return &LogNotifier{}
+}
+
+func (n *LogNotifier) Notify(ctx context.Context, notification ports.Notification) error {
+	observability.Logger(ctx).Info("notification",
+		"to", notification.To,
+		"subject", notification.Subject,
+		"body", notification.Body,
+	)
+	return nil
+}
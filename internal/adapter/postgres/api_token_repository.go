@@ -0,0 +1,162 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/PabloPavan/jaiu/internal/adapter/postgres/sqlc"
+	"github.com/PabloPavan/jaiu/internal/domain"
+	"github.com/PabloPavan/jaiu/internal/ports"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type APITokenRepository struct {
+	queries *sqlc.Queries
+}
+
+func NewAPITokenRepository(pool *pgxpool.Pool) *APITokenRepository {
+	return &APITokenRepository{queries: sqlc.New(pool)}
+}
+
+func (r *APITokenRepository) Create(ctx context.Context, token domain.APIToken) (domain.APIToken, error) {
+	userID, err := stringToUUID(token.UserID)
+	if err != nil || !userID.Valid {
+		return domain.APIToken{}, ports.ErrNotFound
+	}
+	createdBy, err := stringToUUID(token.CreatedBy)
+	if err != nil {
+		return domain.APIToken{}, err
+	}
+
+	var expiresAt pgtype.Timestamptz
+	if token.ExpiresAt != nil {
+		expiresAt = timestamptzTo(*token.ExpiresAt)
+	}
+
+	created, err := r.queries.CreateAPIToken(ctx, sqlc.CreateAPITokenParams{
+		UserID:      userID,
+		Name:        token.Name,
+		TokenPrefix: token.Prefix,
+		TokenHash:   token.TokenHash,
+		Scopes:      scopesTo(token.Scopes),
+		ExpiresAt:   expiresAt,
+		CreatedBy:   createdBy,
+	})
+	if err != nil {
+		return domain.APIToken{}, err
+	}
+
+	return mapAPIToken(created), nil
+}
+
+func (r *APITokenRepository) FindByHash(ctx context.Context, tokenHash string) (domain.APIToken, error) {
+	token, err := r.queries.GetAPITokenByHash(ctx, tokenHash)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return domain.APIToken{}, ports.ErrNotFound
+		}
+		return domain.APIToken{}, err
+	}
+
+	return mapAPIToken(token), nil
+}
+
+func (r *APITokenRepository) List(ctx context.Context) ([]domain.APIToken, error) {
+	rows, err := r.queries.ListAPITokens(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	tokens := make([]domain.APIToken, 0, len(rows))
+	for _, row := range rows {
+		token := mapAPIToken(sqlc.ApiToken{
+			ID:          row.ID,
+			UserID:      row.UserID,
+			Name:        row.Name,
+			TokenPrefix: row.TokenPrefix,
+			TokenHash:   row.TokenHash,
+			Scopes:      row.Scopes,
+			ExpiresAt:   row.ExpiresAt,
+			LastUsedAt:  row.LastUsedAt,
+			RevokedAt:   row.RevokedAt,
+			CreatedBy:   row.CreatedBy,
+			CreatedAt:   row.CreatedAt,
+		})
+		token.UserName = row.UserName
+		token.UserEmail = row.UserEmail
+		tokens = append(tokens, token)
+	}
+	return tokens, nil
+}
+
+// Revoke retorna ErrNotFound quando o token nao existe ou ja foi revogado.
+func (r *APITokenRepository) Revoke(ctx context.Context, id string, revokedAt time.Time) error {
+	uuidValue, err := stringToUUID(id)
+	if err != nil || !uuidValue.Valid {
+		return ports.ErrNotFound
+	}
+
+	rows, err := r.queries.RevokeAPIToken(ctx, sqlc.RevokeAPITokenParams{
+		ID:        uuidValue,
+		RevokedAt: timestamptzTo(revokedAt),
+	})
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return ports.ErrNotFound
+	}
+	return nil
+}
+
+func (r *APITokenRepository) Touch(ctx context.Context, id string, usedAt time.Time) error {
+	uuidValue, err := stringToUUID(id)
+	if err != nil || !uuidValue.Valid {
+		return ports.ErrNotFound
+	}
+
+	return r.queries.TouchAPIToken(ctx, sqlc.TouchAPITokenParams{
+		ID:         uuidValue,
+		LastUsedAt: timestamptzTo(usedAt),
+	})
+}
+
+func mapAPIToken(token sqlc.ApiToken) domain.APIToken {
+	result := domain.APIToken{
+		ID:        uuidToString(token.ID),
+		UserID:    uuidToString(token.UserID),
+		Name:      token.Name,
+		Prefix:    token.TokenPrefix,
+		TokenHash: token.TokenHash,
+		Scopes:    make([]domain.APIScope, 0, len(token.Scopes)),
+		CreatedBy: uuidToString(token.CreatedBy),
+		CreatedAt: timeFrom(token.CreatedAt),
+	}
+	for _, scope := range token.Scopes {
+		result.Scopes = append(result.Scopes, domain.APIScope(scope))
+	}
+	if token.ExpiresAt.Valid {
+		expiresAt := token.ExpiresAt.Time
+		result.ExpiresAt = &expiresAt
+	}
+	if token.LastUsedAt.Valid {
+		lastUsedAt := token.LastUsedAt.Time
+		result.LastUsedAt = &lastUsedAt
+	}
+	if token.RevokedAt.Valid {
+		revokedAt := token.RevokedAt.Time
+		result.RevokedAt = &revokedAt
+	}
+	return result
+}
+
+func scopesTo(scopes []domain.APIScope) []string {
+	values := make([]string, 0, len(scopes))
+	for _, scope := range scopes {
+		values = append(values, string(scope))
+	}
+	return values
+}
@@ -0,0 +1,157 @@
+package postgres
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/PabloPavan/jaiu/internal/adapter/postgres/sqlc"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const auditChainBatchSize = 1000
+
+// AuditChainReport resume a verificacao da cadeia de auditoria. Quando Broken
+// e verdadeiro, BrokenSeq e BrokenID apontam o primeiro elo invalido.
+type AuditChainReport struct {
+	Checked   int64
+	Legacy    int64
+	HeadSeq   int64
+	Broken    bool
+	BrokenSeq int64
+	BrokenID  string
+	Reason    string
+}
+
+// auditChainEntry reune os campos cobertos pelo hash de cada evento.
+type auditChainEntry struct {
+	Seq        int64
+	ID         string
+	ActorID    string
+	ActorRole  string
+	Action     string
+	EntityType string
+	EntityID   string
+	Metadata   string
+	IP         string
+	UserAgent  string
+	CreatedAt  time.Time
+}
+
+// auditChainHash calcula sha256(prev || campos), com cada campo terminado por
+// um byte zero. Metadata usa a forma textual normalizada do jsonb.
+func auditChainHash(prev []byte, entry auditChainEntry) []byte {
+	h := sha256.New()
+	h.Write(prev)
+	for _, field := range []string{
+		strconv.FormatInt(entry.Seq, 10),
+		entry.ID,
+		entry.ActorID,
+		entry.ActorRole,
+		entry.Action,
+		entry.EntityType,
+		entry.EntityID,
+		entry.Metadata,
+		entry.IP,
+		entry.UserAgent,
+		entry.CreatedAt.UTC().Format(time.RFC3339Nano),
+	} {
+		h.Write([]byte(field))
+		h.Write([]byte{0})
+	}
+	return h.Sum(nil)
+}
+
+// VerifyChain percorre os eventos encadeados em ordem de seq, a partir da
+// ancora deixada pela retencao, e confere a continuidade da sequencia, o hash
+// anterior e o hash de cada linha. Por fim compara o ultimo evento com
+// audit_chain_head para detectar remocoes no fim da cadeia. Eventos gravados
+// antes do encadeamento sao apenas contados.
+func (r *AuditRepository) VerifyChain(ctx context.Context) (AuditChainReport, error) {
+	if r == nil || r.queries == nil {
+		return AuditChainReport{}, errors.New("audit repository unavailable")
+	}
+
+	report := AuditChainReport{}
+	legacy, err := r.queries.CountUnchainedAuditEvents(ctx)
+	if err != nil {
+		return report, err
+	}
+	report.Legacy = legacy
+
+	head, err := r.queries.GetAuditChainHead(ctx)
+	if err != nil {
+		return report, err
+	}
+	report.HeadSeq = head.Seq
+
+	anchor, err := r.queries.GetAuditChainAnchor(ctx)
+	if err != nil {
+		return report, err
+	}
+
+	lastSeq := anchor.Seq
+	lastHash := anchor.Hash
+	for {
+		rows, err := r.queries.ListAuditChain(ctx, sqlc.ListAuditChainParams{
+			Seq:   pgtype.Int8{Int64: lastSeq, Valid: true},
+			Limit: auditChainBatchSize,
+		})
+		if err != nil {
+			return report, err
+		}
+
+		for _, row := range rows {
+			seq := row.Seq.Int64
+			id := uuidToString(row.ID)
+			if seq != lastSeq+1 {
+				return brokenAuditChain(report, seq, id, fmt.Sprintf("sequencia salta de %d para %d", lastSeq, seq)), nil
+			}
+			if !bytes.Equal(row.PrevHash, lastHash) {
+				return brokenAuditChain(report, seq, id, "hash anterior nao confere"), nil
+			}
+
+			expected := auditChainHash(row.PrevHash, auditChainEntry{
+				Seq:        seq,
+				ID:         id,
+				ActorID:    uuidToString(row.ActorID),
+				ActorRole:  textFrom(row.ActorRole),
+				Action:     row.Action,
+				EntityType: row.EntityType,
+				EntityID:   uuidToString(row.EntityID),
+				Metadata:   row.MetadataText,
+				IP:         textFrom(row.Ip),
+				UserAgent:  textFrom(row.UserAgent),
+				CreatedAt:  timeFrom(row.CreatedAt),
+			})
+			if !bytes.Equal(row.Hash, expected) {
+				return brokenAuditChain(report, seq, id, "conteudo do evento foi alterado"), nil
+			}
+
+			lastSeq = seq
+			lastHash = row.Hash
+			report.Checked++
+		}
+
+		if len(rows) < auditChainBatchSize {
+			break
+		}
+	}
+
+	if lastSeq != head.Seq || !bytes.Equal(lastHash, head.Hash) {
+		return brokenAuditChain(report, head.Seq, "", fmt.Sprintf("ultimo evento (%d) difere do topo da cadeia (%d)", lastSeq, head.Seq)), nil
+	}
+	return report, nil
+}
+
+func brokenAuditChain(report AuditChainReport, seq int64, id, reason string) AuditChainReport {
+	report.Broken = true
+	report.BrokenSeq = seq
+	report.BrokenID = id
+	report.Reason = reason
+	return report
+}
@@ -2,36 +2,90 @@ package postgres
 
 import (
 	"context"
+	"encoding/hex"
 	"encoding/json"
 	"errors"
+	"sync"
+	"time"
 
+	"github.com/PabloPavan/jaiu/internal/adapter/postgres/sqlc"
 	"github.com/PabloPavan/jaiu/internal/domain"
+	"github.com/PabloPavan/jaiu/internal/ports"
 	"github.com/jackc/pgx/v5"
-	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgtype"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
 type AuditRepository struct {
-	exec auditExecer
+	db      auditDB
+	queries *sqlc.Queries
 }
 
-type auditExecer interface {
-	Exec(ctx context.Context, sql string, arguments ...any) (pgconn.CommandTag, error)
+// auditDB e atendido pelo pool; Begin abre a transacao curta de cada Record.
+type auditDB interface {
+	sqlc.DBTX
+	Begin(ctx context.Context) (pgx.Tx, error)
 }
 
 func NewAuditRepository(pool *pgxpool.Pool) *AuditRepository {
-	return &AuditRepository{exec: pool}
+	return &AuditRepository{db: pool, queries: sqlc.New(pool)}
 }
 
-func NewAuditRepositoryWithTx(tx pgx.Tx) *AuditRepository {
-	return &AuditRepository{exec: tx}
+// pendingAudit guarda os eventos gravados dentro de uma transacao do TxRunner.
+// Eles entram na cadeia na propria transacao, logo antes do commit: a linha
+// de audit_chain_head so fica bloqueada nesse trecho final, e uma falha ao
+// encadear desfaz a operacao junto com o evento.
+type pendingAudit struct {
+	mu     sync.Mutex
+	events []domain.AuditEvent
 }
 
+func (p *pendingAudit) Record(ctx context.Context, event domain.AuditEvent) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.events = append(p.events, event)
+	return nil
+}
+
+// flush encadeia os eventos pendentes, na ordem em que foram registrados,
+// usando as queries da transacao de negocio.
+func (p *pendingAudit) flush(ctx context.Context, q *sqlc.Queries) error {
+	p.mu.Lock()
+	events := p.events
+	p.events = nil
+	p.mu.Unlock()
+
+	for _, event := range events {
+		if err := appendAuditEvent(ctx, q, event); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Record grava o evento encadeado ao anterior numa transacao curta propria.
 func (r *AuditRepository) Record(ctx context.Context, event domain.AuditEvent) error {
-	if r == nil || r.exec == nil {
+	if r == nil || r.db == nil {
 		return errors.New("audit repository unavailable")
 	}
 
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = tx.Rollback(ctx)
+	}()
+	if err := appendAuditEvent(ctx, sqlc.New(tx), event); err != nil {
+		return err
+	}
+	return tx.Commit(ctx)
+}
+
+// appendAuditEvent insere o evento no fim da cadeia. A linha de
+// audit_chain_head fica bloqueada ate o fim da transacao de q para que dois
+// eventos nunca compartilhem o mesmo antecessor.
+func appendAuditEvent(ctx context.Context, q *sqlc.Queries, event domain.AuditEvent) error {
 	actorID, err := stringToUUID(event.ActorID)
 	if err != nil {
 		return err
@@ -48,17 +102,289 @@ func (r *AuditRepository) Record(ctx context.Context, event domain.AuditEvent) e
 			return err
 		}
 	}
-	_, err = r.exec.Exec(ctx, `
-		INSERT INTO audit_events (
-			actor_id,
-			actor_role,
-			action,
-			entity_type,
-			entity_id,
-			metadata,
-			ip,
-			user_agent
-		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
-	`, actorID, textTo(event.ActorRole), event.Action, event.EntityType, entityID, metadataBytes, textTo(event.IP), textTo(event.UserAgent))
-	return err
+
+	head, err := q.LockAuditChainHead(ctx)
+	if err != nil {
+		return err
+	}
+
+	seq := head.Seq + 1
+	inserted, err := q.InsertChainedAuditEvent(ctx, sqlc.InsertChainedAuditEventParams{
+		ActorID:    actorID,
+		ActorRole:  textTo(event.ActorRole),
+		Action:     event.Action,
+		EntityType: event.EntityType,
+		EntityID:   entityID,
+		Metadata:   metadataBytes,
+		Ip:         textTo(event.IP),
+		UserAgent:  textTo(event.UserAgent),
+		Seq:        pgtype.Int8{Int64: seq, Valid: true},
+		PrevHash:   head.Hash,
+	})
+	if err != nil {
+		return err
+	}
+
+	hash := auditChainHash(head.Hash, auditChainEntry{
+		Seq:        seq,
+		ID:         uuidToString(inserted.ID),
+		ActorID:    uuidToString(actorID),
+		ActorRole:  event.ActorRole,
+		Action:     event.Action,
+		EntityType: event.EntityType,
+		EntityID:   uuidToString(entityID),
+		Metadata:   inserted.MetadataText,
+		IP:         event.IP,
+		UserAgent:  event.UserAgent,
+		CreatedAt:  timeFrom(inserted.CreatedAt),
+	})
+	if err := q.SetAuditEventHash(ctx, sqlc.SetAuditEventHashParams{ID: inserted.ID, Hash: hash}); err != nil {
+		return err
+	}
+	return q.AdvanceAuditChainHead(ctx, sqlc.AdvanceAuditChainHeadParams{Seq: seq, Hash: hash})
+}
+
+func (r *AuditRepository) Search(ctx context.Context, filter ports.AuditFilter) ([]domain.AuditEvent, error) {
+	if r == nil || r.queries == nil {
+		return nil, errors.New("audit repository unavailable")
+	}
+
+	limit := filter.Limit
+	if limit <= 0 {
+		limit = 50
+	}
+
+	params, ok := auditFilterParams(filter)
+	if !ok {
+		return []domain.AuditEvent{}, nil
+	}
+
+	rows, err := r.queries.SearchAuditEvents(ctx, sqlc.SearchAuditEventsParams{
+		ActorID:      params.ActorID,
+		EntityType:   params.EntityType,
+		EntityID:     params.EntityID,
+		ActionPrefix: params.ActionPrefix,
+		CreatedFrom:  params.CreatedFrom,
+		CreatedTo:    params.CreatedTo,
+		RowLimit:     int32(limit),
+		RowOffset:    int32(filter.Offset),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]domain.AuditEvent, 0, len(rows))
+	for _, row := range rows {
+		event, err := mapAuditEvent(row)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, event)
+	}
+	return result, nil
+}
+
+func (r *AuditRepository) Count(ctx context.Context, filter ports.AuditFilter) (int, error) {
+	if r == nil || r.queries == nil {
+		return 0, errors.New("audit repository unavailable")
+	}
+
+	params, ok := auditFilterParams(filter)
+	if !ok {
+		return 0, nil
+	}
+
+	count, err := r.queries.CountAuditEvents(ctx, params)
+	if err != nil {
+		return 0, err
+	}
+	return int(count), nil
+}
+
+// AccessSummary agrupa por usuario os eventos de acesso a uma entidade, do
+// acesso mais recente para o mais antigo.
+func (r *AuditRepository) AccessSummary(ctx context.Context, action, entityType, entityID string) ([]ports.AuditAccessSummary, error) {
+	if r == nil || r.queries == nil {
+		return nil, errors.New("audit repository unavailable")
+	}
+
+	id, err := stringToUUID(entityID)
+	if err != nil || !id.Valid {
+		return []ports.AuditAccessSummary{}, nil
+	}
+
+	rows, err := r.queries.SummarizeAuditAccess(ctx, sqlc.SummarizeAuditAccessParams{
+		Action:     action,
+		EntityType: entityType,
+		EntityID:   id,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]ports.AuditAccessSummary, 0, len(rows))
+	for _, row := range rows {
+		result = append(result, ports.AuditAccessSummary{
+			ActorID:   uuidToString(row.ActorID),
+			ActorRole: textFrom(row.ActorRole),
+			Views:     int(row.Views),
+			Sources:   row.Sources,
+			FirstAt:   timeFrom(row.FirstAt),
+			LastAt:    timeFrom(row.LastAt),
+		})
+	}
+	return result, nil
+}
+
+// OldestCreatedAt devolve a data do evento mais antigo; ok e falso quando nao
+// ha eventos.
+func (r *AuditRepository) OldestCreatedAt(ctx context.Context) (time.Time, bool, error) {
+	if r == nil || r.queries == nil {
+		return time.Time{}, false, errors.New("audit repository unavailable")
+	}
+
+	oldest, err := r.queries.GetOldestAuditEventAt(ctx)
+	if err != nil {
+		return time.Time{}, false, err
+	}
+	return timeFrom(oldest), oldest.Valid, nil
+}
+
+func (r *AuditRepository) ListRange(ctx context.Context, from, to time.Time, after ports.AuditCursor, limit int) ([]domain.AuditEvent, error) {
+	if r == nil || r.queries == nil {
+		return nil, errors.New("audit repository unavailable")
+	}
+
+	afterID, err := stringToUUID(after.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := r.queries.ListAuditEventsRange(ctx, sqlc.ListAuditEventsRangeParams{
+		CreatedFrom:    timestamptzTo(from),
+		CreatedTo:      timestamptzTo(to),
+		AfterCreatedAt: timestamptzTo(after.CreatedAt),
+		AfterID:        afterID,
+		RowLimit:       int32(limit),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]domain.AuditEvent, 0, len(rows))
+	for _, row := range rows {
+		event, err := mapAuditEvent(row)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, event)
+	}
+	return result, nil
+}
+
+// DeleteRange remove os eventos anteriores ao encadeamento criados em
+// [from, to) e o inicio da cadeia ate maxSeq, parando antes do primeiro evento
+// criado a partir de to. Como created_at e seq nao andam sempre juntos, so um
+// prefixo continuo de seq sai da tabela; o hash do ultimo evento removido vira
+// a ancora de VerifyChain na mesma transacao.
+func (r *AuditRepository) DeleteRange(ctx context.Context, from, to time.Time, maxSeq int64) (int64, error) {
+	if r == nil || r.db == nil {
+		return 0, errors.New("audit repository unavailable")
+	}
+
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return 0, err
+	}
+	defer func() {
+		_ = tx.Rollback(ctx)
+	}()
+	q := sqlc.New(tx)
+
+	deleted, err := q.DeleteUnchainedAuditEventsRange(ctx, sqlc.DeleteUnchainedAuditEventsRangeParams{
+		CreatedFrom: timestamptzTo(from),
+		CreatedTo:   timestamptzTo(to),
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	anchor, err := q.LockAuditChainAnchor(ctx)
+	if err != nil {
+		return 0, err
+	}
+	end, err := q.GetAuditChainPrefixEnd(ctx, sqlc.GetAuditChainPrefixEndParams{
+		MaxSeq:    maxSeq,
+		CreatedTo: timestamptzTo(to),
+	})
+	if err != nil {
+		return 0, err
+	}
+	if end > anchor.Seq {
+		seq := pgtype.Int8{Int64: end, Valid: true}
+		hash, err := q.GetAuditEventHashBySeq(ctx, seq)
+		if err != nil {
+			return 0, err
+		}
+		chained, err := q.DeleteAuditChainPrefix(ctx, seq)
+		if err != nil {
+			return 0, err
+		}
+		if err := q.SetAuditChainAnchor(ctx, sqlc.SetAuditChainAnchorParams{Seq: end, Hash: hash}); err != nil {
+			return 0, err
+		}
+		deleted += chained
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return 0, err
+	}
+	return deleted, nil
+}
+
+// auditFilterParams converte o filtro; ids invalidos nao casam com nenhum
+// evento e sao sinalizados com ok falso.
+func auditFilterParams(filter ports.AuditFilter) (sqlc.CountAuditEventsParams, bool) {
+	actorID, err := stringToUUID(filter.ActorID)
+	if err != nil {
+		return sqlc.CountAuditEventsParams{}, false
+	}
+	entityID, err := stringToUUID(filter.EntityID)
+	if err != nil {
+		return sqlc.CountAuditEventsParams{}, false
+	}
+
+	return sqlc.CountAuditEventsParams{
+		ActorID:      actorID,
+		EntityType:   filter.EntityType,
+		EntityID:     entityID,
+		ActionPrefix: filter.ActionPrefix,
+		CreatedFrom:  timestamptzTo(filter.From),
+		CreatedTo:    timestamptzTo(filter.To),
+	}, true
+}
+
+func mapAuditEvent(row sqlc.AuditEvent) (domain.AuditEvent, error) {
+	metadata := map[string]any{}
+	if len(row.Metadata) > 0 {
+		if err := json.Unmarshal(row.Metadata, &metadata); err != nil {
+			return domain.AuditEvent{}, err
+		}
+	}
+
+	return domain.AuditEvent{
+		ID:         uuidToString(row.ID),
+		ActorID:    uuidToString(row.ActorID),
+		ActorRole:  textFrom(row.ActorRole),
+		Action:     row.Action,
+		EntityType: row.EntityType,
+		EntityID:   uuidToString(row.EntityID),
+		Metadata:   metadata,
+		IP:         textFrom(row.Ip),
+		UserAgent:  textFrom(row.UserAgent),
+		CreatedAt:  timeFrom(row.CreatedAt),
+		Seq:        row.Seq.Int64,
+		PrevHash:   hex.EncodeToString(row.PrevHash),
+		Hash:       hex.EncodeToString(row.Hash),
+	}, nil
 }
@@ -37,6 +37,11 @@ func (r *BillingPeriodRepository) Create(ctx context.Context, period domain.Bill
 		AmountDueCents:  period.AmountDueCents,
 		AmountPaidCents: period.AmountPaidCents,
 		Status:          sqlc.BillingPeriodStatus(period.Status),
+		DiscountCents:   period.DiscountCents,
+		ShiftDays:       int32(period.ShiftDays),
+		Installment:     int32(max(period.Installment, 1)),
+		Installments:    int32(max(period.Installments, 1)),
+		DueDate:         dateTo(&period.DueDate),
 	}
 
 	created, err := r.queries.CreateBillingPeriod(ctx, params)
@@ -58,9 +63,22 @@ func (r *BillingPeriodRepository) Update(ctx context.Context, period domain.Bill
 	}
 
 	params := sqlc.UpdateBillingPeriodParams{
-		ID:              id,
-		AmountPaidCents: period.AmountPaidCents,
-		Status:          sqlc.BillingPeriodStatus(period.Status),
+		ID:               id,
+		AmountPaidCents:  period.AmountPaidCents,
+		Status:           sqlc.BillingPeriodStatus(period.Status),
+		FeeCents:         period.FeeCents,
+		FeeAccruedOn:     dateTo(period.FeeAccruedOn),
+		PeriodEnd:        dateTo(&period.PeriodEnd),
+		AmountDueCents:   period.AmountDueCents,
+		DiscountCents:    period.DiscountCents,
+		PeriodStart:      dateTo(&period.PeriodStart),
+		ShiftDays:        int32(period.ShiftDays),
+		FrozenDays:       int32(period.FrozenDays),
+		InterestCentDays: period.InterestCentDays,
+		DueDate:          dateTo(&period.DueDate),
+	}
+	if period.FeeWaivedAt != nil {
+		params.FeeWaivedAt = timestamptzTo(*period.FeeWaivedAt)
 	}
 
 	updated, err := r.queries.UpdateBillingPeriod(ctx, params)
@@ -90,6 +108,34 @@ func (r *BillingPeriodRepository) ListBySubscription(ctx context.Context, subscr
 	return result, nil
 }
 
+func (r *BillingPeriodRepository) ListBySubscriptionPage(ctx context.Context, subscriptionID string, page ports.PageRequest) ([]domain.BillingPeriod, error) {
+	uuidValue, err := stringToUUID(subscriptionID)
+	if err != nil || !uuidValue.Valid {
+		return nil, err
+	}
+	afterCreatedAt, afterID, err := pageCursorTo(page.After)
+	if err != nil {
+		return nil, err
+	}
+
+	periods, err := r.queries.ListBillingPeriodsBySubscriptionPage(ctx, sqlc.ListBillingPeriodsBySubscriptionPageParams{
+		SubscriptionID: uuidValue,
+		AfterCreatedAt: afterCreatedAt,
+		AfterID:        afterID,
+		RowLimit:       int32(page.Limit),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]domain.BillingPeriod, 0, len(periods))
+	for _, period := range periods {
+		result = append(result, mapBillingPeriod(period))
+	}
+
+	return result, nil
+}
+
 func (r *BillingPeriodRepository) ListOpenBySubscription(ctx context.Context, subscriptionID string) ([]domain.BillingPeriod, error) {
 	uuidValue, err := stringToUUID(subscriptionID)
 	if err != nil || !uuidValue.Valid {
@@ -128,15 +174,29 @@ func mapBillingPeriod(period sqlc.BillingPeriod) domain.BillingPeriod {
 		return domain.BillingPeriod{}
 	}
 
-	return domain.BillingPeriod{
-		ID:              uuidToString(period.ID),
-		SubscriptionID:  uuidToString(period.SubscriptionID),
-		PeriodStart:     dateFromValue(period.PeriodStart),
-		PeriodEnd:       dateFromValue(period.PeriodEnd),
-		AmountDueCents:  period.AmountDueCents,
-		AmountPaidCents: period.AmountPaidCents,
-		Status:          domain.BillingPeriodStatus(period.Status),
-		CreatedAt:       timeFrom(period.CreatedAt),
-		UpdatedAt:       timeFrom(period.UpdatedAt),
-	}
+	result := domain.BillingPeriod{
+		ID:               uuidToString(period.ID),
+		SubscriptionID:   uuidToString(period.SubscriptionID),
+		PeriodStart:      dateFromValue(period.PeriodStart),
+		PeriodEnd:        dateFromValue(period.PeriodEnd),
+		AmountDueCents:   period.AmountDueCents,
+		DiscountCents:    period.DiscountCents,
+		AmountPaidCents:  period.AmountPaidCents,
+		Status:           domain.BillingPeriodStatus(period.Status),
+		FeeCents:         period.FeeCents,
+		FeeAccruedOn:     dateFrom(period.FeeAccruedOn),
+		ShiftDays:        int(period.ShiftDays),
+		FrozenDays:       int(period.FrozenDays),
+		Installment:      int(period.Installment),
+		Installments:     int(period.Installments),
+		InterestCentDays: period.InterestCentDays,
+		DueDate:          dateFromValue(period.DueDate),
+		CreatedAt:        timeFrom(period.CreatedAt),
+		UpdatedAt:        timeFrom(period.UpdatedAt),
+	}
+	if period.FeeWaivedAt.Valid {
+		waivedAt := period.FeeWaivedAt.Time
+		result.FeeWaivedAt = &waivedAt
+	}
+	return result
 }
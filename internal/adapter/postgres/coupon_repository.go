@@ -0,0 +1,125 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+
+	"github.com/PabloPavan/jaiu/internal/adapter/postgres/sqlc"
+	"github.com/PabloPavan/jaiu/internal/domain"
+	"github.com/PabloPavan/jaiu/internal/ports"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type CouponRepository struct {
+	queries *sqlc.Queries
+}
+
+func NewCouponRepository(pool *pgxpool.Pool) *CouponRepository {
+	return &CouponRepository{queries: sqlc.New(pool)}
+}
+
+func NewCouponRepositoryWithQueries(queries *sqlc.Queries) *CouponRepository {
+	return &CouponRepository{queries: queries}
+}
+
+func (r *CouponRepository) Create(ctx context.Context, coupon domain.Coupon) (domain.Coupon, error) {
+	params := sqlc.CreateCouponParams{
+		Code:             coupon.Code,
+		Description:      textTo(coupon.Description),
+		DiscountKind:     string(coupon.Discount.Kind),
+		DiscountValue:    coupon.Discount.Value,
+		DiscountDuration: string(coupon.Discount.Duration),
+		DiscountPeriods:  int32(coupon.Discount.Periods),
+		Active:           coupon.Active,
+	}
+
+	created, err := r.queries.CreateCoupon(ctx, params)
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23505" && pgErr.ConstraintName == "coupons_code_idx" {
+			return domain.Coupon{}, ports.ErrConflict
+		}
+		return domain.Coupon{}, err
+	}
+
+	return mapCoupon(created), nil
+}
+
+func (r *CouponRepository) SetActive(ctx context.Context, id string, active bool) (domain.Coupon, error) {
+	uuidValue, err := stringToUUID(id)
+	if err != nil || !uuidValue.Valid {
+		return domain.Coupon{}, ports.ErrNotFound
+	}
+
+	updated, err := r.queries.SetCouponActive(ctx, sqlc.SetCouponActiveParams{ID: uuidValue, Active: active})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return domain.Coupon{}, ports.ErrNotFound
+		}
+		return domain.Coupon{}, err
+	}
+
+	return mapCoupon(updated), nil
+}
+
+func (r *CouponRepository) FindByID(ctx context.Context, id string) (domain.Coupon, error) {
+	uuidValue, err := stringToUUID(id)
+	if err != nil || !uuidValue.Valid {
+		return domain.Coupon{}, ports.ErrNotFound
+	}
+
+	coupon, err := r.queries.GetCoupon(ctx, uuidValue)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return domain.Coupon{}, ports.ErrNotFound
+		}
+		return domain.Coupon{}, err
+	}
+
+	return mapCoupon(coupon), nil
+}
+
+func (r *CouponRepository) FindByCode(ctx context.Context, code string) (domain.Coupon, error) {
+	coupon, err := r.queries.GetCouponByCode(ctx, code)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return domain.Coupon{}, ports.ErrNotFound
+		}
+		return domain.Coupon{}, err
+	}
+
+	return mapCoupon(coupon), nil
+}
+
+func (r *CouponRepository) List(ctx context.Context) ([]domain.Coupon, error) {
+	coupons, err := r.queries.ListCoupons(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]domain.Coupon, 0, len(coupons))
+	for _, coupon := range coupons {
+		result = append(result, mapCoupon(coupon))
+	}
+
+	return result, nil
+}
+
+func mapCoupon(coupon sqlc.Coupon) domain.Coupon {
+	return domain.Coupon{
+		ID:          uuidToString(coupon.ID),
+		Code:        coupon.Code,
+		Description: textFrom(coupon.Description),
+		Discount: domain.Discount{
+			Kind:     domain.DiscountKind(coupon.DiscountKind),
+			Value:    coupon.DiscountValue,
+			Duration: domain.DiscountDuration(coupon.DiscountDuration),
+			Periods:  int(coupon.DiscountPeriods),
+		},
+		Active:    coupon.Active,
+		CreatedAt: timeFrom(coupon.CreatedAt),
+		UpdatedAt: timeFrom(coupon.UpdatedAt),
+	}
+}
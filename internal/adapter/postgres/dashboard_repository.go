@@ -0,0 +1,34 @@
+package postgres
+
+import (
+	"context"
+	"time"
+
+	"github.com/PabloPavan/jaiu/internal/adapter/postgres/sqlc"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type DashboardRepository struct {
+	queries *sqlc.Queries
+}
+
+func NewDashboardRepository(pool *pgxpool.Pool) *DashboardRepository {
+	return &DashboardRepository{queries: sqlc.New(pool)}
+}
+
+func (r *DashboardRepository) CountActiveStudents(ctx context.Context) (int64, error) {
+	return r.queries.CountActiveStudents(ctx)
+}
+
+func (r *DashboardRepository) CountOverdueSubscriptions(ctx context.Context) (int64, error) {
+	return r.queries.CountOverdueSubscriptions(ctx)
+}
+
+func (r *DashboardRepository) OutstandingDue(ctx context.Context, start, end time.Time) (int64, error) {
+	params := sqlc.OutstandingDueByPeriodParams{
+		Column1: dateTo(&start),
+		Column2: dateTo(&end),
+	}
+
+	return r.queries.OutstandingDueByPeriod(ctx, params)
+}
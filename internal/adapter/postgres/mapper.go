@@ -3,6 +3,7 @@ package postgres
 import (
 	"time"
 
+	"github.com/PabloPavan/jaiu/internal/ports"
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5/pgtype"
 )
@@ -40,6 +41,13 @@ func timeFrom(value pgtype.Timestamptz) time.Time {
 	return value.Time
 }
 
+func timestamptzTo(value time.Time) pgtype.Timestamptz {
+	if value.IsZero() {
+		return pgtype.Timestamptz{}
+	}
+	return pgtype.Timestamptz{Time: value, Valid: true}
+}
+
 func textFrom(value pgtype.Text) string {
 	if !value.Valid {
 		return ""
@@ -75,3 +83,13 @@ func dateFromValue(value pgtype.Date) time.Time {
 	}
 	return value.Time
 }
+
+// pageCursorTo converte o cursor de paginacao nos parametros after_created_at e
+// after_id das consultas paginadas; o cursor zero vira NULL.
+func pageCursorTo(cursor ports.PageCursor) (pgtype.Timestamptz, pgtype.UUID, error) {
+	afterID, err := stringToUUID(cursor.ID)
+	if err != nil {
+		return pgtype.Timestamptz{}, pgtype.UUID{}, err
+	}
+	return timestamptzTo(cursor.CreatedAt), afterID, nil
+}
@@ -0,0 +1,167 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/PabloPavan/jaiu/internal/adapter/postgres/sqlc"
+	"github.com/PabloPavan/jaiu/internal/domain"
+	"github.com/PabloPavan/jaiu/internal/ports"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type MFARepository struct {
+	queries *sqlc.Queries
+}
+
+func NewMFARepository(pool *pgxpool.Pool) *MFARepository {
+	return &MFARepository{queries: sqlc.New(pool)}
+}
+
+func (r *MFARepository) FindByUser(ctx context.Context, userID string) (domain.UserMFA, error) {
+	uuidValue, err := stringToUUID(userID)
+	if err != nil || !uuidValue.Valid {
+		return domain.UserMFA{}, ports.ErrNotFound
+	}
+
+	mfa, err := r.queries.GetUserMFA(ctx, uuidValue)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return domain.UserMFA{}, ports.ErrNotFound
+		}
+		return domain.UserMFA{}, err
+	}
+
+	return mapUserMFA(mfa), nil
+}
+
+// SaveSecret grava um novo segredo pendente, descartando uma inscricao anterior.
+func (r *MFARepository) SaveSecret(ctx context.Context, userID, secret string) (domain.UserMFA, error) {
+	uuidValue, err := stringToUUID(userID)
+	if err != nil || !uuidValue.Valid {
+		return domain.UserMFA{}, ports.ErrNotFound
+	}
+
+	mfa, err := r.queries.UpsertUserMFASecret(ctx, sqlc.UpsertUserMFASecretParams{
+		UserID: uuidValue,
+		Secret: secret,
+	})
+	if err != nil {
+		return domain.UserMFA{}, err
+	}
+
+	return mapUserMFA(mfa), nil
+}
+
+func (r *MFARepository) Enable(ctx context.Context, userID string, enabledAt time.Time, step int64) (domain.UserMFA, error) {
+	uuidValue, err := stringToUUID(userID)
+	if err != nil || !uuidValue.Valid {
+		return domain.UserMFA{}, ports.ErrNotFound
+	}
+
+	mfa, err := r.queries.EnableUserMFA(ctx, sqlc.EnableUserMFAParams{
+		UserID:       uuidValue,
+		EnabledAt:    pgtype.Timestamptz{Time: enabledAt, Valid: true},
+		LastUsedStep: step,
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return domain.UserMFA{}, ports.ErrNotFound
+		}
+		return domain.UserMFA{}, err
+	}
+
+	return mapUserMFA(mfa), nil
+}
+
+// AdvanceStep registra o intervalo TOTP usado. Retorna false quando o intervalo
+// ja foi consumido, impedindo a reutilizacao do mesmo codigo.
+func (r *MFARepository) AdvanceStep(ctx context.Context, userID string, step int64) (bool, error) {
+	uuidValue, err := stringToUUID(userID)
+	if err != nil || !uuidValue.Valid {
+		return false, ports.ErrNotFound
+	}
+
+	rows, err := r.queries.AdvanceUserMFAStep(ctx, sqlc.AdvanceUserMFAStepParams{
+		UserID:       uuidValue,
+		LastUsedStep: step,
+	})
+	if err != nil {
+		return false, err
+	}
+
+	return rows > 0, nil
+}
+
+func (r *MFARepository) Delete(ctx context.Context, userID string) error {
+	uuidValue, err := stringToUUID(userID)
+	if err != nil || !uuidValue.Valid {
+		return ports.ErrNotFound
+	}
+
+	return r.queries.DeleteUserMFA(ctx, uuidValue)
+}
+
+func (r *MFARepository) ReplaceRecoveryCodes(ctx context.Context, userID string, codeHashes []string) error {
+	uuidValue, err := stringToUUID(userID)
+	if err != nil || !uuidValue.Valid {
+		return ports.ErrNotFound
+	}
+
+	return r.queries.ReplaceRecoveryCodes(ctx, sqlc.ReplaceRecoveryCodesParams{
+		UserID:     uuidValue,
+		CodeHashes: codeHashes,
+	})
+}
+
+// ConsumeRecoveryCode marca o codigo como usado. Retorna false quando o codigo
+// nao existe ou ja foi usado.
+func (r *MFARepository) ConsumeRecoveryCode(ctx context.Context, userID, codeHash string, usedAt time.Time) (bool, error) {
+	uuidValue, err := stringToUUID(userID)
+	if err != nil || !uuidValue.Valid {
+		return false, ports.ErrNotFound
+	}
+
+	rows, err := r.queries.ConsumeRecoveryCode(ctx, sqlc.ConsumeRecoveryCodeParams{
+		UserID:   uuidValue,
+		CodeHash: codeHash,
+		UsedAt:   pgtype.Timestamptz{Time: usedAt, Valid: true},
+	})
+	if err != nil {
+		return false, err
+	}
+
+	return rows > 0, nil
+}
+
+func (r *MFARepository) CountRecoveryCodes(ctx context.Context, userID string) (int, error) {
+	uuidValue, err := stringToUUID(userID)
+	if err != nil || !uuidValue.Valid {
+		return 0, ports.ErrNotFound
+	}
+
+	count, err := r.queries.CountRecoveryCodes(ctx, uuidValue)
+	if err != nil {
+		return 0, err
+	}
+
+	return int(count), nil
+}
+
+func mapUserMFA(mfa sqlc.UserMfa) domain.UserMFA {
+	result := domain.UserMFA{
+		UserID:       uuidToString(mfa.UserID),
+		Secret:       mfa.Secret,
+		LastUsedStep: mfa.LastUsedStep,
+		CreatedAt:    timeFrom(mfa.CreatedAt),
+		UpdatedAt:    timeFrom(mfa.UpdatedAt),
+	}
+	if mfa.EnabledAt.Valid {
+		enabledAt := mfa.EnabledAt.Time
+		result.EnabledAt = &enabledAt
+	}
+	return result
+}
@@ -0,0 +1,84 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/PabloPavan/jaiu/internal/adapter/postgres/sqlc"
+	"github.com/PabloPavan/jaiu/internal/domain"
+	"github.com/PabloPavan/jaiu/internal/ports"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type PasswordResetRepository struct {
+	queries *sqlc.Queries
+}
+
+func NewPasswordResetRepository(pool *pgxpool.Pool) *PasswordResetRepository {
+	return &PasswordResetRepository{queries: sqlc.New(pool)}
+}
+
+func (r *PasswordResetRepository) Create(ctx context.Context, token domain.PasswordResetToken) (domain.PasswordResetToken, error) {
+	userID, err := stringToUUID(token.UserID)
+	if err != nil || !userID.Valid {
+		return domain.PasswordResetToken{}, ports.ErrNotFound
+	}
+
+	created, err := r.queries.CreatePasswordResetToken(ctx, sqlc.CreatePasswordResetTokenParams{
+		UserID:    userID,
+		TokenHash: token.TokenHash,
+		ExpiresAt: pgtype.Timestamptz{Time: token.ExpiresAt, Valid: true},
+	})
+	if err != nil {
+		return domain.PasswordResetToken{}, err
+	}
+
+	return mapPasswordResetToken(created), nil
+}
+
+// Consume marca o token como usado apenas se ainda valido, garantindo uso unico
+// mesmo com requisicoes concorrentes.
+func (r *PasswordResetRepository) Consume(ctx context.Context, tokenHash string, now time.Time) (domain.PasswordResetToken, error) {
+	consumed, err := r.queries.ConsumePasswordResetToken(ctx, sqlc.ConsumePasswordResetTokenParams{
+		TokenHash: tokenHash,
+		UsedAt:    pgtype.Timestamptz{Time: now, Valid: true},
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return domain.PasswordResetToken{}, ports.ErrNotFound
+		}
+		return domain.PasswordResetToken{}, err
+	}
+
+	return mapPasswordResetToken(consumed), nil
+}
+
+func (r *PasswordResetRepository) InvalidateByUser(ctx context.Context, userID string, now time.Time) error {
+	uuidValue, err := stringToUUID(userID)
+	if err != nil || !uuidValue.Valid {
+		return ports.ErrNotFound
+	}
+
+	return r.queries.InvalidatePasswordResetTokens(ctx, sqlc.InvalidatePasswordResetTokensParams{
+		UserID: uuidValue,
+		UsedAt: pgtype.Timestamptz{Time: now, Valid: true},
+	})
+}
+
+func mapPasswordResetToken(token sqlc.PasswordResetToken) domain.PasswordResetToken {
+	result := domain.PasswordResetToken{
+		ID:        uuidToString(token.ID),
+		UserID:    uuidToString(token.UserID),
+		TokenHash: token.TokenHash,
+		ExpiresAt: timeFrom(token.ExpiresAt),
+		CreatedAt: timeFrom(token.CreatedAt),
+	}
+	if token.UsedAt.Valid {
+		usedAt := token.UsedAt.Time
+		result.UsedAt = &usedAt
+	}
+	return result
+}
@@ -44,6 +44,29 @@ func (r *PaymentAllocationRepository) Create(ctx context.Context, allocation dom
 	return r.queries.CreatePaymentAllocation(ctx, params)
 }
 
+// Update grava o novo valor de uma alocacao ja existente; zero mantem a
+// linha sem efeito no estorno.
+func (r *PaymentAllocationRepository) Update(ctx context.Context, allocation domain.PaymentAllocation) error {
+	paymentID, err := stringToUUID(allocation.PaymentID)
+	if err != nil || !paymentID.Valid {
+		return err
+	}
+	periodID, err := stringToUUID(allocation.BillingPeriodID)
+	if err != nil || !periodID.Valid {
+		return err
+	}
+
+	if allocation.AmountCents < 0 {
+		return errors.New("valor de alocacao invalido")
+	}
+
+	return r.queries.UpdatePaymentAllocationAmount(ctx, sqlc.UpdatePaymentAllocationAmountParams{
+		PaymentID:       paymentID,
+		BillingPeriodID: periodID,
+		AmountCents:     allocation.AmountCents,
+	})
+}
+
 func (r *PaymentAllocationRepository) ListByPayment(ctx context.Context, paymentID string) ([]domain.PaymentAllocation, error) {
 	uuidValue, err := stringToUUID(paymentID)
 	if err != nil || !uuidValue.Valid {
@@ -55,17 +78,21 @@ func (r *PaymentAllocationRepository) ListByPayment(ctx context.Context, payment
 		return nil, err
 	}
 
-	result := make([]domain.PaymentAllocation, 0, len(allocations))
-	for _, allocation := range allocations {
-		result = append(result, domain.PaymentAllocation{
-			PaymentID:       uuidToString(allocation.PaymentID),
-			BillingPeriodID: uuidToString(allocation.BillingPeriodID),
-			AmountCents:     allocation.AmountCents,
-			CreatedAt:       timeFrom(allocation.CreatedAt),
-		})
+	return mapPaymentAllocations(allocations), nil
+}
+
+func (r *PaymentAllocationRepository) ListByBillingPeriod(ctx context.Context, periodID string) ([]domain.PaymentAllocation, error) {
+	uuidValue, err := stringToUUID(periodID)
+	if err != nil || !uuidValue.Valid {
+		return nil, err
+	}
+
+	allocations, err := r.queries.ListPaymentAllocationsByBillingPeriod(ctx, uuidValue)
+	if err != nil {
+		return nil, err
 	}
 
-	return result, nil
+	return mapPaymentAllocations(allocations), nil
 }
 
 func (r *PaymentAllocationRepository) DeleteByPayment(ctx context.Context, paymentID string) error {
@@ -76,3 +103,16 @@ func (r *PaymentAllocationRepository) DeleteByPayment(ctx context.Context, payme
 
 	return r.queries.DeletePaymentAllocationsByPayment(ctx, uuidValue)
 }
+
+func mapPaymentAllocations(allocations []sqlc.PaymentAllocation) []domain.PaymentAllocation {
+	result := make([]domain.PaymentAllocation, 0, len(allocations))
+	for _, allocation := range allocations {
+		result = append(result, domain.PaymentAllocation{
+			PaymentID:       uuidToString(allocation.PaymentID),
+			BillingPeriodID: uuidToString(allocation.BillingPeriodID),
+			AmountCents:     allocation.AmountCents,
+			CreatedAt:       timeFrom(allocation.CreatedAt),
+		})
+	}
+	return result
+}
@@ -0,0 +1,78 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+
+	"github.com/PabloPavan/jaiu/internal/adapter/postgres/sqlc"
+	"github.com/PabloPavan/jaiu/internal/domain"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type PaymentCreditTransferRepository struct {
+	queries *sqlc.Queries
+}
+
+func NewPaymentCreditTransferRepository(pool *pgxpool.Pool) *PaymentCreditTransferRepository {
+	return &PaymentCreditTransferRepository{queries: sqlc.New(pool)}
+}
+
+func NewPaymentCreditTransferRepositoryWithQueries(queries *sqlc.Queries) *PaymentCreditTransferRepository {
+	return &PaymentCreditTransferRepository{queries: queries}
+}
+
+func (r *PaymentCreditTransferRepository) Create(ctx context.Context, transfer domain.PaymentCreditTransfer) error {
+	paymentID, err := stringToUUID(transfer.PaymentID)
+	if err != nil || !paymentID.Valid {
+		return err
+	}
+	subscriptionID, err := stringToUUID(transfer.SubscriptionID)
+	if err != nil || !subscriptionID.Valid {
+		return err
+	}
+
+	if transfer.AmountCents <= 0 || transfer.CreditCents < 0 || transfer.CreditCents > transfer.AmountCents {
+		return errors.New("valor de transferencia invalido")
+	}
+
+	return r.queries.CreatePaymentCreditTransfer(ctx, sqlc.CreatePaymentCreditTransferParams{
+		PaymentID:      paymentID,
+		SubscriptionID: subscriptionID,
+		AmountCents:    transfer.AmountCents,
+		CreditCents:    transfer.CreditCents,
+	})
+}
+
+func (r *PaymentCreditTransferRepository) ListByPayment(ctx context.Context, paymentID string) ([]domain.PaymentCreditTransfer, error) {
+	uuidValue, err := stringToUUID(paymentID)
+	if err != nil || !uuidValue.Valid {
+		return nil, err
+	}
+
+	transfers, err := r.queries.ListPaymentCreditTransfersByPayment(ctx, uuidValue)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]domain.PaymentCreditTransfer, 0, len(transfers))
+	for _, transfer := range transfers {
+		result = append(result, domain.PaymentCreditTransfer{
+			PaymentID:      uuidToString(transfer.PaymentID),
+			SubscriptionID: uuidToString(transfer.SubscriptionID),
+			AmountCents:    transfer.AmountCents,
+			CreditCents:    transfer.CreditCents,
+			CreatedAt:      timeFrom(transfer.CreatedAt),
+		})
+	}
+
+	return result, nil
+}
+
+func (r *PaymentCreditTransferRepository) DeleteByPayment(ctx context.Context, paymentID string) error {
+	uuidValue, err := stringToUUID(paymentID)
+	if err != nil || !uuidValue.Valid {
+		return err
+	}
+
+	return r.queries.DeletePaymentCreditTransfersByPayment(ctx, uuidValue)
+}
@@ -151,6 +151,34 @@ func (r *PaymentRepository) ListBySubscription(ctx context.Context, subscription
 	return result, nil
 }
 
+func (r *PaymentRepository) ListBySubscriptionPage(ctx context.Context, subscriptionID string, page ports.PageRequest) ([]domain.Payment, error) {
+	uuidValue, err := stringToUUID(subscriptionID)
+	if err != nil || !uuidValue.Valid {
+		return nil, err
+	}
+	afterCreatedAt, afterID, err := pageCursorTo(page.After)
+	if err != nil {
+		return nil, err
+	}
+
+	payments, err := r.queries.ListPaymentsBySubscriptionPage(ctx, sqlc.ListPaymentsBySubscriptionPageParams{
+		SubscriptionID: uuidValue,
+		AfterCreatedAt: afterCreatedAt,
+		AfterID:        afterID,
+		RowLimit:       int32(page.Limit),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]domain.Payment, 0, len(payments))
+	for _, payment := range payments {
+		result = append(result, mapPayment(payment))
+	}
+
+	return result, nil
+}
+
 func (r *PaymentRepository) ListByPeriod(ctx context.Context, start, end time.Time) ([]domain.Payment, error) {
 	params := sqlc.ListPaymentsByPeriodParams{
 		PaidAt:   pgtype.Timestamptz{Time: start, Valid: true},
@@ -170,6 +198,31 @@ func (r *PaymentRepository) ListByPeriod(ctx context.Context, start, end time.Ti
 	return result, nil
 }
 
+func (r *PaymentRepository) ListByPeriodPage(ctx context.Context, start, end time.Time, page ports.PageRequest) ([]domain.Payment, error) {
+	afterCreatedAt, afterID, err := pageCursorTo(page.After)
+	if err != nil {
+		return nil, err
+	}
+
+	payments, err := r.queries.ListPaymentsByPeriodPage(ctx, sqlc.ListPaymentsByPeriodPageParams{
+		PaidFrom:       pgtype.Timestamptz{Time: start, Valid: true},
+		PaidTo:         pgtype.Timestamptz{Time: end, Valid: true},
+		AfterCreatedAt: afterCreatedAt,
+		AfterID:        afterID,
+		RowLimit:       int32(page.Limit),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]domain.Payment, 0, len(payments))
+	for _, payment := range payments {
+		result = append(result, mapPayment(payment))
+	}
+
+	return result, nil
+}
+
 func mapPayment(payment sqlc.Payment) domain.Payment {
 	if !payment.ID.Valid {
 		return domain.Payment{}
@@ -26,11 +26,15 @@ func NewPlanRepositoryWithQueries(queries *sqlc.Queries) *PlanRepository {
 
 func (r *PlanRepository) Create(ctx context.Context, plan domain.Plan) (domain.Plan, error) {
 	params := sqlc.CreatePlanParams{
-		Name:         plan.Name,
-		DurationDays: int32(plan.DurationDays),
-		PriceCents:   plan.PriceCents,
-		Active:       plan.Active,
-		Description:  pgtype.Text{String: plan.Description, Valid: plan.Description != ""},
+		Name:            plan.Name,
+		DurationDays:    int32(plan.DurationDays),
+		PriceCents:      plan.PriceCents,
+		Active:          plan.Active,
+		Description:     pgtype.Text{String: plan.Description, Valid: plan.Description != ""},
+		LateFeeBps:      int32(plan.LateFeeBasisPoints),
+		LateInterestBps: int32(plan.LateInterestBasisPoints),
+		MaxFreezeDays:   int32(plan.MaxFreezeDays),
+		Installments:    int32(plan.InstallmentCount()),
 	}
 
 	created, err := r.queries.CreatePlan(ctx, params)
@@ -48,12 +52,16 @@ func (r *PlanRepository) Update(ctx context.Context, plan domain.Plan) (domain.P
 	}
 
 	params := sqlc.UpdatePlanParams{
-		ID:           id,
-		Name:         plan.Name,
-		DurationDays: int32(plan.DurationDays),
-		PriceCents:   plan.PriceCents,
-		Active:       plan.Active,
-		Description:  pgtype.Text{String: plan.Description, Valid: plan.Description != ""},
+		ID:              id,
+		Name:            plan.Name,
+		DurationDays:    int32(plan.DurationDays),
+		PriceCents:      plan.PriceCents,
+		Active:          plan.Active,
+		Description:     pgtype.Text{String: plan.Description, Valid: plan.Description != ""},
+		LateFeeBps:      int32(plan.LateFeeBasisPoints),
+		LateInterestBps: int32(plan.LateInterestBasisPoints),
+		MaxFreezeDays:   int32(plan.MaxFreezeDays),
+		Installments:    int32(plan.InstallmentCount()),
 	}
 
 	updated, err := r.queries.UpdatePlan(ctx, params)
@@ -95,15 +103,42 @@ func (r *PlanRepository) ListActive(ctx context.Context) ([]domain.Plan, error)
 	return result, nil
 }
 
+func (r *PlanRepository) ListActivePage(ctx context.Context, page ports.PageRequest) ([]domain.Plan, error) {
+	afterCreatedAt, afterID, err := pageCursorTo(page.After)
+	if err != nil {
+		return nil, err
+	}
+
+	plans, err := r.queries.ListActivePlansPage(ctx, sqlc.ListActivePlansPageParams{
+		AfterCreatedAt: afterCreatedAt,
+		AfterID:        afterID,
+		RowLimit:       int32(page.Limit),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]domain.Plan, 0, len(plans))
+	for _, plan := range plans {
+		result = append(result, mapPlan(plan))
+	}
+
+	return result, nil
+}
+
 func mapPlan(plan sqlc.Plan) domain.Plan {
 	return domain.Plan{
-		ID:           uuidToString(plan.ID),
-		Name:         plan.Name,
-		DurationDays: int(plan.DurationDays),
-		PriceCents:   plan.PriceCents,
-		Active:       plan.Active,
-		Description:  textFrom(plan.Description),
-		CreatedAt:    timeFrom(plan.CreatedAt),
-		UpdatedAt:    timeFrom(plan.UpdatedAt),
+		ID:                      uuidToString(plan.ID),
+		Name:                    plan.Name,
+		DurationDays:            int(plan.DurationDays),
+		PriceCents:              plan.PriceCents,
+		Active:                  plan.Active,
+		Description:             textFrom(plan.Description),
+		LateFeeBasisPoints:      int(plan.LateFeeBps),
+		LateInterestBasisPoints: int(plan.LateInterestBps),
+		MaxFreezeDays:           int(plan.MaxFreezeDays),
+		Installments:            int(plan.Installments),
+		CreatedAt:               timeFrom(plan.CreatedAt),
+		UpdatedAt:               timeFrom(plan.UpdatedAt),
 	}
 }
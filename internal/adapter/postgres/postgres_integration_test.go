@@ -4,13 +4,18 @@ package postgres
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"os"
 	"path/filepath"
+	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
 	"github.com/PabloPavan/jaiu/internal/domain"
 	"github.com/PabloPavan/jaiu/internal/ports"
+	"github.com/PabloPavan/jaiu/internal/service"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
@@ -63,12 +68,16 @@ func resetDatabase(t *testing.T, pool *pgxpool.Pool) {
 	_, err := pool.Exec(ctx, `
 		TRUNCATE TABLE
 			payment_allocations,
+			payment_credit_transfers,
 			billing_periods,
 			subscription_balances,
 			payments,
 			subscriptions,
 			students,
 			plans,
+			password_reset_tokens,
+			user_recovery_codes,
+			user_mfa,
 			users,
 			audit_events,
 			imagekit_outbox
@@ -77,6 +86,12 @@ func resetDatabase(t *testing.T, pool *pgxpool.Pool) {
 	if err != nil {
 		t.Fatalf("truncate database: %v", err)
 	}
+	if _, err := pool.Exec(ctx, "UPDATE audit_chain_head SET seq = 0, hash = ''::bytea"); err != nil {
+		t.Fatalf("reset audit chain: %v", err)
+	}
+	if _, err := pool.Exec(ctx, "UPDATE audit_chain_anchor SET seq = 0, hash = ''::bytea"); err != nil {
+		t.Fatalf("reset audit chain anchor: %v", err)
+	}
 }
 
 func loadFixtures(t *testing.T, pool *pgxpool.Pool) {
@@ -305,6 +320,16 @@ func TestPaymentRepositoryIntegration(t *testing.T) {
 	if updated.Reference != "ref-updated" {
 		t.Fatalf("expected updated reference, got %q", updated.Reference)
 	}
+
+	first, err := repo.ListBySubscriptionPage(ctx, fixtureSubscriptionID, ports.PageRequest{Limit: 1})
+	if err != nil || len(first) != 1 {
+		t.Fatalf("list first page: %d payments (%v)", len(first), err)
+	}
+	after := ports.PageCursor{CreatedAt: first[0].CreatedAt, ID: first[0].ID}
+	second, err := repo.ListBySubscriptionPage(ctx, fixtureSubscriptionID, ports.PageRequest{After: after, Limit: 2})
+	if err != nil || len(second) != 1 || second[0].ID == first[0].ID {
+		t.Fatalf("unexpected second page: %#v (%v)", second, err)
+	}
 }
 
 // Testa billing periods com listagem, criacao, update e overdue.
@@ -342,10 +367,14 @@ func TestBillingPeriodRepositoryIntegration(t *testing.T) {
 		AmountDueCents:  1000,
 		AmountPaidCents: 0,
 		Status:          domain.BillingOpen,
+		DueDate:         time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC),
 	})
 	if err != nil {
 		t.Fatalf("create period: %v", err)
 	}
+	if !created.DueDate.Equal(time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Fatalf("unexpected due date: %v", created.DueDate)
+	}
 	created.AmountPaidCents = 1000
 	created.Status = domain.BillingPaid
 	updated, err := repo.Update(ctx, created)
@@ -427,6 +456,56 @@ func TestPaymentAllocationRepositoryIntegration(t *testing.T) {
 	}); err != nil {
 		t.Fatalf("create allocation: %v", err)
 	}
+
+	if err := repo.Update(ctx, domain.PaymentAllocation{
+		PaymentID:       fixturePaymentID,
+		BillingPeriodID: fixturePeriodOpenID,
+		AmountCents:     200,
+	}); err != nil {
+		t.Fatalf("update allocation: %v", err)
+	}
+	byPeriod, err := repo.ListByBillingPeriod(ctx, fixturePeriodOpenID)
+	if err != nil {
+		t.Fatalf("list allocations by period: %v", err)
+	}
+	if len(byPeriod) != 1 || byPeriod[0].AmountCents != 200 {
+		t.Fatalf("expected updated allocation, got %#v", byPeriod)
+	}
+}
+
+// Testa o registro de credito levado por uma troca de plano.
+func TestPaymentCreditTransferRepositoryIntegration(t *testing.T) {
+	pool := setupIntegration(t)
+	repo := NewPaymentCreditTransferRepository(pool)
+	ctx := context.Background()
+
+	if err := repo.Create(ctx, domain.PaymentCreditTransfer{
+		PaymentID:      fixturePaymentID,
+		SubscriptionID: fixtureSubscriptionID,
+		AmountCents:    600,
+		CreditCents:    100,
+	}); err != nil {
+		t.Fatalf("create transfer: %v", err)
+	}
+
+	transfers, err := repo.ListByPayment(ctx, fixturePaymentID)
+	if err != nil {
+		t.Fatalf("list transfers: %v", err)
+	}
+	if len(transfers) != 1 || transfers[0].AmountCents != 600 || transfers[0].CreditCents != 100 {
+		t.Fatalf("unexpected transfers: %#v", transfers)
+	}
+
+	if err := repo.DeleteByPayment(ctx, fixturePaymentID); err != nil {
+		t.Fatalf("delete transfers: %v", err)
+	}
+	transfers, err = repo.ListByPayment(ctx, fixturePaymentID)
+	if err != nil {
+		t.Fatalf("list transfers after delete: %v", err)
+	}
+	if len(transfers) != 0 {
+		t.Fatalf("expected 0 transfers, got %d", len(transfers))
+	}
 }
 
 // Testa CRUD de usuarios e find por email.
@@ -456,6 +535,172 @@ func TestUserRepositoryIntegration(t *testing.T) {
 	if created.ID == "" {
 		t.Fatal("expected created user id")
 	}
+
+	if _, err := repo.Create(ctx, domain.User{Name: "Dup", Email: "user2@example.com", PasswordHash: "hash", Role: domain.RoleOperator}); !errors.Is(err, ports.ErrConflict) {
+		t.Fatalf("expected conflict for duplicated email, got %v", err)
+	}
+
+	created.Role = domain.RoleAdmin
+	updated, err := repo.Update(ctx, created)
+	if err != nil {
+		t.Fatalf("update user: %v", err)
+	}
+	if updated.Role != domain.RoleAdmin {
+		t.Fatalf("expected role admin, got %q", updated.Role)
+	}
+
+	deactivated, err := repo.SetActive(ctx, created.ID, false)
+	if err != nil {
+		t.Fatalf("set active: %v", err)
+	}
+	if deactivated.Active {
+		t.Fatal("expected user to be inactive")
+	}
+
+	if err := repo.UpdatePassword(ctx, created.ID, "hash3", true); err != nil {
+		t.Fatalf("update password: %v", err)
+	}
+	loaded, err := repo.FindByID(ctx, created.ID)
+	if err != nil {
+		t.Fatalf("find by id: %v", err)
+	}
+	if loaded.PasswordHash != "hash3" {
+		t.Fatalf("expected updated hash, got %q", loaded.PasswordHash)
+	}
+	if !loaded.MustChangePassword {
+		t.Fatal("expected must change password flag")
+	}
+
+	users, err := repo.List(ctx)
+	if err != nil {
+		t.Fatalf("list users: %v", err)
+	}
+	if len(users) != 2 {
+		t.Fatalf("expected 2 users, got %d", len(users))
+	}
+}
+
+// Testa que o token de recuperacao de senha e de uso unico e respeita a expiracao.
+func TestPasswordResetRepositoryIntegration(t *testing.T) {
+	pool := setupIntegration(t)
+	users := NewUserRepository(pool)
+	repo := NewPasswordResetRepository(pool)
+	ctx := context.Background()
+
+	user, err := users.FindByEmail(ctx, fixtureUserEmail)
+	if err != nil {
+		t.Fatalf("find user: %v", err)
+	}
+
+	now := time.Now()
+	if _, err := repo.Create(ctx, domain.PasswordResetToken{UserID: user.ID, TokenHash: "hash-valid", ExpiresAt: now.Add(time.Hour)}); err != nil {
+		t.Fatalf("create token: %v", err)
+	}
+	if _, err := repo.Create(ctx, domain.PasswordResetToken{UserID: user.ID, TokenHash: "hash-expired", ExpiresAt: now.Add(-time.Minute)}); err != nil {
+		t.Fatalf("create expired token: %v", err)
+	}
+
+	consumed, err := repo.Consume(ctx, "hash-valid", now)
+	if err != nil {
+		t.Fatalf("consume token: %v", err)
+	}
+	if consumed.UserID != user.ID || consumed.UsedAt == nil {
+		t.Fatalf("unexpected consumed token: %#v", consumed)
+	}
+	if _, err := repo.Consume(ctx, "hash-valid", now); !errors.Is(err, ports.ErrNotFound) {
+		t.Fatalf("expected not found on reuse, got %v", err)
+	}
+	if _, err := repo.Consume(ctx, "hash-expired", now); !errors.Is(err, ports.ErrNotFound) {
+		t.Fatalf("expected not found for expired token, got %v", err)
+	}
+
+	if _, err := repo.Create(ctx, domain.PasswordResetToken{UserID: user.ID, TokenHash: "hash-other", ExpiresAt: now.Add(time.Hour)}); err != nil {
+		t.Fatalf("create token: %v", err)
+	}
+	if err := repo.InvalidateByUser(ctx, user.ID, now); err != nil {
+		t.Fatalf("invalidate tokens: %v", err)
+	}
+	if _, err := repo.Consume(ctx, "hash-other", now); !errors.Is(err, ports.ErrNotFound) {
+		t.Fatalf("expected not found after invalidate, got %v", err)
+	}
+}
+
+// Testa inscricao, intervalo TOTP sem reutilizacao e codigos de recuperacao.
+func TestMFARepositoryIntegration(t *testing.T) {
+	pool := setupIntegration(t)
+	users := NewUserRepository(pool)
+	repo := NewMFARepository(pool)
+	ctx := context.Background()
+
+	user, err := users.FindByEmail(ctx, fixtureUserEmail)
+	if err != nil {
+		t.Fatalf("find user: %v", err)
+	}
+
+	if _, err := repo.FindByUser(ctx, user.ID); !errors.Is(err, ports.ErrNotFound) {
+		t.Fatalf("expected not found before enrollment, got %v", err)
+	}
+	if _, err := repo.SaveSecret(ctx, user.ID, "SECRET1"); err != nil {
+		t.Fatalf("save secret: %v", err)
+	}
+	saved, err := repo.SaveSecret(ctx, user.ID, "SECRET2")
+	if err != nil {
+		t.Fatalf("replace secret: %v", err)
+	}
+	if saved.Secret != "SECRET2" || saved.Enabled() {
+		t.Fatalf("expected pending replaced secret, got %#v", saved)
+	}
+
+	enabled, err := repo.Enable(ctx, user.ID, time.Now(), 100)
+	if err != nil {
+		t.Fatalf("enable: %v", err)
+	}
+	if !enabled.Enabled() || enabled.LastUsedStep != 100 {
+		t.Fatalf("unexpected enabled mfa: %#v", enabled)
+	}
+	if _, err := repo.Enable(ctx, user.ID, time.Now(), 101); !errors.Is(err, ports.ErrNotFound) {
+		t.Fatalf("expected not found when already enabled, got %v", err)
+	}
+
+	if advanced, err := repo.AdvanceStep(ctx, user.ID, 100); err != nil || advanced {
+		t.Fatalf("expected used step to be rejected, got %v (%v)", advanced, err)
+	}
+	if advanced, err := repo.AdvanceStep(ctx, user.ID, 101); err != nil || !advanced {
+		t.Fatalf("expected next step to be accepted, got %v (%v)", advanced, err)
+	}
+
+	if err := repo.ReplaceRecoveryCodes(ctx, user.ID, []string{"h1", "h2", "h3"}); err != nil {
+		t.Fatalf("replace codes: %v", err)
+	}
+	if err := repo.ReplaceRecoveryCodes(ctx, user.ID, []string{"h4", "h5"}); err != nil {
+		t.Fatalf("replace codes again: %v", err)
+	}
+	if consumed, err := repo.ConsumeRecoveryCode(ctx, user.ID, "h1", time.Now()); err != nil || consumed {
+		t.Fatalf("expected replaced code to be invalid, got %v (%v)", consumed, err)
+	}
+	if consumed, err := repo.ConsumeRecoveryCode(ctx, user.ID, "h4", time.Now()); err != nil || !consumed {
+		t.Fatalf("expected code to be consumed, got %v (%v)", consumed, err)
+	}
+	if consumed, err := repo.ConsumeRecoveryCode(ctx, user.ID, "h4", time.Now()); err != nil || consumed {
+		t.Fatalf("expected code reuse to fail, got %v (%v)", consumed, err)
+	}
+	count, err := repo.CountRecoveryCodes(ctx, user.ID)
+	if err != nil {
+		t.Fatalf("count codes: %v", err)
+	}
+	if count != 1 {
+		t.Fatalf("expected 1 remaining code, got %d", count)
+	}
+
+	if err := repo.Delete(ctx, user.ID); err != nil {
+		t.Fatalf("delete: %v", err)
+	}
+	if _, err := repo.FindByUser(ctx, user.ID); !errors.Is(err, ports.ErrNotFound) {
+		t.Fatalf("expected not found after delete, got %v", err)
+	}
+	if count, _ := repo.CountRecoveryCodes(ctx, user.ID); count != 0 {
+		t.Fatalf("expected recovery codes removed, got %d", count)
+	}
 }
 
 // Testa gravacao de eventos de auditoria no banco.
@@ -486,4 +731,553 @@ func TestAuditRepositoryIntegration(t *testing.T) {
 	if count != 1 {
 		t.Fatalf("expected 1 audit event, got %d", count)
 	}
+
+	other := domain.AuditEvent{
+		Action:     "payment.reverse.success",
+		EntityType: "payment",
+		ActorID:    fixtureStudentID,
+	}
+	if err := repo.Record(ctx, other); err != nil {
+		t.Fatalf("record audit: %v", err)
+	}
+
+	events, err := repo.Search(ctx, ports.AuditFilter{EntityType: "student", EntityID: fixtureStudentTwoID})
+	if err != nil {
+		t.Fatalf("search audit: %v", err)
+	}
+	if len(events) != 1 || events[0].Action != "integration.test" || events[0].Metadata["source"] != "integration" {
+		t.Fatalf("unexpected audit events: %#v", events)
+	}
+
+	total, err := repo.Count(ctx, ports.AuditFilter{ActorID: fixtureStudentID, ActionPrefix: "payment."})
+	if err != nil {
+		t.Fatalf("count audit: %v", err)
+	}
+	if total != 1 {
+		t.Fatalf("expected 1 payment event, got %d", total)
+	}
+
+	future := time.Now().Add(time.Hour)
+	total, err = repo.Count(ctx, ports.AuditFilter{From: future})
+	if err != nil {
+		t.Fatalf("count audit: %v", err)
+	}
+	if total != 0 {
+		t.Fatalf("expected no events after %v, got %d", future, total)
+	}
+
+	events, err = repo.Search(ctx, ports.AuditFilter{EntityID: "invalido"})
+	if err != nil || len(events) != 0 {
+		t.Fatalf("expected no events for invalid id, got %#v err=%v", events, err)
+	}
+}
+
+// Testa o encadeamento dos eventos de auditoria e a deteccao de adulteracao.
+func TestAuditChainIntegration(t *testing.T) {
+	pool := setupIntegration(t)
+	repo := NewAuditRepository(pool)
+	ctx := context.Background()
+
+	var wg sync.WaitGroup
+	errs := make(chan error, 10)
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			errs <- repo.Record(ctx, domain.AuditEvent{
+				Action:     "chain.test",
+				EntityType: "student",
+				EntityID:   fixtureStudentID,
+				Metadata:   map[string]any{"index": i, "nested": map[string]any{"b": 2, "a": 1}},
+			})
+		}(i)
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		if err != nil {
+			t.Fatalf("record audit: %v", err)
+		}
+	}
+
+	report, err := repo.VerifyChain(ctx)
+	if err != nil {
+		t.Fatalf("verify chain: %v", err)
+	}
+	if report.Broken || report.Checked != 10 || report.HeadSeq != 10 {
+		t.Fatalf("unexpected report: %#v", report)
+	}
+
+	if _, err := pool.Exec(ctx, `UPDATE audit_events SET metadata = '{"index": 99}' WHERE seq = 4`); err != nil {
+		t.Fatalf("tamper audit event: %v", err)
+	}
+	report, err = repo.VerifyChain(ctx)
+	if err != nil {
+		t.Fatalf("verify chain: %v", err)
+	}
+	if !report.Broken || report.BrokenSeq != 4 {
+		t.Fatalf("expected broken link at seq 4, got %#v", report)
+	}
+
+	if _, err := pool.Exec(ctx, "DELETE FROM audit_events WHERE seq >= 4"); err != nil {
+		t.Fatalf("delete audit events: %v", err)
+	}
+	report, err = repo.VerifyChain(ctx)
+	if err != nil {
+		t.Fatalf("verify chain: %v", err)
+	}
+	if !report.Broken || report.BrokenSeq != 10 {
+		t.Fatalf("expected truncated tail to be detected, got %#v", report)
+	}
+}
+
+// Testa a listagem paginada e a remocao de eventos por intervalo.
+func TestAuditRepositoryRangeIntegration(t *testing.T) {
+	pool := setupIntegration(t)
+	repo := NewAuditRepository(pool)
+	ctx := context.Background()
+
+	for i := 0; i < 3; i++ {
+		if err := repo.Record(ctx, domain.AuditEvent{Action: "range.test", EntityType: "student"}); err != nil {
+			t.Fatalf("record audit: %v", err)
+		}
+	}
+	if _, err := pool.Exec(ctx, "UPDATE audit_events SET created_at = '2024-01-10T12:00:00Z'::timestamptz + (seq || ' minutes')::interval"); err != nil {
+		t.Fatalf("backdate audit events: %v", err)
+	}
+
+	oldest, ok, err := repo.OldestCreatedAt(ctx)
+	if err != nil || !ok {
+		t.Fatalf("oldest audit event: ok=%v err=%v", ok, err)
+	}
+	if !oldest.Equal(time.Date(2024, 1, 10, 12, 1, 0, 0, time.UTC)) {
+		t.Fatalf("unexpected oldest event: %v", oldest)
+	}
+
+	from := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC)
+	first, err := repo.ListRange(ctx, from, to, ports.AuditCursor{}, 2)
+	if err != nil {
+		t.Fatalf("list range: %v", err)
+	}
+	if len(first) != 2 || first[0].Seq != 1 || first[1].Seq != 2 || first[0].Hash == "" {
+		t.Fatalf("unexpected first page: %#v", first)
+	}
+	rest, err := repo.ListRange(ctx, from, to, ports.AuditCursor{CreatedAt: first[1].CreatedAt, ID: first[1].ID}, 2)
+	if err != nil {
+		t.Fatalf("list range: %v", err)
+	}
+	if len(rest) != 1 || rest[0].Seq != 3 {
+		t.Fatalf("unexpected second page: %#v", rest)
+	}
+
+	deleted, err := repo.DeleteRange(ctx, from, to, 2)
+	if err != nil {
+		t.Fatalf("delete range: %v", err)
+	}
+	if deleted != 2 {
+		t.Fatalf("expected 2 deleted events up to seq 2, got %d", deleted)
+	}
+	if report, err := repo.VerifyChain(ctx); err != nil || report.Broken || report.Checked != 1 {
+		t.Fatalf("expected chain anchored after purge, got %#v err=%v", report, err)
+	}
+	deleted, err = repo.DeleteRange(ctx, from, to, 3)
+	if err != nil {
+		t.Fatalf("delete range: %v", err)
+	}
+	if deleted != 1 {
+		t.Fatalf("expected 1 deleted event, got %d", deleted)
+	}
+	if _, ok, err := repo.OldestCreatedAt(ctx); err != nil || ok {
+		t.Fatalf("expected no events left, ok=%v err=%v", ok, err)
+	}
+
+	// Um evento de abril com seq menor que um de marco interrompe o prefixo:
+	// o de marco fica na tabela para nao abrir um buraco na cadeia.
+	for i := 0; i < 3; i++ {
+		if err := repo.Record(ctx, domain.AuditEvent{Action: "range.test", EntityType: "student"}); err != nil {
+			t.Fatalf("record audit: %v", err)
+		}
+	}
+	if _, err := pool.Exec(ctx, `UPDATE audit_events SET created_at = CASE seq
+		WHEN 4 THEN '2024-03-10T12:00:00Z'::timestamptz
+		WHEN 5 THEN '2024-04-10T12:00:00Z'::timestamptz
+		ELSE '2024-03-11T12:00:00Z'::timestamptz END`); err != nil {
+		t.Fatalf("backdate audit events: %v", err)
+	}
+	deleted, err = repo.DeleteRange(ctx, time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC), time.Date(2024, 4, 1, 0, 0, 0, 0, time.UTC), 6)
+	if err != nil {
+		t.Fatalf("delete range: %v", err)
+	}
+	if deleted != 1 {
+		t.Fatalf("expected only seq 4 deleted, got %d", deleted)
+	}
+	report, err := repo.VerifyChain(ctx)
+	if err != nil || report.Broken || report.Checked != 2 {
+		t.Fatalf("expected contiguous chain after purge, got %#v err=%v", report, err)
+	}
+}
+
+// Testa o resumo de acessos e a busca do aluno pela chave da foto.
+func TestStudentAccessIntegration(t *testing.T) {
+	pool := setupIntegration(t)
+	ctx := context.Background()
+
+	students := NewStudentRepository(pool)
+	if _, err := pool.Exec(ctx, "UPDATE students SET photo_object_key = 'photo-key' WHERE id = $1", fixtureStudentID); err != nil {
+		t.Fatalf("set photo key: %v", err)
+	}
+	id, err := students.FindIDByPhotoObjectKey(ctx, "photo-key")
+	if err != nil || id != fixtureStudentID {
+		t.Fatalf("expected fixture student, got %q err=%v", id, err)
+	}
+	if _, err := students.FindIDByPhotoObjectKey(ctx, "missing"); !errors.Is(err, ports.ErrNotFound) {
+		t.Fatalf("expected not found, got %v", err)
+	}
+
+	audit := NewAuditRepository(pool)
+	for _, source := range []string{"detail", "photo", "detail"} {
+		if err := audit.Record(ctx, domain.AuditEvent{
+			Action:     "student.view",
+			EntityType: "student",
+			EntityID:   fixtureStudentID,
+			ActorID:    fixtureStudentTwoID,
+			ActorRole:  "operator",
+			Metadata:   map[string]any{"source": source},
+		}); err != nil {
+			t.Fatalf("record view: %v", err)
+		}
+	}
+
+	summaries, err := audit.AccessSummary(ctx, "student.view", "student", fixtureStudentID)
+	if err != nil {
+		t.Fatalf("access summary: %v", err)
+	}
+	if len(summaries) != 1 || summaries[0].ActorID != fixtureStudentTwoID || summaries[0].Views != 3 || len(summaries[0].Sources) != 2 {
+		t.Fatalf("unexpected summaries: %#v", summaries)
+	}
+}
+
+// Testa que o TxRunner desfaz alteracoes e eventos de auditoria juntos.
+func TestTxRunnerIntegration(t *testing.T) {
+	pool := setupIntegration(t)
+	ctx := context.Background()
+	runner := NewTxRunner(pool)
+
+	errRollback := errors.New("rollback")
+	err := runner.RunInTx(ctx, ports.TxOptions{Isolation: ports.TxRepeatableRead}, func(ctx context.Context, deps ports.TxDependencies) error {
+		student, err := deps.Students.FindByID(ctx, fixtureStudentID)
+		if err != nil {
+			return err
+		}
+		student.Status = domain.StudentInactive
+		if _, err := deps.Students.Update(ctx, student); err != nil {
+			return err
+		}
+		if err := deps.Audit.Record(ctx, domain.AuditEvent{Action: "student.update.success", EntityType: "student", EntityID: fixtureStudentID}); err != nil {
+			return err
+		}
+		return errRollback
+	})
+	if !errors.Is(err, errRollback) {
+		t.Fatalf("expected rollback error, got %v", err)
+	}
+
+	student, err := NewStudentRepository(pool).FindByID(ctx, fixtureStudentID)
+	if err != nil {
+		t.Fatalf("find student: %v", err)
+	}
+	if student.Status != domain.StudentActive {
+		t.Fatalf("expected status to be rolled back, got %q", student.Status)
+	}
+	audit := NewAuditRepository(pool)
+	count, err := audit.Count(ctx, ports.AuditFilter{ActionPrefix: "student.update"})
+	if err != nil || count != 0 {
+		t.Fatalf("expected no audit events after rollback, got %d err=%v", count, err)
+	}
+
+	err = runner.RunInTx(ctx, ports.TxOptions{}, func(ctx context.Context, deps ports.TxDependencies) error {
+		return deps.Audit.Record(ctx, domain.AuditEvent{Action: "student.update.success", EntityType: "student", EntityID: fixtureStudentID})
+	})
+	if err != nil {
+		t.Fatalf("run tx: %v", err)
+	}
+	report, err := audit.VerifyChain(ctx)
+	if err != nil || report.Broken || report.Checked != 1 {
+		t.Fatalf("unexpected chain report: %#v err=%v", report, err)
+	}
+
+	// Um evento que nao entra na cadeia desfaz a alteracao de negocio.
+	err = runner.RunInTx(ctx, ports.TxOptions{}, func(ctx context.Context, deps ports.TxDependencies) error {
+		student, err := deps.Students.FindByID(ctx, fixtureStudentID)
+		if err != nil {
+			return err
+		}
+		student.Status = domain.StudentInactive
+		if _, err := deps.Students.Update(ctx, student); err != nil {
+			return err
+		}
+		return deps.Audit.Record(ctx, domain.AuditEvent{Action: "student.update.success", EntityType: "student", EntityID: "not-a-uuid"})
+	})
+	if err == nil {
+		t.Fatal("expected audit append error")
+	}
+	student, err = NewStudentRepository(pool).FindByID(ctx, fixtureStudentID)
+	if err != nil || student.Status != domain.StudentActive {
+		t.Fatalf("expected update rolled back with the audit event, got %q err=%v", student.Status, err)
+	}
+}
+
+// Testa que duas transacoes serializaveis em conflito concluem apos a repeticao.
+func TestTxRunnerRetriesSerializationFailureIntegration(t *testing.T) {
+	pool := setupIntegration(t)
+	ctx := context.Background()
+	runner := NewPaymentTxRunner(pool)
+
+	var (
+		attempts atomic.Int32
+		ready    sync.WaitGroup
+		wg       sync.WaitGroup
+	)
+	ready.Add(2)
+	errs := make(chan error, 2)
+	for i := 0; i < 2; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			first := true
+			errs <- runner.RunSerializable(ctx, func(ctx context.Context, deps ports.PaymentDependencies) error {
+				attempts.Add(1)
+				existing, err := deps.Payments.ListBySubscription(ctx, fixtureSubscriptionID)
+				if err != nil {
+					return err
+				}
+				if first {
+					first = false
+					ready.Done()
+					ready.Wait()
+				}
+				_, err = deps.Payments.Create(ctx, domain.Payment{
+					SubscriptionID: fixtureSubscriptionID,
+					PaidAt:         time.Date(2024, 1, 10, 0, 0, 0, 0, time.UTC),
+					AmountCents:    int64(100 * (len(existing) + 1)),
+					Method:         domain.PaymentCash,
+					Status:         domain.PaymentConfirmed,
+					Kind:           domain.PaymentFull,
+				})
+				return err
+			})
+		}(i)
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		if err != nil {
+			t.Fatalf("run serializable: %v", err)
+		}
+	}
+
+	if attempts.Load() < 3 {
+		t.Fatalf("expected a retried attempt, got %d attempts", attempts.Load())
+	}
+	payments, err := NewPaymentRepository(pool).ListBySubscription(ctx, fixtureSubscriptionID)
+	if err != nil {
+		t.Fatalf("list payments: %v", err)
+	}
+	amounts := map[int64]bool{}
+	for _, payment := range payments {
+		amounts[payment.AmountCents] = true
+	}
+	if len(payments) != 3 || len(amounts) != 3 {
+		t.Fatalf("expected serialized inserts with distinct amounts, got %#v", payments)
+	}
+}
+
+// Testa que pagamentos registrados em paralelo, com transacoes repetidas por
+// conflito, deixam todos os eventos de auditoria na cadeia.
+func TestPaymentRegisterConcurrentAuditIntegration(t *testing.T) {
+	pool := setupIntegration(t)
+	ctx := context.Background()
+	audit := NewAuditRepository(pool)
+	payments := service.NewPaymentService(
+		NewPaymentRepository(pool),
+		NewSubscriptionRepository(pool),
+		NewPlanRepository(pool),
+		NewBillingPeriodRepository(pool),
+		NewSubscriptionBalanceRepository(pool),
+		NewPaymentAllocationRepository(pool),
+		audit,
+		NewPaymentTxRunner(pool),
+	)
+
+	const workers = 4
+	var wg sync.WaitGroup
+	created := make(chan domain.Payment, workers)
+	failed := make(chan error, workers)
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			payment, err := payments.Register(ctx, domain.Payment{
+				SubscriptionID: fixtureSubscriptionID,
+				AmountCents:    100,
+				Method:         domain.PaymentCash,
+				IdempotencyKey: fmt.Sprintf("concurrent-%d", i),
+			})
+			if err != nil {
+				failed <- err
+				return
+			}
+			created <- payment
+		}(i)
+	}
+	wg.Wait()
+	close(created)
+	close(failed)
+
+	events, err := audit.Search(ctx, ports.AuditFilter{ActionPrefix: "payment.create", Limit: 100})
+	if err != nil {
+		t.Fatalf("search audit: %v", err)
+	}
+	successes := map[string]int{}
+	failures := 0
+	for _, event := range events {
+		switch event.Action {
+		case "payment.create.success":
+			successes[event.EntityID]++
+		case "payment.create.failure":
+			failures++
+		}
+	}
+
+	registered := 0
+	for payment := range created {
+		registered++
+		if successes[payment.ID] != 1 {
+			t.Fatalf("expected one success event for payment %s, got %d", payment.ID, successes[payment.ID])
+		}
+	}
+	if registered == 0 {
+		t.Fatalf("expected at least one registered payment, got errors %v", <-failed)
+	}
+	if len(successes) != registered || failures != len(failed) {
+		t.Fatalf("expected %d success and %d failure events, got %v and %d", registered, len(failed), successes, failures)
+	}
+
+	report, err := audit.VerifyChain(ctx)
+	if err != nil || report.Broken {
+		t.Fatalf("unexpected chain report: %#v err=%v", report, err)
+	}
+}
+
+// Testa que o intervalo entre tentativas cresce e respeita o limite.
+func TestTxRunnerBackoffIntegration(t *testing.T) {
+	for attempt := 0; attempt < 10; attempt++ {
+		delay := backoff(attempt)
+		limit := txBackoffBase << attempt
+		if limit <= 0 || limit > txBackoffMax {
+			limit = txBackoffMax
+		}
+		if delay < limit/2 || delay > limit {
+			t.Fatalf("attempt %d: delay %s outside [%s, %s]", attempt, delay, limit/2, limit)
+		}
+	}
+}
+
+// Testa consultas de relatorio sobre os dados de fixture.
+func TestReportRepositoryIntegration(t *testing.T) {
+	pool := setupIntegration(t)
+	repo := NewReportRepository(pool)
+	ctx := context.Background()
+
+	summary, err := repo.RevenueByPeriod(ctx, time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatalf("revenue by period: %v", err)
+	}
+	if summary.TotalCents != 1000 {
+		t.Fatalf("expected revenue 1000, got %d", summary.TotalCents)
+	}
+
+	delinquent, err := repo.DelinquentSubscriptions(ctx, time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatalf("delinquent subscriptions: %v", err)
+	}
+	found := false
+	for _, item := range delinquent {
+		if item.SubscriptionID == fixtureSubscriptionID {
+			found = true
+			if item.StudentName == "" || item.PlanName == "" {
+				t.Fatalf("expected student and plan names, got %#v", item)
+			}
+		}
+	}
+	if !found {
+		t.Fatalf("expected subscription %s to be delinquent", fixtureSubscriptionID)
+	}
+}
+
+// Testa os contadores do dashboard sobre os dados de fixture.
+func TestDashboardRepositoryIntegration(t *testing.T) {
+	pool := setupIntegration(t)
+	repo := NewDashboardRepository(pool)
+	ctx := context.Background()
+
+	active, err := repo.CountActiveStudents(ctx)
+	if err != nil {
+		t.Fatalf("count active students: %v", err)
+	}
+	if active != 1 {
+		t.Fatalf("expected 1 active student, got %d", active)
+	}
+
+	overdue, err := repo.CountOverdueSubscriptions(ctx)
+	if err != nil {
+		t.Fatalf("count overdue subscriptions: %v", err)
+	}
+	if overdue != 0 {
+		t.Fatalf("expected 0 overdue subscriptions, got %d", overdue)
+	}
+
+	outstanding, err := repo.OutstandingDue(ctx, time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC), time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatalf("outstanding due: %v", err)
+	}
+	if outstanding != 1000 {
+		t.Fatalf("expected outstanding 1000, got %d", outstanding)
+	}
+}
+
+// Testa que a receita a vencer do mes segue o vencimento gravado na
+// competencia e nao o seu inicio: comecando no dia 28 com pagamento no dia 5,
+// ela vence no mes seguinte.
+func TestDashboardOutstandingDueByDueDateIntegration(t *testing.T) {
+	pool := setupIntegration(t)
+	repo := NewDashboardRepository(pool)
+	ctx := context.Background()
+
+	_, err := pool.Exec(ctx, `
+		INSERT INTO subscriptions (id, student_id, plan_id, start_date, end_date, status, price_cents, payment_day, auto_renew)
+		VALUES ('77777777-7777-7777-7777-777777777777', '22222222-2222-2222-2222-222222222222', 'aaaaaaaa-aaaa-aaaa-aaaa-aaaaaaaaaaaa', '2024-03-28', '2024-04-27', 'active', 1500, 5, false);
+		INSERT INTO billing_periods (subscription_id, period_start, period_end, amount_due_cents, amount_paid_cents, status, due_date)
+		VALUES ('77777777-7777-7777-7777-777777777777', '2024-03-28', '2024-04-05', 1500, 0, 'open', '2024-04-05');
+	`)
+	if err != nil {
+		t.Fatalf("insert subscription: %v", err)
+	}
+
+	march, err := repo.OutstandingDue(ctx, time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC), time.Date(2024, 4, 1, 0, 0, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatalf("outstanding due march: %v", err)
+	}
+	if march != 0 {
+		t.Fatalf("expected nothing due in march, got %d", march)
+	}
+
+	april, err := repo.OutstandingDue(ctx, time.Date(2024, 4, 1, 0, 0, 0, 0, time.UTC), time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatalf("outstanding due april: %v", err)
+	}
+	if april != 1500 {
+		t.Fatalf("expected 1500 due in april, got %d", april)
+	}
 }
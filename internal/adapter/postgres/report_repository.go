@@ -0,0 +1,105 @@
+package postgres
+
+import (
+	"context"
+	"time"
+
+	"github.com/PabloPavan/jaiu/internal/adapter/postgres/sqlc"
+	"github.com/PabloPavan/jaiu/internal/domain"
+	"github.com/PabloPavan/jaiu/internal/ports"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type ReportRepository struct {
+	queries *sqlc.Queries
+}
+
+func NewReportRepository(pool *pgxpool.Pool) *ReportRepository {
+	return &ReportRepository{queries: sqlc.New(pool)}
+}
+
+func (r *ReportRepository) RevenueByPeriod(ctx context.Context, start, end time.Time) (ports.RevenueSummary, error) {
+	params := sqlc.RevenueByPeriodParams{
+		Column1: pgtype.Timestamptz{Time: start, Valid: true},
+		Column2: pgtype.Timestamptz{Time: end, Valid: true},
+	}
+
+	row, err := r.queries.RevenueByPeriod(ctx, params)
+	if err != nil {
+		return ports.RevenueSummary{}, err
+	}
+
+	return ports.RevenueSummary{
+		Start:            start,
+		End:              end,
+		TotalCents:       row.TotalCents,
+		GrossBilledCents: row.GrossBilledCents,
+		DiscountCents:    row.DiscountCents,
+	}, nil
+}
+
+func (r *ReportRepository) StudentsByStatus(ctx context.Context) ([]ports.StudentStatusSummary, error) {
+	rows, err := r.queries.StudentsByStatus(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]ports.StudentStatusSummary, 0, len(rows))
+	for _, row := range rows {
+		result = append(result, ports.StudentStatusSummary{
+			Status: domain.StudentStatus(row.Status),
+			Total:  row.Total,
+		})
+	}
+
+	return result, nil
+}
+
+func (r *ReportRepository) DelinquentSubscriptions(ctx context.Context, now time.Time) ([]ports.DelinquentSubscription, error) {
+	rows, err := r.queries.DelinquentSubscriptions(ctx, dateTo(&now))
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]ports.DelinquentSubscription, 0, len(rows))
+	for _, row := range rows {
+		result = append(result, ports.DelinquentSubscription{
+			SubscriptionID: uuidToString(row.SubscriptionID),
+			StudentID:      uuidToString(row.StudentID),
+			PlanID:         uuidToString(row.PlanID),
+			StudentName:    row.StudentName,
+			PlanName:       row.PlanName,
+			EndDate:        dateFromValue(row.EndDate),
+			DaysOverdue:    int(row.DaysOverdue),
+		})
+	}
+
+	return result, nil
+}
+
+func (r *ReportRepository) UpcomingDue(ctx context.Context, start, end time.Time) ([]ports.DueSubscription, error) {
+	params := sqlc.UpcomingDueParams{
+		Column1: dateTo(&start),
+		Column2: dateTo(&end),
+	}
+
+	rows, err := r.queries.UpcomingDue(ctx, params)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]ports.DueSubscription, 0, len(rows))
+	for _, row := range rows {
+		result = append(result, ports.DueSubscription{
+			SubscriptionID: uuidToString(row.SubscriptionID),
+			StudentID:      uuidToString(row.StudentID),
+			PlanID:         uuidToString(row.PlanID),
+			StudentName:    row.StudentName,
+			PlanName:       row.PlanName,
+			EndDate:        dateFromValue(row.EndDate),
+		})
+	}
+
+	return result, nil
+}
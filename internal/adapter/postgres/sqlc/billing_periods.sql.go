@@ -18,11 +18,16 @@ INSERT INTO billing_periods (
   period_end,
   amount_due_cents,
   amount_paid_cents,
-  status
+  status,
+  discount_cents,
+  shift_days,
+  installment,
+  installments,
+  due_date
 ) VALUES (
-  $1, $2, $3, $4, $5, $6
+  $1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11
 )
-RETURNING id, subscription_id, period_start, period_end, amount_due_cents, amount_paid_cents, status, created_at, updated_at
+RETURNING id, subscription_id, period_start, period_end, amount_due_cents, amount_paid_cents, status, created_at, updated_at, fee_cents, fee_accrued_on, fee_waived_at, discount_cents, shift_days, frozen_days, installment, installments, interest_cent_days, due_date
 `
 
 type CreateBillingPeriodParams struct {
@@ -32,6 +37,11 @@ type CreateBillingPeriodParams struct {
 	AmountDueCents  int64               `json:"amount_due_cents"`
 	AmountPaidCents int64               `json:"amount_paid_cents"`
 	Status          BillingPeriodStatus `json:"status"`
+	DiscountCents   int64               `json:"discount_cents"`
+	ShiftDays       int32               `json:"shift_days"`
+	Installment     int32               `json:"installment"`
+	Installments    int32               `json:"installments"`
+	DueDate         pgtype.Date         `json:"due_date"`
 }
 
 func (q *Queries) CreateBillingPeriod(ctx context.Context, arg CreateBillingPeriodParams) (BillingPeriod, error) {
@@ -42,6 +52,11 @@ func (q *Queries) CreateBillingPeriod(ctx context.Context, arg CreateBillingPeri
 		arg.AmountDueCents,
 		arg.AmountPaidCents,
 		arg.Status,
+		arg.DiscountCents,
+		arg.ShiftDays,
+		arg.Installment,
+		arg.Installments,
+		arg.DueDate,
 	)
 	var i BillingPeriod
 	err := row.Scan(
@@ -54,12 +69,22 @@ func (q *Queries) CreateBillingPeriod(ctx context.Context, arg CreateBillingPeri
 		&i.Status,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.FeeCents,
+		&i.FeeAccruedOn,
+		&i.FeeWaivedAt,
+		&i.DiscountCents,
+		&i.ShiftDays,
+		&i.FrozenDays,
+		&i.Installment,
+		&i.Installments,
+		&i.InterestCentDays,
+		&i.DueDate,
 	)
 	return i, err
 }
 
 const listBillingPeriodsBySubscription = `-- name: ListBillingPeriodsBySubscription :many
-SELECT id, subscription_id, period_start, period_end, amount_due_cents, amount_paid_cents, status, created_at, updated_at FROM billing_periods WHERE subscription_id = $1 ORDER BY period_start
+SELECT id, subscription_id, period_start, period_end, amount_due_cents, amount_paid_cents, status, created_at, updated_at, fee_cents, fee_accrued_on, fee_waived_at, discount_cents, shift_days, frozen_days, installment, installments, interest_cent_days, due_date FROM billing_periods WHERE subscription_id = $1 ORDER BY period_start
 `
 
 func (q *Queries) ListBillingPeriodsBySubscription(ctx context.Context, subscriptionID pgtype.UUID) ([]BillingPeriod, error) {
@@ -81,6 +106,78 @@ func (q *Queries) ListBillingPeriodsBySubscription(ctx context.Context, subscrip
 			&i.Status,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.FeeCents,
+			&i.FeeAccruedOn,
+			&i.FeeWaivedAt,
+			&i.DiscountCents,
+			&i.ShiftDays,
+			&i.FrozenDays,
+			&i.Installment,
+			&i.Installments,
+			&i.InterestCentDays,
+			&i.DueDate,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listBillingPeriodsBySubscriptionPage = `-- name: ListBillingPeriodsBySubscriptionPage :many
+SELECT id, subscription_id, period_start, period_end, amount_due_cents, amount_paid_cents, status, created_at, updated_at, fee_cents, fee_accrued_on, fee_waived_at, discount_cents, shift_days, frozen_days, installment, installments, interest_cent_days, due_date
+FROM billing_periods
+WHERE subscription_id = $1
+  AND ($2::timestamptz IS NULL
+    OR (created_at, id) > ($2::timestamptz, $3::uuid))
+ORDER BY created_at, id
+LIMIT $4
+`
+
+type ListBillingPeriodsBySubscriptionPageParams struct {
+	SubscriptionID pgtype.UUID        `json:"subscription_id"`
+	AfterCreatedAt pgtype.Timestamptz `json:"after_created_at"`
+	AfterID        pgtype.UUID        `json:"after_id"`
+	RowLimit       int32              `json:"row_limit"`
+}
+
+func (q *Queries) ListBillingPeriodsBySubscriptionPage(ctx context.Context, arg ListBillingPeriodsBySubscriptionPageParams) ([]BillingPeriod, error) {
+	rows, err := q.db.Query(ctx, listBillingPeriodsBySubscriptionPage,
+		arg.SubscriptionID,
+		arg.AfterCreatedAt,
+		arg.AfterID,
+		arg.RowLimit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []BillingPeriod
+	for rows.Next() {
+		var i BillingPeriod
+		if err := rows.Scan(
+			&i.ID,
+			&i.SubscriptionID,
+			&i.PeriodStart,
+			&i.PeriodEnd,
+			&i.AmountDueCents,
+			&i.AmountPaidCents,
+			&i.Status,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.FeeCents,
+			&i.FeeAccruedOn,
+			&i.FeeWaivedAt,
+			&i.DiscountCents,
+			&i.ShiftDays,
+			&i.FrozenDays,
+			&i.Installment,
+			&i.Installments,
+			&i.InterestCentDays,
+			&i.DueDate,
 		); err != nil {
 			return nil, err
 		}
@@ -93,7 +190,7 @@ func (q *Queries) ListBillingPeriodsBySubscription(ctx context.Context, subscrip
 }
 
 const listOpenBillingPeriodsBySubscription = `-- name: ListOpenBillingPeriodsBySubscription :many
-SELECT id, subscription_id, period_start, period_end, amount_due_cents, amount_paid_cents, status, created_at, updated_at
+SELECT id, subscription_id, period_start, period_end, amount_due_cents, amount_paid_cents, status, created_at, updated_at, fee_cents, fee_accrued_on, fee_waived_at, discount_cents, shift_days, frozen_days, installment, installments, interest_cent_days, due_date
 FROM billing_periods
 WHERE subscription_id = $1
   AND status IN ('open', 'partial', 'overdue')
@@ -119,6 +216,16 @@ func (q *Queries) ListOpenBillingPeriodsBySubscription(ctx context.Context, subs
 			&i.Status,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.FeeCents,
+			&i.FeeAccruedOn,
+			&i.FeeWaivedAt,
+			&i.DiscountCents,
+			&i.ShiftDays,
+			&i.FrozenDays,
+			&i.Installment,
+			&i.Installments,
+			&i.InterestCentDays,
+			&i.DueDate,
 		); err != nil {
 			return nil, err
 		}
@@ -154,19 +261,56 @@ UPDATE billing_periods
 SET
   amount_paid_cents = $2,
   status = $3,
+  fee_cents = $4,
+  fee_accrued_on = $5,
+  fee_waived_at = $6,
+  period_end = $7,
+  amount_due_cents = $8,
+  discount_cents = $9,
+  period_start = $10,
+  shift_days = $11,
+  frozen_days = $12,
+  interest_cent_days = $13,
+  due_date = $14,
   updated_at = now()
 WHERE id = $1
-RETURNING id, subscription_id, period_start, period_end, amount_due_cents, amount_paid_cents, status, created_at, updated_at
+RETURNING id, subscription_id, period_start, period_end, amount_due_cents, amount_paid_cents, status, created_at, updated_at, fee_cents, fee_accrued_on, fee_waived_at, discount_cents, shift_days, frozen_days, installment, installments, interest_cent_days, due_date
 `
 
 type UpdateBillingPeriodParams struct {
-	ID              pgtype.UUID         `json:"id"`
-	AmountPaidCents int64               `json:"amount_paid_cents"`
-	Status          BillingPeriodStatus `json:"status"`
+	ID               pgtype.UUID         `json:"id"`
+	AmountPaidCents  int64               `json:"amount_paid_cents"`
+	Status           BillingPeriodStatus `json:"status"`
+	FeeCents         int64               `json:"fee_cents"`
+	FeeAccruedOn     pgtype.Date         `json:"fee_accrued_on"`
+	FeeWaivedAt      pgtype.Timestamptz  `json:"fee_waived_at"`
+	PeriodEnd        pgtype.Date         `json:"period_end"`
+	AmountDueCents   int64               `json:"amount_due_cents"`
+	DiscountCents    int64               `json:"discount_cents"`
+	PeriodStart      pgtype.Date         `json:"period_start"`
+	ShiftDays        int32               `json:"shift_days"`
+	FrozenDays       int32               `json:"frozen_days"`
+	InterestCentDays int64               `json:"interest_cent_days"`
+	DueDate          pgtype.Date         `json:"due_date"`
 }
 
 func (q *Queries) UpdateBillingPeriod(ctx context.Context, arg UpdateBillingPeriodParams) (BillingPeriod, error) {
-	row := q.db.QueryRow(ctx, updateBillingPeriod, arg.ID, arg.AmountPaidCents, arg.Status)
+	row := q.db.QueryRow(ctx, updateBillingPeriod,
+		arg.ID,
+		arg.AmountPaidCents,
+		arg.Status,
+		arg.FeeCents,
+		arg.FeeAccruedOn,
+		arg.FeeWaivedAt,
+		arg.PeriodEnd,
+		arg.AmountDueCents,
+		arg.DiscountCents,
+		arg.PeriodStart,
+		arg.ShiftDays,
+		arg.FrozenDays,
+		arg.InterestCentDays,
+		arg.DueDate,
+	)
 	var i BillingPeriod
 	err := row.Scan(
 		&i.ID,
@@ -178,6 +322,16 @@ func (q *Queries) UpdateBillingPeriod(ctx context.Context, arg UpdateBillingPeri
 		&i.Status,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.FeeCents,
+		&i.FeeAccruedOn,
+		&i.FeeWaivedAt,
+		&i.DiscountCents,
+		&i.ShiftDays,
+		&i.FrozenDays,
+		&i.Installment,
+		&i.Installments,
+		&i.InterestCentDays,
+		&i.DueDate,
 	)
 	return i, err
 }
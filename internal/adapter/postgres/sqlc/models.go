@@ -315,6 +315,20 @@ func (ns NullUserRole) Value() (driver.Value, error) {
 	return string(ns.UserRole), nil
 }
 
+type ApiToken struct {
+	ID          pgtype.UUID        `json:"id"`
+	UserID      pgtype.UUID        `json:"user_id"`
+	Name        string             `json:"name"`
+	TokenPrefix string             `json:"token_prefix"`
+	TokenHash   string             `json:"token_hash"`
+	Scopes      []string           `json:"scopes"`
+	ExpiresAt   pgtype.Timestamptz `json:"expires_at"`
+	LastUsedAt  pgtype.Timestamptz `json:"last_used_at"`
+	RevokedAt   pgtype.Timestamptz `json:"revoked_at"`
+	CreatedBy   pgtype.UUID        `json:"created_by"`
+	CreatedAt   pgtype.Timestamptz `json:"created_at"`
+}
+
 type AuditEvent struct {
 	ID         pgtype.UUID        `json:"id"`
 	ActorID    pgtype.UUID        `json:"actor_id"`
@@ -326,18 +340,58 @@ type AuditEvent struct {
 	Ip         pgtype.Text        `json:"ip"`
 	UserAgent  pgtype.Text        `json:"user_agent"`
 	CreatedAt  pgtype.Timestamptz `json:"created_at"`
+	Seq        pgtype.Int8        `json:"seq"`
+	PrevHash   []byte             `json:"prev_hash"`
+	Hash       []byte             `json:"hash"`
+}
+
+type AuditChainAnchor struct {
+	ID        int16              `json:"id"`
+	Seq       int64              `json:"seq"`
+	Hash      []byte             `json:"hash"`
+	UpdatedAt pgtype.Timestamptz `json:"updated_at"`
+}
+
+type AuditChainHead struct {
+	ID        int16              `json:"id"`
+	Seq       int64              `json:"seq"`
+	Hash      []byte             `json:"hash"`
+	UpdatedAt pgtype.Timestamptz `json:"updated_at"`
 }
 
 type BillingPeriod struct {
-	ID              pgtype.UUID         `json:"id"`
-	SubscriptionID  pgtype.UUID         `json:"subscription_id"`
-	PeriodStart     pgtype.Date         `json:"period_start"`
-	PeriodEnd       pgtype.Date         `json:"period_end"`
-	AmountDueCents  int64               `json:"amount_due_cents"`
-	AmountPaidCents int64               `json:"amount_paid_cents"`
-	Status          BillingPeriodStatus `json:"status"`
-	CreatedAt       pgtype.Timestamptz  `json:"created_at"`
-	UpdatedAt       pgtype.Timestamptz  `json:"updated_at"`
+	ID               pgtype.UUID         `json:"id"`
+	SubscriptionID   pgtype.UUID         `json:"subscription_id"`
+	PeriodStart      pgtype.Date         `json:"period_start"`
+	PeriodEnd        pgtype.Date         `json:"period_end"`
+	AmountDueCents   int64               `json:"amount_due_cents"`
+	AmountPaidCents  int64               `json:"amount_paid_cents"`
+	Status           BillingPeriodStatus `json:"status"`
+	CreatedAt        pgtype.Timestamptz  `json:"created_at"`
+	UpdatedAt        pgtype.Timestamptz  `json:"updated_at"`
+	FeeCents         int64               `json:"fee_cents"`
+	FeeAccruedOn     pgtype.Date         `json:"fee_accrued_on"`
+	FeeWaivedAt      pgtype.Timestamptz  `json:"fee_waived_at"`
+	DiscountCents    int64               `json:"discount_cents"`
+	ShiftDays        int32               `json:"shift_days"`
+	FrozenDays       int32               `json:"frozen_days"`
+	Installment      int32               `json:"installment"`
+	Installments     int32               `json:"installments"`
+	InterestCentDays int64               `json:"interest_cent_days"`
+	DueDate          pgtype.Date         `json:"due_date"`
+}
+
+type Coupon struct {
+	ID               pgtype.UUID        `json:"id"`
+	Code             string             `json:"code"`
+	Description      pgtype.Text        `json:"description"`
+	DiscountKind     string             `json:"discount_kind"`
+	DiscountValue    int64              `json:"discount_value"`
+	DiscountDuration string             `json:"discount_duration"`
+	DiscountPeriods  int32              `json:"discount_periods"`
+	Active           bool               `json:"active"`
+	CreatedAt        pgtype.Timestamptz `json:"created_at"`
+	UpdatedAt        pgtype.Timestamptz `json:"updated_at"`
 }
 
 type ImagekitOutbox struct {
@@ -350,6 +404,15 @@ type ImagekitOutbox struct {
 	CreatedAt   pgtype.Timestamptz `json:"created_at"`
 }
 
+type PasswordResetToken struct {
+	ID        pgtype.UUID        `json:"id"`
+	UserID    pgtype.UUID        `json:"user_id"`
+	TokenHash string             `json:"token_hash"`
+	ExpiresAt pgtype.Timestamptz `json:"expires_at"`
+	UsedAt    pgtype.Timestamptz `json:"used_at"`
+	CreatedAt pgtype.Timestamptz `json:"created_at"`
+}
+
 type Payment struct {
 	ID             pgtype.UUID        `json:"id"`
 	SubscriptionID pgtype.UUID        `json:"subscription_id"`
@@ -372,15 +435,27 @@ type PaymentAllocation struct {
 	CreatedAt       pgtype.Timestamptz `json:"created_at"`
 }
 
+type PaymentCreditTransfer struct {
+	PaymentID      pgtype.UUID        `json:"payment_id"`
+	SubscriptionID pgtype.UUID        `json:"subscription_id"`
+	AmountCents    int64              `json:"amount_cents"`
+	CreditCents    int64              `json:"credit_cents"`
+	CreatedAt      pgtype.Timestamptz `json:"created_at"`
+}
+
 type Plan struct {
-	ID           pgtype.UUID        `json:"id"`
-	Name         string             `json:"name"`
-	DurationDays int32              `json:"duration_days"`
-	PriceCents   int64              `json:"price_cents"`
-	Active       bool               `json:"active"`
-	Description  pgtype.Text        `json:"description"`
-	CreatedAt    pgtype.Timestamptz `json:"created_at"`
-	UpdatedAt    pgtype.Timestamptz `json:"updated_at"`
+	ID              pgtype.UUID        `json:"id"`
+	Name            string             `json:"name"`
+	DurationDays    int32              `json:"duration_days"`
+	PriceCents      int64              `json:"price_cents"`
+	Active          bool               `json:"active"`
+	Description     pgtype.Text        `json:"description"`
+	CreatedAt       pgtype.Timestamptz `json:"created_at"`
+	UpdatedAt       pgtype.Timestamptz `json:"updated_at"`
+	LateFeeBps      int32              `json:"late_fee_bps"`
+	LateInterestBps int32              `json:"late_interest_bps"`
+	MaxFreezeDays   int32              `json:"max_freeze_days"`
+	Installments    int32              `json:"installments"`
 }
 
 type Student struct {
@@ -400,17 +475,23 @@ type Student struct {
 }
 
 type Subscription struct {
-	ID         pgtype.UUID        `json:"id"`
-	StudentID  pgtype.UUID        `json:"student_id"`
-	PlanID     pgtype.UUID        `json:"plan_id"`
-	StartDate  pgtype.Date        `json:"start_date"`
-	EndDate    pgtype.Date        `json:"end_date"`
-	Status     SubscriptionStatus `json:"status"`
-	PriceCents int64              `json:"price_cents"`
-	CreatedAt  pgtype.Timestamptz `json:"created_at"`
-	UpdatedAt  pgtype.Timestamptz `json:"updated_at"`
-	PaymentDay int32              `json:"payment_day"`
-	AutoRenew  bool               `json:"auto_renew"`
+	ID                     pgtype.UUID        `json:"id"`
+	StudentID              pgtype.UUID        `json:"student_id"`
+	PlanID                 pgtype.UUID        `json:"plan_id"`
+	StartDate              pgtype.Date        `json:"start_date"`
+	EndDate                pgtype.Date        `json:"end_date"`
+	Status                 SubscriptionStatus `json:"status"`
+	PriceCents             int64              `json:"price_cents"`
+	CreatedAt              pgtype.Timestamptz `json:"created_at"`
+	UpdatedAt              pgtype.Timestamptz `json:"updated_at"`
+	PaymentDay             int32              `json:"payment_day"`
+	AutoRenew              bool               `json:"auto_renew"`
+	CouponID               pgtype.UUID        `json:"coupon_id"`
+	DiscountKind           pgtype.Text        `json:"discount_kind"`
+	DiscountValue          int64              `json:"discount_value"`
+	DiscountDuration       pgtype.Text        `json:"discount_duration"`
+	DiscountPeriods        int32              `json:"discount_periods"`
+	PreviousSubscriptionID pgtype.UUID        `json:"previous_subscription_id"`
 }
 
 type SubscriptionBalance struct {
@@ -419,13 +500,40 @@ type SubscriptionBalance struct {
 	UpdatedAt      pgtype.Timestamptz `json:"updated_at"`
 }
 
+type SubscriptionFreeze struct {
+	ID             pgtype.UUID        `json:"id"`
+	SubscriptionID pgtype.UUID        `json:"subscription_id"`
+	StartDate      pgtype.Date        `json:"start_date"`
+	EndDate        pgtype.Date        `json:"end_date"`
+	Reason         string             `json:"reason"`
+	CreatedAt      pgtype.Timestamptz `json:"created_at"`
+}
+
 type User struct {
-	ID           pgtype.UUID        `json:"id"`
-	Name         string             `json:"name"`
-	Email        string             `json:"email"`
-	PasswordHash string             `json:"password_hash"`
-	Role         UserRole           `json:"role"`
-	Active       bool               `json:"active"`
+	ID                 pgtype.UUID        `json:"id"`
+	Name               string             `json:"name"`
+	Email              string             `json:"email"`
+	PasswordHash       string             `json:"password_hash"`
+	Role               UserRole           `json:"role"`
+	Active             bool               `json:"active"`
+	CreatedAt          pgtype.Timestamptz `json:"created_at"`
+	UpdatedAt          pgtype.Timestamptz `json:"updated_at"`
+	MustChangePassword bool               `json:"must_change_password"`
+}
+
+type UserMfa struct {
+	UserID       pgtype.UUID        `json:"user_id"`
+	Secret       string             `json:"secret"`
+	EnabledAt    pgtype.Timestamptz `json:"enabled_at"`
+	LastUsedStep int64              `json:"last_used_step"`
 	CreatedAt    pgtype.Timestamptz `json:"created_at"`
 	UpdatedAt    pgtype.Timestamptz `json:"updated_at"`
 }
+
+type UserRecoveryCode struct {
+	ID        pgtype.UUID        `json:"id"`
+	UserID    pgtype.UUID        `json:"user_id"`
+	CodeHash  string             `json:"code_hash"`
+	UsedAt    pgtype.Timestamptz `json:"used_at"`
+	CreatedAt pgtype.Timestamptz `json:"created_at"`
+}
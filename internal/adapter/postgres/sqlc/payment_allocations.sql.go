@@ -36,6 +36,35 @@ func (q *Queries) DeletePaymentAllocationsByPayment(ctx context.Context, payment
 	return err
 }
 
+const listPaymentAllocationsByBillingPeriod = `-- name: ListPaymentAllocationsByBillingPeriod :many
+SELECT payment_id, billing_period_id, amount_cents, created_at FROM payment_allocations WHERE billing_period_id = $1 ORDER BY created_at
+`
+
+func (q *Queries) ListPaymentAllocationsByBillingPeriod(ctx context.Context, billingPeriodID pgtype.UUID) ([]PaymentAllocation, error) {
+	rows, err := q.db.Query(ctx, listPaymentAllocationsByBillingPeriod, billingPeriodID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []PaymentAllocation
+	for rows.Next() {
+		var i PaymentAllocation
+		if err := rows.Scan(
+			&i.PaymentID,
+			&i.BillingPeriodID,
+			&i.AmountCents,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const listPaymentAllocationsByPayment = `-- name: ListPaymentAllocationsByPayment :many
 SELECT payment_id, billing_period_id, amount_cents, created_at FROM payment_allocations WHERE payment_id = $1 ORDER BY created_at
 `
@@ -64,3 +93,20 @@ func (q *Queries) ListPaymentAllocationsByPayment(ctx context.Context, paymentID
 	}
 	return items, nil
 }
+
+const updatePaymentAllocationAmount = `-- name: UpdatePaymentAllocationAmount :exec
+UPDATE payment_allocations
+SET amount_cents = $3
+WHERE payment_id = $1 AND billing_period_id = $2
+`
+
+type UpdatePaymentAllocationAmountParams struct {
+	PaymentID       pgtype.UUID `json:"payment_id"`
+	BillingPeriodID pgtype.UUID `json:"billing_period_id"`
+	AmountCents     int64       `json:"amount_cents"`
+}
+
+func (q *Queries) UpdatePaymentAllocationAmount(ctx context.Context, arg UpdatePaymentAllocationAmountParams) error {
+	_, err := q.db.Exec(ctx, updatePaymentAllocationAmount, arg.PaymentID, arg.BillingPeriodID, arg.AmountCents)
+	return err
+}
@@ -166,6 +166,64 @@ func (q *Queries) ListPaymentsByPeriod(ctx context.Context, arg ListPaymentsByPe
 	return items, nil
 }
 
+const listPaymentsByPeriodPage = `-- name: ListPaymentsByPeriodPage :many
+SELECT id, subscription_id, paid_at, amount_cents, method, reference, notes, status, created_at, kind, credit_cents, idempotency_key
+FROM payments
+WHERE paid_at >= $1::timestamptz
+  AND paid_at < $2::timestamptz
+  AND ($3::timestamptz IS NULL
+    OR (created_at, id) > ($3::timestamptz, $4::uuid))
+ORDER BY created_at, id
+LIMIT $5
+`
+
+type ListPaymentsByPeriodPageParams struct {
+	PaidFrom       pgtype.Timestamptz `json:"paid_from"`
+	PaidTo         pgtype.Timestamptz `json:"paid_to"`
+	AfterCreatedAt pgtype.Timestamptz `json:"after_created_at"`
+	AfterID        pgtype.UUID        `json:"after_id"`
+	RowLimit       int32              `json:"row_limit"`
+}
+
+func (q *Queries) ListPaymentsByPeriodPage(ctx context.Context, arg ListPaymentsByPeriodPageParams) ([]Payment, error) {
+	rows, err := q.db.Query(ctx, listPaymentsByPeriodPage,
+		arg.PaidFrom,
+		arg.PaidTo,
+		arg.AfterCreatedAt,
+		arg.AfterID,
+		arg.RowLimit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Payment
+	for rows.Next() {
+		var i Payment
+		if err := rows.Scan(
+			&i.ID,
+			&i.SubscriptionID,
+			&i.PaidAt,
+			&i.AmountCents,
+			&i.Method,
+			&i.Reference,
+			&i.Notes,
+			&i.Status,
+			&i.CreatedAt,
+			&i.Kind,
+			&i.CreditCents,
+			&i.IdempotencyKey,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const listPaymentsBySubscription = `-- name: ListPaymentsBySubscription :many
 SELECT id, subscription_id, paid_at, amount_cents, method, reference, notes, status, created_at, kind, credit_cents, idempotency_key FROM payments WHERE subscription_id = $1 ORDER BY paid_at DESC
 `
@@ -203,6 +261,61 @@ func (q *Queries) ListPaymentsBySubscription(ctx context.Context, subscriptionID
 	return items, nil
 }
 
+const listPaymentsBySubscriptionPage = `-- name: ListPaymentsBySubscriptionPage :many
+SELECT id, subscription_id, paid_at, amount_cents, method, reference, notes, status, created_at, kind, credit_cents, idempotency_key
+FROM payments
+WHERE subscription_id = $1
+  AND ($2::timestamptz IS NULL
+    OR (created_at, id) > ($2::timestamptz, $3::uuid))
+ORDER BY created_at, id
+LIMIT $4
+`
+
+type ListPaymentsBySubscriptionPageParams struct {
+	SubscriptionID pgtype.UUID        `json:"subscription_id"`
+	AfterCreatedAt pgtype.Timestamptz `json:"after_created_at"`
+	AfterID        pgtype.UUID        `json:"after_id"`
+	RowLimit       int32              `json:"row_limit"`
+}
+
+func (q *Queries) ListPaymentsBySubscriptionPage(ctx context.Context, arg ListPaymentsBySubscriptionPageParams) ([]Payment, error) {
+	rows, err := q.db.Query(ctx, listPaymentsBySubscriptionPage,
+		arg.SubscriptionID,
+		arg.AfterCreatedAt,
+		arg.AfterID,
+		arg.RowLimit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Payment
+	for rows.Next() {
+		var i Payment
+		if err := rows.Scan(
+			&i.ID,
+			&i.SubscriptionID,
+			&i.PaidAt,
+			&i.AmountCents,
+			&i.Method,
+			&i.Reference,
+			&i.Notes,
+			&i.Status,
+			&i.CreatedAt,
+			&i.Kind,
+			&i.CreditCents,
+			&i.IdempotencyKey,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const updatePayment = `-- name: UpdatePayment :one
 UPDATE payments
 SET
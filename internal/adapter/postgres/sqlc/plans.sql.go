@@ -17,19 +17,27 @@ INSERT INTO plans (
   duration_days,
   price_cents,
   active,
-  description
+  description,
+  late_fee_bps,
+  late_interest_bps,
+  max_freeze_days,
+  installments
 ) VALUES (
-  $1, $2, $3, $4, $5
+  $1, $2, $3, $4, $5, $6, $7, $8, $9
 )
-RETURNING id, name, duration_days, price_cents, active, description, created_at, updated_at
+RETURNING id, name, duration_days, price_cents, active, description, created_at, updated_at, late_fee_bps, late_interest_bps, max_freeze_days, installments
 `
 
 type CreatePlanParams struct {
-	Name         string      `json:"name"`
-	DurationDays int32       `json:"duration_days"`
-	PriceCents   int64       `json:"price_cents"`
-	Active       bool        `json:"active"`
-	Description  pgtype.Text `json:"description"`
+	Name            string      `json:"name"`
+	DurationDays    int32       `json:"duration_days"`
+	PriceCents      int64       `json:"price_cents"`
+	Active          bool        `json:"active"`
+	Description     pgtype.Text `json:"description"`
+	LateFeeBps      int32       `json:"late_fee_bps"`
+	LateInterestBps int32       `json:"late_interest_bps"`
+	MaxFreezeDays   int32       `json:"max_freeze_days"`
+	Installments    int32       `json:"installments"`
 }
 
 func (q *Queries) CreatePlan(ctx context.Context, arg CreatePlanParams) (Plan, error) {
@@ -39,6 +47,10 @@ func (q *Queries) CreatePlan(ctx context.Context, arg CreatePlanParams) (Plan, e
 		arg.PriceCents,
 		arg.Active,
 		arg.Description,
+		arg.LateFeeBps,
+		arg.LateInterestBps,
+		arg.MaxFreezeDays,
+		arg.Installments,
 	)
 	var i Plan
 	err := row.Scan(
@@ -50,12 +62,16 @@ func (q *Queries) CreatePlan(ctx context.Context, arg CreatePlanParams) (Plan, e
 		&i.Description,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.LateFeeBps,
+		&i.LateInterestBps,
+		&i.MaxFreezeDays,
+		&i.Installments,
 	)
 	return i, err
 }
 
 const getPlan = `-- name: GetPlan :one
-SELECT id, name, duration_days, price_cents, active, description, created_at, updated_at FROM plans WHERE id = $1 LIMIT 1
+SELECT id, name, duration_days, price_cents, active, description, created_at, updated_at, late_fee_bps, late_interest_bps, max_freeze_days, installments FROM plans WHERE id = $1 LIMIT 1
 `
 
 func (q *Queries) GetPlan(ctx context.Context, id pgtype.UUID) (Plan, error) {
@@ -70,12 +86,16 @@ func (q *Queries) GetPlan(ctx context.Context, id pgtype.UUID) (Plan, error) {
 		&i.Description,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.LateFeeBps,
+		&i.LateInterestBps,
+		&i.MaxFreezeDays,
+		&i.Installments,
 	)
 	return i, err
 }
 
 const listActivePlans = `-- name: ListActivePlans :many
-SELECT id, name, duration_days, price_cents, active, description, created_at, updated_at FROM plans WHERE active = true ORDER BY name
+SELECT id, name, duration_days, price_cents, active, description, created_at, updated_at, late_fee_bps, late_interest_bps, max_freeze_days, installments FROM plans WHERE active = true ORDER BY name
 `
 
 func (q *Queries) ListActivePlans(ctx context.Context) ([]Plan, error) {
@@ -96,6 +116,63 @@ func (q *Queries) ListActivePlans(ctx context.Context) ([]Plan, error) {
 			&i.Description,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.LateFeeBps,
+			&i.LateInterestBps,
+			&i.MaxFreezeDays,
+			&i.Installments,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listActivePlansPage = `-- name: ListActivePlansPage :many
+SELECT id, name, duration_days, price_cents, active, description, created_at, updated_at, late_fee_bps, late_interest_bps, max_freeze_days, installments
+FROM plans
+WHERE active = true
+  AND ($1::timestamptz IS NULL
+    OR (created_at, id) > ($1::timestamptz, $2::uuid))
+ORDER BY created_at, id
+LIMIT $3
+`
+
+type ListActivePlansPageParams struct {
+	AfterCreatedAt pgtype.Timestamptz `json:"after_created_at"`
+	AfterID        pgtype.UUID        `json:"after_id"`
+	RowLimit       int32              `json:"row_limit"`
+}
+
+func (q *Queries) ListActivePlansPage(ctx context.Context, arg ListActivePlansPageParams) ([]Plan, error) {
+	rows, err := q.db.Query(ctx, listActivePlansPage,
+		arg.AfterCreatedAt,
+		arg.AfterID,
+		arg.RowLimit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Plan
+	for rows.Next() {
+		var i Plan
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.DurationDays,
+			&i.PriceCents,
+			&i.Active,
+			&i.Description,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.LateFeeBps,
+			&i.LateInterestBps,
+			&i.MaxFreezeDays,
+			&i.Installments,
 		); err != nil {
 			return nil, err
 		}
@@ -115,18 +192,26 @@ SET
   price_cents = $4,
   active = $5,
   description = $6,
+  late_fee_bps = $7,
+  late_interest_bps = $8,
+  max_freeze_days = $9,
+  installments = $10,
   updated_at = now()
 WHERE id = $1
-RETURNING id, name, duration_days, price_cents, active, description, created_at, updated_at
+RETURNING id, name, duration_days, price_cents, active, description, created_at, updated_at, late_fee_bps, late_interest_bps, max_freeze_days, installments
 `
 
 type UpdatePlanParams struct {
-	ID           pgtype.UUID `json:"id"`
-	Name         string      `json:"name"`
-	DurationDays int32       `json:"duration_days"`
-	PriceCents   int64       `json:"price_cents"`
-	Active       bool        `json:"active"`
-	Description  pgtype.Text `json:"description"`
+	ID              pgtype.UUID `json:"id"`
+	Name            string      `json:"name"`
+	DurationDays    int32       `json:"duration_days"`
+	PriceCents      int64       `json:"price_cents"`
+	Active          bool        `json:"active"`
+	Description     pgtype.Text `json:"description"`
+	LateFeeBps      int32       `json:"late_fee_bps"`
+	LateInterestBps int32       `json:"late_interest_bps"`
+	MaxFreezeDays   int32       `json:"max_freeze_days"`
+	Installments    int32       `json:"installments"`
 }
 
 func (q *Queries) UpdatePlan(ctx context.Context, arg UpdatePlanParams) (Plan, error) {
@@ -137,6 +222,10 @@ func (q *Queries) UpdatePlan(ctx context.Context, arg UpdatePlanParams) (Plan, e
 		arg.PriceCents,
 		arg.Active,
 		arg.Description,
+		arg.LateFeeBps,
+		arg.LateInterestBps,
+		arg.MaxFreezeDays,
+		arg.Installments,
 	)
 	var i Plan
 	err := row.Scan(
@@ -148,6 +237,10 @@ func (q *Queries) UpdatePlan(ctx context.Context, arg UpdatePlanParams) (Plan, e
 		&i.Description,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.LateFeeBps,
+		&i.LateInterestBps,
+		&i.MaxFreezeDays,
+		&i.Installments,
 	)
 	return i, err
 }
@@ -12,44 +12,109 @@ import (
 
 type Querier interface {
 	AddSubscriptionBalance(ctx context.Context, arg AddSubscriptionBalanceParams) (SubscriptionBalance, error)
+	AdvanceAuditChainHead(ctx context.Context, arg AdvanceAuditChainHeadParams) error
+	AdvanceUserMFAStep(ctx context.Context, arg AdvanceUserMFAStepParams) (int64, error)
+	ConsumePasswordResetToken(ctx context.Context, arg ConsumePasswordResetTokenParams) (PasswordResetToken, error)
+	ConsumeRecoveryCode(ctx context.Context, arg ConsumeRecoveryCodeParams) (int64, error)
+	CountActiveStudents(ctx context.Context) (int64, error)
+	CountAuditEvents(ctx context.Context, arg CountAuditEventsParams) (int64, error)
+	CountOverdueSubscriptions(ctx context.Context) (int64, error)
+	CountRecoveryCodes(ctx context.Context, userID pgtype.UUID) (int64, error)
 	CountStudents(ctx context.Context, arg CountStudentsParams) (int64, error)
+	CountUnchainedAuditEvents(ctx context.Context) (int64, error)
+	CreateAPIToken(ctx context.Context, arg CreateAPITokenParams) (ApiToken, error)
 	CreateBillingPeriod(ctx context.Context, arg CreateBillingPeriodParams) (BillingPeriod, error)
+	CreateCoupon(ctx context.Context, arg CreateCouponParams) (Coupon, error)
+	CreatePasswordResetToken(ctx context.Context, arg CreatePasswordResetTokenParams) (PasswordResetToken, error)
 	CreatePayment(ctx context.Context, arg CreatePaymentParams) (Payment, error)
 	CreatePaymentAllocation(ctx context.Context, arg CreatePaymentAllocationParams) error
+	CreatePaymentCreditTransfer(ctx context.Context, arg CreatePaymentCreditTransferParams) error
 	CreatePlan(ctx context.Context, arg CreatePlanParams) (Plan, error)
 	CreateStudent(ctx context.Context, arg CreateStudentParams) (Student, error)
 	CreateSubscription(ctx context.Context, arg CreateSubscriptionParams) (Subscription, error)
+	CreateSubscriptionFreeze(ctx context.Context, arg CreateSubscriptionFreezeParams) (SubscriptionFreeze, error)
 	CreateUser(ctx context.Context, arg CreateUserParams) (User, error)
+	DeleteAuditChainPrefix(ctx context.Context, seq pgtype.Int8) (int64, error)
 	DeletePaymentAllocationsByPayment(ctx context.Context, paymentID pgtype.UUID) error
+	DeletePaymentCreditTransfersByPayment(ctx context.Context, paymentID pgtype.UUID) error
+	DeleteUnchainedAuditEventsRange(ctx context.Context, arg DeleteUnchainedAuditEventsRangeParams) (int64, error)
+	DeleteUserMFA(ctx context.Context, userID pgtype.UUID) error
 	DelinquentSubscriptions(ctx context.Context, dollar_1 pgtype.Date) ([]DelinquentSubscriptionsRow, error)
+	EnableUserMFA(ctx context.Context, arg EnableUserMFAParams) (UserMfa, error)
+	GetAPITokenByHash(ctx context.Context, tokenHash string) (ApiToken, error)
+	GetAuditChainAnchor(ctx context.Context) (GetAuditChainAnchorRow, error)
+	GetAuditChainHead(ctx context.Context) (GetAuditChainHeadRow, error)
+	GetAuditChainPrefixEnd(ctx context.Context, arg GetAuditChainPrefixEndParams) (int64, error)
+	GetAuditEventHashBySeq(ctx context.Context, seq pgtype.Int8) ([]byte, error)
+	GetCoupon(ctx context.Context, id pgtype.UUID) (Coupon, error)
+	GetCouponByCode(ctx context.Context, lower string) (Coupon, error)
+	GetOldestAuditEventAt(ctx context.Context) (pgtype.Timestamptz, error)
 	GetPayment(ctx context.Context, id pgtype.UUID) (Payment, error)
 	GetPaymentByIdempotencyKey(ctx context.Context, idempotencyKey pgtype.Text) (Payment, error)
 	GetPlan(ctx context.Context, id pgtype.UUID) (Plan, error)
 	GetStudent(ctx context.Context, id pgtype.UUID) (Student, error)
+	GetStudentIDByPhotoObjectKey(ctx context.Context, photoObjectKey pgtype.Text) (pgtype.UUID, error)
 	GetSubscription(ctx context.Context, id pgtype.UUID) (Subscription, error)
 	GetSubscriptionBalance(ctx context.Context, subscriptionID pgtype.UUID) (SubscriptionBalance, error)
+	GetUser(ctx context.Context, id pgtype.UUID) (User, error)
 	GetUserByEmail(ctx context.Context, email string) (User, error)
+	GetUserMFA(ctx context.Context, userID pgtype.UUID) (UserMfa, error)
+	InsertChainedAuditEvent(ctx context.Context, arg InsertChainedAuditEventParams) (InsertChainedAuditEventRow, error)
+	InvalidatePasswordResetTokens(ctx context.Context, arg InvalidatePasswordResetTokensParams) error
+	ListAPITokens(ctx context.Context) ([]ListAPITokensRow, error)
 	ListActivePlans(ctx context.Context) ([]Plan, error)
+	ListActivePlansPage(ctx context.Context, arg ListActivePlansPageParams) ([]Plan, error)
+	ListAuditChain(ctx context.Context, arg ListAuditChainParams) ([]ListAuditChainRow, error)
+	ListAuditEventsRange(ctx context.Context, arg ListAuditEventsRangeParams) ([]AuditEvent, error)
 	ListAutoRenewSubscriptions(ctx context.Context) ([]Subscription, error)
 	ListBillingPeriodsBySubscription(ctx context.Context, subscriptionID pgtype.UUID) ([]BillingPeriod, error)
+	ListBillingPeriodsBySubscriptionPage(ctx context.Context, arg ListBillingPeriodsBySubscriptionPageParams) ([]BillingPeriod, error)
+	ListCoupons(ctx context.Context) ([]Coupon, error)
 	ListOpenBillingPeriodsBySubscription(ctx context.Context, subscriptionID pgtype.UUID) ([]BillingPeriod, error)
+	ListPaymentAllocationsByBillingPeriod(ctx context.Context, billingPeriodID pgtype.UUID) ([]PaymentAllocation, error)
 	ListPaymentAllocationsByPayment(ctx context.Context, paymentID pgtype.UUID) ([]PaymentAllocation, error)
+	ListPaymentCreditTransfersByPayment(ctx context.Context, paymentID pgtype.UUID) ([]PaymentCreditTransfer, error)
 	ListPaymentsByPeriod(ctx context.Context, arg ListPaymentsByPeriodParams) ([]Payment, error)
+	ListPaymentsByPeriodPage(ctx context.Context, arg ListPaymentsByPeriodPageParams) ([]Payment, error)
 	ListPaymentsBySubscription(ctx context.Context, subscriptionID pgtype.UUID) ([]Payment, error)
+	ListPaymentsBySubscriptionPage(ctx context.Context, arg ListPaymentsBySubscriptionPageParams) ([]Payment, error)
+	ListSubscriptionFreezes(ctx context.Context, subscriptionID pgtype.UUID) ([]SubscriptionFreeze, error)
+	ListSubscriptionFreezesPage(ctx context.Context, arg ListSubscriptionFreezesPageParams) ([]SubscriptionFreeze, error)
 	ListSubscriptionsByPlan(ctx context.Context, planID pgtype.UUID) ([]Subscription, error)
 	ListSubscriptionsByStudent(ctx context.Context, studentID pgtype.UUID) ([]Subscription, error)
+	ListSubscriptionsByStudentPage(ctx context.Context, arg ListSubscriptionsByStudentPageParams) ([]Subscription, error)
 	ListSubscriptionsDueBetween(ctx context.Context, arg ListSubscriptionsDueBetweenParams) ([]Subscription, error)
+	ListSubscriptionsDueBetweenPage(ctx context.Context, arg ListSubscriptionsDueBetweenPageParams) ([]Subscription, error)
+	ListSubscriptionsWithOpenPeriods(ctx context.Context) ([]Subscription, error)
+	ListUsers(ctx context.Context) ([]User, error)
+	LockAuditChainAnchor(ctx context.Context) (LockAuditChainAnchorRow, error)
+	LockAuditChainHead(ctx context.Context) (LockAuditChainHeadRow, error)
 	MarkBillingPeriodsOverdue(ctx context.Context, arg MarkBillingPeriodsOverdueParams) error
+	OutstandingDueByPeriod(ctx context.Context, arg OutstandingDueByPeriodParams) (int64, error)
+	ReplaceRecoveryCodes(ctx context.Context, arg ReplaceRecoveryCodesParams) error
 	RevenueByPeriod(ctx context.Context, arg RevenueByPeriodParams) (RevenueByPeriodRow, error)
+	RevokeAPIToken(ctx context.Context, arg RevokeAPITokenParams) (int64, error)
+	SearchAuditEvents(ctx context.Context, arg SearchAuditEventsParams) ([]AuditEvent, error)
 	SearchStudents(ctx context.Context, arg SearchStudentsParams) ([]Student, error)
+	SearchStudentsPage(ctx context.Context, arg SearchStudentsPageParams) ([]Student, error)
+	SetAuditChainAnchor(ctx context.Context, arg SetAuditChainAnchorParams) error
+	SetAuditEventHash(ctx context.Context, arg SetAuditEventHashParams) error
+	SetCouponActive(ctx context.Context, arg SetCouponActiveParams) (Coupon, error)
+	SetUserActive(ctx context.Context, arg SetUserActiveParams) (User, error)
 	StudentsByStatus(ctx context.Context) ([]StudentsByStatusRow, error)
+	SummarizeAuditAccess(ctx context.Context, arg SummarizeAuditAccessParams) ([]SummarizeAuditAccessRow, error)
+	TouchAPIToken(ctx context.Context, arg TouchAPITokenParams) error
 	UpcomingDue(ctx context.Context, arg UpcomingDueParams) ([]UpcomingDueRow, error)
 	UpdateBillingPeriod(ctx context.Context, arg UpdateBillingPeriodParams) (BillingPeriod, error)
 	UpdatePayment(ctx context.Context, arg UpdatePaymentParams) (Payment, error)
+	UpdatePaymentAllocationAmount(ctx context.Context, arg UpdatePaymentAllocationAmountParams) error
 	UpdatePlan(ctx context.Context, arg UpdatePlanParams) (Plan, error)
 	UpdateStudent(ctx context.Context, arg UpdateStudentParams) (Student, error)
 	UpdateSubscription(ctx context.Context, arg UpdateSubscriptionParams) (Subscription, error)
+	UpdateUser(ctx context.Context, arg UpdateUserParams) (User, error)
+	UpdateUserPassword(ctx context.Context, arg UpdateUserPasswordParams) error
 	UpsertSubscriptionBalance(ctx context.Context, arg UpsertSubscriptionBalanceParams) (SubscriptionBalance, error)
+	UpsertUserMFASecret(ctx context.Context, arg UpsertUserMFASecretParams) (UserMfa, error)
 }
 
 var _ Querier = (*Queries)(nil)
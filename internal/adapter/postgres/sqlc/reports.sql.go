@@ -16,9 +16,13 @@ SELECT
   s.id AS subscription_id,
   s.student_id,
   s.plan_id,
+  st.full_name AS student_name,
+  p.name AS plan_name,
   s.end_date,
   GREATEST(0, DATE_PART('day', $1::date - s.end_date))::int AS days_overdue
 FROM subscriptions s
+JOIN students st ON st.id = s.student_id
+JOIN plans p ON p.id = s.plan_id
 WHERE s.status = 'active'
   AND s.end_date < $1::date
 ORDER BY s.end_date
@@ -28,6 +32,8 @@ type DelinquentSubscriptionsRow struct {
 	SubscriptionID pgtype.UUID `json:"subscription_id"`
 	StudentID      pgtype.UUID `json:"student_id"`
 	PlanID         pgtype.UUID `json:"plan_id"`
+	StudentName    string      `json:"student_name"`
+	PlanName       string      `json:"plan_name"`
 	EndDate        pgtype.Date `json:"end_date"`
 	DaysOverdue    int32       `json:"days_overdue"`
 }
@@ -45,6 +51,8 @@ func (q *Queries) DelinquentSubscriptions(ctx context.Context, dollar_1 pgtype.D
 			&i.SubscriptionID,
 			&i.StudentID,
 			&i.PlanID,
+			&i.StudentName,
+			&i.PlanName,
 			&i.EndDate,
 			&i.DaysOverdue,
 		); err != nil {
@@ -62,7 +70,19 @@ const revenueByPeriod = `-- name: RevenueByPeriod :one
 SELECT
   $1::timestamptz AS start,
   $2::timestamptz AS end,
-  COALESCE(SUM(amount_cents), 0)::bigint AS total_cents
+  COALESCE(SUM(amount_cents), 0)::bigint AS total_cents,
+  (
+    SELECT COALESCE(SUM(bp.amount_due_cents + bp.discount_cents), 0)
+    FROM billing_periods bp
+    WHERE bp.period_start >= $1::date
+      AND bp.period_start < $2::date
+  )::bigint AS gross_billed_cents,
+  (
+    SELECT COALESCE(SUM(bp.discount_cents), 0)
+    FROM billing_periods bp
+    WHERE bp.period_start >= $1::date
+      AND bp.period_start < $2::date
+  )::bigint AS discount_cents
 FROM payments
 WHERE paid_at >= $1
   AND paid_at < $2
@@ -75,15 +95,23 @@ type RevenueByPeriodParams struct {
 }
 
 type RevenueByPeriodRow struct {
-	Start      pgtype.Timestamptz `json:"start"`
-	End        pgtype.Timestamptz `json:"end"`
-	TotalCents int64              `json:"total_cents"`
+	Start            pgtype.Timestamptz `json:"start"`
+	End              pgtype.Timestamptz `json:"end"`
+	TotalCents       int64              `json:"total_cents"`
+	GrossBilledCents int64              `json:"gross_billed_cents"`
+	DiscountCents    int64              `json:"discount_cents"`
 }
 
 func (q *Queries) RevenueByPeriod(ctx context.Context, arg RevenueByPeriodParams) (RevenueByPeriodRow, error) {
 	row := q.db.QueryRow(ctx, revenueByPeriod, arg.Column1, arg.Column2)
 	var i RevenueByPeriodRow
-	err := row.Scan(&i.Start, &i.End, &i.TotalCents)
+	err := row.Scan(
+		&i.Start,
+		&i.End,
+		&i.TotalCents,
+		&i.GrossBilledCents,
+		&i.DiscountCents,
+	)
 	return i, err
 }
 
@@ -124,8 +152,12 @@ SELECT
   s.id AS subscription_id,
   s.student_id,
   s.plan_id,
+  st.full_name AS student_name,
+  p.name AS plan_name,
   s.end_date
 FROM subscriptions s
+JOIN students st ON st.id = s.student_id
+JOIN plans p ON p.id = s.plan_id
 WHERE s.status = 'active'
   AND s.end_date BETWEEN $1::date AND $2::date
 ORDER BY s.end_date
@@ -140,6 +172,8 @@ type UpcomingDueRow struct {
 	SubscriptionID pgtype.UUID `json:"subscription_id"`
 	StudentID      pgtype.UUID `json:"student_id"`
 	PlanID         pgtype.UUID `json:"plan_id"`
+	StudentName    string      `json:"student_name"`
+	PlanName       string      `json:"plan_name"`
 	EndDate        pgtype.Date `json:"end_date"`
 }
 
@@ -156,6 +190,8 @@ func (q *Queries) UpcomingDue(ctx context.Context, arg UpcomingDueParams) ([]Upc
 			&i.SubscriptionID,
 			&i.StudentID,
 			&i.PlanID,
+			&i.StudentName,
+			&i.PlanName,
 			&i.EndDate,
 		); err != nil {
 			return nil, err
@@ -117,6 +117,17 @@ func (q *Queries) GetStudent(ctx context.Context, id pgtype.UUID) (Student, erro
 	return i, err
 }
 
+const getStudentIDByPhotoObjectKey = `-- name: GetStudentIDByPhotoObjectKey :one
+SELECT id FROM students WHERE photo_object_key = $1 LIMIT 1
+`
+
+func (q *Queries) GetStudentIDByPhotoObjectKey(ctx context.Context, photoObjectKey pgtype.Text) (pgtype.UUID, error) {
+	row := q.db.QueryRow(ctx, getStudentIDByPhotoObjectKey, photoObjectKey)
+	var id pgtype.UUID
+	err := row.Scan(&id)
+	return id, err
+}
+
 const searchStudents = `-- name: SearchStudents :many
 SELECT id, full_name, birth_date, gender, phone, email, cpf, address, notes, photo_object_key, status, created_at, updated_at
 FROM students
@@ -172,6 +183,65 @@ func (q *Queries) SearchStudents(ctx context.Context, arg SearchStudentsParams)
 	return items, nil
 }
 
+const searchStudentsPage = `-- name: SearchStudentsPage :many
+SELECT id, full_name, birth_date, gender, phone, email, cpf, address, notes, photo_object_key, status, created_at, updated_at
+FROM students
+WHERE ($1::text = '' OR full_name ILIKE '%' || $1::text || '%' OR phone ILIKE '%' || $1::text || '%' OR cpf ILIKE '%' || $1::text || '%')
+  AND (COALESCE(array_length($2::student_status[], 1), 0) = 0 OR status = ANY($2::student_status[]))
+  AND ($3::timestamptz IS NULL
+    OR (created_at, id) > ($3::timestamptz, $4::uuid))
+ORDER BY created_at, id
+LIMIT $5
+`
+
+type SearchStudentsPageParams struct {
+	Query          string             `json:"query"`
+	Statuses       []StudentStatus    `json:"statuses"`
+	AfterCreatedAt pgtype.Timestamptz `json:"after_created_at"`
+	AfterID        pgtype.UUID        `json:"after_id"`
+	RowLimit       int32              `json:"row_limit"`
+}
+
+func (q *Queries) SearchStudentsPage(ctx context.Context, arg SearchStudentsPageParams) ([]Student, error) {
+	rows, err := q.db.Query(ctx, searchStudentsPage,
+		arg.Query,
+		arg.Statuses,
+		arg.AfterCreatedAt,
+		arg.AfterID,
+		arg.RowLimit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Student
+	for rows.Next() {
+		var i Student
+		if err := rows.Scan(
+			&i.ID,
+			&i.FullName,
+			&i.BirthDate,
+			&i.Gender,
+			&i.Phone,
+			&i.Email,
+			&i.Cpf,
+			&i.Address,
+			&i.Notes,
+			&i.PhotoObjectKey,
+			&i.Status,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const updateStudent = `-- name: UpdateStudent :one
 UPDATE students
 SET
@@ -20,22 +20,34 @@ INSERT INTO subscriptions (
   status,
   price_cents,
   payment_day,
-  auto_renew
+  auto_renew,
+  coupon_id,
+  discount_kind,
+  discount_value,
+  discount_duration,
+  discount_periods,
+  previous_subscription_id
 ) VALUES (
-  $1, $2, $3, $4, $5, $6, $7, $8
+  $1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14
 )
-RETURNING id, student_id, plan_id, start_date, end_date, status, price_cents, created_at, updated_at, payment_day, auto_renew
+RETURNING id, student_id, plan_id, start_date, end_date, status, price_cents, created_at, updated_at, payment_day, auto_renew, coupon_id, discount_kind, discount_value, discount_duration, discount_periods, previous_subscription_id
 `
 
 type CreateSubscriptionParams struct {
-	StudentID  pgtype.UUID        `json:"student_id"`
-	PlanID     pgtype.UUID        `json:"plan_id"`
-	StartDate  pgtype.Date        `json:"start_date"`
-	EndDate    pgtype.Date        `json:"end_date"`
-	Status     SubscriptionStatus `json:"status"`
-	PriceCents int64              `json:"price_cents"`
-	PaymentDay int32              `json:"payment_day"`
-	AutoRenew  bool               `json:"auto_renew"`
+	StudentID              pgtype.UUID        `json:"student_id"`
+	PlanID                 pgtype.UUID        `json:"plan_id"`
+	StartDate              pgtype.Date        `json:"start_date"`
+	EndDate                pgtype.Date        `json:"end_date"`
+	Status                 SubscriptionStatus `json:"status"`
+	PriceCents             int64              `json:"price_cents"`
+	PaymentDay             int32              `json:"payment_day"`
+	AutoRenew              bool               `json:"auto_renew"`
+	CouponID               pgtype.UUID        `json:"coupon_id"`
+	DiscountKind           pgtype.Text        `json:"discount_kind"`
+	DiscountValue          int64              `json:"discount_value"`
+	DiscountDuration       pgtype.Text        `json:"discount_duration"`
+	DiscountPeriods        int32              `json:"discount_periods"`
+	PreviousSubscriptionID pgtype.UUID        `json:"previous_subscription_id"`
 }
 
 func (q *Queries) CreateSubscription(ctx context.Context, arg CreateSubscriptionParams) (Subscription, error) {
@@ -48,6 +60,12 @@ func (q *Queries) CreateSubscription(ctx context.Context, arg CreateSubscription
 		arg.PriceCents,
 		arg.PaymentDay,
 		arg.AutoRenew,
+		arg.CouponID,
+		arg.DiscountKind,
+		arg.DiscountValue,
+		arg.DiscountDuration,
+		arg.DiscountPeriods,
+		arg.PreviousSubscriptionID,
 	)
 	var i Subscription
 	err := row.Scan(
@@ -62,12 +80,18 @@ func (q *Queries) CreateSubscription(ctx context.Context, arg CreateSubscription
 		&i.UpdatedAt,
 		&i.PaymentDay,
 		&i.AutoRenew,
+		&i.CouponID,
+		&i.DiscountKind,
+		&i.DiscountValue,
+		&i.DiscountDuration,
+		&i.DiscountPeriods,
+		&i.PreviousSubscriptionID,
 	)
 	return i, err
 }
 
 const getSubscription = `-- name: GetSubscription :one
-SELECT id, student_id, plan_id, start_date, end_date, status, price_cents, created_at, updated_at, payment_day, auto_renew FROM subscriptions WHERE id = $1 LIMIT 1
+SELECT id, student_id, plan_id, start_date, end_date, status, price_cents, created_at, updated_at, payment_day, auto_renew, coupon_id, discount_kind, discount_value, discount_duration, discount_periods, previous_subscription_id FROM subscriptions WHERE id = $1 LIMIT 1
 `
 
 func (q *Queries) GetSubscription(ctx context.Context, id pgtype.UUID) (Subscription, error) {
@@ -85,12 +109,18 @@ func (q *Queries) GetSubscription(ctx context.Context, id pgtype.UUID) (Subscrip
 		&i.UpdatedAt,
 		&i.PaymentDay,
 		&i.AutoRenew,
+		&i.CouponID,
+		&i.DiscountKind,
+		&i.DiscountValue,
+		&i.DiscountDuration,
+		&i.DiscountPeriods,
+		&i.PreviousSubscriptionID,
 	)
 	return i, err
 }
 
 const listAutoRenewSubscriptions = `-- name: ListAutoRenewSubscriptions :many
-SELECT id, student_id, plan_id, start_date, end_date, status, price_cents, created_at, updated_at, payment_day, auto_renew
+SELECT id, student_id, plan_id, start_date, end_date, status, price_cents, created_at, updated_at, payment_day, auto_renew, coupon_id, discount_kind, discount_value, discount_duration, discount_periods, previous_subscription_id
 FROM subscriptions
 WHERE status = 'active'
   AND auto_renew = true
@@ -118,6 +148,12 @@ func (q *Queries) ListAutoRenewSubscriptions(ctx context.Context) ([]Subscriptio
 			&i.UpdatedAt,
 			&i.PaymentDay,
 			&i.AutoRenew,
+			&i.CouponID,
+			&i.DiscountKind,
+			&i.DiscountValue,
+			&i.DiscountDuration,
+			&i.DiscountPeriods,
+			&i.PreviousSubscriptionID,
 		); err != nil {
 			return nil, err
 		}
@@ -130,7 +166,7 @@ func (q *Queries) ListAutoRenewSubscriptions(ctx context.Context) ([]Subscriptio
 }
 
 const listSubscriptionsByPlan = `-- name: ListSubscriptionsByPlan :many
-SELECT id, student_id, plan_id, start_date, end_date, status, price_cents, created_at, updated_at, payment_day, auto_renew FROM subscriptions WHERE plan_id = $1 ORDER BY start_date DESC
+SELECT id, student_id, plan_id, start_date, end_date, status, price_cents, created_at, updated_at, payment_day, auto_renew, coupon_id, discount_kind, discount_value, discount_duration, discount_periods, previous_subscription_id FROM subscriptions WHERE plan_id = $1 ORDER BY start_date DESC
 `
 
 func (q *Queries) ListSubscriptionsByPlan(ctx context.Context, planID pgtype.UUID) ([]Subscription, error) {
@@ -154,6 +190,12 @@ func (q *Queries) ListSubscriptionsByPlan(ctx context.Context, planID pgtype.UUI
 			&i.UpdatedAt,
 			&i.PaymentDay,
 			&i.AutoRenew,
+			&i.CouponID,
+			&i.DiscountKind,
+			&i.DiscountValue,
+			&i.DiscountDuration,
+			&i.DiscountPeriods,
+			&i.PreviousSubscriptionID,
 		); err != nil {
 			return nil, err
 		}
@@ -166,7 +208,7 @@ func (q *Queries) ListSubscriptionsByPlan(ctx context.Context, planID pgtype.UUI
 }
 
 const listSubscriptionsByStudent = `-- name: ListSubscriptionsByStudent :many
-SELECT id, student_id, plan_id, start_date, end_date, status, price_cents, created_at, updated_at, payment_day, auto_renew FROM subscriptions WHERE student_id = $1 ORDER BY start_date DESC
+SELECT id, student_id, plan_id, start_date, end_date, status, price_cents, created_at, updated_at, payment_day, auto_renew, coupon_id, discount_kind, discount_value, discount_duration, discount_periods, previous_subscription_id FROM subscriptions WHERE student_id = $1 ORDER BY start_date DESC
 `
 
 func (q *Queries) ListSubscriptionsByStudent(ctx context.Context, studentID pgtype.UUID) ([]Subscription, error) {
@@ -190,6 +232,72 @@ func (q *Queries) ListSubscriptionsByStudent(ctx context.Context, studentID pgty
 			&i.UpdatedAt,
 			&i.PaymentDay,
 			&i.AutoRenew,
+			&i.CouponID,
+			&i.DiscountKind,
+			&i.DiscountValue,
+			&i.DiscountDuration,
+			&i.DiscountPeriods,
+			&i.PreviousSubscriptionID,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listSubscriptionsByStudentPage = `-- name: ListSubscriptionsByStudentPage :many
+SELECT id, student_id, plan_id, start_date, end_date, status, price_cents, created_at, updated_at, payment_day, auto_renew, coupon_id, discount_kind, discount_value, discount_duration, discount_periods, previous_subscription_id
+FROM subscriptions
+WHERE student_id = $1
+  AND ($2::timestamptz IS NULL
+    OR (created_at, id) > ($2::timestamptz, $3::uuid))
+ORDER BY created_at, id
+LIMIT $4
+`
+
+type ListSubscriptionsByStudentPageParams struct {
+	StudentID      pgtype.UUID        `json:"student_id"`
+	AfterCreatedAt pgtype.Timestamptz `json:"after_created_at"`
+	AfterID        pgtype.UUID        `json:"after_id"`
+	RowLimit       int32              `json:"row_limit"`
+}
+
+func (q *Queries) ListSubscriptionsByStudentPage(ctx context.Context, arg ListSubscriptionsByStudentPageParams) ([]Subscription, error) {
+	rows, err := q.db.Query(ctx, listSubscriptionsByStudentPage,
+		arg.StudentID,
+		arg.AfterCreatedAt,
+		arg.AfterID,
+		arg.RowLimit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Subscription
+	for rows.Next() {
+		var i Subscription
+		if err := rows.Scan(
+			&i.ID,
+			&i.StudentID,
+			&i.PlanID,
+			&i.StartDate,
+			&i.EndDate,
+			&i.Status,
+			&i.PriceCents,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.PaymentDay,
+			&i.AutoRenew,
+			&i.CouponID,
+			&i.DiscountKind,
+			&i.DiscountValue,
+			&i.DiscountDuration,
+			&i.DiscountPeriods,
+			&i.PreviousSubscriptionID,
 		); err != nil {
 			return nil, err
 		}
@@ -202,7 +310,7 @@ func (q *Queries) ListSubscriptionsByStudent(ctx context.Context, studentID pgty
 }
 
 const listSubscriptionsDueBetween = `-- name: ListSubscriptionsDueBetween :many
-SELECT id, student_id, plan_id, start_date, end_date, status, price_cents, created_at, updated_at, payment_day, auto_renew
+SELECT id, student_id, plan_id, start_date, end_date, status, price_cents, created_at, updated_at, payment_day, auto_renew, coupon_id, discount_kind, discount_value, discount_duration, discount_periods, previous_subscription_id
 FROM subscriptions
 WHERE status = 'active'
   AND end_date BETWEEN $1 AND $2
@@ -235,6 +343,127 @@ func (q *Queries) ListSubscriptionsDueBetween(ctx context.Context, arg ListSubsc
 			&i.UpdatedAt,
 			&i.PaymentDay,
 			&i.AutoRenew,
+			&i.CouponID,
+			&i.DiscountKind,
+			&i.DiscountValue,
+			&i.DiscountDuration,
+			&i.DiscountPeriods,
+			&i.PreviousSubscriptionID,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listSubscriptionsDueBetweenPage = `-- name: ListSubscriptionsDueBetweenPage :many
+SELECT id, student_id, plan_id, start_date, end_date, status, price_cents, created_at, updated_at, payment_day, auto_renew, coupon_id, discount_kind, discount_value, discount_duration, discount_periods, previous_subscription_id
+FROM subscriptions
+WHERE status = 'active'
+  AND end_date BETWEEN $1::date AND $2::date
+  AND ($3::timestamptz IS NULL
+    OR (created_at, id) > ($3::timestamptz, $4::uuid))
+ORDER BY created_at, id
+LIMIT $5
+`
+
+type ListSubscriptionsDueBetweenPageParams struct {
+	DueFrom        pgtype.Date        `json:"due_from"`
+	DueTo          pgtype.Date        `json:"due_to"`
+	AfterCreatedAt pgtype.Timestamptz `json:"after_created_at"`
+	AfterID        pgtype.UUID        `json:"after_id"`
+	RowLimit       int32              `json:"row_limit"`
+}
+
+func (q *Queries) ListSubscriptionsDueBetweenPage(ctx context.Context, arg ListSubscriptionsDueBetweenPageParams) ([]Subscription, error) {
+	rows, err := q.db.Query(ctx, listSubscriptionsDueBetweenPage,
+		arg.DueFrom,
+		arg.DueTo,
+		arg.AfterCreatedAt,
+		arg.AfterID,
+		arg.RowLimit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Subscription
+	for rows.Next() {
+		var i Subscription
+		if err := rows.Scan(
+			&i.ID,
+			&i.StudentID,
+			&i.PlanID,
+			&i.StartDate,
+			&i.EndDate,
+			&i.Status,
+			&i.PriceCents,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.PaymentDay,
+			&i.AutoRenew,
+			&i.CouponID,
+			&i.DiscountKind,
+			&i.DiscountValue,
+			&i.DiscountDuration,
+			&i.DiscountPeriods,
+			&i.PreviousSubscriptionID,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listSubscriptionsWithOpenPeriods = `-- name: ListSubscriptionsWithOpenPeriods :many
+SELECT s.id, s.student_id, s.plan_id, s.start_date, s.end_date, s.status, s.price_cents, s.created_at, s.updated_at, s.payment_day, s.auto_renew, s.coupon_id, s.discount_kind, s.discount_value, s.discount_duration, s.discount_periods
+FROM subscriptions s
+WHERE s.status = 'active'
+  AND EXISTS (
+    SELECT 1
+    FROM billing_periods bp
+    WHERE bp.subscription_id = s.id
+      AND bp.status IN ('open', 'partial', 'overdue')
+      AND bp.fee_waived_at IS NULL
+  )
+ORDER BY s.start_date
+`
+
+func (q *Queries) ListSubscriptionsWithOpenPeriods(ctx context.Context) ([]Subscription, error) {
+	rows, err := q.db.Query(ctx, listSubscriptionsWithOpenPeriods)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Subscription
+	for rows.Next() {
+		var i Subscription
+		if err := rows.Scan(
+			&i.ID,
+			&i.StudentID,
+			&i.PlanID,
+			&i.StartDate,
+			&i.EndDate,
+			&i.Status,
+			&i.PriceCents,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.PaymentDay,
+			&i.AutoRenew,
+			&i.CouponID,
+			&i.DiscountKind,
+			&i.DiscountValue,
+			&i.DiscountDuration,
+			&i.DiscountPeriods,
+			&i.PreviousSubscriptionID,
 		); err != nil {
 			return nil, err
 		}
@@ -255,19 +484,29 @@ SET
   price_cents = $5,
   payment_day = $6,
   auto_renew = $7,
+  coupon_id = $8,
+  discount_kind = $9,
+  discount_value = $10,
+  discount_duration = $11,
+  discount_periods = $12,
   updated_at = now()
 WHERE id = $1
-RETURNING id, student_id, plan_id, start_date, end_date, status, price_cents, created_at, updated_at, payment_day, auto_renew
+RETURNING id, student_id, plan_id, start_date, end_date, status, price_cents, created_at, updated_at, payment_day, auto_renew, coupon_id, discount_kind, discount_value, discount_duration, discount_periods, previous_subscription_id
 `
 
 type UpdateSubscriptionParams struct {
-	ID         pgtype.UUID        `json:"id"`
-	StartDate  pgtype.Date        `json:"start_date"`
-	EndDate    pgtype.Date        `json:"end_date"`
-	Status     SubscriptionStatus `json:"status"`
-	PriceCents int64              `json:"price_cents"`
-	PaymentDay int32              `json:"payment_day"`
-	AutoRenew  bool               `json:"auto_renew"`
+	ID               pgtype.UUID        `json:"id"`
+	StartDate        pgtype.Date        `json:"start_date"`
+	EndDate          pgtype.Date        `json:"end_date"`
+	Status           SubscriptionStatus `json:"status"`
+	PriceCents       int64              `json:"price_cents"`
+	PaymentDay       int32              `json:"payment_day"`
+	AutoRenew        bool               `json:"auto_renew"`
+	CouponID         pgtype.UUID        `json:"coupon_id"`
+	DiscountKind     pgtype.Text        `json:"discount_kind"`
+	DiscountValue    int64              `json:"discount_value"`
+	DiscountDuration pgtype.Text        `json:"discount_duration"`
+	DiscountPeriods  int32              `json:"discount_periods"`
 }
 
 func (q *Queries) UpdateSubscription(ctx context.Context, arg UpdateSubscriptionParams) (Subscription, error) {
@@ -279,6 +518,11 @@ func (q *Queries) UpdateSubscription(ctx context.Context, arg UpdateSubscription
 		arg.PriceCents,
 		arg.PaymentDay,
 		arg.AutoRenew,
+		arg.CouponID,
+		arg.DiscountKind,
+		arg.DiscountValue,
+		arg.DiscountDuration,
+		arg.DiscountPeriods,
 	)
 	var i Subscription
 	err := row.Scan(
@@ -293,6 +537,12 @@ func (q *Queries) UpdateSubscription(ctx context.Context, arg UpdateSubscription
 		&i.UpdatedAt,
 		&i.PaymentDay,
 		&i.AutoRenew,
+		&i.CouponID,
+		&i.DiscountKind,
+		&i.DiscountValue,
+		&i.DiscountDuration,
+		&i.DiscountPeriods,
+		&i.PreviousSubscriptionID,
 	)
 	return i, err
 }
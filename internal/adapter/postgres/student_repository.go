@@ -19,6 +19,10 @@ func NewStudentRepository(pool *pgxpool.Pool) *StudentRepository {
 	return &StudentRepository{queries: sqlc.New(pool)}
 }
 
+func NewStudentRepositoryWithQueries(queries *sqlc.Queries) *StudentRepository {
+	return &StudentRepository{queries: queries}
+}
+
 func (r *StudentRepository) Create(ctx context.Context, student domain.Student) (domain.Student, error) {
 	params := sqlc.CreateStudentParams{
 		FullName:       student.FullName,
@@ -86,6 +90,21 @@ func (r *StudentRepository) FindByID(ctx context.Context, id string) (domain.Stu
 	return mapStudent(student), nil
 }
 
+func (r *StudentRepository) FindIDByPhotoObjectKey(ctx context.Context, objectKey string) (string, error) {
+	if objectKey == "" {
+		return "", ports.ErrNotFound
+	}
+
+	id, err := r.queries.GetStudentIDByPhotoObjectKey(ctx, textTo(objectKey))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return "", ports.ErrNotFound
+		}
+		return "", err
+	}
+	return uuidToString(id), nil
+}
+
 func (r *StudentRepository) Search(ctx context.Context, filter ports.StudentFilter) ([]domain.Student, error) {
 	limit := filter.Limit
 	if limit <= 0 {
@@ -114,6 +133,33 @@ func (r *StudentRepository) Search(ctx context.Context, filter ports.StudentFilt
 	return result, nil
 }
 
+// SearchPage aplica os filtros de Search, ignorando Limit e Offset, e pagina
+// por created_at e id.
+func (r *StudentRepository) SearchPage(ctx context.Context, filter ports.StudentFilter, page ports.PageRequest) ([]domain.Student, error) {
+	afterCreatedAt, afterID, err := pageCursorTo(page.After)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := r.queries.SearchStudentsPage(ctx, sqlc.SearchStudentsPageParams{
+		Query:          filter.Query,
+		Statuses:       studentStatuses(filter.Statuses),
+		AfterCreatedAt: afterCreatedAt,
+		AfterID:        afterID,
+		RowLimit:       int32(page.Limit),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]domain.Student, 0, len(rows))
+	for _, student := range rows {
+		result = append(result, mapStudent(student))
+	}
+
+	return result, nil
+}
+
 func (r *StudentRepository) Count(ctx context.Context, filter ports.StudentFilter) (int, error) {
 	params := sqlc.CountStudentsParams{
 		Column1: filter.Query,
@@ -0,0 +1,99 @@
+package postgres
+
+import (
+	"context"
+
+	"github.com/PabloPavan/jaiu/internal/adapter/postgres/sqlc"
+	"github.com/PabloPavan/jaiu/internal/domain"
+	"github.com/PabloPavan/jaiu/internal/ports"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type SubscriptionFreezeRepository struct {
+	queries *sqlc.Queries
+}
+
+func NewSubscriptionFreezeRepository(pool *pgxpool.Pool) *SubscriptionFreezeRepository {
+	return &SubscriptionFreezeRepository{queries: sqlc.New(pool)}
+}
+
+func NewSubscriptionFreezeRepositoryWithQueries(queries *sqlc.Queries) *SubscriptionFreezeRepository {
+	return &SubscriptionFreezeRepository{queries: queries}
+}
+
+func (r *SubscriptionFreezeRepository) Create(ctx context.Context, freeze domain.SubscriptionFreeze) (domain.SubscriptionFreeze, error) {
+	subscriptionID, err := stringToUUID(freeze.SubscriptionID)
+	if err != nil || !subscriptionID.Valid {
+		return domain.SubscriptionFreeze{}, err
+	}
+
+	created, err := r.queries.CreateSubscriptionFreeze(ctx, sqlc.CreateSubscriptionFreezeParams{
+		SubscriptionID: subscriptionID,
+		StartDate:      dateTo(&freeze.StartDate),
+		EndDate:        dateTo(&freeze.EndDate),
+		Reason:         freeze.Reason,
+	})
+	if err != nil {
+		return domain.SubscriptionFreeze{}, err
+	}
+
+	return mapSubscriptionFreeze(created), nil
+}
+
+func (r *SubscriptionFreezeRepository) ListBySubscription(ctx context.Context, subscriptionID string) ([]domain.SubscriptionFreeze, error) {
+	uuidValue, err := stringToUUID(subscriptionID)
+	if err != nil || !uuidValue.Valid {
+		return nil, err
+	}
+
+	freezes, err := r.queries.ListSubscriptionFreezes(ctx, uuidValue)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]domain.SubscriptionFreeze, 0, len(freezes))
+	for _, freeze := range freezes {
+		result = append(result, mapSubscriptionFreeze(freeze))
+	}
+
+	return result, nil
+}
+
+func (r *SubscriptionFreezeRepository) ListBySubscriptionPage(ctx context.Context, subscriptionID string, page ports.PageRequest) ([]domain.SubscriptionFreeze, error) {
+	uuidValue, err := stringToUUID(subscriptionID)
+	if err != nil || !uuidValue.Valid {
+		return nil, err
+	}
+	afterCreatedAt, afterID, err := pageCursorTo(page.After)
+	if err != nil {
+		return nil, err
+	}
+
+	freezes, err := r.queries.ListSubscriptionFreezesPage(ctx, sqlc.ListSubscriptionFreezesPageParams{
+		SubscriptionID: uuidValue,
+		AfterCreatedAt: afterCreatedAt,
+		AfterID:        afterID,
+		RowLimit:       int32(page.Limit),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]domain.SubscriptionFreeze, 0, len(freezes))
+	for _, freeze := range freezes {
+		result = append(result, mapSubscriptionFreeze(freeze))
+	}
+
+	return result, nil
+}
+
+func mapSubscriptionFreeze(freeze sqlc.SubscriptionFreeze) domain.SubscriptionFreeze {
+	return domain.SubscriptionFreeze{
+		ID:             uuidToString(freeze.ID),
+		SubscriptionID: uuidToString(freeze.SubscriptionID),
+		StartDate:      dateFromValue(freeze.StartDate),
+		EndDate:        dateFromValue(freeze.EndDate),
+		Reason:         freeze.Reason,
+		CreatedAt:      timeFrom(freeze.CreatedAt),
+	}
+}
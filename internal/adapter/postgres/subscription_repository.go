@@ -34,15 +34,30 @@ func (r *SubscriptionRepository) Create(ctx context.Context, subscription domain
 		return domain.Subscription{}, err
 	}
 
+	couponID, err := stringToUUID(subscription.CouponID)
+	if err != nil {
+		return domain.Subscription{}, err
+	}
+	previousID, err := stringToUUID(subscription.PreviousSubscriptionID)
+	if err != nil {
+		return domain.Subscription{}, err
+	}
+
 	params := sqlc.CreateSubscriptionParams{
-		StudentID:  studentID,
-		PlanID:     planID,
-		StartDate:  dateTo(&subscription.StartDate),
-		EndDate:    dateTo(&subscription.EndDate),
-		Status:     sqlc.SubscriptionStatus(subscription.Status),
-		PriceCents: subscription.PriceCents,
-		PaymentDay: int32(subscription.PaymentDay),
-		AutoRenew:  subscription.AutoRenew,
+		StudentID:              studentID,
+		PlanID:                 planID,
+		StartDate:              dateTo(&subscription.StartDate),
+		EndDate:                dateTo(&subscription.EndDate),
+		Status:                 sqlc.SubscriptionStatus(subscription.Status),
+		PriceCents:             subscription.PriceCents,
+		PaymentDay:             int32(subscription.PaymentDay),
+		AutoRenew:              subscription.AutoRenew,
+		CouponID:               couponID,
+		DiscountKind:           textTo(string(subscription.Discount.Kind)),
+		DiscountValue:          subscription.Discount.Value,
+		DiscountDuration:       textTo(string(subscription.Discount.Duration)),
+		DiscountPeriods:        int32(subscription.Discount.Periods),
+		PreviousSubscriptionID: previousID,
 	}
 
 	created, err := r.queries.CreateSubscription(ctx, params)
@@ -59,14 +74,24 @@ func (r *SubscriptionRepository) Update(ctx context.Context, subscription domain
 		return domain.Subscription{}, err
 	}
 
+	couponID, err := stringToUUID(subscription.CouponID)
+	if err != nil {
+		return domain.Subscription{}, err
+	}
+
 	params := sqlc.UpdateSubscriptionParams{
-		ID:         id,
-		StartDate:  dateTo(&subscription.StartDate),
-		EndDate:    dateTo(&subscription.EndDate),
-		Status:     sqlc.SubscriptionStatus(subscription.Status),
-		PriceCents: subscription.PriceCents,
-		PaymentDay: int32(subscription.PaymentDay),
-		AutoRenew:  subscription.AutoRenew,
+		ID:               id,
+		StartDate:        dateTo(&subscription.StartDate),
+		EndDate:          dateTo(&subscription.EndDate),
+		Status:           sqlc.SubscriptionStatus(subscription.Status),
+		PriceCents:       subscription.PriceCents,
+		PaymentDay:       int32(subscription.PaymentDay),
+		AutoRenew:        subscription.AutoRenew,
+		CouponID:         couponID,
+		DiscountKind:     textTo(string(subscription.Discount.Kind)),
+		DiscountValue:    subscription.Discount.Value,
+		DiscountDuration: textTo(string(subscription.Discount.Duration)),
+		DiscountPeriods:  int32(subscription.Discount.Periods),
 	}
 
 	updated, err := r.queries.UpdateSubscription(ctx, params)
@@ -113,6 +138,34 @@ func (r *SubscriptionRepository) ListByStudent(ctx context.Context, studentID st
 	return result, nil
 }
 
+func (r *SubscriptionRepository) ListByStudentPage(ctx context.Context, studentID string, page ports.PageRequest) ([]domain.Subscription, error) {
+	uuidValue, err := stringToUUID(studentID)
+	if err != nil || !uuidValue.Valid {
+		return nil, err
+	}
+	afterCreatedAt, afterID, err := pageCursorTo(page.After)
+	if err != nil {
+		return nil, err
+	}
+
+	subscriptions, err := r.queries.ListSubscriptionsByStudentPage(ctx, sqlc.ListSubscriptionsByStudentPageParams{
+		StudentID:      uuidValue,
+		AfterCreatedAt: afterCreatedAt,
+		AfterID:        afterID,
+		RowLimit:       int32(page.Limit),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]domain.Subscription, 0, len(subscriptions))
+	for _, subscription := range subscriptions {
+		result = append(result, mapSubscription(subscription))
+	}
+
+	return result, nil
+}
+
 func (r *SubscriptionRepository) ListByPlan(ctx context.Context, planID string) ([]domain.Subscription, error) {
 	uuidValue, err := stringToUUID(planID)
 	if err != nil || !uuidValue.Valid {
@@ -151,6 +204,31 @@ func (r *SubscriptionRepository) ListDueBetween(ctx context.Context, start, end
 	return result, nil
 }
 
+func (r *SubscriptionRepository) ListDueBetweenPage(ctx context.Context, start, end time.Time, page ports.PageRequest) ([]domain.Subscription, error) {
+	afterCreatedAt, afterID, err := pageCursorTo(page.After)
+	if err != nil {
+		return nil, err
+	}
+
+	subscriptions, err := r.queries.ListSubscriptionsDueBetweenPage(ctx, sqlc.ListSubscriptionsDueBetweenPageParams{
+		DueFrom:        dateTo(&start),
+		DueTo:          dateTo(&end),
+		AfterCreatedAt: afterCreatedAt,
+		AfterID:        afterID,
+		RowLimit:       int32(page.Limit),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]domain.Subscription, 0, len(subscriptions))
+	for _, subscription := range subscriptions {
+		result = append(result, mapSubscription(subscription))
+	}
+
+	return result, nil
+}
+
 func (r *SubscriptionRepository) ListAutoRenew(ctx context.Context) ([]domain.Subscription, error) {
 	subscriptions, err := r.queries.ListAutoRenewSubscriptions(ctx)
 	if err != nil {
@@ -165,18 +243,40 @@ func (r *SubscriptionRepository) ListAutoRenew(ctx context.Context) ([]domain.Su
 	return result, nil
 }
 
+func (r *SubscriptionRepository) ListWithOpenPeriods(ctx context.Context) ([]domain.Subscription, error) {
+	subscriptions, err := r.queries.ListSubscriptionsWithOpenPeriods(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]domain.Subscription, 0, len(subscriptions))
+	for _, subscription := range subscriptions {
+		result = append(result, mapSubscription(subscription))
+	}
+
+	return result, nil
+}
+
 func mapSubscription(subscription sqlc.Subscription) domain.Subscription {
 	return domain.Subscription{
-		ID:         uuidToString(subscription.ID),
-		StudentID:  uuidToString(subscription.StudentID),
-		PlanID:     uuidToString(subscription.PlanID),
-		StartDate:  dateFromValue(subscription.StartDate),
-		EndDate:    dateFromValue(subscription.EndDate),
-		Status:     domain.SubscriptionStatus(subscription.Status),
-		PriceCents: subscription.PriceCents,
-		PaymentDay: int(subscription.PaymentDay),
-		AutoRenew:  subscription.AutoRenew,
-		CreatedAt:  timeFrom(subscription.CreatedAt),
-		UpdatedAt:  timeFrom(subscription.UpdatedAt),
+		ID:                     uuidToString(subscription.ID),
+		StudentID:              uuidToString(subscription.StudentID),
+		PlanID:                 uuidToString(subscription.PlanID),
+		StartDate:              dateFromValue(subscription.StartDate),
+		EndDate:                dateFromValue(subscription.EndDate),
+		Status:                 domain.SubscriptionStatus(subscription.Status),
+		PriceCents:             subscription.PriceCents,
+		PaymentDay:             int(subscription.PaymentDay),
+		AutoRenew:              subscription.AutoRenew,
+		CouponID:               uuidToString(subscription.CouponID),
+		PreviousSubscriptionID: uuidToString(subscription.PreviousSubscriptionID),
+		Discount: domain.Discount{
+			Kind:     domain.DiscountKind(textFrom(subscription.DiscountKind)),
+			Value:    subscription.DiscountValue,
+			Duration: domain.DiscountDuration(textFrom(subscription.DiscountDuration)),
+			Periods:  int(subscription.DiscountPeriods),
+		},
+		CreatedAt: timeFrom(subscription.CreatedAt),
+		UpdatedAt: timeFrom(subscription.UpdatedAt),
 	}
 }
@@ -0,0 +1,170 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"sync"
+	"time"
+
+	"github.com/PabloPavan/jaiu/internal/adapter/postgres/sqlc"
+	"github.com/PabloPavan/jaiu/internal/ports"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+)
+
+const (
+	maxTxAttempts = 5
+	txBackoffBase = 20 * time.Millisecond
+	txBackoffMax  = 500 * time.Millisecond
+)
+
+type txMetrics struct {
+	retries   metric.Int64Counter
+	exhausted metric.Int64Counter
+}
+
+var (
+	txMetricsOnce sync.Once
+	txStats       txMetrics
+)
+
+// TxRunner abre uma transacao no pool e entrega a fn repositorios ligados a
+// ela. Os eventos de auditoria registrados por fn sao encadeados na mesma
+// transacao, logo antes do commit, e descartados quando a tentativa e desfeita.
+type TxRunner struct {
+	pool  *pgxpool.Pool
+	sleep func(context.Context, time.Duration) error
+}
+
+func NewTxRunner(pool *pgxpool.Pool) *TxRunner {
+	txMetricsOnce.Do(initTxMetrics)
+	return &TxRunner{pool: pool, sleep: sleepContext}
+}
+
+// RunInTx confirma a transacao quando fn termina sem erro. Falhas de
+// serializacao e deadlocks desfazem a tentativa e executam fn novamente,
+// apos um intervalo aleatorio, ate maxTxAttempts vezes.
+func (r *TxRunner) RunInTx(ctx context.Context, opts ports.TxOptions, fn func(context.Context, ports.TxDependencies) error) error {
+	if r == nil || r.pool == nil {
+		return errors.New("transaction pool unavailable")
+	}
+
+	txOptions := pgx.TxOptions{IsoLevel: pgx.TxIsoLevel(opts.Isolation)}
+	attrs := metric.WithAttributes(attribute.String("db.tx.isolation", isolationName(opts.Isolation)))
+	for attempt := 0; ; attempt++ {
+		err := r.runOnce(ctx, txOptions, fn)
+		if err == nil || !isRetryable(err) {
+			return err
+		}
+		if attempt == maxTxAttempts-1 {
+			txStats.exhausted.Add(ctx, 1, attrs)
+			if errors.Is(err, ports.ErrSerialization) {
+				return err
+			}
+			return fmt.Errorf("%w: %w", ports.ErrSerialization, err)
+		}
+
+		txStats.retries.Add(ctx, 1, attrs)
+		if err := r.sleep(ctx, backoff(attempt)); err != nil {
+			return err
+		}
+	}
+}
+
+func (r *TxRunner) runOnce(ctx context.Context, opts pgx.TxOptions, fn func(context.Context, ports.TxDependencies) error) error {
+	tx, err := r.pool.BeginTx(ctx, opts)
+	if err != nil {
+		return err
+	}
+
+	audit := &pendingAudit{}
+	if err := fn(ctx, newTxDependencies(tx, audit)); err != nil {
+		_ = tx.Rollback(ctx)
+		return err
+	}
+	// Em transacoes serializaveis, um head alterado depois do snapshot gera
+	// 40001 aqui e a tentativa inteira e repetida.
+	if err := audit.flush(ctx, sqlc.New(tx)); err != nil {
+		_ = tx.Rollback(ctx)
+		return err
+	}
+	return tx.Commit(ctx)
+}
+
+func newTxDependencies(tx pgx.Tx, audit ports.AuditRepository) ports.TxDependencies {
+	queries := sqlc.New(tx)
+	return ports.TxDependencies{
+		Students:       NewStudentRepositoryWithQueries(queries),
+		Plans:          NewPlanRepositoryWithQueries(queries),
+		Subscriptions:  NewSubscriptionRepositoryWithQueries(queries),
+		Freezes:        NewSubscriptionFreezeRepositoryWithQueries(queries),
+		Payments:       NewPaymentRepositoryWithQueries(queries),
+		BillingPeriods: NewBillingPeriodRepositoryWithQueries(queries),
+		Balances:       NewSubscriptionBalanceRepositoryWithQueries(queries),
+		Allocations:    NewPaymentAllocationRepositoryWithQueries(queries),
+		Transfers:      NewPaymentCreditTransferRepositoryWithQueries(queries),
+		Users:          NewUserRepositoryWithQueries(queries),
+		Audit:          audit,
+	}
+}
+
+// isRetryable cobre falhas de serializacao (40001), deadlocks (40P01) e
+// conflitos que o servico sinalizou com ports.ErrSerialization.
+func isRetryable(err error) bool {
+	if errors.Is(err, ports.ErrSerialization) {
+		return true
+	}
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		return pgErr.Code == "40001" || pgErr.Code == "40P01"
+	}
+	return false
+}
+
+// backoff dobra o intervalo a cada tentativa, limitado a txBackoffMax, e
+// sorteia um valor entre a metade e o total para dessincronizar as transacoes
+// concorrentes.
+func backoff(attempt int) time.Duration {
+	delay := txBackoffBase << attempt
+	if delay <= 0 || delay > txBackoffMax {
+		delay = txBackoffMax
+	}
+	half := delay / 2
+	return half + rand.N(half+1)
+}
+
+func sleepContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+func isolationName(isolation ports.TxIsolation) string {
+	if isolation == "" {
+		return string(ports.TxReadCommitted)
+	}
+	return string(isolation)
+}
+
+func initTxMetrics() {
+	meter := otel.Meter("db.tx")
+	txStats.retries, _ = meter.Int64Counter(
+		"db.tx.retries",
+		metric.WithDescription("Transacoes repetidas por falha de serializacao ou deadlock"),
+	)
+	txStats.exhausted, _ = meter.Int64Counter(
+		"db.tx.retries_exhausted",
+		metric.WithDescription("Transacoes que falharam apos esgotar as tentativas"),
+	)
+}
@@ -8,6 +8,7 @@ import (
 	"github.com/PabloPavan/jaiu/internal/domain"
 	"github.com/PabloPavan/jaiu/internal/ports"
 	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
@@ -19,17 +20,25 @@ func NewUserRepository(pool *pgxpool.Pool) *UserRepository {
 	return &UserRepository{queries: sqlc.New(pool)}
 }
 
+func NewUserRepositoryWithQueries(queries *sqlc.Queries) *UserRepository {
+	return &UserRepository{queries: queries}
+}
+
 func (r *UserRepository) Create(ctx context.Context, user domain.User) (domain.User, error) {
 	params := sqlc.CreateUserParams{
-		Name:         user.Name,
-		Email:        user.Email,
-		PasswordHash: user.PasswordHash,
-		Role:         sqlc.UserRole(user.Role),
-		Active:       user.Active,
+		Name:               user.Name,
+		Email:              user.Email,
+		PasswordHash:       user.PasswordHash,
+		Role:               sqlc.UserRole(user.Role),
+		Active:             user.Active,
+		MustChangePassword: user.MustChangePassword,
 	}
 
 	created, err := r.queries.CreateUser(ctx, params)
 	if err != nil {
+		if isUserEmailConflict(err) {
+			return domain.User{}, ports.ErrConflict
+		}
 		return domain.User{}, err
 	}
 
@@ -48,15 +57,111 @@ func (r *UserRepository) FindByEmail(ctx context.Context, email string) (domain.
 	return mapUser(user), nil
 }
 
+func (r *UserRepository) FindByID(ctx context.Context, id string) (domain.User, error) {
+	uuidValue, err := stringToUUID(id)
+	if err != nil || !uuidValue.Valid {
+		return domain.User{}, ports.ErrNotFound
+	}
+
+	user, err := r.queries.GetUser(ctx, uuidValue)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return domain.User{}, ports.ErrNotFound
+		}
+		return domain.User{}, err
+	}
+
+	return mapUser(user), nil
+}
+
+func (r *UserRepository) List(ctx context.Context) ([]domain.User, error) {
+	users, err := r.queries.ListUsers(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]domain.User, 0, len(users))
+	for _, user := range users {
+		result = append(result, mapUser(user))
+	}
+
+	return result, nil
+}
+
+func (r *UserRepository) Update(ctx context.Context, user domain.User) (domain.User, error) {
+	uuidValue, err := stringToUUID(user.ID)
+	if err != nil || !uuidValue.Valid {
+		return domain.User{}, ports.ErrNotFound
+	}
+
+	params := sqlc.UpdateUserParams{
+		ID:                 uuidValue,
+		Name:               user.Name,
+		Email:              user.Email,
+		Role:               sqlc.UserRole(user.Role),
+		Active:             user.Active,
+		MustChangePassword: user.MustChangePassword,
+	}
+
+	updated, err := r.queries.UpdateUser(ctx, params)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return domain.User{}, ports.ErrNotFound
+		}
+		if isUserEmailConflict(err) {
+			return domain.User{}, ports.ErrConflict
+		}
+		return domain.User{}, err
+	}
+
+	return mapUser(updated), nil
+}
+
+func (r *UserRepository) SetActive(ctx context.Context, id string, active bool) (domain.User, error) {
+	uuidValue, err := stringToUUID(id)
+	if err != nil || !uuidValue.Valid {
+		return domain.User{}, ports.ErrNotFound
+	}
+
+	updated, err := r.queries.SetUserActive(ctx, sqlc.SetUserActiveParams{ID: uuidValue, Active: active})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return domain.User{}, ports.ErrNotFound
+		}
+		return domain.User{}, err
+	}
+
+	return mapUser(updated), nil
+}
+
+func (r *UserRepository) UpdatePassword(ctx context.Context, id, passwordHash string, mustChange bool) error {
+	uuidValue, err := stringToUUID(id)
+	if err != nil || !uuidValue.Valid {
+		return ports.ErrNotFound
+	}
+
+	return r.queries.UpdateUserPassword(ctx, sqlc.UpdateUserPasswordParams{
+		ID:                 uuidValue,
+		PasswordHash:       passwordHash,
+		MustChangePassword: mustChange,
+	})
+}
+
+func isUserEmailConflict(err error) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == "23505" && pgErr.ConstraintName == "users_email_key"
+}
+
 func mapUser(user sqlc.User) domain.User {
 	return domain.User{
-		ID:           uuidToString(user.ID),
-		Name:         user.Name,
-		Email:        user.Email,
-		PasswordHash: user.PasswordHash,
-		Role:         domain.UserRole(user.Role),
-		Active:       user.Active,
-		CreatedAt:    timeFrom(user.CreatedAt),
-		UpdatedAt:    timeFrom(user.UpdatedAt),
+		ID:                 uuidToString(user.ID),
+		Name:               user.Name,
+		Email:              user.Email,
+		PasswordHash:       user.PasswordHash,
+		Role:               domain.UserRole(user.Role),
+		Active:             user.Active,
+		MustChangePassword: user.MustChangePassword,
+		CreatedAt:          timeFrom(user.CreatedAt),
+		UpdatedAt:          timeFrom(user.UpdatedAt),
 	}
 }
@@ -0,0 +1,23 @@
+package redis
+
+import (
+	"context"
+	"time"
+
+	redis "github.com/redis/go-redis/v9"
+)
+
+// AccessDedupStore usa SET NX com expiracao para que apenas a primeira
+// instancia registre o acesso dentro da janela.
+type AccessDedupStore struct {
+	client *redis.Client
+	prefix string
+}
+
+func NewAccessDedupStore(client *redis.Client) *AccessDedupStore {
+	return &AccessDedupStore{client: client, prefix: "access_dedup:"}
+}
+
+func (s *AccessDedupStore) Claim(ctx context.Context, key string, window time.Duration) (bool, error) {
+	return s.client.SetNX(ctx, s.prefix+key, 1, window).Result()
+}
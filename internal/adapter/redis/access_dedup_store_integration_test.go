@@ -0,0 +1,33 @@
+//go:build integration
+
+package redis
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+// Testa que a chave de acesso so e reivindicada uma vez dentro da janela.
+func TestAccessDedupStoreIntegration(t *testing.T) {
+	client := integrationRedisClient(t)
+	store := NewAccessDedupStore(client)
+	ctx := context.Background()
+
+	claimed, err := store.Claim(ctx, "user-1:stu-1", time.Minute)
+	if err != nil || !claimed {
+		t.Fatalf("expected first claim, got %v err=%v", claimed, err)
+	}
+	claimed, err = store.Claim(ctx, "user-1:stu-1", time.Minute)
+	if err != nil || claimed {
+		t.Fatalf("expected duplicate claim to be rejected, got %v err=%v", claimed, err)
+	}
+
+	ttl, err := client.TTL(ctx, "access_dedup:user-1:stu-1").Result()
+	if err != nil {
+		t.Fatalf("ttl: %v", err)
+	}
+	if ttl <= 0 || ttl > time.Minute {
+		t.Fatalf("unexpected ttl: %v", ttl)
+	}
+}
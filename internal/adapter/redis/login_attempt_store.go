@@ -0,0 +1,102 @@
+package redis
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/PabloPavan/jaiu/internal/ports"
+	redis "github.com/redis/go-redis/v9"
+)
+
+type LoginAttemptStore struct {
+	client *redis.Client
+	prefix string
+}
+
+func NewLoginAttemptStore(client *redis.Client) *LoginAttemptStore {
+	return &LoginAttemptStore{client: client, prefix: "login_attempts:"}
+}
+
+func (s *LoginAttemptStore) Get(ctx context.Context, key string) (ports.LoginAttempts, error) {
+	values, err := s.client.MGet(ctx, s.failuresKey(key), s.lockoutsKey(key), s.lockKey(key)).Result()
+	if err != nil {
+		return ports.LoginAttempts{}, err
+	}
+
+	attempts := ports.LoginAttempts{
+		Failures: parseCounter(values[0]),
+		Lockouts: parseCounter(values[1]),
+	}
+	if raw, ok := values[2].(string); ok {
+		unix, err := strconv.ParseInt(raw, 10, 64)
+		if err == nil {
+			until := time.Unix(unix, 0)
+			if time.Now().Before(until) {
+				attempts.LockedUntil = until
+			}
+		}
+	}
+
+	return attempts, nil
+}
+
+func (s *LoginAttemptStore) RegisterFailure(ctx context.Context, key string, window time.Duration) (int, error) {
+	failuresKey := s.failuresKey(key)
+	count, err := s.client.Incr(ctx, failuresKey).Result()
+	if err != nil {
+		return 0, err
+	}
+	if count == 1 && window > 0 {
+		if err := s.client.Expire(ctx, failuresKey, window).Err(); err != nil {
+			return 0, err
+		}
+	}
+
+	return int(count), nil
+}
+
+func (s *LoginAttemptStore) Lock(ctx context.Context, key string, until time.Time, memory time.Duration) error {
+	ttl := time.Until(until)
+	if ttl <= 0 {
+		return nil
+	}
+
+	pipe := s.client.TxPipeline()
+	pipe.Set(ctx, s.lockKey(key), strconv.FormatInt(until.Unix(), 10), ttl)
+	pipe.Del(ctx, s.failuresKey(key))
+	pipe.Incr(ctx, s.lockoutsKey(key))
+	if memory > 0 {
+		pipe.Expire(ctx, s.lockoutsKey(key), memory)
+	}
+	_, err := pipe.Exec(ctx)
+	return err
+}
+
+func (s *LoginAttemptStore) Reset(ctx context.Context, key string) error {
+	return s.client.Del(ctx, s.failuresKey(key), s.lockoutsKey(key), s.lockKey(key)).Err()
+}
+
+func (s *LoginAttemptStore) failuresKey(key string) string {
+	return s.prefix + "failures:" + key
+}
+
+func (s *LoginAttemptStore) lockoutsKey(key string) string {
+	return s.prefix + "lockouts:" + key
+}
+
+func (s *LoginAttemptStore) lockKey(key string) string {
+	return s.prefix + "lock:" + key
+}
+
+func parseCounter(value any) int {
+	raw, ok := value.(string)
+	if !ok {
+		return 0
+	}
+	parsed, err := strconv.Atoi(raw)
+	if err != nil {
+		return 0
+	}
+	return parsed
+}
@@ -0,0 +1,53 @@
+//go:build integration
+
+package redis
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+// Testa contagem de falhas, bloqueio e reset das tentativas de login no Redis.
+func TestLoginAttemptStoreIntegration(t *testing.T) {
+	client := integrationRedisClient(t)
+	store := NewLoginAttemptStore(client)
+	ctx := context.Background()
+
+	for i := 1; i <= 3; i++ {
+		count, err := store.RegisterFailure(ctx, "email:user@example.com", time.Minute)
+		if err != nil {
+			t.Fatalf("register failure: %v", err)
+		}
+		if count != i {
+			t.Fatalf("expected %d failures, got %d", i, count)
+		}
+	}
+
+	until := time.Now().Add(2 * time.Minute)
+	if err := store.Lock(ctx, "email:user@example.com", until, time.Hour); err != nil {
+		t.Fatalf("lock: %v", err)
+	}
+
+	attempts, err := store.Get(ctx, "email:user@example.com")
+	if err != nil {
+		t.Fatalf("get: %v", err)
+	}
+	if attempts.Failures != 0 || attempts.Lockouts != 1 {
+		t.Fatalf("expected failures reset and one lockout, got %#v", attempts)
+	}
+	if attempts.LockedUntil.Unix() != until.Unix() {
+		t.Fatalf("expected locked until %v, got %v", until, attempts.LockedUntil)
+	}
+
+	if err := store.Reset(ctx, "email:user@example.com"); err != nil {
+		t.Fatalf("reset: %v", err)
+	}
+	attempts, err = store.Get(ctx, "email:user@example.com")
+	if err != nil {
+		t.Fatalf("get after reset: %v", err)
+	}
+	if attempts.Lockouts != 0 || !attempts.LockedUntil.IsZero() {
+		t.Fatalf("expected clean attempts after reset, got %#v", attempts)
+	}
+}
@@ -0,0 +1,104 @@
+package redis
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/PabloPavan/jaiu/internal/ports"
+	redis "github.com/redis/go-redis/v9"
+)
+
+// LoginChallengeStore guarda os logins aguardando o segundo fator. As falhas
+// ficam em um contador separado para que o incremento seja atomico.
+type LoginChallengeStore struct {
+	client *redis.Client
+	prefix string
+}
+
+func NewLoginChallengeStore(client *redis.Client) *LoginChallengeStore {
+	return &LoginChallengeStore{client: client, prefix: "login_challenge:"}
+}
+
+func (s *LoginChallengeStore) Create(ctx context.Context, challenge ports.LoginChallenge) (string, error) {
+	token, err := generateToken()
+	if err != nil {
+		return "", err
+	}
+
+	if challenge.ExpiresAt.IsZero() {
+		challenge.ExpiresAt = time.Now().Add(5 * time.Minute)
+	}
+	challenge.Failures = 0
+
+	payload, err := json.Marshal(challenge)
+	if err != nil {
+		return "", err
+	}
+
+	ttl := time.Until(challenge.ExpiresAt)
+	if ttl <= 0 {
+		return "", errors.New("login challenge already expired")
+	}
+
+	if err := s.client.Set(ctx, s.prefix+token, payload, ttl).Err(); err != nil {
+		return "", err
+	}
+	return token, nil
+}
+
+func (s *LoginChallengeStore) Get(ctx context.Context, token string) (ports.LoginChallenge, error) {
+	if token == "" {
+		return ports.LoginChallenge{}, ports.ErrNotFound
+	}
+
+	values, err := s.client.MGet(ctx, s.prefix+token, s.failuresKey(token)).Result()
+	if err != nil {
+		return ports.LoginChallenge{}, err
+	}
+
+	raw, ok := values[0].(string)
+	if !ok {
+		return ports.LoginChallenge{}, ports.ErrNotFound
+	}
+
+	var challenge ports.LoginChallenge
+	if err := json.Unmarshal([]byte(raw), &challenge); err != nil {
+		return ports.LoginChallenge{}, err
+	}
+	if failures, ok := values[1].(string); ok {
+		challenge.Failures, _ = strconv.Atoi(failures)
+	}
+	return challenge, nil
+}
+
+func (s *LoginChallengeStore) RegisterFailure(ctx context.Context, token string) (int, error) {
+	ttl, err := s.client.PTTL(ctx, s.prefix+token).Result()
+	if err != nil {
+		return 0, err
+	}
+	if ttl <= 0 {
+		return 0, ports.ErrNotFound
+	}
+
+	pipe := s.client.TxPipeline()
+	incr := pipe.Incr(ctx, s.failuresKey(token))
+	pipe.PExpire(ctx, s.failuresKey(token), ttl)
+	if _, err := pipe.Exec(ctx); err != nil {
+		return 0, err
+	}
+	return int(incr.Val()), nil
+}
+
+func (s *LoginChallengeStore) Delete(ctx context.Context, token string) error {
+	if token == "" {
+		return nil
+	}
+	return s.client.Del(ctx, s.prefix+token, s.failuresKey(token)).Err()
+}
+
+func (s *LoginChallengeStore) failuresKey(token string) string {
+	return s.prefix + "failures:" + token
+}
@@ -0,0 +1,65 @@
+//go:build integration
+
+package redis
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/PabloPavan/jaiu/internal/domain"
+	"github.com/PabloPavan/jaiu/internal/ports"
+)
+
+// Testa criacao, contagem de falhas e remocao do desafio de segundo fator no Redis.
+func TestLoginChallengeStoreIntegration(t *testing.T) {
+	client := integrationRedisClient(t)
+	store := NewLoginChallengeStore(client)
+	ctx := context.Background()
+
+	token, err := store.Create(ctx, ports.LoginChallenge{
+		UserID:    "user-1",
+		Role:      domain.RoleAdmin,
+		Enroll:    true,
+		ExpiresAt: time.Now().Add(time.Minute),
+	})
+	if err != nil {
+		t.Fatalf("create challenge: %v", err)
+	}
+
+	for i := 1; i <= 2; i++ {
+		failures, err := store.RegisterFailure(ctx, token)
+		if err != nil {
+			t.Fatalf("register failure: %v", err)
+		}
+		if failures != i {
+			t.Fatalf("expected %d failures, got %d", i, failures)
+		}
+	}
+
+	challenge, err := store.Get(ctx, token)
+	if err != nil {
+		t.Fatalf("get challenge: %v", err)
+	}
+	if challenge.UserID != "user-1" || !challenge.Enroll || challenge.Failures != 2 {
+		t.Fatalf("unexpected challenge: %#v", challenge)
+	}
+
+	ttl, err := client.TTL(ctx, "login_challenge:failures:"+token).Result()
+	if err != nil {
+		t.Fatalf("ttl failures: %v", err)
+	}
+	if ttl <= 0 {
+		t.Fatalf("expected failures counter to expire, got %v", ttl)
+	}
+
+	if err := store.Delete(ctx, token); err != nil {
+		t.Fatalf("delete challenge: %v", err)
+	}
+	if _, err := store.Get(ctx, token); err != ports.ErrNotFound {
+		t.Fatalf("expected not found after delete, got %v", err)
+	}
+	if _, err := store.RegisterFailure(ctx, token); err != ports.ErrNotFound {
+		t.Fatalf("expected not found on failure after delete, got %v", err)
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"encoding/base64"
 	"encoding/json"
 	"errors"
+	"sort"
 	"time"
 
 	"github.com/PabloPavan/jaiu/internal/ports"
@@ -13,12 +14,18 @@ import (
 )
 
 type SessionStore struct {
-	client *redis.Client
-	prefix string
+	client     *redis.Client
+	prefix     string
+	userPrefix string
+}
+
+type userSession struct {
+	token   string
+	session ports.Session
 }
 
 func NewSessionStore(client *redis.Client) *SessionStore {
-	return &SessionStore{client: client, prefix: "session:"}
+	return &SessionStore{client: client, prefix: "session:", userPrefix: "user_sessions:"}
 }
 
 func (s *SessionStore) Create(ctx context.Context, session ports.Session) (string, error) {
@@ -27,8 +34,22 @@ func (s *SessionStore) Create(ctx context.Context, session ports.Session) (strin
 		return "", err
 	}
 
+	if session.ID == "" {
+		session.ID, err = generateSessionID()
+		if err != nil {
+			return "", err
+		}
+	}
+
+	now := time.Now()
 	if session.ExpiresAt.IsZero() {
-		session.ExpiresAt = time.Now().Add(24 * time.Hour)
+		session.ExpiresAt = now.Add(24 * time.Hour)
+	}
+	if session.CreatedAt.IsZero() {
+		session.CreatedAt = now
+	}
+	if session.LastSeenAt.IsZero() {
+		session.LastSeenAt = session.CreatedAt
 	}
 
 	payload, err := json.Marshal(session)
@@ -41,9 +62,19 @@ func (s *SessionStore) Create(ctx context.Context, session ports.Session) (strin
 		ttl = 24 * time.Hour
 	}
 
-	if err := s.client.Set(ctx, s.prefix+token, payload, ttl).Err(); err != nil {
+	pipe := s.client.TxPipeline()
+	pipe.Set(ctx, s.prefix+token, payload, ttl)
+	if session.UserID != "" {
+		pipe.SAdd(ctx, s.userPrefix+session.UserID, token)
+	}
+	if _, err := pipe.Exec(ctx); err != nil {
 		return "", err
 	}
+	if session.UserID != "" {
+		if err := s.extendUserIndex(ctx, s.userPrefix+session.UserID, ttl); err != nil {
+			return "", err
+		}
+	}
 
 	return token, nil
 }
@@ -61,8 +92,8 @@ func (s *SessionStore) Get(ctx context.Context, token string) (ports.Session, er
 		return ports.Session{}, err
 	}
 
-	var session ports.Session
-	if err := json.Unmarshal([]byte(value), &session); err != nil {
+	session, err := decodeSession(value)
+	if err != nil {
 		return ports.Session{}, err
 	}
 
@@ -74,12 +105,185 @@ func (s *SessionStore) Get(ctx context.Context, token string) (ports.Session, er
 	return session, nil
 }
 
+// Touch registra a atividade da sessao. Quando expiresAt e informado, o TTL
+// da chave e estendido ate esse instante; caso contrario o TTL e mantido.
+func (s *SessionStore) Touch(ctx context.Context, token string, seenAt, expiresAt time.Time) error {
+	session, err := s.Get(ctx, token)
+	if err != nil {
+		return err
+	}
+
+	session.LastSeenAt = seenAt
+	ttl := time.Duration(redis.KeepTTL)
+	if !expiresAt.IsZero() {
+		ttl = time.Until(expiresAt)
+		if ttl <= 0 {
+			return s.Delete(ctx, token)
+		}
+		session.ExpiresAt = expiresAt
+	}
+
+	payload, err := json.Marshal(session)
+	if err != nil {
+		return err
+	}
+
+	if err := s.client.SetXX(ctx, s.prefix+token, payload, ttl).Err(); err != nil {
+		return err
+	}
+	if ttl == redis.KeepTTL || session.UserID == "" {
+		return nil
+	}
+
+	return s.extendUserIndex(ctx, s.userPrefix+session.UserID, ttl)
+}
+
 func (s *SessionStore) Delete(ctx context.Context, token string) error {
 	if token == "" {
 		return nil
 	}
 
-	return s.client.Del(ctx, s.prefix+token).Err()
+	session, err := s.Get(ctx, token)
+	if err != nil && !errors.Is(err, ports.ErrNotFound) {
+		return err
+	}
+
+	pipe := s.client.TxPipeline()
+	pipe.Del(ctx, s.prefix+token)
+	if session.UserID != "" {
+		pipe.SRem(ctx, s.userPrefix+session.UserID, token)
+	}
+	_, err = pipe.Exec(ctx)
+	return err
+}
+
+func (s *SessionStore) ListByUser(ctx context.Context, userID string) ([]ports.Session, error) {
+	entries, err := s.userSessions(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	sessions := make([]ports.Session, 0, len(entries))
+	for _, entry := range entries {
+		sessions = append(sessions, entry.session)
+	}
+	sort.Slice(sessions, func(i, j int) bool {
+		return sessions[i].LastSeenAt.After(sessions[j].LastSeenAt)
+	})
+
+	return sessions, nil
+}
+
+func (s *SessionStore) DeleteByID(ctx context.Context, userID, sessionID string) error {
+	if sessionID == "" {
+		return ports.ErrNotFound
+	}
+
+	entries, err := s.userSessions(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	for _, entry := range entries {
+		if entry.session.ID != sessionID {
+			continue
+		}
+		pipe := s.client.TxPipeline()
+		pipe.Del(ctx, s.prefix+entry.token)
+		pipe.SRem(ctx, s.userPrefix+userID, entry.token)
+		_, err := pipe.Exec(ctx)
+		return err
+	}
+
+	return ports.ErrNotFound
+}
+
+func (s *SessionStore) DeleteByUser(ctx context.Context, userID string) error {
+	if userID == "" {
+		return nil
+	}
+
+	userKey := s.userPrefix + userID
+	tokens, err := s.client.SMembers(ctx, userKey).Result()
+	if err != nil {
+		return err
+	}
+
+	keys := make([]string, 0, len(tokens)+1)
+	for _, token := range tokens {
+		keys = append(keys, s.prefix+token)
+	}
+	keys = append(keys, userKey)
+
+	return s.client.Del(ctx, keys...).Err()
+}
+
+func (s *SessionStore) userSessions(ctx context.Context, userID string) ([]userSession, error) {
+	if userID == "" {
+		return nil, nil
+	}
+
+	userKey := s.userPrefix + userID
+	tokens, err := s.client.SMembers(ctx, userKey).Result()
+	if err != nil {
+		return nil, err
+	}
+	if len(tokens) == 0 {
+		return nil, nil
+	}
+
+	keys := make([]string, 0, len(tokens))
+	for _, token := range tokens {
+		keys = append(keys, s.prefix+token)
+	}
+	values, err := s.client.MGet(ctx, keys...).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	entries := make([]userSession, 0, len(tokens))
+	stale := make([]any, 0)
+	for i, value := range values {
+		raw, ok := value.(string)
+		if !ok {
+			stale = append(stale, tokens[i])
+			continue
+		}
+		session, err := decodeSession(raw)
+		if err != nil || (!session.ExpiresAt.IsZero() && now.After(session.ExpiresAt)) {
+			stale = append(stale, tokens[i])
+			continue
+		}
+		entries = append(entries, userSession{token: tokens[i], session: session})
+	}
+
+	if len(stale) > 0 {
+		_ = s.client.SRem(ctx, userKey, stale...).Err()
+	}
+
+	return entries, nil
+}
+
+// extendUserIndex garante que o indice de sessoes do usuario viva pelo menos
+// tanto quanto a sessao mais longa, sem encurtar o TTL atual.
+func (s *SessionStore) extendUserIndex(ctx context.Context, userKey string, ttl time.Duration) error {
+	current, err := s.client.TTL(ctx, userKey).Result()
+	if err != nil {
+		return err
+	}
+	if current >= ttl {
+		return nil
+	}
+	return s.client.Expire(ctx, userKey, ttl).Err()
+}
+
+func decodeSession(value string) (ports.Session, error) {
+	var session ports.Session
+	if err := json.Unmarshal([]byte(value), &session); err != nil {
+		return ports.Session{}, err
+	}
+	return session, nil
 }
 
 func generateToken() (string, error) {
@@ -90,3 +294,12 @@ func generateToken() (string, error) {
 
 	return base64.RawURLEncoding.EncodeToString(buf), nil
 }
+
+func generateSessionID() (string, error) {
+	buf := make([]byte, 12)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+
+	return base64.RawURLEncoding.EncodeToString(buf), nil
+}
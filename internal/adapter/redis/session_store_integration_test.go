@@ -120,3 +120,142 @@ func TestSessionStoreIntegrationEmptyToken(t *testing.T) {
 		t.Fatalf("expected not found for empty token, got %v", err)
 	}
 }
+
+// Testa a remocao de todas as sessoes de um usuario.
+func TestSessionStoreIntegrationDeleteByUser(t *testing.T) {
+	client := integrationRedisClient(t)
+	store := NewSessionStore(client)
+	ctx := context.Background()
+
+	session := ports.Session{
+		UserID:    "user-3",
+		Role:      domain.RoleOperator,
+		ExpiresAt: time.Now().Add(1 * time.Minute),
+	}
+	first, err := store.Create(ctx, session)
+	if err != nil {
+		t.Fatalf("create first session: %v", err)
+	}
+	second, err := store.Create(ctx, session)
+	if err != nil {
+		t.Fatalf("create second session: %v", err)
+	}
+	other, err := store.Create(ctx, ports.Session{UserID: "user-4", Role: domain.RoleAdmin, ExpiresAt: time.Now().Add(1 * time.Minute)})
+	if err != nil {
+		t.Fatalf("create other session: %v", err)
+	}
+
+	if err := store.DeleteByUser(ctx, session.UserID); err != nil {
+		t.Fatalf("delete by user: %v", err)
+	}
+	for _, token := range []string{first, second} {
+		if _, err := store.Get(ctx, token); err != ports.ErrNotFound {
+			t.Fatalf("expected not found after delete by user, got %v", err)
+		}
+	}
+	if _, err := store.Get(ctx, other); err != nil {
+		t.Fatalf("expected other user session to remain, got %v", err)
+	}
+}
+
+// Testa listagem, atualizacao de atividade e remocao por ID das sessoes do usuario.
+func TestSessionStoreIntegrationListByUser(t *testing.T) {
+	client := integrationRedisClient(t)
+	store := NewSessionStore(client)
+	ctx := context.Background()
+
+	session := ports.Session{
+		UserID:    "user-5",
+		Role:      domain.RoleOperator,
+		IP:        "10.0.0.1",
+		UserAgent: "test-agent",
+		ExpiresAt: time.Now().Add(1 * time.Minute),
+	}
+	first, err := store.Create(ctx, session)
+	if err != nil {
+		t.Fatalf("create first session: %v", err)
+	}
+	second, err := store.Create(ctx, session)
+	if err != nil {
+		t.Fatalf("create second session: %v", err)
+	}
+
+	seenAt := time.Now().Add(30 * time.Second)
+	if err := store.Touch(ctx, second, seenAt, time.Time{}); err != nil {
+		t.Fatalf("touch session: %v", err)
+	}
+
+	sessions, err := store.ListByUser(ctx, session.UserID)
+	if err != nil {
+		t.Fatalf("list sessions: %v", err)
+	}
+	if len(sessions) != 2 {
+		t.Fatalf("expected 2 sessions, got %d", len(sessions))
+	}
+	if sessions[0].LastSeenAt.Unix() != seenAt.Unix() {
+		t.Fatalf("expected most recent session first, got %v", sessions[0].LastSeenAt)
+	}
+	if sessions[0].ID == "" || sessions[0].ID == sessions[1].ID {
+		t.Fatalf("expected distinct session ids, got %q and %q", sessions[0].ID, sessions[1].ID)
+	}
+	if sessions[1].IP != "10.0.0.1" || sessions[1].UserAgent != "test-agent" {
+		t.Fatalf("expected request info to be stored, got %#v", sessions[1])
+	}
+
+	if err := store.DeleteByID(ctx, "user-other", sessions[0].ID); err != ports.ErrNotFound {
+		t.Fatalf("expected not found for another user, got %v", err)
+	}
+	if err := store.DeleteByID(ctx, session.UserID, sessions[0].ID); err != nil {
+		t.Fatalf("delete by id: %v", err)
+	}
+	if _, err := store.Get(ctx, second); err != ports.ErrNotFound {
+		t.Fatalf("expected not found after delete by id, got %v", err)
+	}
+	if _, err := store.Get(ctx, first); err != nil {
+		t.Fatalf("expected remaining session, got %v", err)
+	}
+}
+
+// Testa que Touch estende o TTL da sessao quando recebe nova expiracao.
+func TestSessionStoreIntegrationTouchExtendsTTL(t *testing.T) {
+	client := integrationRedisClient(t)
+	store := NewSessionStore(client)
+	ctx := context.Background()
+
+	token, err := store.Create(ctx, ports.Session{
+		UserID:    "user-6",
+		Role:      domain.RoleOperator,
+		ExpiresAt: time.Now().Add(1 * time.Minute),
+	})
+	if err != nil {
+		t.Fatalf("create session: %v", err)
+	}
+
+	expiresAt := time.Now().Add(10 * time.Minute)
+	if err := store.Touch(ctx, token, time.Now(), expiresAt); err != nil {
+		t.Fatalf("touch session: %v", err)
+	}
+
+	ttl, err := client.TTL(ctx, "session:"+token).Result()
+	if err != nil {
+		t.Fatalf("ttl session: %v", err)
+	}
+	if ttl < 9*time.Minute {
+		t.Fatalf("expected ttl extended, got %v", ttl)
+	}
+	indexTTL, err := client.TTL(ctx, "user_sessions:user-6").Result()
+	if err != nil {
+		t.Fatalf("ttl index: %v", err)
+	}
+	if indexTTL < 9*time.Minute {
+		t.Fatalf("expected index ttl extended, got %v", indexTTL)
+	}
+
+	loaded, err := store.Get(ctx, token)
+	if err != nil {
+		t.Fatalf("get session: %v", err)
+	}
+	if loaded.ExpiresAt.Unix() != expiresAt.Unix() {
+		t.Fatalf("expected expires at %v, got %v", expiresAt, loaded.ExpiresAt)
+	}
+}
@@ -9,8 +9,11 @@ import (
 	"github.com/PabloPavan/eventrail/sse"
 	"github.com/PabloPavan/jaiu/imagekit"
 	kitconfig "github.com/PabloPavan/jaiu/imagekit/config"
+	"github.com/PabloPavan/jaiu/internal/adapter/memory"
+	"github.com/PabloPavan/jaiu/internal/adapter/notifier"
 	"github.com/PabloPavan/jaiu/internal/adapter/postgres"
 	redisadapter "github.com/PabloPavan/jaiu/internal/adapter/redis"
+	"github.com/PabloPavan/jaiu/internal/domain"
 	"github.com/PabloPavan/jaiu/internal/http/handlers"
 	httpmw "github.com/PabloPavan/jaiu/internal/http/middleware"
 	"github.com/PabloPavan/jaiu/internal/http/router"
@@ -29,7 +32,12 @@ type Config struct {
 	ImageUploadDir    string
 	SessionCookieName string
 	SessionTTL        time.Duration
+	SessionIdleTTL    time.Duration
 	SessionSecure     bool
+	BaseURL           string
+	NotificationFile  string
+	MFARequireAdmin   bool
+	StudentViewWindow time.Duration
 	Context           context.Context
 }
 
@@ -80,12 +88,23 @@ func New(cfg Config) (*App, error) {
 	var planService handlers.PlanService
 	var studentService handlers.StudentService
 	var subscriptionService handlers.SubscriptionService
+	var couponService handlers.CouponService
 	var paymentService handlers.PaymentService
+	var reportService handlers.ReportService
+	var dashboardService handlers.DashboardService
+	var authorizationService handlers.AuthorizationService
+	var userService handlers.UserService
+	var sessionService handlers.SessionService
+	var passwordService handlers.PasswordService
+	var mfaService handlers.MFAService
+	var auditService handlers.AuditService
+	var apiTokenService handlers.APITokenService
 	var sessionStore ports.SessionStore
 	sessionConfig := handlers.SessionConfig{
-		CookieName: cfg.SessionCookieName,
-		TTL:        cfg.SessionTTL,
-		Secure:     cfg.SessionSecure,
+		CookieName:  cfg.SessionCookieName,
+		TTL:         cfg.SessionTTL,
+		IdleTimeout: cfg.SessionIdleTTL,
+		Secure:      cfg.SessionSecure,
 	}
 	if sessionConfig.CookieName == "" {
 		sessionConfig.CookieName = "jaiu_session"
@@ -93,6 +112,9 @@ func New(cfg Config) (*App, error) {
 	if sessionConfig.TTL == 0 {
 		sessionConfig.TTL = 24 * time.Hour
 	}
+	if sessionConfig.IdleTimeout == 0 {
+		sessionConfig.IdleTimeout = 30 * time.Minute
+	}
 	if sessionConfig.SameSite == 0 {
 		sessionConfig.SameSite = http.SameSiteLaxMode
 	}
@@ -124,29 +146,84 @@ func New(cfg Config) (*App, error) {
 		return nil, fmt.Errorf("init imagekit: %w", err)
 	}
 
+	var loginAttempts ports.LoginAttemptStore = memory.NewLoginAttemptStore()
+	var loginChallenges ports.LoginChallengeStore = memory.NewLoginChallengeStore()
+	var accessDedup ports.AccessDedupStore = memory.NewAccessDedupStore()
+	if redisClient != nil {
+		sessionStore = redisadapter.NewSessionStore(redisClient)
+		loginAttempts = redisadapter.NewLoginAttemptStore(redisClient)
+		loginChallenges = redisadapter.NewLoginChallengeStore(redisClient)
+		accessDedup = redisadapter.NewAccessDedupStore(redisClient)
+	}
+
 	if pool != nil {
 		imageKit.EnableOutbox(pool)
 		userRepo := postgres.NewUserRepository(pool)
 		auditRepo := postgres.NewAuditRepository(pool)
-		authService = service.NewAuthService(userRepo, auditRepo)
+		auth := service.NewAuthService(userRepo, auditRepo)
+		loginLimiter := service.NewLoginLimiter(loginAttempts, service.DefaultLoginLimitPolicy())
+		auth.SetLoginLimiter(loginLimiter)
+		authService = auth
+		authorizationService = service.NewAuthorizationService(auditRepo)
+		auditService = service.NewAuditService(auditRepo)
+		txRunner := postgres.NewTxRunner(pool)
+		users := service.NewUserService(userRepo, sessionStore, auditRepo)
+		users.SetTxRunner(txRunner)
+		userService = users
+		sessionService = service.NewSessionService(sessionStore, auditRepo)
+		apiTokenService = service.NewAPITokenService(postgres.NewAPITokenRepository(pool), userRepo, auditRepo)
+
+		var passwordNotifier ports.Notifier = notifier.NewLogNotifier()
+		if cfg.NotificationFile != "" {
+			passwordNotifier = notifier.NewFileNotifier(cfg.NotificationFile)
+		}
+		baseURL := cfg.BaseURL
+		if baseURL == "" {
+			baseURL = "http://localhost:8080"
+		}
+		passwordService = service.NewPasswordService(userRepo, postgres.NewPasswordResetRepository(pool), sessionStore, passwordNotifier, auditRepo, baseURL)
+
+		mfa := service.NewMFAService(userRepo, postgres.NewMFARepository(pool), loginChallenges, auditRepo, "Jaiu")
+		if cfg.MFARequireAdmin {
+			mfa.SetPolicy(service.MFAPolicy{RequiredRoles: []domain.UserRole{domain.RoleAdmin}})
+		}
+		mfa.SetLoginLimiter(loginLimiter)
+		auth.SetMFA(mfa)
+		mfaService = mfa
 
 		planRepo := postgres.NewPlanRepository(pool)
 		studentRepo := postgres.NewStudentRepository(pool)
 		subscriptionRepo := postgres.NewSubscriptionRepository(pool)
-		planService = service.NewPlanService(planRepo, subscriptionRepo, auditRepo)
-		studentService = service.NewStudentService(studentRepo, subscriptionRepo, auditRepo)
-		subscriptionService = service.NewSubscriptionService(subscriptionRepo, planRepo, studentRepo, auditRepo)
-
-		paymentRepo := postgres.NewPaymentRepository(pool)
+		plans := service.NewPlanService(planRepo, subscriptionRepo, auditRepo)
+		plans.SetTxRunner(txRunner)
+		planService = plans
+		students := service.NewStudentService(studentRepo, subscriptionRepo, auditRepo)
+		students.SetViewDedup(accessDedup, cfg.StudentViewWindow)
+		students.SetTxRunner(txRunner)
+		studentService = students
+		couponRepo := postgres.NewCouponRepository(pool)
+		couponService = service.NewCouponService(couponRepo, auditRepo)
 		periodRepo := postgres.NewBillingPeriodRepository(pool)
 		balanceRepo := postgres.NewSubscriptionBalanceRepository(pool)
 		allocationRepo := postgres.NewPaymentAllocationRepository(pool)
+		transferRepo := postgres.NewPaymentCreditTransferRepository(pool)
+		subscriptions := service.NewSubscriptionService(subscriptionRepo, planRepo, studentRepo, auditRepo)
+		subscriptions.SetCoupons(couponRepo)
+		subscriptions.SetBilling(periodRepo, balanceRepo)
+		subscriptions.SetPaymentLedger(allocationRepo, transferRepo)
+		subscriptions.SetFreezes(postgres.NewSubscriptionFreezeRepository(pool))
+		subscriptions.SetTxRunner(txRunner)
+		subscriptionService = subscriptions
+
+		paymentRepo := postgres.NewPaymentRepository(pool)
 		paymentTx := postgres.NewPaymentTxRunner(pool)
-		paymentService = service.NewPaymentService(paymentRepo, subscriptionRepo, planRepo, periodRepo, balanceRepo, allocationRepo, auditRepo, paymentTx)
-	}
+		payments := service.NewPaymentService(paymentRepo, subscriptionRepo, planRepo, periodRepo, balanceRepo, allocationRepo, auditRepo, paymentTx)
+		payments.SetCreditTransfers(transferRepo)
+		paymentService = payments
 
-	if redisClient != nil {
-		sessionStore = redisadapter.NewSessionStore(redisClient)
+		reportRepo := postgres.NewReportRepository(pool)
+		reportService = service.NewReportService(reportRepo)
+		dashboardService = service.NewDashboardService(postgres.NewDashboardRepository(pool), reportRepo)
 	}
 
 	h := handlers.New(handlers.Services{
@@ -154,7 +231,17 @@ func New(cfg Config) (*App, error) {
 		Plans:         planService,
 		Students:      studentService,
 		Subscriptions: subscriptionService,
+		Coupons:       couponService,
 		Payments:      paymentService,
+		Reports:       reportService,
+		Dashboard:     dashboardService,
+		Authorization: authorizationService,
+		Users:         userService,
+		Sessions:      sessionService,
+		Passwords:     passwordService,
+		MFA:           mfaService,
+		Audit:         auditService,
+		APITokens:     apiTokenService,
 	}, sessionStore, sessionConfig)
 	h.SetImageConfig(handlers.ImageConfig{
 		ImageService: imageKit,
@@ -175,7 +262,7 @@ func New(cfg Config) (*App, error) {
 	}
 
 	return &App{
-		Router:      router.New(h, sessionStore, sessionConfig.CookieName, notifyCfg, eventHandler),
+		Router:      router.New(h, sessionStore, h.SessionPolicy(), notifyCfg, eventHandler),
 		DB:          pool,
 		Redis:       redisClient,
 		ImageKit:    imageKit,
@@ -2,10 +2,13 @@ package auditctx
 
 import "context"
 
+// Actor e quem executa a acao. TokenID so e preenchido quando a requisicao foi
+// autenticada por token de API.
 type Actor struct {
-	ID    string
-	Role  string
-	Email string
+	ID      string
+	Role    string
+	Email   string
+	TokenID string
 }
 
 type RequestInfo struct {
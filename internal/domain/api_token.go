@@ -0,0 +1,103 @@
+package domain
+
+import (
+	"strings"
+	"time"
+)
+
+// APIResource agrupa as rotas da API para fins de escopo dos tokens.
+type APIResource string
+
+const (
+	APIResourceStudents      APIResource = "students"
+	APIResourcePlans         APIResource = "plans"
+	APIResourceSubscriptions APIResource = "subscriptions"
+	APIResourcePayments      APIResource = "payments"
+	APIResourceReports       APIResource = "reports"
+)
+
+// APIResources lista os recursos na ordem exibida na tela de tokens.
+var APIResources = []APIResource{
+	APIResourceStudents,
+	APIResourcePlans,
+	APIResourceSubscriptions,
+	APIResourcePayments,
+	APIResourceReports,
+}
+
+func (r APIResource) IsValid() bool {
+	for _, resource := range APIResources {
+		if resource == r {
+			return true
+		}
+	}
+	return false
+}
+
+type APIAccess string
+
+const (
+	APIAccessRead  APIAccess = "read"
+	APIAccessWrite APIAccess = "write"
+)
+
+func (a APIAccess) IsValid() bool {
+	return a == APIAccessRead || a == APIAccessWrite
+}
+
+// APIScope e gravado como "recurso:acesso", por exemplo "payments:write".
+type APIScope string
+
+func NewAPIScope(resource APIResource, access APIAccess) APIScope {
+	return APIScope(string(resource) + ":" + string(access))
+}
+
+func (s APIScope) Parts() (APIResource, APIAccess) {
+	resource, access, _ := strings.Cut(string(s), ":")
+	return APIResource(resource), APIAccess(access)
+}
+
+func (s APIScope) IsValid() bool {
+	resource, access := s.Parts()
+	return resource.IsValid() && access.IsValid()
+}
+
+// APIToken da acesso a API em nome de um usuario. Apenas o hash do segredo e
+// guardado; o prefixo serve para identificar o token na listagem.
+type APIToken struct {
+	ID         string
+	UserID     string
+	UserName   string
+	UserEmail  string
+	Name       string
+	Prefix     string
+	TokenHash  string
+	Scopes     []APIScope
+	ExpiresAt  *time.Time
+	LastUsedAt *time.Time
+	RevokedAt  *time.Time
+	CreatedBy  string
+	CreatedAt  time.Time
+}
+
+// Allows indica se o token pode acessar o recurso. Escrita inclui leitura.
+func (t APIToken) Allows(resource APIResource, access APIAccess) bool {
+	for _, scope := range t.Scopes {
+		scopeResource, scopeAccess := scope.Parts()
+		if scopeResource != resource {
+			continue
+		}
+		if scopeAccess == access || scopeAccess == APIAccessWrite {
+			return true
+		}
+	}
+	return false
+}
+
+func (t APIToken) Revoked() bool {
+	return t.RevokedAt != nil
+}
+
+func (t APIToken) Expired(now time.Time) bool {
+	return t.ExpiresAt != nil && !now.Before(*t.ExpiresAt)
+}
@@ -13,4 +13,7 @@ type AuditEvent struct {
 	IP         string
 	UserAgent  string
 	CreatedAt  time.Time
+	Seq        int64
+	PrevHash   string
+	Hash       string
 }
@@ -3,13 +3,78 @@ package domain
 import "time"
 
 type BillingPeriod struct {
-	ID              string
-	SubscriptionID  string
-	PeriodStart     time.Time
-	PeriodEnd       time.Time
+	ID             string
+	SubscriptionID string
+	PeriodStart    time.Time
+	PeriodEnd      time.Time
+	// AmountDueCents e o valor ja com desconto; DiscountCents guarda o
+	// desconto aplicado na geracao da competencia.
 	AmountDueCents  int64
+	DiscountCents   int64
 	AmountPaidCents int64
 	Status          BillingPeriodStatus
-	CreatedAt       time.Time
-	UpdatedAt       time.Time
+	// FeeCents soma a multa e os juros lancados apos o vencimento. Fica
+	// separado de AmountDueCents; os pagamentos quitam primeiro os encargos.
+	FeeCents int64
+	// FeeAccruedOn e o ultimo dia com juros lancados. Nil enquanto a multa
+	// nao foi aplicada.
+	FeeAccruedOn *time.Time
+	// InterestCentDays acumula o valor em aberto vezes os dias em atraso ja
+	// lancados; os juros sao calculados sobre o total para nao arredondar
+	// cada dia.
+	InterestCentDays int64
+	// FeeWaivedAt marca a dispensa dos encargos; depois dela nada mais e
+	// lancado na competencia.
+	FeeWaivedAt *time.Time
+	// ShiftDays sao os dias que trancamentos anteriores empurraram o inicio
+	// da competencia; FrozenDays sao os dias trancados dentro dela, que
+	// adiam o vencimento e o fim.
+	ShiftDays  int
+	FrozenDays int
+	// DueDate e o vencimento calculado pela cobranca a cada gravacao, ja com
+	// os dias deslocados e trancados.
+	DueDate time.Time
+	// Installment e a parcela desta competencia dentro do ciclo do plano, de
+	// 1 ate Installments. Planos sem parcelamento usam 1 de 1.
+	Installment  int
+	Installments int
+	CreatedAt    time.Time
+	UpdatedAt    time.Time
+}
+
+// GrossCents e o valor da competencia antes do desconto.
+func (p BillingPeriod) GrossCents() int64 {
+	return p.AmountDueCents + p.DiscountCents
+}
+
+// TotalDueCents e o valor da competencia somado aos encargos.
+func (p BillingPeriod) TotalDueCents() int64 {
+	return p.AmountDueCents + p.FeeCents
+}
+
+// OutstandingCents e o que falta pagar, encargos incluidos.
+func (p BillingPeriod) OutstandingCents() int64 {
+	outstanding := p.TotalDueCents() - p.AmountPaidCents
+	if outstanding < 0 {
+		return 0
+	}
+	return outstanding
+}
+
+// FeePaidCents e a parte de AmountPaidCents que quitou encargos.
+func (p BillingPeriod) FeePaidCents() int64 {
+	if p.AmountPaidCents < p.FeeCents {
+		return p.AmountPaidCents
+	}
+	return p.FeeCents
+}
+
+// PrincipalOutstandingCents e o valor da competencia ainda em aberto, sem
+// os encargos. E a base dos juros.
+func (p BillingPeriod) PrincipalOutstandingCents() int64 {
+	outstanding := p.AmountDueCents - (p.AmountPaidCents - p.FeePaidCents())
+	if outstanding < 0 {
+		return 0
+	}
+	return outstanding
 }
@@ -0,0 +1,86 @@
+package domain
+
+import "time"
+
+// Discount descreve um desconto sobre o valor de cada competencia. Value e
+// em centesimos de ponto percentual para DiscountPercent (1000 = 10%) e em
+// centavos para DiscountFixed.
+type Discount struct {
+	Kind     DiscountKind
+	Value    int64
+	Duration DiscountDuration
+	// Periods e o numero de competencias com desconto quando Duration e
+	// DiscountRepeating.
+	Periods int
+}
+
+// IsZero indica ausencia de desconto.
+func (d Discount) IsZero() bool {
+	return d.Kind == "" || d.Value <= 0
+}
+
+// AppliesTo indica se a competencia de indice index (0 para a primeira)
+// recebe o desconto.
+func (d Discount) AppliesTo(index int) bool {
+	if d.IsZero() || index < 0 {
+		return false
+	}
+	switch d.Duration {
+	case DiscountOnce:
+		return index == 0
+	case DiscountRepeating:
+		return index < d.Periods
+	case DiscountForever:
+		return true
+	default:
+		return false
+	}
+}
+
+// Remaining devolve o desconto que sobra depois de applied competencias com
+// desconto, para ser levado a outra assinatura. O resultado e zero quando nada
+// mais seria descontado.
+func (d Discount) Remaining(applied int) Discount {
+	if d.IsZero() || applied <= 0 {
+		return d
+	}
+	switch d.Duration {
+	case DiscountRepeating:
+		if applied < d.Periods {
+			d.Periods -= applied
+			return d
+		}
+	case DiscountForever:
+		return d
+	}
+	return Discount{}
+}
+
+// AmountCents calcula o desconto sobre priceCents, arredondando para o
+// centavo mais proximo e sem passar do proprio valor.
+func (d Discount) AmountCents(priceCents int64) int64 {
+	if d.IsZero() || priceCents <= 0 {
+		return 0
+	}
+	amount := d.Value
+	if d.Kind == DiscountPercent {
+		amount = (priceCents*d.Value + MaxBasisPoints/2) / MaxBasisPoints
+	}
+	if amount > priceCents {
+		return priceCents
+	}
+	return amount
+}
+
+// Coupon e um desconto reutilizavel identificado por um codigo. As
+// assinaturas copiam o desconto do cupom ao aplica-lo, entao desativar o
+// cupom nao muda as assinaturas que ja o usam.
+type Coupon struct {
+	ID          string
+	Code        string
+	Description string
+	Discount    Discount
+	Active      bool
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+}
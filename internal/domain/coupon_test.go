@@ -0,0 +1,60 @@
+package domain
+
+import "testing"
+
+// Testa em quais competencias cada duracao de desconto vale.
+func TestDiscountAppliesTo(t *testing.T) {
+	tests := []struct {
+		name     string
+		discount Discount
+		index    int
+		want     bool
+	}{
+		{"once-first", Discount{Kind: DiscountFixed, Value: 100, Duration: DiscountOnce}, 0, true},
+		{"once-second", Discount{Kind: DiscountFixed, Value: 100, Duration: DiscountOnce}, 1, false},
+		{"repeating-inside", Discount{Kind: DiscountPercent, Value: 1000, Duration: DiscountRepeating, Periods: 3}, 2, true},
+		{"repeating-after", Discount{Kind: DiscountPercent, Value: 1000, Duration: DiscountRepeating, Periods: 3}, 3, false},
+		{"forever", Discount{Kind: DiscountPercent, Value: 1000, Duration: DiscountForever}, 40, true},
+		{"zero", Discount{}, 0, false},
+	}
+
+	for _, tt := range tests {
+		if got := tt.discount.AppliesTo(tt.index); got != tt.want {
+			t.Fatalf("%s: expected %v, got %v", tt.name, tt.want, got)
+		}
+	}
+}
+
+// Testa o calculo do desconto percentual e fixo.
+func TestDiscountAmountCents(t *testing.T) {
+	percent := Discount{Kind: DiscountPercent, Value: 1250, Duration: DiscountForever}
+	if got := percent.AmountCents(14990); got != 1874 {
+		t.Fatalf("expected 1874, got %d", got)
+	}
+	fixed := Discount{Kind: DiscountFixed, Value: 5000, Duration: DiscountOnce}
+	if got := fixed.AmountCents(3000); got != 3000 {
+		t.Fatalf("expected discount capped at the price, got %d", got)
+	}
+}
+
+// Testa o desconto que sobra depois das competencias ja descontadas.
+func TestDiscountRemaining(t *testing.T) {
+	once := Discount{Kind: DiscountFixed, Value: 100, Duration: DiscountOnce}
+	if got := once.Remaining(0); got != once {
+		t.Fatalf("expected unused discount to be kept, got %#v", got)
+	}
+	if got := once.Remaining(1); !got.IsZero() {
+		t.Fatalf("expected used once discount to end, got %#v", got)
+	}
+	repeating := Discount{Kind: DiscountPercent, Value: 1000, Duration: DiscountRepeating, Periods: 3}
+	if got := repeating.Remaining(1); got.Periods != 2 || got.Value != 1000 {
+		t.Fatalf("expected 2 periods left, got %#v", got)
+	}
+	if got := repeating.Remaining(3); !got.IsZero() {
+		t.Fatalf("expected repeating discount to end, got %#v", got)
+	}
+	forever := Discount{Kind: DiscountPercent, Value: 1000, Duration: DiscountForever}
+	if got := forever.Remaining(12); got != forever {
+		t.Fatalf("expected forever discount to be kept, got %#v", got)
+	}
+}
@@ -0,0 +1,18 @@
+package domain
+
+import "time"
+
+// UserMFA guarda o segredo TOTP do usuario. O segundo fator so passa a valer
+// depois que a inscricao e confirmada com um codigo valido.
+type UserMFA struct {
+	UserID       string
+	Secret       string
+	EnabledAt    *time.Time
+	LastUsedStep int64
+	CreatedAt    time.Time
+	UpdatedAt    time.Time
+}
+
+func (m UserMFA) Enabled() bool {
+	return m.EnabledAt != nil
+}
@@ -8,3 +8,15 @@ type PaymentAllocation struct {
 	AmountCents     int64
 	CreatedAt       time.Time
 }
+
+// PaymentCreditTransfer registra o valor de um pagamento levado para outra
+// assinatura numa troca de plano. AmountCents e o total levado; CreditCents e
+// a parte que entrou no saldo da assinatura, o resto virou alocacoes do
+// pagamento nas competencias dela.
+type PaymentCreditTransfer struct {
+	PaymentID      string
+	SubscriptionID string
+	AmountCents    int64
+	CreditCents    int64
+	CreatedAt      time.Time
+}
@@ -0,0 +1,47 @@
+package domain
+
+import "strings"
+
+type Permission string
+
+const (
+	PermissionPlanCreate      Permission = "plan.create"
+	PermissionPlanUpdate      Permission = "plan.update"
+	PermissionPlanDelete      Permission = "plan.delete"
+	PermissionPaymentReverse  Permission = "payment.reverse"
+	PermissionUserManage      Permission = "user.manage"
+	PermissionAuditView       Permission = "audit.view"
+	PermissionAPITokenManage  Permission = "api_token.manage"
+	PermissionBillingFeeWaive Permission = "billing_period.fee_waive"
+	PermissionCouponManage    Permission = "coupon.manage"
+)
+
+var permissionMatrix = map[Permission][]UserRole{
+	PermissionPlanCreate:      {RoleAdmin},
+	PermissionPlanUpdate:      {RoleAdmin},
+	PermissionPlanDelete:      {RoleAdmin},
+	PermissionPaymentReverse:  {RoleAdmin},
+	PermissionUserManage:      {RoleAdmin},
+	PermissionAuditView:       {RoleAdmin},
+	PermissionAPITokenManage:  {RoleAdmin},
+	PermissionBillingFeeWaive: {RoleAdmin},
+	PermissionCouponManage:    {RoleAdmin},
+}
+
+func (p Permission) EntityType() string {
+	entity, _, _ := strings.Cut(string(p), ".")
+	return entity
+}
+
+func (r UserRole) Can(permission Permission) bool {
+	roles, ok := permissionMatrix[permission]
+	if !ok {
+		return r == RoleAdmin
+	}
+	for _, role := range roles {
+		if role == r {
+			return true
+		}
+	}
+	return false
+}
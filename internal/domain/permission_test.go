@@ -0,0 +1,47 @@
+package domain
+
+import "testing"
+
+// Testa a matriz de permissoes por papel.
+func TestUserRoleCan(t *testing.T) {
+	tests := []struct {
+		name       string
+		role       UserRole
+		permission Permission
+		want       bool
+	}{
+		{"admin-plan-create", RoleAdmin, PermissionPlanCreate, true},
+		{"operator-plan-create", RoleOperator, PermissionPlanCreate, false},
+		{"operator-plan-delete", RoleOperator, PermissionPlanDelete, false},
+		{"admin-payment-reverse", RoleAdmin, PermissionPaymentReverse, true},
+		{"operator-payment-reverse", RoleOperator, PermissionPaymentReverse, false},
+		{"operator-user-manage", RoleOperator, PermissionUserManage, false},
+		{"admin-audit-view", RoleAdmin, PermissionAuditView, true},
+		{"operator-audit-view", RoleOperator, PermissionAuditView, false},
+		{"admin-api-token-manage", RoleAdmin, PermissionAPITokenManage, true},
+		{"operator-api-token-manage", RoleOperator, PermissionAPITokenManage, false},
+		{"admin-billing-fee-waive", RoleAdmin, PermissionBillingFeeWaive, true},
+		{"operator-billing-fee-waive", RoleOperator, PermissionBillingFeeWaive, false},
+		{"admin-coupon-manage", RoleAdmin, PermissionCouponManage, true},
+		{"operator-coupon-manage", RoleOperator, PermissionCouponManage, false},
+		{"admin-unknown", RoleAdmin, Permission("unknown.action"), true},
+		{"operator-unknown", RoleOperator, Permission("unknown.action"), false},
+		{"invalid-role", UserRole("guest"), PermissionPlanUpdate, false},
+	}
+
+	for _, tt := range tests {
+		if got := tt.role.Can(tt.permission); got != tt.want {
+			t.Fatalf("%s: expected %v, got %v", tt.name, tt.want, got)
+		}
+	}
+}
+
+// Testa a extracao do tipo de entidade da permissao.
+func TestPermissionEntityType(t *testing.T) {
+	if got := PermissionPaymentReverse.EntityType(); got != "payment" {
+		t.Fatalf("expected payment, got %q", got)
+	}
+	if got := Permission("plain").EntityType(); got != "plain" {
+		t.Fatalf("expected plain, got %q", got)
+	}
+}
@@ -2,6 +2,9 @@ package domain
 
 import "time"
 
+// MaxBasisPoints equivale a 100%.
+const MaxBasisPoints = 10000
+
 type Plan struct {
 	ID           string
 	Name         string
@@ -9,6 +12,48 @@ type Plan struct {
 	PriceCents   int64
 	Active       bool
 	Description  string
+	// LateFeeBasisPoints e a multa por atraso sobre o valor da competencia,
+	// em centesimos de ponto percentual (200 = 2%).
+	LateFeeBasisPoints int
+	// LateInterestBasisPoints sao os juros de mora ao mes, cobrados por dia
+	// de atraso sobre o valor em aberto (100 = 1% ao mes).
+	LateInterestBasisPoints int
+	// MaxFreezeDays limita os dias de trancamento por ano civil. Zero
+	// desabilita o trancamento.
+	MaxFreezeDays int
+	// Installments divide o preco de cada ciclo do plano em parcelas
+	// mensais. Zero ou um cobra o ciclo inteiro de uma vez.
+	Installments int
 	CreatedAt    time.Time
 	UpdatedAt    time.Time
 }
+
+// MaxInstallments limita o parcelamento a uma parcela por mes de um plano
+// anual.
+const MaxInstallments = 12
+
+// InstallmentCount e o numero de parcelas de cada ciclo, no minimo uma.
+func (p Plan) InstallmentCount() int {
+	if p.Installments < 1 {
+		return 1
+	}
+	return p.Installments
+}
+
+// MaxInstallmentsFor e o maior parcelamento que cabe na duracao: uma parcela
+// a cada 30 dias, sem passar de MaxInstallments.
+func MaxInstallmentsFor(durationDays int) int {
+	limit := durationDays / 30
+	if limit < 1 {
+		return 1
+	}
+	if limit > MaxInstallments {
+		return MaxInstallments
+	}
+	return limit
+}
+
+// ChargesLateFees indica se o plano cobra multa ou juros por atraso.
+func (p Plan) ChargesLateFees() bool {
+	return p.LateFeeBasisPoints > 0 || p.LateInterestBasisPoints > 0
+}
@@ -14,6 +14,12 @@ type BillingPeriodStatus string
 
 type UserRole string
 
+type ReportPeriod string
+
+type DiscountKind string
+
+type DiscountDuration string
+
 const (
 	StudentActive    StudentStatus = "active"
 	StudentInactive  StudentStatus = "inactive"
@@ -59,6 +65,37 @@ const (
 	RoleOperator UserRole = "operator"
 )
 
+const (
+	ReportDaily   ReportPeriod = "daily"
+	ReportWeekly  ReportPeriod = "weekly"
+	ReportMonthly ReportPeriod = "monthly"
+)
+
+const (
+	DiscountPercent DiscountKind = "percent"
+	DiscountFixed   DiscountKind = "fixed"
+)
+
+const (
+	DiscountOnce      DiscountDuration = "once"
+	DiscountRepeating DiscountDuration = "repeating"
+	DiscountForever   DiscountDuration = "forever"
+)
+
+// Listas com todos os valores de cada enum, usadas para documentar a API.
+var (
+	StudentStatuses       = []StudentStatus{StudentActive, StudentInactive, StudentSuspended}
+	SubscriptionStatuses  = []SubscriptionStatus{SubscriptionActive, SubscriptionEnded, SubscriptionCanceled, SubscriptionSuspended}
+	PaymentStatuses       = []PaymentStatus{PaymentConfirmed, PaymentReversed}
+	PaymentKinds          = []PaymentKind{PaymentFull, PaymentPartial, PaymentAdvance, PaymentCredit}
+	PaymentMethods        = []PaymentMethod{PaymentCash, PaymentPix, PaymentCard, PaymentTransfer, PaymentOther}
+	BillingPeriodStatuses = []BillingPeriodStatus{BillingOpen, BillingPaid, BillingPartial, BillingOverdue}
+	UserRoles             = []UserRole{RoleAdmin, RoleOperator}
+	ReportPeriods         = []ReportPeriod{ReportDaily, ReportWeekly, ReportMonthly}
+	DiscountKinds         = []DiscountKind{DiscountPercent, DiscountFixed}
+	DiscountDurations     = []DiscountDuration{DiscountOnce, DiscountRepeating, DiscountForever}
+)
+
 func (s StudentStatus) IsValid() bool {
 	switch s {
 	case StudentActive, StudentInactive, StudentSuspended:
@@ -121,3 +158,30 @@ func (s UserRole) IsValid() bool {
 		return false
 	}
 }
+
+func (s ReportPeriod) IsValid() bool {
+	switch s {
+	case ReportDaily, ReportWeekly, ReportMonthly:
+		return true
+	default:
+		return false
+	}
+}
+
+func (s DiscountKind) IsValid() bool {
+	switch s {
+	case DiscountPercent, DiscountFixed:
+		return true
+	default:
+		return false
+	}
+}
+
+func (s DiscountDuration) IsValid() bool {
+	switch s {
+	case DiscountOnce, DiscountRepeating, DiscountForever:
+		return true
+	default:
+		return false
+	}
+}
@@ -27,6 +27,12 @@ func TestStatusIsValid(t *testing.T) {
 		{"billing-invalid", BillingPeriodStatus("unknown"), false},
 		{"role-admin", RoleAdmin, true},
 		{"role-invalid", UserRole("unknown"), false},
+		{"report-weekly", ReportWeekly, true},
+		{"report-invalid", ReportPeriod("unknown"), false},
+		{"discount-percent", DiscountPercent, true},
+		{"discount-kind-invalid", DiscountKind("unknown"), false},
+		{"discount-repeating", DiscountRepeating, true},
+		{"discount-duration-invalid", DiscountDuration("unknown"), false},
 	}
 
 	for _, tt := range tests {
@@ -35,3 +41,44 @@ func TestStatusIsValid(t *testing.T) {
 		}
 	}
 }
+
+// Testa que as listas de valores contem apenas valores validos.
+func TestStatusListsAreValid(t *testing.T) {
+	var values []validatable
+	for _, value := range StudentStatuses {
+		values = append(values, value)
+	}
+	for _, value := range SubscriptionStatuses {
+		values = append(values, value)
+	}
+	for _, value := range PaymentStatuses {
+		values = append(values, value)
+	}
+	for _, value := range PaymentKinds {
+		values = append(values, value)
+	}
+	for _, value := range PaymentMethods {
+		values = append(values, value)
+	}
+	for _, value := range BillingPeriodStatuses {
+		values = append(values, value)
+	}
+	for _, value := range UserRoles {
+		values = append(values, value)
+	}
+	for _, value := range ReportPeriods {
+		values = append(values, value)
+	}
+	for _, value := range DiscountKinds {
+		values = append(values, value)
+	}
+	for _, value := range DiscountDurations {
+		values = append(values, value)
+	}
+
+	for _, value := range values {
+		if !value.IsValid() {
+			t.Fatalf("expected %v to be valid", value)
+		}
+	}
+}
@@ -12,6 +12,12 @@ type Subscription struct {
 	PriceCents int64
 	PaymentDay int
 	AutoRenew  bool
-	CreatedAt  time.Time
-	UpdatedAt  time.Time
+	// CouponID e o cupom de origem do desconto, quando houver.
+	CouponID string
+	Discount Discount
+	// PreviousSubscriptionID aponta a assinatura encerrada por uma troca de
+	// plano que deu origem a esta.
+	PreviousSubscriptionID string
+	CreatedAt              time.Time
+	UpdatedAt              time.Time
 }
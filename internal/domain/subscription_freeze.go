@@ -0,0 +1,58 @@
+package domain
+
+import "time"
+
+// SubscriptionFreeze e um trancamento da assinatura. StartDate e EndDate sao
+// inclusivos: um trancamento de 01/03 a 10/03 congela 10 dias.
+type SubscriptionFreeze struct {
+	ID             string
+	SubscriptionID string
+	StartDate      time.Time
+	EndDate        time.Time
+	Reason         string
+	CreatedAt      time.Time
+}
+
+// Days conta os dias trancados.
+func (f SubscriptionFreeze) Days() int {
+	return daysBetweenDates(f.StartDate, f.EndDate) + 1
+}
+
+// DaysInYear conta os dias trancados dentro do ano civil informado, usado no
+// limite anual do plano.
+func (f SubscriptionFreeze) DaysInYear(year int) int {
+	start := calendarDate(f.StartDate)
+	end := calendarDate(f.EndDate)
+	first := time.Date(year, time.January, 1, 0, 0, 0, 0, time.UTC)
+	last := time.Date(year, time.December, 31, 0, 0, 0, 0, time.UTC)
+	if start.Before(first) {
+		start = first
+	}
+	if end.After(last) {
+		end = last
+	}
+	if end.Before(start) {
+		return 0
+	}
+	return daysBetweenDates(start, end) + 1
+}
+
+// Covers indica se day esta dentro do trancamento.
+func (f SubscriptionFreeze) Covers(day time.Time) bool {
+	day = calendarDate(day)
+	return !day.Before(calendarDate(f.StartDate)) && !day.After(calendarDate(f.EndDate))
+}
+
+// Overlaps indica se os dois trancamentos tem algum dia em comum.
+func (f SubscriptionFreeze) Overlaps(other SubscriptionFreeze) bool {
+	return !calendarDate(f.EndDate).Before(calendarDate(other.StartDate)) &&
+		!calendarDate(other.EndDate).Before(calendarDate(f.StartDate))
+}
+
+func calendarDate(value time.Time) time.Time {
+	return time.Date(value.Year(), value.Month(), value.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+func daysBetweenDates(from, to time.Time) int {
+	return int(calendarDate(to).Sub(calendarDate(from)).Hours() / 24)
+}
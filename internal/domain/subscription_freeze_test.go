@@ -0,0 +1,35 @@
+package domain
+
+import (
+	"testing"
+	"time"
+)
+
+// Testa a contagem de dias inclusiva e a divisao entre anos civis.
+func TestSubscriptionFreezeDays(t *testing.T) {
+	freeze := SubscriptionFreeze{
+		StartDate: time.Date(2024, 12, 25, 0, 0, 0, 0, time.UTC),
+		EndDate:   time.Date(2025, 1, 5, 0, 0, 0, 0, time.UTC),
+	}
+	if freeze.Days() != 12 {
+		t.Fatalf("expected 12 days, got %d", freeze.Days())
+	}
+	if freeze.DaysInYear(2024) != 7 || freeze.DaysInYear(2025) != 5 || freeze.DaysInYear(2023) != 0 {
+		t.Fatalf("unexpected days per year: %d %d %d", freeze.DaysInYear(2024), freeze.DaysInYear(2025), freeze.DaysInYear(2023))
+	}
+	if !freeze.Covers(time.Date(2025, 1, 5, 18, 0, 0, 0, time.UTC)) || freeze.Covers(time.Date(2025, 1, 6, 0, 0, 0, 0, time.UTC)) {
+		t.Fatal("expected the end date to be covered and the next day not")
+	}
+
+	next := SubscriptionFreeze{
+		StartDate: time.Date(2025, 1, 5, 0, 0, 0, 0, time.UTC),
+		EndDate:   time.Date(2025, 1, 10, 0, 0, 0, 0, time.UTC),
+	}
+	if !freeze.Overlaps(next) {
+		t.Fatal("expected freezes sharing a day to overlap")
+	}
+	next.StartDate = time.Date(2025, 1, 6, 0, 0, 0, 0, time.UTC)
+	if freeze.Overlaps(next) {
+		t.Fatal("expected consecutive freezes not to overlap")
+	}
+}
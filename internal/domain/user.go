@@ -1,14 +1,31 @@
 package domain
 
-import "time"
+import (
+	"errors"
+	"time"
+)
+
+// ErrSelfLockout e devolvido quando um administrador tenta desativar ou
+// rebaixar o proprio usuario.
+var ErrSelfLockout = errors.New("nao e possivel remover o proprio acesso de administrador")
+
+// ErrLastAdmin e devolvido quando a alteracao deixaria o sistema sem nenhum
+// administrador ativo.
+var ErrLastAdmin = errors.New("e preciso manter pelo menos um administrador ativo")
 
 type User struct {
-	ID           string
-	Name         string
-	Email        string
-	PasswordHash string
-	Role         UserRole
-	Active       bool
-	CreatedAt    time.Time
-	UpdatedAt    time.Time
+	ID                 string
+	Name               string
+	Email              string
+	PasswordHash       string
+	Role               UserRole
+	Active             bool
+	MustChangePassword bool
+	CreatedAt          time.Time
+	UpdatedAt          time.Time
+}
+
+// IsActiveAdmin indica se o usuario tem acesso de administrador.
+func (u User) IsActiveAdmin() bool {
+	return u.Active && u.Role == RoleAdmin
 }
@@ -0,0 +1,215 @@
+package handlers
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	httpmw "github.com/PabloPavan/jaiu/internal/http/middleware"
+	"github.com/PabloPavan/jaiu/internal/observability"
+	"github.com/PabloPavan/jaiu/internal/ports"
+)
+
+const (
+	apiDefaultLimit = 50
+	apiMaxLimit     = 200
+	apiMaxBodyBytes = 1 << 20
+	apiDateLayout   = "2006-01-02"
+	apiKeysetPrefix = "k:"
+)
+
+var (
+	errAPICursor = errors.New("cursor invalido")
+	errAPILimit  = errors.New("limit deve estar entre 1 e 200")
+)
+
+// apiResource envolve um unico recurso: {"data": {...}}.
+type apiResource struct {
+	Data any `json:"data"`
+}
+
+// apiPage envolve uma lista paginada; next_cursor so aparece quando ha mais
+// itens depois desta pagina.
+type apiPage struct {
+	Data       any    `json:"data"`
+	NextCursor string `json:"next_cursor,omitempty"`
+}
+
+// apiPageRequest e a janela pedida pelos parametros limit e cursor. O cursor
+// e opaco para o cliente e guarda o ultimo item da pagina anterior.
+type apiPageRequest struct {
+	Limit int
+	After ports.PageCursor
+}
+
+func (h *Handler) APINotFound(w http.ResponseWriter, r *http.Request) {
+	httpmw.WriteAPIError(w, http.StatusNotFound, "not_found", "rota nao encontrada")
+}
+
+func (h *Handler) APIMethodNotAllowed(w http.ResponseWriter, r *http.Request) {
+	httpmw.WriteAPIError(w, http.StatusMethodNotAllowed, "method_not_allowed", "metodo nao permitido")
+}
+
+func writeAPIJSON(w http.ResponseWriter, status int, body any) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(body)
+}
+
+func writeAPIBadRequest(w http.ResponseWriter, message string) {
+	httpmw.WriteAPIError(w, http.StatusBadRequest, "invalid_request", message)
+}
+
+func writeAPIUnavailable(w http.ResponseWriter) {
+	httpmw.WriteAPIError(w, http.StatusServiceUnavailable, "unavailable", "servico indisponivel")
+}
+
+// writeAPIServiceError converte os erros dos servicos. Apenas validacoes
+// (ports.ErrValidation) voltam como 422 com a mensagem original; erros sem
+// mapeamento sao falhas internas e o detalhe fica so no log.
+func writeAPIServiceError(w http.ResponseWriter, r *http.Request, err error) {
+	switch {
+	case errors.Is(err, ports.ErrNotFound):
+		httpmw.WriteAPIError(w, http.StatusNotFound, "not_found", "recurso nao encontrado")
+	case errors.Is(err, ports.ErrSerialization):
+		httpmw.WriteAPIError(w, http.StatusConflict, "conflict", "operacao concorrente, tente novamente")
+	case errors.Is(err, ports.ErrConflict):
+		httpmw.WriteAPIError(w, http.StatusConflict, "conflict", "conflito com o estado atual do recurso")
+	case errors.Is(err, ports.ErrUnauthorized):
+		httpmw.WriteAPIError(w, http.StatusUnauthorized, "unauthorized", "nao autorizado")
+	case errors.Is(err, ports.ErrValidation):
+		observability.Logger(r.Context()).Warn("api request rejected", "err", err, "path", r.URL.Path)
+		httpmw.WriteAPIError(w, http.StatusUnprocessableEntity, "unprocessable", err.Error())
+	default:
+		observability.Logger(r.Context()).Error("api request failed", "err", err, "path", r.URL.Path)
+		httpmw.WriteAPIError(w, http.StatusInternalServerError, "internal", "erro interno")
+	}
+}
+
+// decodeAPIJSON le o corpo JSON rejeitando campos desconhecidos.
+func decodeAPIJSON(w http.ResponseWriter, r *http.Request, dst any) bool {
+	decoder := json.NewDecoder(http.MaxBytesReader(w, r.Body, apiMaxBodyBytes))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(dst); err != nil {
+		writeAPIBadRequest(w, "corpo JSON invalido: "+err.Error())
+		return false
+	}
+	if err := decoder.Decode(&struct{}{}); !errors.Is(err, io.EOF) {
+		writeAPIBadRequest(w, "corpo JSON deve conter um unico objeto")
+		return false
+	}
+	return true
+}
+
+// parseAPIKeysetPage le limit e o cursor das listagens paginadas no banco por
+// created_at e id.
+func parseAPIKeysetPage(r *http.Request) (apiPageRequest, error) {
+	page, err := parseAPILimit(r)
+	if err != nil {
+		return apiPageRequest{}, err
+	}
+	if raw := strings.TrimSpace(r.URL.Query().Get("cursor")); raw != "" {
+		after, err := decodeAPIKeysetCursor(raw)
+		if err != nil {
+			return apiPageRequest{}, err
+		}
+		page.After = after
+	}
+	return page, nil
+}
+
+func parseAPILimit(r *http.Request) (apiPageRequest, error) {
+	page := apiPageRequest{Limit: apiDefaultLimit}
+	if raw := strings.TrimSpace(r.URL.Query().Get("limit")); raw != "" {
+		limit, err := strconv.Atoi(raw)
+		if err != nil || limit < 1 || limit > apiMaxLimit {
+			return apiPageRequest{}, errAPILimit
+		}
+		page.Limit = limit
+	}
+	return page, nil
+}
+
+// encodeAPIKeysetCursor guarda created_at em microssegundos, a precisao do
+// timestamptz, para a proxima pagina comecar exatamente depois do item.
+func encodeAPIKeysetCursor(cursor ports.PageCursor) string {
+	value := apiKeysetPrefix + strconv.FormatInt(cursor.CreatedAt.UnixMicro(), 10) + ":" + cursor.ID
+	return base64.RawURLEncoding.EncodeToString([]byte(value))
+}
+
+func decodeAPIKeysetCursor(cursor string) (ports.PageCursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return ports.PageCursor{}, errAPICursor
+	}
+	value, ok := strings.CutPrefix(string(raw), apiKeysetPrefix)
+	if !ok {
+		return ports.PageCursor{}, errAPICursor
+	}
+	micros, id, ok := strings.Cut(value, ":")
+	if !ok || id == "" {
+		return ports.PageCursor{}, errAPICursor
+	}
+	createdAt, err := strconv.ParseInt(micros, 10, 64)
+	if err != nil {
+		return ports.PageCursor{}, errAPICursor
+	}
+	return ports.PageCursor{CreatedAt: time.UnixMicro(createdAt).UTC(), ID: id}, nil
+}
+
+// keyset pede ao banco um item a mais que o limite para saber se ha pagina
+// seguinte.
+func (p apiPageRequest) keyset() ports.PageRequest {
+	return ports.PageRequest{After: p.After, Limit: p.Limit + 1}
+}
+
+// apiKeysetPageOf descarta o item extra pedido em keyset e monta o cursor a
+// partir do ultimo item da pagina.
+func apiKeysetPageOf[T any](items []T, page apiPageRequest, cursor func(T) ports.PageCursor) ([]T, string) {
+	if len(items) <= page.Limit {
+		return items, ""
+	}
+	items = items[:page.Limit]
+	return items, encodeAPIKeysetCursor(cursor(items[len(items)-1]))
+}
+
+func parseAPIDate(value string) (time.Time, error) {
+	return time.Parse(apiDateLayout, strings.TrimSpace(value))
+}
+
+// parseAPIRange le o intervalo de datas [from, to], ambas inclusivas.
+func parseAPIRange(r *http.Request, fromKey, toKey string) (time.Time, time.Time, error) {
+	from, err := parseAPIDate(r.URL.Query().Get(fromKey))
+	if err != nil {
+		return time.Time{}, time.Time{}, errors.New(fromKey + " deve estar no formato AAAA-MM-DD")
+	}
+	to, err := parseAPIDate(r.URL.Query().Get(toKey))
+	if err != nil {
+		return time.Time{}, time.Time{}, errors.New(toKey + " deve estar no formato AAAA-MM-DD")
+	}
+	if to.Before(from) {
+		return time.Time{}, time.Time{}, errors.New(toKey + " deve ser igual ou posterior a " + fromKey)
+	}
+	return from, to, nil
+}
+
+func formatAPIDate(value time.Time) string {
+	if value.IsZero() {
+		return ""
+	}
+	return value.Format(apiDateLayout)
+}
+
+func formatAPIDatePtr(value *time.Time) *string {
+	if value == nil || value.IsZero() {
+		return nil
+	}
+	formatted := value.Format(apiDateLayout)
+	return &formatted
+}
@@ -0,0 +1,484 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/PabloPavan/jaiu/internal/domain"
+	httpmw "github.com/PabloPavan/jaiu/internal/http/middleware"
+)
+
+const (
+	apiOpenAPIVersion = "3.0.3"
+	apiVersion        = "1.0.0"
+	apiBasePath       = "/api/v1"
+	apiOpenAPIPath    = "/openapi.json"
+)
+
+// apiParam descreve um parametro de query, de caminho ou de cabecalho.
+type apiParam struct {
+	Name        string
+	In          string
+	Description string
+	Required    bool
+	Schema      map[string]any
+}
+
+// apiOperation documenta uma rota da API. Path segue o formato do chi,
+// relativo a /api/v1, e deve bater com o router: o teste do router falha
+// quando uma rota nao aparece aqui.
+type apiOperation struct {
+	Method     string
+	Path       string
+	Tag        string
+	Summary    string
+	Scope      domain.APIResource
+	Permission domain.Permission
+	Params     []apiParam
+	Body       any
+	Response   any
+	Paged      bool
+	Status     int
+	Public     bool
+}
+
+var apiOperations = []apiOperation{
+	{Method: http.MethodGet, Path: apiOpenAPIPath, Tag: "meta", Summary: "Especificacao OpenAPI da API", Public: true},
+
+	{Method: http.MethodGet, Path: "/students", Tag: "students", Summary: "Lista alunos", Scope: domain.APIResourceStudents, Response: apiStudent{}, Paged: true, Params: []apiParam{
+		{Name: "q", In: "query", Description: "Busca por nome, email, telefone ou CPF.", Schema: apiStringSchema()},
+		{Name: "status", In: "query", Description: "Status separados por virgula.", Schema: apiStringSchema()},
+	}},
+	{Method: http.MethodPost, Path: "/students", Tag: "students", Summary: "Cadastra um aluno", Scope: domain.APIResourceStudents, Body: apiStudentInput{}, Response: apiStudent{}, Status: http.StatusCreated},
+	{Method: http.MethodGet, Path: "/students/{studentID}", Tag: "students", Summary: "Busca um aluno", Scope: domain.APIResourceStudents, Response: apiStudent{}},
+	{Method: http.MethodPut, Path: "/students/{studentID}", Tag: "students", Summary: "Atualiza um aluno", Scope: domain.APIResourceStudents, Body: apiStudentInput{}, Response: apiStudent{}},
+	{Method: http.MethodDelete, Path: "/students/{studentID}", Tag: "students", Summary: "Inativa um aluno", Scope: domain.APIResourceStudents, Response: apiStudent{}},
+	{Method: http.MethodGet, Path: "/students/{studentID}/subscriptions", Tag: "students", Summary: "Lista as assinaturas do aluno", Scope: domain.APIResourceSubscriptions, Response: apiSubscription{}, Paged: true},
+
+	{Method: http.MethodGet, Path: "/plans", Tag: "plans", Summary: "Lista os planos ativos", Scope: domain.APIResourcePlans, Response: apiPlan{}, Paged: true},
+	{Method: http.MethodPost, Path: "/plans", Tag: "plans", Summary: "Cria um plano", Scope: domain.APIResourcePlans, Permission: domain.PermissionPlanCreate, Body: apiPlanInput{}, Response: apiPlan{}, Status: http.StatusCreated},
+	{Method: http.MethodGet, Path: "/plans/{planID}", Tag: "plans", Summary: "Busca um plano", Scope: domain.APIResourcePlans, Response: apiPlan{}},
+	{Method: http.MethodPut, Path: "/plans/{planID}", Tag: "plans", Summary: "Atualiza um plano", Scope: domain.APIResourcePlans, Permission: domain.PermissionPlanUpdate, Body: apiPlanInput{}, Response: apiPlan{}},
+	{Method: http.MethodDelete, Path: "/plans/{planID}", Tag: "plans", Summary: "Desativa um plano", Scope: domain.APIResourcePlans, Permission: domain.PermissionPlanDelete, Response: apiPlan{}},
+
+	{Method: http.MethodGet, Path: "/subscriptions", Tag: "subscriptions", Summary: "Lista as assinaturas ativas que vencem no intervalo", Scope: domain.APIResourceSubscriptions, Response: apiSubscription{}, Paged: true, Params: []apiParam{
+		{Name: "due_from", In: "query", Required: true, Schema: apiDateSchema()},
+		{Name: "due_to", In: "query", Required: true, Schema: apiDateSchema()},
+	}},
+	{Method: http.MethodPost, Path: "/subscriptions", Tag: "subscriptions", Summary: "Cria uma assinatura", Scope: domain.APIResourceSubscriptions, Body: apiSubscriptionInput{}, Response: apiSubscription{}, Status: http.StatusCreated},
+	{Method: http.MethodGet, Path: "/subscriptions/{subscriptionID}", Tag: "subscriptions", Summary: "Busca uma assinatura", Scope: domain.APIResourceSubscriptions, Response: apiSubscription{}},
+	{Method: http.MethodPut, Path: "/subscriptions/{subscriptionID}", Tag: "subscriptions", Summary: "Atualiza uma assinatura", Scope: domain.APIResourceSubscriptions, Body: apiSubscriptionInput{}, Response: apiSubscription{}},
+	{Method: http.MethodPost, Path: "/subscriptions/{subscriptionID}/cancel", Tag: "subscriptions", Summary: "Cancela uma assinatura", Scope: domain.APIResourceSubscriptions, Response: apiSubscription{}},
+	{Method: http.MethodPost, Path: "/subscriptions/{subscriptionID}/change-plan", Tag: "subscriptions", Summary: "Troca o plano com cobranca proporcional e devolve a nova assinatura", Scope: domain.APIResourceSubscriptions, Body: apiChangePlanInput{}, Response: apiSubscription{}, Status: http.StatusCreated},
+	{Method: http.MethodGet, Path: "/subscriptions/{subscriptionID}/freezes", Tag: "subscriptions", Summary: "Lista os trancamentos da assinatura", Scope: domain.APIResourceSubscriptions, Response: apiSubscriptionFreeze{}, Paged: true},
+	{Method: http.MethodPost, Path: "/subscriptions/{subscriptionID}/freezes", Tag: "subscriptions", Summary: "Tranca a assinatura adiando vencimentos e o fim", Scope: domain.APIResourceSubscriptions, Body: apiSubscriptionFreezeInput{}, Response: apiSubscriptionFreeze{}, Status: http.StatusCreated},
+	{Method: http.MethodGet, Path: "/subscriptions/{subscriptionID}/payments", Tag: "subscriptions", Summary: "Lista os pagamentos da assinatura", Scope: domain.APIResourcePayments, Response: apiPayment{}, Paged: true},
+	{Method: http.MethodGet, Path: "/subscriptions/{subscriptionID}/billing-periods", Tag: "subscriptions", Summary: "Lista as competencias da assinatura", Scope: domain.APIResourcePayments, Response: apiBillingPeriod{}, Paged: true},
+	{Method: http.MethodPost, Path: "/subscriptions/{subscriptionID}/billing-periods/{periodID}/waive-fees", Tag: "subscriptions", Summary: "Dispensa multa e juros em aberto da competencia", Scope: domain.APIResourcePayments, Permission: domain.PermissionBillingFeeWaive, Response: apiBillingPeriod{}},
+
+	{Method: http.MethodGet, Path: "/payments", Tag: "payments", Summary: "Lista os pagamentos do intervalo", Scope: domain.APIResourcePayments, Response: apiPayment{}, Paged: true, Params: []apiParam{
+		{Name: "from", In: "query", Required: true, Schema: apiDateSchema()},
+		{Name: "to", In: "query", Required: true, Schema: apiDateSchema()},
+	}},
+	{Method: http.MethodPost, Path: "/payments", Tag: "payments", Summary: "Registra um pagamento", Scope: domain.APIResourcePayments, Body: apiPaymentInput{}, Response: apiPayment{}, Status: http.StatusCreated, Params: []apiParam{
+		{Name: apiIdempotencyHeader, In: "header", Description: "Repetir a chave devolve o pagamento ja registrado.", Schema: apiStringSchema()},
+	}},
+	{Method: http.MethodGet, Path: "/payments/{paymentID}", Tag: "payments", Summary: "Busca um pagamento", Scope: domain.APIResourcePayments, Response: apiPayment{}},
+	{Method: http.MethodPatch, Path: "/payments/{paymentID}", Tag: "payments", Summary: "Altera os dados descritivos de um pagamento", Scope: domain.APIResourcePayments, Body: apiPaymentPatch{}, Response: apiPayment{}},
+	{Method: http.MethodPost, Path: "/payments/{paymentID}/reverse", Tag: "payments", Summary: "Estorna um pagamento", Scope: domain.APIResourcePayments, Permission: domain.PermissionPaymentReverse, Response: apiPayment{}},
+
+	{Method: http.MethodGet, Path: "/reports/revenue", Tag: "reports", Summary: "Receita por periodo", Scope: domain.APIResourceReports, Response: []apiRevenueBucket{}, Params: []apiParam{
+		{Name: "period", In: "query", Schema: apiEnumSchema(domain.ReportPeriods)},
+		{Name: "buckets", In: "query", Schema: apiIntSchema(1, apiMaxRevenueBuckets)},
+	}},
+	{Method: http.MethodGet, Path: "/reports/students-by-status", Tag: "reports", Summary: "Total de alunos por status", Scope: domain.APIResourceReports, Response: []apiStatusCount{}},
+	{Method: http.MethodGet, Path: "/reports/delinquent", Tag: "reports", Summary: "Assinaturas vencidas", Scope: domain.APIResourceReports, Response: []apiDueSubscription{}},
+	{Method: http.MethodGet, Path: "/reports/upcoming-due", Tag: "reports", Summary: "Assinaturas que vencem nos proximos dias", Scope: domain.APIResourceReports, Response: []apiDueSubscription{}, Params: []apiParam{
+		{Name: "days", In: "query", Schema: apiIntSchema(1, apiMaxDueDays)},
+	}},
+}
+
+// apiEnumValues liga os tipos do dominio aos valores aceitos, para que os
+// campos desses tipos aparecam como enum na especificacao.
+var apiEnumValues = map[reflect.Type][]string{
+	reflect.TypeOf(domain.StudentStatus("")):       apiEnumStrings(domain.StudentStatuses),
+	reflect.TypeOf(domain.SubscriptionStatus("")):  apiEnumStrings(domain.SubscriptionStatuses),
+	reflect.TypeOf(domain.PaymentStatus("")):       apiEnumStrings(domain.PaymentStatuses),
+	reflect.TypeOf(domain.PaymentKind("")):         apiEnumStrings(domain.PaymentKinds),
+	reflect.TypeOf(domain.PaymentMethod("")):       apiEnumStrings(domain.PaymentMethods),
+	reflect.TypeOf(domain.BillingPeriodStatus("")): apiEnumStrings(domain.BillingPeriodStatuses),
+	reflect.TypeOf(domain.ReportPeriod("")):        apiEnumStrings(domain.ReportPeriods),
+	reflect.TypeOf(domain.DiscountKind("")):        apiEnumStrings(domain.DiscountKinds),
+	reflect.TypeOf(domain.DiscountDuration("")):    apiEnumStrings(domain.DiscountDurations),
+}
+
+var (
+	apiTimeType     = reflect.TypeOf(time.Time{})
+	apiPathParamExp = regexp.MustCompile(`\{([^}]+)\}`)
+
+	apiOpenAPIOnce sync.Once
+	apiOpenAPIBody []byte
+)
+
+// APIOpenAPI serve a especificacao OpenAPI 3 gerada a partir de apiOperations.
+func (h *Handler) APIOpenAPI(w http.ResponseWriter, r *http.Request) {
+	apiOpenAPIOnce.Do(func() {
+		body, err := json.Marshal(buildAPIOpenAPI(h.config.CookieName))
+		if err != nil {
+			panic("openapi: " + err.Error())
+		}
+		apiOpenAPIBody = body
+	})
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.Header().Set("Cache-Control", "public, max-age=300")
+	_, _ = w.Write(apiOpenAPIBody)
+}
+
+func buildAPIOpenAPI(cookieName string) map[string]any {
+	schemas := &apiSchemaSet{schemas: map[string]any{}}
+	schemas.schemas["Error"] = map[string]any{
+		"type":     "object",
+		"required": []string{"error"},
+		"properties": map[string]any{
+			"error": schemas.response(reflect.TypeOf(httpmw.APIError{})),
+		},
+	}
+
+	paths := map[string]any{}
+	for _, op := range apiOperations {
+		item, ok := paths[op.Path].(map[string]any)
+		if !ok {
+			item = map[string]any{}
+			paths[op.Path] = item
+		}
+		item[strings.ToLower(op.Method)] = schemas.operation(op)
+	}
+
+	return map[string]any{
+		"openapi": apiOpenAPIVersion,
+		"info": map[string]any{
+			"title":       "Jaiu API",
+			"version":     apiVersion,
+			"description": "API JSON para alunos, planos, assinaturas, pagamentos e relatorios. Escritas com cookie de sessao exigem o cabecalho X-CSRF-Token; tokens de API dispensam CSRF e sao limitados pelos escopos indicados em x-required-scope.",
+		},
+		"servers": []any{map[string]any{"url": apiBasePath}},
+		"paths":   paths,
+		"components": map[string]any{
+			"schemas": schemas.schemas,
+			"responses": map[string]any{
+				"BadRequest":    apiErrorResponse("Parametros ou corpo invalidos (invalid_request)."),
+				"Unauthorized":  apiErrorResponse("Sessao ausente ou token invalido (unauthorized, invalid_token)."),
+				"Forbidden":     apiErrorResponse("Sem permissao, escopo ou CSRF invalido (forbidden, insufficient_scope, csrf_invalid)."),
+				"NotFound":      apiErrorResponse("Recurso nao encontrado (not_found)."),
+				"Conflict":      apiErrorResponse("Conflito com o estado atual do recurso (conflict)."),
+				"Unprocessable": apiErrorResponse("Rejeitado pelas regras de negocio (unprocessable)."),
+			},
+			"securitySchemes": map[string]any{
+				"bearerAuth": map[string]any{"type": "http", "scheme": "bearer"},
+				"cookieAuth": map[string]any{"type": "apiKey", "in": "cookie", "name": cookieName},
+			},
+		},
+		"security": []any{
+			map[string]any{"bearerAuth": []string{}},
+			map[string]any{"cookieAuth": []string{}},
+		},
+	}
+}
+
+// apiSchemaSet acumula os schemas nomeados em components/schemas.
+type apiSchemaSet struct {
+	schemas map[string]any
+}
+
+func (s *apiSchemaSet) operation(op apiOperation) map[string]any {
+	operation := map[string]any{
+		"operationId": apiOperationID(op),
+		"summary":     op.Summary,
+		"tags":        []string{op.Tag},
+	}
+	if op.Public {
+		operation["security"] = []any{}
+	}
+	if op.Scope != "" {
+		operation["x-required-scope"] = string(domain.NewAPIScope(op.Scope, apiOperationAccess(op.Method)))
+	}
+	if op.Permission != "" {
+		operation["x-required-permission"] = string(op.Permission)
+	}
+
+	var params []any
+	for _, match := range apiPathParamExp.FindAllStringSubmatch(op.Path, -1) {
+		params = append(params, apiParamObject(apiParam{Name: match[1], In: "path", Required: true, Schema: apiStringSchema()}))
+	}
+	for _, param := range op.Params {
+		params = append(params, apiParamObject(param))
+	}
+	if op.Paged {
+		params = append(params,
+			apiParamObject(apiParam{Name: "limit", In: "query", Schema: apiIntSchema(1, apiMaxLimit)}),
+			apiParamObject(apiParam{Name: "cursor", In: "query", Description: "Valor de next_cursor da pagina anterior.", Schema: apiStringSchema()}),
+		)
+	}
+	if len(params) > 0 {
+		operation["parameters"] = params
+	}
+
+	if op.Body != nil {
+		operation["requestBody"] = map[string]any{
+			"required": true,
+			"content": map[string]any{
+				"application/json": map[string]any{"schema": s.request(reflect.TypeOf(op.Body))},
+			},
+		}
+	}
+
+	status := op.Status
+	if status == 0 {
+		status = http.StatusOK
+	}
+	responses := map[string]any{}
+	if op.Public {
+		responses[strconv.Itoa(status)] = map[string]any{
+			"description": "Documento OpenAPI.",
+			"content":     map[string]any{"application/json": map[string]any{"schema": map[string]any{"type": "object"}}},
+		}
+		operation["responses"] = responses
+		return operation
+	}
+
+	responses[strconv.Itoa(status)] = map[string]any{
+		"description": http.StatusText(status),
+		"content":     map[string]any{"application/json": map[string]any{"schema": s.envelope(op)}},
+	}
+	responses["401"] = apiResponseRef("Unauthorized")
+	responses["403"] = apiResponseRef("Forbidden")
+	if len(params) > 0 || op.Body != nil {
+		responses["400"] = apiResponseRef("BadRequest")
+	}
+	if strings.Contains(op.Path, "{") {
+		responses["404"] = apiResponseRef("NotFound")
+	}
+	if op.Method != http.MethodGet {
+		responses["409"] = apiResponseRef("Conflict")
+		responses["422"] = apiResponseRef("Unprocessable")
+	}
+	operation["responses"] = responses
+	return operation
+}
+
+// envelope descreve {"data": ...} e, nas listagens paginadas, next_cursor.
+func (s *apiSchemaSet) envelope(op apiOperation) map[string]any {
+	data := s.response(reflect.TypeOf(op.Response))
+	if op.Paged {
+		return map[string]any{
+			"type":     "object",
+			"required": []string{"data"},
+			"properties": map[string]any{
+				"data":        map[string]any{"type": "array", "items": data},
+				"next_cursor": map[string]any{"type": "string", "description": "Ausente na ultima pagina."},
+			},
+		}
+	}
+	return map[string]any{
+		"type":       "object",
+		"required":   []string{"data"},
+		"properties": map[string]any{"data": data},
+	}
+}
+
+func (s *apiSchemaSet) request(t reflect.Type) map[string]any {
+	return s.schema(t, "", false)
+}
+
+func (s *apiSchemaSet) response(t reflect.Type) map[string]any {
+	return s.schema(t, "", true)
+}
+
+// schema traduz um tipo Go para JSON Schema. Structs viram schemas nomeados;
+// nas respostas os campos sem omitempty sao sempre enviados, enquanto nos
+// corpos de entrada so sao obrigatorios os marcados com openapi:"required".
+func (s *apiSchemaSet) schema(t reflect.Type, format string, response bool) map[string]any {
+	if values, ok := apiEnumValues[t]; ok {
+		return map[string]any{"type": "string", "enum": values}
+	}
+	if t == apiTimeType {
+		return map[string]any{"type": "string", "format": "date-time"}
+	}
+
+	switch t.Kind() {
+	case reflect.Pointer:
+		inner := s.schema(t.Elem(), format, response)
+		if _, ok := inner["$ref"]; ok {
+			return map[string]any{"allOf": []any{inner}, "nullable": true}
+		}
+		inner["nullable"] = true
+		return inner
+	case reflect.Struct:
+		return s.ref(t, response)
+	case reflect.Slice:
+		return map[string]any{"type": "array", "items": s.schema(t.Elem(), "", response)}
+	case reflect.String:
+		schema := apiStringSchema()
+		if format != "" {
+			schema["format"] = format
+		}
+		return schema
+	case reflect.Bool:
+		return map[string]any{"type": "boolean"}
+	case reflect.Int64:
+		return map[string]any{"type": "integer", "format": "int64"}
+	case reflect.Int, reflect.Int32:
+		return map[string]any{"type": "integer"}
+	case reflect.Float32, reflect.Float64:
+		return map[string]any{"type": "number"}
+	default:
+		panic("openapi: tipo sem schema: " + t.String())
+	}
+}
+
+func (s *apiSchemaSet) ref(t reflect.Type, response bool) map[string]any {
+	name := apiSchemaName(t)
+	if _, ok := s.schemas[name]; !ok {
+		s.schemas[name] = map[string]any{}
+		s.schemas[name] = s.object(t, response)
+	}
+	return map[string]any{"$ref": "#/components/schemas/" + name}
+}
+
+func (s *apiSchemaSet) object(t reflect.Type, response bool) map[string]any {
+	properties := map[string]any{}
+	required := []string{}
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		name, options, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if name == "-" {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+
+		var format string
+		var mandatory bool
+		for _, option := range strings.Split(field.Tag.Get("openapi"), ",") {
+			switch option {
+			case "date":
+				format = "date"
+			case "required":
+				mandatory = true
+			}
+		}
+		if response {
+			mandatory = !strings.Contains(options, "omitempty")
+		}
+
+		properties[name] = s.schema(field.Type, format, response)
+		if mandatory {
+			required = append(required, name)
+		}
+	}
+
+	object := map[string]any{"type": "object", "properties": properties}
+	if !response {
+		object["additionalProperties"] = false
+	}
+	if len(required) > 0 {
+		sort.Strings(required)
+		object["required"] = required
+	}
+	return object
+}
+
+// apiSchemaName remove o prefixo api dos tipos internos: apiStudentInput vira
+// StudentInput.
+func apiSchemaName(t reflect.Type) string {
+	name := strings.TrimPrefix(t.Name(), "api")
+	if name == "" {
+		return t.Name()
+	}
+	return strings.ToUpper(name[:1]) + name[1:]
+}
+
+func apiOperationID(op apiOperation) string {
+	var b strings.Builder
+	b.WriteString(strings.ToLower(op.Method))
+	for _, part := range strings.FieldsFunc(op.Path, func(r rune) bool {
+		return r == '/' || r == '-' || r == '.' || r == '{' || r == '}'
+	}) {
+		b.WriteString(strings.ToUpper(part[:1]) + part[1:])
+	}
+	return b.String()
+}
+
+// apiOperationAccess segue o RequireAPIScope: metodos seguros exigem leitura.
+func apiOperationAccess(method string) domain.APIAccess {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return domain.APIAccessRead
+	default:
+		return domain.APIAccessWrite
+	}
+}
+
+func apiParamObject(param apiParam) map[string]any {
+	object := map[string]any{
+		"name":   param.Name,
+		"in":     param.In,
+		"schema": param.Schema,
+	}
+	if param.Required {
+		object["required"] = true
+	}
+	if param.Description != "" {
+		object["description"] = param.Description
+	}
+	return object
+}
+
+func apiErrorResponse(description string) map[string]any {
+	return map[string]any{
+		"description": description,
+		"content": map[string]any{
+			"application/json": map[string]any{"schema": map[string]any{"$ref": "#/components/schemas/Error"}},
+		},
+	}
+}
+
+func apiResponseRef(name string) map[string]any {
+	return map[string]any{"$ref": "#/components/responses/" + name}
+}
+
+func apiStringSchema() map[string]any {
+	return map[string]any{"type": "string"}
+}
+
+func apiDateSchema() map[string]any {
+	return map[string]any{"type": "string", "format": "date"}
+}
+
+func apiIntSchema(minimum, maximum int) map[string]any {
+	return map[string]any{"type": "integer", "minimum": minimum, "maximum": maximum}
+}
+
+func apiEnumSchema[T ~string](values []T) map[string]any {
+	return map[string]any{"type": "string", "enum": apiEnumStrings(values)}
+}
+
+func apiEnumStrings[T ~string](values []T) []string {
+	out := make([]string, 0, len(values))
+	for _, value := range values {
+		out = append(out, string(value))
+	}
+	return out
+}
@@ -0,0 +1,315 @@
+package handlers
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/PabloPavan/jaiu/internal/domain"
+	"github.com/PabloPavan/jaiu/internal/ports"
+	"github.com/go-chi/chi/v5"
+)
+
+// apiIdempotencyHeader tem precedencia sobre o campo idempotency_key do corpo.
+const apiIdempotencyHeader = "Idempotency-Key"
+
+type apiPayment struct {
+	ID             string               `json:"id"`
+	SubscriptionID string               `json:"subscription_id"`
+	PaidAt         time.Time            `json:"paid_at"`
+	AmountCents    int64                `json:"amount_cents"`
+	Method         domain.PaymentMethod `json:"method"`
+	Reference      string               `json:"reference"`
+	Notes          string               `json:"notes"`
+	Status         domain.PaymentStatus `json:"status"`
+	Kind           domain.PaymentKind   `json:"kind"`
+	CreditCents    int64                `json:"credit_cents"`
+	IdempotencyKey string               `json:"idempotency_key,omitempty"`
+	CreatedAt      time.Time            `json:"created_at"`
+}
+
+type apiPaymentInput struct {
+	SubscriptionID string               `json:"subscription_id" openapi:"required"`
+	PaidAt         *time.Time           `json:"paid_at"`
+	AmountCents    int64                `json:"amount_cents" openapi:"required"`
+	Method         domain.PaymentMethod `json:"method" openapi:"required"`
+	Reference      string               `json:"reference"`
+	Notes          string               `json:"notes"`
+	IdempotencyKey string               `json:"idempotency_key"`
+}
+
+// apiPaymentPatch altera apenas dados descritivos; valor e data exigem estorno.
+type apiPaymentPatch struct {
+	Method    *domain.PaymentMethod `json:"method"`
+	Reference *string               `json:"reference"`
+	Notes     *string               `json:"notes"`
+}
+
+type apiBillingPeriod struct {
+	ID              string                     `json:"id"`
+	SubscriptionID  string                     `json:"subscription_id"`
+	PeriodStart     string                     `json:"period_start" openapi:"date"`
+	PeriodEnd       string                     `json:"period_end" openapi:"date"`
+	AmountDueCents  int64                      `json:"amount_due_cents"`
+	DiscountCents   int64                      `json:"discount_cents"`
+	AmountPaidCents int64                      `json:"amount_paid_cents"`
+	FeeCents        int64                      `json:"fee_cents"`
+	FeeAccruedOn    *string                    `json:"fee_accrued_on" openapi:"date"`
+	FeeWaivedAt     *time.Time                 `json:"fee_waived_at"`
+	Installment     int                        `json:"installment"`
+	Installments    int                        `json:"installments"`
+	Status          domain.BillingPeriodStatus `json:"status"`
+	CreatedAt       time.Time                  `json:"created_at"`
+	UpdatedAt       time.Time                  `json:"updated_at"`
+}
+
+func newAPIPayment(payment domain.Payment) apiPayment {
+	return apiPayment{
+		ID:             payment.ID,
+		SubscriptionID: payment.SubscriptionID,
+		PaidAt:         payment.PaidAt,
+		AmountCents:    payment.AmountCents,
+		Method:         payment.Method,
+		Reference:      payment.Reference,
+		Notes:          payment.Notes,
+		Status:         payment.Status,
+		Kind:           payment.Kind,
+		CreditCents:    payment.CreditCents,
+		IdempotencyKey: payment.IdempotencyKey,
+		CreatedAt:      payment.CreatedAt,
+	}
+}
+
+func newAPIPayments(payments []domain.Payment) []apiPayment {
+	items := make([]apiPayment, 0, len(payments))
+	for _, payment := range payments {
+		items = append(items, newAPIPayment(payment))
+	}
+	return items
+}
+
+func newAPIBillingPeriod(period domain.BillingPeriod) apiBillingPeriod {
+	return apiBillingPeriod{
+		ID:              period.ID,
+		SubscriptionID:  period.SubscriptionID,
+		PeriodStart:     formatAPIDate(period.PeriodStart),
+		PeriodEnd:       formatAPIDate(period.PeriodEnd),
+		AmountDueCents:  period.AmountDueCents,
+		DiscountCents:   period.DiscountCents,
+		AmountPaidCents: period.AmountPaidCents,
+		FeeCents:        period.FeeCents,
+		FeeAccruedOn:    formatAPIDatePtr(period.FeeAccruedOn),
+		FeeWaivedAt:     period.FeeWaivedAt,
+		Installment:     max(period.Installment, 1),
+		Installments:    max(period.Installments, 1),
+		Status:          period.Status,
+		CreatedAt:       period.CreatedAt,
+		UpdatedAt:       period.UpdatedAt,
+	}
+}
+
+func (in apiPaymentInput) payment(r *http.Request) (domain.Payment, string) {
+	payment := domain.Payment{
+		SubscriptionID: strings.TrimSpace(in.SubscriptionID),
+		AmountCents:    in.AmountCents,
+		Method:         domain.PaymentMethod(strings.TrimSpace(string(in.Method))),
+		Reference:      strings.TrimSpace(in.Reference),
+		Notes:          strings.TrimSpace(in.Notes),
+		IdempotencyKey: strings.TrimSpace(in.IdempotencyKey),
+	}
+	if key := strings.TrimSpace(r.Header.Get(apiIdempotencyHeader)); key != "" {
+		payment.IdempotencyKey = key
+	}
+	if in.PaidAt != nil {
+		payment.PaidAt = *in.PaidAt
+	}
+	if payment.SubscriptionID == "" {
+		return domain.Payment{}, "subscription_id e obrigatorio"
+	}
+	if payment.AmountCents <= 0 {
+		return domain.Payment{}, "amount_cents deve ser maior que zero"
+	}
+	if !payment.Method.IsValid() {
+		return domain.Payment{}, "method invalido"
+	}
+	return payment, ""
+}
+
+func apiPaymentCursor(payment domain.Payment) ports.PageCursor {
+	return ports.PageCursor{CreatedAt: payment.CreatedAt, ID: payment.ID}
+}
+
+// APIPaymentsList lista os pagamentos feitos entre from e to.
+func (h *Handler) APIPaymentsList(w http.ResponseWriter, r *http.Request) {
+	if h.services.Payments == nil {
+		writeAPIUnavailable(w)
+		return
+	}
+	page, err := parseAPIKeysetPage(r)
+	if err != nil {
+		writeAPIBadRequest(w, err.Error())
+		return
+	}
+	from, to, err := parseAPIRange(r, "from", "to")
+	if err != nil {
+		writeAPIBadRequest(w, err.Error())
+		return
+	}
+
+	payments, err := h.services.Payments.ListByPeriodPage(r.Context(), from, to.AddDate(0, 0, 1), page.keyset())
+	if err != nil {
+		writeAPIServiceError(w, r, err)
+		return
+	}
+	payments, next := apiKeysetPageOf(payments, page, apiPaymentCursor)
+	writeAPIJSON(w, http.StatusOK, apiPage{Data: newAPIPayments(payments), NextCursor: next})
+}
+
+func (h *Handler) APISubscriptionPayments(w http.ResponseWriter, r *http.Request) {
+	if h.services.Payments == nil {
+		writeAPIUnavailable(w)
+		return
+	}
+	page, err := parseAPIKeysetPage(r)
+	if err != nil {
+		writeAPIBadRequest(w, err.Error())
+		return
+	}
+
+	payments, err := h.services.Payments.ListBySubscriptionPage(r.Context(), chi.URLParam(r, "subscriptionID"), page.keyset())
+	if err != nil {
+		writeAPIServiceError(w, r, err)
+		return
+	}
+	payments, next := apiKeysetPageOf(payments, page, apiPaymentCursor)
+	writeAPIJSON(w, http.StatusOK, apiPage{Data: newAPIPayments(payments), NextCursor: next})
+}
+
+func (h *Handler) APISubscriptionBillingPeriods(w http.ResponseWriter, r *http.Request) {
+	if h.services.Payments == nil {
+		writeAPIUnavailable(w)
+		return
+	}
+	page, err := parseAPIKeysetPage(r)
+	if err != nil {
+		writeAPIBadRequest(w, err.Error())
+		return
+	}
+
+	periods, err := h.services.Payments.ListBillingPeriodsPage(r.Context(), chi.URLParam(r, "subscriptionID"), page.keyset())
+	if err != nil {
+		writeAPIServiceError(w, r, err)
+		return
+	}
+	periods, next := apiKeysetPageOf(periods, page, func(period domain.BillingPeriod) ports.PageCursor {
+		return ports.PageCursor{CreatedAt: period.CreatedAt, ID: period.ID}
+	})
+	items := make([]apiBillingPeriod, 0, len(periods))
+	for _, period := range periods {
+		items = append(items, newAPIBillingPeriod(period))
+	}
+	writeAPIJSON(w, http.StatusOK, apiPage{Data: items, NextCursor: next})
+}
+
+// APISubscriptionBillingPeriodWaiveFees dispensa os encargos em aberto da
+// competencia.
+func (h *Handler) APISubscriptionBillingPeriodWaiveFees(w http.ResponseWriter, r *http.Request) {
+	if h.services.Payments == nil {
+		writeAPIUnavailable(w)
+		return
+	}
+	period, err := h.services.Payments.WaiveLateFees(r.Context(), chi.URLParam(r, "subscriptionID"), chi.URLParam(r, "periodID"))
+	if err != nil {
+		writeAPIServiceError(w, r, err)
+		return
+	}
+	writeAPIJSON(w, http.StatusOK, apiResource{Data: newAPIBillingPeriod(period)})
+}
+
+func (h *Handler) APIPaymentsGet(w http.ResponseWriter, r *http.Request) {
+	if h.services.Payments == nil {
+		writeAPIUnavailable(w)
+		return
+	}
+	payment, err := h.services.Payments.FindByID(r.Context(), chi.URLParam(r, "paymentID"))
+	if err != nil {
+		writeAPIServiceError(w, r, err)
+		return
+	}
+	writeAPIJSON(w, http.StatusOK, apiResource{Data: newAPIPayment(payment)})
+}
+
+// APIPaymentsCreate registra o pagamento. Repetir a requisicao com a mesma
+// chave de idempotencia devolve o pagamento ja registrado.
+func (h *Handler) APIPaymentsCreate(w http.ResponseWriter, r *http.Request) {
+	if h.services.Payments == nil {
+		writeAPIUnavailable(w)
+		return
+	}
+	var input apiPaymentInput
+	if !decodeAPIJSON(w, r, &input) {
+		return
+	}
+	payment, problem := input.payment(r)
+	if problem != "" {
+		writeAPIBadRequest(w, problem)
+		return
+	}
+
+	created, err := h.services.Payments.Register(r.Context(), payment)
+	if err != nil {
+		writeAPIServiceError(w, r, err)
+		return
+	}
+	writeAPIJSON(w, http.StatusCreated, apiResource{Data: newAPIPayment(created)})
+}
+
+func (h *Handler) APIPaymentsUpdate(w http.ResponseWriter, r *http.Request) {
+	if h.services.Payments == nil {
+		writeAPIUnavailable(w)
+		return
+	}
+	var patch apiPaymentPatch
+	if !decodeAPIJSON(w, r, &patch) {
+		return
+	}
+
+	payment, err := h.services.Payments.FindByID(r.Context(), chi.URLParam(r, "paymentID"))
+	if err != nil {
+		writeAPIServiceError(w, r, err)
+		return
+	}
+	if patch.Method != nil {
+		payment.Method = domain.PaymentMethod(strings.TrimSpace(string(*patch.Method)))
+		if !payment.Method.IsValid() {
+			writeAPIBadRequest(w, "method invalido")
+			return
+		}
+	}
+	if patch.Reference != nil {
+		payment.Reference = strings.TrimSpace(*patch.Reference)
+	}
+	if patch.Notes != nil {
+		payment.Notes = strings.TrimSpace(*patch.Notes)
+	}
+
+	updated, err := h.services.Payments.Update(r.Context(), payment)
+	if err != nil {
+		writeAPIServiceError(w, r, err)
+		return
+	}
+	writeAPIJSON(w, http.StatusOK, apiResource{Data: newAPIPayment(updated)})
+}
+
+func (h *Handler) APIPaymentsReverse(w http.ResponseWriter, r *http.Request) {
+	if h.services.Payments == nil {
+		writeAPIUnavailable(w)
+		return
+	}
+	reversed, err := h.services.Payments.Reverse(r.Context(), chi.URLParam(r, "paymentID"))
+	if err != nil {
+		writeAPIServiceError(w, r, err)
+		return
+	}
+	writeAPIJSON(w, http.StatusOK, apiResource{Data: newAPIPayment(reversed)})
+}
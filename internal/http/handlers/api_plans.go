@@ -0,0 +1,199 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/PabloPavan/jaiu/internal/domain"
+	"github.com/PabloPavan/jaiu/internal/ports"
+	"github.com/go-chi/chi/v5"
+)
+
+type apiPlan struct {
+	ID              string    `json:"id"`
+	Name            string    `json:"name"`
+	DurationDays    int       `json:"duration_days"`
+	PriceCents      int64     `json:"price_cents"`
+	Active          bool      `json:"active"`
+	Description     string    `json:"description"`
+	LateFeeBps      int       `json:"late_fee_bps"`
+	LateInterestBps int       `json:"late_interest_bps"`
+	MaxFreezeDays   int       `json:"max_freeze_days"`
+	Installments    int       `json:"installments"`
+	CreatedAt       time.Time `json:"created_at"`
+	UpdatedAt       time.Time `json:"updated_at"`
+}
+
+type apiPlanInput struct {
+	Name            string `json:"name" openapi:"required"`
+	DurationDays    int    `json:"duration_days" openapi:"required"`
+	PriceCents      int64  `json:"price_cents"`
+	Active          *bool  `json:"active"`
+	Description     string `json:"description"`
+	LateFeeBps      int    `json:"late_fee_bps"`
+	LateInterestBps int    `json:"late_interest_bps"`
+	MaxFreezeDays   int    `json:"max_freeze_days"`
+	Installments    int    `json:"installments"`
+}
+
+func newAPIPlan(plan domain.Plan) apiPlan {
+	return apiPlan{
+		ID:              plan.ID,
+		Name:            plan.Name,
+		DurationDays:    plan.DurationDays,
+		PriceCents:      plan.PriceCents,
+		Active:          plan.Active,
+		Description:     plan.Description,
+		LateFeeBps:      plan.LateFeeBasisPoints,
+		LateInterestBps: plan.LateInterestBasisPoints,
+		MaxFreezeDays:   plan.MaxFreezeDays,
+		Installments:    plan.InstallmentCount(),
+		CreatedAt:       plan.CreatedAt,
+		UpdatedAt:       plan.UpdatedAt,
+	}
+}
+
+func (in apiPlanInput) plan() (domain.Plan, string) {
+	plan := domain.Plan{
+		Name:                    strings.TrimSpace(in.Name),
+		DurationDays:            in.DurationDays,
+		PriceCents:              in.PriceCents,
+		Active:                  true,
+		Description:             strings.TrimSpace(in.Description),
+		LateFeeBasisPoints:      in.LateFeeBps,
+		LateInterestBasisPoints: in.LateInterestBps,
+		MaxFreezeDays:           in.MaxFreezeDays,
+		Installments:            in.Installments,
+	}
+	if in.Active != nil {
+		plan.Active = *in.Active
+	}
+	if plan.Name == "" {
+		return domain.Plan{}, "name e obrigatorio"
+	}
+	if plan.DurationDays <= 0 {
+		return domain.Plan{}, "duration_days deve ser maior que zero"
+	}
+	if plan.PriceCents < 0 {
+		return domain.Plan{}, "price_cents nao pode ser negativo"
+	}
+	if plan.LateFeeBasisPoints < 0 || plan.LateFeeBasisPoints > domain.MaxBasisPoints {
+		return domain.Plan{}, "late_fee_bps deve estar entre 0 e 10000"
+	}
+	if plan.LateInterestBasisPoints < 0 || plan.LateInterestBasisPoints > domain.MaxBasisPoints {
+		return domain.Plan{}, "late_interest_bps deve estar entre 0 e 10000"
+	}
+	if plan.MaxFreezeDays < 0 || plan.MaxFreezeDays > 366 {
+		return domain.Plan{}, "max_freeze_days deve estar entre 0 e 366"
+	}
+	if plan.Installments == 0 {
+		plan.Installments = 1
+	}
+	if limit := domain.MaxInstallmentsFor(plan.DurationDays); plan.Installments < 1 || plan.Installments > limit {
+		return domain.Plan{}, "installments deve estar entre 1 e " + strconv.Itoa(limit)
+	}
+	return plan, ""
+}
+
+// APIPlansList lista os planos ativos.
+func (h *Handler) APIPlansList(w http.ResponseWriter, r *http.Request) {
+	if h.services.Plans == nil {
+		writeAPIUnavailable(w)
+		return
+	}
+	page, err := parseAPIKeysetPage(r)
+	if err != nil {
+		writeAPIBadRequest(w, err.Error())
+		return
+	}
+
+	plans, err := h.services.Plans.ListActivePage(r.Context(), page.keyset())
+	if err != nil {
+		writeAPIServiceError(w, r, err)
+		return
+	}
+	plans, next := apiKeysetPageOf(plans, page, func(plan domain.Plan) ports.PageCursor {
+		return ports.PageCursor{CreatedAt: plan.CreatedAt, ID: plan.ID}
+	})
+	items := make([]apiPlan, 0, len(plans))
+	for _, plan := range plans {
+		items = append(items, newAPIPlan(plan))
+	}
+	writeAPIJSON(w, http.StatusOK, apiPage{Data: items, NextCursor: next})
+}
+
+func (h *Handler) APIPlansGet(w http.ResponseWriter, r *http.Request) {
+	if h.services.Plans == nil {
+		writeAPIUnavailable(w)
+		return
+	}
+	plan, err := h.services.Plans.FindByID(r.Context(), chi.URLParam(r, "planID"))
+	if err != nil {
+		writeAPIServiceError(w, r, err)
+		return
+	}
+	writeAPIJSON(w, http.StatusOK, apiResource{Data: newAPIPlan(plan)})
+}
+
+func (h *Handler) APIPlansCreate(w http.ResponseWriter, r *http.Request) {
+	if h.services.Plans == nil {
+		writeAPIUnavailable(w)
+		return
+	}
+	var input apiPlanInput
+	if !decodeAPIJSON(w, r, &input) {
+		return
+	}
+	plan, problem := input.plan()
+	if problem != "" {
+		writeAPIBadRequest(w, problem)
+		return
+	}
+
+	created, err := h.services.Plans.Create(r.Context(), plan)
+	if err != nil {
+		writeAPIServiceError(w, r, err)
+		return
+	}
+	writeAPIJSON(w, http.StatusCreated, apiResource{Data: newAPIPlan(created)})
+}
+
+func (h *Handler) APIPlansUpdate(w http.ResponseWriter, r *http.Request) {
+	if h.services.Plans == nil {
+		writeAPIUnavailable(w)
+		return
+	}
+	var input apiPlanInput
+	if !decodeAPIJSON(w, r, &input) {
+		return
+	}
+	plan, problem := input.plan()
+	if problem != "" {
+		writeAPIBadRequest(w, problem)
+		return
+	}
+
+	plan.ID = chi.URLParam(r, "planID")
+	updated, err := h.services.Plans.Update(r.Context(), plan)
+	if err != nil {
+		writeAPIServiceError(w, r, err)
+		return
+	}
+	writeAPIJSON(w, http.StatusOK, apiResource{Data: newAPIPlan(updated)})
+}
+
+// APIPlansDelete desativa o plano e encerra as assinaturas dele.
+func (h *Handler) APIPlansDelete(w http.ResponseWriter, r *http.Request) {
+	if h.services.Plans == nil {
+		writeAPIUnavailable(w)
+		return
+	}
+	updated, err := h.services.Plans.Deactivate(r.Context(), chi.URLParam(r, "planID"))
+	if err != nil {
+		writeAPIServiceError(w, r, err)
+		return
+	}
+	writeAPIJSON(w, http.StatusOK, apiResource{Data: newAPIPlan(updated)})
+}
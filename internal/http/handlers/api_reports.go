@@ -0,0 +1,174 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/PabloPavan/jaiu/internal/domain"
+)
+
+const (
+	apiDefaultRevenueBuckets = 6
+	apiMaxRevenueBuckets     = 36
+	apiDefaultDueDays        = 7
+	apiMaxDueDays            = 90
+)
+
+// apiRevenueBucket traz o recebido no periodo (total_cents) e o faturado nas
+// competencias iniciadas nele, com o valor cheio, os descontos e o liquido.
+type apiRevenueBucket struct {
+	Start            string `json:"start" openapi:"date"`
+	End              string `json:"end" openapi:"date"`
+	TotalCents       int64  `json:"total_cents"`
+	GrossBilledCents int64  `json:"gross_billed_cents"`
+	DiscountCents    int64  `json:"discount_cents"`
+	NetBilledCents   int64  `json:"net_billed_cents"`
+}
+
+type apiStatusCount struct {
+	Status domain.StudentStatus `json:"status"`
+	Total  int64                `json:"total"`
+}
+
+type apiDueSubscription struct {
+	SubscriptionID string `json:"subscription_id"`
+	StudentID      string `json:"student_id"`
+	PlanID         string `json:"plan_id"`
+	StudentName    string `json:"student_name"`
+	PlanName       string `json:"plan_name"`
+	EndDate        string `json:"end_date" openapi:"date"`
+	DaysOverdue    *int   `json:"days_overdue,omitempty"`
+}
+
+// APIReportsRevenue aceita period (daily, weekly, monthly) e buckets.
+func (h *Handler) APIReportsRevenue(w http.ResponseWriter, r *http.Request) {
+	if h.services.Reports == nil {
+		writeAPIUnavailable(w)
+		return
+	}
+	period := domain.ReportMonthly
+	if raw := strings.TrimSpace(r.URL.Query().Get("period")); raw != "" {
+		period = domain.ReportPeriod(strings.ToLower(raw))
+		if !period.IsValid() {
+			writeAPIBadRequest(w, "period invalido")
+			return
+		}
+	}
+	buckets, ok := parseAPIIntParam(r, "buckets", apiDefaultRevenueBuckets, apiMaxRevenueBuckets)
+	if !ok {
+		writeAPIBadRequest(w, "buckets deve estar entre 1 e "+strconv.Itoa(apiMaxRevenueBuckets))
+		return
+	}
+
+	series, err := h.services.Reports.RevenueSeries(r.Context(), period, time.Now(), buckets)
+	if err != nil {
+		writeAPIServiceError(w, r, err)
+		return
+	}
+	items := make([]apiRevenueBucket, 0, len(series))
+	for _, summary := range series {
+		items = append(items, apiRevenueBucket{
+			Start:            formatAPIDate(summary.Start),
+			End:              formatAPIDate(summary.End),
+			TotalCents:       summary.TotalCents,
+			GrossBilledCents: summary.GrossBilledCents,
+			DiscountCents:    summary.DiscountCents,
+			NetBilledCents:   summary.GrossBilledCents - summary.DiscountCents,
+		})
+	}
+	writeAPIJSON(w, http.StatusOK, apiResource{Data: items})
+}
+
+func (h *Handler) APIReportsStudentsByStatus(w http.ResponseWriter, r *http.Request) {
+	if h.services.Reports == nil {
+		writeAPIUnavailable(w)
+		return
+	}
+	statuses, err := h.services.Reports.StudentsByStatus(r.Context())
+	if err != nil {
+		writeAPIServiceError(w, r, err)
+		return
+	}
+	items := make([]apiStatusCount, 0, len(statuses))
+	for _, summary := range statuses {
+		items = append(items, apiStatusCount{Status: summary.Status, Total: summary.Total})
+	}
+	writeAPIJSON(w, http.StatusOK, apiResource{Data: items})
+}
+
+func (h *Handler) APIReportsDelinquent(w http.ResponseWriter, r *http.Request) {
+	if h.services.Reports == nil {
+		writeAPIUnavailable(w)
+		return
+	}
+	delinquents, err := h.services.Reports.DelinquentSubscriptions(r.Context(), apiToday())
+	if err != nil {
+		writeAPIServiceError(w, r, err)
+		return
+	}
+	items := make([]apiDueSubscription, 0, len(delinquents))
+	for _, item := range delinquents {
+		daysOverdue := item.DaysOverdue
+		items = append(items, apiDueSubscription{
+			SubscriptionID: item.SubscriptionID,
+			StudentID:      item.StudentID,
+			PlanID:         item.PlanID,
+			StudentName:    item.StudentName,
+			PlanName:       item.PlanName,
+			EndDate:        formatAPIDate(item.EndDate),
+			DaysOverdue:    &daysOverdue,
+		})
+	}
+	writeAPIJSON(w, http.StatusOK, apiResource{Data: items})
+}
+
+// APIReportsUpcomingDue lista as assinaturas que vencem nos proximos days dias.
+func (h *Handler) APIReportsUpcomingDue(w http.ResponseWriter, r *http.Request) {
+	if h.services.Reports == nil {
+		writeAPIUnavailable(w)
+		return
+	}
+	days, ok := parseAPIIntParam(r, "days", apiDefaultDueDays, apiMaxDueDays)
+	if !ok {
+		writeAPIBadRequest(w, "days deve estar entre 1 e "+strconv.Itoa(apiMaxDueDays))
+		return
+	}
+
+	today := apiToday()
+	upcoming, err := h.services.Reports.UpcomingDue(r.Context(), today, today.AddDate(0, 0, days))
+	if err != nil {
+		writeAPIServiceError(w, r, err)
+		return
+	}
+	items := make([]apiDueSubscription, 0, len(upcoming))
+	for _, item := range upcoming {
+		items = append(items, apiDueSubscription{
+			SubscriptionID: item.SubscriptionID,
+			StudentID:      item.StudentID,
+			PlanID:         item.PlanID,
+			StudentName:    item.StudentName,
+			PlanName:       item.PlanName,
+			EndDate:        formatAPIDate(item.EndDate),
+		})
+	}
+	writeAPIJSON(w, http.StatusOK, apiResource{Data: items})
+}
+
+func parseAPIIntParam(r *http.Request, key string, fallback, max int) (int, bool) {
+	raw := strings.TrimSpace(r.URL.Query().Get(key))
+	if raw == "" {
+		return fallback, true
+	}
+	value, err := strconv.Atoi(raw)
+	if err != nil || value < 1 || value > max {
+		return 0, false
+	}
+	return value, true
+}
+
+func apiToday() time.Time {
+	now := time.Now()
+	return time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+}
@@ -0,0 +1,210 @@
+package handlers
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/PabloPavan/jaiu/internal/domain"
+	"github.com/PabloPavan/jaiu/internal/ports"
+	"github.com/go-chi/chi/v5"
+)
+
+type apiStudent struct {
+	ID             string               `json:"id"`
+	FullName       string               `json:"full_name"`
+	BirthDate      *string              `json:"birth_date" openapi:"date"`
+	Gender         string               `json:"gender"`
+	Phone          string               `json:"phone"`
+	Email          string               `json:"email"`
+	CPF            string               `json:"cpf"`
+	Address        string               `json:"address"`
+	Notes          string               `json:"notes"`
+	PhotoObjectKey string               `json:"photo_object_key"`
+	Status         domain.StudentStatus `json:"status"`
+	CreatedAt      time.Time            `json:"created_at"`
+	UpdatedAt      time.Time            `json:"updated_at"`
+}
+
+// apiStudentInput e o corpo de POST e PUT; no PUT substitui todos os campos.
+type apiStudentInput struct {
+	FullName       string               `json:"full_name" openapi:"required"`
+	BirthDate      string               `json:"birth_date" openapi:"date"`
+	Gender         string               `json:"gender"`
+	Phone          string               `json:"phone"`
+	Email          string               `json:"email"`
+	CPF            string               `json:"cpf"`
+	Address        string               `json:"address"`
+	Notes          string               `json:"notes"`
+	PhotoObjectKey string               `json:"photo_object_key"`
+	Status         domain.StudentStatus `json:"status"`
+}
+
+func newAPIStudent(student domain.Student) apiStudent {
+	return apiStudent{
+		ID:             student.ID,
+		FullName:       student.FullName,
+		BirthDate:      formatAPIDatePtr(student.BirthDate),
+		Gender:         student.Gender,
+		Phone:          student.Phone,
+		Email:          student.Email,
+		CPF:            student.CPF,
+		Address:        student.Address,
+		Notes:          student.Notes,
+		PhotoObjectKey: student.PhotoObjectKey,
+		Status:         student.Status,
+		CreatedAt:      student.CreatedAt,
+		UpdatedAt:      student.UpdatedAt,
+	}
+}
+
+func (in apiStudentInput) student() (domain.Student, string) {
+	student := domain.Student{
+		FullName:       strings.TrimSpace(in.FullName),
+		Gender:         strings.TrimSpace(in.Gender),
+		Phone:          strings.TrimSpace(in.Phone),
+		Email:          strings.TrimSpace(in.Email),
+		CPF:            strings.TrimSpace(in.CPF),
+		Address:        strings.TrimSpace(in.Address),
+		Notes:          strings.TrimSpace(in.Notes),
+		PhotoObjectKey: strings.TrimSpace(in.PhotoObjectKey),
+		Status:         domain.StudentStatus(strings.TrimSpace(string(in.Status))),
+	}
+	if student.FullName == "" {
+		return domain.Student{}, "full_name e obrigatorio"
+	}
+	if student.Status == "" {
+		student.Status = domain.StudentActive
+	}
+	if !student.Status.IsValid() {
+		return domain.Student{}, "status invalido"
+	}
+	if in.BirthDate != "" {
+		birthDate, err := parseAPIDate(in.BirthDate)
+		if err != nil {
+			return domain.Student{}, "birth_date deve estar no formato AAAA-MM-DD"
+		}
+		student.BirthDate = &birthDate
+	}
+	return student, ""
+}
+
+// APIStudentsList aceita q, status (lista separada por virgulas), limit e
+// cursor.
+func (h *Handler) APIStudentsList(w http.ResponseWriter, r *http.Request) {
+	if h.services.Students == nil {
+		writeAPIUnavailable(w)
+		return
+	}
+	page, err := parseAPIKeysetPage(r)
+	if err != nil {
+		writeAPIBadRequest(w, err.Error())
+		return
+	}
+
+	filter := ports.StudentFilter{
+		Query: strings.TrimSpace(r.URL.Query().Get("q")),
+	}
+	if raw := strings.TrimSpace(r.URL.Query().Get("status")); raw != "" {
+		for _, value := range strings.Split(raw, ",") {
+			status := domain.StudentStatus(strings.TrimSpace(value))
+			if !status.IsValid() {
+				writeAPIBadRequest(w, "status invalido: "+value)
+				return
+			}
+			filter.Statuses = append(filter.Statuses, status)
+		}
+	}
+
+	students, err := h.services.Students.SearchPage(r.Context(), filter, page.keyset())
+	if err != nil {
+		writeAPIServiceError(w, r, err)
+		return
+	}
+
+	students, next := apiKeysetPageOf(students, page, func(student domain.Student) ports.PageCursor {
+		return ports.PageCursor{CreatedAt: student.CreatedAt, ID: student.ID}
+	})
+	items := make([]apiStudent, 0, len(students))
+	ids := make([]string, 0, len(students))
+	for _, student := range students {
+		items = append(items, newAPIStudent(student))
+		ids = append(ids, student.ID)
+	}
+	h.services.Students.RecordViews(r.Context(), "api", ids...)
+	writeAPIJSON(w, http.StatusOK, apiPage{Data: items, NextCursor: next})
+}
+
+func (h *Handler) APIStudentsGet(w http.ResponseWriter, r *http.Request) {
+	if h.services.Students == nil {
+		writeAPIUnavailable(w)
+		return
+	}
+	student, err := h.services.Students.FindByID(r.Context(), chi.URLParam(r, "studentID"))
+	if err != nil {
+		writeAPIServiceError(w, r, err)
+		return
+	}
+	writeAPIJSON(w, http.StatusOK, apiResource{Data: newAPIStudent(student)})
+}
+
+func (h *Handler) APIStudentsCreate(w http.ResponseWriter, r *http.Request) {
+	if h.services.Students == nil {
+		writeAPIUnavailable(w)
+		return
+	}
+	var input apiStudentInput
+	if !decodeAPIJSON(w, r, &input) {
+		return
+	}
+	student, problem := input.student()
+	if problem != "" {
+		writeAPIBadRequest(w, problem)
+		return
+	}
+
+	created, err := h.services.Students.Register(r.Context(), student)
+	if err != nil {
+		writeAPIServiceError(w, r, err)
+		return
+	}
+	writeAPIJSON(w, http.StatusCreated, apiResource{Data: newAPIStudent(created)})
+}
+
+func (h *Handler) APIStudentsUpdate(w http.ResponseWriter, r *http.Request) {
+	if h.services.Students == nil {
+		writeAPIUnavailable(w)
+		return
+	}
+	var input apiStudentInput
+	if !decodeAPIJSON(w, r, &input) {
+		return
+	}
+	student, problem := input.student()
+	if problem != "" {
+		writeAPIBadRequest(w, problem)
+		return
+	}
+
+	student.ID = chi.URLParam(r, "studentID")
+	updated, err := h.services.Students.Update(r.Context(), student)
+	if err != nil {
+		writeAPIServiceError(w, r, err)
+		return
+	}
+	writeAPIJSON(w, http.StatusOK, apiResource{Data: newAPIStudent(updated)})
+}
+
+// APIStudentsDelete desativa o aluno; o registro e mantido.
+func (h *Handler) APIStudentsDelete(w http.ResponseWriter, r *http.Request) {
+	if h.services.Students == nil {
+		writeAPIUnavailable(w)
+		return
+	}
+	updated, err := h.services.Students.Deactivate(r.Context(), chi.URLParam(r, "studentID"))
+	if err != nil {
+		writeAPIServiceError(w, r, err)
+		return
+	}
+	writeAPIJSON(w, http.StatusOK, apiResource{Data: newAPIStudent(updated)})
+}
@@ -0,0 +1,114 @@
+package handlers
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/PabloPavan/jaiu/internal/domain"
+	"github.com/PabloPavan/jaiu/internal/ports"
+	"github.com/go-chi/chi/v5"
+)
+
+type apiSubscriptionFreeze struct {
+	ID             string    `json:"id"`
+	SubscriptionID string    `json:"subscription_id"`
+	StartDate      string    `json:"start_date" openapi:"date"`
+	EndDate        string    `json:"end_date" openapi:"date"`
+	Days           int       `json:"days"`
+	Reason         string    `json:"reason"`
+	CreatedAt      time.Time `json:"created_at"`
+}
+
+// apiSubscriptionFreezeInput recebe o intervalo do trancamento; as duas datas
+// sao inclusivas.
+type apiSubscriptionFreezeInput struct {
+	StartDate string `json:"start_date" openapi:"required,date"`
+	EndDate   string `json:"end_date" openapi:"required,date"`
+	Reason    string `json:"reason" openapi:"required"`
+}
+
+func newAPISubscriptionFreeze(freeze domain.SubscriptionFreeze) apiSubscriptionFreeze {
+	return apiSubscriptionFreeze{
+		ID:             freeze.ID,
+		SubscriptionID: freeze.SubscriptionID,
+		StartDate:      formatAPIDate(freeze.StartDate),
+		EndDate:        formatAPIDate(freeze.EndDate),
+		Days:           freeze.Days(),
+		Reason:         freeze.Reason,
+		CreatedAt:      freeze.CreatedAt,
+	}
+}
+
+func (in apiSubscriptionFreezeInput) freeze() (domain.SubscriptionFreeze, string) {
+	startDate, err := parseAPIDate(in.StartDate)
+	if err != nil {
+		return domain.SubscriptionFreeze{}, "start_date deve estar no formato AAAA-MM-DD"
+	}
+	endDate, err := parseAPIDate(in.EndDate)
+	if err != nil {
+		return domain.SubscriptionFreeze{}, "end_date deve estar no formato AAAA-MM-DD"
+	}
+	reason := strings.TrimSpace(in.Reason)
+	if reason == "" {
+		return domain.SubscriptionFreeze{}, "reason e obrigatorio"
+	}
+	return domain.SubscriptionFreeze{StartDate: startDate, EndDate: endDate, Reason: reason}, ""
+}
+
+func (h *Handler) APISubscriptionFreezes(w http.ResponseWriter, r *http.Request) {
+	if h.services.Subscriptions == nil {
+		writeAPIUnavailable(w)
+		return
+	}
+	page, err := parseAPIKeysetPage(r)
+	if err != nil {
+		writeAPIBadRequest(w, err.Error())
+		return
+	}
+
+	subscription, err := h.services.Subscriptions.FindByID(r.Context(), chi.URLParam(r, "subscriptionID"))
+	if err != nil {
+		writeAPIServiceError(w, r, err)
+		return
+	}
+	freezes, err := h.services.Subscriptions.ListFreezesPage(r.Context(), subscription.ID, page.keyset())
+	if err != nil {
+		writeAPIServiceError(w, r, err)
+		return
+	}
+	freezes, next := apiKeysetPageOf(freezes, page, func(freeze domain.SubscriptionFreeze) ports.PageCursor {
+		return ports.PageCursor{CreatedAt: freeze.CreatedAt, ID: freeze.ID}
+	})
+	items := make([]apiSubscriptionFreeze, 0, len(freezes))
+	for _, freeze := range freezes {
+		items = append(items, newAPISubscriptionFreeze(freeze))
+	}
+	writeAPIJSON(w, http.StatusOK, apiPage{Data: items, NextCursor: next})
+}
+
+// APISubscriptionFreezesCreate tranca a assinatura, adiando os vencimentos e o
+// fim pelos dias do trancamento.
+func (h *Handler) APISubscriptionFreezesCreate(w http.ResponseWriter, r *http.Request) {
+	if h.services.Subscriptions == nil {
+		writeAPIUnavailable(w)
+		return
+	}
+	var input apiSubscriptionFreezeInput
+	if !decodeAPIJSON(w, r, &input) {
+		return
+	}
+	freeze, problem := input.freeze()
+	if problem != "" {
+		writeAPIBadRequest(w, problem)
+		return
+	}
+
+	freeze.SubscriptionID = chi.URLParam(r, "subscriptionID")
+	created, err := h.services.Subscriptions.Freeze(r.Context(), freeze)
+	if err != nil {
+		writeAPIServiceError(w, r, err)
+		return
+	}
+	writeAPIJSON(w, http.StatusCreated, apiResource{Data: newAPISubscriptionFreeze(created)})
+}
@@ -0,0 +1,314 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/PabloPavan/jaiu/internal/domain"
+	"github.com/PabloPavan/jaiu/internal/ports"
+	"github.com/go-chi/chi/v5"
+)
+
+type apiSubscription struct {
+	ID                     string                    `json:"id"`
+	StudentID              string                    `json:"student_id"`
+	PlanID                 string                    `json:"plan_id"`
+	StartDate              string                    `json:"start_date" openapi:"date"`
+	EndDate                string                    `json:"end_date" openapi:"date"`
+	Status                 domain.SubscriptionStatus `json:"status"`
+	PriceCents             int64                     `json:"price_cents"`
+	PaymentDay             int                       `json:"payment_day"`
+	AutoRenew              bool                      `json:"auto_renew"`
+	CouponID               *string                   `json:"coupon_id"`
+	Discount               *apiDiscount              `json:"discount"`
+	PreviousSubscriptionID *string                   `json:"previous_subscription_id"`
+	CreatedAt              time.Time                 `json:"created_at"`
+	UpdatedAt              time.Time                 `json:"updated_at"`
+}
+
+// apiDiscount e o desconto copiado do cupom. Value esta em centesimos de ponto
+// percentual para percent e em centavos para fixed.
+type apiDiscount struct {
+	Kind     domain.DiscountKind     `json:"kind"`
+	Value    int64                   `json:"value"`
+	Duration domain.DiscountDuration `json:"duration"`
+	Periods  int                     `json:"periods"`
+}
+
+// apiSubscriptionInput deixa para o servico os valores padrao: datas, preco e
+// dia de pagamento vazios sao derivados do plano e da data de inicio.
+type apiSubscriptionInput struct {
+	StudentID  string                    `json:"student_id" openapi:"required"`
+	PlanID     string                    `json:"plan_id" openapi:"required"`
+	StartDate  string                    `json:"start_date" openapi:"date"`
+	EndDate    string                    `json:"end_date" openapi:"date"`
+	Status     domain.SubscriptionStatus `json:"status"`
+	PriceCents int64                     `json:"price_cents"`
+	PaymentDay int                       `json:"payment_day"`
+	AutoRenew  bool                      `json:"auto_renew"`
+	CouponCode string                    `json:"coupon_code"`
+}
+
+type apiChangePlanInput struct {
+	PlanID string `json:"plan_id" openapi:"required"`
+}
+
+func newAPISubscription(subscription domain.Subscription) apiSubscription {
+	item := apiSubscription{
+		ID:         subscription.ID,
+		StudentID:  subscription.StudentID,
+		PlanID:     subscription.PlanID,
+		StartDate:  formatAPIDate(subscription.StartDate),
+		EndDate:    formatAPIDate(subscription.EndDate),
+		Status:     subscription.Status,
+		PriceCents: subscription.PriceCents,
+		PaymentDay: subscription.PaymentDay,
+		AutoRenew:  subscription.AutoRenew,
+		CreatedAt:  subscription.CreatedAt,
+		UpdatedAt:  subscription.UpdatedAt,
+	}
+	if subscription.CouponID != "" {
+		couponID := subscription.CouponID
+		item.CouponID = &couponID
+	}
+	if subscription.PreviousSubscriptionID != "" {
+		previousID := subscription.PreviousSubscriptionID
+		item.PreviousSubscriptionID = &previousID
+	}
+	if !subscription.Discount.IsZero() {
+		item.Discount = &apiDiscount{
+			Kind:     subscription.Discount.Kind,
+			Value:    subscription.Discount.Value,
+			Duration: subscription.Discount.Duration,
+			Periods:  subscription.Discount.Periods,
+		}
+	}
+	return item
+}
+
+func apiSubscriptionCursor(subscription domain.Subscription) ports.PageCursor {
+	return ports.PageCursor{CreatedAt: subscription.CreatedAt, ID: subscription.ID}
+}
+
+func newAPISubscriptions(subscriptions []domain.Subscription) []apiSubscription {
+	items := make([]apiSubscription, 0, len(subscriptions))
+	for _, subscription := range subscriptions {
+		items = append(items, newAPISubscription(subscription))
+	}
+	return items
+}
+
+func (in apiSubscriptionInput) subscription() (domain.Subscription, string) {
+	subscription := domain.Subscription{
+		StudentID:  strings.TrimSpace(in.StudentID),
+		PlanID:     strings.TrimSpace(in.PlanID),
+		Status:     domain.SubscriptionStatus(strings.TrimSpace(string(in.Status))),
+		PriceCents: in.PriceCents,
+		PaymentDay: in.PaymentDay,
+		AutoRenew:  in.AutoRenew,
+	}
+	if subscription.StudentID == "" {
+		return domain.Subscription{}, "student_id e obrigatorio"
+	}
+	if subscription.PlanID == "" {
+		return domain.Subscription{}, "plan_id e obrigatorio"
+	}
+	if subscription.Status != "" && !subscription.Status.IsValid() {
+		return domain.Subscription{}, "status invalido"
+	}
+	if subscription.PriceCents < 0 {
+		return domain.Subscription{}, "price_cents nao pode ser negativo"
+	}
+	if in.StartDate != "" {
+		startDate, err := parseAPIDate(in.StartDate)
+		if err != nil {
+			return domain.Subscription{}, "start_date deve estar no formato AAAA-MM-DD"
+		}
+		subscription.StartDate = startDate
+	}
+	if in.EndDate != "" {
+		endDate, err := parseAPIDate(in.EndDate)
+		if err != nil {
+			return domain.Subscription{}, "end_date deve estar no formato AAAA-MM-DD"
+		}
+		subscription.EndDate = endDate
+	}
+	return subscription, ""
+}
+
+// resolveAPICoupon troca o coupon_code do corpo pelo cupom correspondente.
+func (h *Handler) resolveAPICoupon(w http.ResponseWriter, r *http.Request, code string, subscription *domain.Subscription) bool {
+	code = strings.TrimSpace(code)
+	if code != "" && h.services.Coupons == nil {
+		writeAPIUnavailable(w)
+		return false
+	}
+	couponID, err := h.couponIDForCode(r.Context(), code)
+	if err != nil {
+		if errors.Is(err, ports.ErrNotFound) {
+			writeAPIBadRequest(w, "coupon_code nao encontrado")
+			return false
+		}
+		writeAPIServiceError(w, r, err)
+		return false
+	}
+	subscription.CouponID = couponID
+	return true
+}
+
+// APISubscriptionsList lista as assinaturas ativas que vencem entre due_from e
+// due_to.
+func (h *Handler) APISubscriptionsList(w http.ResponseWriter, r *http.Request) {
+	if h.services.Subscriptions == nil {
+		writeAPIUnavailable(w)
+		return
+	}
+	page, err := parseAPIKeysetPage(r)
+	if err != nil {
+		writeAPIBadRequest(w, err.Error())
+		return
+	}
+	from, to, err := parseAPIRange(r, "due_from", "due_to")
+	if err != nil {
+		writeAPIBadRequest(w, err.Error())
+		return
+	}
+
+	subscriptions, err := h.services.Subscriptions.DueBetweenPage(r.Context(), from, to, page.keyset())
+	if err != nil {
+		writeAPIServiceError(w, r, err)
+		return
+	}
+	subscriptions, next := apiKeysetPageOf(subscriptions, page, apiSubscriptionCursor)
+	writeAPIJSON(w, http.StatusOK, apiPage{Data: newAPISubscriptions(subscriptions), NextCursor: next})
+}
+
+func (h *Handler) APIStudentSubscriptions(w http.ResponseWriter, r *http.Request) {
+	if h.services.Subscriptions == nil {
+		writeAPIUnavailable(w)
+		return
+	}
+	page, err := parseAPIKeysetPage(r)
+	if err != nil {
+		writeAPIBadRequest(w, err.Error())
+		return
+	}
+
+	subscriptions, err := h.services.Subscriptions.ListByStudentPage(r.Context(), chi.URLParam(r, "studentID"), page.keyset())
+	if err != nil {
+		writeAPIServiceError(w, r, err)
+		return
+	}
+	subscriptions, next := apiKeysetPageOf(subscriptions, page, apiSubscriptionCursor)
+	writeAPIJSON(w, http.StatusOK, apiPage{Data: newAPISubscriptions(subscriptions), NextCursor: next})
+}
+
+func (h *Handler) APISubscriptionsGet(w http.ResponseWriter, r *http.Request) {
+	if h.services.Subscriptions == nil {
+		writeAPIUnavailable(w)
+		return
+	}
+	subscription, err := h.services.Subscriptions.FindByID(r.Context(), chi.URLParam(r, "subscriptionID"))
+	if err != nil {
+		writeAPIServiceError(w, r, err)
+		return
+	}
+	writeAPIJSON(w, http.StatusOK, apiResource{Data: newAPISubscription(subscription)})
+}
+
+func (h *Handler) APISubscriptionsCreate(w http.ResponseWriter, r *http.Request) {
+	if h.services.Subscriptions == nil {
+		writeAPIUnavailable(w)
+		return
+	}
+	var input apiSubscriptionInput
+	if !decodeAPIJSON(w, r, &input) {
+		return
+	}
+	subscription, problem := input.subscription()
+	if problem != "" {
+		writeAPIBadRequest(w, problem)
+		return
+	}
+	if !h.resolveAPICoupon(w, r, input.CouponCode, &subscription) {
+		return
+	}
+
+	created, err := h.services.Subscriptions.Create(r.Context(), subscription)
+	if err != nil {
+		writeAPIServiceError(w, r, err)
+		return
+	}
+	writeAPIJSON(w, http.StatusCreated, apiResource{Data: newAPISubscription(created)})
+}
+
+func (h *Handler) APISubscriptionsUpdate(w http.ResponseWriter, r *http.Request) {
+	if h.services.Subscriptions == nil {
+		writeAPIUnavailable(w)
+		return
+	}
+	var input apiSubscriptionInput
+	if !decodeAPIJSON(w, r, &input) {
+		return
+	}
+	subscription, problem := input.subscription()
+	if problem != "" {
+		writeAPIBadRequest(w, problem)
+		return
+	}
+	if subscription.StartDate.IsZero() {
+		writeAPIBadRequest(w, "start_date e obrigatorio")
+		return
+	}
+	if !h.resolveAPICoupon(w, r, input.CouponCode, &subscription) {
+		return
+	}
+
+	subscription.ID = chi.URLParam(r, "subscriptionID")
+	updated, err := h.services.Subscriptions.Update(r.Context(), subscription)
+	if err != nil {
+		writeAPIServiceError(w, r, err)
+		return
+	}
+	writeAPIJSON(w, http.StatusOK, apiResource{Data: newAPISubscription(updated)})
+}
+
+func (h *Handler) APISubscriptionsCancel(w http.ResponseWriter, r *http.Request) {
+	if h.services.Subscriptions == nil {
+		writeAPIUnavailable(w)
+		return
+	}
+	canceled, err := h.services.Subscriptions.Cancel(r.Context(), chi.URLParam(r, "subscriptionID"))
+	if err != nil {
+		writeAPIServiceError(w, r, err)
+		return
+	}
+	writeAPIJSON(w, http.StatusOK, apiResource{Data: newAPISubscription(canceled)})
+}
+
+// APISubscriptionsChangePlan encerra a assinatura e devolve a nova, criada com
+// o plano informado.
+func (h *Handler) APISubscriptionsChangePlan(w http.ResponseWriter, r *http.Request) {
+	if h.services.Subscriptions == nil {
+		writeAPIUnavailable(w)
+		return
+	}
+	var input apiChangePlanInput
+	if !decodeAPIJSON(w, r, &input) {
+		return
+	}
+	planID := strings.TrimSpace(input.PlanID)
+	if planID == "" {
+		writeAPIBadRequest(w, "plan_id e obrigatorio")
+		return
+	}
+
+	created, err := h.services.Subscriptions.ChangePlan(r.Context(), chi.URLParam(r, "subscriptionID"), planID)
+	if err != nil {
+		writeAPIServiceError(w, r, err)
+		return
+	}
+	writeAPIJSON(w, http.StatusCreated, apiResource{Data: newAPISubscription(created)})
+}
@@ -0,0 +1,175 @@
+package handlers
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	httpmw "github.com/PabloPavan/jaiu/internal/http/middleware"
+	"github.com/PabloPavan/jaiu/internal/ports"
+)
+
+// Testa a leitura de limit e a rejeicao de valores fora da faixa.
+func TestParseAPIKeysetPageLimit(t *testing.T) {
+	page, err := parseAPIKeysetPage(httptest.NewRequest(http.MethodGet, "/api/v1/plans", nil))
+	if err != nil || page.Limit != apiDefaultLimit || page.After != (ports.PageCursor{}) {
+		t.Fatalf("expected default page, got %#v (%v)", page, err)
+	}
+
+	for _, query := range []string{"limit=0", "limit=201", "limit=abc", "cursor=xyz"} {
+		if _, err := parseAPIKeysetPage(httptest.NewRequest(http.MethodGet, "/api/v1/plans?"+query, nil)); err == nil {
+			t.Fatalf("expected error for %q", query)
+		}
+	}
+}
+
+// Testa que o cursor keyset guarda created_at e id e nao aceita o cursor de
+// deslocamento.
+func TestAPIKeysetCursorRoundTrip(t *testing.T) {
+	after := ports.PageCursor{CreatedAt: time.Date(2024, 3, 5, 10, 30, 0, 123456000, time.UTC), ID: "5f0c6a1e-0000-4000-8000-000000000001"}
+	cursor := encodeAPIKeysetCursor(after)
+	if strings.Contains(cursor, after.ID) {
+		t.Fatalf("expected opaque cursor, got %q", cursor)
+	}
+	decoded, err := decodeAPIKeysetCursor(cursor)
+	if err != nil || !decoded.CreatedAt.Equal(after.CreatedAt) || decoded.ID != after.ID {
+		t.Fatalf("unexpected cursor: %#v (%v)", decoded, err)
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/plans?limit=5&cursor="+cursor, nil)
+	page, err := parseAPIKeysetPage(req)
+	if err != nil || page.Limit != 5 || page.After != decoded {
+		t.Fatalf("unexpected page: %#v (%v)", page, err)
+	}
+	if keyset := page.keyset(); keyset.Limit != 6 || keyset.After != decoded {
+		t.Fatalf("expected one extra row in the request, got %#v", keyset)
+	}
+
+	for _, raw := range []string{base64.RawURLEncoding.EncodeToString([]byte("o:20")), "not-base64!", base64.RawURLEncoding.EncodeToString([]byte("k:abc:id")), base64.RawURLEncoding.EncodeToString([]byte("k:10:"))} {
+		if _, err := parseAPIKeysetPage(httptest.NewRequest(http.MethodGet, "/api/v1/plans?cursor="+raw, nil)); err == nil {
+			t.Fatalf("expected error for cursor %q", raw)
+		}
+	}
+}
+
+// Testa que o item extra devolvido pelo banco vira o cursor da pagina seguinte.
+func TestAPIKeysetPageOf(t *testing.T) {
+	created := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	cursor := func(id int) ports.PageCursor {
+		return ports.PageCursor{CreatedAt: created.Add(time.Duration(id) * time.Minute), ID: fmt.Sprint(id)}
+	}
+
+	got, next := apiKeysetPageOf([]int{1, 2, 3}, apiPageRequest{Limit: 2}, cursor)
+	if fmt.Sprint(got) != "[1 2]" || next == "" {
+		t.Fatalf("unexpected first page: %v %q", got, next)
+	}
+	after, err := decodeAPIKeysetCursor(next)
+	if err != nil || after != cursor(2) {
+		t.Fatalf("expected cursor of the last item, got %#v (%v)", after, err)
+	}
+	got, next = apiKeysetPageOf([]int{3}, apiPageRequest{Limit: 2, After: after}, cursor)
+	if fmt.Sprint(got) != "[3]" || next != "" {
+		t.Fatalf("unexpected last page: %v %q", got, next)
+	}
+}
+
+// Testa o mapeamento dos erros dos servicos para status e codigo da API.
+func TestWriteAPIServiceError(t *testing.T) {
+	tests := []struct {
+		name   string
+		method string
+		err    error
+		status int
+		code   string
+	}{
+		{"not-found", http.MethodGet, fmt.Errorf("find: %w", ports.ErrNotFound), http.StatusNotFound, "not_found"},
+		{"conflict", http.MethodPost, ports.ErrConflict, http.StatusConflict, "conflict"},
+		{"serialization", http.MethodPost, ports.ErrSerialization, http.StatusConflict, "conflict"},
+		{"unauthorized", http.MethodPut, ports.ErrUnauthorized, http.StatusUnauthorized, "unauthorized"},
+		{"read-failure", http.MethodGet, errors.New("db down"), http.StatusInternalServerError, "internal"},
+		{"write-failure", http.MethodPost, errors.New("db down"), http.StatusInternalServerError, "internal"},
+		{"validation", http.MethodPost, fmt.Errorf("create: %w", &ports.ValidationError{Message: "valor invalido"}), http.StatusUnprocessableEntity, "unprocessable"},
+	}
+
+	for _, tt := range tests {
+		rec := httptest.NewRecorder()
+		writeAPIServiceError(rec, httptest.NewRequest(tt.method, "/api/v1/payments", nil), tt.err)
+		if rec.Code != tt.status {
+			t.Fatalf("%s: expected %d, got %d", tt.name, tt.status, rec.Code)
+		}
+		var body struct {
+			Error httpmw.APIError `json:"error"`
+		}
+		if err := json.NewDecoder(rec.Body).Decode(&body); err != nil {
+			t.Fatalf("%s: decode body: %v", tt.name, err)
+		}
+		if body.Error.Code != tt.code {
+			t.Fatalf("%s: expected code %q, got %q", tt.name, tt.code, body.Error.Code)
+		}
+		if tt.code == "unprocessable" && body.Error.Message != "create: valor invalido" {
+			t.Fatalf("%s: expected validation message, got %q", tt.name, body.Error.Message)
+		}
+		if tt.code == "internal" && strings.Contains(body.Error.Message, "db down") {
+			t.Fatalf("%s: expected internal error to be hidden", tt.name)
+		}
+	}
+}
+
+// Testa que o corpo JSON rejeita campos desconhecidos e objetos extras.
+func TestDecodeAPIJSON(t *testing.T) {
+	for _, body := range []string{`{"name":"Mensal","unknown":1}`, `{"name":"Mensal"}{}`, `not-json`} {
+		var input apiPlanInput
+		rec := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/plans", strings.NewReader(body))
+		if decodeAPIJSON(rec, req, &input) {
+			t.Fatalf("expected %q to be rejected", body)
+		}
+		if rec.Code != http.StatusBadRequest {
+			t.Fatalf("expected 400 for %q, got %d", body, rec.Code)
+		}
+	}
+
+	var input apiPlanInput
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/plans", strings.NewReader(`{"name":"Mensal","duration_days":30}`))
+	if !decodeAPIJSON(httptest.NewRecorder(), req, &input) || input.Name != "Mensal" {
+		t.Fatalf("expected valid body to decode, got %#v", input)
+	}
+}
+
+// Testa que os schemas do OpenAPI usam os enums do dominio e os campos
+// obrigatorios de entrada.
+func TestBuildAPIOpenAPISchemas(t *testing.T) {
+	spec := buildAPIOpenAPI("test_session")
+	schemas := spec["components"].(map[string]any)["schemas"].(map[string]any)
+
+	payment := schemas["Payment"].(map[string]any)
+	method := payment["properties"].(map[string]any)["method"].(map[string]any)
+	if fmt.Sprint(method["enum"]) != "[cash pix card transfer other]" {
+		t.Fatalf("unexpected method enum: %v", method["enum"])
+	}
+
+	input := schemas["PaymentInput"].(map[string]any)
+	if fmt.Sprint(input["required"]) != "[amount_cents method subscription_id]" {
+		t.Fatalf("unexpected required fields: %v", input["required"])
+	}
+	paidAt := input["properties"].(map[string]any)["paid_at"].(map[string]any)
+	if paidAt["format"] != "date-time" || paidAt["nullable"] != true {
+		t.Fatalf("unexpected paid_at schema: %v", paidAt)
+	}
+
+	subscription := schemas["Subscription"].(map[string]any)
+	startDate := subscription["properties"].(map[string]any)["start_date"].(map[string]any)
+	if startDate["format"] != "date" {
+		t.Fatalf("expected date format, got %v", startDate)
+	}
+
+	if _, err := json.Marshal(spec); err != nil {
+		t.Fatalf("marshal spec: %v", err)
+	}
+}
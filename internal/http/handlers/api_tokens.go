@@ -0,0 +1,234 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/PabloPavan/jaiu/internal/domain"
+	httpmw "github.com/PabloPavan/jaiu/internal/http/middleware"
+	"github.com/PabloPavan/jaiu/internal/observability"
+	"github.com/PabloPavan/jaiu/internal/ports"
+	"github.com/PabloPavan/jaiu/internal/view"
+	"github.com/go-chi/chi/v5"
+)
+
+var apiResourceLabels = map[domain.APIResource]string{
+	domain.APIResourceStudents:      "Alunos",
+	domain.APIResourcePlans:         "Planos",
+	domain.APIResourceSubscriptions: "Assinaturas",
+	domain.APIResourcePayments:      "Pagamentos",
+	domain.APIResourceReports:       "Relatorios",
+}
+
+// APITokenAuthenticator expoe o servico de tokens para o middleware da API.
+func (h *Handler) APITokenAuthenticator() httpmw.APITokenAuthenticator {
+	if h.services.APITokens == nil {
+		return nil
+	}
+	return h.services.APITokens
+}
+
+func (h *Handler) APITokensIndex(w http.ResponseWriter, r *http.Request) {
+	data := h.buildAPITokensData(r)
+	h.renderPage(w, r, page("Tokens de API", view.APITokensPage(data)))
+}
+
+func (h *Handler) APITokensNew(w http.ResponseWriter, r *http.Request) {
+	data := h.apiTokenFormData(r)
+	h.renderPage(w, r, page("Novo token de API", view.APITokenFormPage(data)))
+}
+
+func (h *Handler) APITokensCreate(w http.ResponseWriter, r *http.Request) {
+	data := h.apiTokenFormData(r)
+	token, err := parseAPITokenForm(r, &data)
+	if err != nil {
+		data.Error = err.Error()
+		h.renderFormError(w, r, "Novo token de API", view.APITokenFormPage(data))
+		return
+	}
+
+	if h.services.APITokens == nil {
+		data.Error = "Servico de tokens indisponivel."
+		h.renderFormError(w, r, "Novo token de API", view.APITokenFormPage(data))
+		return
+	}
+
+	created, secret, err := h.services.APITokens.Issue(r.Context(), token)
+	if err != nil {
+		data.Error = "Nao foi possivel emitir o token."
+		if errors.Is(err, ports.ErrNotFound) {
+			data.Error = "Usuario nao encontrado."
+		}
+		h.renderFormError(w, r, "Novo token de API", view.APITokenFormPage(data))
+		return
+	}
+
+	result := view.APITokenCreatedData{
+		Name:   created.Name,
+		User:   created.UserName,
+		Secret: secret,
+	}
+	w.Header().Set("Cache-Control", "no-store")
+	h.renderHTMXOrPage(w, r, "Token emitido", view.APITokenCreatedPage(result), view.APITokenCreatedPage(result))
+}
+
+func (h *Handler) APITokensRevoke(w http.ResponseWriter, r *http.Request) {
+	if h.services.APITokens == nil {
+		http.NotFound(w, r)
+		return
+	}
+
+	if err := h.services.APITokens.Revoke(r.Context(), chi.URLParam(r, "tokenID")); err != nil {
+		if errors.Is(err, ports.ErrNotFound) {
+			http.NotFound(w, r)
+			return
+		}
+		observability.Logger(r.Context()).Error("failed to revoke api token", "err", err)
+		http.Error(w, "Erro ao revogar token.", http.StatusInternalServerError)
+		return
+	}
+
+	h.renderHTMXOrRedirect(w, r, "/api-tokens", func() {
+		data := h.buildAPITokensData(r)
+		h.renderComponent(w, r, view.APITokensList(data))
+	})
+}
+
+func (h *Handler) buildAPITokensData(r *http.Request) view.APITokensPageData {
+	data := view.APITokensPageData{}
+	if h.services.APITokens == nil {
+		data.Error = "Servico de tokens indisponivel."
+		return data
+	}
+
+	tokens, err := h.services.APITokens.List(r.Context())
+	if err != nil {
+		observability.Logger(r.Context()).Error("failed to list api tokens", "err", err)
+		data.Error = "Nao foi possivel carregar os tokens."
+		return data
+	}
+
+	now := time.Now()
+	data.Items = make([]view.APITokenItem, 0, len(tokens))
+	for _, token := range tokens {
+		statusLabel, statusClass := apiTokenStatusPresentation(token, now)
+		scopes := make([]string, 0, len(token.Scopes))
+		for _, scope := range token.Scopes {
+			scopes = append(scopes, string(scope))
+		}
+		item := view.APITokenItem{
+			ID:          token.ID,
+			Name:        token.Name,
+			Prefix:      token.Prefix,
+			User:        token.UserName,
+			Scopes:      scopes,
+			CreatedAt:   formatDateTimeBR(token.CreatedAt),
+			ExpiresAt:   "nunca",
+			LastUsedAt:  "nunca",
+			Active:      !token.Revoked() && !token.Expired(now),
+			StatusLabel: statusLabel,
+			StatusClass: statusClass,
+		}
+		if token.ExpiresAt != nil {
+			item.ExpiresAt = "em " + formatDateTimeBR(*token.ExpiresAt)
+		}
+		if token.LastUsedAt != nil {
+			item.LastUsedAt = formatDateTimeBR(*token.LastUsedAt)
+		}
+		data.Items = append(data.Items, item)
+	}
+	return data
+}
+
+func (h *Handler) apiTokenFormData(r *http.Request) view.APITokenFormData {
+	data := view.APITokenFormData{}
+	for _, resource := range domain.APIResources {
+		data.Scopes = append(data.Scopes, view.APITokenScopeField{
+			Resource: string(resource),
+			Label:    apiResourceLabels[resource],
+		})
+	}
+	if h.services.Users == nil {
+		return data
+	}
+
+	users, err := h.services.Users.List(r.Context())
+	if err != nil {
+		observability.Logger(r.Context()).Error("failed to list users", "err", err)
+		return data
+	}
+	for _, user := range users {
+		if !user.Active {
+			continue
+		}
+		data.Users = append(data.Users, view.APITokenUserOption{
+			ID:    user.ID,
+			Label: user.Name + " (" + userRoleLabel(user.Role) + ")",
+		})
+	}
+	return data
+}
+
+func parseAPITokenForm(r *http.Request, data *view.APITokenFormData) (domain.APIToken, error) {
+	if err := r.ParseForm(); err != nil {
+		return domain.APIToken{}, errors.New("Nao foi possivel ler o formulario.")
+	}
+
+	token := domain.APIToken{
+		Name:   strings.TrimSpace(r.FormValue("name")),
+		UserID: strings.TrimSpace(r.FormValue("user_id")),
+	}
+	data.Name = token.Name
+	data.UserID = token.UserID
+
+	for i, field := range data.Scopes {
+		access := domain.APIAccess(strings.TrimSpace(r.FormValue("scope_" + field.Resource)))
+		if access == "" {
+			continue
+		}
+		data.Scopes[i].Access = string(access)
+		if !access.IsValid() {
+			return domain.APIToken{}, errors.New("Acesso invalido para " + field.Label + ".")
+		}
+		token.Scopes = append(token.Scopes, domain.NewAPIScope(domain.APIResource(field.Resource), access))
+	}
+
+	expiresAt := strings.TrimSpace(r.FormValue("expires_at"))
+	data.ExpiresAt = expiresAt
+	if expiresAt != "" {
+		date, err := parseDateInput(expiresAt)
+		if err != nil || date == nil {
+			return domain.APIToken{}, errors.New("Data de expiracao invalida.")
+		}
+		// O token vale ate o fim do dia escolhido.
+		endOfDay := time.Date(date.Year(), date.Month(), date.Day()+1, 0, 0, 0, 0, time.Local)
+		if !endOfDay.After(time.Now()) {
+			return domain.APIToken{}, errors.New("A expiracao deve ser uma data futura.")
+		}
+		token.ExpiresAt = &endOfDay
+	}
+
+	if token.Name == "" {
+		return domain.APIToken{}, errors.New("Nome do token e obrigatorio.")
+	}
+	if token.UserID == "" {
+		return domain.APIToken{}, errors.New("Selecione o usuario do token.")
+	}
+	if len(token.Scopes) == 0 {
+		return domain.APIToken{}, errors.New("Libere ao menos um recurso.")
+	}
+	return token, nil
+}
+
+func apiTokenStatusPresentation(token domain.APIToken, now time.Time) (string, string) {
+	switch {
+	case token.Revoked():
+		return "Revogado", "rounded-full bg-rose-400/10 px-3 py-1 text-rose-200"
+	case token.Expired(now):
+		return "Expirado", "rounded-full bg-slate-700/50 px-3 py-1 text-slate-300"
+	default:
+		return "Ativo", "rounded-full bg-emerald-400/10 px-3 py-1 text-emerald-200"
+	}
+}
@@ -0,0 +1,289 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/PabloPavan/jaiu/internal/domain"
+	"github.com/PabloPavan/jaiu/internal/observability"
+	"github.com/PabloPavan/jaiu/internal/ports"
+	"github.com/PabloPavan/jaiu/internal/view"
+	"github.com/go-chi/chi/v5"
+)
+
+const auditPageSize = 25
+
+var auditEntityTypes = []view.AuditOption{
+	{ID: "student", Label: "Alunos"},
+	{ID: "plan", Label: "Planos"},
+	{ID: "coupon", Label: "Cupons"},
+	{ID: "subscription", Label: "Assinaturas"},
+	{ID: "payment", Label: "Pagamentos"},
+	{ID: "billing_period", Label: "Competencias"},
+	{ID: "user", Label: "Usuarios"},
+	{ID: "session", Label: "Sessoes"},
+	{ID: "api_token", Label: "Tokens de API"},
+}
+
+func (h *Handler) AuditIndex(w http.ResponseWriter, r *http.Request) {
+	data := h.buildAuditData(r)
+	h.renderHTMXOrPage(w, r, "Auditoria", view.AuditPage(data), view.AuditList(data))
+}
+
+// StudentsHistory inclui o resumo de acessos aos dados do aluno antes da
+// linha do tempo.
+func (h *Handler) StudentsHistory(w http.ResponseWriter, r *http.Request) {
+	if h.services.Audit == nil {
+		http.NotFound(w, r)
+		return
+	}
+
+	studentID := chi.URLParam(r, "studentID")
+	names := h.auditActorNames(r)
+	h.renderComponent(w, r, view.StudentHistory(h.studentAccessData(r, studentID, names), h.auditTimelineData(r, "student", studentID, names)))
+}
+
+func (h *Handler) studentAccessData(r *http.Request, studentID string, names map[string]string) view.StudentAccessData {
+	data := view.StudentAccessData{}
+	summaries, err := h.services.Audit.StudentAccess(r.Context(), studentID)
+	if err != nil {
+		observability.Logger(r.Context()).Error("failed to load student access", "err", err)
+		data.Error = "Nao foi possivel carregar os acessos."
+		return data
+	}
+	for _, summary := range summaries {
+		data.Items = append(data.Items, view.StudentAccessItem{
+			Actor:     auditActorName(summary.ActorID, names),
+			ActorRole: summary.ActorRole,
+			Views:     summary.Views,
+			Sources:   strings.Join(summary.Sources, ", "),
+			FirstAt:   formatDateTimeBR(summary.FirstAt),
+			LastAt:    formatDateTimeBR(summary.LastAt),
+		})
+	}
+	return data
+}
+
+func (h *Handler) SubscriptionsHistory(w http.ResponseWriter, r *http.Request) {
+	h.renderEntityHistory(w, r, "subscription", chi.URLParam(r, "subscriptionID"))
+}
+
+func (h *Handler) PaymentsHistory(w http.ResponseWriter, r *http.Request) {
+	h.renderEntityHistory(w, r, "payment", chi.URLParam(r, "paymentID"))
+}
+
+func (h *Handler) renderEntityHistory(w http.ResponseWriter, r *http.Request, entityType, entityID string) {
+	if h.services.Audit == nil {
+		http.NotFound(w, r)
+		return
+	}
+
+	h.renderComponent(w, r, view.AuditTimeline(h.auditTimelineData(r, entityType, entityID, h.auditActorNames(r))))
+}
+
+func (h *Handler) auditTimelineData(r *http.Request, entityType, entityID string, names map[string]string) view.AuditTimelineData {
+	data := view.AuditTimelineData{}
+	events, err := h.services.Audit.Timeline(r.Context(), entityType, entityID)
+	if err != nil {
+		observability.Logger(r.Context()).Error("failed to load audit timeline", "err", err)
+		data.Error = "Nao foi possivel carregar o historico."
+	} else {
+		data.Items = auditEventItems(events, names)
+	}
+	return data
+}
+
+// historyURL devolve a rota do historico da entidade quando o usuario pode
+// consultar a auditoria.
+func (h *Handler) historyURL(r *http.Request, base string) string {
+	if h.services.Audit == nil || !can(r, domain.PermissionAuditView) {
+		return ""
+	}
+	if strings.HasSuffix(base, "/") {
+		return ""
+	}
+	return base + "/history"
+}
+
+func (h *Handler) buildAuditData(r *http.Request) view.AuditPageData {
+	data := view.AuditPageData{
+		ActorID:     strings.TrimSpace(r.FormValue("actor_id")),
+		EntityType:  strings.TrimSpace(r.FormValue("entity_type")),
+		EntityID:    strings.TrimSpace(r.FormValue("entity_id")),
+		Action:      strings.TrimSpace(r.FormValue("action")),
+		From:        strings.TrimSpace(r.FormValue("from")),
+		To:          strings.TrimSpace(r.FormValue("to")),
+		EntityTypes: auditEntityTypes,
+		Page:        1,
+		TotalPages:  1,
+	}
+	if value := strings.TrimSpace(r.FormValue("page")); value != "" {
+		if parsed, err := strconv.Atoi(value); err == nil && parsed > 0 {
+			data.Page = parsed
+		}
+	}
+
+	names := h.auditActorNames(r)
+	data.Actors = auditActorOptions(names)
+
+	if h.services.Audit == nil {
+		data.Error = "Servico de auditoria indisponivel."
+		return data
+	}
+
+	filter := ports.AuditFilter{
+		ActorID:      data.ActorID,
+		EntityType:   data.EntityType,
+		EntityID:     data.EntityID,
+		ActionPrefix: data.Action,
+	}
+	from, err := parseDateInput(data.From)
+	if err != nil {
+		data.Error = "Data inicial invalida."
+		return data
+	}
+	to, err := parseDateInput(data.To)
+	if err != nil {
+		data.Error = "Data final invalida."
+		return data
+	}
+	if from != nil {
+		filter.From = time.Date(from.Year(), from.Month(), from.Day(), 0, 0, 0, 0, time.Local)
+	}
+	if to != nil {
+		filter.To = time.Date(to.Year(), to.Month(), to.Day(), 0, 0, 0, 0, time.Local).AddDate(0, 0, 1)
+	}
+
+	total, err := h.services.Audit.Count(r.Context(), filter)
+	if err != nil {
+		observability.Logger(r.Context()).Error("failed to count audit events", "err", err)
+		data.Error = "Nao foi possivel consultar a auditoria."
+		return data
+	}
+	data.TotalItems = total
+	if total > 0 {
+		data.TotalPages = (total + auditPageSize - 1) / auditPageSize
+	}
+	if data.Page > data.TotalPages {
+		data.Page = data.TotalPages
+	}
+
+	filter.Limit = auditPageSize
+	filter.Offset = (data.Page - 1) * auditPageSize
+	events, err := h.services.Audit.Search(r.Context(), filter)
+	if err != nil {
+		observability.Logger(r.Context()).Error("failed to list audit events", "err", err)
+		data.Error = "Nao foi possivel consultar a auditoria."
+		return data
+	}
+	data.Items = auditEventItems(events, names)
+	return data
+}
+
+func (h *Handler) auditActorNames(r *http.Request) map[string]string {
+	names := map[string]string{}
+	if h.services.Users == nil {
+		return names
+	}
+	users, err := h.services.Users.List(r.Context())
+	if err != nil {
+		observability.Logger(r.Context()).Error("failed to list users", "err", err)
+		return names
+	}
+	for _, user := range users {
+		names[user.ID] = user.Name
+	}
+	return names
+}
+
+func auditActorOptions(names map[string]string) []view.AuditOption {
+	options := make([]view.AuditOption, 0, len(names))
+	for id, name := range names {
+		options = append(options, view.AuditOption{ID: id, Label: name})
+	}
+	sort.Slice(options, func(i, j int) bool {
+		return options[i].Label < options[j].Label
+	})
+	return options
+}
+
+func auditEventItems(events []domain.AuditEvent, names map[string]string) []view.AuditEventItem {
+	items := make([]view.AuditEventItem, 0, len(events))
+	for _, event := range events {
+		items = append(items, view.AuditEventItem{
+			ID:          event.ID,
+			Action:      event.Action,
+			ActionClass: auditActionClass(event.Action),
+			Actor:       auditActorName(event.ActorID, names),
+			ActorRole:   event.ActorRole,
+			EntityType:  event.EntityType,
+			EntityID:    event.EntityID,
+			CreatedAt:   formatDateTimeBR(event.CreatedAt),
+			IP:          event.IP,
+			Metadata:    auditMetadataItems(event.Metadata),
+		})
+	}
+	return items
+}
+
+func auditActorName(actorID string, names map[string]string) string {
+	if actorID == "" {
+		return "Sistema"
+	}
+	if name := names[actorID]; name != "" {
+		return name
+	}
+	return actorID
+}
+
+func auditActionClass(action string) string {
+	switch {
+	case strings.HasSuffix(action, ".success"):
+		return "text-emerald-200"
+	case strings.HasSuffix(action, ".failure"):
+		return "text-rose-200"
+	case strings.HasSuffix(action, ".denied"):
+		return "text-amber-200"
+	default:
+		return "text-slate-200"
+	}
+}
+
+func auditMetadataItems(metadata map[string]any) []view.AuditMetadataItem {
+	if len(metadata) == 0 {
+		return nil
+	}
+	keys := make([]string, 0, len(metadata))
+	for key := range metadata {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	items := make([]view.AuditMetadataItem, 0, len(keys))
+	for _, key := range keys {
+		items = append(items, view.AuditMetadataItem{Key: key, Value: auditMetadataValue(metadata[key])})
+	}
+	return items
+}
+
+func auditMetadataValue(value any) string {
+	switch v := value.(type) {
+	case nil:
+		return "-"
+	case string:
+		return v
+	case map[string]any, []any:
+		payload, err := json.Marshal(v)
+		if err != nil {
+			return fmt.Sprint(v)
+		}
+		return string(payload)
+	default:
+		return fmt.Sprint(v)
+	}
+}
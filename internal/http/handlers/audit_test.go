@@ -0,0 +1,42 @@
+package handlers
+
+import (
+	"testing"
+	"time"
+
+	"github.com/PabloPavan/jaiu/internal/domain"
+)
+
+// Testa a conversao de eventos de auditoria para exibicao.
+func TestAuditEventItems(t *testing.T) {
+	events := []domain.AuditEvent{
+		{
+			ID:         "evt-1",
+			ActorID:    "user-1",
+			ActorRole:  "admin",
+			Action:     "payment.reverse.success",
+			EntityType: "payment",
+			EntityID:   "pay-1",
+			Metadata:   map[string]any{"subscription_id": "sub-1", "amount_cents": float64(1500), "changes": map[string]any{"status": "reversed"}},
+			CreatedAt:  time.Date(2024, 1, 2, 15, 4, 0, 0, time.Local),
+		},
+		{ID: "evt-2", Action: "student.update.failure", EntityType: "student"},
+	}
+
+	items := auditEventItems(events, map[string]string{"user-1": "Ana"})
+	if len(items) != 2 {
+		t.Fatalf("expected 2 items, got %d", len(items))
+	}
+	if items[0].Actor != "Ana" || items[0].CreatedAt != "02/01/2024 15:04" || items[0].ActionClass != "text-emerald-200" {
+		t.Fatalf("unexpected first item: %#v", items[0])
+	}
+	if len(items[0].Metadata) != 3 || items[0].Metadata[0].Key != "amount_cents" || items[0].Metadata[0].Value != "1500" {
+		t.Fatalf("expected sorted metadata, got %#v", items[0].Metadata)
+	}
+	if items[0].Metadata[1].Value != `{"status":"reversed"}` {
+		t.Fatalf("expected nested metadata as json, got %q", items[0].Metadata[1].Value)
+	}
+	if items[1].Actor != "Sistema" || items[1].ActionClass != "text-rose-200" {
+		t.Fatalf("unexpected second item: %#v", items[1])
+	}
+}
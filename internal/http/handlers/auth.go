@@ -1,18 +1,28 @@
 package handlers
 
 import (
+	"crypto/rand"
+	"encoding/base64"
 	"errors"
+	"fmt"
+	"math"
 	"net/http"
+	"strconv"
 	"strings"
 	"time"
 
 	"github.com/PabloPavan/jaiu/internal/auditctx"
+	httpmw "github.com/PabloPavan/jaiu/internal/http/middleware"
 	"github.com/PabloPavan/jaiu/internal/ports"
 	"github.com/PabloPavan/jaiu/internal/view"
 )
 
 func (h *Handler) Login(w http.ResponseWriter, r *http.Request) {
-	h.renderPage(w, r, page("Entrar", view.LoginPage(view.LoginData{})))
+	data := view.LoginData{}
+	if r.URL.Query().Get("reset") == "1" {
+		data.Notice = "Senha redefinida. Entre com a nova senha."
+	}
+	h.renderPage(w, r, page("Entrar", view.LoginPage(data)))
 }
 
 func (h *Handler) LoginPost(w http.ResponseWriter, r *http.Request) {
@@ -43,6 +53,14 @@ func (h *Handler) LoginPost(w http.ResponseWriter, r *http.Request) {
 	ctx := auditctx.WithActor(r.Context(), auditctx.Actor{Email: email})
 	user, err := h.services.Auth.Authenticate(ctx, email, password)
 	if err != nil {
+		var lockErr *ports.LockoutError
+		if errors.As(err, &lockErr) {
+			wait := time.Until(lockErr.Until)
+			w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(wait.Seconds()))))
+			viewData.Error = lockoutMessage(wait)
+			h.renderPage(w, r, page("Entrar", view.LoginPage(viewData)))
+			return
+		}
 		if errors.Is(err, ports.ErrUnauthorized) || errors.Is(err, ports.ErrNotFound) {
 			viewData.Error = "Credenciais invalidas."
 			h.renderPage(w, r, page("Entrar", view.LoginPage(viewData)))
@@ -52,18 +70,60 @@ func (h *Handler) LoginPost(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if h.services.MFA != nil {
+		challengeToken, err := h.services.MFA.StartLogin(ctx, user)
+		if err != nil {
+			http.Error(w, "erro ao autenticar", http.StatusInternalServerError)
+			return
+		}
+		if challengeToken != "" {
+			h.setMFACookie(w, challengeToken)
+			http.Redirect(w, r, "/auth/mfa", http.StatusSeeOther)
+			return
+		}
+	}
+
 	session := ports.Session{
-		UserID:    user.ID,
-		Name:      user.Name,
-		Role:      user.Role,
-		ExpiresAt: time.Now().Add(h.config.TTL),
+		UserID:             user.ID,
+		Name:               user.Name,
+		Role:               user.Role,
+		MustChangePassword: user.MustChangePassword,
 	}
-	token, err := h.sessions.Create(r.Context(), session)
-	if err != nil {
+	if err := h.startSession(w, r, session); err != nil {
 		http.Error(w, "erro ao criar sessao", http.StatusInternalServerError)
 		return
 	}
 
+	if user.MustChangePassword {
+		http.Redirect(w, r, "/account/password", http.StatusSeeOther)
+		return
+	}
+	http.Redirect(w, r, "/", http.StatusSeeOther)
+}
+
+// startSession cria uma nova sessao com os dados da requisicao e grava o cookie.
+// Cada sessao recebe seu proprio token CSRF.
+func (h *Handler) startSession(w http.ResponseWriter, r *http.Request, session ports.Session) error {
+	csrfToken, err := generateCSRFToken()
+	if err != nil {
+		return err
+	}
+
+	request := auditctx.FromContext(r.Context()).Request
+	now := time.Now()
+	session.ID = ""
+	session.CSRFToken = csrfToken
+	session.IP = request.IP
+	session.UserAgent = request.UserAgent
+	session.CreatedAt = now
+	session.LastSeenAt = now
+	session.ExpiresAt = h.SessionPolicy().ExpiresAt(now, now)
+
+	token, err := h.sessions.Create(r.Context(), session)
+	if err != nil {
+		return err
+	}
+
 	http.SetCookie(w, &http.Cookie{
 		Name:     h.config.CookieName,
 		Value:    token,
@@ -73,8 +133,7 @@ func (h *Handler) LoginPost(w http.ResponseWriter, r *http.Request) {
 		Secure:   h.config.Secure,
 		SameSite: h.config.SameSite,
 	})
-
-	http.Redirect(w, r, "/", http.StatusSeeOther)
+	return nil
 }
 
 func (h *Handler) Logout(w http.ResponseWriter, r *http.Request) {
@@ -92,6 +151,10 @@ func (h *Handler) Logout(w http.ResponseWriter, r *http.Request) {
 			ID:   session.UserID,
 			Role: string(session.Role),
 		})
+		if !httpmw.VerifyCSRF(r, session.CSRFToken) {
+			httpmw.RejectCSRF(w, r.WithContext(ctx), h.CSRFFailureRecorder())
+			return
+		}
 	}
 	if h.services.Auth != nil {
 		_ = h.services.Auth.Logout(ctx, session)
@@ -103,6 +166,12 @@ func (h *Handler) Logout(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
+	h.clearSessionCookie(w)
+
+	http.Redirect(w, r, "/auth/login", http.StatusSeeOther)
+}
+
+func (h *Handler) clearSessionCookie(w http.ResponseWriter) {
 	http.SetCookie(w, &http.Cookie{
 		Name:     h.config.CookieName,
 		Value:    "",
@@ -112,6 +181,20 @@ func (h *Handler) Logout(w http.ResponseWriter, r *http.Request) {
 		Secure:   h.config.Secure,
 		SameSite: h.config.SameSite,
 	})
+}
 
-	http.Redirect(w, r, "/auth/login", http.StatusSeeOther)
+func generateCSRFToken() (string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(buf), nil
+}
+
+func lockoutMessage(wait time.Duration) string {
+	minutes := int(math.Ceil(wait.Minutes()))
+	if minutes <= 1 {
+		return "Muitas tentativas de acesso. Tente novamente em 1 minuto."
+	}
+	return fmt.Sprintf("Muitas tentativas de acesso. Tente novamente em %d minutos.", minutes)
 }
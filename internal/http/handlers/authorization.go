@@ -0,0 +1,30 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/PabloPavan/jaiu/internal/domain"
+	httpmw "github.com/PabloPavan/jaiu/internal/http/middleware"
+)
+
+func (h *Handler) AccessDeniedRecorder() httpmw.AccessDeniedRecorder {
+	if h.services.Authorization == nil {
+		return nil
+	}
+	return h.services.Authorization
+}
+
+func (h *Handler) CSRFFailureRecorder() httpmw.CSRFFailureRecorder {
+	if h.services.Authorization == nil {
+		return nil
+	}
+	return h.services.Authorization
+}
+
+func can(r *http.Request, permission domain.Permission) bool {
+	session, ok := httpmw.SessionFromContext(r.Context())
+	if !ok {
+		return false
+	}
+	return session.Role.Can(permission)
+}
@@ -0,0 +1,74 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/PabloPavan/jaiu/internal/domain"
+	httpmw "github.com/PabloPavan/jaiu/internal/http/middleware"
+	"github.com/PabloPavan/jaiu/internal/ports"
+)
+
+// Testa a checagem de permissao a partir da sessao da requisicao.
+func TestCan(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/plans", nil)
+	if can(req, domain.PermissionPlanCreate) {
+		t.Fatal("expected no permission without session")
+	}
+
+	var admin, operator *http.Request
+	store := &stubSessionStore{session: ports.Session{UserID: "user-1", Role: domain.RoleAdmin}}
+	capture := func(target **http.Request) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) { *target = r })
+	}
+
+	adminReq := httptest.NewRequest(http.MethodGet, "/plans", nil)
+	adminReq.AddCookie(&http.Cookie{Name: "session", Value: "token"})
+	httpmw.RequireSession(store, "session")(capture(&admin)).ServeHTTP(httptest.NewRecorder(), adminReq)
+	if admin == nil || !can(admin, domain.PermissionPlanCreate) {
+		t.Fatal("expected admin to create plans")
+	}
+
+	store.session.Role = domain.RoleOperator
+	operatorReq := httptest.NewRequest(http.MethodGet, "/plans", nil)
+	operatorReq.AddCookie(&http.Cookie{Name: "session", Value: "token"})
+	httpmw.RequireSession(store, "session")(capture(&operator)).ServeHTTP(httptest.NewRecorder(), operatorReq)
+	if operator == nil || can(operator, domain.PermissionPlanCreate) {
+		t.Fatal("expected operator to be denied plan creation")
+	}
+}
+
+type stubSessionStore struct {
+	session ports.Session
+}
+
+func (s *stubSessionStore) Create(ctx context.Context, session ports.Session) (string, error) {
+	return "token", nil
+}
+
+func (s *stubSessionStore) Get(ctx context.Context, token string) (ports.Session, error) {
+	return s.session, nil
+}
+
+func (s *stubSessionStore) Delete(ctx context.Context, token string) error {
+	return nil
+}
+
+func (s *stubSessionStore) DeleteByUser(ctx context.Context, userID string) error {
+	return nil
+}
+
+func (s *stubSessionStore) Touch(ctx context.Context, token string, seenAt, expiresAt time.Time) error {
+	return nil
+}
+
+func (s *stubSessionStore) ListByUser(ctx context.Context, userID string) ([]ports.Session, error) {
+	return nil, nil
+}
+
+func (s *stubSessionStore) DeleteByID(ctx context.Context, userID, sessionID string) error {
+	return nil
+}
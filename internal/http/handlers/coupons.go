@@ -0,0 +1,185 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/PabloPavan/jaiu/internal/domain"
+	"github.com/PabloPavan/jaiu/internal/observability"
+	"github.com/PabloPavan/jaiu/internal/ports"
+	"github.com/PabloPavan/jaiu/internal/view"
+	"github.com/go-chi/chi/v5"
+)
+
+func (h *Handler) CouponsIndex(w http.ResponseWriter, r *http.Request) {
+	data := h.buildCouponsData(r)
+	h.renderPage(w, r, page("Cupons", view.CouponsPage(data)))
+}
+
+func (h *Handler) CouponsNew(w http.ResponseWriter, r *http.Request) {
+	data := couponFormData()
+	h.renderPage(w, r, page("Novo cupom", view.CouponFormPage(data)))
+}
+
+func (h *Handler) CouponsCreate(w http.ResponseWriter, r *http.Request) {
+	data := couponFormData()
+	coupon, err := parseCouponForm(r, &data)
+	if err != nil {
+		data.Error = err.Error()
+		h.renderFormError(w, r, "Novo cupom", view.CouponFormPage(data))
+		return
+	}
+
+	if h.services.Coupons == nil {
+		data.Error = "Servico de cupons indisponivel."
+		h.renderFormError(w, r, "Novo cupom", view.CouponFormPage(data))
+		return
+	}
+
+	if _, err := h.services.Coupons.Create(r.Context(), coupon); err != nil {
+		data.Error = "Nao foi possivel salvar o cupom."
+		if errors.Is(err, ports.ErrConflict) {
+			data.Error = "Ja existe um cupom com este codigo."
+		}
+		h.renderFormError(w, r, "Novo cupom", view.CouponFormPage(data))
+		return
+	}
+
+	h.redirectHTMXOrRedirect(w, r, "/coupons")
+}
+
+func (h *Handler) CouponsDeactivate(w http.ResponseWriter, r *http.Request) {
+	if h.services.Coupons == nil {
+		http.NotFound(w, r)
+		return
+	}
+
+	if _, err := h.services.Coupons.Deactivate(r.Context(), chi.URLParam(r, "couponID")); err != nil {
+		if errors.Is(err, ports.ErrNotFound) {
+			http.NotFound(w, r)
+			return
+		}
+		observability.Logger(r.Context()).Error("failed to deactivate coupon", "err", err)
+		http.Error(w, "Erro ao desativar cupom.", http.StatusInternalServerError)
+		return
+	}
+
+	h.renderHTMXOrRedirect(w, r, "/coupons", func() {
+		data := h.buildCouponsData(r)
+		h.renderComponent(w, r, view.CouponsList(data))
+	})
+}
+
+func (h *Handler) buildCouponsData(r *http.Request) view.CouponsPageData {
+	data := view.CouponsPageData{}
+	if h.services.Coupons == nil {
+		data.Error = "Servico de cupons indisponivel."
+		return data
+	}
+
+	coupons, err := h.services.Coupons.List(r.Context())
+	if err != nil {
+		observability.Logger(r.Context()).Error("failed to list coupons", "err", err)
+		data.Error = "Nao foi possivel carregar os cupons."
+		return data
+	}
+
+	data.Items = make([]view.CouponItem, 0, len(coupons))
+	for _, coupon := range coupons {
+		data.Items = append(data.Items, view.CouponItem{
+			ID:          coupon.ID,
+			Code:        coupon.Code,
+			Description: coupon.Description,
+			Discount:    formatDiscount(coupon.Discount),
+			CreatedAt:   formatDateBRValue(coupon.CreatedAt),
+			Active:      coupon.Active,
+		})
+	}
+	return data
+}
+
+func couponFormData() view.CouponFormData {
+	return view.CouponFormData{
+		Kind:     string(domain.DiscountPercent),
+		Duration: string(domain.DiscountOnce),
+	}
+}
+
+func parseCouponForm(r *http.Request, data *view.CouponFormData) (domain.Coupon, error) {
+	if err := r.ParseForm(); err != nil {
+		return domain.Coupon{}, errors.New("Nao foi possivel ler o formulario.")
+	}
+
+	data.Code = strings.TrimSpace(r.FormValue("code"))
+	data.Description = strings.TrimSpace(r.FormValue("description"))
+	data.Kind = strings.TrimSpace(r.FormValue("discount_kind"))
+	data.Value = strings.TrimSpace(r.FormValue("discount_value"))
+	data.Duration = strings.TrimSpace(r.FormValue("discount_duration"))
+	data.Periods = strings.TrimSpace(r.FormValue("discount_periods"))
+
+	if data.Code == "" {
+		return domain.Coupon{}, errors.New("Codigo do cupom e obrigatorio.")
+	}
+
+	kind := domain.DiscountKind(data.Kind)
+	if !kind.IsValid() {
+		return domain.Coupon{}, errors.New("Tipo de desconto invalido.")
+	}
+	value, err := parsePriceCents(data.Value)
+	if err != nil || value <= 0 {
+		return domain.Coupon{}, errors.New("Valor do desconto invalido.")
+	}
+	if kind == domain.DiscountPercent && value > domain.MaxBasisPoints {
+		return domain.Coupon{}, errors.New("O desconto percentual deve ser de no maximo 100%.")
+	}
+
+	duration := domain.DiscountDuration(data.Duration)
+	if !duration.IsValid() {
+		return domain.Coupon{}, errors.New("Duracao do desconto invalida.")
+	}
+	periods := 0
+	if duration == domain.DiscountRepeating {
+		periods, err = strconv.Atoi(data.Periods)
+		if err != nil || periods <= 0 {
+			return domain.Coupon{}, errors.New("Informe quantas competencias recebem o desconto.")
+		}
+	}
+
+	return domain.Coupon{
+		Code:        data.Code,
+		Description: data.Description,
+		Discount: domain.Discount{
+			Kind:     kind,
+			Value:    value,
+			Duration: duration,
+			Periods:  periods,
+		},
+	}, nil
+}
+
+// formatDiscount descreve o desconto para as telas, como "10,00% nas 3
+// primeiras competencias".
+func formatDiscount(discount domain.Discount) string {
+	if discount.IsZero() {
+		return ""
+	}
+
+	amount := formatBRL(discount.Value)
+	if discount.Kind == domain.DiscountPercent {
+		amount = formatCentsInput(discount.Value) + "%"
+	}
+
+	switch discount.Duration {
+	case domain.DiscountOnce:
+		return amount + " na primeira competencia"
+	case domain.DiscountRepeating:
+		if discount.Periods == 1 {
+			return amount + " na primeira competencia"
+		}
+		return amount + " nas " + strconv.Itoa(discount.Periods) + " primeiras competencias"
+	default:
+		return amount + " em todas as competencias"
+	}
+}
@@ -0,0 +1,68 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/PabloPavan/jaiu/internal/domain"
+	"github.com/PabloPavan/jaiu/internal/view"
+)
+
+// Testa a descricao do desconto exibida nas telas.
+func TestFormatDiscount(t *testing.T) {
+	tests := []struct {
+		name     string
+		discount domain.Discount
+		want     string
+	}{
+		{"none", domain.Discount{}, ""},
+		{"once", domain.Discount{Kind: domain.DiscountPercent, Value: 1000, Duration: domain.DiscountOnce}, "10,00% na primeira competencia"},
+		{"repeating", domain.Discount{Kind: domain.DiscountFixed, Value: 2500, Duration: domain.DiscountRepeating, Periods: 3}, "R$ 25,00 nas 3 primeiras competencias"},
+		{"forever", domain.Discount{Kind: domain.DiscountPercent, Value: 550, Duration: domain.DiscountForever}, "5,50% em todas as competencias"},
+	}
+
+	for _, tt := range tests {
+		if got := formatDiscount(tt.discount); got != tt.want {
+			t.Fatalf("%s: expected %q, got %q", tt.name, tt.want, got)
+		}
+	}
+}
+
+// Testa o parse do formulario de cupom.
+func TestParseCouponForm(t *testing.T) {
+	tests := []struct {
+		name      string
+		form      url.Values
+		want      domain.Discount
+		wantError bool
+	}{
+		{"percent", url.Values{"code": {"PROMO"}, "discount_kind": {"percent"}, "discount_value": {"10,00"}, "discount_duration": {"once"}}, domain.Discount{Kind: domain.DiscountPercent, Value: 1000, Duration: domain.DiscountOnce}, false},
+		{"repeating", url.Values{"code": {"PROMO"}, "discount_kind": {"fixed"}, "discount_value": {"25,00"}, "discount_duration": {"repeating"}, "discount_periods": {"3"}}, domain.Discount{Kind: domain.DiscountFixed, Value: 2500, Duration: domain.DiscountRepeating, Periods: 3}, false},
+		{"missing code", url.Values{"discount_kind": {"percent"}, "discount_value": {"10,00"}, "discount_duration": {"once"}}, domain.Discount{}, true},
+		{"over 100%", url.Values{"code": {"PROMO"}, "discount_kind": {"percent"}, "discount_value": {"100,01"}, "discount_duration": {"once"}}, domain.Discount{}, true},
+		{"repeating without periods", url.Values{"code": {"PROMO"}, "discount_kind": {"fixed"}, "discount_value": {"25,00"}, "discount_duration": {"repeating"}}, domain.Discount{}, true},
+	}
+
+	for _, tt := range tests {
+		req := httptest.NewRequest(http.MethodPost, "/coupons", strings.NewReader(tt.form.Encode()))
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		data := view.CouponFormData{}
+
+		coupon, err := parseCouponForm(req, &data)
+		if tt.wantError {
+			if err == nil {
+				t.Fatalf("%s: expected error, got nil", tt.name)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", tt.name, err)
+		}
+		if coupon.Discount != tt.want {
+			t.Fatalf("%s: expected %#v, got %#v", tt.name, tt.want, coupon.Discount)
+		}
+	}
+}
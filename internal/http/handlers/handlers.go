@@ -25,7 +25,17 @@ type Services struct {
 	Plans         PlanService
 	Students      StudentService
 	Subscriptions SubscriptionService
+	Coupons       CouponService
 	Payments      PaymentService
+	Reports       ReportService
+	Dashboard     DashboardService
+	Authorization AuthorizationService
+	Users         UserService
+	Sessions      SessionService
+	Passwords     PasswordService
+	MFA           MFAService
+	Audit         AuditService
+	APITokens     APITokenService
 }
 
 type AuthService interface {
@@ -35,6 +45,7 @@ type AuthService interface {
 
 type PlanService interface {
 	ListActive(ctx context.Context) ([]domain.Plan, error)
+	ListActivePage(ctx context.Context, page ports.PageRequest) ([]domain.Plan, error)
 	FindByID(ctx context.Context, id string) (domain.Plan, error)
 	Create(ctx context.Context, plan domain.Plan) (domain.Plan, error)
 	Update(ctx context.Context, plan domain.Plan) (domain.Plan, error)
@@ -44,7 +55,11 @@ type PlanService interface {
 type StudentService interface {
 	Count(ctx context.Context, filter ports.StudentFilter) (int, error)
 	Search(ctx context.Context, filter ports.StudentFilter) ([]domain.Student, error)
+	SearchPage(ctx context.Context, filter ports.StudentFilter, page ports.PageRequest) ([]domain.Student, error)
 	FindByID(ctx context.Context, id string) (domain.Student, error)
+	FindName(ctx context.Context, id string) (string, error)
+	RecordViews(ctx context.Context, source string, studentIDs ...string)
+	RecordPhotoView(ctx context.Context, objectKey string) error
 	Register(ctx context.Context, student domain.Student) (domain.Student, error)
 	Update(ctx context.Context, student domain.Student) (domain.Student, error)
 	SetStatus(ctx context.Context, studentID string, status domain.StudentStatus) (domain.Student, error)
@@ -56,8 +71,22 @@ type SubscriptionService interface {
 	Create(ctx context.Context, subscription domain.Subscription) (domain.Subscription, error)
 	Update(ctx context.Context, subscription domain.Subscription) (domain.Subscription, error)
 	Cancel(ctx context.Context, subscriptionID string) (domain.Subscription, error)
+	ChangePlan(ctx context.Context, subscriptionID, planID string) (domain.Subscription, error)
+	Freeze(ctx context.Context, freeze domain.SubscriptionFreeze) (domain.SubscriptionFreeze, error)
+	ListFreezes(ctx context.Context, subscriptionID string) ([]domain.SubscriptionFreeze, error)
+	ListFreezesPage(ctx context.Context, subscriptionID string, page ports.PageRequest) ([]domain.SubscriptionFreeze, error)
 	ListByStudent(ctx context.Context, studentID string) ([]domain.Subscription, error)
+	ListByStudentPage(ctx context.Context, studentID string, page ports.PageRequest) ([]domain.Subscription, error)
 	DueBetween(ctx context.Context, start, end time.Time) ([]domain.Subscription, error)
+	DueBetweenPage(ctx context.Context, start, end time.Time, page ports.PageRequest) ([]domain.Subscription, error)
+}
+
+type CouponService interface {
+	List(ctx context.Context) ([]domain.Coupon, error)
+	FindByID(ctx context.Context, id string) (domain.Coupon, error)
+	FindByCode(ctx context.Context, code string) (domain.Coupon, error)
+	Create(ctx context.Context, coupon domain.Coupon) (domain.Coupon, error)
+	Deactivate(ctx context.Context, couponID string) (domain.Coupon, error)
 }
 
 type PaymentService interface {
@@ -66,14 +95,85 @@ type PaymentService interface {
 	Update(ctx context.Context, payment domain.Payment) (domain.Payment, error)
 	Reverse(ctx context.Context, paymentID string) (domain.Payment, error)
 	ListBySubscription(ctx context.Context, subscriptionID string) ([]domain.Payment, error)
+	ListBySubscriptionPage(ctx context.Context, subscriptionID string, page ports.PageRequest) ([]domain.Payment, error)
 	ListByPeriod(ctx context.Context, start, end time.Time) ([]domain.Payment, error)
+	ListByPeriodPage(ctx context.Context, start, end time.Time, page ports.PageRequest) ([]domain.Payment, error)
+	ListBillingPeriods(ctx context.Context, subscriptionID string) ([]domain.BillingPeriod, error)
+	ListBillingPeriodsPage(ctx context.Context, subscriptionID string, page ports.PageRequest) ([]domain.BillingPeriod, error)
+	WaiveLateFees(ctx context.Context, subscriptionID, periodID string) (domain.BillingPeriod, error)
+}
+
+type UserService interface {
+	List(ctx context.Context) ([]domain.User, error)
+	FindByID(ctx context.Context, userID string) (domain.User, error)
+	Create(ctx context.Context, user domain.User, password string) (domain.User, error)
+	Update(ctx context.Context, user domain.User) (domain.User, error)
+	SetActive(ctx context.Context, userID string, active bool) (domain.User, error)
+	ResetPassword(ctx context.Context, userID, password string) error
+}
+
+type SessionService interface {
+	ListByUser(ctx context.Context, userID string) ([]ports.Session, error)
+	Revoke(ctx context.Context, userID, sessionID string) error
+	RevokeAll(ctx context.Context, userID string) error
+}
+
+type PasswordService interface {
+	ChangePassword(ctx context.Context, userID, current, next string) error
+	RequestReset(ctx context.Context, email string) error
+	ResetPassword(ctx context.Context, token, password string) error
+}
+
+type MFAService interface {
+	StartLogin(ctx context.Context, user domain.User) (string, error)
+	LoginChallenge(ctx context.Context, token string) (ports.LoginChallenge, error)
+	CompleteLogin(ctx context.Context, token, code string) (ports.LoginChallenge, []string, error)
+	Status(ctx context.Context, userID string, role domain.UserRole) (ports.MFAStatus, error)
+	BeginEnrollment(ctx context.Context, userID string) (ports.MFAEnrollment, error)
+	ConfirmEnrollment(ctx context.Context, userID, code string) ([]string, error)
+	Disable(ctx context.Context, userID, code string) error
+	RegenerateRecoveryCodes(ctx context.Context, userID, code string) ([]string, error)
+}
+
+type AuditService interface {
+	Search(ctx context.Context, filter ports.AuditFilter) ([]domain.AuditEvent, error)
+	Count(ctx context.Context, filter ports.AuditFilter) (int, error)
+	Timeline(ctx context.Context, entityType, entityID string) ([]domain.AuditEvent, error)
+	StudentAccess(ctx context.Context, studentID string) ([]ports.AuditAccessSummary, error)
+}
+
+type APITokenService interface {
+	List(ctx context.Context) ([]domain.APIToken, error)
+	Issue(ctx context.Context, token domain.APIToken) (domain.APIToken, string, error)
+	Revoke(ctx context.Context, tokenID string) error
+	Authenticate(ctx context.Context, token string) (ports.APIPrincipal, error)
+}
+
+type AuthorizationService interface {
+	RecordDenied(ctx context.Context, permission domain.Permission, metadata map[string]any)
+	RecordCSRFFailure(ctx context.Context, metadata map[string]any)
+}
+
+type DashboardService interface {
+	Metrics(ctx context.Context, now time.Time) (ports.DashboardMetrics, error)
+}
+
+type ReportService interface {
+	RevenueSeries(ctx context.Context, period domain.ReportPeriod, now time.Time, buckets int) ([]ports.RevenueSummary, error)
+	StudentsByStatus(ctx context.Context) ([]ports.StudentStatusSummary, error)
+	DelinquentSubscriptions(ctx context.Context, now time.Time) ([]ports.DelinquentSubscription, error)
+	UpcomingDue(ctx context.Context, start, end time.Time) ([]ports.DueSubscription, error)
 }
 
+// SessionConfig define o cookie e a expiracao das sessoes. TTL e o tempo
+// maximo de vida da sessao; IdleTimeout, quando informado, ativa a expiracao
+// deslizante renovada a cada atividade.
 type SessionConfig struct {
-	CookieName string
-	TTL        time.Duration
-	Secure     bool
-	SameSite   http.SameSite
+	CookieName  string
+	TTL         time.Duration
+	IdleTimeout time.Duration
+	Secure      bool
+	SameSite    http.SameSite
 }
 
 func New(services Services, sessions ports.SessionStore, config SessionConfig) *Handler {
@@ -89,17 +189,32 @@ func New(services Services, sessions ports.SessionStore, config SessionConfig) *
 	return &Handler{services: services, sessions: sessions, config: config}
 }
 
+func (h *Handler) SessionPolicy() httpmw.SessionPolicy {
+	return httpmw.SessionPolicy{
+		CookieName:  h.config.CookieName,
+		IdleTimeout: h.config.IdleTimeout,
+		MaxLifetime: h.config.TTL,
+		Secure:      h.config.Secure,
+		SameSite:    h.config.SameSite,
+	}
+}
+
 func (h *Handler) renderPage(w http.ResponseWriter, r *http.Request, page view.Page) {
 	page.Now = time.Now()
+	r = withCSRFToken(r)
 	if session, ok := httpmw.SessionFromContext(r.Context()); ok {
 		displayName := session.Name
 		if displayName == "" {
 			displayName = "Usuario"
 		}
 		page.CurrentUser = &view.UserInfo{
-			Name:        session.Name,
-			DisplayName: displayName,
-			Role:        string(session.Role),
+			Name:               session.Name,
+			DisplayName:        displayName,
+			Role:               string(session.Role),
+			CanManageUsers:     session.Role.Can(domain.PermissionUserManage),
+			CanViewAudit:       session.Role.Can(domain.PermissionAuditView),
+			CanManageAPITokens: session.Role.Can(domain.PermissionAPITokenManage),
+			CanManageCoupons:   session.Role.Can(domain.PermissionCouponManage),
 		}
 	}
 	if err := view.RenderPage(w, r, page); err != nil {
@@ -108,6 +223,7 @@ func (h *Handler) renderPage(w http.ResponseWriter, r *http.Request, page view.P
 }
 
 func (h *Handler) renderComponent(w http.ResponseWriter, r *http.Request, component templ.Component) {
+	r = withCSRFToken(r)
 	if err := view.RenderComponent(w, r, component); err != nil {
 		observability.Logger(r.Context()).Error("failed to render component", "err", err)
 	}
@@ -145,3 +261,12 @@ func (h *Handler) redirectHTMXOrRedirect(w http.ResponseWriter, r *http.Request,
 	}
 	http.Redirect(w, r, location, http.StatusSeeOther)
 }
+
+// withCSRFToken disponibiliza o token CSRF da sessao para os formularios.
+func withCSRFToken(r *http.Request) *http.Request {
+	session, ok := httpmw.SessionFromContext(r.Context())
+	if !ok || session.CSRFToken == "" {
+		return r
+	}
+	return r.WithContext(view.WithCSRFToken(r.Context(), session.CSRFToken))
+}
@@ -2,10 +2,41 @@ package handlers
 
 import (
 	"net/http"
+	"time"
 
+	"github.com/PabloPavan/jaiu/internal/observability"
+	"github.com/PabloPavan/jaiu/internal/ports"
 	"github.com/PabloPavan/jaiu/internal/view"
 )
 
 func (h *Handler) Home(w http.ResponseWriter, r *http.Request) {
-	h.renderPage(w, r, page("Dashboard", view.HomePage()))
+	data := h.buildHomeData(r)
+	h.renderHTMXOrPage(w, r, "Dashboard", view.HomePage(data), view.HomeMetrics(data))
+}
+
+func (h *Handler) buildHomeData(r *http.Request) view.HomePageData {
+	data := buildHomeMetrics(ports.DashboardMetrics{})
+	if h.services.Dashboard == nil {
+		data.Error = "Servico de indicadores indisponivel."
+		return data
+	}
+
+	ctx := r.Context()
+	metrics, err := h.services.Dashboard.Metrics(ctx, time.Now())
+	if err != nil {
+		observability.Logger(ctx).Error("failed to load dashboard metrics", "err", err)
+		data.Error = "Nao foi possivel carregar os indicadores."
+		return data
+	}
+
+	return buildHomeMetrics(metrics)
+}
+
+func buildHomeMetrics(metrics ports.DashboardMetrics) view.HomePageData {
+	return view.HomePageData{
+		ActiveStudents:          metrics.ActiveStudents,
+		DelinquentSubscriptions: metrics.DelinquentSubscriptions,
+		RevenueMonth:            formatBRL(metrics.RevenueMonthCents),
+		ProjectedRevenue:        formatBRL(metrics.ProjectedRevenueCents),
+	}
 }
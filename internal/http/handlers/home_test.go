@@ -0,0 +1,27 @@
+package handlers
+
+import (
+	"testing"
+
+	"github.com/PabloPavan/jaiu/internal/ports"
+)
+
+// Testa a formatacao das metricas do dashboard para a view.
+func TestBuildHomeMetrics(t *testing.T) {
+	data := buildHomeMetrics(ports.DashboardMetrics{
+		ActiveStudents:          10,
+		DelinquentSubscriptions: 2,
+		RevenueMonthCents:       150000,
+		ProjectedRevenueCents:   0,
+	})
+
+	if data.ActiveStudents != 10 || data.DelinquentSubscriptions != 2 {
+		t.Fatalf("unexpected counters: %#v", data)
+	}
+	if data.RevenueMonth != formatBRL(150000) {
+		t.Fatalf("expected revenue %q, got %q", formatBRL(150000), data.RevenueMonth)
+	}
+	if data.ProjectedRevenue != formatBRL(0) {
+		t.Fatalf("expected projected %q, got %q", formatBRL(0), data.ProjectedRevenue)
+	}
+}
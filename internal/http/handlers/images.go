@@ -4,6 +4,10 @@ import (
 	"context"
 	"mime/multipart"
 	"net/http"
+	"path"
+	"strings"
+
+	"github.com/PabloPavan/jaiu/internal/observability"
 )
 
 type ImageService interface {
@@ -40,6 +44,20 @@ func (h *Handler) ImageHandler() http.Handler {
 		if h.images.ImageService == nil {
 			return nil
 		}
-		return h.images.ImageService.Handler()
+		return h.auditPhotoViews(h.images.ImageService.Handler())
 	}
 }
+
+// auditPhotoViews registra o acesso a fotos de alunos. O caminho chega sem o
+// prefixo da rota, no formato /<chave>/<variante>.
+func (h *Handler) auditPhotoViews(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if h.services.Students != nil {
+			key, _, _ := strings.Cut(strings.TrimPrefix(path.Clean("/"+r.URL.Path), "/"), "/")
+			if err := h.services.Students.RecordPhotoView(r.Context(), key); err != nil {
+				observability.Logger(r.Context()).Error("failed to record photo view", "err", err)
+			}
+		}
+		next.ServeHTTP(w, r)
+	})
+}
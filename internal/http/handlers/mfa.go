@@ -0,0 +1,315 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/PabloPavan/jaiu/internal/auditctx"
+	httpmw "github.com/PabloPavan/jaiu/internal/http/middleware"
+	"github.com/PabloPavan/jaiu/internal/observability"
+	"github.com/PabloPavan/jaiu/internal/ports"
+	"github.com/PabloPavan/jaiu/internal/view"
+)
+
+const mfaCookiePath = "/auth/mfa"
+
+func (h *Handler) MFAChallenge(w http.ResponseWriter, r *http.Request) {
+	challenge, ok := h.loadMFAChallenge(w, r)
+	if !ok {
+		return
+	}
+
+	data := view.MFAChallengeData{Enroll: challenge.Enroll}
+	if challenge.Enroll {
+		if !h.fillMFAEnrollment(r, challenge.UserID, &data) {
+			http.Error(w, "erro ao iniciar segundo fator", http.StatusInternalServerError)
+			return
+		}
+	}
+	h.renderPage(w, r, page("Verificacao em duas etapas", view.MFAChallengePage(data)))
+}
+
+func (h *Handler) MFAChallengePost(w http.ResponseWriter, r *http.Request) {
+	challenge, ok := h.loadMFAChallenge(w, r)
+	if !ok {
+		return
+	}
+	cookie, _ := r.Cookie(h.mfaCookieName())
+
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, "erro ao ler formulario", http.StatusBadRequest)
+		return
+	}
+
+	data := view.MFAChallengeData{Enroll: challenge.Enroll}
+	render := func() {
+		if challenge.Enroll && !h.fillMFAEnrollment(r, challenge.UserID, &data) {
+			http.Error(w, "erro ao iniciar segundo fator", http.StatusInternalServerError)
+			return
+		}
+		h.renderPage(w, r, page("Verificacao em duas etapas", view.MFAChallengePage(data)))
+	}
+
+	code := strings.TrimSpace(r.FormValue("code"))
+	if code == "" {
+		data.Error = "Informe o codigo."
+		render()
+		return
+	}
+
+	ctx := auditctx.WithActor(r.Context(), auditctx.Actor{ID: challenge.UserID, Role: string(challenge.Role)})
+	completed, recoveryCodes, err := h.services.MFA.CompleteLogin(ctx, cookie.Value, code)
+	if err != nil {
+		switch {
+		case errors.Is(err, ports.ErrTooManyAttempts):
+			h.clearMFACookie(w)
+			h.renderPage(w, r, page("Entrar", view.LoginPage(view.LoginData{Error: "Muitas tentativas com codigo invalido. Entre novamente."})))
+		case errors.Is(err, ports.ErrNotFound):
+			h.clearMFACookie(w)
+			http.Redirect(w, r, "/auth/login", http.StatusSeeOther)
+		case errors.Is(err, ports.ErrUnauthorized):
+			data.Error = "Codigo invalido."
+			render()
+		default:
+			http.Error(w, "erro ao verificar codigo", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	h.clearMFACookie(w)
+	session := ports.Session{
+		UserID:             completed.UserID,
+		Name:               completed.Name,
+		Role:               completed.Role,
+		MustChangePassword: completed.MustChangePassword,
+	}
+	if err := h.startSession(w, r, session); err != nil {
+		http.Error(w, "erro ao criar sessao", http.StatusInternalServerError)
+		return
+	}
+
+	next := "/"
+	if completed.MustChangePassword {
+		next = "/account/password"
+	}
+	if len(recoveryCodes) > 0 {
+		h.renderPage(w, r, page("Codigos de recuperacao", view.MFARecoveryCodesPage(view.MFARecoveryCodesData{
+			Codes:       recoveryCodes,
+			ContinueURL: next,
+		})))
+		return
+	}
+	http.Redirect(w, r, next, http.StatusSeeOther)
+}
+
+func (h *Handler) MFASettings(w http.ResponseWriter, r *http.Request) {
+	session, ok := httpmw.SessionFromContext(r.Context())
+	if !ok {
+		http.Redirect(w, r, "/auth/login", http.StatusSeeOther)
+		return
+	}
+
+	data := view.MFASettingsData{}
+	h.fillMFAStatus(r, session, &data)
+	h.renderPage(w, r, page("Verificacao em duas etapas", view.MFASettingsPage(data)))
+}
+
+func (h *Handler) MFAEnroll(w http.ResponseWriter, r *http.Request) {
+	session, ok := httpmw.SessionFromContext(r.Context())
+	if !ok {
+		http.Redirect(w, r, "/auth/login", http.StatusSeeOther)
+		return
+	}
+
+	data := view.MFASettingsData{}
+	if h.services.MFA == nil {
+		data.Error = "Servico de segundo fator indisponivel."
+		h.renderMFASettings(w, r, data)
+		return
+	}
+
+	enrollment, err := h.services.MFA.BeginEnrollment(r.Context(), session.UserID)
+	if err != nil {
+		h.fillMFAStatus(r, session, &data)
+		data.Error = mfaErrorMessage(err, "Nao foi possivel iniciar o cadastro.")
+		h.renderMFASettings(w, r, data)
+		return
+	}
+
+	data.Secret = enrollment.Secret
+	data.ProvisioningURI = enrollment.ProvisioningURI
+	h.renderMFASettings(w, r, data)
+}
+
+func (h *Handler) MFAConfirm(w http.ResponseWriter, r *http.Request) {
+	h.mfaCodeAction(w, r, func(session ports.Session, code string, data *view.MFASettingsData) error {
+		codes, err := h.services.MFA.ConfirmEnrollment(r.Context(), session.UserID, code)
+		if err != nil {
+			if errors.Is(err, ports.ErrUnauthorized) {
+				if enrollment, enrollErr := h.services.MFA.BeginEnrollment(r.Context(), session.UserID); enrollErr == nil {
+					data.Secret = enrollment.Secret
+					data.ProvisioningURI = enrollment.ProvisioningURI
+				}
+			}
+			return err
+		}
+		data.RecoveryCodes = codes
+		data.Success = "Segundo fator ativado."
+		return nil
+	})
+}
+
+func (h *Handler) MFADisable(w http.ResponseWriter, r *http.Request) {
+	h.mfaCodeAction(w, r, func(session ports.Session, code string, data *view.MFASettingsData) error {
+		if err := h.services.MFA.Disable(r.Context(), session.UserID, code); err != nil {
+			return err
+		}
+		data.Success = "Segundo fator desativado."
+		return nil
+	})
+}
+
+func (h *Handler) MFARecoveryCodes(w http.ResponseWriter, r *http.Request) {
+	h.mfaCodeAction(w, r, func(session ports.Session, code string, data *view.MFASettingsData) error {
+		codes, err := h.services.MFA.RegenerateRecoveryCodes(r.Context(), session.UserID, code)
+		if err != nil {
+			return err
+		}
+		data.RecoveryCodes = codes
+		data.Success = "Novos codigos de recuperacao gerados."
+		return nil
+	})
+}
+
+// mfaCodeAction trata as acoes da tela de segundo fator que exigem um codigo
+// valido, recarregando o status antes de renderizar o resultado.
+func (h *Handler) mfaCodeAction(w http.ResponseWriter, r *http.Request, action func(session ports.Session, code string, data *view.MFASettingsData) error) {
+	session, ok := httpmw.SessionFromContext(r.Context())
+	if !ok {
+		http.Redirect(w, r, "/auth/login", http.StatusSeeOther)
+		return
+	}
+
+	data := view.MFASettingsData{}
+	if h.services.MFA == nil {
+		data.Error = "Servico de segundo fator indisponivel."
+		h.renderMFASettings(w, r, data)
+		return
+	}
+	if err := r.ParseForm(); err != nil {
+		data.Error = "Nao foi possivel ler o formulario."
+		h.renderMFASettings(w, r, data)
+		return
+	}
+
+	code := strings.TrimSpace(r.FormValue("code"))
+	var err error
+	if code == "" {
+		err = ports.ErrUnauthorized
+	} else {
+		err = action(session, code, &data)
+	}
+
+	pending := data
+	h.fillMFAStatus(r, session, &data)
+	data.Secret = pending.Secret
+	data.ProvisioningURI = pending.ProvisioningURI
+	if err != nil {
+		data.Error = mfaErrorMessage(err, "Nao foi possivel concluir a operacao.")
+	}
+	h.renderMFASettings(w, r, data)
+}
+
+func (h *Handler) fillMFAStatus(r *http.Request, session ports.Session, data *view.MFASettingsData) {
+	if h.services.MFA == nil {
+		data.Error = "Servico de segundo fator indisponivel."
+		return
+	}
+	status, err := h.services.MFA.Status(r.Context(), session.UserID, session.Role)
+	if err != nil {
+		observability.Logger(r.Context()).Error("failed to load mfa status", "err", err)
+		data.Error = "Nao foi possivel carregar o segundo fator."
+		return
+	}
+	data.Enabled = status.Enabled
+	data.Required = status.Required
+	data.RecoveryCodesLeft = status.RecoveryCodesLeft
+}
+
+func (h *Handler) fillMFAEnrollment(r *http.Request, userID string, data *view.MFAChallengeData) bool {
+	enrollment, err := h.services.MFA.BeginEnrollment(r.Context(), userID)
+	if err != nil {
+		observability.Logger(r.Context()).Error("failed to begin mfa enrollment", "err", err)
+		return false
+	}
+	data.Secret = enrollment.Secret
+	data.ProvisioningURI = enrollment.ProvisioningURI
+	return true
+}
+
+func (h *Handler) renderMFASettings(w http.ResponseWriter, r *http.Request, data view.MFASettingsData) {
+	h.renderFormError(w, r, "Verificacao em duas etapas", view.MFASettingsPage(data))
+}
+
+// loadMFAChallenge carrega o desafio pendente do cookie, voltando ao login
+// quando ele nao existe ou expirou.
+func (h *Handler) loadMFAChallenge(w http.ResponseWriter, r *http.Request) (ports.LoginChallenge, bool) {
+	if h.services.MFA == nil {
+		http.Redirect(w, r, "/auth/login", http.StatusSeeOther)
+		return ports.LoginChallenge{}, false
+	}
+
+	cookie, err := r.Cookie(h.mfaCookieName())
+	if err != nil {
+		http.Redirect(w, r, "/auth/login", http.StatusSeeOther)
+		return ports.LoginChallenge{}, false
+	}
+	challenge, err := h.services.MFA.LoginChallenge(r.Context(), cookie.Value)
+	if err != nil {
+		h.clearMFACookie(w)
+		http.Redirect(w, r, "/auth/login", http.StatusSeeOther)
+		return ports.LoginChallenge{}, false
+	}
+	return challenge, true
+}
+
+func (h *Handler) mfaCookieName() string {
+	return h.config.CookieName + "_mfa"
+}
+
+func (h *Handler) setMFACookie(w http.ResponseWriter, token string) {
+	http.SetCookie(w, &http.Cookie{
+		Name:     h.mfaCookieName(),
+		Value:    token,
+		Path:     mfaCookiePath,
+		HttpOnly: true,
+		Secure:   h.config.Secure,
+		SameSite: h.config.SameSite,
+	})
+}
+
+func (h *Handler) clearMFACookie(w http.ResponseWriter) {
+	http.SetCookie(w, &http.Cookie{
+		Name:     h.mfaCookieName(),
+		Value:    "",
+		Path:     mfaCookiePath,
+		MaxAge:   -1,
+		HttpOnly: true,
+		Secure:   h.config.Secure,
+		SameSite: h.config.SameSite,
+	})
+}
+
+func mfaErrorMessage(err error, fallback string) string {
+	switch {
+	case errors.Is(err, ports.ErrUnauthorized):
+		return "Codigo invalido."
+	case errors.Is(err, ports.ErrConflict):
+		return "O segundo fator ja esta ativo."
+	case errors.Is(err, ports.ErrNotFound):
+		return "Segundo fator nao configurado."
+	default:
+		return fallback
+	}
+}
@@ -0,0 +1,194 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	httpmw "github.com/PabloPavan/jaiu/internal/http/middleware"
+	"github.com/PabloPavan/jaiu/internal/observability"
+	"github.com/PabloPavan/jaiu/internal/ports"
+	"github.com/PabloPavan/jaiu/internal/view"
+)
+
+func (h *Handler) PasswordEdit(w http.ResponseWriter, r *http.Request) {
+	data := view.PasswordChangeData{}
+	if session, ok := httpmw.SessionFromContext(r.Context()); ok {
+		data.Forced = session.MustChangePassword
+	}
+	if r.URL.Query().Get("changed") == "1" {
+		data.Success = "Senha alterada com sucesso."
+	}
+	h.renderPage(w, r, page("Alterar senha", view.PasswordChangePage(data)))
+}
+
+func (h *Handler) PasswordUpdate(w http.ResponseWriter, r *http.Request) {
+	session, ok := httpmw.SessionFromContext(r.Context())
+	if !ok {
+		http.Redirect(w, r, "/auth/login", http.StatusSeeOther)
+		return
+	}
+
+	data := view.PasswordChangeData{Forced: session.MustChangePassword}
+	render := func() {
+		h.renderFormError(w, r, "Alterar senha", view.PasswordChangePage(data))
+	}
+
+	if err := r.ParseForm(); err != nil {
+		data.Error = "Nao foi possivel ler o formulario."
+		render()
+		return
+	}
+
+	current := r.FormValue("current_password")
+	next := r.FormValue("new_password")
+	if current == "" || next == "" {
+		data.Error = "Informe a senha atual e a nova senha."
+		render()
+		return
+	}
+	if len(next) < 8 {
+		data.Error = "A senha deve ter pelo menos 8 caracteres."
+		render()
+		return
+	}
+	if next != r.FormValue("confirm_password") {
+		data.Error = "A confirmacao nao confere com a nova senha."
+		render()
+		return
+	}
+	if next == current {
+		data.Error = "A nova senha deve ser diferente da atual."
+		render()
+		return
+	}
+
+	if h.services.Passwords == nil {
+		data.Error = "Servico de senhas indisponivel."
+		render()
+		return
+	}
+
+	if err := h.services.Passwords.ChangePassword(r.Context(), session.UserID, current, next); err != nil {
+		data.Error = passwordErrorMessage(err, "Nao foi possivel alterar a senha.")
+		render()
+		return
+	}
+
+	// O servico ja encerrou todas as sessoes do usuario; so a nova sessao aberta
+	// aqui continua valida.
+	if h.sessions != nil {
+		if cookie, err := r.Cookie(h.config.CookieName); err == nil {
+			_ = h.sessions.Delete(r.Context(), cookie.Value)
+		}
+		session.MustChangePassword = false
+		if err := h.startSession(w, r, session); err != nil {
+			observability.Logger(r.Context()).Error("failed to start session after password change", "err", err)
+			h.redirectHTMXOrRedirect(w, r, "/auth/login")
+			return
+		}
+	}
+
+	if data.Forced {
+		h.redirectHTMXOrRedirect(w, r, "/")
+		return
+	}
+
+	h.redirectHTMXOrRedirect(w, r, "/account/password?changed=1")
+}
+
+func (h *Handler) ForgotPassword(w http.ResponseWriter, r *http.Request) {
+	h.renderPage(w, r, page("Esqueci minha senha", view.ForgotPasswordPage(view.ForgotPasswordData{})))
+}
+
+func (h *Handler) ForgotPasswordPost(w http.ResponseWriter, r *http.Request) {
+	data := view.ForgotPasswordData{}
+	if err := r.ParseForm(); err != nil {
+		data.Error = "Nao foi possivel ler o formulario."
+		h.renderPage(w, r, page("Esqueci minha senha", view.ForgotPasswordPage(data)))
+		return
+	}
+
+	data.Email = strings.TrimSpace(r.FormValue("email"))
+	if !strings.Contains(data.Email, "@") {
+		data.Error = "Informe um email valido."
+		h.renderPage(w, r, page("Esqueci minha senha", view.ForgotPasswordPage(data)))
+		return
+	}
+
+	if h.services.Passwords == nil {
+		data.Error = "Servico de senhas indisponivel."
+		h.renderPage(w, r, page("Esqueci minha senha", view.ForgotPasswordPage(data)))
+		return
+	}
+
+	if err := h.services.Passwords.RequestReset(r.Context(), data.Email); err != nil {
+		observability.Logger(r.Context()).Error("failed to request password reset", "err", err)
+		data.Error = "Nao foi possivel enviar o link agora. Tente novamente."
+		h.renderPage(w, r, page("Esqueci minha senha", view.ForgotPasswordPage(data)))
+		return
+	}
+
+	data.Sent = true
+	h.renderPage(w, r, page("Esqueci minha senha", view.ForgotPasswordPage(data)))
+}
+
+// ResetPassword abre o formulario do link enviado por email. O token chega na
+// query e segue no corpo do POST; no-referrer evita que vaze para outros sites.
+func (h *Handler) ResetPassword(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Referrer-Policy", "no-referrer")
+	data := view.ResetPasswordData{Token: r.URL.Query().Get("token")}
+	h.renderPage(w, r, page("Redefinir senha", view.ResetPasswordPage(data)))
+}
+
+func (h *Handler) ResetPasswordPost(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Referrer-Policy", "no-referrer")
+	data := view.ResetPasswordData{}
+	render := func() {
+		h.renderPage(w, r, page("Redefinir senha", view.ResetPasswordPage(data)))
+	}
+
+	if err := r.ParseForm(); err != nil {
+		data.Error = "Nao foi possivel ler o formulario."
+		render()
+		return
+	}
+	data.Token = r.PostFormValue("token")
+	token := data.Token
+
+	password := r.FormValue("new_password")
+	if len(password) < 8 {
+		data.Error = "A senha deve ter pelo menos 8 caracteres."
+		render()
+		return
+	}
+	if password != r.FormValue("confirm_password") {
+		data.Error = "A confirmacao nao confere com a nova senha."
+		render()
+		return
+	}
+
+	if h.services.Passwords == nil {
+		data.Error = "Servico de senhas indisponivel."
+		render()
+		return
+	}
+
+	if err := h.services.Passwords.ResetPassword(r.Context(), token, password); err != nil {
+		data.Error = passwordErrorMessage(err, "Nao foi possivel redefinir a senha.")
+		render()
+		return
+	}
+
+	http.Redirect(w, r, "/auth/login?reset=1", http.StatusSeeOther)
+}
+
+func passwordErrorMessage(err error, fallback string) string {
+	switch {
+	case errors.Is(err, ports.ErrUnauthorized):
+		return "Senha atual incorreta."
+	case errors.Is(err, ports.ErrNotFound):
+		return "Link invalido ou expirado. Solicite uma nova redefinicao."
+	}
+	return fallback
+}
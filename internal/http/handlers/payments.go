@@ -45,6 +45,9 @@ func (h *Handler) PaymentsCreate(w http.ResponseWriter, r *http.Request) {
 	_, err = h.services.Payments.Register(r.Context(), payment)
 	if err != nil {
 		data.Error = "Nao foi possivel salvar o pagamento."
+		if errors.Is(err, ports.ErrSerialization) {
+			data.Error = "Outro pagamento foi registrado ao mesmo tempo. Tente novamente."
+		}
 		h.renderFormError(w, r, data.Title, view.PaymentFormPage(data))
 		return
 	}
@@ -134,6 +137,7 @@ func (h *Handler) buildPaymentsData(r *http.Request) view.PaymentsPageData {
 		SubscriptionID: subscriptionID,
 		Status:         status,
 		Subscriptions:  toSubscriptionOptions(subscriptions, r, h),
+		CanReverse:     can(r, domain.PermissionPaymentReverse),
 	}
 
 	if h.services.Payments == nil {
@@ -231,7 +235,8 @@ func (h *Handler) paymentFormEditData(r *http.Request, payment domain.Payment) v
 		Action:         "/payments/" + payment.ID,
 		SubmitLabel:    "Salvar",
 		DeleteAction:   "/payments/" + payment.ID + "/reverse",
-		ShowDelete:     payment.ID != "",
+		HistoryURL:     h.historyURL(r, "/payments/"+payment.ID),
+		ShowDelete:     payment.ID != "" && can(r, domain.PermissionPaymentReverse),
 		SubscriptionID: payment.SubscriptionID,
 		PaidAt:         formatDateBRValue(payment.PaidAt),
 		Amount:         formatAmountInput(payment.AmountCents),
@@ -378,8 +383,8 @@ func ensureSubscriptionOption(ctx context.Context, options []view.SubscriptionOp
 
 	studentName := subscription.StudentID
 	if h.services.Students != nil {
-		if student, err := h.services.Students.FindByID(ctx, subscription.StudentID); err == nil {
-			studentName = student.FullName
+		if name, err := h.services.Students.FindName(ctx, subscription.StudentID); err == nil {
+			studentName = name
 		}
 	}
 	planName := subscription.PlanID
@@ -132,6 +132,16 @@ func formatCents(cents int64) string {
 	return formatBRL(cents)
 }
 
+// formatInstallments descreve o parcelamento do plano, como "12x de R$ 100,00".
+// Planos sem parcelamento nao tem descricao.
+func formatInstallments(plan domain.Plan) string {
+	installments := plan.InstallmentCount()
+	if installments == 1 {
+		return ""
+	}
+	return strconv.Itoa(installments) + "x de " + formatBRL(plan.PriceCents/int64(installments))
+}
+
 func formatCentsInput(cents int64) string {
 	value := fmt.Sprintf("%.2f", float64(cents)/100)
 	return strings.ReplaceAll(value, ".", ",")
@@ -163,6 +173,10 @@ func planFormEditData(plan domain.Plan) view.PlanFormData {
 		DurationDays: strconv.Itoa(plan.DurationDays),
 		Price:        formatCentsInput(plan.PriceCents),
 		Description:  plan.Description,
+		LateFee:      formatCentsInput(int64(plan.LateFeeBasisPoints)),
+		LateInterest: formatCentsInput(int64(plan.LateInterestBasisPoints)),
+		MaxFreeze:    strconv.Itoa(plan.MaxFreezeDays),
+		Installments: strconv.Itoa(plan.InstallmentCount()),
 		Active:       plan.Active,
 	}
 }
@@ -195,18 +209,75 @@ func parsePlanForm(r *http.Request, data *view.PlanFormData) (domain.Plan, error
 	description := strings.TrimSpace(r.FormValue("description"))
 	data.Description = description
 
+	lateFeeRaw := strings.TrimSpace(r.FormValue("late_fee"))
+	data.LateFee = lateFeeRaw
+	lateFee, err := parseBasisPointsOptional(lateFeeRaw)
+	if err != nil {
+		return domain.Plan{}, errors.New("Multa invalida. Use um percentual de 0 a 100.")
+	}
+
+	lateInterestRaw := strings.TrimSpace(r.FormValue("late_interest"))
+	data.LateInterest = lateInterestRaw
+	lateInterest, err := parseBasisPointsOptional(lateInterestRaw)
+	if err != nil {
+		return domain.Plan{}, errors.New("Juros invalidos. Use um percentual de 0 a 100.")
+	}
+
+	maxFreezeRaw := strings.TrimSpace(r.FormValue("max_freeze_days"))
+	data.MaxFreeze = maxFreezeRaw
+	maxFreeze := 0
+	if maxFreezeRaw != "" {
+		maxFreeze, err = strconv.Atoi(maxFreezeRaw)
+		if err != nil || maxFreeze < 0 || maxFreeze > 366 {
+			return domain.Plan{}, errors.New("Dias de trancamento invalidos. Use um numero de 0 a 366.")
+		}
+	}
+
+	installmentsRaw := strings.TrimSpace(r.FormValue("installments"))
+	data.Installments = installmentsRaw
+	installments := 1
+	if installmentsRaw != "" {
+		installments, err = strconv.Atoi(installmentsRaw)
+		if err != nil || installments < 1 {
+			return domain.Plan{}, errors.New("Numero de parcelas invalido.")
+		}
+	}
+	if limit := domain.MaxInstallmentsFor(duration); installments > limit {
+		return domain.Plan{}, fmt.Errorf("Este plano aceita no maximo %d parcelas mensais.", limit)
+	}
+
 	active := r.FormValue("active") != ""
 	data.Active = active
 
 	return domain.Plan{
-		Name:         name,
-		DurationDays: duration,
-		PriceCents:   priceCents,
-		Description:  description,
-		Active:       active,
+		Name:                    name,
+		DurationDays:            duration,
+		PriceCents:              priceCents,
+		Description:             description,
+		Active:                  active,
+		LateFeeBasisPoints:      lateFee,
+		LateInterestBasisPoints: lateInterest,
+		MaxFreezeDays:           maxFreeze,
+		Installments:            installments,
 	}, nil
 }
 
+// parseBasisPointsOptional le um percentual com duas casas ("2,50") em
+// centesimos de ponto percentual. Vazio significa zero.
+func parseBasisPointsOptional(value string) (int, error) {
+	if value == "" {
+		return 0, nil
+	}
+	parsed, err := parsePriceCents(value)
+	if err != nil {
+		return 0, err
+	}
+	if parsed < 0 || parsed > domain.MaxBasisPoints {
+		return 0, errors.New("percentual fora do intervalo")
+	}
+	return int(parsed), nil
+}
+
 func parsePriceCents(value string) (int64, error) {
 	clean := strings.TrimSpace(value)
 	if clean == "" {
@@ -228,7 +299,11 @@ func parsePriceCents(value string) (int64, error) {
 }
 
 func (h *Handler) buildPlansData(r *http.Request) view.PlansPageData {
-	data := view.PlansPageData{}
+	data := view.PlansPageData{
+		CanCreate: can(r, domain.PermissionPlanCreate),
+		CanUpdate: can(r, domain.PermissionPlanUpdate),
+		CanDelete: can(r, domain.PermissionPlanDelete),
+	}
 
 	if h.services.Plans != nil {
 		plans, err := h.services.Plans.ListActive(r.Context())
@@ -242,6 +317,7 @@ func (h *Handler) buildPlansData(r *http.Request) view.PlansPageData {
 					Name:         plan.Name,
 					DurationDays: plan.DurationDays,
 					Price:        formatCents(plan.PriceCents),
+					Installments: formatInstallments(plan),
 					Description:  plan.Description,
 				}
 				data.Items = append(data.Items, item)
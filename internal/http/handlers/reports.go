@@ -2,10 +2,178 @@ package handlers
 
 import (
 	"net/http"
+	"strconv"
+	"strings"
+	"time"
 
+	"github.com/PabloPavan/jaiu/internal/domain"
+	"github.com/PabloPavan/jaiu/internal/observability"
+	"github.com/PabloPavan/jaiu/internal/ports"
 	"github.com/PabloPavan/jaiu/internal/view"
 )
 
+const reportRevenueBuckets = 6
+
 func (h *Handler) ReportsIndex(w http.ResponseWriter, r *http.Request) {
-	h.renderPage(w, r, page("Relatórios", view.ReportsPage()))
+	data := h.buildReportsData(r)
+	h.renderHTMXOrPage(w, r, "Relatórios", view.ReportsPage(data), view.ReportsContent(data))
+}
+
+func (h *Handler) buildReportsData(r *http.Request) view.ReportsPageData {
+	period := parseReportPeriod(strings.TrimSpace(r.FormValue("period")))
+	dueDays := parseDueDays(strings.TrimSpace(r.FormValue("due_days")))
+
+	data := view.ReportsPageData{
+		Period:          string(period),
+		DueDays:         dueDays,
+		Revenue:         formatBRL(0),
+		RevenueLabel:    reportPeriodLabel(period),
+		ActiveRate:      "0%",
+		DelinquencyRate: "0%",
+	}
+
+	if h.services.Reports == nil {
+		return data
+	}
+
+	ctx := r.Context()
+	now := time.Now()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+
+	series, err := h.services.Reports.RevenueSeries(ctx, period, now, reportRevenueBuckets)
+	if err != nil {
+		observability.Logger(ctx).Error("failed to load revenue report", "err", err)
+	} else {
+		data.RevenueSeries = make([]view.ReportRevenueItem, 0, len(series))
+		for _, summary := range series {
+			data.RevenueSeries = append(data.RevenueSeries, view.ReportRevenueItem{
+				Label:       reportBucketLabel(period, summary.Start),
+				Amount:      formatBRL(summary.TotalCents),
+				GrossBilled: formatBRL(summary.GrossBilledCents),
+				Discount:    formatBRL(summary.DiscountCents),
+				NetBilled:   formatBRL(summary.GrossBilledCents - summary.DiscountCents),
+			})
+		}
+		if len(series) > 0 {
+			data.Revenue = formatBRL(series[len(series)-1].TotalCents)
+		}
+	}
+
+	var activeStudents int64
+	statuses, err := h.services.Reports.StudentsByStatus(ctx)
+	if err != nil {
+		observability.Logger(ctx).Error("failed to load students by status", "err", err)
+	} else {
+		data.StatusItems, activeStudents = buildReportStatusItems(statuses)
+		for _, item := range data.StatusItems {
+			if item.Status == string(domain.StudentActive) {
+				data.ActiveRate = item.Percent
+			}
+		}
+	}
+
+	delinquents, err := h.services.Reports.DelinquentSubscriptions(ctx, today)
+	if err != nil {
+		observability.Logger(ctx).Error("failed to load delinquent subscriptions", "err", err)
+	} else {
+		students := make(map[string]struct{}, len(delinquents))
+		data.Delinquents = make([]view.ReportSubscriptionItem, 0, len(delinquents))
+		for _, item := range delinquents {
+			students[item.StudentID] = struct{}{}
+			data.Delinquents = append(data.Delinquents, view.ReportSubscriptionItem{
+				ID:          item.SubscriptionID,
+				StudentName: item.StudentName,
+				PlanName:    item.PlanName,
+				EndDate:     formatDateBRValue(item.EndDate),
+				DaysOverdue: item.DaysOverdue,
+			})
+		}
+		data.DelinquencyRate = formatPercent(int64(len(students)), activeStudents)
+	}
+
+	upcoming, err := h.services.Reports.UpcomingDue(ctx, today, today.AddDate(0, 0, dueDays))
+	if err != nil {
+		observability.Logger(ctx).Error("failed to load upcoming due subscriptions", "err", err)
+	} else {
+		data.Upcoming = make([]view.ReportSubscriptionItem, 0, len(upcoming))
+		for _, item := range upcoming {
+			data.Upcoming = append(data.Upcoming, view.ReportSubscriptionItem{
+				ID:          item.SubscriptionID,
+				StudentName: item.StudentName,
+				PlanName:    item.PlanName,
+				EndDate:     formatDateBRValue(item.EndDate),
+			})
+		}
+	}
+
+	return data
+}
+
+func buildReportStatusItems(statuses []ports.StudentStatusSummary) ([]view.ReportStatusItem, int64) {
+	var total, active int64
+	for _, summary := range statuses {
+		total += summary.Total
+		if summary.Status == domain.StudentActive {
+			active = summary.Total
+		}
+	}
+
+	items := make([]view.ReportStatusItem, 0, len(statuses))
+	for _, summary := range statuses {
+		items = append(items, view.ReportStatusItem{
+			Status:  string(summary.Status),
+			Label:   statusPresentation(summary.Status),
+			Total:   summary.Total,
+			Percent: formatPercent(summary.Total, total),
+		})
+	}
+	return items, active
+}
+
+func parseReportPeriod(value string) domain.ReportPeriod {
+	period := domain.ReportPeriod(strings.ToLower(value))
+	if !period.IsValid() {
+		return domain.ReportMonthly
+	}
+	return period
+}
+
+func parseDueDays(value string) int {
+	switch value {
+	case "7":
+		return 7
+	case "15":
+		return 15
+	default:
+		return 30
+	}
+}
+
+func reportPeriodLabel(period domain.ReportPeriod) string {
+	switch period {
+	case domain.ReportDaily:
+		return "hoje"
+	case domain.ReportWeekly:
+		return "semana atual"
+	default:
+		return "mes atual"
+	}
+}
+
+func reportBucketLabel(period domain.ReportPeriod, start time.Time) string {
+	switch period {
+	case domain.ReportMonthly:
+		return start.Format("01/2006")
+	case domain.ReportWeekly:
+		return "Semana de " + start.Format("02/01")
+	default:
+		return start.Format("02/01")
+	}
+}
+
+func formatPercent(part, total int64) string {
+	if total <= 0 {
+		return "0%"
+	}
+	return strconv.FormatInt((part*100+total/2)/total, 10) + "%"
 }
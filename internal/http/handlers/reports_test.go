@@ -0,0 +1,58 @@
+package handlers
+
+import (
+	"testing"
+
+	"github.com/PabloPavan/jaiu/internal/domain"
+	"github.com/PabloPavan/jaiu/internal/ports"
+)
+
+// Testa o parse do periodo do relatorio com default mensal.
+func TestParseReportPeriod(t *testing.T) {
+	if got := parseReportPeriod("weekly"); got != domain.ReportWeekly {
+		t.Fatalf("expected weekly, got %q", got)
+	}
+	if got := parseReportPeriod("DAILY"); got != domain.ReportDaily {
+		t.Fatalf("expected daily, got %q", got)
+	}
+	if got := parseReportPeriod("yearly"); got != domain.ReportMonthly {
+		t.Fatalf("expected default monthly, got %q", got)
+	}
+}
+
+// Testa o filtro de proximos vencimentos limitado a 7/15/30 dias.
+func TestParseDueDays(t *testing.T) {
+	tests := []struct {
+		value string
+		want  int
+	}{
+		{"7", 7},
+		{"15", 15},
+		{"30", 30},
+		{"", 30},
+		{"90", 30},
+	}
+
+	for _, tt := range tests {
+		if got := parseDueDays(tt.value); got != tt.want {
+			t.Fatalf("parseDueDays(%q) = %d, want %d", tt.value, got, tt.want)
+		}
+	}
+}
+
+// Testa o calculo de percentuais por status de aluno.
+func TestBuildReportStatusItems(t *testing.T) {
+	items, active := buildReportStatusItems([]ports.StudentStatusSummary{
+		{Status: domain.StudentActive, Total: 3},
+		{Status: domain.StudentInactive, Total: 1},
+	})
+	if active != 3 {
+		t.Fatalf("expected 3 active students, got %d", active)
+	}
+	if len(items) != 2 || items[0].Percent != "75%" || items[1].Percent != "25%" {
+		t.Fatalf("unexpected status items: %#v", items)
+	}
+	if got := formatPercent(1, 0); got != "0%" {
+		t.Fatalf("expected 0%% for empty base, got %q", got)
+	}
+}
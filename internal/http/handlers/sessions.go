@@ -0,0 +1,152 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	httpmw "github.com/PabloPavan/jaiu/internal/http/middleware"
+	"github.com/PabloPavan/jaiu/internal/observability"
+	"github.com/PabloPavan/jaiu/internal/ports"
+	"github.com/PabloPavan/jaiu/internal/view"
+	"github.com/go-chi/chi/v5"
+)
+
+func (h *Handler) SessionsIndex(w http.ResponseWriter, r *http.Request) {
+	data := h.buildSessionsData(r)
+	h.renderPage(w, r, page("Minhas sessoes", view.SessionsPage(data)))
+}
+
+func (h *Handler) SessionsRevoke(w http.ResponseWriter, r *http.Request) {
+	current, ok := httpmw.SessionFromContext(r.Context())
+	if !ok || h.services.Sessions == nil {
+		http.NotFound(w, r)
+		return
+	}
+
+	sessionID := chi.URLParam(r, "sessionID")
+	if err := h.services.Sessions.Revoke(r.Context(), current.UserID, sessionID); err != nil {
+		if errors.Is(err, ports.ErrNotFound) {
+			http.NotFound(w, r)
+			return
+		}
+		observability.Logger(r.Context()).Error("failed to revoke session", "err", err)
+		http.Error(w, "Erro ao encerrar sessao.", http.StatusInternalServerError)
+		return
+	}
+
+	if sessionID == current.ID {
+		h.clearSessionCookie(w)
+		h.redirectHTMXOrRedirect(w, r, "/auth/login")
+		return
+	}
+
+	h.renderHTMXOrRedirect(w, r, "/account/sessions", func() {
+		data := h.buildSessionsData(r)
+		h.renderComponent(w, r, view.SessionsList(data))
+	})
+}
+
+func (h *Handler) SessionsRevokeAll(w http.ResponseWriter, r *http.Request) {
+	current, ok := httpmw.SessionFromContext(r.Context())
+	if !ok || h.services.Sessions == nil {
+		http.NotFound(w, r)
+		return
+	}
+
+	if err := h.services.Sessions.RevokeAll(r.Context(), current.UserID); err != nil {
+		observability.Logger(r.Context()).Error("failed to revoke sessions", "err", err)
+		http.Error(w, "Erro ao encerrar sessoes.", http.StatusInternalServerError)
+		return
+	}
+
+	h.clearSessionCookie(w)
+	h.redirectHTMXOrRedirect(w, r, "/auth/login")
+}
+
+func (h *Handler) buildSessionsData(r *http.Request) view.SessionsPageData {
+	data := view.SessionsPageData{}
+	current, ok := httpmw.SessionFromContext(r.Context())
+	if !ok {
+		return data
+	}
+	if h.services.Sessions == nil {
+		data.Error = "Servico de sessoes indisponivel."
+		return data
+	}
+
+	sessions, err := h.services.Sessions.ListByUser(r.Context(), current.UserID)
+	if err != nil {
+		observability.Logger(r.Context()).Error("failed to list sessions", "err", err)
+		data.Error = "Nao foi possivel carregar as sessoes."
+		return data
+	}
+
+	data.Items = make([]view.SessionItem, 0, len(sessions))
+	for _, session := range sessions {
+		ip := session.IP
+		if ip == "" {
+			ip = "desconhecido"
+		}
+		data.Items = append(data.Items, view.SessionItem{
+			ID:        session.ID,
+			Device:    deviceLabel(session.UserAgent),
+			IP:        ip,
+			CreatedAt: formatDateTimeBR(session.CreatedAt),
+			LastSeen:  formatDateTimeBR(session.LastSeenAt),
+			Current:   session.ID != "" && session.ID == current.ID,
+		})
+	}
+
+	return data
+}
+
+func deviceLabel(userAgent string) string {
+	ua := strings.ToLower(userAgent)
+	if ua == "" {
+		return "Dispositivo desconhecido"
+	}
+
+	browser := "Navegador"
+	switch {
+	case strings.Contains(ua, "edg/"):
+		browser = "Edge"
+	case strings.Contains(ua, "opr/") || strings.Contains(ua, "opera"):
+		browser = "Opera"
+	case strings.Contains(ua, "firefox/"):
+		browser = "Firefox"
+	case strings.Contains(ua, "chrome/") || strings.Contains(ua, "crios/"):
+		browser = "Chrome"
+	case strings.Contains(ua, "safari/"):
+		browser = "Safari"
+	}
+
+	system := ""
+	switch {
+	case strings.Contains(ua, "ipad"):
+		system = "iPad"
+	case strings.Contains(ua, "iphone"):
+		system = "iPhone"
+	case strings.Contains(ua, "android"):
+		system = "Android"
+	case strings.Contains(ua, "windows"):
+		system = "Windows"
+	case strings.Contains(ua, "mac os"):
+		system = "macOS"
+	case strings.Contains(ua, "linux"):
+		system = "Linux"
+	}
+
+	if system == "" {
+		return browser
+	}
+	return browser + " em " + system
+}
+
+func formatDateTimeBR(value time.Time) string {
+	if value.IsZero() {
+		return "-"
+	}
+	return value.Local().Format("02/01/2006 15:04")
+}
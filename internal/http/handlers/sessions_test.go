@@ -0,0 +1,38 @@
+package handlers
+
+import (
+	"testing"
+	"time"
+)
+
+// Testa a identificacao do dispositivo a partir do user agent.
+func TestDeviceLabel(t *testing.T) {
+	tests := []struct {
+		userAgent string
+		want      string
+	}{
+		{"", "Dispositivo desconhecido"},
+		{"Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0 Safari/537.36", "Chrome em Windows"},
+		{"Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0 Safari/537.36 Edg/120.0", "Edge em Windows"},
+		{"Mozilla/5.0 (iPad; CPU OS 17_0 like Mac OS X) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/17.0 Mobile/15E148 Safari/604.1", "Safari em iPad"},
+		{"Mozilla/5.0 (X11; Linux x86_64; rv:120.0) Gecko/20100101 Firefox/120.0", "Firefox em Linux"},
+		{"curl/8.0", "Navegador"},
+	}
+
+	for _, tt := range tests {
+		if got := deviceLabel(tt.userAgent); got != tt.want {
+			t.Fatalf("deviceLabel(%q) = %q, want %q", tt.userAgent, got, tt.want)
+		}
+	}
+}
+
+// Testa formatacao de data e hora para exibicao.
+func TestFormatDateTimeBR(t *testing.T) {
+	if got := formatDateTimeBR(time.Time{}); got != "-" {
+		t.Fatalf("expected - for zero time, got %q", got)
+	}
+	value := time.Date(2024, 1, 2, 15, 4, 0, 0, time.Local)
+	if got := formatDateTimeBR(value); got != "02/01/2024 15:04" {
+		t.Fatalf("expected 02/01/2024 15:04, got %q", got)
+	}
+}
@@ -37,7 +37,9 @@ func (h *Handler) StudentsPreview(w http.ResponseWriter, r *http.Request) {
 			observability.Logger(r.Context()).Error("failed to load students preview", "err", err)
 		} else {
 			data.Items = make([]view.StudentItem, 0, len(students))
+			ids := make([]string, 0, len(students))
 			for _, student := range students {
+				ids = append(ids, student.ID)
 				item := view.StudentItem{
 					ID:          student.ID,
 					FullName:    student.FullName,
@@ -51,6 +53,7 @@ func (h *Handler) StudentsPreview(w http.ResponseWriter, r *http.Request) {
 				}
 				data.Items = append(data.Items, item)
 			}
+			h.services.Students.RecordViews(r.Context(), "preview", ids...)
 		}
 	}
 
@@ -106,12 +109,14 @@ func (h *Handler) StudentsEdit(w http.ResponseWriter, r *http.Request) {
 	}
 
 	data := studentFormEditData(student, h.photoURLForVariant(student.PhotoObjectKey, "preview"))
+	data.HistoryURL = h.historyURL(r, "/students/"+student.ID)
 	h.renderPage(w, r, page(data.Title, view.StudentFormPage(data)))
 }
 
 func (h *Handler) StudentsUpdate(w http.ResponseWriter, r *http.Request) {
 	studentID := chi.URLParam(r, "studentID")
 	data := studentFormEditData(domain.Student{ID: studentID}, "")
+	data.HistoryURL = h.historyURL(r, "/students/"+studentID)
 	student, err := h.parseStudentForm(r, &data)
 	if err != nil {
 		data.Error = err.Error()
@@ -70,6 +70,15 @@ func (h *Handler) SubscriptionsEdit(w http.ResponseWriter, r *http.Request) {
 	}
 
 	data := h.subscriptionFormEditData(r, subscription)
+	data.BillingPeriods = h.subscriptionBillingPeriodsData(r, subscription.ID)
+	if subscription.Status == domain.SubscriptionActive {
+		data.ChangePlan = view.SubscriptionChangePlanData{
+			SubscriptionID: subscription.ID,
+			CurrentPlanID:  subscription.PlanID,
+			Plans:          data.Plans,
+		}
+	}
+	data.Freezes = h.subscriptionFreezesData(r, subscription)
 	h.renderPage(w, r, page(data.Title, view.SubscriptionFormPage(data)))
 }
 
@@ -124,6 +133,222 @@ func (h *Handler) SubscriptionsCancel(w http.ResponseWriter, r *http.Request) {
 	})
 }
 
+func (h *Handler) SubscriptionsChangePlan(w http.ResponseWriter, r *http.Request) {
+	subscriptionID := chi.URLParam(r, "subscriptionID")
+	if h.services.Subscriptions == nil {
+		http.NotFound(w, r)
+		return
+	}
+
+	subscription, err := h.services.Subscriptions.FindByID(r.Context(), subscriptionID)
+	if err != nil {
+		if errors.Is(err, ports.ErrNotFound) {
+			http.NotFound(w, r)
+			return
+		}
+		observability.Logger(r.Context()).Error("failed to load subscription", "err", err)
+		http.Error(w, "Erro ao carregar assinatura.", http.StatusInternalServerError)
+		return
+	}
+
+	data := view.SubscriptionChangePlanData{
+		SubscriptionID: subscription.ID,
+		CurrentPlanID:  subscription.PlanID,
+		Plans:          h.listPlanOptions(r),
+	}
+	if err := r.ParseForm(); err != nil {
+		data.Error = "Nao foi possivel ler o formulario."
+		h.renderFormError(w, r, "Trocar plano", view.SubscriptionChangePlan(data))
+		return
+	}
+	planID := strings.TrimSpace(r.FormValue("plan_id"))
+	if planID == "" {
+		data.Error = "Selecione o novo plano."
+		h.renderFormError(w, r, "Trocar plano", view.SubscriptionChangePlan(data))
+		return
+	}
+
+	created, err := h.services.Subscriptions.ChangePlan(r.Context(), subscription.ID, planID)
+	if err != nil {
+		observability.Logger(r.Context()).Error("failed to change subscription plan", "err", err)
+		data.Error = "Nao foi possivel trocar o plano."
+		h.renderFormError(w, r, "Trocar plano", view.SubscriptionChangePlan(data))
+		return
+	}
+
+	h.redirectHTMXOrRedirect(w, r, "/subscriptions/"+created.ID+"/edit")
+}
+
+func (h *Handler) SubscriptionsFreeze(w http.ResponseWriter, r *http.Request) {
+	subscriptionID := chi.URLParam(r, "subscriptionID")
+	if h.services.Subscriptions == nil {
+		http.NotFound(w, r)
+		return
+	}
+
+	subscription, err := h.services.Subscriptions.FindByID(r.Context(), subscriptionID)
+	if err != nil {
+		if errors.Is(err, ports.ErrNotFound) {
+			http.NotFound(w, r)
+			return
+		}
+		observability.Logger(r.Context()).Error("failed to load subscription", "err", err)
+		http.Error(w, "Erro ao carregar assinatura.", http.StatusInternalServerError)
+		return
+	}
+
+	data := h.subscriptionFreezesData(r, subscription)
+	freeze, err := parseSubscriptionFreezeForm(r, &data)
+	if err != nil {
+		data.Error = err.Error()
+		h.renderFormError(w, r, "Trancamentos", view.SubscriptionFreezes(data))
+		return
+	}
+
+	freeze.SubscriptionID = subscription.ID
+	if _, err := h.services.Subscriptions.Freeze(r.Context(), freeze); err != nil {
+		observability.Logger(r.Context()).Warn("failed to freeze subscription", "err", err)
+		data.Error = "Nao foi possivel trancar a assinatura: " + err.Error() + "."
+		h.renderFormError(w, r, "Trancamentos", view.SubscriptionFreezes(data))
+		return
+	}
+
+	h.redirectHTMXOrRedirect(w, r, "/subscriptions/"+subscription.ID+"/edit")
+}
+
+func (h *Handler) SubscriptionsWaiveFees(w http.ResponseWriter, r *http.Request) {
+	subscriptionID := chi.URLParam(r, "subscriptionID")
+	if h.services.Payments == nil {
+		http.NotFound(w, r)
+		return
+	}
+
+	_, err := h.services.Payments.WaiveLateFees(r.Context(), subscriptionID, chi.URLParam(r, "periodID"))
+	if err != nil {
+		if errors.Is(err, ports.ErrNotFound) {
+			http.NotFound(w, r)
+			return
+		}
+		observability.Logger(r.Context()).Error("failed to waive late fees", "err", err)
+		http.Error(w, "Erro ao dispensar encargos.", http.StatusInternalServerError)
+		return
+	}
+
+	h.renderHTMXOrRedirect(w, r, "/subscriptions/"+subscriptionID+"/edit", func() {
+		h.renderComponent(w, r, view.SubscriptionBillingPeriods(h.subscriptionBillingPeriodsData(r, subscriptionID)))
+	})
+}
+
+// subscriptionFreezesData monta a lista de trancamentos com o saldo de dias do
+// plano no ano corrente.
+func (h *Handler) subscriptionFreezesData(r *http.Request, subscription domain.Subscription) view.SubscriptionFreezesData {
+	data := view.SubscriptionFreezesData{
+		SubscriptionID: subscription.ID,
+		Year:           time.Now().Year(),
+	}
+
+	freezes, err := h.services.Subscriptions.ListFreezes(r.Context(), subscription.ID)
+	if err != nil {
+		observability.Logger(r.Context()).Error("failed to list subscription freezes", "err", err)
+		data.Error = "Nao foi possivel carregar os trancamentos."
+		return data
+	}
+	data.Items = make([]view.SubscriptionFreezeItem, 0, len(freezes))
+	for _, freeze := range freezes {
+		data.UsedDays += freeze.DaysInYear(data.Year)
+		data.Items = append(data.Items, view.SubscriptionFreezeItem{
+			Period: formatDateBRValue(freeze.StartDate) + " a " + formatDateBRValue(freeze.EndDate),
+			Days:   freeze.Days(),
+			Reason: freeze.Reason,
+		})
+	}
+
+	if h.services.Plans != nil {
+		plan, err := h.services.Plans.FindByID(r.Context(), subscription.PlanID)
+		if err != nil {
+			observability.Logger(r.Context()).Error("failed to load plan", "err", err)
+		} else {
+			data.MaxDays = plan.MaxFreezeDays
+		}
+	}
+	data.CanFreeze = subscription.Status == domain.SubscriptionActive && data.MaxDays > 0
+	return data
+}
+
+func formatInstallment(period domain.BillingPeriod) string {
+	if period.Installments <= 1 {
+		return ""
+	}
+	return strconv.Itoa(period.Installment) + "/" + strconv.Itoa(period.Installments)
+}
+
+func parseSubscriptionFreezeForm(r *http.Request, data *view.SubscriptionFreezesData) (domain.SubscriptionFreeze, error) {
+	if err := r.ParseForm(); err != nil {
+		return domain.SubscriptionFreeze{}, errors.New("Nao foi possivel ler o formulario.")
+	}
+
+	data.StartDate = strings.TrimSpace(r.FormValue("start_date"))
+	startDate, err := parseDateInput(data.StartDate)
+	if err != nil || startDate == nil {
+		return domain.SubscriptionFreeze{}, errors.New("Inicio invalido. Use o formato dd/mm/aaaa.")
+	}
+
+	data.EndDate = strings.TrimSpace(r.FormValue("end_date"))
+	endDate, err := parseDateInput(data.EndDate)
+	if err != nil || endDate == nil {
+		return domain.SubscriptionFreeze{}, errors.New("Fim invalido. Use o formato dd/mm/aaaa.")
+	}
+
+	data.Reason = strings.TrimSpace(r.FormValue("reason"))
+	if data.Reason == "" {
+		return domain.SubscriptionFreeze{}, errors.New("Motivo do trancamento e obrigatorio.")
+	}
+
+	return domain.SubscriptionFreeze{
+		StartDate: *startDate,
+		EndDate:   *endDate,
+		Reason:    data.Reason,
+	}, nil
+}
+
+func (h *Handler) subscriptionBillingPeriodsData(r *http.Request, subscriptionID string) view.SubscriptionBillingPeriodsData {
+	data := view.SubscriptionBillingPeriodsData{SubscriptionID: subscriptionID}
+	if h.services.Payments == nil {
+		return data
+	}
+
+	periods, err := h.services.Payments.ListBillingPeriods(r.Context(), subscriptionID)
+	if err != nil {
+		observability.Logger(r.Context()).Error("failed to list billing periods", "err", err)
+		return data
+	}
+
+	canWaive := can(r, domain.PermissionBillingFeeWaive)
+	data.Items = make([]view.BillingPeriodItem, 0, len(periods))
+	for _, period := range periods {
+		label, className := billingPeriodStatusPresentation(period.Status)
+		discount := ""
+		if period.DiscountCents > 0 {
+			discount = formatBRL(period.DiscountCents)
+		}
+		data.Items = append(data.Items, view.BillingPeriodItem{
+			ID:          period.ID,
+			Period:      formatDateBRValue(period.PeriodStart) + " a " + formatDateBRValue(period.PeriodEnd),
+			Installment: formatInstallment(period),
+			AmountDue:   formatBRL(period.AmountDueCents),
+			Discount:    discount,
+			Fee:         formatBRL(period.FeeCents),
+			AmountPaid:  formatBRL(period.AmountPaidCents),
+			Outstanding: formatBRL(period.OutstandingCents()),
+			StatusLabel: label,
+			StatusClass: className,
+			FeeWaived:   period.FeeWaivedAt != nil,
+			CanWaive:    canWaive && period.FeeWaivedAt == nil && period.FeeCents > period.FeePaidCents(),
+		})
+	}
+	return data
+}
+
 func (h *Handler) buildSubscriptionsData(r *http.Request) view.SubscriptionsPageData {
 	studentID := strings.TrimSpace(r.FormValue("student_id"))
 	status := normalizeSubscriptionStatus(strings.TrimSpace(r.FormValue("status")))
@@ -193,8 +418,8 @@ func (h *Handler) buildSubscriptionsData(r *http.Request) view.SubscriptionsPage
 
 		studentName := studentMap[subscription.StudentID]
 		if studentName == "" && h.services.Students != nil {
-			if student, err := h.services.Students.FindByID(r.Context(), subscription.StudentID); err == nil {
-				studentName = student.FullName
+			if name, err := h.services.Students.FindName(r.Context(), subscription.StudentID); err == nil {
+				studentName = name
 			}
 		}
 
@@ -238,6 +463,7 @@ func (h *Handler) subscriptionFormEditData(r *http.Request, subscription domain.
 		Action:       "/subscriptions/" + subscription.ID,
 		SubmitLabel:  "Salvar",
 		DeleteAction: "/subscriptions/" + subscription.ID + "/cancel",
+		HistoryURL:   h.historyURL(r, "/subscriptions/"+subscription.ID),
 		ShowDelete:   subscription.ID != "",
 		StudentID:    subscription.StudentID,
 		PlanID:       subscription.PlanID,
@@ -247,6 +473,11 @@ func (h *Handler) subscriptionFormEditData(r *http.Request, subscription domain.
 		AutoRenew:    subscription.AutoRenew,
 		Status:       string(subscription.Status),
 		Price:        formatCentsInput(subscription.PriceCents),
+		CouponCode:   h.couponCode(r.Context(), subscription.CouponID),
+		Discount:     formatDiscount(subscription.Discount),
+	}
+	if subscription.PreviousSubscriptionID != "" {
+		data.PreviousURL = "/subscriptions/" + subscription.PreviousSubscriptionID + "/edit"
 	}
 	h.fillSubscriptionFormOptions(r, &data)
 	data.DisableSelects = true
@@ -319,6 +550,17 @@ func (h *Handler) parseSubscriptionForm(r *http.Request, data *view.Subscription
 	autoRenew := strings.TrimSpace(r.FormValue("auto_renew")) != ""
 	data.AutoRenew = autoRenew
 
+	couponCode := strings.TrimSpace(r.FormValue("coupon_code"))
+	data.CouponCode = couponCode
+	couponID, err := h.couponIDForCode(r.Context(), couponCode)
+	if err != nil {
+		if errors.Is(err, ports.ErrNotFound) {
+			return domain.Subscription{}, errors.New("Cupom nao encontrado.")
+		}
+		observability.Logger(r.Context()).Error("failed to load coupon", "err", err)
+		return domain.Subscription{}, errors.New("Nao foi possivel validar o cupom.")
+	}
+
 	subscription := domain.Subscription{
 		StudentID:  studentID,
 		PlanID:     planID,
@@ -327,6 +569,7 @@ func (h *Handler) parseSubscriptionForm(r *http.Request, data *view.Subscription
 		PriceCents: priceCents,
 		PaymentDay: paymentDay,
 		AutoRenew:  autoRenew,
+		CouponID:   couponID,
 	}
 	if endDate != nil {
 		subscription.EndDate = *endDate
@@ -335,6 +578,34 @@ func (h *Handler) parseSubscriptionForm(r *http.Request, data *view.Subscription
 	return subscription, nil
 }
 
+// couponIDForCode encontra o cupom pelo codigo digitado. Se o cupom pode ser
+// usado e decidido pelo servico de assinaturas, que mantem o desconto ja
+// aplicado mesmo depois de o cupom ser desativado.
+func (h *Handler) couponIDForCode(ctx context.Context, code string) (string, error) {
+	if code == "" {
+		return "", nil
+	}
+	if h.services.Coupons == nil {
+		return "", errors.New("servico de cupons indisponivel")
+	}
+	coupon, err := h.services.Coupons.FindByCode(ctx, code)
+	if err != nil {
+		return "", err
+	}
+	return coupon.ID, nil
+}
+
+func (h *Handler) couponCode(ctx context.Context, couponID string) string {
+	if couponID == "" || h.services.Coupons == nil {
+		return ""
+	}
+	coupon, err := h.services.Coupons.FindByID(ctx, couponID)
+	if err != nil {
+		return ""
+	}
+	return coupon.Code
+}
+
 func (h *Handler) listActiveStudentOptions(r *http.Request) []view.StudentOption {
 	students := h.loadStudents(r, []domain.StudentStatus{domain.StudentActive}, 500)
 	return toStudentOptions(students)
@@ -406,11 +677,11 @@ func ensureStudentOption(ctx context.Context, options []view.StudentOption, stud
 	if h.services.Students == nil {
 		return options
 	}
-	student, err := h.services.Students.FindByID(ctx, studentID)
+	name, err := h.services.Students.FindName(ctx, studentID)
 	if err != nil {
 		return options
 	}
-	return append(options, view.StudentOption{ID: student.ID, Name: student.FullName})
+	return append(options, view.StudentOption{ID: studentID, Name: name})
 }
 
 func ensurePlanOption(ctx context.Context, options []view.PlanOption, planID string, h *Handler) []view.PlanOption {
@@ -471,6 +742,19 @@ func subscriptionStatusPresentation(status domain.SubscriptionStatus) (string, s
 	}
 }
 
+func billingPeriodStatusPresentation(status domain.BillingPeriodStatus) (string, string) {
+	switch status {
+	case domain.BillingPaid:
+		return "Paga", "rounded-full bg-emerald-400/10 px-3 py-1 text-emerald-200"
+	case domain.BillingPartial:
+		return "Parcial", "rounded-full bg-amber-400/10 px-3 py-1 text-amber-200"
+	case domain.BillingOverdue:
+		return "Vencida", "rounded-full bg-rose-400/10 px-3 py-1 text-rose-200"
+	default:
+		return "Em aberto", "rounded-full bg-slate-700/50 px-3 py-1 text-slate-300"
+	}
+}
+
 func parsePriceCentsOptional(value string) (int64, error) {
 	if value == "" {
 		return 0, nil
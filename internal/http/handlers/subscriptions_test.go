@@ -50,6 +50,18 @@ func TestSubscriptionStatusPresentation(t *testing.T) {
 	}
 }
 
+// Testa a apresentacao de status das competencias.
+func TestBillingPeriodStatusPresentation(t *testing.T) {
+	label, _ := billingPeriodStatusPresentation(domain.BillingOverdue)
+	if label != "Vencida" {
+		t.Fatalf("expected label Vencida, got %q", label)
+	}
+	label, _ = billingPeriodStatusPresentation(domain.BillingOpen)
+	if label != "Em aberto" {
+		t.Fatalf("expected label Em aberto, got %q", label)
+	}
+}
+
 // Testa parse opcional de preco em centavos.
 func TestParsePriceCentsOptional(t *testing.T) {
 	if got, err := parsePriceCentsOptional(""); err != nil || got != 0 {
@@ -0,0 +1,270 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/PabloPavan/jaiu/internal/domain"
+	"github.com/PabloPavan/jaiu/internal/observability"
+	"github.com/PabloPavan/jaiu/internal/ports"
+	"github.com/PabloPavan/jaiu/internal/view"
+	"github.com/go-chi/chi/v5"
+)
+
+func (h *Handler) UsersIndex(w http.ResponseWriter, r *http.Request) {
+	data := h.buildUsersData(r)
+	h.renderPage(w, r, page("Usuarios", view.UsersPage(data)))
+}
+
+func (h *Handler) UsersNew(w http.ResponseWriter, r *http.Request) {
+	data := userFormCreateData()
+	h.renderPage(w, r, page(data.Title, view.UserFormPage(data)))
+}
+
+func (h *Handler) UsersCreate(w http.ResponseWriter, r *http.Request) {
+	data := userFormCreateData()
+	user, password, err := parseUserForm(r, &data)
+	if err != nil {
+		data.Error = err.Error()
+		h.renderFormError(w, r, data.Title, view.UserFormPage(data))
+		return
+	}
+
+	if h.services.Users == nil {
+		data.Error = "Servico de usuarios indisponivel."
+		h.renderFormError(w, r, data.Title, view.UserFormPage(data))
+		return
+	}
+
+	_, err = h.services.Users.Create(r.Context(), user, password)
+	if err != nil {
+		data.Error = userErrorMessage(err, "Nao foi possivel salvar o usuario.")
+		h.renderFormError(w, r, data.Title, view.UserFormPage(data))
+		return
+	}
+
+	h.redirectHTMXOrRedirect(w, r, "/users")
+}
+
+func (h *Handler) UsersEdit(w http.ResponseWriter, r *http.Request) {
+	userID := chi.URLParam(r, "userID")
+	if h.services.Users == nil {
+		http.NotFound(w, r)
+		return
+	}
+
+	user, err := h.services.Users.FindByID(r.Context(), userID)
+	if err != nil {
+		if errors.Is(err, ports.ErrNotFound) {
+			http.NotFound(w, r)
+			return
+		}
+		observability.Logger(r.Context()).Error("failed to load user", "err", err)
+		http.Error(w, "Erro ao carregar usuario.", http.StatusInternalServerError)
+		return
+	}
+
+	data := userFormEditData(user)
+	h.renderPage(w, r, page(data.Title, view.UserFormPage(data)))
+}
+
+func (h *Handler) UsersUpdate(w http.ResponseWriter, r *http.Request) {
+	userID := chi.URLParam(r, "userID")
+	data := userFormEditData(domain.User{ID: userID})
+	user, password, err := parseUserForm(r, &data)
+	if err != nil {
+		data.Error = err.Error()
+		h.renderFormError(w, r, data.Title, view.UserFormPage(data))
+		return
+	}
+
+	if h.services.Users == nil {
+		data.Error = "Servico de usuarios indisponivel."
+		h.renderFormError(w, r, data.Title, view.UserFormPage(data))
+		return
+	}
+
+	user.ID = userID
+	if _, err := h.services.Users.Update(r.Context(), user); err != nil {
+		data.Error = userErrorMessage(err, "Nao foi possivel atualizar o usuario.")
+		h.renderFormError(w, r, data.Title, view.UserFormPage(data))
+		return
+	}
+
+	if password != "" {
+		if err := h.services.Users.ResetPassword(r.Context(), userID, password); err != nil {
+			data.Error = "Nao foi possivel redefinir a senha."
+			h.renderFormError(w, r, data.Title, view.UserFormPage(data))
+			return
+		}
+	}
+
+	h.redirectHTMXOrRedirect(w, r, "/users")
+}
+
+func (h *Handler) UsersSetActive(w http.ResponseWriter, r *http.Request) {
+	userID := chi.URLParam(r, "userID")
+	if h.services.Users == nil {
+		http.NotFound(w, r)
+		return
+	}
+
+	active := r.FormValue("active") == "true"
+	_, err := h.services.Users.SetActive(r.Context(), userID, active)
+	if err != nil {
+		if errors.Is(err, ports.ErrNotFound) {
+			http.NotFound(w, r)
+			return
+		}
+		if message := userAccessErrorMessage(err); message != "" {
+			h.renderHTMXOrRedirect(w, r, "/users", func() {
+				data := h.buildUsersData(r)
+				data.Error = message
+				h.renderComponent(w, r, view.UsersList(data))
+			})
+			return
+		}
+		observability.Logger(r.Context()).Error("failed to update user status", "err", err)
+		http.Error(w, "Erro ao atualizar usuario.", http.StatusInternalServerError)
+		return
+	}
+
+	h.renderHTMXOrRedirect(w, r, "/users", func() {
+		data := h.buildUsersData(r)
+		h.renderComponent(w, r, view.UsersList(data))
+	})
+}
+
+func (h *Handler) buildUsersData(r *http.Request) view.UsersPageData {
+	data := view.UsersPageData{}
+	if h.services.Users == nil {
+		return data
+	}
+
+	users, err := h.services.Users.List(r.Context())
+	if err != nil {
+		observability.Logger(r.Context()).Error("failed to list users", "err", err)
+		return data
+	}
+
+	data.Items = make([]view.UserItem, 0, len(users))
+	for _, user := range users {
+		statusLabel, statusClass := userStatusPresentation(user.Active)
+		data.Items = append(data.Items, view.UserItem{
+			ID:          user.ID,
+			Name:        user.Name,
+			Email:       user.Email,
+			RoleLabel:   userRoleLabel(user.Role),
+			Active:      user.Active,
+			StatusLabel: statusLabel,
+			StatusClass: statusClass,
+		})
+	}
+
+	return data
+}
+
+func userFormCreateData() view.UserFormData {
+	return view.UserFormData{
+		Title:       "Novo usuario",
+		Action:      "/users",
+		SubmitLabel: "Criar usuario",
+		IsNew:       true,
+		Role:        string(domain.RoleOperator),
+		Active:      true,
+	}
+}
+
+func userFormEditData(user domain.User) view.UserFormData {
+	return view.UserFormData{
+		Title:              "Editar usuario",
+		Action:             "/users/" + user.ID,
+		SubmitLabel:        "Salvar",
+		Name:               user.Name,
+		Email:              user.Email,
+		Role:               string(user.Role),
+		Active:             user.Active,
+		MustChangePassword: user.MustChangePassword,
+	}
+}
+
+func parseUserForm(r *http.Request, data *view.UserFormData) (domain.User, string, error) {
+	if err := r.ParseForm(); err != nil {
+		return domain.User{}, "", errors.New("Nao foi possivel ler o formulario.")
+	}
+
+	name := strings.TrimSpace(r.FormValue("name"))
+	data.Name = name
+	if name == "" {
+		return domain.User{}, "", errors.New("Nome do usuario e obrigatorio.")
+	}
+
+	email := strings.TrimSpace(r.FormValue("email"))
+	data.Email = email
+	if !strings.Contains(email, "@") {
+		return domain.User{}, "", errors.New("Email invalido.")
+	}
+
+	role := domain.UserRole(strings.TrimSpace(r.FormValue("role")))
+	data.Role = string(role)
+	if !role.IsValid() {
+		return domain.User{}, "", errors.New("Papel invalido.")
+	}
+
+	active := r.FormValue("active") != ""
+	data.Active = active
+
+	mustChange := r.FormValue("must_change_password") != ""
+	data.MustChangePassword = mustChange
+
+	password := r.FormValue("password")
+	if (data.IsNew || password != "") && len(password) < 8 {
+		return domain.User{}, "", errors.New("A senha deve ter pelo menos 8 caracteres.")
+	}
+
+	return domain.User{
+		Name:               name,
+		Email:              email,
+		Role:               role,
+		Active:             active,
+		MustChangePassword: mustChange,
+	}, password, nil
+}
+
+func userErrorMessage(err error, fallback string) string {
+	if errors.Is(err, ports.ErrConflict) {
+		return "Ja existe um usuario com este email."
+	}
+	if message := userAccessErrorMessage(err); message != "" {
+		return message
+	}
+	return fallback
+}
+
+func userAccessErrorMessage(err error) string {
+	switch {
+	case errors.Is(err, domain.ErrSelfLockout):
+		return "Nao e possivel remover o proprio acesso de administrador."
+	case errors.Is(err, domain.ErrLastAdmin):
+		return "E preciso manter pelo menos um administrador ativo."
+	default:
+		return ""
+	}
+}
+
+func userRoleLabel(role domain.UserRole) string {
+	switch role {
+	case domain.RoleAdmin:
+		return "Administrador"
+	default:
+		return "Operador"
+	}
+}
+
+func userStatusPresentation(active bool) (string, string) {
+	if active {
+		return "Ativo", "rounded-full bg-emerald-400/10 px-3 py-1 text-emerald-200"
+	}
+	return "Inativo", "rounded-full bg-slate-700/50 px-3 py-1 text-slate-300"
+}
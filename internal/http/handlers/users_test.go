@@ -0,0 +1,117 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/PabloPavan/jaiu/internal/domain"
+	"github.com/PabloPavan/jaiu/internal/view"
+)
+
+// Testa dados padrao do formulario de criacao de usuario.
+func TestUserFormCreateData(t *testing.T) {
+	data := userFormCreateData()
+	if !data.IsNew || !data.Active {
+		t.Fatalf("expected new active user form, got %#v", data)
+	}
+	if data.Role != string(domain.RoleOperator) {
+		t.Fatalf("expected default role operator, got %q", data.Role)
+	}
+}
+
+// Testa parse e validacoes do formulario de usuario.
+func TestParseUserForm(t *testing.T) {
+	tests := []struct {
+		name      string
+		isNew     bool
+		values    url.Values
+		wantError bool
+	}{
+		{
+			name:  "valid-new",
+			isNew: true,
+			values: url.Values{
+				"name":     {"Ana"},
+				"email":    {"ana@example.com"},
+				"role":     {"admin"},
+				"password": {"segredo123"},
+				"active":   {"on"},
+			},
+		},
+		{
+			name:  "edit-without-password",
+			isNew: false,
+			values: url.Values{
+				"name":  {"Ana"},
+				"email": {"ana@example.com"},
+				"role":  {"operator"},
+			},
+		},
+		{
+			name:  "new-without-password",
+			isNew: true,
+			values: url.Values{
+				"name":  {"Ana"},
+				"email": {"ana@example.com"},
+				"role":  {"operator"},
+			},
+			wantError: true,
+		},
+		{
+			name:  "invalid-role",
+			isNew: false,
+			values: url.Values{
+				"name":  {"Ana"},
+				"email": {"ana@example.com"},
+				"role":  {"guest"},
+			},
+			wantError: true,
+		},
+		{
+			name:  "invalid-email",
+			isNew: false,
+			values: url.Values{
+				"name":  {"Ana"},
+				"email": {"ana"},
+				"role":  {"operator"},
+			},
+			wantError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		req := httptest.NewRequest(http.MethodPost, "/users", strings.NewReader(tt.values.Encode()))
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		data := view.UserFormData{IsNew: tt.isNew}
+
+		user, _, err := parseUserForm(req, &data)
+		if tt.wantError {
+			if err == nil {
+				t.Fatalf("%s: expected error, got nil", tt.name)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", tt.name, err)
+		}
+		if user.Name == "" || user.Email == "" || !user.Role.IsValid() {
+			t.Fatalf("%s: expected user fields to be populated, got %#v", tt.name, user)
+		}
+	}
+}
+
+// Testa os rotulos de papel e status de usuario.
+func TestUserPresentation(t *testing.T) {
+	if got := userRoleLabel(domain.RoleAdmin); got != "Administrador" {
+		t.Fatalf("expected Administrador, got %q", got)
+	}
+	if got := userRoleLabel(domain.RoleOperator); got != "Operador" {
+		t.Fatalf("expected Operador, got %q", got)
+	}
+	if label, _ := userStatusPresentation(false); label != "Inativo" {
+		t.Fatalf("expected Inativo, got %q", label)
+	}
+}
@@ -0,0 +1,41 @@
+package middleware
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+)
+
+// APIPrefix identifica as rotas da API JSON. Nelas as falhas de sessao, CSRF e
+// permissao respondem com o corpo de erro da API em vez de redirecionar.
+const APIPrefix = "/api/"
+
+// APIError e o corpo de erro padrao da API: {"error": {"code", "message"}}.
+type APIError struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+type apiErrorBody struct {
+	Error APIError `json:"error"`
+}
+
+func IsAPIRequest(r *http.Request) bool {
+	return strings.HasPrefix(r.URL.Path, APIPrefix)
+}
+
+func WriteAPIError(w http.ResponseWriter, status int, code, message string) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(apiErrorBody{Error: APIError{Code: code, Message: message}})
+}
+
+// writeError responde em JSON nas rotas da API e em texto nas demais.
+func writeError(w http.ResponseWriter, r *http.Request, status int, code, message string) {
+	if IsAPIRequest(r) {
+		WriteAPIError(w, status, code, message)
+		return
+	}
+	http.Error(w, message, status)
+}
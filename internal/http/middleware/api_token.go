@@ -0,0 +1,109 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/PabloPavan/jaiu/internal/auditctx"
+	"github.com/PabloPavan/jaiu/internal/domain"
+	"github.com/PabloPavan/jaiu/internal/ports"
+)
+
+const apiPrincipalKey contextKey = "api_principal"
+
+type APITokenAuthenticator interface {
+	Authenticate(ctx context.Context, token string) (ports.APIPrincipal, error)
+}
+
+// RequireAPIAuth autentica pelo cabecalho "Authorization: Bearer <token>".
+// Sem o cabecalho a requisicao segue para a validacao da sessao, permitindo
+// que as telas do navegador usem a mesma API.
+func RequireAPIAuth(tokens APITokenAuthenticator, store ports.SessionStore, policy SessionPolicy) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		withSession := RequireSessionWithPolicy(store, policy)(next)
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			token, ok := bearerToken(r)
+			if !ok {
+				withSession.ServeHTTP(w, r)
+				return
+			}
+			if tokens == nil || token == "" {
+				denyToken(w)
+				return
+			}
+
+			principal, err := tokens.Authenticate(r.Context(), token)
+			if err != nil {
+				if errors.Is(err, ports.ErrUnauthorized) {
+					denyToken(w)
+					return
+				}
+				WriteAPIError(w, http.StatusInternalServerError, "internal", "erro ao validar token")
+				return
+			}
+
+			if activity, ok := userActivityFromContext(r.Context()); ok {
+				activity.UserID = principal.UserID
+				activity.Role = string(principal.Role)
+			}
+
+			ctx := context.WithValue(r.Context(), apiPrincipalKey, principal)
+			ctx = auditctx.WithActor(ctx, auditctx.Actor{
+				ID:      principal.UserID,
+				Role:    string(principal.Role),
+				Email:   principal.Email,
+				TokenID: principal.TokenID,
+			})
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+// RequireAPIScope exige do token o escopo do recurso: leitura para metodos
+// seguros e escrita para os demais. Requisicoes com sessao nao tem escopo.
+func RequireAPIScope(resource domain.APIResource) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			principal, ok := APIPrincipalFromContext(r.Context())
+			if !ok {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			access := domain.APIAccessWrite
+			if isSafeMethod(r.Method) {
+				access = domain.APIAccessRead
+			}
+			if !principal.Allows(resource, access) {
+				WriteAPIError(w, http.StatusForbidden, "insufficient_scope", "token sem o escopo "+string(domain.NewAPIScope(resource, access)))
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+func APIPrincipalFromContext(ctx context.Context) (ports.APIPrincipal, bool) {
+	principal, ok := ctx.Value(apiPrincipalKey).(ports.APIPrincipal)
+	return principal, ok
+}
+
+func bearerToken(r *http.Request) (string, bool) {
+	header := r.Header.Get("Authorization")
+	if header == "" {
+		return "", false
+	}
+	scheme, token, _ := strings.Cut(header, " ")
+	if !strings.EqualFold(scheme, "Bearer") {
+		return "", true
+	}
+	return strings.TrimSpace(token), true
+}
+
+func denyToken(w http.ResponseWriter) {
+	w.Header().Set("WWW-Authenticate", `Bearer realm="api"`)
+	WriteAPIError(w, http.StatusUnauthorized, "invalid_token", "token invalido ou expirado")
+}
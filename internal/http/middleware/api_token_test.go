@@ -0,0 +1,97 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/PabloPavan/jaiu/internal/auditctx"
+	"github.com/PabloPavan/jaiu/internal/domain"
+	"github.com/PabloPavan/jaiu/internal/ports"
+)
+
+type fakeTokenAuthenticator struct {
+	principals map[string]ports.APIPrincipal
+}
+
+func (f *fakeTokenAuthenticator) Authenticate(ctx context.Context, token string) (ports.APIPrincipal, error) {
+	principal, ok := f.principals[token]
+	if !ok {
+		return ports.APIPrincipal{}, ports.ErrUnauthorized
+	}
+	return principal, nil
+}
+
+// Testa a autenticacao por token Bearer e o ator registrado na auditoria.
+func TestRequireAPIAuth(t *testing.T) {
+	tokens := &fakeTokenAuthenticator{principals: map[string]ports.APIPrincipal{
+		"jaiu_ok": {TokenID: "tok-1", UserID: "user-1", Email: "erp@example.com", Role: domain.RoleOperator},
+	}}
+	var actor auditctx.Actor
+	handler := RequireAPIAuth(tokens, nil, SessionPolicy{CookieName: "test_session"})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		actor = auditctx.FromContext(r.Context()).Actor
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	tests := []struct {
+		name   string
+		header string
+		status int
+	}{
+		{"valid", "Bearer jaiu_ok", http.StatusOK},
+		{"lowercase-scheme", "bearer jaiu_ok", http.StatusOK},
+		{"unknown", "Bearer jaiu_bad", http.StatusUnauthorized},
+		{"empty", "Bearer ", http.StatusUnauthorized},
+		{"basic", "Basic dXNlcjpwYXNz", http.StatusUnauthorized},
+	}
+	for _, tt := range tests {
+		actor = auditctx.Actor{}
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/plans", nil)
+		req.Header.Set("Authorization", tt.header)
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+
+		if rec.Code != tt.status {
+			t.Fatalf("%s: expected %d, got %d", tt.name, tt.status, rec.Code)
+		}
+		if tt.status == http.StatusUnauthorized && rec.Header().Get("WWW-Authenticate") == "" {
+			t.Fatalf("%s: expected WWW-Authenticate header", tt.name)
+		}
+		if tt.status == http.StatusOK && (actor.ID != "user-1" || actor.TokenID != "tok-1") {
+			t.Fatalf("%s: unexpected actor: %#v", tt.name, actor)
+		}
+	}
+}
+
+// Testa que o escopo exigido depende do metodo e que sessoes nao sao limitadas.
+func TestRequireAPIScope(t *testing.T) {
+	handler := RequireAPIScope(domain.APIResourcePayments)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	readOnly := ports.APIPrincipal{TokenID: "tok-1", Scopes: []domain.APIScope{"payments:read"}}
+	writer := ports.APIPrincipal{TokenID: "tok-2", Scopes: []domain.APIScope{"payments:write"}}
+	other := ports.APIPrincipal{TokenID: "tok-3", Scopes: []domain.APIScope{"students:write"}}
+
+	tests := []struct {
+		name   string
+		method string
+		ctx    context.Context
+		status int
+	}{
+		{"read-get", http.MethodGet, context.WithValue(context.Background(), apiPrincipalKey, readOnly), http.StatusOK},
+		{"read-post", http.MethodPost, context.WithValue(context.Background(), apiPrincipalKey, readOnly), http.StatusForbidden},
+		{"write-get", http.MethodGet, context.WithValue(context.Background(), apiPrincipalKey, writer), http.StatusOK},
+		{"write-post", http.MethodPost, context.WithValue(context.Background(), apiPrincipalKey, writer), http.StatusOK},
+		{"other-resource", http.MethodGet, context.WithValue(context.Background(), apiPrincipalKey, other), http.StatusForbidden},
+		{"session", http.MethodPost, context.WithValue(context.Background(), sessionKey, ports.Session{UserID: "user-1"}), http.StatusOK},
+	}
+	for _, tt := range tests {
+		req := httptest.NewRequest(tt.method, "/api/v1/payments", nil).WithContext(tt.ctx)
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		if rec.Code != tt.status {
+			t.Fatalf("%s: expected %d, got %d", tt.name, tt.status, rec.Code)
+		}
+	}
+}
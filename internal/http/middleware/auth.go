@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"net/http"
+	"time"
 
 	"github.com/PabloPavan/jaiu/internal/auditctx"
 	"github.com/PabloPavan/jaiu/internal/ports"
@@ -13,7 +14,42 @@ type contextKey string
 
 const sessionKey contextKey = "session"
 
+const sessionTouchInterval = time.Minute
+
+// SessionPolicy controla a expiracao deslizante das sessoes. Com IdleTimeout
+// zero a sessao mantem a expiracao definida no login.
+type SessionPolicy struct {
+	CookieName  string
+	IdleTimeout time.Duration
+	MaxLifetime time.Duration
+	Secure      bool
+	SameSite    http.SameSite
+}
+
+// ExpiresAt calcula a nova expiracao da sessao, limitada ao tempo maximo
+// contado a partir da criacao. Retorna zero quando nao ha expiracao a aplicar.
+func (p SessionPolicy) ExpiresAt(createdAt, now time.Time) time.Time {
+	var limit time.Time
+	if p.MaxLifetime > 0 && !createdAt.IsZero() {
+		limit = createdAt.Add(p.MaxLifetime)
+	}
+	if p.IdleTimeout <= 0 {
+		return limit
+	}
+
+	expiresAt := now.Add(p.IdleTimeout)
+	if !limit.IsZero() && expiresAt.After(limit) {
+		return limit
+	}
+	return expiresAt
+}
+
 func RequireSession(store ports.SessionStore, cookieName string) func(http.Handler) http.Handler {
+	return RequireSessionWithPolicy(store, SessionPolicy{CookieName: cookieName})
+}
+
+func RequireSessionWithPolicy(store ports.SessionStore, policy SessionPolicy) func(http.Handler) http.Handler {
+	cookieName := policy.CookieName
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 			if store == nil {
@@ -33,10 +69,37 @@ func RequireSession(store ports.SessionStore, cookieName string) func(http.Handl
 					deny(w, r)
 					return
 				}
-				http.Error(w, "erro ao validar sessao", http.StatusInternalServerError)
+				writeError(w, r, http.StatusInternalServerError, "internal", "erro ao validar sessao")
 				return
 			}
 
+			now := time.Now()
+			if policy.IdleTimeout > 0 && !session.LastSeenAt.IsZero() && now.Sub(session.LastSeenAt) > policy.IdleTimeout {
+				_ = store.Delete(r.Context(), cookie.Value)
+				clearSessionCookie(w, policy)
+				deny(w, r)
+				return
+			}
+
+			if now.Sub(session.LastSeenAt) >= sessionTouchInterval {
+				expiresAt := policy.ExpiresAt(session.CreatedAt, now)
+				if err := store.Touch(r.Context(), cookie.Value, now, expiresAt); err == nil {
+					session.LastSeenAt = now
+					if policy.IdleTimeout > 0 && !expiresAt.IsZero() {
+						session.ExpiresAt = expiresAt
+						http.SetCookie(w, &http.Cookie{
+							Name:     cookieName,
+							Value:    cookie.Value,
+							Path:     "/",
+							Expires:  expiresAt,
+							HttpOnly: true,
+							Secure:   policy.Secure,
+							SameSite: policy.SameSite,
+						})
+					}
+				}
+			}
+
 			if activity, ok := userActivityFromContext(r.Context()); ok {
 				activity.UserID = session.UserID
 				activity.Role = string(session.Role)
@@ -52,16 +115,59 @@ func RequireSession(store ports.SessionStore, cookieName string) func(http.Handl
 	}
 }
 
+// OptionalSession carrega a sessao quando o cookie e valido, sem exigir login
+// nem renovar a expiracao. Serve para rotas publicas que registram o ator na
+// auditoria.
+func OptionalSession(store ports.SessionStore, cookieName string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if store == nil {
+				next.ServeHTTP(w, r)
+				return
+			}
+			cookie, err := r.Cookie(cookieName)
+			if err != nil || cookie.Value == "" {
+				next.ServeHTTP(w, r)
+				return
+			}
+			session, err := store.Get(r.Context(), cookie.Value)
+			if err != nil {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			ctx := context.WithValue(r.Context(), sessionKey, session)
+			ctx = auditctx.WithActor(ctx, auditctx.Actor{
+				ID:   session.UserID,
+				Role: string(session.Role),
+			})
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
 func SessionFromContext(ctx context.Context) (ports.Session, bool) {
 	session, ok := ctx.Value(sessionKey).(ports.Session)
 	return session, ok
 }
 
+func clearSessionCookie(w http.ResponseWriter, policy SessionPolicy) {
+	http.SetCookie(w, &http.Cookie{
+		Name:     policy.CookieName,
+		Value:    "",
+		Path:     "/",
+		MaxAge:   -1,
+		HttpOnly: true,
+		Secure:   policy.Secure,
+		SameSite: policy.SameSite,
+	})
+}
+
 func deny(w http.ResponseWriter, r *http.Request) {
-	if r.Method == http.MethodGet {
+	if r.Method == http.MethodGet && !IsAPIRequest(r) {
 		http.Redirect(w, r, "/auth/login", http.StatusSeeOther)
 		return
 	}
 
-	http.Error(w, "nao autorizado", http.StatusUnauthorized)
+	writeError(w, r, http.StatusUnauthorized, "unauthorized", "nao autorizado")
 }
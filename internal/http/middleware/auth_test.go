@@ -5,6 +5,7 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/PabloPavan/jaiu/internal/auditctx"
 	"github.com/PabloPavan/jaiu/internal/domain"
@@ -15,6 +16,9 @@ type fakeSessionStore struct {
 	session  ports.Session
 	err      error
 	gotToken string
+	touched  []time.Time
+	expires  []time.Time
+	deleted  []string
 }
 
 func (s *fakeSessionStore) Create(ctx context.Context, session ports.Session) (string, error) {
@@ -30,6 +34,25 @@ func (s *fakeSessionStore) Get(ctx context.Context, token string) (ports.Session
 }
 
 func (s *fakeSessionStore) Delete(ctx context.Context, token string) error {
+	s.deleted = append(s.deleted, token)
+	return nil
+}
+
+func (s *fakeSessionStore) DeleteByUser(ctx context.Context, userID string) error {
+	return nil
+}
+
+func (s *fakeSessionStore) Touch(ctx context.Context, token string, seenAt, expiresAt time.Time) error {
+	s.touched = append(s.touched, seenAt)
+	s.expires = append(s.expires, expiresAt)
+	return nil
+}
+
+func (s *fakeSessionStore) ListByUser(ctx context.Context, userID string) ([]ports.Session, error) {
+	return nil, nil
+}
+
+func (s *fakeSessionStore) DeleteByID(ctx context.Context, userID, sessionID string) error {
 	return nil
 }
 
@@ -178,3 +201,151 @@ func TestRequireSession_AllowsValidSession(t *testing.T) {
 		t.Fatalf("expected token %q, got %q", "token", store.gotToken)
 	}
 }
+
+// Testa que OptionalSession preenche o ator quando ha sessao e segue sem ela.
+func TestOptionalSession(t *testing.T) {
+	store := &fakeSessionStore{session: ports.Session{UserID: "user-1", Role: domain.RoleOperator}}
+	mw := OptionalSession(store, "session")
+
+	var actors []auditctx.Actor
+	handler := mw(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		actors = append(actors, auditctx.FromContext(r.Context()).Actor)
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	anonymous := httptest.NewRecorder()
+	handler.ServeHTTP(anonymous, httptest.NewRequest(http.MethodGet, "/images/key/list", nil))
+
+	req := httptest.NewRequest(http.MethodGet, "/images/key/list", nil)
+	req.AddCookie(&http.Cookie{Name: "session", Value: "token"})
+	authenticated := httptest.NewRecorder()
+	handler.ServeHTTP(authenticated, req)
+
+	if anonymous.Code != http.StatusOK || authenticated.Code != http.StatusOK {
+		t.Fatalf("expected both requests to pass, got %d and %d", anonymous.Code, authenticated.Code)
+	}
+	if len(actors) != 2 || actors[0].ID != "" || actors[1].ID != "user-1" || actors[1].Role != string(domain.RoleOperator) {
+		t.Fatalf("unexpected actors: %#v", actors)
+	}
+	if len(store.touched) != 0 {
+		t.Fatalf("expected session not to be touched")
+	}
+}
+
+// Testa que a sessao tem o ultimo acesso atualizado apenas apos o intervalo.
+func TestRequireSession_TouchesLastSeen(t *testing.T) {
+	tests := []struct {
+		name      string
+		lastSeen  time.Time
+		wantTouch bool
+	}{
+		{"stale", time.Now().Add(-2 * sessionTouchInterval), true},
+		{"recent", time.Now(), false},
+	}
+
+	for _, tt := range tests {
+		store := &fakeSessionStore{session: ports.Session{UserID: "user-1", Role: domain.RoleAdmin, LastSeenAt: tt.lastSeen}}
+		mw := RequireSession(store, "session")
+		handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusOK)
+		})
+
+		req := httptest.NewRequest(http.MethodGet, "/private", nil)
+		req.AddCookie(&http.Cookie{Name: "session", Value: "token"})
+		rec := httptest.NewRecorder()
+
+		mw(handler).ServeHTTP(rec, req)
+
+		if touched := len(store.touched) == 1; touched != tt.wantTouch {
+			t.Fatalf("%s: expected touch=%v, got %v", tt.name, tt.wantTouch, store.touched)
+		}
+	}
+}
+
+// Testa o calculo da expiracao deslizante limitada pelo tempo maximo.
+func TestSessionPolicyExpiresAt(t *testing.T) {
+	created := time.Date(2024, 1, 1, 8, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name   string
+		policy SessionPolicy
+		now    time.Time
+		want   time.Time
+	}{
+		{"disabled", SessionPolicy{}, created.Add(time.Hour), time.Time{}},
+		{"max-only", SessionPolicy{MaxLifetime: 12 * time.Hour}, created.Add(time.Hour), created.Add(12 * time.Hour)},
+		{"sliding", SessionPolicy{IdleTimeout: 30 * time.Minute, MaxLifetime: 12 * time.Hour}, created.Add(time.Hour), created.Add(90 * time.Minute)},
+		{"capped", SessionPolicy{IdleTimeout: 30 * time.Minute, MaxLifetime: 12 * time.Hour}, created.Add(11*time.Hour + 50*time.Minute), created.Add(12 * time.Hour)},
+		{"no-max", SessionPolicy{IdleTimeout: 30 * time.Minute}, created.Add(48 * time.Hour), created.Add(48*time.Hour + 30*time.Minute)},
+	}
+
+	for _, tt := range tests {
+		if got := tt.policy.ExpiresAt(created, tt.now); !got.Equal(tt.want) {
+			t.Fatalf("%s: expected %v, got %v", tt.name, tt.want, got)
+		}
+	}
+}
+
+// Testa que a atividade estende a sessao e renova o cookie.
+func TestRequireSessionWithPolicy_SlidesExpiration(t *testing.T) {
+	created := time.Now().Add(-time.Hour)
+	store := &fakeSessionStore{session: ports.Session{
+		UserID:     "user-1",
+		Role:       domain.RoleOperator,
+		CreatedAt:  created,
+		LastSeenAt: time.Now().Add(-5 * time.Minute),
+	}}
+	policy := SessionPolicy{CookieName: "session", IdleTimeout: 30 * time.Minute, MaxLifetime: 12 * time.Hour}
+	var got ports.Session
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got, _ = SessionFromContext(r.Context())
+		w.WriteHeader(http.StatusOK)
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/private", nil)
+	req.AddCookie(&http.Cookie{Name: "session", Value: "token"})
+	rec := httptest.NewRecorder()
+	RequireSessionWithPolicy(store, policy)(handler).ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d", http.StatusOK, rec.Code)
+	}
+	if len(store.expires) != 1 || time.Until(store.expires[0]) < 29*time.Minute {
+		t.Fatalf("expected expiration extended by idle timeout, got %v", store.expires)
+	}
+	if !got.ExpiresAt.Equal(store.expires[0]) {
+		t.Fatalf("expected session in context with new expiration, got %v", got.ExpiresAt)
+	}
+	cookies := rec.Result().Cookies()
+	if len(cookies) != 1 || cookies[0].Value != "token" || cookies[0].Expires.IsZero() {
+		t.Fatalf("expected refreshed cookie, got %#v", cookies)
+	}
+}
+
+// Testa que a sessao ociosa alem do limite e encerrada.
+func TestRequireSessionWithPolicy_IdleTimeout(t *testing.T) {
+	store := &fakeSessionStore{session: ports.Session{
+		UserID:     "user-1",
+		Role:       domain.RoleOperator,
+		CreatedAt:  time.Now().Add(-2 * time.Hour),
+		LastSeenAt: time.Now().Add(-time.Hour),
+	}}
+	policy := SessionPolicy{CookieName: "session", IdleTimeout: 30 * time.Minute}
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Fatal("handler should not be called")
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/private", nil)
+	req.AddCookie(&http.Cookie{Name: "session", Value: "token"})
+	rec := httptest.NewRecorder()
+	RequireSessionWithPolicy(store, policy)(handler).ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusSeeOther {
+		t.Fatalf("expected status %d, got %d", http.StatusSeeOther, rec.Code)
+	}
+	if len(store.deleted) != 1 || store.deleted[0] != "token" {
+		t.Fatalf("expected idle session to be deleted, got %v", store.deleted)
+	}
+	if len(store.touched) != 0 {
+		t.Fatalf("expected no touch for idle session, got %v", store.touched)
+	}
+}
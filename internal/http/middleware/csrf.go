@@ -0,0 +1,85 @@
+package middleware
+
+import (
+	"context"
+	"crypto/subtle"
+	"net/http"
+)
+
+const (
+	CSRFHeader    = "X-CSRF-Token"
+	CSRFFormField = "csrf_token"
+)
+
+type CSRFFailureRecorder interface {
+	RecordCSRFFailure(ctx context.Context, metadata map[string]any)
+}
+
+// RequireCSRF exige o token CSRF da sessao em toda requisicao que altera
+// estado. O token pode vir no header usado pelo HTMX ou no campo do formulario.
+func RequireCSRF(recorder CSRFFailureRecorder) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if isSafeMethod(r.Method) {
+				next.ServeHTTP(w, r)
+				return
+			}
+			// O token de API nao e enviado automaticamente pelo navegador, entao
+			// nao ha o que proteger contra CSRF.
+			if _, ok := APIPrincipalFromContext(r.Context()); ok {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			session, ok := SessionFromContext(r.Context())
+			if !ok {
+				deny(w, r)
+				return
+			}
+
+			if !VerifyCSRF(r, session.CSRFToken) {
+				RejectCSRF(w, r, recorder)
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// VerifyCSRF compara o token enviado na requisicao com o token esperado.
+func VerifyCSRF(r *http.Request, expected string) bool {
+	if expected == "" {
+		return false
+	}
+	token := r.Header.Get(CSRFHeader)
+	if token == "" {
+		token = r.PostFormValue(CSRFFormField)
+	}
+	return subtle.ConstantTimeCompare([]byte(token), []byte(expected)) == 1
+}
+
+// RejectCSRF registra a falha e responde 403.
+func RejectCSRF(w http.ResponseWriter, r *http.Request, recorder CSRFFailureRecorder) {
+	if recorder != nil {
+		reason := "mismatch"
+		if r.Header.Get(CSRFHeader) == "" && r.PostFormValue(CSRFFormField) == "" {
+			reason = "missing"
+		}
+		recorder.RecordCSRFFailure(r.Context(), map[string]any{
+			"method": r.Method,
+			"path":   r.URL.Path,
+			"reason": reason,
+		})
+	}
+	writeError(w, r, http.StatusForbidden, "csrf_invalid", "token CSRF invalido")
+}
+
+func isSafeMethod(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodTrace:
+		return true
+	default:
+		return false
+	}
+}
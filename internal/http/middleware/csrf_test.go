@@ -0,0 +1,89 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/PabloPavan/jaiu/internal/domain"
+	"github.com/PabloPavan/jaiu/internal/ports"
+)
+
+type fakeCSRFRecorder struct {
+	metadata []map[string]any
+}
+
+func (f *fakeCSRFRecorder) RecordCSRFFailure(ctx context.Context, metadata map[string]any) {
+	f.metadata = append(f.metadata, metadata)
+}
+
+// Testa a validacao do token CSRF por header, formulario e metodo seguro.
+func TestRequireCSRF(t *testing.T) {
+	tests := []struct {
+		name       string
+		method     string
+		header     string
+		form       string
+		wantStatus int
+		wantReason string
+	}{
+		{"get-allowed", http.MethodGet, "", "", http.StatusOK, ""},
+		{"header-valid", http.MethodPost, "token-1", "", http.StatusOK, ""},
+		{"form-valid", http.MethodPost, "", "token-1", http.StatusOK, ""},
+		{"missing", http.MethodPost, "", "", http.StatusForbidden, "missing"},
+		{"mismatch", http.MethodPost, "other", "", http.StatusForbidden, "mismatch"},
+	}
+
+	for _, tt := range tests {
+		recorder := &fakeCSRFRecorder{}
+		handler := RequireCSRF(recorder)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusOK)
+		}))
+
+		var body *strings.Reader
+		if tt.form != "" {
+			body = strings.NewReader(url.Values{CSRFFormField: {tt.form}}.Encode())
+		} else {
+			body = strings.NewReader("")
+		}
+		ctx := context.WithValue(context.Background(), sessionKey, ports.Session{UserID: "user-1", Role: domain.RoleAdmin, CSRFToken: "token-1"})
+		req := httptest.NewRequest(tt.method, "/students/1/delete", body).WithContext(ctx)
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		if tt.header != "" {
+			req.Header.Set(CSRFHeader, tt.header)
+		}
+		rec := httptest.NewRecorder()
+
+		handler.ServeHTTP(rec, req)
+
+		if rec.Code != tt.wantStatus {
+			t.Fatalf("%s: expected status %d, got %d", tt.name, tt.wantStatus, rec.Code)
+		}
+		if tt.wantReason == "" && len(recorder.metadata) != 0 {
+			t.Fatalf("%s: expected no audit, got %#v", tt.name, recorder.metadata)
+		}
+		if tt.wantReason != "" && (len(recorder.metadata) != 1 || recorder.metadata[0]["reason"] != tt.wantReason) {
+			t.Fatalf("%s: expected reason %q, got %#v", tt.name, tt.wantReason, recorder.metadata)
+		}
+	}
+}
+
+// Testa que sessao sem token nunca aceita requisicoes que alteram estado.
+func TestRequireCSRFSessionWithoutToken(t *testing.T) {
+	handler := RequireCSRF(nil)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	ctx := context.WithValue(context.Background(), sessionKey, ports.Session{UserID: "user-1", Role: domain.RoleAdmin})
+	req := httptest.NewRequest(http.MethodPost, "/students/1/delete", nil).WithContext(ctx)
+	rec := httptest.NewRecorder()
+
+	handler.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusForbidden {
+		t.Fatalf("expected 403, got %d", rec.Code)
+	}
+}
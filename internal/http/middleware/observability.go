@@ -48,9 +48,11 @@ func Observability() func(http.Handler) http.Handler {
 
 			next.ServeHTTP(recorder, r)
 
+			// So o padrao da rota vai para metricas, spans e logs: o caminho
+			// cru pode levar identificadores e segredos.
 			route := chi.RouteContext(r.Context()).RoutePattern()
 			if route == "" {
-				route = r.URL.Path
+				route = "unmatched"
 			}
 
 			status := recorder.status
@@ -61,7 +63,6 @@ func Observability() func(http.Handler) http.Handler {
 			attrs := []attribute.KeyValue{
 				attribute.String("http.method", r.Method),
 				attribute.String("http.route", route),
-				attribute.String("http.target", r.URL.Path),
 				attribute.String("http.user_agent", r.UserAgent()),
 				attribute.String("net.peer.ip", clientIP(r)),
 				attribute.Int("http.status_code", status),
@@ -0,0 +1,33 @@
+package middleware
+
+import "net/http"
+
+// RequirePasswordChange limita a sessao a pagina de troca de senha enquanto o
+// usuario estiver obrigado a trocar a senha.
+func RequirePasswordChange(changePath string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			session, ok := SessionFromContext(r.Context())
+			if !ok || !session.MustChangePassword || r.URL.Path == changePath {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			if IsAPIRequest(r) {
+				WriteAPIError(w, http.StatusForbidden, "password_change_required", "troca de senha obrigatoria")
+				return
+			}
+			if r.Header.Get("HX-Request") == "true" {
+				w.Header().Set("HX-Redirect", changePath)
+				w.WriteHeader(http.StatusNoContent)
+				return
+			}
+			if r.Method == http.MethodGet {
+				http.Redirect(w, r, changePath, http.StatusSeeOther)
+				return
+			}
+
+			http.Error(w, "troca de senha obrigatoria", http.StatusForbidden)
+		})
+	}
+}
@@ -0,0 +1,62 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/PabloPavan/jaiu/internal/ports"
+)
+
+// Testa que a troca obrigatoria de senha restringe a navegacao.
+func TestRequirePasswordChange(t *testing.T) {
+	tests := []struct {
+		name         string
+		session      *ports.Session
+		method       string
+		path         string
+		htmx         bool
+		wantStatus   int
+		wantLocation string
+		wantNext     bool
+	}{
+		{"no-session", nil, http.MethodGet, "/students", false, http.StatusOK, "", true},
+		{"not-required", &ports.Session{UserID: "user-1"}, http.MethodGet, "/students", false, http.StatusOK, "", true},
+		{"change-page", &ports.Session{UserID: "user-1", MustChangePassword: true}, http.MethodPost, "/account/password", false, http.StatusOK, "", true},
+		{"redirect-get", &ports.Session{UserID: "user-1", MustChangePassword: true}, http.MethodGet, "/students", false, http.StatusSeeOther, "/account/password", false},
+		{"forbid-post", &ports.Session{UserID: "user-1", MustChangePassword: true}, http.MethodPost, "/students", false, http.StatusForbidden, "", false},
+		{"htmx-redirect", &ports.Session{UserID: "user-1", MustChangePassword: true}, http.MethodPost, "/students", true, http.StatusNoContent, "", false},
+	}
+
+	for _, tt := range tests {
+		called := false
+		handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			called = true
+			w.WriteHeader(http.StatusOK)
+		})
+
+		req := httptest.NewRequest(tt.method, tt.path, nil)
+		if tt.session != nil {
+			req = req.WithContext(context.WithValue(req.Context(), sessionKey, *tt.session))
+		}
+		if tt.htmx {
+			req.Header.Set("HX-Request", "true")
+		}
+		rec := httptest.NewRecorder()
+		RequirePasswordChange("/account/password")(handler).ServeHTTP(rec, req)
+
+		if rec.Code != tt.wantStatus {
+			t.Fatalf("%s: expected status %d, got %d", tt.name, tt.wantStatus, rec.Code)
+		}
+		if called != tt.wantNext {
+			t.Fatalf("%s: expected next called=%v, got %v", tt.name, tt.wantNext, called)
+		}
+		if tt.wantLocation != "" && rec.Header().Get("Location") != tt.wantLocation {
+			t.Fatalf("%s: expected location %q, got %q", tt.name, tt.wantLocation, rec.Header().Get("Location"))
+		}
+		if tt.htmx && rec.Header().Get("HX-Redirect") != "/account/password" {
+			t.Fatalf("%s: expected HX-Redirect, got %q", tt.name, rec.Header().Get("HX-Redirect"))
+		}
+	}
+}
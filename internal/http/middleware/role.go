@@ -0,0 +1,50 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/PabloPavan/jaiu/internal/domain"
+)
+
+type AccessDeniedRecorder interface {
+	RecordDenied(ctx context.Context, permission domain.Permission, metadata map[string]any)
+}
+
+func RequireRole(permission domain.Permission, recorder AccessDeniedRecorder) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			role, ok := requestRole(r.Context())
+			if !ok {
+				deny(w, r)
+				return
+			}
+
+			if !role.Can(permission) {
+				if recorder != nil {
+					recorder.RecordDenied(r.Context(), permission, map[string]any{
+						"method": r.Method,
+						"path":   r.URL.Path,
+						"role":   string(role),
+					})
+				}
+				writeError(w, r, http.StatusForbidden, "forbidden", "acesso negado")
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// requestRole devolve o papel da sessao ou, em requisicoes por token de API,
+// o papel do usuario dono do token.
+func requestRole(ctx context.Context) (domain.UserRole, bool) {
+	if session, ok := SessionFromContext(ctx); ok {
+		return session.Role, true
+	}
+	if principal, ok := APIPrincipalFromContext(ctx); ok {
+		return principal.Role, true
+	}
+	return "", false
+}
@@ -0,0 +1,79 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/PabloPavan/jaiu/internal/domain"
+	"github.com/PabloPavan/jaiu/internal/ports"
+)
+
+type fakeDeniedRecorder struct {
+	permissions []domain.Permission
+	metadata    []map[string]any
+}
+
+func (f *fakeDeniedRecorder) RecordDenied(ctx context.Context, permission domain.Permission, metadata map[string]any) {
+	f.permissions = append(f.permissions, permission)
+	f.metadata = append(f.metadata, metadata)
+}
+
+// Testa a autorizacao por papel com registro de negacao.
+func TestRequireRole(t *testing.T) {
+	tests := []struct {
+		name       string
+		role       domain.UserRole
+		wantStatus int
+		wantDenied bool
+	}{
+		{"admin-allowed", domain.RoleAdmin, http.StatusOK, false},
+		{"operator-denied", domain.RoleOperator, http.StatusForbidden, true},
+	}
+
+	for _, tt := range tests {
+		recorder := &fakeDeniedRecorder{}
+		mw := RequireRole(domain.PermissionPlanDelete, recorder)
+		handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusOK)
+		})
+
+		ctx := context.WithValue(context.Background(), sessionKey, ports.Session{UserID: "user-1", Role: tt.role})
+		req := httptest.NewRequest(http.MethodPost, "/plans/1/delete", nil).WithContext(ctx)
+		rec := httptest.NewRecorder()
+
+		mw(handler).ServeHTTP(rec, req)
+
+		if rec.Code != tt.wantStatus {
+			t.Fatalf("%s: expected status %d, got %d", tt.name, tt.wantStatus, rec.Code)
+		}
+		if denied := len(recorder.permissions) == 1; denied != tt.wantDenied {
+			t.Fatalf("%s: expected denied=%v, got %#v", tt.name, tt.wantDenied, recorder.permissions)
+		}
+		if tt.wantDenied && recorder.metadata[0]["path"] != "/plans/1/delete" {
+			t.Fatalf("%s: expected path metadata, got %#v", tt.name, recorder.metadata[0])
+		}
+	}
+}
+
+// Testa que a ausencia de sessao nega o acesso sem registrar auditoria.
+func TestRequireRole_MissingSession(t *testing.T) {
+	recorder := &fakeDeniedRecorder{}
+	mw := RequireRole(domain.PermissionPlanCreate, recorder)
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Fatal("handler should not be called")
+	})
+
+	req := httptest.NewRequest(http.MethodPost, "/plans", nil)
+	rec := httptest.NewRecorder()
+
+	mw(handler).ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusUnauthorized {
+		t.Fatalf("expected status %d, got %d", http.StatusUnauthorized, rec.Code)
+	}
+	if len(recorder.permissions) != 0 {
+		t.Fatalf("expected no denial record, got %#v", recorder.permissions)
+	}
+}
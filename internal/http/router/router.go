@@ -6,12 +6,13 @@ import (
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/chi/v5/middleware"
 
+	"github.com/PabloPavan/jaiu/internal/domain"
 	"github.com/PabloPavan/jaiu/internal/http/handlers"
 	httpmw "github.com/PabloPavan/jaiu/internal/http/middleware"
 	"github.com/PabloPavan/jaiu/internal/ports"
 )
 
-func New(h *handlers.Handler, sessions ports.SessionStore, cookieName string, notifyCfg httpmw.NotifyConfig, eventHandler http.Handler) http.Handler {
+func New(h *handlers.Handler, sessions ports.SessionStore, sessionPolicy httpmw.SessionPolicy, notifyCfg httpmw.NotifyConfig, eventHandler http.Handler) http.Handler {
 	r := chi.NewRouter()
 
 	r.Use(middleware.RequestID)
@@ -20,16 +21,92 @@ func New(h *handlers.Handler, sessions ports.SessionStore, cookieName string, no
 	r.Use(httpmw.Notify(notifyCfg))
 	r.Use(middleware.Recoverer)
 
+	denied := h.AccessDeniedRecorder()
+	requireRole := func(permission domain.Permission) func(http.Handler) http.Handler {
+		return httpmw.RequireRole(permission, denied)
+	}
+
 	r.Get("/healthz", h.Health)
 
 	r.Route("/auth", func(r chi.Router) {
 		r.Get("/login", h.Login)
 		r.Post("/login", h.LoginPost)
 		r.Post("/logout", h.Logout)
+		r.Get("/forgot", h.ForgotPassword)
+		r.Post("/forgot", h.ForgotPasswordPost)
+		r.Get("/reset", h.ResetPassword)
+		r.Post("/reset", h.ResetPasswordPost)
+		r.Get("/mfa", h.MFAChallenge)
+		r.Post("/mfa", h.MFAChallengePost)
+	})
+
+	r.Route("/api/v1", func(r chi.Router) {
+		r.NotFound(h.APINotFound)
+		r.MethodNotAllowed(h.APIMethodNotAllowed)
+		r.Get("/openapi.json", h.APIOpenAPI)
+
+		r.Group(func(r chi.Router) {
+			r.Use(httpmw.RequireAPIAuth(h.APITokenAuthenticator(), sessions, sessionPolicy))
+			r.Use(httpmw.RequireCSRF(h.CSRFFailureRecorder()))
+			r.Use(httpmw.RequirePasswordChange("/account/password"))
+
+			r.Route("/students", func(r chi.Router) {
+				r.Use(httpmw.RequireAPIScope(domain.APIResourceStudents))
+				r.Get("/", h.APIStudentsList)
+				r.Post("/", h.APIStudentsCreate)
+				r.Get("/{studentID}", h.APIStudentsGet)
+				r.Put("/{studentID}", h.APIStudentsUpdate)
+				r.Delete("/{studentID}", h.APIStudentsDelete)
+				r.With(httpmw.RequireAPIScope(domain.APIResourceSubscriptions)).Get("/{studentID}/subscriptions", h.APIStudentSubscriptions)
+			})
+
+			r.Route("/plans", func(r chi.Router) {
+				r.Use(httpmw.RequireAPIScope(domain.APIResourcePlans))
+				r.Get("/", h.APIPlansList)
+				r.With(requireRole(domain.PermissionPlanCreate)).Post("/", h.APIPlansCreate)
+				r.Get("/{planID}", h.APIPlansGet)
+				r.With(requireRole(domain.PermissionPlanUpdate)).Put("/{planID}", h.APIPlansUpdate)
+				r.With(requireRole(domain.PermissionPlanDelete)).Delete("/{planID}", h.APIPlansDelete)
+			})
+
+			r.Route("/subscriptions", func(r chi.Router) {
+				r.Use(httpmw.RequireAPIScope(domain.APIResourceSubscriptions))
+				r.Get("/", h.APISubscriptionsList)
+				r.Post("/", h.APISubscriptionsCreate)
+				r.Get("/{subscriptionID}", h.APISubscriptionsGet)
+				r.Put("/{subscriptionID}", h.APISubscriptionsUpdate)
+				r.Post("/{subscriptionID}/cancel", h.APISubscriptionsCancel)
+				r.Post("/{subscriptionID}/change-plan", h.APISubscriptionsChangePlan)
+				r.Get("/{subscriptionID}/freezes", h.APISubscriptionFreezes)
+				r.Post("/{subscriptionID}/freezes", h.APISubscriptionFreezesCreate)
+				r.With(httpmw.RequireAPIScope(domain.APIResourcePayments)).Get("/{subscriptionID}/payments", h.APISubscriptionPayments)
+				r.With(httpmw.RequireAPIScope(domain.APIResourcePayments)).Get("/{subscriptionID}/billing-periods", h.APISubscriptionBillingPeriods)
+				r.With(httpmw.RequireAPIScope(domain.APIResourcePayments), requireRole(domain.PermissionBillingFeeWaive)).Post("/{subscriptionID}/billing-periods/{periodID}/waive-fees", h.APISubscriptionBillingPeriodWaiveFees)
+			})
+
+			r.Route("/payments", func(r chi.Router) {
+				r.Use(httpmw.RequireAPIScope(domain.APIResourcePayments))
+				r.Get("/", h.APIPaymentsList)
+				r.Post("/", h.APIPaymentsCreate)
+				r.Get("/{paymentID}", h.APIPaymentsGet)
+				r.Patch("/{paymentID}", h.APIPaymentsUpdate)
+				r.With(requireRole(domain.PermissionPaymentReverse)).Post("/{paymentID}/reverse", h.APIPaymentsReverse)
+			})
+
+			r.Route("/reports", func(r chi.Router) {
+				r.Use(httpmw.RequireAPIScope(domain.APIResourceReports))
+				r.Get("/revenue", h.APIReportsRevenue)
+				r.Get("/students-by-status", h.APIReportsStudentsByStatus)
+				r.Get("/delinquent", h.APIReportsDelinquent)
+				r.Get("/upcoming-due", h.APIReportsUpcomingDue)
+			})
+		})
 	})
 
 	r.Group(func(r chi.Router) {
-		r.Use(httpmw.RequireSession(sessions, cookieName))
+		r.Use(httpmw.RequireSessionWithPolicy(sessions, sessionPolicy))
+		r.Use(httpmw.RequireCSRF(h.CSRFFailureRecorder()))
+		r.Use(httpmw.RequirePasswordChange("/account/password"))
 
 		if eventHandler != nil {
 			r.Get("/events", eventHandler.ServeHTTP)
@@ -43,17 +120,26 @@ func New(h *handlers.Handler, sessions ports.SessionStore, cookieName string, no
 			r.Post("/", h.StudentsCreate)
 			r.Get("/{studentID}/edit", h.StudentsEdit)
 			r.Post("/{studentID}", h.StudentsUpdate)
+			r.With(requireRole(domain.PermissionAuditView)).Get("/{studentID}/history", h.StudentsHistory)
 			r.Post("/{studentID}/delete", h.StudentsDelete)
 			r.With(httpmw.RequireHTMX).Get("/preview", h.StudentsPreview)
 		})
 
 		r.Route("/plans", func(r chi.Router) {
 			r.Get("/", h.PlansIndex)
-			r.Get("/new", h.PlansNew)
-			r.Post("/", h.PlansCreate)
-			r.Get("/{planID}/edit", h.PlansEdit)
-			r.Post("/{planID}", h.PlansUpdate)
-			r.Post("/{planID}/delete", h.PlansDelete)
+			r.With(requireRole(domain.PermissionPlanCreate)).Get("/new", h.PlansNew)
+			r.With(requireRole(domain.PermissionPlanCreate)).Post("/", h.PlansCreate)
+			r.With(requireRole(domain.PermissionPlanUpdate)).Get("/{planID}/edit", h.PlansEdit)
+			r.With(requireRole(domain.PermissionPlanUpdate)).Post("/{planID}", h.PlansUpdate)
+			r.With(requireRole(domain.PermissionPlanDelete)).Post("/{planID}/delete", h.PlansDelete)
+		})
+
+		r.Route("/coupons", func(r chi.Router) {
+			r.Use(requireRole(domain.PermissionCouponManage))
+			r.Get("/", h.CouponsIndex)
+			r.Get("/new", h.CouponsNew)
+			r.Post("/", h.CouponsCreate)
+			r.Post("/{couponID}/deactivate", h.CouponsDeactivate)
 		})
 
 		r.Route("/subscriptions", func(r chi.Router) {
@@ -62,7 +148,11 @@ func New(h *handlers.Handler, sessions ports.SessionStore, cookieName string, no
 			r.Post("/", h.SubscriptionsCreate)
 			r.Get("/{subscriptionID}/edit", h.SubscriptionsEdit)
 			r.Post("/{subscriptionID}", h.SubscriptionsUpdate)
+			r.With(requireRole(domain.PermissionAuditView)).Get("/{subscriptionID}/history", h.SubscriptionsHistory)
 			r.Post("/{subscriptionID}/cancel", h.SubscriptionsCancel)
+			r.Post("/{subscriptionID}/change-plan", h.SubscriptionsChangePlan)
+			r.Post("/{subscriptionID}/freezes", h.SubscriptionsFreeze)
+			r.With(requireRole(domain.PermissionBillingFeeWaive)).Post("/{subscriptionID}/billing-periods/{periodID}/waive-fees", h.SubscriptionsWaiveFees)
 		})
 
 		r.Route("/payments", func(r chi.Router) {
@@ -71,16 +161,54 @@ func New(h *handlers.Handler, sessions ports.SessionStore, cookieName string, no
 			r.Post("/", h.PaymentsCreate)
 			r.Get("/{paymentID}/edit", h.PaymentsEdit)
 			r.Post("/{paymentID}", h.PaymentsUpdate)
-			r.Post("/{paymentID}/reverse", h.PaymentsReverse)
+			r.With(requireRole(domain.PermissionAuditView)).Get("/{paymentID}/history", h.PaymentsHistory)
+			r.With(requireRole(domain.PermissionPaymentReverse)).Post("/{paymentID}/reverse", h.PaymentsReverse)
 		})
 
 		r.Route("/reports", func(r chi.Router) {
 			r.Get("/", h.ReportsIndex)
 		})
+
+		r.Get("/account/password", h.PasswordEdit)
+		r.Post("/account/password", h.PasswordUpdate)
+
+		r.Route("/account/mfa", func(r chi.Router) {
+			r.Get("/", h.MFASettings)
+			r.Post("/enroll", h.MFAEnroll)
+			r.Post("/confirm", h.MFAConfirm)
+			r.Post("/disable", h.MFADisable)
+			r.Post("/recovery-codes", h.MFARecoveryCodes)
+		})
+
+		r.Route("/account/sessions", func(r chi.Router) {
+			r.Get("/", h.SessionsIndex)
+			r.Post("/revoke-all", h.SessionsRevokeAll)
+			r.Post("/{sessionID}/revoke", h.SessionsRevoke)
+		})
+
+		r.With(requireRole(domain.PermissionAuditView)).Get("/audit", h.AuditIndex)
+
+		r.Route("/users", func(r chi.Router) {
+			r.Use(requireRole(domain.PermissionUserManage))
+			r.Get("/", h.UsersIndex)
+			r.Get("/new", h.UsersNew)
+			r.Post("/", h.UsersCreate)
+			r.Get("/{userID}/edit", h.UsersEdit)
+			r.Post("/{userID}", h.UsersUpdate)
+			r.Post("/{userID}/active", h.UsersSetActive)
+		})
+
+		r.Route("/api-tokens", func(r chi.Router) {
+			r.Use(requireRole(domain.PermissionAPITokenManage))
+			r.Get("/", h.APITokensIndex)
+			r.Get("/new", h.APITokensNew)
+			r.Post("/", h.APITokensCreate)
+			r.Post("/{tokenID}/revoke", h.APITokensRevoke)
+		})
 	})
 
 	if imageHandler := h.ImageHandler(); imageHandler != nil {
-		r.Handle("/images/*", http.StripPrefix("/images", imageHandler))
+		r.With(httpmw.OptionalSession(sessions, sessionPolicy.CookieName)).Handle("/images/*", http.StripPrefix("/images", imageHandler))
 	}
 	r.Handle("/static/*", http.StripPrefix("/static", http.FileServer(http.Dir("web/static"))))
 
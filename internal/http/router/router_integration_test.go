@@ -2,13 +2,19 @@ package router
 
 import (
 	"context"
+	"encoding/json"
+	"fmt"
 	"mime/multipart"
 	"net/http"
 	"net/http/httptest"
 	"net/url"
 	"strings"
 	"testing"
+	"time"
 
+	"github.com/go-chi/chi/v5"
+
+	"github.com/PabloPavan/jaiu/internal/auditctx"
 	"github.com/PabloPavan/jaiu/internal/domain"
 	"github.com/PabloPavan/jaiu/internal/http/handlers"
 	httpmw "github.com/PabloPavan/jaiu/internal/http/middleware"
@@ -59,6 +65,45 @@ func (s *fakeSessionStore) Delete(ctx context.Context, token string) error {
 	return nil
 }
 
+func (s *fakeSessionStore) DeleteByUser(ctx context.Context, userID string) error {
+	for token, session := range s.sessions {
+		if session.UserID == userID {
+			delete(s.sessions, token)
+		}
+	}
+	return nil
+}
+
+func (s *fakeSessionStore) Touch(ctx context.Context, token string, seenAt, expiresAt time.Time) error {
+	session, ok := s.sessions[token]
+	if !ok {
+		return ports.ErrNotFound
+	}
+	session.LastSeenAt = seenAt
+	s.sessions[token] = session
+	return nil
+}
+
+func (s *fakeSessionStore) ListByUser(ctx context.Context, userID string) ([]ports.Session, error) {
+	sessions := make([]ports.Session, 0)
+	for _, session := range s.sessions {
+		if session.UserID == userID {
+			sessions = append(sessions, session)
+		}
+	}
+	return sessions, nil
+}
+
+func (s *fakeSessionStore) DeleteByID(ctx context.Context, userID, sessionID string) error {
+	for token, session := range s.sessions {
+		if session.UserID == userID && session.ID == sessionID {
+			delete(s.sessions, token)
+			return nil
+		}
+	}
+	return ports.ErrNotFound
+}
+
 type fakeAuthService struct {
 	user        domain.User
 	err         error
@@ -94,7 +139,7 @@ func (f *fakeImageService) Handler() http.Handler {
 // Testa que o healthcheck responde sem depender de sessao.
 func TestRouterHealthz(t *testing.T) {
 	h := handlers.New(handlers.Services{}, nil, handlers.SessionConfig{})
-	r := New(h, nil, "", httpmw.NotifyConfig{}, nil)
+	r := New(h, nil, httpmw.SessionPolicy{}, httpmw.NotifyConfig{}, nil)
 
 	req := httptest.NewRequest(http.MethodGet, "/healthz", nil)
 	rec := httptest.NewRecorder()
@@ -112,7 +157,7 @@ func TestRouterHealthz(t *testing.T) {
 func TestRouterProtectedRedirectsWithoutSession(t *testing.T) {
 	store := &fakeSessionStore{}
 	h := handlers.New(handlers.Services{}, store, handlers.SessionConfig{CookieName: "test_session"})
-	r := New(h, store, "test_session", httpmw.NotifyConfig{}, nil)
+	r := New(h, store, httpmw.SessionPolicy{CookieName: "test_session"}, httpmw.NotifyConfig{}, nil)
 
 	req := httptest.NewRequest(http.MethodGet, "/students/", nil)
 	rec := httptest.NewRecorder()
@@ -134,7 +179,7 @@ func TestRouterProtectedAllowsWithSession(t *testing.T) {
 		},
 	}
 	h := handlers.New(handlers.Services{}, store, handlers.SessionConfig{CookieName: "test_session"})
-	r := New(h, store, "test_session", httpmw.NotifyConfig{}, nil)
+	r := New(h, store, httpmw.SessionPolicy{CookieName: "test_session"}, httpmw.NotifyConfig{}, nil)
 
 	req := httptest.NewRequest(http.MethodGet, "/students/", nil)
 	req.AddCookie(&http.Cookie{Name: "test_session", Value: "token-1"})
@@ -155,7 +200,7 @@ func TestRouterEventsRouteOptional(t *testing.T) {
 	}
 	h := handlers.New(handlers.Services{}, store, handlers.SessionConfig{CookieName: "test_session"})
 
-	noEvents := New(h, store, "test_session", httpmw.NotifyConfig{}, nil)
+	noEvents := New(h, store, httpmw.SessionPolicy{CookieName: "test_session"}, httpmw.NotifyConfig{}, nil)
 	req := httptest.NewRequest(http.MethodGet, "/events", nil)
 	req.AddCookie(&http.Cookie{Name: "test_session", Value: "token-1"})
 	rec := httptest.NewRecorder()
@@ -167,7 +212,7 @@ func TestRouterEventsRouteOptional(t *testing.T) {
 	eventHandler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		w.WriteHeader(http.StatusNoContent)
 	})
-	withEvents := New(h, store, "test_session", httpmw.NotifyConfig{}, eventHandler)
+	withEvents := New(h, store, httpmw.SessionPolicy{CookieName: "test_session"}, httpmw.NotifyConfig{}, eventHandler)
 
 	unauthReq := httptest.NewRequest(http.MethodGet, "/events", nil)
 	unauthRec := httptest.NewRecorder()
@@ -190,7 +235,7 @@ func TestRouterImagesRouteOptional(t *testing.T) {
 	store := &fakeSessionStore{}
 	h := handlers.New(handlers.Services{}, store, handlers.SessionConfig{CookieName: "test_session"})
 
-	withoutImages := New(h, store, "test_session", httpmw.NotifyConfig{}, nil)
+	withoutImages := New(h, store, httpmw.SessionPolicy{CookieName: "test_session"}, httpmw.NotifyConfig{}, nil)
 	req := httptest.NewRequest(http.MethodGet, "/images/test", nil)
 	rec := httptest.NewRecorder()
 	withoutImages.ServeHTTP(rec, req)
@@ -203,7 +248,7 @@ func TestRouterImagesRouteOptional(t *testing.T) {
 			w.WriteHeader(http.StatusAccepted)
 		})},
 	})
-	withImages := New(h, store, "test_session", httpmw.NotifyConfig{}, nil)
+	withImages := New(h, store, httpmw.SessionPolicy{CookieName: "test_session"}, httpmw.NotifyConfig{}, nil)
 	imgReq := httptest.NewRequest(http.MethodGet, "/images/test", nil)
 	imgRec := httptest.NewRecorder()
 	withImages.ServeHTTP(imgRec, imgReq)
@@ -219,7 +264,7 @@ func TestRouterLoginPost(t *testing.T) {
 	}
 	store := &fakeSessionStore{}
 	h := handlers.New(handlers.Services{Auth: auth}, store, handlers.SessionConfig{CookieName: "test_session"})
-	r := New(h, store, "test_session", httpmw.NotifyConfig{}, nil)
+	r := New(h, store, httpmw.SessionPolicy{CookieName: "test_session"}, httpmw.NotifyConfig{}, nil)
 
 	values := url.Values{
 		"email":    {"user@example.com"},
@@ -242,7 +287,1002 @@ func TestRouterLoginPost(t *testing.T) {
 	if store.lastCreated.UserID != "user-1" {
 		t.Fatalf("expected session for user-1, got %#v", store.lastCreated)
 	}
+	if store.lastCreated.CSRFToken == "" {
+		t.Fatalf("expected session with csrf token")
+	}
 	if auth.gotEmail != "user@example.com" || auth.gotPassword != "secret" {
 		t.Fatalf("expected auth to receive credentials, got %q/%q", auth.gotEmail, auth.gotPassword)
 	}
 }
+
+type fakeAuthorizationService struct {
+	denied []domain.Permission
+	csrf   []map[string]any
+}
+
+func (f *fakeAuthorizationService) RecordDenied(ctx context.Context, permission domain.Permission, metadata map[string]any) {
+	f.denied = append(f.denied, permission)
+}
+
+func (f *fakeAuthorizationService) RecordCSRFFailure(ctx context.Context, metadata map[string]any) {
+	f.csrf = append(f.csrf, metadata)
+}
+
+// Testa que rotas administrativas negam operadores e registram a negacao.
+func TestRouterRequireRole(t *testing.T) {
+	tests := []struct {
+		name       string
+		role       domain.UserRole
+		method     string
+		path       string
+		wantStatus int
+		wantDenied []domain.Permission
+	}{
+		{"operator-plan-new", domain.RoleOperator, http.MethodGet, "/plans/new", http.StatusForbidden, []domain.Permission{domain.PermissionPlanCreate}},
+		{"operator-plan-delete", domain.RoleOperator, http.MethodPost, "/plans/plan-1/delete", http.StatusForbidden, []domain.Permission{domain.PermissionPlanDelete}},
+		{"operator-payment-reverse", domain.RoleOperator, http.MethodPost, "/payments/pay-1/reverse", http.StatusForbidden, []domain.Permission{domain.PermissionPaymentReverse}},
+		{"operator-waive-fees", domain.RoleOperator, http.MethodPost, "/subscriptions/sub-1/billing-periods/period-1/waive-fees", http.StatusForbidden, []domain.Permission{domain.PermissionBillingFeeWaive}},
+		{"operator-plans-index", domain.RoleOperator, http.MethodGet, "/plans/", http.StatusOK, nil},
+		{"admin-plan-new", domain.RoleAdmin, http.MethodGet, "/plans/new", http.StatusOK, nil},
+		{"operator-coupons", domain.RoleOperator, http.MethodGet, "/coupons/", http.StatusForbidden, []domain.Permission{domain.PermissionCouponManage}},
+		{"admin-coupons", domain.RoleAdmin, http.MethodGet, "/coupons/", http.StatusOK, nil},
+		{"operator-users", domain.RoleOperator, http.MethodGet, "/users/", http.StatusForbidden, []domain.Permission{domain.PermissionUserManage}},
+		{"admin-users", domain.RoleAdmin, http.MethodGet, "/users/", http.StatusOK, nil},
+	}
+
+	for _, tt := range tests {
+		store := &fakeSessionStore{
+			sessions: map[string]ports.Session{
+				"token-1": {UserID: "user-1", Role: tt.role, CSRFToken: "csrf-1"},
+			},
+		}
+		authz := &fakeAuthorizationService{}
+		h := handlers.New(handlers.Services{Authorization: authz}, store, handlers.SessionConfig{CookieName: "test_session"})
+		r := New(h, store, httpmw.SessionPolicy{CookieName: "test_session"}, httpmw.NotifyConfig{}, nil)
+
+		req := httptest.NewRequest(tt.method, tt.path, nil)
+		req.Header.Set("X-CSRF-Token", "csrf-1")
+		req.AddCookie(&http.Cookie{Name: "test_session", Value: "token-1"})
+		rec := httptest.NewRecorder()
+		r.ServeHTTP(rec, req)
+
+		if rec.Code != tt.wantStatus {
+			t.Fatalf("%s: expected %d, got %d", tt.name, tt.wantStatus, rec.Code)
+		}
+		if len(authz.denied) != len(tt.wantDenied) {
+			t.Fatalf("%s: expected denied %v, got %v", tt.name, tt.wantDenied, authz.denied)
+		}
+		for i, permission := range tt.wantDenied {
+			if authz.denied[i] != permission {
+				t.Fatalf("%s: expected denied %v, got %v", tt.name, tt.wantDenied, authz.denied)
+			}
+		}
+	}
+}
+
+type fakeSessionService struct {
+	store *fakeSessionStore
+}
+
+func (f *fakeSessionService) ListByUser(ctx context.Context, userID string) ([]ports.Session, error) {
+	return f.store.ListByUser(ctx, userID)
+}
+
+func (f *fakeSessionService) Revoke(ctx context.Context, userID, sessionID string) error {
+	return f.store.DeleteByID(ctx, userID, sessionID)
+}
+
+func (f *fakeSessionService) RevokeAll(ctx context.Context, userID string) error {
+	return f.store.DeleteByUser(ctx, userID)
+}
+
+// Testa a listagem e o encerramento de sessoes do proprio usuario.
+// Testa que requisicoes que alteram estado exigem o token CSRF da sessao.
+func TestRouterCSRF(t *testing.T) {
+	store := &fakeSessionStore{
+		sessions: map[string]ports.Session{
+			"token-1": {ID: "sess-1", UserID: "user-1", Role: domain.RoleOperator, CSRFToken: "csrf-1"},
+		},
+	}
+	authz := &fakeAuthorizationService{}
+	sessions := &fakeSessionService{store: store}
+	h := handlers.New(handlers.Services{Authorization: authz, Sessions: sessions}, store, handlers.SessionConfig{CookieName: "test_session"})
+	r := New(h, store, httpmw.SessionPolicy{CookieName: "test_session"}, httpmw.NotifyConfig{}, nil)
+
+	page := httptest.NewRequest(http.MethodGet, "/account/sessions/", nil)
+	page.AddCookie(&http.Cookie{Name: "test_session", Value: "token-1"})
+	pageRec := httptest.NewRecorder()
+	r.ServeHTTP(pageRec, page)
+	if pageRec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", pageRec.Code)
+	}
+	if !strings.Contains(pageRec.Body.String(), `name="csrf_token" value="csrf-1"`) {
+		t.Fatalf("expected csrf field in forms")
+	}
+
+	missing := httptest.NewRequest(http.MethodPost, "/account/sessions/revoke-all", nil)
+	missing.AddCookie(&http.Cookie{Name: "test_session", Value: "token-1"})
+	missingRec := httptest.NewRecorder()
+	r.ServeHTTP(missingRec, missing)
+	if missingRec.Code != http.StatusForbidden {
+		t.Fatalf("expected 403 without token, got %d", missingRec.Code)
+	}
+
+	wrong := httptest.NewRequest(http.MethodPost, "/account/sessions/revoke-all", nil)
+	wrong.Header.Set("X-CSRF-Token", "outro")
+	wrong.AddCookie(&http.Cookie{Name: "test_session", Value: "token-1"})
+	wrongRec := httptest.NewRecorder()
+	r.ServeHTTP(wrongRec, wrong)
+	if wrongRec.Code != http.StatusForbidden {
+		t.Fatalf("expected 403 with wrong token, got %d", wrongRec.Code)
+	}
+
+	if len(authz.csrf) != 2 {
+		t.Fatalf("expected 2 csrf failures recorded, got %d", len(authz.csrf))
+	}
+	if authz.csrf[0]["reason"] != "missing" || authz.csrf[1]["reason"] != "mismatch" {
+		t.Fatalf("unexpected csrf metadata: %v", authz.csrf)
+	}
+	if _, ok := store.sessions["token-1"]; !ok {
+		t.Fatal("expected session to remain after rejected requests")
+	}
+
+	values := url.Values{"csrf_token": {"csrf-1"}}
+	valid := httptest.NewRequest(http.MethodPost, "/account/sessions/revoke-all", strings.NewReader(values.Encode()))
+	valid.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	valid.AddCookie(&http.Cookie{Name: "test_session", Value: "token-1"})
+	validRec := httptest.NewRecorder()
+	r.ServeHTTP(validRec, valid)
+	if validRec.Code == http.StatusForbidden {
+		t.Fatalf("expected request with token to pass")
+	}
+	if _, ok := store.sessions["token-1"]; ok {
+		t.Fatal("expected sessions to be revoked")
+	}
+}
+
+func TestRouterAccountSessions(t *testing.T) {
+	store := &fakeSessionStore{
+		sessions: map[string]ports.Session{
+			"token-1": {ID: "sess-1", UserID: "user-1", Role: domain.RoleOperator, CSRFToken: "csrf-1", UserAgent: "Mozilla/5.0 (X11; Linux x86_64) Firefox/120.0"},
+			"token-2": {ID: "sess-2", UserID: "user-1", Role: domain.RoleOperator},
+			"token-3": {ID: "sess-3", UserID: "user-2", Role: domain.RoleOperator},
+		},
+	}
+	h := handlers.New(handlers.Services{Sessions: &fakeSessionService{store: store}}, store, handlers.SessionConfig{CookieName: "test_session"})
+	r := New(h, store, httpmw.SessionPolicy{CookieName: "test_session"}, httpmw.NotifyConfig{}, nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/account/sessions/", nil)
+	req.AddCookie(&http.Cookie{Name: "test_session", Value: "token-1"})
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, req)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", rec.Code)
+	}
+	if !strings.Contains(rec.Body.String(), "Firefox em Linux") {
+		t.Fatalf("expected device label in body")
+	}
+
+	revokeOther := httptest.NewRequest(http.MethodPost, "/account/sessions/sess-3/revoke", nil)
+	revokeOther.Header.Set("X-CSRF-Token", "csrf-1")
+	revokeOther.AddCookie(&http.Cookie{Name: "test_session", Value: "token-1"})
+	otherRec := httptest.NewRecorder()
+	r.ServeHTTP(otherRec, revokeOther)
+	if otherRec.Code != http.StatusNotFound {
+		t.Fatalf("expected 404 for another user's session, got %d", otherRec.Code)
+	}
+	if _, ok := store.sessions["token-3"]; !ok {
+		t.Fatalf("expected another user's session to remain")
+	}
+
+	revoke := httptest.NewRequest(http.MethodPost, "/account/sessions/sess-2/revoke", nil)
+	revoke.Header.Set("X-CSRF-Token", "csrf-1")
+	revoke.AddCookie(&http.Cookie{Name: "test_session", Value: "token-1"})
+	revokeRec := httptest.NewRecorder()
+	r.ServeHTTP(revokeRec, revoke)
+	if revokeRec.Code != http.StatusSeeOther {
+		t.Fatalf("expected 303, got %d", revokeRec.Code)
+	}
+	if _, ok := store.sessions["token-2"]; ok {
+		t.Fatalf("expected session sess-2 to be revoked")
+	}
+
+	revokeCurrent := httptest.NewRequest(http.MethodPost, "/account/sessions/sess-1/revoke", nil)
+	revokeCurrent.Header.Set("X-CSRF-Token", "csrf-1")
+	revokeCurrent.AddCookie(&http.Cookie{Name: "test_session", Value: "token-1"})
+	currentRec := httptest.NewRecorder()
+	r.ServeHTTP(currentRec, revokeCurrent)
+	if currentRec.Header().Get("Location") != "/auth/login" {
+		t.Fatalf("expected redirect to /auth/login, got %q", currentRec.Header().Get("Location"))
+	}
+}
+
+// Testa que o login bloqueado exibe a mensagem de espera.
+func TestRouterLoginPostLockout(t *testing.T) {
+	auth := &fakeAuthService{err: &ports.LockoutError{Until: time.Now().Add(3 * time.Minute)}}
+	store := &fakeSessionStore{}
+	h := handlers.New(handlers.Services{Auth: auth}, store, handlers.SessionConfig{CookieName: "test_session"})
+	r := New(h, store, httpmw.SessionPolicy{CookieName: "test_session"}, httpmw.NotifyConfig{}, nil)
+
+	values := url.Values{
+		"email":    {"user@example.com"},
+		"password": {"secret"},
+	}
+	req := httptest.NewRequest(http.MethodPost, "/auth/login", strings.NewReader(values.Encode()))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", rec.Code)
+	}
+	if !strings.Contains(rec.Body.String(), "Tente novamente em 3 minutos") {
+		t.Fatalf("expected lockout message, got %q", rec.Body.String())
+	}
+	if rec.Header().Get("Retry-After") == "" {
+		t.Fatal("expected Retry-After header")
+	}
+	if store.lastCreated.UserID != "" {
+		t.Fatalf("expected no session, got %#v", store.lastCreated)
+	}
+}
+
+type fakePasswordService struct {
+	changeErr  error
+	gotUserID  string
+	gotCurrent string
+	gotNext    string
+	gotToken   string
+	gotEmail   string
+}
+
+func (f *fakePasswordService) ChangePassword(ctx context.Context, userID, current, next string) error {
+	f.gotUserID = userID
+	f.gotCurrent = current
+	f.gotNext = next
+	return f.changeErr
+}
+
+func (f *fakePasswordService) RequestReset(ctx context.Context, email string) error {
+	f.gotEmail = email
+	return nil
+}
+
+func (f *fakePasswordService) ResetPassword(ctx context.Context, token, password string) error {
+	f.gotToken = token
+	f.gotNext = password
+	return nil
+}
+
+// Testa que sessao com troca obrigatoria so acessa a tela de senha e e liberada apos a troca.
+func TestRouterForcedPasswordChange(t *testing.T) {
+	store := &fakeSessionStore{
+		createToken: "token-2",
+		sessions: map[string]ports.Session{
+			"token-1": {UserID: "user-1", Role: domain.RoleOperator, MustChangePassword: true, CSRFToken: "csrf-1"},
+		},
+	}
+	passwords := &fakePasswordService{}
+	h := handlers.New(handlers.Services{Passwords: passwords}, store, handlers.SessionConfig{CookieName: "test_session"})
+	r := New(h, store, httpmw.SessionPolicy{CookieName: "test_session"}, httpmw.NotifyConfig{}, nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/students/", nil)
+	req.AddCookie(&http.Cookie{Name: "test_session", Value: "token-1"})
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, req)
+	if rec.Header().Get("Location") != "/account/password" {
+		t.Fatalf("expected redirect to /account/password, got %d %q", rec.Code, rec.Header().Get("Location"))
+	}
+
+	page := httptest.NewRequest(http.MethodGet, "/account/password", nil)
+	page.AddCookie(&http.Cookie{Name: "test_session", Value: "token-1"})
+	pageRec := httptest.NewRecorder()
+	r.ServeHTTP(pageRec, page)
+	if pageRec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", pageRec.Code)
+	}
+
+	values := url.Values{
+		"current_password": {"temporaria"},
+		"new_password":     {"nova-senha-1"},
+		"confirm_password": {"nova-senha-1"},
+		"csrf_token":       {"csrf-1"},
+	}
+	post := httptest.NewRequest(http.MethodPost, "/account/password", strings.NewReader(values.Encode()))
+	post.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	post.AddCookie(&http.Cookie{Name: "test_session", Value: "token-1"})
+	postRec := httptest.NewRecorder()
+	r.ServeHTTP(postRec, post)
+	if postRec.Header().Get("Location") != "/" {
+		t.Fatalf("expected redirect to /, got %d %q", postRec.Code, postRec.Header().Get("Location"))
+	}
+	if passwords.gotUserID != "user-1" || passwords.gotNext != "nova-senha-1" {
+		t.Fatalf("unexpected change call: %#v", passwords)
+	}
+	if _, ok := store.sessions["token-1"]; ok {
+		t.Fatal("expected old session to be removed")
+	}
+	rotated, ok := store.sessions["token-2"]
+	if !ok || rotated.MustChangePassword {
+		t.Fatalf("expected rotated session without forced change, got %#v", rotated)
+	}
+	if rotated.CSRFToken == "" || rotated.CSRFToken == "csrf-1" {
+		t.Fatalf("expected rotated session with a new csrf token, got %q", rotated.CSRFToken)
+	}
+}
+
+// Testa que a troca de senha com confirmacao divergente nao chama o servico.
+func TestRouterPasswordChangeMismatch(t *testing.T) {
+	store := &fakeSessionStore{
+		sessions: map[string]ports.Session{
+			"token-1": {UserID: "user-1", Role: domain.RoleOperator, CSRFToken: "csrf-1"},
+		},
+	}
+	passwords := &fakePasswordService{}
+	h := handlers.New(handlers.Services{Passwords: passwords}, store, handlers.SessionConfig{CookieName: "test_session"})
+	r := New(h, store, httpmw.SessionPolicy{CookieName: "test_session"}, httpmw.NotifyConfig{}, nil)
+
+	values := url.Values{
+		"current_password": {"atual-senha"},
+		"new_password":     {"nova-senha-1"},
+		"confirm_password": {"outra-senha-1"},
+		"csrf_token":       {"csrf-1"},
+	}
+	req := httptest.NewRequest(http.MethodPost, "/account/password", strings.NewReader(values.Encode()))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.AddCookie(&http.Cookie{Name: "test_session", Value: "token-1"})
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, req)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", rec.Code)
+	}
+	if passwords.gotUserID != "" {
+		t.Fatalf("expected service not to be called, got %#v", passwords)
+	}
+}
+
+// Testa as rotas publicas de recuperacao de senha.
+func TestRouterPasswordReset(t *testing.T) {
+	store := &fakeSessionStore{}
+	passwords := &fakePasswordService{}
+	h := handlers.New(handlers.Services{Passwords: passwords}, store, handlers.SessionConfig{CookieName: "test_session"})
+	r := New(h, store, httpmw.SessionPolicy{CookieName: "test_session"}, httpmw.NotifyConfig{}, nil)
+
+	forgot := url.Values{"email": {"user@example.com"}}
+	req := httptest.NewRequest(http.MethodPost, "/auth/forgot", strings.NewReader(forgot.Encode()))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, req)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", rec.Code)
+	}
+	if passwords.gotEmail != "user@example.com" {
+		t.Fatalf("expected email to be forwarded, got %q", passwords.gotEmail)
+	}
+
+	reset := url.Values{
+		"new_password":     {"nova-senha-1"},
+		"confirm_password": {"nova-senha-1"},
+		"csrf_token":       {"csrf-1"},
+		"token":            {"abc123"},
+	}
+	resetReq := httptest.NewRequest(http.MethodPost, "/auth/reset", strings.NewReader(reset.Encode()))
+	resetReq.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	resetRec := httptest.NewRecorder()
+	r.ServeHTTP(resetRec, resetReq)
+	if resetRec.Header().Get("Location") != "/auth/login?reset=1" {
+		t.Fatalf("expected redirect to login, got %d %q", resetRec.Code, resetRec.Header().Get("Location"))
+	}
+	if passwords.gotToken != "abc123" {
+		t.Fatalf("expected token abc123, got %q", passwords.gotToken)
+	}
+}
+
+type fakeMFAService struct {
+	challenges map[string]ports.LoginChallenge
+	validCode  string
+	gotCode    string
+}
+
+func (f *fakeMFAService) StartLogin(ctx context.Context, user domain.User) (string, error) {
+	if f.challenges == nil {
+		f.challenges = make(map[string]ports.LoginChallenge)
+	}
+	f.challenges["challenge-1"] = ports.LoginChallenge{UserID: user.ID, Name: user.Name, Role: user.Role}
+	return "challenge-1", nil
+}
+
+func (f *fakeMFAService) LoginChallenge(ctx context.Context, token string) (ports.LoginChallenge, error) {
+	challenge, ok := f.challenges[token]
+	if !ok {
+		return ports.LoginChallenge{}, ports.ErrNotFound
+	}
+	return challenge, nil
+}
+
+func (f *fakeMFAService) CompleteLogin(ctx context.Context, token, code string) (ports.LoginChallenge, []string, error) {
+	f.gotCode = code
+	challenge, ok := f.challenges[token]
+	if !ok {
+		return ports.LoginChallenge{}, nil, ports.ErrNotFound
+	}
+	if code != f.validCode {
+		return ports.LoginChallenge{}, nil, ports.ErrUnauthorized
+	}
+	delete(f.challenges, token)
+	return challenge, nil, nil
+}
+
+func (f *fakeMFAService) Status(ctx context.Context, userID string, role domain.UserRole) (ports.MFAStatus, error) {
+	return ports.MFAStatus{}, nil
+}
+
+func (f *fakeMFAService) BeginEnrollment(ctx context.Context, userID string) (ports.MFAEnrollment, error) {
+	return ports.MFAEnrollment{}, nil
+}
+
+func (f *fakeMFAService) ConfirmEnrollment(ctx context.Context, userID, code string) ([]string, error) {
+	return nil, nil
+}
+
+func (f *fakeMFAService) Disable(ctx context.Context, userID, code string) error {
+	return nil
+}
+
+func (f *fakeMFAService) RegenerateRecoveryCodes(ctx context.Context, userID, code string) ([]string, error) {
+	return nil, nil
+}
+
+// Testa que o login com segundo fator so cria a sessao apos o codigo valido.
+func TestRouterLoginPostMFA(t *testing.T) {
+	auth := &fakeAuthService{
+		user: domain.User{ID: "user-1", Name: "User", Role: domain.RoleAdmin, Active: true},
+	}
+	mfa := &fakeMFAService{validCode: "123456"}
+	store := &fakeSessionStore{}
+	h := handlers.New(handlers.Services{Auth: auth, MFA: mfa}, store, handlers.SessionConfig{CookieName: "test_session"})
+	r := New(h, store, httpmw.SessionPolicy{CookieName: "test_session"}, httpmw.NotifyConfig{}, nil)
+
+	values := url.Values{
+		"email":    {"user@example.com"},
+		"password": {"secret"},
+	}
+	req := httptest.NewRequest(http.MethodPost, "/auth/login", strings.NewReader(values.Encode()))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, req)
+
+	if rec.Header().Get("Location") != "/auth/mfa" {
+		t.Fatalf("expected redirect to /auth/mfa, got %d %q", rec.Code, rec.Header().Get("Location"))
+	}
+	if !strings.Contains(rec.Header().Get("Set-Cookie"), "test_session_mfa=challenge-1") {
+		t.Fatalf("expected challenge cookie, got %q", rec.Header().Get("Set-Cookie"))
+	}
+	if store.lastCreated.UserID != "" {
+		t.Fatalf("expected no session before second factor, got %#v", store.lastCreated)
+	}
+
+	post := func(code string) *httptest.ResponseRecorder {
+		form := url.Values{"code": {code}}
+		req := httptest.NewRequest(http.MethodPost, "/auth/mfa", strings.NewReader(form.Encode()))
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		req.AddCookie(&http.Cookie{Name: "test_session_mfa", Value: "challenge-1"})
+		rec := httptest.NewRecorder()
+		r.ServeHTTP(rec, req)
+		return rec
+	}
+
+	wrong := post("000000")
+	if wrong.Code != http.StatusOK || !strings.Contains(wrong.Body.String(), "Codigo invalido") {
+		t.Fatalf("expected invalid code message, got %d", wrong.Code)
+	}
+	if store.lastCreated.UserID != "" {
+		t.Fatalf("expected no session after wrong code, got %#v", store.lastCreated)
+	}
+
+	ok := post("123456")
+	if ok.Header().Get("Location") != "/" {
+		t.Fatalf("expected redirect to /, got %d %q", ok.Code, ok.Header().Get("Location"))
+	}
+	if store.lastCreated.UserID != "user-1" || store.lastCreated.Role != domain.RoleAdmin {
+		t.Fatalf("expected session for user-1, got %#v", store.lastCreated)
+	}
+
+	expired := post("123456")
+	if expired.Header().Get("Location") != "/auth/login" {
+		t.Fatalf("expected redirect to login for used challenge, got %d %q", expired.Code, expired.Header().Get("Location"))
+	}
+}
+
+type fakeAuditService struct {
+	events     []domain.AuditEvent
+	access     []ports.AuditAccessSummary
+	lastFilter ports.AuditFilter
+	timeline   []string
+}
+
+func (f *fakeAuditService) Search(ctx context.Context, filter ports.AuditFilter) ([]domain.AuditEvent, error) {
+	f.lastFilter = filter
+	return f.events, nil
+}
+
+func (f *fakeAuditService) Count(ctx context.Context, filter ports.AuditFilter) (int, error) {
+	return len(f.events), nil
+}
+
+func (f *fakeAuditService) Timeline(ctx context.Context, entityType, entityID string) ([]domain.AuditEvent, error) {
+	f.timeline = append(f.timeline, entityType+":"+entityID)
+	return f.events, nil
+}
+
+func (f *fakeAuditService) StudentAccess(ctx context.Context, studentID string) ([]ports.AuditAccessSummary, error) {
+	return f.access, nil
+}
+
+type fakeStudentService struct {
+	photoViews []string
+}
+
+func (f *fakeStudentService) Count(ctx context.Context, filter ports.StudentFilter) (int, error) {
+	return 0, nil
+}
+
+func (f *fakeStudentService) Search(ctx context.Context, filter ports.StudentFilter) ([]domain.Student, error) {
+	return nil, nil
+}
+
+func (f *fakeStudentService) SearchPage(ctx context.Context, filter ports.StudentFilter, page ports.PageRequest) ([]domain.Student, error) {
+	return nil, nil
+}
+
+func (f *fakeStudentService) FindByID(ctx context.Context, id string) (domain.Student, error) {
+	return domain.Student{}, ports.ErrNotFound
+}
+
+func (f *fakeStudentService) FindName(ctx context.Context, id string) (string, error) {
+	return "", ports.ErrNotFound
+}
+
+func (f *fakeStudentService) RecordViews(ctx context.Context, source string, studentIDs ...string) {}
+
+func (f *fakeStudentService) RecordPhotoView(ctx context.Context, objectKey string) error {
+	f.photoViews = append(f.photoViews, objectKey+":"+auditctx.FromContext(ctx).Actor.ID)
+	return nil
+}
+
+func (f *fakeStudentService) Register(ctx context.Context, student domain.Student) (domain.Student, error) {
+	return student, nil
+}
+
+func (f *fakeStudentService) Update(ctx context.Context, student domain.Student) (domain.Student, error) {
+	return student, nil
+}
+
+func (f *fakeStudentService) SetStatus(ctx context.Context, studentID string, status domain.StudentStatus) (domain.Student, error) {
+	return domain.Student{}, nil
+}
+
+func (f *fakeStudentService) Deactivate(ctx context.Context, studentID string) (domain.Student, error) {
+	return domain.Student{}, nil
+}
+
+// Testa a consulta de auditoria restrita a administradores e o historico por entidade.
+func TestRouterAudit(t *testing.T) {
+	store := &fakeSessionStore{
+		sessions: map[string]ports.Session{
+			"token-admin":    {UserID: "user-1", Role: domain.RoleAdmin},
+			"token-operator": {UserID: "user-2", Role: domain.RoleOperator},
+		},
+	}
+	audit := &fakeAuditService{events: []domain.AuditEvent{
+		{ID: "evt-1", Action: "payment.reverse.success", EntityType: "payment", EntityID: "pay-1", CreatedAt: time.Now()},
+	}}
+	h := handlers.New(handlers.Services{Audit: audit, Authorization: &fakeAuthorizationService{}}, store, handlers.SessionConfig{CookieName: "test_session"})
+	r := New(h, store, httpmw.SessionPolicy{CookieName: "test_session"}, httpmw.NotifyConfig{}, nil)
+
+	get := func(path, token string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		req.AddCookie(&http.Cookie{Name: "test_session", Value: token})
+		rec := httptest.NewRecorder()
+		r.ServeHTTP(rec, req)
+		return rec
+	}
+
+	if rec := get("/audit", "token-operator"); rec.Code != http.StatusForbidden {
+		t.Fatalf("expected 403 for operator, got %d", rec.Code)
+	}
+	if rec := get("/payments/pay-1/history", "token-operator"); rec.Code != http.StatusForbidden {
+		t.Fatalf("expected 403 for operator history, got %d", rec.Code)
+	}
+
+	rec := get("/audit?entity_type=payment&action=payment.&from=2024-01-01&to=2024-01-31", "token-admin")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", rec.Code)
+	}
+	if !strings.Contains(rec.Body.String(), "payment.reverse.success") {
+		t.Fatalf("expected event in body")
+	}
+	filter := audit.lastFilter
+	if filter.EntityType != "payment" || filter.ActionPrefix != "payment." {
+		t.Fatalf("unexpected filter: %#v", filter)
+	}
+	if filter.From.Format("2006-01-02") != "2024-01-01" || filter.To.Format("2006-01-02") != "2024-02-01" {
+		t.Fatalf("expected inclusive date range, got %v - %v", filter.From, filter.To)
+	}
+
+	history := get("/payments/pay-1/history", "token-admin")
+	if history.Code != http.StatusOK || !strings.Contains(history.Body.String(), "payment.reverse.success") {
+		t.Fatalf("expected history timeline, got %d", history.Code)
+	}
+	if len(audit.timeline) != 1 || audit.timeline[0] != "payment:pay-1" {
+		t.Fatalf("unexpected timeline calls: %v", audit.timeline)
+	}
+}
+
+// Testa que o historico do aluno inclui o resumo de acessos aos dados.
+func TestRouterStudentHistoryShowsAccess(t *testing.T) {
+	store := &fakeSessionStore{
+		sessions: map[string]ports.Session{
+			"token-admin": {UserID: "user-1", Role: domain.RoleAdmin},
+		},
+	}
+	audit := &fakeAuditService{access: []ports.AuditAccessSummary{
+		{ActorID: "user-9", ActorRole: "operator", Views: 3, Sources: []string{"detail", "photo"}, FirstAt: time.Now(), LastAt: time.Now()},
+	}}
+	h := handlers.New(handlers.Services{Audit: audit, Authorization: &fakeAuthorizationService{}}, store, handlers.SessionConfig{CookieName: "test_session"})
+	r := New(h, store, httpmw.SessionPolicy{CookieName: "test_session"}, httpmw.NotifyConfig{}, nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/students/stu-1/history", nil)
+	req.AddCookie(&http.Cookie{Name: "test_session", Value: "token-admin"})
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", rec.Code)
+	}
+	body := rec.Body.String()
+	if !strings.Contains(body, "Acessos aos dados") || !strings.Contains(body, "user-9") || !strings.Contains(body, "detail, photo") {
+		t.Fatalf("expected access summary in body: %s", body)
+	}
+	if len(audit.timeline) != 1 || audit.timeline[0] != "student:stu-1" {
+		t.Fatalf("unexpected timeline calls: %v", audit.timeline)
+	}
+}
+
+// Testa que o acesso a fotos registra a visualizacao com o usuario da sessao.
+func TestRouterImagesRecordPhotoView(t *testing.T) {
+	store := &fakeSessionStore{
+		sessions: map[string]ports.Session{
+			"token-operator": {UserID: "user-2", Role: domain.RoleOperator},
+		},
+	}
+	students := &fakeStudentService{}
+	h := handlers.New(handlers.Services{Students: students}, store, handlers.SessionConfig{CookieName: "test_session"})
+	h.SetImageConfig(handlers.ImageConfig{
+		ImageService: &fakeImageService{handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusOK)
+		})},
+	})
+	r := New(h, store, httpmw.SessionPolicy{CookieName: "test_session"}, httpmw.NotifyConfig{}, nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/images/photo-1/list", nil)
+	req.AddCookie(&http.Cookie{Name: "test_session", Value: "token-operator"})
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", rec.Code)
+	}
+	if len(students.photoViews) != 1 || students.photoViews[0] != "photo-1:user-2" {
+		t.Fatalf("unexpected photo views: %v", students.photoViews)
+	}
+}
+
+type fakePlanService struct {
+	plans []domain.Plan
+}
+
+func (f *fakePlanService) ListActive(ctx context.Context) ([]domain.Plan, error) {
+	return f.plans, nil
+}
+
+// ListActivePage usa a ordem de criacao dos planos; os IDs crescem com ela.
+func (f *fakePlanService) ListActivePage(ctx context.Context, page ports.PageRequest) ([]domain.Plan, error) {
+	result := make([]domain.Plan, 0, page.Limit)
+	for _, plan := range f.plans {
+		if plan.ID <= page.After.ID || len(result) == page.Limit {
+			continue
+		}
+		result = append(result, plan)
+	}
+	return result, nil
+}
+
+func (f *fakePlanService) FindByID(ctx context.Context, id string) (domain.Plan, error) {
+	for _, plan := range f.plans {
+		if plan.ID == id {
+			return plan, nil
+		}
+	}
+	return domain.Plan{}, ports.ErrNotFound
+}
+
+func (f *fakePlanService) Create(ctx context.Context, plan domain.Plan) (domain.Plan, error) {
+	plan.ID = fmt.Sprintf("plan-%d", len(f.plans)+1)
+	f.plans = append(f.plans, plan)
+	return plan, nil
+}
+
+func (f *fakePlanService) Update(ctx context.Context, plan domain.Plan) (domain.Plan, error) {
+	return plan, nil
+}
+
+func (f *fakePlanService) Deactivate(ctx context.Context, planID string) (domain.Plan, error) {
+	return domain.Plan{}, nil
+}
+
+// Testa que a API responde erros em JSON em vez de redirecionar.
+func TestRouterAPIErrors(t *testing.T) {
+	store := &fakeSessionStore{
+		sessions: map[string]ports.Session{
+			"token-operator": {UserID: "user-2", Role: domain.RoleOperator, CSRFToken: "csrf-1"},
+		},
+	}
+	h := handlers.New(handlers.Services{Students: &fakeStudentService{}, Plans: &fakePlanService{}, Authorization: &fakeAuthorizationService{}}, store, handlers.SessionConfig{CookieName: "test_session"})
+	r := New(h, store, httpmw.SessionPolicy{CookieName: "test_session"}, httpmw.NotifyConfig{}, nil)
+
+	do := func(method, path, token, csrf, body string) (*httptest.ResponseRecorder, httpmw.APIError) {
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		if token != "" {
+			req.AddCookie(&http.Cookie{Name: "test_session", Value: token})
+		}
+		if csrf != "" {
+			req.Header.Set("X-CSRF-Token", csrf)
+		}
+		rec := httptest.NewRecorder()
+		r.ServeHTTP(rec, req)
+		var payload struct {
+			Error httpmw.APIError `json:"error"`
+		}
+		_ = json.Unmarshal(rec.Body.Bytes(), &payload)
+		return rec, payload.Error
+	}
+
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		token  string
+		csrf   string
+		body   string
+		status int
+		code   string
+	}{
+		{"no-session", http.MethodGet, "/api/v1/students/", "", "", "", http.StatusUnauthorized, "unauthorized"},
+		{"no-csrf", http.MethodPost, "/api/v1/students/", "token-operator", "", `{"full_name":"Ana"}`, http.StatusForbidden, "csrf_invalid"},
+		{"forbidden", http.MethodPost, "/api/v1/plans/", "token-operator", "csrf-1", `{"name":"Mensal","duration_days":30}`, http.StatusForbidden, "forbidden"},
+		{"not-found", http.MethodGet, "/api/v1/students/stu-1", "token-operator", "", "", http.StatusNotFound, "not_found"},
+		{"unknown-route", http.MethodGet, "/api/v1/unknown", "token-operator", "", "", http.StatusNotFound, "not_found"},
+		{"bad-body", http.MethodPost, "/api/v1/students/", "token-operator", "csrf-1", `{"full_name":""}`, http.StatusBadRequest, "invalid_request"},
+	}
+
+	for _, tt := range tests {
+		rec, apiErr := do(tt.method, tt.path, tt.token, tt.csrf, tt.body)
+		if rec.Code != tt.status {
+			t.Fatalf("%s: expected %d, got %d", tt.name, tt.status, rec.Code)
+		}
+		if apiErr.Code != tt.code {
+			t.Fatalf("%s: expected code %q, got %q (%s)", tt.name, tt.code, apiErr.Code, rec.Body.String())
+		}
+		if !strings.HasPrefix(rec.Header().Get("Content-Type"), "application/json") {
+			t.Fatalf("%s: expected JSON content type, got %q", tt.name, rec.Header().Get("Content-Type"))
+		}
+	}
+}
+
+// Testa a criacao de planos pela API e a paginacao por cursor da listagem.
+func TestRouterAPIPlans(t *testing.T) {
+	store := &fakeSessionStore{
+		sessions: map[string]ports.Session{
+			"token-admin": {UserID: "user-1", Role: domain.RoleAdmin, CSRFToken: "csrf-1"},
+		},
+	}
+	plans := &fakePlanService{}
+	h := handlers.New(handlers.Services{Plans: plans, Authorization: &fakeAuthorizationService{}}, store, handlers.SessionConfig{CookieName: "test_session"})
+	r := New(h, store, httpmw.SessionPolicy{CookieName: "test_session"}, httpmw.NotifyConfig{}, nil)
+
+	for _, name := range []string{"Mensal", "Trimestral", "Anual"} {
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/plans/", strings.NewReader(`{"name":"`+name+`","duration_days":30,"price_cents":9900}`))
+		req.AddCookie(&http.Cookie{Name: "test_session", Value: "token-admin"})
+		req.Header.Set("X-CSRF-Token", "csrf-1")
+		rec := httptest.NewRecorder()
+		r.ServeHTTP(rec, req)
+		if rec.Code != http.StatusCreated {
+			t.Fatalf("expected 201, got %d: %s", rec.Code, rec.Body.String())
+		}
+	}
+
+	type planPage struct {
+		Data []struct {
+			ID   string `json:"id"`
+			Name string `json:"name"`
+		} `json:"data"`
+		NextCursor string `json:"next_cursor"`
+	}
+	list := func(query string) planPage {
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/plans/?"+query, nil)
+		req.AddCookie(&http.Cookie{Name: "test_session", Value: "token-admin"})
+		rec := httptest.NewRecorder()
+		r.ServeHTTP(rec, req)
+		if rec.Code != http.StatusOK {
+			t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
+		}
+		var page planPage
+		if err := json.Unmarshal(rec.Body.Bytes(), &page); err != nil {
+			t.Fatalf("decode page: %v", err)
+		}
+		return page
+	}
+
+	first := list("limit=2")
+	if len(first.Data) != 2 || first.Data[0].Name != "Mensal" || first.NextCursor == "" {
+		t.Fatalf("unexpected first page: %#v", first)
+	}
+	second := list("limit=2&cursor=" + url.QueryEscape(first.NextCursor))
+	if len(second.Data) != 1 || second.Data[0].Name != "Anual" || second.NextCursor != "" {
+		t.Fatalf("unexpected second page: %#v", second)
+	}
+}
+
+type fakeAPITokenService struct {
+	principals map[string]ports.APIPrincipal
+}
+
+func (f *fakeAPITokenService) List(ctx context.Context) ([]domain.APIToken, error) {
+	return nil, nil
+}
+
+func (f *fakeAPITokenService) Issue(ctx context.Context, token domain.APIToken) (domain.APIToken, string, error) {
+	return token, "", nil
+}
+
+func (f *fakeAPITokenService) Revoke(ctx context.Context, tokenID string) error {
+	return nil
+}
+
+func (f *fakeAPITokenService) Authenticate(ctx context.Context, token string) (ports.APIPrincipal, error) {
+	principal, ok := f.principals[token]
+	if !ok {
+		return ports.APIPrincipal{}, ports.ErrUnauthorized
+	}
+	return principal, nil
+}
+
+// Testa o acesso a API com token Bearer: escopo, CSRF e token invalido.
+func TestRouterAPITokens(t *testing.T) {
+	tokens := &fakeAPITokenService{
+		principals: map[string]ports.APIPrincipal{
+			"jaiu_read":  {TokenID: "tok-1", UserID: "user-1", Role: domain.RoleAdmin, Scopes: []domain.APIScope{"plans:read"}},
+			"jaiu_write": {TokenID: "tok-2", UserID: "user-1", Role: domain.RoleAdmin, Scopes: []domain.APIScope{"plans:write"}},
+		},
+	}
+	store := &fakeSessionStore{sessions: map[string]ports.Session{}}
+	h := handlers.New(handlers.Services{Plans: &fakePlanService{}, APITokens: tokens, Authorization: &fakeAuthorizationService{}}, store, handlers.SessionConfig{CookieName: "test_session"})
+	r := New(h, store, httpmw.SessionPolicy{CookieName: "test_session"}, httpmw.NotifyConfig{}, nil)
+
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		token  string
+		body   string
+		status int
+		code   string
+	}{
+		{"read", http.MethodGet, "/api/v1/plans/", "jaiu_read", "", http.StatusOK, ""},
+		{"read-cannot-write", http.MethodPost, "/api/v1/plans/", "jaiu_read", `{"name":"Mensal","duration_days":30}`, http.StatusForbidden, "insufficient_scope"},
+		{"write-without-csrf", http.MethodPost, "/api/v1/plans/", "jaiu_write", `{"name":"Mensal","duration_days":30}`, http.StatusCreated, ""},
+		{"other-resource", http.MethodGet, "/api/v1/students/", "jaiu_write", "", http.StatusForbidden, "insufficient_scope"},
+		{"invalid", http.MethodGet, "/api/v1/plans/", "jaiu_unknown", "", http.StatusUnauthorized, "invalid_token"},
+	}
+
+	for _, tt := range tests {
+		req := httptest.NewRequest(tt.method, tt.path, strings.NewReader(tt.body))
+		req.Header.Set("Authorization", "Bearer "+tt.token)
+		rec := httptest.NewRecorder()
+		r.ServeHTTP(rec, req)
+		if rec.Code != tt.status {
+			t.Fatalf("%s: expected %d, got %d: %s", tt.name, tt.status, rec.Code, rec.Body.String())
+		}
+		if tt.code == "" {
+			continue
+		}
+		var payload struct {
+			Error httpmw.APIError `json:"error"`
+		}
+		_ = json.Unmarshal(rec.Body.Bytes(), &payload)
+		if payload.Error.Code != tt.code {
+			t.Fatalf("%s: expected code %q, got %q", tt.name, tt.code, payload.Error.Code)
+		}
+	}
+}
+
+// Testa que apenas administradores acessam a tela de tokens de API.
+func TestRouterAPITokensPageRequiresAdmin(t *testing.T) {
+	store := &fakeSessionStore{
+		sessions: map[string]ports.Session{
+			"token-admin":    {UserID: "user-1", Role: domain.RoleAdmin},
+			"token-operator": {UserID: "user-2", Role: domain.RoleOperator},
+		},
+	}
+	h := handlers.New(handlers.Services{APITokens: &fakeAPITokenService{}, Authorization: &fakeAuthorizationService{}}, store, handlers.SessionConfig{CookieName: "test_session"})
+	r := New(h, store, httpmw.SessionPolicy{CookieName: "test_session"}, httpmw.NotifyConfig{}, nil)
+
+	for token, status := range map[string]int{"token-admin": http.StatusOK, "token-operator": http.StatusForbidden} {
+		req := httptest.NewRequest(http.MethodGet, "/api-tokens/", nil)
+		req.AddCookie(&http.Cookie{Name: "test_session", Value: token})
+		rec := httptest.NewRecorder()
+		r.ServeHTTP(rec, req)
+		if rec.Code != status {
+			t.Fatalf("%s: expected %d, got %d", token, status, rec.Code)
+		}
+	}
+}
+
+// Testa que toda rota de /api/v1 esta documentada no OpenAPI e vice-versa.
+func TestRouterAPIOpenAPICoversRoutes(t *testing.T) {
+	store := &fakeSessionStore{sessions: map[string]ports.Session{}}
+	h := handlers.New(handlers.Services{}, store, handlers.SessionConfig{CookieName: "test_session"})
+	r := New(h, store, httpmw.SessionPolicy{CookieName: "test_session"}, httpmw.NotifyConfig{}, nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/openapi.json", nil)
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, req)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", rec.Code)
+	}
+	var spec struct {
+		OpenAPI string                                `json:"openapi"`
+		Paths   map[string]map[string]json.RawMessage `json:"paths"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &spec); err != nil {
+		t.Fatalf("decode spec: %v", err)
+	}
+	if !strings.HasPrefix(spec.OpenAPI, "3.") {
+		t.Fatalf("expected OpenAPI 3, got %q", spec.OpenAPI)
+	}
+
+	documented := map[string]bool{}
+	for path, operations := range spec.Paths {
+		for method := range operations {
+			documented[strings.ToUpper(method)+" "+path] = true
+		}
+	}
+
+	routed := map[string]bool{}
+	err := chi.Walk(r.(chi.Routes), func(method, route string, handler http.Handler, middlewares ...func(http.Handler) http.Handler) error {
+		path, ok := strings.CutPrefix(route, "/api/v1")
+		if !ok {
+			return nil
+		}
+		if trimmed := strings.TrimSuffix(path, "/"); trimmed != "" {
+			path = trimmed
+		}
+		routed[method+" "+path] = true
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("walk routes: %v", err)
+	}
+
+	for route := range routed {
+		if !documented[route] {
+			t.Fatalf("route %s is not documented in openapi.json", route)
+		}
+	}
+	for route := range documented {
+		if !routed[route] {
+			t.Fatalf("openapi.json documents %s, which is not routed", route)
+		}
+	}
+}
@@ -0,0 +1,13 @@
+package ports
+
+import (
+	"context"
+	"time"
+)
+
+// AccessDedupStore evita que o mesmo acesso seja registrado varias vezes.
+// Claim devolve verdadeiro somente na primeira chamada com a chave dentro da
+// janela.
+type AccessDedupStore interface {
+	Claim(ctx context.Context, key string, window time.Duration) (bool, error)
+}
@@ -0,0 +1,18 @@
+package ports
+
+import "github.com/PabloPavan/jaiu/internal/domain"
+
+// APIPrincipal identifica quem faz uma requisicao autenticada por token de
+// API. O papel e o do usuario dono do token; os escopos limitam os recursos.
+type APIPrincipal struct {
+	TokenID string
+	UserID  string
+	Name    string
+	Email   string
+	Role    domain.UserRole
+	Scopes  []domain.APIScope
+}
+
+func (p APIPrincipal) Allows(resource domain.APIResource, access domain.APIAccess) bool {
+	return domain.APIToken{Scopes: p.Scopes}.Allows(resource, access)
+}
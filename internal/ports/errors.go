@@ -1,7 +1,45 @@
 package ports
 
-import "errors"
+import (
+	"errors"
+	"time"
+)
 
 var ErrNotFound = errors.New("not found")
 var ErrUnauthorized = errors.New("unauthorized")
 var ErrConflict = errors.New("conflict")
+var ErrTooManyAttempts = errors.New("too many attempts")
+
+// ErrSerialization indica que a transacao conflitou com outra concorrente e
+// pode ser repetida; os TxRunners repetem automaticamente erros que o envolvem.
+var ErrSerialization = errors.New("serialization failure")
+
+// LockoutError indica um bloqueio temporario e quando ele termina.
+type LockoutError struct {
+	Until time.Time
+}
+
+func (e *LockoutError) Error() string {
+	return "too many attempts until " + e.Until.Format(time.RFC3339)
+}
+
+func (e *LockoutError) Is(target error) bool {
+	return target == ErrTooManyAttempts
+}
+
+// ErrValidation marca os erros de entrada recusada pelos servicos; a mensagem
+// do ValidationError e segura para mostrar ao usuario.
+var ErrValidation = errors.New("validation failed")
+
+// ValidationError descreve por que os dados recebidos foram recusados.
+type ValidationError struct {
+	Message string
+}
+
+func (e *ValidationError) Error() string {
+	return e.Message
+}
+
+func (e *ValidationError) Is(target error) bool {
+	return target == ErrValidation
+}
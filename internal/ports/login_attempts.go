@@ -0,0 +1,19 @@
+package ports
+
+import (
+	"context"
+	"time"
+)
+
+type LoginAttempts struct {
+	Failures    int
+	Lockouts    int
+	LockedUntil time.Time
+}
+
+type LoginAttemptStore interface {
+	Get(ctx context.Context, key string) (LoginAttempts, error)
+	RegisterFailure(ctx context.Context, key string, window time.Duration) (int, error)
+	Lock(ctx context.Context, key string, until time.Time, memory time.Duration) error
+	Reset(ctx context.Context, key string) error
+}
@@ -0,0 +1,39 @@
+package ports
+
+import (
+	"context"
+	"time"
+
+	"github.com/PabloPavan/jaiu/internal/domain"
+)
+
+// LoginChallenge representa um login com senha valida aguardando o segundo
+// fator. A sessao so e criada depois que o desafio e concluido.
+type LoginChallenge struct {
+	UserID             string
+	Email              string
+	Name               string
+	Role               domain.UserRole
+	MustChangePassword bool
+	Enroll             bool
+	Failures           int
+	ExpiresAt          time.Time
+}
+
+type LoginChallengeStore interface {
+	Create(ctx context.Context, challenge LoginChallenge) (string, error)
+	Get(ctx context.Context, token string) (LoginChallenge, error)
+	RegisterFailure(ctx context.Context, token string) (int, error)
+	Delete(ctx context.Context, token string) error
+}
+
+type MFAStatus struct {
+	Enabled           bool
+	Required          bool
+	RecoveryCodesLeft int
+}
+
+type MFAEnrollment struct {
+	Secret          string
+	ProvisioningURI string
+}
@@ -0,0 +1,13 @@
+package ports
+
+import "context"
+
+type Notification struct {
+	To      string
+	Subject string
+	Body    string
+}
+
+type Notifier interface {
+	Notify(ctx context.Context, notification Notification) error
+}
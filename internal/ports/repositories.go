@@ -2,6 +2,7 @@ package ports
 
 import (
 	"context"
+	"io"
 	"time"
 
 	"github.com/PabloPavan/jaiu/internal/domain"
@@ -11,7 +12,9 @@ type StudentRepository interface {
 	Create(ctx context.Context, student domain.Student) (domain.Student, error)
 	Update(ctx context.Context, student domain.Student) (domain.Student, error)
 	FindByID(ctx context.Context, id string) (domain.Student, error)
+	FindIDByPhotoObjectKey(ctx context.Context, objectKey string) (string, error)
 	Search(ctx context.Context, filter StudentFilter) ([]domain.Student, error)
+	SearchPage(ctx context.Context, filter StudentFilter, page PageRequest) ([]domain.Student, error)
 	Count(ctx context.Context, filter StudentFilter) (int, error)
 }
 
@@ -22,11 +25,25 @@ type StudentFilter struct {
 	Offset   int
 }
 
+// PageRequest pede ate Limit itens ordenados por CreatedAt e ID depois de
+// After; o After zero comeca do inicio.
+type PageRequest struct {
+	After PageCursor
+	Limit int
+}
+
+// PageCursor marca o ultimo item devolvido em uma pagina.
+type PageCursor struct {
+	CreatedAt time.Time
+	ID        string
+}
+
 type PlanRepository interface {
 	Create(ctx context.Context, plan domain.Plan) (domain.Plan, error)
 	Update(ctx context.Context, plan domain.Plan) (domain.Plan, error)
 	FindByID(ctx context.Context, id string) (domain.Plan, error)
 	ListActive(ctx context.Context) ([]domain.Plan, error)
+	ListActivePage(ctx context.Context, page PageRequest) ([]domain.Plan, error)
 }
 
 type SubscriptionRepository interface {
@@ -34,9 +51,26 @@ type SubscriptionRepository interface {
 	Update(ctx context.Context, subscription domain.Subscription) (domain.Subscription, error)
 	FindByID(ctx context.Context, id string) (domain.Subscription, error)
 	ListByStudent(ctx context.Context, studentID string) ([]domain.Subscription, error)
+	ListByStudentPage(ctx context.Context, studentID string, page PageRequest) ([]domain.Subscription, error)
 	ListByPlan(ctx context.Context, planID string) ([]domain.Subscription, error)
 	ListDueBetween(ctx context.Context, start, end time.Time) ([]domain.Subscription, error)
+	ListDueBetweenPage(ctx context.Context, start, end time.Time, page PageRequest) ([]domain.Subscription, error)
 	ListAutoRenew(ctx context.Context) ([]domain.Subscription, error)
+	ListWithOpenPeriods(ctx context.Context) ([]domain.Subscription, error)
+}
+
+type CouponRepository interface {
+	Create(ctx context.Context, coupon domain.Coupon) (domain.Coupon, error)
+	SetActive(ctx context.Context, id string, active bool) (domain.Coupon, error)
+	FindByID(ctx context.Context, id string) (domain.Coupon, error)
+	FindByCode(ctx context.Context, code string) (domain.Coupon, error)
+	List(ctx context.Context) ([]domain.Coupon, error)
+}
+
+type SubscriptionFreezeRepository interface {
+	Create(ctx context.Context, freeze domain.SubscriptionFreeze) (domain.SubscriptionFreeze, error)
+	ListBySubscription(ctx context.Context, subscriptionID string) ([]domain.SubscriptionFreeze, error)
+	ListBySubscriptionPage(ctx context.Context, subscriptionID string, page PageRequest) ([]domain.SubscriptionFreeze, error)
 }
 
 type PaymentRepository interface {
@@ -45,20 +79,31 @@ type PaymentRepository interface {
 	FindByID(ctx context.Context, id string) (domain.Payment, error)
 	FindByIdempotencyKey(ctx context.Context, key string) (domain.Payment, error)
 	ListBySubscription(ctx context.Context, subscriptionID string) ([]domain.Payment, error)
+	ListBySubscriptionPage(ctx context.Context, subscriptionID string, page PageRequest) ([]domain.Payment, error)
 	ListByPeriod(ctx context.Context, start, end time.Time) ([]domain.Payment, error)
+	ListByPeriodPage(ctx context.Context, start, end time.Time, page PageRequest) ([]domain.Payment, error)
 }
 
 type BillingPeriodRepository interface {
 	Create(ctx context.Context, period domain.BillingPeriod) (domain.BillingPeriod, error)
 	Update(ctx context.Context, period domain.BillingPeriod) (domain.BillingPeriod, error)
 	ListBySubscription(ctx context.Context, subscriptionID string) ([]domain.BillingPeriod, error)
+	ListBySubscriptionPage(ctx context.Context, subscriptionID string, page PageRequest) ([]domain.BillingPeriod, error)
 	ListOpenBySubscription(ctx context.Context, subscriptionID string) ([]domain.BillingPeriod, error)
 	MarkOverdue(ctx context.Context, subscriptionID string, now time.Time) error
 }
 
 type PaymentAllocationRepository interface {
 	Create(ctx context.Context, allocation domain.PaymentAllocation) error
+	Update(ctx context.Context, allocation domain.PaymentAllocation) error
 	ListByPayment(ctx context.Context, paymentID string) ([]domain.PaymentAllocation, error)
+	ListByBillingPeriod(ctx context.Context, periodID string) ([]domain.PaymentAllocation, error)
+	DeleteByPayment(ctx context.Context, paymentID string) error
+}
+
+type PaymentCreditTransferRepository interface {
+	Create(ctx context.Context, transfer domain.PaymentCreditTransfer) error
+	ListByPayment(ctx context.Context, paymentID string) ([]domain.PaymentCreditTransfer, error)
 	DeleteByPayment(ctx context.Context, paymentID string) error
 }
 
@@ -71,6 +116,28 @@ type SubscriptionBalanceRepository interface {
 type UserRepository interface {
 	Create(ctx context.Context, user domain.User) (domain.User, error)
 	FindByEmail(ctx context.Context, email string) (domain.User, error)
+	FindByID(ctx context.Context, id string) (domain.User, error)
+	List(ctx context.Context) ([]domain.User, error)
+	Update(ctx context.Context, user domain.User) (domain.User, error)
+	SetActive(ctx context.Context, id string, active bool) (domain.User, error)
+	UpdatePassword(ctx context.Context, id, passwordHash string, mustChange bool) error
+}
+
+type PasswordResetRepository interface {
+	Create(ctx context.Context, token domain.PasswordResetToken) (domain.PasswordResetToken, error)
+	Consume(ctx context.Context, tokenHash string, now time.Time) (domain.PasswordResetToken, error)
+	InvalidateByUser(ctx context.Context, userID string, now time.Time) error
+}
+
+type MFARepository interface {
+	FindByUser(ctx context.Context, userID string) (domain.UserMFA, error)
+	SaveSecret(ctx context.Context, userID, secret string) (domain.UserMFA, error)
+	Enable(ctx context.Context, userID string, enabledAt time.Time, step int64) (domain.UserMFA, error)
+	AdvanceStep(ctx context.Context, userID string, step int64) (bool, error)
+	Delete(ctx context.Context, userID string) error
+	ReplaceRecoveryCodes(ctx context.Context, userID string, codeHashes []string) error
+	ConsumeRecoveryCode(ctx context.Context, userID, codeHash string, usedAt time.Time) (bool, error)
+	CountRecoveryCodes(ctx context.Context, userID string) (int, error)
 }
 
 type ReportRepository interface {
@@ -80,14 +147,85 @@ type ReportRepository interface {
 	UpcomingDue(ctx context.Context, start, end time.Time) ([]DueSubscription, error)
 }
 
+type DashboardRepository interface {
+	CountActiveStudents(ctx context.Context) (int64, error)
+	CountOverdueSubscriptions(ctx context.Context) (int64, error)
+	OutstandingDue(ctx context.Context, start, end time.Time) (int64, error)
+}
+
 type AuditRepository interface {
 	Record(ctx context.Context, event domain.AuditEvent) error
 }
 
+type AuditReader interface {
+	Search(ctx context.Context, filter AuditFilter) ([]domain.AuditEvent, error)
+	Count(ctx context.Context, filter AuditFilter) (int, error)
+	AccessSummary(ctx context.Context, action, entityType, entityID string) ([]AuditAccessSummary, error)
+}
+
+// AuditAccessSummary agrupa os acessos de um usuario a uma entidade.
+type AuditAccessSummary struct {
+	ActorID   string
+	ActorRole string
+	Views     int
+	Sources   []string
+	FirstAt   time.Time
+	LastAt    time.Time
+}
+
+// AuditArchiveRepository percorre e remove eventos por intervalo de criacao,
+// usado pela retencao e pela exportacao. ListRange ordena por CreatedAt e ID e
+// devolve os eventos depois do cursor after. DeleteRange remove os eventos
+// anteriores ao encadeamento criados em [from, to) e um prefixo continuo da
+// cadeia que termina em maxSeq ou antes do primeiro evento criado a partir de
+// to, guardando o hash do ultimo removido como ancora da verificacao.
+type AuditArchiveRepository interface {
+	OldestCreatedAt(ctx context.Context) (time.Time, bool, error)
+	ListRange(ctx context.Context, from, to time.Time, after AuditCursor, limit int) ([]domain.AuditEvent, error)
+	DeleteRange(ctx context.Context, from, to time.Time, maxSeq int64) (int64, error)
+}
+
+// AuditCursor marca o ultimo evento lido em ListRange; o valor zero comeca do
+// inicio do intervalo.
+type AuditCursor struct {
+	CreatedAt time.Time
+	ID        string
+}
+
+// ArchiveStorage grava arquivos de arquivamento; e atendido por
+// imagekit/storage.ObjectStorage.
+type ArchiveStorage interface {
+	Put(ctx context.Context, key string, body io.Reader, contentType string) error
+}
+
+// AuditFilter seleciona eventos de auditoria. ActionPrefix casa o inicio da
+// acao (ex.: "student." ou "payment.reverse"); From e inclusivo e To exclusivo.
+type AuditFilter struct {
+	ActorID      string
+	EntityType   string
+	EntityID     string
+	ActionPrefix string
+	From         time.Time
+	To           time.Time
+	Limit        int
+	Offset       int
+}
+
 type RevenueSummary struct {
 	Start      time.Time
 	End        time.Time
 	TotalCents int64
+	// GrossBilledCents e o valor cheio das competencias iniciadas no periodo e
+	// DiscountCents a parte dele abatida por descontos.
+	GrossBilledCents int64
+	DiscountCents    int64
+}
+
+type DashboardMetrics struct {
+	ActiveStudents          int64
+	DelinquentSubscriptions int64
+	RevenueMonthCents       int64
+	ProjectedRevenueCents   int64
 }
 
 type StudentStatusSummary struct {
@@ -99,6 +237,8 @@ type DelinquentSubscription struct {
 	SubscriptionID string
 	StudentID      string
 	PlanID         string
+	StudentName    string
+	PlanName       string
 	EndDate        time.Time
 	DaysOverdue    int
 }
@@ -107,5 +247,15 @@ type DueSubscription struct {
 	SubscriptionID string
 	StudentID      string
 	PlanID         string
+	StudentName    string
+	PlanName       string
 	EndDate        time.Time
 }
+
+type APITokenRepository interface {
+	Create(ctx context.Context, token domain.APIToken) (domain.APIToken, error)
+	FindByHash(ctx context.Context, tokenHash string) (domain.APIToken, error)
+	List(ctx context.Context) ([]domain.APIToken, error)
+	Revoke(ctx context.Context, id string, revokedAt time.Time) error
+	Touch(ctx context.Context, id string, usedAt time.Time) error
+}
@@ -8,14 +8,25 @@ import (
 )
 
 type Session struct {
-	UserID    string
-	Name      string
-	Role      domain.UserRole
-	ExpiresAt time.Time
+	ID                 string
+	UserID             string
+	Name               string
+	Role               domain.UserRole
+	MustChangePassword bool
+	CSRFToken          string
+	IP                 string
+	UserAgent          string
+	CreatedAt          time.Time
+	LastSeenAt         time.Time
+	ExpiresAt          time.Time
 }
 
 type SessionStore interface {
 	Create(ctx context.Context, session Session) (string, error)
 	Get(ctx context.Context, token string) (Session, error)
+	Touch(ctx context.Context, token string, seenAt, expiresAt time.Time) error
 	Delete(ctx context.Context, token string) error
+	ListByUser(ctx context.Context, userID string) ([]Session, error)
+	DeleteByID(ctx context.Context, userID, sessionID string) error
+	DeleteByUser(ctx context.Context, userID string) error
 }
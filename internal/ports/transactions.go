@@ -2,6 +2,59 @@ package ports
 
 import "context"
 
+// TxIsolation e o nivel de isolamento de uma transacao; o valor vazio usa o
+// padrao do banco (read committed).
+type TxIsolation string
+
+const (
+	TxReadCommitted  TxIsolation = "read committed"
+	TxRepeatableRead TxIsolation = "repeatable read"
+	TxSerializable   TxIsolation = "serializable"
+)
+
+type TxOptions struct {
+	Isolation TxIsolation
+}
+
+// TxDependencies expoe os repositorios vinculados a uma unica transacao. Os
+// eventos gravados em Audit entram na cadeia de auditoria dentro da propria
+// transacao, logo antes do commit; se nao puderem ser gravados, a transacao
+// inteira e desfeita.
+type TxDependencies struct {
+	Students       StudentRepository
+	Plans          PlanRepository
+	Subscriptions  SubscriptionRepository
+	Freezes        SubscriptionFreezeRepository
+	Payments       PaymentRepository
+	BillingPeriods BillingPeriodRepository
+	Balances       SubscriptionBalanceRepository
+	Allocations    PaymentAllocationRepository
+	Transfers      PaymentCreditTransferRepository
+	Users          UserRepository
+	Audit          AuditRepository
+}
+
+// Payment devolve o subconjunto usado pelos fluxos de pagamento.
+func (d TxDependencies) Payment() PaymentDependencies {
+	return PaymentDependencies{
+		Payments:       d.Payments,
+		Subscriptions:  d.Subscriptions,
+		Plans:          d.Plans,
+		BillingPeriods: d.BillingPeriods,
+		Balances:       d.Balances,
+		Allocations:    d.Allocations,
+		Transfers:      d.Transfers,
+		Audit:          d.Audit,
+	}
+}
+
+// TxRunner executa fn dentro de uma transacao: um erro retornado por fn desfaz
+// todas as alteracoes. Falhas de serializacao podem executar fn mais de uma
+// vez, entao fn nao deve ter efeitos fora das dependencias recebidas.
+type TxRunner interface {
+	RunInTx(ctx context.Context, opts TxOptions, fn func(context.Context, TxDependencies) error) error
+}
+
 type PaymentDependencies struct {
 	Payments       PaymentRepository
 	Subscriptions  SubscriptionRepository
@@ -9,6 +62,7 @@ type PaymentDependencies struct {
 	BillingPeriods BillingPeriodRepository
 	Balances       SubscriptionBalanceRepository
 	Allocations    PaymentAllocationRepository
+	Transfers      PaymentCreditTransferRepository
 	Audit          AuditRepository
 }
 
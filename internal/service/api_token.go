@@ -0,0 +1,224 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/PabloPavan/jaiu/internal/auditctx"
+	"github.com/PabloPavan/jaiu/internal/domain"
+	"github.com/PabloPavan/jaiu/internal/ports"
+)
+
+const (
+	apiTokenPrefix        = "jaiu_"
+	apiTokenPrefixLength  = len(apiTokenPrefix) + 8
+	apiTokenTouchInterval = time.Minute
+)
+
+type APITokenService struct {
+	tokens ports.APITokenRepository
+	users  ports.UserRepository
+	audit  ports.AuditRepository
+	now    func() time.Time
+}
+
+func NewAPITokenService(tokens ports.APITokenRepository, users ports.UserRepository, audit ports.AuditRepository) *APITokenService {
+	return &APITokenService{tokens: tokens, users: users, audit: audit, now: time.Now}
+}
+
+func (s *APITokenService) List(ctx context.Context) ([]domain.APIToken, error) {
+	return s.tokens.List(ctx)
+}
+
+// Issue cria o token para o usuario informado e devolve o segredo em texto
+// puro. Ele nao e guardado e so pode ser exibido nesta resposta.
+func (s *APITokenService) Issue(ctx context.Context, token domain.APIToken) (domain.APIToken, string, error) {
+	token.Name = strings.TrimSpace(token.Name)
+	token.Scopes = normalizeAPIScopes(token.Scopes)
+	metadata := apiTokenAuditMetadata(token)
+	recordAuditAttempt(ctx, s.audit, "api_token.create", "api_token", "", metadata)
+
+	if err := s.validateToken(token); err != nil {
+		recordAuditFailure(ctx, s.audit, "api_token.create", "api_token", "", metadata, err)
+		return domain.APIToken{}, "", err
+	}
+
+	user, err := s.users.FindByID(ctx, token.UserID)
+	if err != nil {
+		recordAuditFailure(ctx, s.audit, "api_token.create", "api_token", "", metadata, err)
+		return domain.APIToken{}, "", err
+	}
+	if !user.Active {
+		err := validationError("usuario inativo nao pode receber token")
+		recordAuditFailure(ctx, s.audit, "api_token.create", "api_token", "", metadata, err)
+		return domain.APIToken{}, "", err
+	}
+
+	secret, err := generateAPIToken()
+	if err != nil {
+		recordAuditFailure(ctx, s.audit, "api_token.create", "api_token", "", metadata, err)
+		return domain.APIToken{}, "", err
+	}
+	token.Prefix = secret[:apiTokenPrefixLength]
+	token.TokenHash = hashAPIToken(secret)
+	token.CreatedBy = auditctx.FromContext(ctx).Actor.ID
+
+	created, err := s.tokens.Create(ctx, token)
+	if err != nil {
+		recordAuditFailure(ctx, s.audit, "api_token.create", "api_token", "", metadata, err)
+		return domain.APIToken{}, "", err
+	}
+	created.UserName = user.Name
+	created.UserEmail = user.Email
+
+	metadata["prefix"] = created.Prefix
+	recordAuditSuccess(ctx, s.audit, "api_token.create", "api_token", created.ID, metadata)
+	return created, secret, nil
+}
+
+func (s *APITokenService) Revoke(ctx context.Context, tokenID string) error {
+	recordAuditAttempt(ctx, s.audit, "api_token.revoke", "api_token", tokenID, nil)
+
+	if err := s.tokens.Revoke(ctx, tokenID, s.now()); err != nil {
+		recordAuditFailure(ctx, s.audit, "api_token.revoke", "api_token", tokenID, nil, err)
+		return err
+	}
+
+	recordAuditSuccess(ctx, s.audit, "api_token.revoke", "api_token", tokenID, nil)
+	return nil
+}
+
+// Authenticate valida o token enviado no cabecalho Authorization. Tokens
+// revogados, expirados ou de usuarios inativos retornam ErrUnauthorized.
+func (s *APITokenService) Authenticate(ctx context.Context, secret string) (ports.APIPrincipal, error) {
+	if !strings.HasPrefix(secret, apiTokenPrefix) {
+		return ports.APIPrincipal{}, ports.ErrUnauthorized
+	}
+
+	token, err := s.tokens.FindByHash(ctx, hashAPIToken(secret))
+	if err != nil {
+		if errors.Is(err, ports.ErrNotFound) {
+			return ports.APIPrincipal{}, ports.ErrUnauthorized
+		}
+		return ports.APIPrincipal{}, err
+	}
+
+	now := s.now()
+	metadata := map[string]any{
+		"prefix": token.Prefix,
+	}
+	if token.Revoked() || token.Expired(now) {
+		recordAuditFailure(ctx, s.audit, "api_token.authenticate", "api_token", token.ID, metadata, ports.ErrUnauthorized)
+		return ports.APIPrincipal{}, ports.ErrUnauthorized
+	}
+
+	user, err := s.users.FindByID(ctx, token.UserID)
+	if err != nil {
+		if errors.Is(err, ports.ErrNotFound) {
+			return ports.APIPrincipal{}, ports.ErrUnauthorized
+		}
+		return ports.APIPrincipal{}, err
+	}
+	if !user.Active {
+		recordAuditFailure(ctx, s.audit, "api_token.authenticate", "api_token", token.ID, metadata, ports.ErrUnauthorized)
+		return ports.APIPrincipal{}, ports.ErrUnauthorized
+	}
+
+	// O ultimo uso e gravado no maximo uma vez por minuto para nao gerar uma
+	// escrita a cada requisicao.
+	if token.LastUsedAt == nil || now.Sub(*token.LastUsedAt) >= apiTokenTouchInterval {
+		_ = s.tokens.Touch(ctx, token.ID, now)
+	}
+
+	return ports.APIPrincipal{
+		TokenID: token.ID,
+		UserID:  user.ID,
+		Name:    user.Name,
+		Email:   user.Email,
+		Role:    user.Role,
+		Scopes:  token.Scopes,
+	}, nil
+}
+
+func (s *APITokenService) validateToken(token domain.APIToken) error {
+	if token.Name == "" {
+		return validationError("nome do token e obrigatorio")
+	}
+	if token.UserID == "" {
+		return validationError("usuario do token e obrigatorio")
+	}
+	if len(token.Scopes) == 0 {
+		return validationError("informe ao menos um escopo")
+	}
+	for _, scope := range token.Scopes {
+		if !scope.IsValid() {
+			return validationError("escopo invalido: " + string(scope))
+		}
+	}
+	if token.ExpiresAt != nil && !token.ExpiresAt.After(s.now()) {
+		return validationError("a expiracao deve ser no futuro")
+	}
+	return nil
+}
+
+// normalizeAPIScopes remove duplicados e mantem apenas o maior acesso de cada
+// recurso, ja que escrita inclui leitura.
+func normalizeAPIScopes(scopes []domain.APIScope) []domain.APIScope {
+	byResource := make(map[domain.APIResource]domain.APIAccess, len(scopes))
+	var invalid []domain.APIScope
+	for _, scope := range scopes {
+		if !scope.IsValid() {
+			invalid = append(invalid, scope)
+			continue
+		}
+		resource, access := scope.Parts()
+		if byResource[resource] != domain.APIAccessWrite {
+			byResource[resource] = access
+		}
+	}
+
+	normalized := make([]domain.APIScope, 0, len(byResource)+len(invalid))
+	for _, resource := range domain.APIResources {
+		if access, ok := byResource[resource]; ok {
+			normalized = append(normalized, domain.NewAPIScope(resource, access))
+		}
+	}
+	sort.Slice(invalid, func(i, j int) bool { return invalid[i] < invalid[j] })
+	return append(normalized, invalid...)
+}
+
+func apiTokenAuditMetadata(token domain.APIToken) map[string]any {
+	scopes := make([]string, 0, len(token.Scopes))
+	for _, scope := range token.Scopes {
+		scopes = append(scopes, string(scope))
+	}
+	metadata := map[string]any{
+		"name":    token.Name,
+		"user_id": token.UserID,
+		"scopes":  scopes,
+	}
+	if token.ExpiresAt != nil {
+		metadata["expires_at"] = token.ExpiresAt.Format(time.RFC3339)
+	}
+	return metadata
+}
+
+func generateAPIToken() (string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return apiTokenPrefix + base64.RawURLEncoding.EncodeToString(buf), nil
+}
+
+func hashAPIToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,163 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/PabloPavan/jaiu/internal/auditctx"
+	"github.com/PabloPavan/jaiu/internal/domain"
+	"github.com/PabloPavan/jaiu/internal/ports"
+)
+
+func newAPITokenTestService() (*APITokenService, *apiTokenRepoFake, *userRepoFake, *auditRepoFake) {
+	users := &userRepoFake{users: map[string]domain.User{
+		"ana@example.com": {ID: "user-1", Name: "Ana", Email: "ana@example.com", Role: domain.RoleOperator, Active: true},
+		"bia@example.com": {ID: "user-2", Name: "Bia", Email: "bia@example.com", Role: domain.RoleOperator, Active: false},
+	}}
+	tokens := &apiTokenRepoFake{}
+	audit := &auditRepoFake{}
+	service := NewAPITokenService(tokens, users, audit)
+	service.now = func() time.Time { return time.Date(2024, 5, 10, 12, 0, 0, 0, time.UTC) }
+	return service, tokens, users, audit
+}
+
+// Testa que o token e guardado apenas como hash e que os escopos sao normalizados.
+func TestAPITokenServiceIssue(t *testing.T) {
+	service, tokens, _, audit := newAPITokenTestService()
+	ctx := auditctx.WithActor(context.Background(), auditctx.Actor{ID: "admin-1", Role: "admin"})
+
+	created, secret, err := service.Issue(ctx, domain.APIToken{
+		UserID: "user-1",
+		Name:   " Integracao ",
+		Scopes: []domain.APIScope{"payments:read", "payments:write", "students:read", "students:read"},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.HasPrefix(secret, apiTokenPrefix) || !strings.HasPrefix(secret, created.Prefix) {
+		t.Fatalf("unexpected secret %q for prefix %q", secret, created.Prefix)
+	}
+	stored := tokens.tokens[0]
+	if stored.TokenHash == secret || stored.TokenHash != hashAPIToken(secret) {
+		t.Fatalf("expected only the hash to be stored, got %q", stored.TokenHash)
+	}
+	if stored.Name != "Integracao" || stored.CreatedBy != "admin-1" {
+		t.Fatalf("unexpected stored token: %#v", stored)
+	}
+	if len(stored.Scopes) != 2 || stored.Scopes[0] != "students:read" || stored.Scopes[1] != "payments:write" {
+		t.Fatalf("unexpected scopes: %v", stored.Scopes)
+	}
+	if last := audit.events[len(audit.events)-1]; last.Action != "api_token.create.success" || last.EntityID != created.ID {
+		t.Fatalf("expected create audit, got %q", last.Action)
+	}
+	for _, event := range audit.events {
+		if strings.Contains(fmt.Sprint(event.Metadata), secret) {
+			t.Fatal("expected secret to stay out of audit metadata")
+		}
+	}
+}
+
+// Testa as validacoes de emissao de token.
+func TestAPITokenServiceIssueValidation(t *testing.T) {
+	service, tokens, _, _ := newAPITokenTestService()
+	past := service.now().Add(-time.Hour)
+
+	tests := []struct {
+		name  string
+		token domain.APIToken
+	}{
+		{"no-name", domain.APIToken{UserID: "user-1", Scopes: []domain.APIScope{"plans:read"}}},
+		{"no-scope", domain.APIToken{UserID: "user-1", Name: "x"}},
+		{"bad-scope", domain.APIToken{UserID: "user-1", Name: "x", Scopes: []domain.APIScope{"users:write"}}},
+		{"expired", domain.APIToken{UserID: "user-1", Name: "x", Scopes: []domain.APIScope{"plans:read"}, ExpiresAt: &past}},
+		{"inactive-user", domain.APIToken{UserID: "user-2", Name: "x", Scopes: []domain.APIScope{"plans:read"}}},
+		{"unknown-user", domain.APIToken{UserID: "user-9", Name: "x", Scopes: []domain.APIScope{"plans:read"}}},
+	}
+	for _, tt := range tests {
+		if _, _, err := service.Issue(context.Background(), tt.token); err == nil {
+			t.Fatalf("%s: expected error", tt.name)
+		}
+	}
+	if len(tokens.tokens) != 0 {
+		t.Fatalf("expected no token stored, got %d", len(tokens.tokens))
+	}
+}
+
+// Testa a autenticacao por token, o registro de ultimo uso e os casos negados.
+func TestAPITokenServiceAuthenticate(t *testing.T) {
+	service, tokens, users, audit := newAPITokenTestService()
+	ctx := context.Background()
+	expiresAt := service.now().Add(24 * time.Hour)
+
+	created, secret, err := service.Issue(ctx, domain.APIToken{UserID: "user-1", Name: "ERP", Scopes: []domain.APIScope{"reports:read"}, ExpiresAt: &expiresAt})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	principal, err := service.Authenticate(ctx, secret)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if principal.TokenID != created.ID || principal.UserID != "user-1" || principal.Role != domain.RoleOperator {
+		t.Fatalf("unexpected principal: %#v", principal)
+	}
+	if !principal.Allows(domain.APIResourceReports, domain.APIAccessRead) || principal.Allows(domain.APIResourceReports, domain.APIAccessWrite) {
+		t.Fatalf("unexpected scopes: %v", principal.Scopes)
+	}
+	if _, err := service.Authenticate(ctx, secret); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(tokens.touched) != 1 {
+		t.Fatalf("expected last use recorded once per interval, got %v", tokens.touched)
+	}
+
+	for _, raw := range []string{"", "jaiu_unknown", "other-token"} {
+		if _, err := service.Authenticate(ctx, raw); !errors.Is(err, ports.ErrUnauthorized) {
+			t.Fatalf("expected unauthorized for %q, got %v", raw, err)
+		}
+	}
+
+	service.now = func() time.Time { return expiresAt }
+	if _, err := service.Authenticate(ctx, secret); !errors.Is(err, ports.ErrUnauthorized) {
+		t.Fatalf("expected expired token to be rejected, got %v", err)
+	}
+	if last := audit.events[len(audit.events)-1]; last.Action != "api_token.authenticate.failure" {
+		t.Fatalf("expected authenticate failure audit, got %q", last.Action)
+	}
+
+	service.now = func() time.Time { return expiresAt.Add(-time.Hour) }
+	user := users.users["ana@example.com"]
+	user.Active = false
+	users.users["ana@example.com"] = user
+	if _, err := service.Authenticate(ctx, secret); !errors.Is(err, ports.ErrUnauthorized) {
+		t.Fatalf("expected inactive user to be rejected, got %v", err)
+	}
+}
+
+// Testa que o token revogado deixa de autenticar.
+func TestAPITokenServiceRevoke(t *testing.T) {
+	service, _, _, audit := newAPITokenTestService()
+	ctx := context.Background()
+
+	created, secret, err := service.Issue(ctx, domain.APIToken{UserID: "user-1", Name: "ERP", Scopes: []domain.APIScope{"plans:read"}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := service.Revoke(ctx, created.ID); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := service.Revoke(ctx, created.ID); !errors.Is(err, ports.ErrNotFound) {
+		t.Fatalf("expected not found for revoked token, got %v", err)
+	}
+	if _, err := service.Authenticate(ctx, secret); !errors.Is(err, ports.ErrUnauthorized) {
+		t.Fatalf("expected revoked token to be rejected, got %v", err)
+	}
+	actions := auditActions(audit)
+	if !strings.Contains(strings.Join(actions, ","), "api_token.revoke.success") {
+		t.Fatalf("expected revoke audit, got %v", actions)
+	}
+}
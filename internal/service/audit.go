@@ -0,0 +1,84 @@
+package service
+
+import (
+	"context"
+	"strings"
+
+	"github.com/PabloPavan/jaiu/internal/domain"
+	"github.com/PabloPavan/jaiu/internal/ports"
+)
+
+const (
+	auditMaxPageSize  = 200
+	auditTimelineSize = 100
+)
+
+var (
+	errAuditInvalidRange = validationError("periodo de auditoria invalido")
+	errAuditEntity       = validationError("entidade de auditoria invalida")
+)
+
+type AuditService struct {
+	repo ports.AuditReader
+}
+
+func NewAuditService(repo ports.AuditReader) *AuditService {
+	return &AuditService{repo: repo}
+}
+
+func (s *AuditService) Search(ctx context.Context, filter ports.AuditFilter) ([]domain.AuditEvent, error) {
+	filter, err := normalizeAuditFilter(filter)
+	if err != nil {
+		return nil, err
+	}
+	return s.repo.Search(ctx, filter)
+}
+
+func (s *AuditService) Count(ctx context.Context, filter ports.AuditFilter) (int, error) {
+	filter, err := normalizeAuditFilter(filter)
+	if err != nil {
+		return 0, err
+	}
+	return s.repo.Count(ctx, filter)
+}
+
+// Timeline devolve os eventos mais recentes de uma entidade, do mais novo
+// para o mais antigo.
+func (s *AuditService) Timeline(ctx context.Context, entityType, entityID string) ([]domain.AuditEvent, error) {
+	entityType = strings.TrimSpace(entityType)
+	entityID = strings.TrimSpace(entityID)
+	if entityType == "" || entityID == "" {
+		return nil, errAuditEntity
+	}
+	return s.repo.Search(ctx, ports.AuditFilter{
+		EntityType: entityType,
+		EntityID:   entityID,
+		Limit:      auditTimelineSize,
+	})
+}
+
+// StudentAccess resume quem visualizou os dados do aluno.
+func (s *AuditService) StudentAccess(ctx context.Context, studentID string) ([]ports.AuditAccessSummary, error) {
+	studentID = strings.TrimSpace(studentID)
+	if studentID == "" {
+		return nil, errAuditEntity
+	}
+	return s.repo.AccessSummary(ctx, "student.view", "student", studentID)
+}
+
+func normalizeAuditFilter(filter ports.AuditFilter) (ports.AuditFilter, error) {
+	filter.ActorID = strings.TrimSpace(filter.ActorID)
+	filter.EntityType = strings.TrimSpace(filter.EntityType)
+	filter.EntityID = strings.TrimSpace(filter.EntityID)
+	filter.ActionPrefix = strings.TrimSpace(filter.ActionPrefix)
+	if !filter.From.IsZero() && !filter.To.IsZero() && !filter.To.After(filter.From) {
+		return filter, errAuditInvalidRange
+	}
+	if filter.Limit > auditMaxPageSize {
+		filter.Limit = auditMaxPageSize
+	}
+	if filter.Offset < 0 {
+		filter.Offset = 0
+	}
+	return filter, nil
+}
@@ -0,0 +1,321 @@
+package service
+
+import (
+	"compress/gzip"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/PabloPavan/jaiu/internal/domain"
+	"github.com/PabloPavan/jaiu/internal/ports"
+)
+
+const (
+	auditArchiveBatchSize     = 1000
+	defaultAuditArchivePrefix = "audit"
+)
+
+type AuditExportFormat string
+
+const (
+	AuditExportCSV    AuditExportFormat = "csv"
+	AuditExportNDJSON AuditExportFormat = "ndjson"
+)
+
+var (
+	errAuditExportFormat = validationError("formato de exportacao invalido")
+	errAuditRetention    = validationError("retencao de auditoria deve ser de pelo menos 1 mes")
+)
+
+var auditExportColumns = []string{
+	"id",
+	"seq",
+	"created_at",
+	"actor_id",
+	"actor_role",
+	"action",
+	"entity_type",
+	"entity_id",
+	"ip",
+	"user_agent",
+	"metadata",
+	"prev_hash",
+	"hash",
+}
+
+// auditExportRecord e a linha gravada nos arquivos NDJSON.
+type auditExportRecord struct {
+	ID         string         `json:"id"`
+	Seq        int64          `json:"seq,omitempty"`
+	CreatedAt  string         `json:"created_at"`
+	ActorID    string         `json:"actor_id,omitempty"`
+	ActorRole  string         `json:"actor_role,omitempty"`
+	Action     string         `json:"action"`
+	EntityType string         `json:"entity_type"`
+	EntityID   string         `json:"entity_id,omitempty"`
+	IP         string         `json:"ip,omitempty"`
+	UserAgent  string         `json:"user_agent,omitempty"`
+	Metadata   map[string]any `json:"metadata"`
+	PrevHash   string         `json:"prev_hash,omitempty"`
+	Hash       string         `json:"hash,omitempty"`
+}
+
+func ParseAuditExportFormat(value string) (AuditExportFormat, error) {
+	switch format := AuditExportFormat(strings.ToLower(strings.TrimSpace(value))); format {
+	case AuditExportCSV, AuditExportNDJSON:
+		return format, nil
+	default:
+		return "", errAuditExportFormat
+	}
+}
+
+type AuditExporter struct {
+	repo ports.AuditArchiveRepository
+}
+
+func NewAuditExporter(repo ports.AuditArchiveRepository) *AuditExporter {
+	return &AuditExporter{repo: repo}
+}
+
+// Export grava em w os eventos criados em [from, to), em ordem cronologica, e
+// devolve quantos foram exportados.
+func (e *AuditExporter) Export(ctx context.Context, w io.Writer, format AuditExportFormat, from, to time.Time) (int, error) {
+	if e.repo == nil {
+		return 0, errors.New("dependencias de exportacao indisponiveis")
+	}
+	if !to.After(from) {
+		return 0, errAuditInvalidRange
+	}
+
+	switch format {
+	case AuditExportNDJSON:
+		return writeAuditNDJSON(ctx, e.repo, w, from, to)
+	case AuditExportCSV:
+		return writeAuditCSV(ctx, e.repo, w, from, to)
+	default:
+		return 0, errAuditExportFormat
+	}
+}
+
+// AuditRetentionJob arquiva em NDJSON.gz, mes a mes, os eventos mais antigos
+// que a retencao configurada e os remove da tabela.
+type AuditRetentionJob struct {
+	repo    ports.AuditArchiveRepository
+	storage ports.ArchiveStorage
+	audit   ports.AuditRepository
+	months  int
+	prefix  string
+	now     func() time.Time
+}
+
+func NewAuditRetentionJob(repo ports.AuditArchiveRepository, storage ports.ArchiveStorage, audit ports.AuditRepository, months int) *AuditRetentionJob {
+	return &AuditRetentionJob{
+		repo:    repo,
+		storage: storage,
+		audit:   audit,
+		months:  months,
+		prefix:  defaultAuditArchivePrefix,
+		now:     time.Now,
+	}
+}
+
+// SetPrefix define o prefixo das chaves dos arquivos no storage.
+func (j *AuditRetentionJob) SetPrefix(prefix string) {
+	prefix = strings.Trim(strings.TrimSpace(prefix), "/")
+	if prefix == "" {
+		prefix = defaultAuditArchivePrefix
+	}
+	j.prefix = prefix
+}
+
+func (j *AuditRetentionJob) Run(ctx context.Context) error {
+	if j.repo == nil || j.storage == nil {
+		return errors.New("dependencias de retencao indisponiveis")
+	}
+	if j.months < 1 {
+		return errAuditRetention
+	}
+
+	now := j.now().UTC()
+	cutoff := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC).AddDate(0, -j.months, 0)
+
+	oldest, ok, err := j.repo.OldestCreatedAt(ctx)
+	if err != nil || !ok || !oldest.Before(cutoff) {
+		return err
+	}
+
+	oldest = oldest.UTC()
+	// maxSeq acumula os meses ja arquivados nesta execucao: um evento que
+	// ficou na tabela por estar depois de um do mes seguinte sai junto com ele.
+	var maxSeq int64
+	for month := time.Date(oldest.Year(), oldest.Month(), 1, 0, 0, 0, 0, time.UTC); month.Before(cutoff); month = month.AddDate(0, 1, 0) {
+		if maxSeq, err = j.archiveMonth(ctx, month, month.AddDate(0, 1, 0), maxSeq); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// auditArchiveResult resume o que foi escrito no arquivo de um mes.
+type auditArchiveResult struct {
+	count  int
+	maxSeq int64
+	err    error
+}
+
+// archiveMonth envia o arquivo ao storage enquanto os eventos sao lidos, sem
+// manter o mes inteiro em memoria, e so remove os eventos depois que ele foi
+// gravado. A remocao vai ate o maior seq arquivado, entao eventos gravados
+// depois da leitura ficam na tabela. Se a execucao for interrompida, a proxima
+// regrava o mesmo arquivo. Quando o primeiro evento do mes e encadeado, o seq
+// dele entra na chave: eventos que DeleteRange deixou para nao abrir buraco na
+// cadeia vao para um arquivo novo em vez de sobrescrever o anterior.
+func (j *AuditRetentionJob) archiveMonth(ctx context.Context, start, end time.Time, maxSeq int64) (int64, error) {
+	first, err := j.repo.ListRange(ctx, start, end, ports.AuditCursor{}, 1)
+	if err != nil || len(first) == 0 {
+		return maxSeq, err
+	}
+
+	reader, writer := io.Pipe()
+	done := make(chan auditArchiveResult, 1)
+	go func() {
+		result := auditArchiveResult{maxSeq: maxSeq}
+		gz := gzip.NewWriter(writer)
+		encoder := json.NewEncoder(gz)
+		result.count, result.err = eachAuditEvent(ctx, j.repo, start, end, func(event domain.AuditEvent) error {
+			result.maxSeq = max(result.maxSeq, event.Seq)
+			return encoder.Encode(newAuditExportRecord(event))
+		})
+		if result.err == nil {
+			result.err = gz.Close()
+		}
+		_ = writer.CloseWithError(result.err)
+		done <- result
+	}()
+
+	key := fmt.Sprintf("%s/%04d/%02d.ndjson.gz", j.prefix, start.Year(), int(start.Month()))
+	if first[0].Seq > 0 {
+		key = fmt.Sprintf("%s/%04d/%02d-%d.ndjson.gz", j.prefix, start.Year(), int(start.Month()), first[0].Seq)
+	}
+	putErr := j.storage.Put(ctx, key, reader, "application/gzip")
+	// Libera a escrita caso o storage tenha parado de ler antes do fim.
+	_ = reader.Close()
+	result := <-done
+	if putErr != nil {
+		return maxSeq, putErr
+	}
+	if result.err != nil {
+		return maxSeq, result.err
+	}
+
+	deleted, err := j.repo.DeleteRange(ctx, start, end, result.maxSeq)
+	if err != nil {
+		return maxSeq, err
+	}
+
+	recordAudit(ctx, j.audit, "audit.archive", "audit", "", map[string]any{
+		"month":    start.Format("2006-01"),
+		"key":      key,
+		"exported": result.count,
+		"deleted":  deleted,
+		"max_seq":  result.maxSeq,
+	})
+	return result.maxSeq, nil
+}
+
+func eachAuditEvent(ctx context.Context, repo ports.AuditArchiveRepository, from, to time.Time, fn func(domain.AuditEvent) error) (int, error) {
+	count := 0
+	cursor := ports.AuditCursor{}
+	for {
+		events, err := repo.ListRange(ctx, from, to, cursor, auditArchiveBatchSize)
+		if err != nil {
+			return count, err
+		}
+		for _, event := range events {
+			if err := fn(event); err != nil {
+				return count, err
+			}
+			count++
+		}
+		if len(events) < auditArchiveBatchSize {
+			return count, nil
+		}
+		last := events[len(events)-1]
+		cursor = ports.AuditCursor{CreatedAt: last.CreatedAt, ID: last.ID}
+	}
+}
+
+func writeAuditNDJSON(ctx context.Context, repo ports.AuditArchiveRepository, w io.Writer, from, to time.Time) (int, error) {
+	encoder := json.NewEncoder(w)
+	return eachAuditEvent(ctx, repo, from, to, func(event domain.AuditEvent) error {
+		return encoder.Encode(newAuditExportRecord(event))
+	})
+}
+
+func writeAuditCSV(ctx context.Context, repo ports.AuditArchiveRepository, w io.Writer, from, to time.Time) (int, error) {
+	writer := csv.NewWriter(w)
+	if err := writer.Write(auditExportColumns); err != nil {
+		return 0, err
+	}
+
+	count, err := eachAuditEvent(ctx, repo, from, to, func(event domain.AuditEvent) error {
+		record := newAuditExportRecord(event)
+		metadata, err := json.Marshal(record.Metadata)
+		if err != nil {
+			return err
+		}
+		seq := ""
+		if record.Seq > 0 {
+			seq = strconv.FormatInt(record.Seq, 10)
+		}
+		return writer.Write([]string{
+			record.ID,
+			seq,
+			record.CreatedAt,
+			record.ActorID,
+			record.ActorRole,
+			record.Action,
+			record.EntityType,
+			record.EntityID,
+			record.IP,
+			record.UserAgent,
+			string(metadata),
+			record.PrevHash,
+			record.Hash,
+		})
+	})
+	if err != nil {
+		return count, err
+	}
+
+	writer.Flush()
+	return count, writer.Error()
+}
+
+func newAuditExportRecord(event domain.AuditEvent) auditExportRecord {
+	metadata := event.Metadata
+	if metadata == nil {
+		metadata = map[string]any{}
+	}
+	return auditExportRecord{
+		ID:         event.ID,
+		Seq:        event.Seq,
+		CreatedAt:  event.CreatedAt.UTC().Format(time.RFC3339Nano),
+		ActorID:    event.ActorID,
+		ActorRole:  event.ActorRole,
+		Action:     event.Action,
+		EntityType: event.EntityType,
+		EntityID:   event.EntityID,
+		IP:         event.IP,
+		UserAgent:  event.UserAgent,
+		Metadata:   metadata,
+		PrevHash:   event.PrevHash,
+		Hash:       event.Hash,
+	}
+}
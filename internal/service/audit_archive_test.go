@@ -0,0 +1,215 @@
+package service
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/csv"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/PabloPavan/jaiu/internal/domain"
+)
+
+// Testa que a retencao arquiva os meses antigos em NDJSON.gz e remove os eventos.
+func TestAuditRetentionJobArchivesOldMonths(t *testing.T) {
+	repo := &auditRepoFake{events: []domain.AuditEvent{
+		{ID: "evt-1", Action: "student.create.success", EntityType: "student", CreatedAt: time.Date(2024, 1, 5, 10, 0, 0, 0, time.UTC)},
+		{ID: "evt-2", Action: "student.update.success", EntityType: "student", CreatedAt: time.Date(2024, 1, 20, 10, 0, 0, 0, time.UTC)},
+		{ID: "evt-3", Action: "plan.create.success", EntityType: "plan", CreatedAt: time.Date(2024, 3, 2, 10, 0, 0, 0, time.UTC)},
+		{ID: "evt-4", Action: "plan.update.success", EntityType: "plan", CreatedAt: time.Date(2024, 6, 2, 10, 0, 0, 0, time.UTC)},
+	}}
+	storage := &archiveStorageFake{}
+	job := NewAuditRetentionJob(repo, storage, repo, 3)
+	job.now = func() time.Time { return time.Date(2024, 7, 15, 12, 0, 0, 0, time.UTC) }
+
+	if err := job.Run(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(storage.objects) != 2 {
+		t.Fatalf("expected 2 archives, got %d", len(storage.objects))
+	}
+	lines := readArchiveLines(t, storage.objects["audit/2024/01.ndjson.gz"])
+	if len(lines) != 2 || !strings.Contains(lines[0], `"id":"evt-1"`) || !strings.Contains(lines[1], `"id":"evt-2"`) {
+		t.Fatalf("unexpected january archive: %#v", lines)
+	}
+	if _, ok := storage.objects["audit/2024/03.ndjson.gz"]; !ok {
+		t.Fatalf("expected march archive, got %#v", storage.objects)
+	}
+
+	var remaining, archived []string
+	for _, event := range repo.events {
+		if event.Action == "audit.archive" {
+			archived = append(archived, event.Metadata["month"].(string))
+			continue
+		}
+		remaining = append(remaining, event.ID)
+	}
+	if len(remaining) != 1 || remaining[0] != "evt-4" {
+		t.Fatalf("expected only recent event to remain, got %#v", remaining)
+	}
+	if len(archived) != 2 || archived[0] != "2024-01" || archived[1] != "2024-03" {
+		t.Fatalf("unexpected archive audit events: %#v", archived)
+	}
+}
+
+// Testa que nenhum evento e removido quando o arquivo nao pode ser gravado.
+func TestAuditRetentionJobKeepsEventsWhenStorageFails(t *testing.T) {
+	repo := &auditRepoFake{events: []domain.AuditEvent{
+		{ID: "evt-1", Action: "student.create.success", CreatedAt: time.Date(2024, 1, 5, 10, 0, 0, 0, time.UTC)},
+	}}
+	storage := &archiveStorageFake{err: errors.New("storage offline")}
+	job := NewAuditRetentionJob(repo, storage, repo, 1)
+	job.now = func() time.Time { return time.Date(2024, 7, 15, 12, 0, 0, 0, time.UTC) }
+
+	if err := job.Run(context.Background()); err == nil {
+		t.Fatalf("expected storage error")
+	}
+	if len(repo.events) != 1 {
+		t.Fatalf("expected event to be kept, got %#v", repo.events)
+	}
+}
+
+// Testa que eventos gravados no mes durante o envio do arquivo nao sao removidos.
+func TestAuditRetentionJobKeepsEventsAppendedDuringArchive(t *testing.T) {
+	repo := &auditRepoFake{events: []domain.AuditEvent{
+		{ID: "evt-1", Seq: 1, Action: "student.create.success", CreatedAt: time.Date(2024, 1, 5, 10, 0, 0, 0, time.UTC)},
+		{ID: "evt-2", Seq: 2, Action: "student.update.success", CreatedAt: time.Date(2024, 1, 6, 10, 0, 0, 0, time.UTC)},
+	}}
+	storage := &archiveStorageFake{}
+	storage.afterPut = func() {
+		repo.events = append(repo.events, domain.AuditEvent{ID: "evt-3", Seq: 3, Action: "student.update.success", CreatedAt: time.Date(2024, 1, 7, 10, 0, 0, 0, time.UTC)})
+	}
+	job := NewAuditRetentionJob(repo, storage, repo, 1)
+	job.now = func() time.Time { return time.Date(2024, 7, 15, 12, 0, 0, 0, time.UTC) }
+
+	if err := job.Run(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	lines := readArchiveLines(t, storage.objects["audit/2024/01-1.ndjson.gz"])
+	if len(lines) != 2 {
+		t.Fatalf("expected 2 archived events, got %#v", lines)
+	}
+	var remaining []string
+	for _, event := range repo.events {
+		if event.Action != "audit.archive" {
+			remaining = append(remaining, event.ID)
+		}
+	}
+	if len(remaining) != 1 || remaining[0] != "evt-3" {
+		t.Fatalf("expected event appended during archive to remain, got %#v", remaining)
+	}
+}
+
+// Testa que a retencao so remove um prefixo continuo da cadeia e que o evento
+// deixado para tras vai para um arquivo novo na execucao seguinte.
+func TestAuditRetentionJobDeletesContiguousPrefix(t *testing.T) {
+	repo := &auditRepoFake{events: []domain.AuditEvent{
+		{ID: "evt-1", Seq: 1, Action: "student.create.success", CreatedAt: time.Date(2024, 1, 5, 10, 0, 0, 0, time.UTC)},
+		{ID: "evt-2", Seq: 2, Action: "student.update.success", CreatedAt: time.Date(2024, 2, 3, 10, 0, 0, 0, time.UTC)},
+		{ID: "evt-3", Seq: 3, Action: "student.update.success", CreatedAt: time.Date(2024, 1, 31, 23, 59, 0, 0, time.UTC)},
+		{ID: "evt-4", Seq: 4, Action: "plan.update.success", CreatedAt: time.Date(2024, 7, 2, 10, 0, 0, 0, time.UTC)},
+	}}
+	storage := &archiveStorageFake{}
+	job := NewAuditRetentionJob(repo, storage, repo, 5)
+	job.now = func() time.Time { return time.Date(2024, 7, 15, 12, 0, 0, 0, time.UTC) }
+
+	if err := job.Run(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if lines := readArchiveLines(t, storage.objects["audit/2024/01-1.ndjson.gz"]); len(lines) != 2 {
+		t.Fatalf("expected january archive with 2 events, got %#v", lines)
+	}
+	if ids := remainingAuditIDs(repo); len(ids) != 3 || ids[0] != "evt-2" || ids[1] != "evt-3" {
+		t.Fatalf("expected evt-3 kept behind evt-2, got %#v", ids)
+	}
+
+	job.now = func() time.Time { return time.Date(2024, 8, 15, 12, 0, 0, 0, time.UTC) }
+	if err := job.Run(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if lines := readArchiveLines(t, storage.objects["audit/2024/01-1.ndjson.gz"]); len(lines) != 2 {
+		t.Fatalf("expected first january archive untouched, got %#v", lines)
+	}
+	if lines := readArchiveLines(t, storage.objects["audit/2024/01-3.ndjson.gz"]); len(lines) != 1 {
+		t.Fatalf("expected leftover archived in a new file, got %#v", lines)
+	}
+	if ids := remainingAuditIDs(repo); len(ids) != 1 || ids[0] != "evt-4" {
+		t.Fatalf("expected only recent event to remain, got %#v", ids)
+	}
+}
+
+// Testa a exportacao em CSV de um intervalo de datas.
+func TestAuditExporterCSV(t *testing.T) {
+	repo := &auditRepoFake{events: []domain.AuditEvent{
+		{ID: "evt-2", Seq: 2, Action: "payment.reverse.success", EntityType: "payment", Metadata: map[string]any{"reason": "duplicado, cliente"}, CreatedAt: time.Date(2024, 2, 10, 9, 0, 0, 0, time.UTC)},
+		{ID: "evt-1", Seq: 1, Action: "payment.create.success", EntityType: "payment", CreatedAt: time.Date(2024, 2, 1, 9, 0, 0, 0, time.UTC)},
+		{ID: "evt-3", Seq: 3, Action: "payment.create.success", EntityType: "payment", CreatedAt: time.Date(2024, 3, 1, 9, 0, 0, 0, time.UTC)},
+	}}
+	exporter := NewAuditExporter(repo)
+
+	var buf bytes.Buffer
+	count, err := exporter.Export(context.Background(), &buf, AuditExportCSV, time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC), time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if count != 2 {
+		t.Fatalf("expected 2 exported events, got %d", count)
+	}
+
+	rows, err := csv.NewReader(&buf).ReadAll()
+	if err != nil {
+		t.Fatalf("read csv: %v", err)
+	}
+	if len(rows) != 3 || rows[0][0] != "id" || rows[1][0] != "evt-1" || rows[2][0] != "evt-2" {
+		t.Fatalf("unexpected csv rows: %#v", rows)
+	}
+	if rows[2][10] != `{"reason":"duplicado, cliente"}` {
+		t.Fatalf("unexpected metadata column: %q", rows[2][10])
+	}
+}
+
+// Testa a validacao de formato e intervalo da exportacao.
+func TestAuditExporterRejectsInvalidInput(t *testing.T) {
+	if _, err := ParseAuditExportFormat("xml"); !errors.Is(err, errAuditExportFormat) {
+		t.Fatalf("expected format error, got %v", err)
+	}
+	if format, err := ParseAuditExportFormat(" NDJSON "); err != nil || format != AuditExportNDJSON {
+		t.Fatalf("expected ndjson format, got %q err=%v", format, err)
+	}
+
+	exporter := NewAuditExporter(&auditRepoFake{})
+	day := time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC)
+	if _, err := exporter.Export(context.Background(), io.Discard, AuditExportCSV, day, day); !errors.Is(err, errAuditInvalidRange) {
+		t.Fatalf("expected invalid range, got %v", err)
+	}
+}
+
+func remainingAuditIDs(repo *auditRepoFake) []string {
+	var ids []string
+	for _, event := range repo.events {
+		if event.Action != "audit.archive" {
+			ids = append(ids, event.ID)
+		}
+	}
+	return ids
+}
+
+func readArchiveLines(t *testing.T, payload []byte) []string {
+	t.Helper()
+
+	reader, err := gzip.NewReader(bytes.NewReader(payload))
+	if err != nil {
+		t.Fatalf("open archive: %v", err)
+	}
+	content, err := io.ReadAll(reader)
+	if err != nil {
+		t.Fatalf("read archive: %v", err)
+	}
+	return strings.Split(strings.TrimSpace(string(content)), "\n")
+}
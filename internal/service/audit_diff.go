@@ -0,0 +1,140 @@
+package service
+
+import (
+	"time"
+
+	"github.com/PabloPavan/jaiu/internal/domain"
+)
+
+const auditMaskedValue = "***"
+
+// auditSensitiveFields nunca tem o valor gravado na auditoria; o diff indica
+// apenas que o campo mudou.
+var auditSensitiveFields = map[string]bool{
+	"cpf":           true,
+	"password_hash": true,
+}
+
+// withAuditChanges acrescenta ao metadata o diff entre o registro salvo e o
+// atualizado, no formato {"campo": {"from": antes, "to": depois}}.
+func withAuditChanges(metadata map[string]any, before, after map[string]any) map[string]any {
+	meta := copyMetadata(metadata)
+	meta["changes"] = auditChanges(before, after)
+	return meta
+}
+
+func auditChanges(before, after map[string]any) map[string]any {
+	changes := map[string]any{}
+	for field, to := range after {
+		from := before[field]
+		if from == to {
+			continue
+		}
+		if auditSensitiveFields[field] {
+			from = maskAuditValue(from)
+			to = maskAuditValue(to)
+		}
+		changes[field] = map[string]any{"from": from, "to": to}
+	}
+	return changes
+}
+
+func maskAuditValue(value any) any {
+	if value == nil || value == "" {
+		return value
+	}
+	return auditMaskedValue
+}
+
+func auditDate(value time.Time) any {
+	if value.IsZero() {
+		return nil
+	}
+	return value.Format("2006-01-02")
+}
+
+func auditDatePtr(value *time.Time) any {
+	if value == nil {
+		return nil
+	}
+	return auditDate(*value)
+}
+
+func auditTimestamp(value time.Time) any {
+	if value.IsZero() {
+		return nil
+	}
+	return value.UTC().Format(time.RFC3339)
+}
+
+func studentAuditFields(student domain.Student) map[string]any {
+	return map[string]any{
+		"full_name":        student.FullName,
+		"birth_date":       auditDatePtr(student.BirthDate),
+		"gender":           student.Gender,
+		"phone":            student.Phone,
+		"email":            student.Email,
+		"cpf":              student.CPF,
+		"address":          student.Address,
+		"notes":            student.Notes,
+		"photo_object_key": student.PhotoObjectKey,
+		"status":           string(student.Status),
+	}
+}
+
+func planAuditFields(plan domain.Plan) map[string]any {
+	return map[string]any{
+		"name":              plan.Name,
+		"duration_days":     plan.DurationDays,
+		"price_cents":       plan.PriceCents,
+		"active":            plan.Active,
+		"description":       plan.Description,
+		"late_fee_bps":      plan.LateFeeBasisPoints,
+		"late_interest_bps": plan.LateInterestBasisPoints,
+		"max_freeze_days":   plan.MaxFreezeDays,
+		"installments":      plan.InstallmentCount(),
+	}
+}
+
+func subscriptionAuditFields(subscription domain.Subscription) map[string]any {
+	return map[string]any{
+		"student_id":        subscription.StudentID,
+		"plan_id":           subscription.PlanID,
+		"start_date":        auditDate(subscription.StartDate),
+		"end_date":          auditDate(subscription.EndDate),
+		"status":            string(subscription.Status),
+		"price_cents":       subscription.PriceCents,
+		"payment_day":       subscription.PaymentDay,
+		"auto_renew":        subscription.AutoRenew,
+		"coupon_id":         subscription.CouponID,
+		"discount_kind":     string(subscription.Discount.Kind),
+		"discount_value":    subscription.Discount.Value,
+		"discount_duration": string(subscription.Discount.Duration),
+		"discount_periods":  subscription.Discount.Periods,
+	}
+}
+
+func paymentAuditFields(payment domain.Payment) map[string]any {
+	return map[string]any{
+		"subscription_id": payment.SubscriptionID,
+		"paid_at":         auditTimestamp(payment.PaidAt),
+		"amount_cents":    payment.AmountCents,
+		"method":          string(payment.Method),
+		"reference":       payment.Reference,
+		"notes":           payment.Notes,
+		"status":          string(payment.Status),
+		"kind":            string(payment.Kind),
+		"credit_cents":    payment.CreditCents,
+	}
+}
+
+func userAuditFields(user domain.User) map[string]any {
+	return map[string]any{
+		"name":                 user.Name,
+		"email":                user.Email,
+		"password_hash":        user.PasswordHash,
+		"role":                 string(user.Role),
+		"active":               user.Active,
+		"must_change_password": user.MustChangePassword,
+	}
+}
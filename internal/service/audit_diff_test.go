@@ -0,0 +1,31 @@
+package service
+
+import "testing"
+
+// Testa o diff de auditoria com campos sensiveis mascarados.
+func TestAuditChanges(t *testing.T) {
+	before := map[string]any{"name": "Ana", "active": true, "password_hash": "hash-1", "cpf": "", "price_cents": int64(1000)}
+	after := map[string]any{"name": "Ana", "active": false, "password_hash": "hash-2", "cpf": "11122233344", "price_cents": int64(1000)}
+
+	changes := auditChanges(before, after)
+	if len(changes) != 3 {
+		t.Fatalf("expected 3 changes, got %#v", changes)
+	}
+	active := changes["active"].(map[string]any)
+	if active["from"] != true || active["to"] != false {
+		t.Fatalf("unexpected active change: %#v", active)
+	}
+	password := changes["password_hash"].(map[string]any)
+	if password["from"] != auditMaskedValue || password["to"] != auditMaskedValue {
+		t.Fatalf("expected masked password hash, got %#v", password)
+	}
+	cpf := changes["cpf"].(map[string]any)
+	if cpf["from"] != "" || cpf["to"] != auditMaskedValue {
+		t.Fatalf("expected masked cpf keeping empty origin, got %#v", cpf)
+	}
+
+	meta := withAuditChanges(map[string]any{"status": "active"}, before, before)
+	if meta["status"] != "active" || len(meta["changes"].(map[string]any)) != 0 {
+		t.Fatalf("unexpected metadata: %#v", meta)
+	}
+}
@@ -38,6 +38,9 @@ func buildAuditEvent(ctx context.Context, action, entityType, entityID string, m
 	if info.Actor.Email != "" {
 		meta["actor_email"] = info.Actor.Email
 	}
+	if info.Actor.TokenID != "" {
+		meta["actor_token_id"] = info.Actor.TokenID
+	}
 
 	event := domain.AuditEvent{
 		Action:     action,
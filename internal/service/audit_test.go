@@ -0,0 +1,67 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/PabloPavan/jaiu/internal/domain"
+	"github.com/PabloPavan/jaiu/internal/ports"
+)
+
+// Testa a normalizacao dos filtros de busca da auditoria.
+func TestAuditServiceSearchNormalizesFilter(t *testing.T) {
+	repo := &auditRepoFake{events: []domain.AuditEvent{
+		{ID: "evt-1", Action: "student.create.success", EntityType: "student", EntityID: "stu-1"},
+		{ID: "evt-2", Action: "plan.update.success", EntityType: "plan", EntityID: "plan-1"},
+	}}
+	service := NewAuditService(repo)
+
+	events, err := service.Search(context.Background(), ports.AuditFilter{ActionPrefix: " student. ", Limit: 1000, Offset: -5})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(events) != 1 || events[0].ID != "evt-1" {
+		t.Fatalf("unexpected events: %#v", events)
+	}
+	if repo.lastFilter.ActionPrefix != "student." || repo.lastFilter.Limit != auditMaxPageSize || repo.lastFilter.Offset != 0 {
+		t.Fatalf("unexpected normalized filter: %#v", repo.lastFilter)
+	}
+}
+
+// Testa que um periodo invertido e rejeitado antes de consultar o repositorio.
+func TestAuditServiceRejectsInvalidRange(t *testing.T) {
+	repo := &auditRepoFake{}
+	service := NewAuditService(repo)
+
+	now := time.Date(2024, 5, 10, 12, 0, 0, 0, time.UTC)
+	_, err := service.Count(context.Background(), ports.AuditFilter{From: now, To: now.Add(-time.Hour)})
+	if !errors.Is(err, errAuditInvalidRange) {
+		t.Fatalf("expected errAuditInvalidRange, got %v", err)
+	}
+}
+
+// Testa a linha do tempo de uma entidade.
+func TestAuditServiceTimeline(t *testing.T) {
+	repo := &auditRepoFake{events: []domain.AuditEvent{
+		{ID: "evt-1", Action: "payment.create.success", EntityType: "payment", EntityID: "pay-1"},
+		{ID: "evt-2", Action: "payment.create.success", EntityType: "payment", EntityID: "pay-2"},
+	}}
+	service := NewAuditService(repo)
+
+	events, err := service.Timeline(context.Background(), "payment", "pay-1")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(events) != 1 || events[0].ID != "evt-1" {
+		t.Fatalf("unexpected events: %#v", events)
+	}
+	if repo.lastFilter.Limit != auditTimelineSize {
+		t.Fatalf("expected timeline limit %d, got %d", auditTimelineSize, repo.lastFilter.Limit)
+	}
+
+	if _, err := service.Timeline(context.Background(), "payment", ""); !errors.Is(err, errAuditEntity) {
+		t.Fatalf("expected errAuditEntity, got %v", err)
+	}
+}
@@ -3,21 +3,37 @@ package service
 import (
 	"context"
 	"errors"
+	"time"
 
+	"github.com/PabloPavan/jaiu/internal/auditctx"
 	"github.com/PabloPavan/jaiu/internal/domain"
 	"github.com/PabloPavan/jaiu/internal/ports"
 	"golang.org/x/crypto/bcrypt"
 )
 
 type AuthService struct {
-	repo  ports.UserRepository
-	audit ports.AuditRepository
+	repo    ports.UserRepository
+	audit   ports.AuditRepository
+	limiter *LoginLimiter
+	mfa     *MFAService
 }
 
 func NewAuthService(repo ports.UserRepository, audit ports.AuditRepository) *AuthService {
 	return &AuthService{repo: repo, audit: audit}
 }
 
+// SetLoginLimiter ativa a protecao contra forca bruta no Authenticate.
+func (s *AuthService) SetLoginLimiter(limiter *LoginLimiter) {
+	s.limiter = limiter
+}
+
+// SetMFA informa quais logins ainda passam pelo segundo fator. Nesses casos a
+// senha correta nao zera as falhas do email; isso so acontece quando o codigo
+// e confirmado em MFAService.CompleteLogin.
+func (s *AuthService) SetMFA(mfa *MFAService) {
+	s.mfa = mfa
+}
+
 func (s *AuthService) CreateUser(ctx context.Context, user domain.User) (domain.User, error) {
 	return s.repo.Create(ctx, user)
 }
@@ -32,30 +48,85 @@ func (s *AuthService) Authenticate(ctx context.Context, email, password string)
 	}
 	recordAuditAttempt(ctx, s.audit, "user.login", "user", "", metadata)
 
+	ip := auditctx.FromContext(ctx).Request.IP
+	if s.limiter != nil {
+		lockedUntil, err := s.limiter.Check(ctx, email, ip)
+		if err != nil {
+			recordAuditFailure(ctx, s.audit, "user.login", "user", "", metadata, err)
+			return domain.User{}, err
+		}
+		if !lockedUntil.IsZero() {
+			lockErr := &ports.LockoutError{Until: lockedUntil}
+			recordAuditFailure(ctx, s.audit, "user.login", "user", "", metadata, lockErr)
+			return domain.User{}, lockErr
+		}
+	}
+
 	user, err := s.repo.FindByEmail(ctx, email)
 	if err != nil {
 		recordAuditFailure(ctx, s.audit, "user.login", "user", "", metadata, err)
+		if errors.Is(err, ports.ErrNotFound) {
+			return domain.User{}, s.registerLoginFailure(ctx, email, ip, "", err)
+		}
 		return domain.User{}, err
 	}
 
 	if !user.Active {
 		recordAuditFailure(ctx, s.audit, "user.login", "user", user.ID, metadata, ports.ErrUnauthorized)
-		return domain.User{}, ports.ErrUnauthorized
+		return domain.User{}, s.registerLoginFailure(ctx, email, ip, user.ID, ports.ErrUnauthorized)
 	}
 
 	if err := bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(password)); err != nil {
 		if errors.Is(err, bcrypt.ErrMismatchedHashAndPassword) {
 			recordAuditFailure(ctx, s.audit, "user.login", "user", user.ID, metadata, ports.ErrUnauthorized)
-			return domain.User{}, ports.ErrUnauthorized
+			return domain.User{}, s.registerLoginFailure(ctx, email, ip, user.ID, ports.ErrUnauthorized)
 		}
 		recordAuditFailure(ctx, s.audit, "user.login", "user", user.ID, metadata, err)
 		return domain.User{}, err
 	}
 
+	if s.limiter != nil {
+		pending := false
+		if s.mfa != nil {
+			pending, err = s.mfa.RequiresChallenge(ctx, user)
+			if err != nil {
+				recordAuditFailure(ctx, s.audit, "user.login", "user", user.ID, metadata, err)
+				return domain.User{}, err
+			}
+		}
+		if !pending {
+			if err := s.limiter.Reset(ctx, email); err != nil {
+				return domain.User{}, err
+			}
+		}
+	}
+
 	recordAuditSuccess(ctx, s.audit, "user.login", "user", user.ID, metadata)
 	return user, nil
 }
 
+// registerLoginFailure contabiliza a falha no limitador e devolve o erro a ser
+// retornado ao chamador, trocando-o pelo bloqueio quando o limite e atingido.
+func (s *AuthService) registerLoginFailure(ctx context.Context, email, ip, userID string, cause error) error {
+	if s.limiter == nil {
+		return cause
+	}
+
+	lockedUntil, err := s.limiter.RegisterFailure(ctx, email, ip)
+	if err != nil {
+		return err
+	}
+	if lockedUntil.IsZero() {
+		return cause
+	}
+
+	recordAudit(ctx, s.audit, "user.login.lockout", "user", userID, map[string]any{
+		"email":        email,
+		"locked_until": lockedUntil.Format(time.RFC3339),
+	})
+	return &ports.LockoutError{Until: lockedUntil}
+}
+
 func (s *AuthService) Logout(ctx context.Context, session ports.Session) error {
 	metadata := map[string]any{
 		"user_id": session.UserID,
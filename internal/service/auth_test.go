@@ -2,6 +2,7 @@ package service
 
 import (
 	"context"
+	"errors"
 	"testing"
 
 	"github.com/PabloPavan/jaiu/internal/domain"
@@ -96,3 +97,74 @@ func TestAuthServiceLogout(t *testing.T) {
 		t.Fatalf("unexpected error: %v", err)
 	}
 }
+
+// Testa Authenticate bloqueando o email apos falhas seguidas, mesmo com senha correta.
+func TestAuthServiceAuthenticateLockout(t *testing.T) {
+	hash, err := HashPassword("correct")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	repo := &userRepoFake{
+		users: map[string]domain.User{
+			"user@example.com": {ID: "user-1", Email: "user@example.com", Active: true, PasswordHash: hash},
+		},
+	}
+	audit := &auditRepoFake{}
+	service := NewAuthService(repo, audit)
+	policy := DefaultLoginLimitPolicy()
+	policy.MaxEmailFailures = 2
+	service.SetLoginLimiter(NewLoginLimiter(&loginAttemptStoreFake{}, policy))
+	ctx := context.Background()
+
+	if _, err := service.Authenticate(ctx, "user@example.com", "wrong"); err != ports.ErrUnauthorized {
+		t.Fatalf("expected unauthorized, got %v", err)
+	}
+	_, err = service.Authenticate(ctx, "user@example.com", "wrong")
+	var lockErr *ports.LockoutError
+	if !errors.As(err, &lockErr) || !errors.Is(err, ports.ErrTooManyAttempts) {
+		t.Fatalf("expected lockout error, got %v", err)
+	}
+	if _, err := service.Authenticate(ctx, "user@example.com", "correct"); !errors.Is(err, ports.ErrTooManyAttempts) {
+		t.Fatalf("expected lockout with correct password, got %v", err)
+	}
+
+	found := false
+	for _, event := range audit.events {
+		if event.Action == "user.login.lockout" {
+			found = true
+			if event.EntityID != "user-1" {
+				t.Fatalf("expected lockout for user-1, got %q", event.EntityID)
+			}
+		}
+	}
+	if !found {
+		t.Fatal("expected user.login.lockout audit event")
+	}
+}
+
+// Testa que login bem sucedido zera as falhas do email.
+func TestAuthServiceAuthenticateResetsFailures(t *testing.T) {
+	hash, err := HashPassword("correct")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	repo := &userRepoFake{
+		users: map[string]domain.User{
+			"user@example.com": {ID: "user-1", Email: "user@example.com", Active: true, PasswordHash: hash},
+		},
+	}
+	store := &loginAttemptStoreFake{}
+	service := NewAuthService(repo, nil)
+	service.SetLoginLimiter(NewLoginLimiter(store, DefaultLoginLimitPolicy()))
+	ctx := context.Background()
+
+	if _, err := service.Authenticate(ctx, "user@example.com", "wrong"); err != ports.ErrUnauthorized {
+		t.Fatalf("expected unauthorized, got %v", err)
+	}
+	if _, err := service.Authenticate(ctx, "user@example.com", "correct"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, ok := store.attempts["email:user@example.com"]; ok {
+		t.Fatalf("expected email failures reset, got %#v", store.attempts)
+	}
+}
@@ -0,0 +1,24 @@
+package service
+
+import (
+	"context"
+
+	"github.com/PabloPavan/jaiu/internal/domain"
+	"github.com/PabloPavan/jaiu/internal/ports"
+)
+
+type AuthorizationService struct {
+	audit ports.AuditRepository
+}
+
+func NewAuthorizationService(audit ports.AuditRepository) *AuthorizationService {
+	return &AuthorizationService{audit: audit}
+}
+
+func (s *AuthorizationService) RecordDenied(ctx context.Context, permission domain.Permission, metadata map[string]any) {
+	recordAudit(ctx, s.audit, string(permission)+".denied", permission.EntityType(), "", metadata)
+}
+
+func (s *AuthorizationService) RecordCSRFFailure(ctx context.Context, metadata map[string]any) {
+	recordAudit(ctx, s.audit, "session.csrf.denied", "session", "", metadata)
+}
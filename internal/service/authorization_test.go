@@ -0,0 +1,52 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/PabloPavan/jaiu/internal/auditctx"
+	"github.com/PabloPavan/jaiu/internal/domain"
+)
+
+// Testa o registro de auditoria para acesso negado.
+func TestAuthorizationServiceRecordDenied(t *testing.T) {
+	audit := &auditRepoFake{}
+	service := NewAuthorizationService(audit)
+
+	ctx := auditctx.WithActor(context.Background(), auditctx.Actor{ID: "user-1", Role: string(domain.RoleOperator)})
+	service.RecordDenied(ctx, domain.PermissionPlanDelete, map[string]any{"path": "/plans/1/delete"})
+
+	if len(audit.events) != 1 {
+		t.Fatalf("expected 1 audit event, got %d", len(audit.events))
+	}
+	event := audit.events[0]
+	if event.Action != "plan.delete.denied" || event.EntityType != "plan" {
+		t.Fatalf("unexpected audit event: %#v", event)
+	}
+	if event.ActorID != "user-1" || event.ActorRole != string(domain.RoleOperator) {
+		t.Fatalf("expected actor to be recorded, got %#v", event)
+	}
+	if event.Metadata["path"] != "/plans/1/delete" {
+		t.Fatalf("expected path metadata, got %#v", event.Metadata)
+	}
+}
+
+// Testa o registro de auditoria para token CSRF invalido.
+func TestAuthorizationServiceRecordCSRFFailure(t *testing.T) {
+	audit := &auditRepoFake{}
+	service := NewAuthorizationService(audit)
+
+	ctx := auditctx.WithActor(context.Background(), auditctx.Actor{ID: "user-1", Role: string(domain.RoleOperator)})
+	service.RecordCSRFFailure(ctx, map[string]any{"path": "/plans", "reason": "missing"})
+
+	if len(audit.events) != 1 {
+		t.Fatalf("expected 1 audit event, got %d", len(audit.events))
+	}
+	event := audit.events[0]
+	if event.Action != "session.csrf.denied" || event.EntityType != "session" {
+		t.Fatalf("unexpected audit event: %#v", event)
+	}
+	if event.ActorID != "user-1" || event.Metadata["reason"] != "missing" {
+		t.Fatalf("unexpected audit event: %#v", event)
+	}
+}
@@ -46,7 +46,7 @@ func applySubscriptionBalance(
 		if remaining == 0 {
 			break
 		}
-		due := period.AmountDueCents - period.AmountPaidCents
+		due := period.OutstandingCents()
 		if due <= 0 {
 			continue
 		}
@@ -57,7 +57,7 @@ func applySubscriptionBalance(
 		}
 
 		period.AmountPaidCents += applied
-		period.Status = resolvePeriodStatus(period, today, paymentDay)
+		period = settlePeriod(period, today, paymentDay)
 		if _, err := periods.Update(ctx, period); err != nil {
 			return err
 		}
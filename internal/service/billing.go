@@ -12,10 +12,10 @@ import (
 
 func ensureBillingPeriods(ctx context.Context, repo ports.BillingPeriodRepository, subscription domain.Subscription, plan domain.Plan, today time.Time) ([]domain.BillingPeriod, error) {
 	if subscription.StartDate.IsZero() {
-		return nil, errors.New("data de inicio da assinatura invalida")
+		return nil, validationError("data de inicio da assinatura invalida")
 	}
 	if plan.DurationDays <= 0 {
-		return nil, errors.New("duracao do plano invalida")
+		return nil, validationError("duracao do plano invalida")
 	}
 
 	priceCents, err := effectivePriceCents(subscription, plan)
@@ -34,9 +34,8 @@ func ensureBillingPeriods(ctx context.Context, repo ports.BillingPeriodRepositor
 	}
 
 	if len(periods) == 0 {
-		first := buildPeriod(subscription.ID, subscription.StartDate, plan.DurationDays, priceCents)
-		first.Status = resolvePeriodStatus(first, today, paymentDay)
-		created, err := repo.Create(ctx, first)
+		cycle := buildCycle(subscription.ID, subscription.StartDate, plan, priceCents, periodDiscountCents(subscription.Discount, nil, priceCents), paymentDay, 0)
+		created, err := createCycle(ctx, repo, cycle, today, paymentDay)
 		if err != nil {
 			if errors.Is(err, ports.ErrConflict) {
 				periods, err = repo.ListBySubscription(ctx, subscription.ID)
@@ -50,7 +49,7 @@ func ensureBillingPeriods(ctx context.Context, repo ports.BillingPeriodRepositor
 				return nil, err
 			}
 		} else {
-			periods = append(periods, created)
+			periods = append(periods, created...)
 		}
 	}
 
@@ -59,7 +58,7 @@ func ensureBillingPeriods(ctx context.Context, repo ports.BillingPeriodRepositor
 	})
 
 	if subscription.AutoRenew {
-		periods, err = ensureRenewals(ctx, repo, periods, subscription, plan.DurationDays, priceCents, today, paymentDay)
+		periods, err = ensureRenewals(ctx, repo, periods, subscription, plan, priceCents, today, paymentDay)
 		if err != nil {
 			return nil, err
 		}
@@ -71,10 +70,9 @@ func ensureBillingPeriods(ctx context.Context, repo ports.BillingPeriodRepositor
 func refreshPeriodStatuses(ctx context.Context, repo ports.BillingPeriodRepository, periods []domain.BillingPeriod, paymentDay int, today time.Time) ([]domain.BillingPeriod, error) {
 	updated := make([]domain.BillingPeriod, 0, len(periods))
 	for _, period := range periods {
-		status := resolvePeriodStatus(period, today, paymentDay)
-		if status != period.Status {
-			period.Status = status
-			saved, err := repo.Update(ctx, period)
+		settled := settlePeriod(period, today, paymentDay)
+		if settled.Status != period.Status || !settled.DueDate.Equal(period.DueDate) {
+			saved, err := repo.Update(ctx, settled)
 			if err != nil {
 				return nil, err
 			}
@@ -98,7 +96,7 @@ func ensureRenewals(
 	repo ports.BillingPeriodRepository,
 	periods []domain.BillingPeriod,
 	subscription domain.Subscription,
-	durationDays int,
+	plan domain.Plan,
 	priceCents int64,
 	today time.Time,
 	paymentDay int,
@@ -117,15 +115,14 @@ func ensureRenewals(
 	})
 
 	last := periods[len(periods)-1]
-	nextStart := renewalDateForPeriod(last.PeriodStart, paymentDay)
+	nextStart := renewalDateForPeriod(last, paymentDay)
 	for !nextStart.After(today) {
 		key := dateKey(nextStart)
 		if existing, ok := periodByStart[key]; ok {
 			last = existing
 		} else {
-			future := buildPeriod(subscription.ID, nextStart, durationDays, priceCents)
-			future.Status = resolvePeriodStatus(future, today, paymentDay)
-			created, err := repo.Create(ctx, future)
+			cycle := buildCycle(subscription.ID, nextStart, plan, priceCents, periodDiscountCents(subscription.Discount, periods, priceCents), paymentDay, last.ShiftDays+last.FrozenDays)
+			created, err := createCycle(ctx, repo, cycle, today, paymentDay)
 			if err != nil {
 				if errors.Is(err, ports.ErrConflict) {
 					refreshed, err := repo.ListBySubscription(ctx, subscription.ID)
@@ -142,16 +139,18 @@ func ensureRenewals(
 						return periods, errors.New("periodo de cobranca indisponivel")
 					}
 					last = existing
-					nextStart = renewalDateForPeriod(last.PeriodStart, paymentDay)
+					nextStart = renewalDateForPeriod(last, paymentDay)
 					continue
 				}
 				return periods, err
 			}
-			periods = append(periods, created)
-			periodByStart[key] = created
-			last = created
+			periods = append(periods, created...)
+			for _, period := range created {
+				periodByStart[dateKey(period.PeriodStart)] = period
+			}
+			last = created[len(created)-1]
 		}
-		nextStart = renewalDateForPeriod(last.PeriodStart, paymentDay)
+		nextStart = renewalDateForPeriod(last, paymentDay)
 	}
 
 	sort.Slice(periods, func(i, j int) bool {
@@ -161,11 +160,93 @@ func ensureRenewals(
 	return periods, nil
 }
 
+// buildCycle monta as competencias de um ciclo do plano que comeca em start.
+// Sem parcelamento o ciclo e uma competencia so. Com parcelamento o preco e o
+// desconto sao divididos em parcelas mensais: cada parcela vence no dia de
+// pagamento seguinte ao inicio dela, a proxima comeca no dia seguinte ao
+// vencimento e a ultima termina junto com o ciclo, que continua durando
+// DurationDays.
+func buildCycle(subscriptionID string, start time.Time, plan domain.Plan, priceCents, discountCents int64, paymentDay, shiftDays int) []domain.BillingPeriod {
+	installments := plan.InstallmentCount()
+	if installments == 1 {
+		period := buildPeriod(subscriptionID, start, plan.DurationDays, priceCents, discountCents)
+		period.ShiftDays = shiftDays
+		return []domain.BillingPeriod{period}
+	}
+
+	cycleEnd := dateOnly(start).AddDate(0, 0, plan.DurationDays)
+	prices := splitInstallments(priceCents, installments)
+	discounts := splitInstallments(discountCents, installments)
+	cycle := make([]domain.BillingPeriod, 0, installments)
+	next := dateOnly(start)
+	for i := 0; i < installments; i++ {
+		period := buildPeriod(subscriptionID, next, 0, prices[i], discounts[i])
+		period.ShiftDays = shiftDays
+		period.Installment = i + 1
+		period.Installments = installments
+		next = periodDueDate(period, paymentDay).AddDate(0, 0, 1)
+		period.PeriodEnd = next
+		if period.Installment == installments && cycleEnd.After(period.PeriodStart) {
+			period.PeriodEnd = cycleEnd
+		}
+		cycle = append(cycle, period)
+	}
+	return cycle
+}
+
+// splitInstallments divide total em parcelas iguais; os centavos que sobram
+// da divisao ficam na primeira parcela.
+func splitInstallments(total int64, installments int) []int64 {
+	parts := make([]int64, installments)
+	base := total / int64(installments)
+	for i := range parts {
+		parts[i] = base
+	}
+	parts[0] += total - base*int64(installments)
+	return parts
+}
+
+// createCycle grava as competencias do ciclo com o status do dia.
+func createCycle(ctx context.Context, repo ports.BillingPeriodRepository, cycle []domain.BillingPeriod, today time.Time, paymentDay int) ([]domain.BillingPeriod, error) {
+	created := make([]domain.BillingPeriod, 0, len(cycle))
+	for _, period := range cycle {
+		period = settlePeriod(period, today, paymentDay)
+		saved, err := repo.Create(ctx, period)
+		if err != nil {
+			return nil, err
+		}
+		created = append(created, saved)
+	}
+	return created, nil
+}
+
+// periodDiscountCents calcula o desconto do proximo ciclo. O indice do
+// desconto conta apenas os ciclos que ja receberam desconto, entao um cupom
+// aplicado depois do inicio vale para os proximos ciclos gerados. Em planos
+// parcelados o ciclo e contado pela primeira parcela.
+func periodDiscountCents(discount domain.Discount, periods []domain.BillingPeriod, priceCents int64) int64 {
+	if !discount.AppliesTo(discountedCycles(periods)) {
+		return 0
+	}
+	return discount.AmountCents(priceCents)
+}
+
+// discountedCycles conta os ciclos que ja receberam desconto.
+func discountedCycles(periods []domain.BillingPeriod) int {
+	applied := 0
+	for _, period := range periods {
+		if period.DiscountCents > 0 && period.Installment <= 1 {
+			applied++
+		}
+	}
+	return applied
+}
+
 func effectivePriceCents(subscription domain.Subscription, plan domain.Plan) (int64, error) {
 	priceCents := subscription.PriceCents
 	if priceCents <= 0 {
 		if plan.PriceCents <= 0 {
-			return 0, errors.New("valor do plano invalido")
+			return 0, validationError("valor do plano invalido")
 		}
 		priceCents = plan.PriceCents
 	}
@@ -175,18 +256,42 @@ func effectivePriceCents(subscription domain.Subscription, plan domain.Plan) (in
 func effectivePaymentDay(subscription domain.Subscription) (int, error) {
 	if subscription.PaymentDay <= 0 {
 		if subscription.StartDate.IsZero() {
-			return 0, errors.New("dia do pagamento invalido")
+			return 0, validationError("dia do pagamento invalido")
 		}
 		return subscription.StartDate.Day(), nil
 	}
 	if subscription.PaymentDay < 1 || subscription.PaymentDay > 31 {
-		return 0, errors.New("dia do pagamento invalido")
+		return 0, validationError("dia do pagamento invalido")
 	}
 	return subscription.PaymentDay, nil
 }
 
-func renewalDateForPeriod(start time.Time, paymentDay int) time.Time {
-	return dueDateForPeriod(start, paymentDay).AddDate(0, 0, 1)
+// renewalDateForPeriod e o inicio da competencia seguinte, o dia depois do
+// vencimento. Depois da ultima parcela o proximo ciclo so comeca quando o
+// ciclo atual termina.
+func renewalDateForPeriod(period domain.BillingPeriod, paymentDay int) time.Time {
+	next := periodDueDate(period, paymentDay).AddDate(0, 0, 1)
+	if period.Installments > 1 && period.Installment >= period.Installments && period.PeriodEnd.After(next) {
+		return dateOnly(period.PeriodEnd)
+	}
+	return next
+}
+
+// periodDueDate e o vencimento da competencia considerando os trancamentos:
+// ShiftDays desloca o calendario de pagamento a partir do inicio original e
+// FrozenDays adia o vencimento da competencia que foi trancada.
+// settlePeriod recalcula o status e o vencimento gravado da competencia. O
+// vencimento gravado vem sempre de periodDueDate, entao as consultas que
+// filtram por due_date seguem as mesmas regras da cobranca.
+func settlePeriod(period domain.BillingPeriod, today time.Time, paymentDay int) domain.BillingPeriod {
+	period.DueDate = periodDueDate(period, paymentDay)
+	period.Status = resolvePeriodStatus(period, today, paymentDay)
+	return period
+}
+
+func periodDueDate(period domain.BillingPeriod, paymentDay int) time.Time {
+	anchor := dateOnly(period.PeriodStart).AddDate(0, 0, -period.ShiftDays)
+	return dueDateForPeriod(anchor, paymentDay).AddDate(0, 0, period.ShiftDays+period.FrozenDays)
 }
 
 func dueDateForPeriod(start time.Time, paymentDay int) time.Time {
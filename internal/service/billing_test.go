@@ -81,6 +81,9 @@ func TestEnsureBillingPeriodsCreatesFirst(t *testing.T) {
 	if periods[0].AmountDueCents != 1000 {
 		t.Fatalf("expected amount due 1000, got %d", periods[0].AmountDueCents)
 	}
+	if !periods[0].DueDate.Equal(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Fatalf("expected due date stored on creation, got %v", periods[0].DueDate)
+	}
 }
 
 // Testa criacao de renovacoes quando auto renew esta ativo.
@@ -113,6 +116,45 @@ func TestEnsureBillingPeriodsAutoRenew(t *testing.T) {
 	}
 }
 
+// Testa o desconto aplicado apenas nas primeiras competencias geradas.
+func TestEnsureBillingPeriodsAppliesDiscount(t *testing.T) {
+	repo := &billingPeriodRepoFake{}
+	subscription := domain.Subscription{
+		ID:         "sub-1",
+		StartDate:  time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+		PaymentDay: 1,
+		AutoRenew:  true,
+		Discount: domain.Discount{
+			Kind:     domain.DiscountPercent,
+			Value:    1000,
+			Duration: domain.DiscountRepeating,
+			Periods:  2,
+		},
+	}
+	plan := domain.Plan{DurationDays: 30, PriceCents: 1000}
+	today := time.Date(2024, 4, 15, 0, 0, 0, 0, time.UTC)
+
+	periods, err := ensureBillingPeriods(context.Background(), repo, subscription, plan, today)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(periods) < 3 {
+		t.Fatalf("expected at least 3 periods, got %d", len(periods))
+	}
+	for i, period := range periods {
+		wantDiscount := int64(0)
+		if i < 2 {
+			wantDiscount = 100
+		}
+		if period.DiscountCents != wantDiscount || period.AmountDueCents != 1000-wantDiscount {
+			t.Fatalf("period %d: expected discount %d, got due=%d discount=%d", i, wantDiscount, period.AmountDueCents, period.DiscountCents)
+		}
+		if period.GrossCents() != 1000 {
+			t.Fatalf("period %d: expected gross 1000, got %d", i, period.GrossCents())
+		}
+	}
+}
+
 // Testa atualizacao de status quando periodo fica vencido.
 func TestRefreshPeriodStatuses(t *testing.T) {
 	repo := &billingPeriodRepoFake{
@@ -136,4 +178,103 @@ func TestRefreshPeriodStatuses(t *testing.T) {
 	if updated[0].Status != domain.BillingOverdue {
 		t.Fatalf("expected overdue, got %q", updated[0].Status)
 	}
+	if !repo.periods["period-1"].DueDate.Equal(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Fatalf("expected due date stored with the status, got %v", repo.periods["period-1"].DueDate)
+	}
+}
+
+// Testa que um vencimento gravado desatualizado e corrigido mesmo sem mudar o status.
+func TestRefreshPeriodStatusesFixesStaleDueDate(t *testing.T) {
+	repo := &billingPeriodRepoFake{
+		periods: map[string]domain.BillingPeriod{
+			"period-1": {
+				ID:             "period-1",
+				SubscriptionID: "sub-1",
+				PeriodStart:    time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+				AmountDueCents: 1000,
+				Status:         domain.BillingOpen,
+				DueDate:        time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+			},
+		},
+	}
+	periods := []domain.BillingPeriod{repo.periods["period-1"]}
+
+	if _, err := refreshPeriodStatuses(context.Background(), repo, periods, 10, time.Date(2024, 1, 5, 0, 0, 0, 0, time.UTC)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !repo.periods["period-1"].DueDate.Equal(time.Date(2024, 1, 10, 0, 0, 0, 0, time.UTC)) {
+		t.Fatalf("expected due date to follow the payment day, got %v", repo.periods["period-1"].DueDate)
+	}
+}
+
+// Testa a divisao do preco em parcelas com os centavos que sobram na primeira.
+func TestSplitInstallments(t *testing.T) {
+	parts := splitInstallments(100001, 12)
+	if len(parts) != 12 || parts[0] != 8338 || parts[1] != 8333 || parts[11] != 8333 {
+		t.Fatalf("unexpected installments: %v", parts)
+	}
+	var total int64
+	for _, part := range parts {
+		total += part
+	}
+	if total != 100001 {
+		t.Fatalf("expected the installments to sum 100001, got %d", total)
+	}
+}
+
+// Testa o plano anual parcelado em 12 vezes com vencimentos mensais no dia de pagamento.
+func TestEnsureBillingPeriodsInstallments(t *testing.T) {
+	repo := &billingPeriodRepoFake{}
+	subscription := domain.Subscription{
+		ID:         "sub-1",
+		StartDate:  time.Date(2024, 1, 11, 0, 0, 0, 0, time.UTC),
+		PaymentDay: 10,
+		AutoRenew:  true,
+		Discount:   domain.Discount{Kind: domain.DiscountPercent, Value: 1000, Duration: domain.DiscountOnce},
+	}
+	plan := domain.Plan{DurationDays: 365, PriceCents: 120001, Installments: 12}
+
+	periods, err := ensureBillingPeriods(context.Background(), repo, subscription, plan, time.Date(2024, 1, 11, 0, 0, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(periods) != 12 {
+		t.Fatalf("expected 12 installments, got %d", len(periods))
+	}
+	var gross, discount int64
+	for i, period := range periods {
+		if period.Installment != i+1 || period.Installments != 12 {
+			t.Fatalf("period %d: unexpected installment %d/%d", i, period.Installment, period.Installments)
+		}
+		want := time.Date(2024, time.Month(2+i), 10, 0, 0, 0, 0, time.UTC)
+		if due := periodDueDate(period, 10); !due.Equal(want) {
+			t.Fatalf("period %d: expected due %s, got %s", i, want.Format("2006-01-02"), due.Format("2006-01-02"))
+		}
+		gross += period.GrossCents()
+		discount += period.DiscountCents
+	}
+	if gross != 120001 || discount != 12000 {
+		t.Fatalf("expected the installments to sum the price and the discount, got gross=%d discount=%d", gross, discount)
+	}
+	if periods[0].AmountDueCents != 9001 || periods[1].AmountDueCents != 9000 {
+		t.Fatalf("expected the rounding cents on the first installment, got %d and %d", periods[0].AmountDueCents, periods[1].AmountDueCents)
+	}
+	if !periods[11].PeriodEnd.Equal(time.Date(2025, 1, 10, 0, 0, 0, 0, time.UTC)) {
+		t.Fatalf("expected the last installment to end with the plan, got %v", periods[11].PeriodEnd)
+	}
+
+	renewed, err := ensureBillingPeriods(context.Background(), repo, subscription, plan, time.Date(2025, 1, 11, 0, 0, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(renewed) != 24 {
+		t.Fatalf("expected a second cycle of 12 installments, got %d", len(renewed))
+	}
+	next := renewed[12]
+	if !next.PeriodStart.Equal(time.Date(2025, 1, 11, 0, 0, 0, 0, time.UTC)) || next.Installment != 1 || next.DiscountCents != 0 {
+		t.Fatalf("unexpected first installment of the second cycle: %#v", next)
+	}
+	if due := periodDueDate(next, 10); !due.Equal(time.Date(2025, 2, 10, 0, 0, 0, 0, time.UTC)) {
+		t.Fatalf("expected the second cycle to keep the monthly due dates, got %s", due.Format("2006-01-02"))
+	}
 }
@@ -0,0 +1,132 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/PabloPavan/jaiu/internal/domain"
+	"github.com/PabloPavan/jaiu/internal/ports"
+)
+
+// maxCouponCodeLength limita o codigo digitado no balcao e na API.
+const maxCouponCodeLength = 32
+
+type CouponService struct {
+	repo  ports.CouponRepository
+	audit ports.AuditRepository
+	now   func() time.Time
+}
+
+func NewCouponService(repo ports.CouponRepository, audit ports.AuditRepository) *CouponService {
+	return &CouponService{repo: repo, audit: audit, now: time.Now}
+}
+
+func (s *CouponService) List(ctx context.Context) ([]domain.Coupon, error) {
+	return s.repo.List(ctx)
+}
+
+func (s *CouponService) FindByID(ctx context.Context, couponID string) (domain.Coupon, error) {
+	return s.repo.FindByID(ctx, couponID)
+}
+
+func (s *CouponService) FindByCode(ctx context.Context, code string) (domain.Coupon, error) {
+	code = normalizeCouponCode(code)
+	if code == "" {
+		return domain.Coupon{}, ports.ErrNotFound
+	}
+	return s.repo.FindByCode(ctx, code)
+}
+
+// Create cadastra um cupom ativo. O desconto nao pode ser alterado depois,
+// apenas desativado, para que o historico das assinaturas continue coerente.
+func (s *CouponService) Create(ctx context.Context, coupon domain.Coupon) (domain.Coupon, error) {
+	coupon.Code = normalizeCouponCode(coupon.Code)
+	coupon.Description = strings.TrimSpace(coupon.Description)
+	coupon.Active = true
+	if coupon.Discount.Duration != domain.DiscountRepeating {
+		coupon.Discount.Periods = 0
+	}
+
+	metadata := couponAuditMetadata(coupon)
+	recordAuditAttempt(ctx, s.audit, "coupon.create", "coupon", "", metadata)
+
+	if err := validateCoupon(coupon); err != nil {
+		recordAuditFailure(ctx, s.audit, "coupon.create", "coupon", "", metadata, err)
+		return domain.Coupon{}, err
+	}
+
+	now := s.now()
+	coupon.CreatedAt = now
+	coupon.UpdatedAt = now
+
+	created, err := s.repo.Create(ctx, coupon)
+	if err != nil {
+		recordAuditFailure(ctx, s.audit, "coupon.create", "coupon", "", metadata, err)
+		return domain.Coupon{}, err
+	}
+
+	recordAuditSuccess(ctx, s.audit, "coupon.create", "coupon", created.ID, metadata)
+	return created, nil
+}
+
+// Deactivate impede novos usos do cupom. Assinaturas que ja o usam mantem o
+// desconto copiado.
+func (s *CouponService) Deactivate(ctx context.Context, couponID string) (domain.Coupon, error) {
+	metadata := map[string]any{
+		"active": false,
+	}
+	recordAuditAttempt(ctx, s.audit, "coupon.deactivate", "coupon", couponID, metadata)
+
+	updated, err := s.repo.SetActive(ctx, couponID, false)
+	if err != nil {
+		recordAuditFailure(ctx, s.audit, "coupon.deactivate", "coupon", couponID, metadata, err)
+		return domain.Coupon{}, err
+	}
+
+	recordAuditSuccess(ctx, s.audit, "coupon.deactivate", "coupon", updated.ID, metadata)
+	return updated, nil
+}
+
+func normalizeCouponCode(code string) string {
+	return strings.ToUpper(strings.TrimSpace(code))
+}
+
+func validateCoupon(coupon domain.Coupon) error {
+	if coupon.Code == "" {
+		return validationError("codigo do cupom e obrigatorio")
+	}
+	if len(coupon.Code) > maxCouponCodeLength || strings.ContainsAny(coupon.Code, " \t\n") {
+		return validationError("codigo do cupom invalido")
+	}
+	return validateDiscount(coupon.Discount)
+}
+
+func validateDiscount(discount domain.Discount) error {
+	if !discount.Kind.IsValid() {
+		return validationError("tipo de desconto invalido")
+	}
+	if discount.Value <= 0 {
+		return validationError("valor do desconto deve ser maior que zero")
+	}
+	if discount.Kind == domain.DiscountPercent && discount.Value > domain.MaxBasisPoints {
+		return validationError("desconto percentual deve ser no maximo 100%")
+	}
+	if !discount.Duration.IsValid() {
+		return validationError("duracao do desconto invalida")
+	}
+	if discount.Duration == domain.DiscountRepeating && discount.Periods <= 0 {
+		return validationError("informe o numero de competencias com desconto")
+	}
+	return nil
+}
+
+func couponAuditMetadata(coupon domain.Coupon) map[string]any {
+	return map[string]any{
+		"code":              coupon.Code,
+		"discount_kind":     string(coupon.Discount.Kind),
+		"discount_value":    coupon.Discount.Value,
+		"discount_duration": string(coupon.Discount.Duration),
+		"discount_periods":  coupon.Discount.Periods,
+	}
+}
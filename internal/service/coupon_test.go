@@ -0,0 +1,126 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/PabloPavan/jaiu/internal/domain"
+	"github.com/PabloPavan/jaiu/internal/ports"
+)
+
+// Testa o cadastro de cupom normalizando o codigo e rejeitando duplicados.
+func TestCouponServiceCreate(t *testing.T) {
+	repo := &couponRepoFake{}
+	audit := &auditRepoFake{}
+	service := NewCouponService(repo, audit)
+
+	coupon, err := service.Create(context.Background(), domain.Coupon{
+		Code: " bemvindo10 ",
+		Discount: domain.Discount{
+			Kind:     domain.DiscountPercent,
+			Value:    1000,
+			Duration: domain.DiscountOnce,
+			Periods:  3,
+		},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if coupon.Code != "BEMVINDO10" || !coupon.Active || coupon.Discount.Periods != 0 {
+		t.Fatalf("unexpected coupon: %#v", coupon)
+	}
+	last := audit.events[len(audit.events)-1]
+	if last.Action != "coupon.create.success" || last.EntityID != coupon.ID {
+		t.Fatalf("unexpected audit event: %#v", last)
+	}
+
+	_, err = service.Create(context.Background(), domain.Coupon{
+		Code:     "BemVindo10",
+		Discount: domain.Discount{Kind: domain.DiscountFixed, Value: 500, Duration: domain.DiscountForever},
+	})
+	if err != ports.ErrConflict {
+		t.Fatalf("expected conflict, got %v", err)
+	}
+}
+
+// Testa as regras de validacao do desconto.
+func TestValidateDiscount(t *testing.T) {
+	cases := []struct {
+		name     string
+		discount domain.Discount
+		valid    bool
+	}{
+		{"percent", domain.Discount{Kind: domain.DiscountPercent, Value: 10000, Duration: domain.DiscountForever}, true},
+		{"repeating", domain.Discount{Kind: domain.DiscountFixed, Value: 500, Duration: domain.DiscountRepeating, Periods: 3}, true},
+		{"missing kind", domain.Discount{Value: 500, Duration: domain.DiscountOnce}, false},
+		{"zero value", domain.Discount{Kind: domain.DiscountFixed, Duration: domain.DiscountOnce}, false},
+		{"over 100%", domain.Discount{Kind: domain.DiscountPercent, Value: 10001, Duration: domain.DiscountOnce}, false},
+		{"missing duration", domain.Discount{Kind: domain.DiscountFixed, Value: 500}, false},
+		{"repeating without periods", domain.Discount{Kind: domain.DiscountFixed, Value: 500, Duration: domain.DiscountRepeating}, false},
+	}
+	for _, tc := range cases {
+		if err := validateDiscount(tc.discount); (err == nil) != tc.valid {
+			t.Fatalf("%s: expected valid=%v, got %v", tc.name, tc.valid, err)
+		}
+	}
+}
+
+// Testa que a assinatura copia o desconto do cupom e o mantem apos a
+// desativacao do cupom.
+func TestSubscriptionServiceAppliesCoupon(t *testing.T) {
+	discount := domain.Discount{Kind: domain.DiscountFixed, Value: 500, Duration: domain.DiscountRepeating, Periods: 2}
+	coupons := &couponRepoFake{
+		coupons: map[string]domain.Coupon{
+			"coupon-1": {ID: "coupon-1", Code: "PROMO", Discount: discount, Active: true},
+			"coupon-2": {ID: "coupon-2", Code: "OLD", Discount: discount, Active: false},
+		},
+	}
+	subRepo := &subscriptionRepoFake{}
+	planRepo := &planRepoFake{
+		plans: map[string]domain.Plan{
+			"plan-1": {ID: "plan-1", DurationDays: 30, PriceCents: 10000},
+		},
+	}
+	service := NewSubscriptionService(subRepo, planRepo, nil, nil)
+	service.SetCoupons(coupons)
+	service.now = func() time.Time { return time.Date(2024, 1, 10, 12, 0, 0, 0, time.UTC) }
+
+	created, err := service.Create(context.Background(), domain.Subscription{
+		StudentID: "student-1",
+		PlanID:    "plan-1",
+		CouponID:  "coupon-1",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if created.Discount != discount {
+		t.Fatalf("expected coupon discount, got %#v", created.Discount)
+	}
+
+	if _, err := service.Create(context.Background(), domain.Subscription{
+		StudentID: "student-1",
+		PlanID:    "plan-1",
+		CouponID:  "coupon-2",
+	}); err == nil {
+		t.Fatal("expected error for inactive coupon")
+	}
+
+	coupons.coupons["coupon-1"] = domain.Coupon{ID: "coupon-1", Code: "PROMO", Discount: discount, Active: false}
+	updated, err := service.Update(context.Background(), created)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if updated.Discount != discount {
+		t.Fatalf("expected discount to be kept, got %#v", updated.Discount)
+	}
+
+	updated.CouponID = ""
+	cleared, err := service.Update(context.Background(), updated)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !cleared.Discount.IsZero() {
+		t.Fatalf("expected discount to be removed, got %#v", cleared.Discount)
+	}
+}
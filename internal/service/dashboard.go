@@ -0,0 +1,49 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/PabloPavan/jaiu/internal/ports"
+)
+
+type DashboardService struct {
+	repo    ports.DashboardRepository
+	reports ports.ReportRepository
+}
+
+func NewDashboardService(repo ports.DashboardRepository, reports ports.ReportRepository) *DashboardService {
+	return &DashboardService{repo: repo, reports: reports}
+}
+
+func (s *DashboardService) Metrics(ctx context.Context, now time.Time) (ports.DashboardMetrics, error) {
+	activeStudents, err := s.repo.CountActiveStudents(ctx)
+	if err != nil {
+		return ports.DashboardMetrics{}, err
+	}
+
+	delinquent, err := s.repo.CountOverdueSubscriptions(ctx)
+	if err != nil {
+		return ports.DashboardMetrics{}, err
+	}
+
+	monthStart := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, now.Location())
+	monthEnd := monthStart.AddDate(0, 1, 0)
+
+	revenue, err := s.reports.RevenueByPeriod(ctx, monthStart, now)
+	if err != nil {
+		return ports.DashboardMetrics{}, err
+	}
+
+	outstanding, err := s.repo.OutstandingDue(ctx, monthStart, monthEnd)
+	if err != nil {
+		return ports.DashboardMetrics{}, err
+	}
+
+	return ports.DashboardMetrics{
+		ActiveStudents:          activeStudents,
+		DelinquentSubscriptions: delinquent,
+		RevenueMonthCents:       revenue.TotalCents,
+		ProjectedRevenueCents:   outstanding,
+	}, nil
+}
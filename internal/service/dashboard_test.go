@@ -0,0 +1,51 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/PabloPavan/jaiu/internal/ports"
+)
+
+// Testa a composicao das metricas do dashboard no mes corrente.
+func TestDashboardServiceMetrics(t *testing.T) {
+	repo := &dashboardRepoFake{activeStudents: 12, overdue: 3, outstanding: 4500}
+	reports := &reportRepoFake{revenue: ports.RevenueSummary{TotalCents: 9000}}
+	service := NewDashboardService(repo, reports)
+
+	now := time.Date(2024, 3, 14, 15, 0, 0, 0, time.UTC)
+	got, err := service.Metrics(context.Background(), now)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := ports.DashboardMetrics{
+		ActiveStudents:          12,
+		DelinquentSubscriptions: 3,
+		RevenueMonthCents:       9000,
+		ProjectedRevenueCents:   4500,
+	}
+	if got != want {
+		t.Fatalf("expected %#v, got %#v", want, got)
+	}
+
+	monthStart := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	if len(reports.revenueStarts) != 1 || !reports.revenueStarts[0].Equal(monthStart) {
+		t.Fatalf("expected revenue from %v, got %v", monthStart, reports.revenueStarts)
+	}
+	if !repo.outstandingStart.Equal(monthStart) || !repo.outstandingEnd.Equal(monthStart.AddDate(0, 1, 0)) {
+		t.Fatalf("unexpected outstanding range: %v - %v", repo.outstandingStart, repo.outstandingEnd)
+	}
+}
+
+// Testa propagacao de erro do repositorio do dashboard.
+func TestDashboardServiceMetricsError(t *testing.T) {
+	repo := &dashboardRepoFake{err: errors.New("boom")}
+	service := NewDashboardService(repo, &reportRepoFake{})
+
+	if _, err := service.Metrics(context.Background(), time.Now()); err == nil {
+		t.Fatal("expected error")
+	}
+}
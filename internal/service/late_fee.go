@@ -0,0 +1,97 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/PabloPavan/jaiu/internal/domain"
+	"github.com/PabloPavan/jaiu/internal/ports"
+)
+
+const lateInterestDaysPerMonth = 30
+
+// applyLateFees lanca multa e juros nas competencias vencidas da assinatura e
+// atualiza o status delas, ja que os encargos entram no total devido.
+func applyLateFees(ctx context.Context, repo ports.BillingPeriodRepository, subscription domain.Subscription, plan domain.Plan, today time.Time) ([]domain.BillingPeriod, error) {
+	paymentDay, err := effectivePaymentDay(subscription)
+	if err != nil {
+		return nil, err
+	}
+
+	periods, err := repo.ListBySubscription(ctx, subscription.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	updated := make([]domain.BillingPeriod, 0, len(periods))
+	for _, period := range periods {
+		accrued, changed := accrueLateFees(period, plan, today, paymentDay)
+		settled := settlePeriod(accrued, today, paymentDay)
+		if changed || settled.Status != period.Status || !settled.DueDate.Equal(period.DueDate) {
+			saved, err := repo.Update(ctx, settled)
+			if err != nil {
+				return nil, err
+			}
+			accrued = saved
+		}
+		updated = append(updated, accrued)
+	}
+	return updated, nil
+}
+
+// accrueLateFees aplica a multa no primeiro dia de atraso e os juros de cada
+// dia desde o ultimo lancamento ate today. Os juros incidem apenas sobre o
+// valor da competencia ainda em aberto e sao calculados sobre o acumulado de
+// InterestCentDays, lancando so a diferenca para o total ja cobrado, para que
+// o arredondamento diario nao zere os juros. Competencias quitadas ou com
+// encargos dispensados nao mudam.
+func accrueLateFees(period domain.BillingPeriod, plan domain.Plan, today time.Time, paymentDay int) (domain.BillingPeriod, bool) {
+	if !plan.ChargesLateFees() || period.FeeWaivedAt != nil || period.OutstandingCents() == 0 {
+		return period, false
+	}
+
+	today = dateOnly(today)
+	dueDate := periodDueDate(period, paymentDay)
+	if !today.After(dueDate) {
+		return period, false
+	}
+
+	accruedOn := dueDate
+	changed := false
+	if period.FeeAccruedOn == nil {
+		period.FeeCents += basisPointsOf(period.PrincipalOutstandingCents(), int64(plan.LateFeeBasisPoints), 1)
+		changed = true
+	} else {
+		accruedOn = dateOnly(*period.FeeAccruedOn)
+	}
+
+	if days := daysBetween(accruedOn, today); days > 0 {
+		interestBPS := int64(plan.LateInterestBasisPoints)
+		before := basisPointsOf(period.InterestCentDays, interestBPS, lateInterestDaysPerMonth)
+		period.InterestCentDays += period.PrincipalOutstandingCents() * int64(days)
+		period.FeeCents += basisPointsOf(period.InterestCentDays, interestBPS, lateInterestDaysPerMonth) - before
+		accruedOn = today
+		changed = true
+	}
+
+	period.FeeAccruedOn = &accruedOn
+	return period, changed
+}
+
+// basisPointsOf calcula amount * bps / (10000 * per), arredondando para o
+// centavo mais proximo.
+func basisPointsOf(amountCents, basisPoints, per int64) int64 {
+	if amountCents <= 0 || basisPoints <= 0 {
+		return 0
+	}
+	divisor := domain.MaxBasisPoints * per
+	return (amountCents*basisPoints + divisor/2) / divisor
+}
+
+// daysBetween conta os dias de calendario entre as datas, sem depender do
+// horario de verao.
+func daysBetween(from, to time.Time) int {
+	start := time.Date(from.Year(), from.Month(), from.Day(), 0, 0, 0, 0, time.UTC)
+	end := time.Date(to.Year(), to.Month(), to.Day(), 0, 0, 0, 0, time.UTC)
+	return int(end.Sub(start).Hours() / 24)
+}
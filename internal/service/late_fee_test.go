@@ -0,0 +1,277 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/PabloPavan/jaiu/internal/domain"
+	"github.com/PabloPavan/jaiu/internal/ports"
+)
+
+// Testa a multa no primeiro dia de atraso e os juros diarios sobre o valor em aberto.
+func TestAccrueLateFees(t *testing.T) {
+	plan := domain.Plan{LateFeeBasisPoints: 200, LateInterestBasisPoints: 100}
+	period := domain.BillingPeriod{
+		PeriodStart:    time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+		AmountDueCents: 10000,
+	}
+
+	accrued, changed := accrueLateFees(period, plan, time.Date(2024, 1, 10, 0, 0, 0, 0, time.UTC), 10)
+	if changed || accrued.FeeCents != 0 {
+		t.Fatalf("expected no fees on the due date, got %d", accrued.FeeCents)
+	}
+
+	accrued, changed = accrueLateFees(period, plan, time.Date(2024, 1, 20, 0, 0, 0, 0, time.UTC), 10)
+	if !changed || accrued.FeeCents != 233 {
+		t.Fatalf("expected 200 of fee plus 33 of interest, got %d", accrued.FeeCents)
+	}
+	if accrued.FeeAccruedOn == nil || !accrued.FeeAccruedOn.Equal(time.Date(2024, 1, 20, 0, 0, 0, 0, time.UTC)) {
+		t.Fatalf("unexpected accrued date: %v", accrued.FeeAccruedOn)
+	}
+
+	again, changed := accrueLateFees(accrued, plan, time.Date(2024, 1, 20, 0, 0, 0, 0, time.UTC), 10)
+	if changed || again.FeeCents != 233 {
+		t.Fatalf("expected no new fees on the same day, got %d", again.FeeCents)
+	}
+
+	next, changed := accrueLateFees(accrued, plan, time.Date(2024, 1, 21, 0, 0, 0, 0, time.UTC), 10)
+	if !changed || next.FeeCents != 237 {
+		t.Fatalf("expected one more day of interest, got %d", next.FeeCents)
+	}
+}
+
+// Testa que os juros lancados dia a dia somam o juro do mes inteiro.
+func TestAccrueLateFeesDailyInterestAddsUp(t *testing.T) {
+	plan := domain.Plan{LateInterestBasisPoints: 100}
+	period := domain.BillingPeriod{
+		PeriodStart:    time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+		AmountDueCents: 10000,
+	}
+
+	day := time.Date(2024, 1, 10, 0, 0, 0, 0, time.UTC)
+	for i := 0; i < lateInterestDaysPerMonth; i++ {
+		day = day.AddDate(0, 0, 1)
+		period, _ = accrueLateFees(period, plan, day, 10)
+	}
+	if period.FeeCents != 100 {
+		t.Fatalf("expected 100 of interest after 30 days, got %d", period.FeeCents)
+	}
+	if period.InterestCentDays != 300000 {
+		t.Fatalf("unexpected interest cent-days: %d", period.InterestCentDays)
+	}
+}
+
+// Testa que competencias quitadas, dispensadas ou sem regra nao acumulam encargos.
+func TestAccrueLateFeesSkips(t *testing.T) {
+	today := time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC)
+	waivedAt := time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC)
+	period := domain.BillingPeriod{
+		PeriodStart:    time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+		AmountDueCents: 10000,
+	}
+	plan := domain.Plan{LateFeeBasisPoints: 200}
+
+	if _, changed := accrueLateFees(period, domain.Plan{}, today, 10); changed {
+		t.Fatal("expected no fees without plan rules")
+	}
+	paid := period
+	paid.AmountPaidCents = 10000
+	if _, changed := accrueLateFees(paid, plan, today, 10); changed {
+		t.Fatal("expected no fees for paid period")
+	}
+	waived := period
+	waived.FeeWaivedAt = &waivedAt
+	if _, changed := accrueLateFees(waived, plan, today, 10); changed {
+		t.Fatal("expected no fees for waived period")
+	}
+}
+
+// Testa que o pagamento quita os encargos antes do valor da competencia.
+func TestPaymentServiceRegisterPaysFeesFirst(t *testing.T) {
+	subscriptions := &subscriptionRepoFake{
+		subscriptions: map[string]domain.Subscription{
+			"sub-1": {
+				ID:         "sub-1",
+				PlanID:     "plan-1",
+				Status:     domain.SubscriptionActive,
+				StartDate:  time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+				PaymentDay: 10,
+			},
+		},
+	}
+	plans := &planRepoFake{
+		plans: map[string]domain.Plan{
+			"plan-1": {ID: "plan-1", DurationDays: 30, PriceCents: 10000, LateFeeBasisPoints: 200, LateInterestBasisPoints: 100},
+		},
+	}
+	periods := &billingPeriodRepoFake{}
+	service := NewPaymentService(&paymentRepoFake{}, subscriptions, plans, periods, &balanceRepoFake{}, &paymentAllocationRepoFake{}, nil, nil)
+	service.now = func() time.Time { return time.Date(2024, 1, 20, 0, 0, 0, 0, time.UTC) }
+
+	payment, err := service.Register(context.Background(), domain.Payment{
+		SubscriptionID: "sub-1",
+		AmountCents:    300,
+		Method:         domain.PaymentCash,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if payment.Kind != domain.PaymentPartial {
+		t.Fatalf("expected partial payment, got %q", payment.Kind)
+	}
+
+	list, _ := periods.ListBySubscription(context.Background(), "sub-1")
+	if len(list) != 1 {
+		t.Fatalf("expected one period, got %d", len(list))
+	}
+	period := list[0]
+	if period.FeeCents != 233 || period.FeePaidCents() != 233 {
+		t.Fatalf("expected fees fully paid, got fee=%d paid=%d", period.FeeCents, period.FeePaidCents())
+	}
+	if period.PrincipalOutstandingCents() != 9933 || period.Status != domain.BillingOverdue {
+		t.Fatalf("unexpected period after payment: %#v", period)
+	}
+}
+
+// Testa a dispensa dos encargos em aberto com registro de auditoria.
+func TestPaymentServiceWaiveLateFees(t *testing.T) {
+	accruedOn := time.Date(2024, 1, 20, 0, 0, 0, 0, time.UTC)
+	subscriptions := &subscriptionRepoFake{
+		subscriptions: map[string]domain.Subscription{
+			"sub-1": {ID: "sub-1", PlanID: "plan-1", Status: domain.SubscriptionActive, PaymentDay: 10},
+		},
+	}
+	periods := &billingPeriodRepoFake{
+		periods: map[string]domain.BillingPeriod{
+			"period-1": {
+				ID:              "period-1",
+				SubscriptionID:  "sub-1",
+				PeriodStart:     time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+				AmountDueCents:  10000,
+				AmountPaidCents: 100,
+				FeeCents:        233,
+				FeeAccruedOn:    &accruedOn,
+				Status:          domain.BillingOverdue,
+			},
+		},
+	}
+	audit := &auditRepoFake{}
+	service := NewPaymentService(&paymentRepoFake{}, subscriptions, &planRepoFake{}, periods, nil, nil, audit, nil)
+	service.now = func() time.Time { return time.Date(2024, 1, 21, 12, 0, 0, 0, time.UTC) }
+
+	period, err := service.WaiveLateFees(context.Background(), "sub-1", "period-1")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if period.FeeCents != 100 || period.FeeWaivedAt == nil {
+		t.Fatalf("expected only the paid fee to remain, got %#v", period)
+	}
+	if period.OutstandingCents() != 10000 {
+		t.Fatalf("expected only the principal outstanding, got %d", period.OutstandingCents())
+	}
+	last := audit.events[len(audit.events)-1]
+	if last.Action != "billing_period.fee_waive.success" || last.Metadata["waived_cents"] != int64(133) {
+		t.Fatalf("unexpected audit event: %#v", last)
+	}
+
+	if _, err := service.WaiveLateFees(context.Background(), "sub-1", "missing"); err != ports.ErrNotFound {
+		t.Fatalf("expected not found, got %v", err)
+	}
+}
+
+// Testa que a dispensa le e grava a competencia dentro da transacao dos
+// pagamentos.
+func TestPaymentServiceWaiveLateFeesRunsInsideTx(t *testing.T) {
+	accruedOn := time.Date(2024, 1, 20, 0, 0, 0, 0, time.UTC)
+	periods := &billingPeriodRepoFake{
+		periods: map[string]domain.BillingPeriod{
+			"period-1": {
+				ID:             "period-1",
+				SubscriptionID: "sub-1",
+				PeriodStart:    time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+				AmountDueCents: 10000,
+				FeeCents:       233,
+				FeeAccruedOn:   &accruedOn,
+				Status:         domain.BillingOverdue,
+			},
+		},
+	}
+	txAudit := &auditRepoFake{}
+	runner := &paymentTxRunnerFake{deps: ports.PaymentDependencies{
+		Subscriptions: &subscriptionRepoFake{subscriptions: map[string]domain.Subscription{
+			"sub-1": {ID: "sub-1", PlanID: "plan-1", Status: domain.SubscriptionActive, PaymentDay: 10},
+		}},
+		BillingPeriods: periods,
+		Audit:          txAudit,
+	}}
+	audit := &auditRepoFake{}
+	service := NewPaymentService(&paymentRepoFake{}, nil, nil, nil, nil, nil, audit, runner)
+	service.now = func() time.Time { return time.Date(2024, 1, 21, 12, 0, 0, 0, time.UTC) }
+
+	if _, err := service.WaiveLateFees(context.Background(), "sub-1", "period-1"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if periods.periods["period-1"].FeeWaivedAt == nil {
+		t.Fatalf("expected period updated through the transaction, got %#v", periods.periods["period-1"])
+	}
+	if len(txAudit.events) != 1 || txAudit.events[0].Action != "billing_period.fee_waive.success" {
+		t.Fatalf("expected success recorded inside the transaction, got %#v", txAudit.events)
+	}
+
+	if _, err := service.WaiveLateFees(context.Background(), "sub-1", "missing"); err != ports.ErrNotFound {
+		t.Fatalf("expected not found, got %v", err)
+	}
+	if runner.rollbacks != 1 {
+		t.Fatalf("expected rolled back transaction, got %d", runner.rollbacks)
+	}
+	if actions := auditActions(audit); len(actions) != 3 || actions[2] != "billing_period.fee_waive.failure" {
+		t.Fatalf("expected attempts and failure outside the transaction, got %v", actions)
+	}
+}
+
+// Testa que o worker acumula encargos em assinaturas sem renovacao automatica.
+func TestRenewalJobRunAppliesLateFees(t *testing.T) {
+	subRepo := &subscriptionRepoFake{
+		subscriptions: map[string]domain.Subscription{
+			"sub-1": {
+				ID:         "sub-1",
+				PlanID:     "plan-1",
+				Status:     domain.SubscriptionActive,
+				StartDate:  time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+				PaymentDay: 10,
+			},
+		},
+	}
+	planRepo := &planRepoFake{
+		plans: map[string]domain.Plan{
+			"plan-1": {ID: "plan-1", DurationDays: 30, PriceCents: 10000, LateFeeBasisPoints: 200},
+		},
+	}
+	periodRepo := &billingPeriodRepoFake{
+		periods: map[string]domain.BillingPeriod{
+			"period-1": {
+				ID:             "period-1",
+				SubscriptionID: "sub-1",
+				PeriodStart:    time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+				AmountDueCents: 10000,
+				Status:         domain.BillingOpen,
+			},
+		},
+	}
+	balanceRepo := &balanceRepoFake{
+		balances: map[string]domain.SubscriptionBalance{
+			"sub-1": {SubscriptionID: "sub-1"},
+		},
+	}
+	job := NewRenewalJob(subRepo, planRepo, periodRepo, balanceRepo, nil)
+	job.now = func() time.Time { return time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC) }
+
+	if err := job.Run(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	period := periodRepo.periods["period-1"]
+	if period.FeeCents != 200 || period.Status != domain.BillingOverdue {
+		t.Fatalf("expected late fee and overdue status, got %#v", period)
+	}
+}
@@ -0,0 +1,123 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/PabloPavan/jaiu/internal/ports"
+)
+
+// LoginLimitPolicy define quantas falhas sao toleradas por email e por IP
+// antes do bloqueio e como o tempo de bloqueio cresce a cada reincidencia.
+type LoginLimitPolicy struct {
+	MaxEmailFailures int
+	MaxIPFailures    int
+	Window           time.Duration
+	BaseLockout      time.Duration
+	MaxLockout       time.Duration
+	LockoutMemory    time.Duration
+}
+
+func DefaultLoginLimitPolicy() LoginLimitPolicy {
+	return LoginLimitPolicy{
+		MaxEmailFailures: 5,
+		MaxIPFailures:    20,
+		Window:           15 * time.Minute,
+		BaseLockout:      time.Minute,
+		MaxLockout:       time.Hour,
+		LockoutMemory:    24 * time.Hour,
+	}
+}
+
+type LoginLimiter struct {
+	store  ports.LoginAttemptStore
+	policy LoginLimitPolicy
+	now    func() time.Time
+}
+
+func NewLoginLimiter(store ports.LoginAttemptStore, policy LoginLimitPolicy) *LoginLimiter {
+	return &LoginLimiter{store: store, policy: policy, now: time.Now}
+}
+
+// Check retorna ate quando o login esta bloqueado para o email ou IP.
+func (l *LoginLimiter) Check(ctx context.Context, email, ip string) (time.Time, error) {
+	var lockedUntil time.Time
+	for _, key := range loginLimitKeys(email, ip) {
+		attempts, err := l.store.Get(ctx, key)
+		if err != nil {
+			return time.Time{}, err
+		}
+		if attempts.LockedUntil.After(lockedUntil) {
+			lockedUntil = attempts.LockedUntil
+		}
+	}
+	return lockedUntil, nil
+}
+
+// RegisterFailure contabiliza a falha e, ao atingir o limite, bloqueia a chave
+// com backoff exponencial. Retorna o fim do bloqueio quando ele ocorre.
+func (l *LoginLimiter) RegisterFailure(ctx context.Context, email, ip string) (time.Time, error) {
+	var lockedUntil time.Time
+	for _, key := range loginLimitKeys(email, ip) {
+		failures, err := l.store.RegisterFailure(ctx, key, l.policy.Window)
+		if err != nil {
+			return time.Time{}, err
+		}
+		if failures < l.maxFailures(key) {
+			continue
+		}
+
+		attempts, err := l.store.Get(ctx, key)
+		if err != nil {
+			return time.Time{}, err
+		}
+		until := l.now().Add(l.lockoutDuration(attempts.Lockouts))
+		if err := l.store.Lock(ctx, key, until, l.policy.LockoutMemory); err != nil {
+			return time.Time{}, err
+		}
+		if until.After(lockedUntil) {
+			lockedUntil = until
+		}
+	}
+	return lockedUntil, nil
+}
+
+// Reset limpa o historico do email apos um login bem sucedido. O contador do
+// IP e mantido para nao liberar quem testa varias contas.
+func (l *LoginLimiter) Reset(ctx context.Context, email string) error {
+	return l.store.Reset(ctx, loginEmailKey(email))
+}
+
+func (l *LoginLimiter) maxFailures(key string) int {
+	if strings.HasPrefix(key, "ip:") {
+		return l.policy.MaxIPFailures
+	}
+	return l.policy.MaxEmailFailures
+}
+
+func (l *LoginLimiter) lockoutDuration(previous int) time.Duration {
+	duration := l.policy.BaseLockout
+	for i := 0; i < previous; i++ {
+		duration *= 2
+		if l.policy.MaxLockout > 0 && duration >= l.policy.MaxLockout {
+			return l.policy.MaxLockout
+		}
+	}
+	if l.policy.MaxLockout > 0 && duration > l.policy.MaxLockout {
+		return l.policy.MaxLockout
+	}
+	return duration
+}
+
+func loginLimitKeys(email, ip string) []string {
+	keys := []string{loginEmailKey(email)}
+	if ip = strings.TrimSpace(ip); ip != "" {
+		keys = append(keys, "ip:"+ip)
+	}
+	return keys
+}
+
+func loginEmailKey(email string) string {
+	return "email:" + strings.ToLower(strings.TrimSpace(email))
+}
@@ -0,0 +1,98 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+// Testa o bloqueio apos o limite de falhas por email com backoff exponencial.
+func TestLoginLimiterLocksWithBackoff(t *testing.T) {
+	now := time.Now()
+	store := &loginAttemptStoreFake{}
+	limiter := NewLoginLimiter(store, LoginLimitPolicy{
+		MaxEmailFailures: 3,
+		MaxIPFailures:    100,
+		Window:           time.Minute,
+		BaseLockout:      time.Minute,
+		MaxLockout:       5 * time.Minute,
+		LockoutMemory:    time.Hour,
+	})
+	limiter.now = func() time.Time { return now }
+	ctx := context.Background()
+
+	expected := []time.Duration{time.Minute, 2 * time.Minute, 4 * time.Minute, 5 * time.Minute}
+	for round, want := range expected {
+		var lockedUntil time.Time
+		for i := 0; i < 3; i++ {
+			until, err := limiter.RegisterFailure(ctx, "User@Example.com", "10.0.0.1")
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if i < 2 && !until.IsZero() {
+				t.Fatalf("round %d: expected no lock before limit, got %v", round, until)
+			}
+			lockedUntil = until
+		}
+		if got := lockedUntil.Sub(now); got != want {
+			t.Fatalf("round %d: expected lockout %v, got %v", round, want, got)
+		}
+	}
+
+	if _, ok := store.attempts["email:user@example.com"]; !ok {
+		t.Fatalf("expected email key normalized, got %v", store.attempts)
+	}
+	if store.attempts["ip:10.0.0.1"].Failures != 12 {
+		t.Fatalf("expected ip failures counted, got %#v", store.attempts["ip:10.0.0.1"])
+	}
+
+	locked, err := limiter.Check(ctx, "user@example.com", "")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if locked.IsZero() {
+		t.Fatal("expected email to be locked")
+	}
+
+	if err := limiter.Reset(ctx, "user@example.com"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	locked, _ = limiter.Check(ctx, "user@example.com", "")
+	if !locked.IsZero() {
+		t.Fatalf("expected lock cleared after reset, got %v", locked)
+	}
+}
+
+// Testa o bloqueio por IP independente do email usado.
+func TestLoginLimiterLocksByIP(t *testing.T) {
+	store := &loginAttemptStoreFake{}
+	policy := DefaultLoginLimitPolicy()
+	policy.MaxIPFailures = 2
+	limiter := NewLoginLimiter(store, policy)
+	ctx := context.Background()
+
+	if _, err := limiter.RegisterFailure(ctx, "a@example.com", "10.0.0.2"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	until, err := limiter.RegisterFailure(ctx, "b@example.com", "10.0.0.2")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if until.IsZero() {
+		t.Fatal("expected ip lock")
+	}
+
+	locked, err := limiter.Check(ctx, "c@example.com", "10.0.0.2")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if locked.IsZero() {
+		t.Fatal("expected other email from same ip to be locked")
+	}
+	if attempts := store.attempts["ip:10.0.0.2"]; attempts.Lockouts != 1 {
+		t.Fatalf("expected one ip lockout, got %#v", attempts)
+	}
+	if len(store.memories) != 1 || store.memories[0] != policy.LockoutMemory {
+		t.Fatalf("expected lockout memory %v, got %v", policy.LockoutMemory, store.memories)
+	}
+}
@@ -0,0 +1,447 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/PabloPavan/jaiu/internal/auditctx"
+	"github.com/PabloPavan/jaiu/internal/domain"
+	"github.com/PabloPavan/jaiu/internal/ports"
+)
+
+const (
+	loginChallengeTTL         = 5 * time.Minute
+	loginChallengeMaxFailures = 5
+	recoveryCodeCount         = 10
+)
+
+var errMFARequired = validationError("segundo fator obrigatorio para o perfil")
+
+// MFAPolicy define quais perfis so podem entrar com o segundo fator ativo.
+type MFAPolicy struct {
+	RequiredRoles []domain.UserRole
+}
+
+func (p MFAPolicy) Requires(role domain.UserRole) bool {
+	for _, required := range p.RequiredRoles {
+		if required == role {
+			return true
+		}
+	}
+	return false
+}
+
+type MFAService struct {
+	users      ports.UserRepository
+	repo       ports.MFARepository
+	challenges ports.LoginChallengeStore
+	audit      ports.AuditRepository
+	limiter    *LoginLimiter
+	issuer     string
+	policy     MFAPolicy
+	now        func() time.Time
+}
+
+// NewMFAService cria o servico de segundo fator TOTP. issuer e o nome exibido
+// nos aplicativos autenticadores.
+func NewMFAService(users ports.UserRepository, repo ports.MFARepository, challenges ports.LoginChallengeStore, audit ports.AuditRepository, issuer string) *MFAService {
+	return &MFAService{
+		users:      users,
+		repo:       repo,
+		challenges: challenges,
+		audit:      audit,
+		issuer:     issuer,
+		now:        time.Now,
+	}
+}
+
+// SetPolicy define os perfis obrigados a usar o segundo fator.
+func (s *MFAService) SetPolicy(policy MFAPolicy) {
+	s.policy = policy
+}
+
+// SetLoginLimiter faz os codigos invalidos contarem como falhas de login do
+// email, o mesmo limitador usado na senha. Sem isso, quem conhece a senha
+// ganharia um desafio novo a cada login.
+func (s *MFAService) SetLoginLimiter(limiter *LoginLimiter) {
+	s.limiter = limiter
+}
+
+// RequiresChallenge indica se o login do usuario passa pelo segundo fator.
+func (s *MFAService) RequiresChallenge(ctx context.Context, user domain.User) (bool, error) {
+	enabled, err := s.enabled(ctx, user.ID)
+	if err != nil {
+		return false, err
+	}
+	return enabled || s.policy.Requires(user.Role), nil
+}
+
+// StartLogin decide se o login precisa do segundo fator. Quando precisa, cria o
+// desafio e retorna seu token; caso contrario retorna um token vazio. Usuarios
+// de perfis obrigatorios sem inscricao recebem um desafio de inscricao.
+func (s *MFAService) StartLogin(ctx context.Context, user domain.User) (string, error) {
+	enabled, err := s.enabled(ctx, user.ID)
+	if err != nil {
+		return "", err
+	}
+	if !enabled && !s.policy.Requires(user.Role) {
+		return "", nil
+	}
+
+	token, err := s.challenges.Create(ctx, ports.LoginChallenge{
+		UserID:             user.ID,
+		Email:              user.Email,
+		Name:               user.Name,
+		Role:               user.Role,
+		MustChangePassword: user.MustChangePassword,
+		Enroll:             !enabled,
+		ExpiresAt:          s.now().Add(loginChallengeTTL),
+	})
+	if err != nil {
+		return "", err
+	}
+
+	recordAudit(ctx, s.audit, "user.mfa.challenge", "user", user.ID, map[string]any{
+		"enroll": !enabled,
+	})
+	return token, nil
+}
+
+func (s *MFAService) LoginChallenge(ctx context.Context, token string) (ports.LoginChallenge, error) {
+	return s.challenges.Get(ctx, token)
+}
+
+// CompleteLogin valida o codigo informado no desafio. Em desafios de inscricao
+// o codigo confirma o segredo pendente e os codigos de recuperacao sao
+// devolvidos. Cada codigo invalido conta como falha de login do email, e as
+// falhas so sao zeradas quando o segundo fator e confirmado.
+func (s *MFAService) CompleteLogin(ctx context.Context, token, code string) (ports.LoginChallenge, []string, error) {
+	challenge, err := s.challenges.Get(ctx, token)
+	if err != nil {
+		return ports.LoginChallenge{}, nil, err
+	}
+
+	ip := auditctx.FromContext(ctx).Request.IP
+	if s.limiter != nil && challenge.Email != "" {
+		lockedUntil, err := s.limiter.Check(ctx, challenge.Email, ip)
+		if err != nil {
+			return ports.LoginChallenge{}, nil, err
+		}
+		if !lockedUntil.IsZero() {
+			_ = s.challenges.Delete(ctx, token)
+			return ports.LoginChallenge{}, nil, &ports.LockoutError{Until: lockedUntil}
+		}
+	}
+
+	var recoveryCodes []string
+	if challenge.Enroll {
+		recoveryCodes, err = s.ConfirmEnrollment(ctx, challenge.UserID, code)
+	} else {
+		err = s.Verify(ctx, challenge.UserID, code)
+	}
+	if err != nil {
+		if !errors.Is(err, ports.ErrUnauthorized) {
+			return ports.LoginChallenge{}, nil, err
+		}
+		if lockErr := s.registerLoginFailure(ctx, challenge, ip); lockErr != nil {
+			_ = s.challenges.Delete(ctx, token)
+			return ports.LoginChallenge{}, nil, lockErr
+		}
+		failures, failErr := s.challenges.RegisterFailure(ctx, token)
+		if failErr != nil {
+			return ports.LoginChallenge{}, nil, failErr
+		}
+		if failures >= loginChallengeMaxFailures {
+			_ = s.challenges.Delete(ctx, token)
+			recordAudit(ctx, s.audit, "user.mfa.challenge_locked", "user", challenge.UserID, map[string]any{
+				"failures": failures,
+			})
+			return ports.LoginChallenge{}, nil, ports.ErrTooManyAttempts
+		}
+		return ports.LoginChallenge{}, nil, err
+	}
+
+	if err := s.challenges.Delete(ctx, token); err != nil {
+		return ports.LoginChallenge{}, nil, err
+	}
+	if s.limiter != nil && challenge.Email != "" {
+		if err := s.limiter.Reset(ctx, challenge.Email); err != nil {
+			return ports.LoginChallenge{}, nil, err
+		}
+	}
+	return challenge, recoveryCodes, nil
+}
+
+// registerLoginFailure conta o codigo invalido no limitador de login e devolve
+// o erro de bloqueio quando o limite do email ou do IP e atingido.
+func (s *MFAService) registerLoginFailure(ctx context.Context, challenge ports.LoginChallenge, ip string) error {
+	if s.limiter == nil || challenge.Email == "" {
+		return nil
+	}
+
+	lockedUntil, err := s.limiter.RegisterFailure(ctx, challenge.Email, ip)
+	if err != nil {
+		return err
+	}
+	if lockedUntil.IsZero() {
+		return nil
+	}
+
+	recordAudit(ctx, s.audit, "user.login.lockout", "user", challenge.UserID, map[string]any{
+		"email":        challenge.Email,
+		"locked_until": lockedUntil.Format(time.RFC3339),
+		"source":       "mfa",
+	})
+	return &ports.LockoutError{Until: lockedUntil}
+}
+
+func (s *MFAService) Status(ctx context.Context, userID string, role domain.UserRole) (ports.MFAStatus, error) {
+	status := ports.MFAStatus{Required: s.policy.Requires(role)}
+
+	enabled, err := s.enabled(ctx, userID)
+	if err != nil {
+		return ports.MFAStatus{}, err
+	}
+	if !enabled {
+		return status, nil
+	}
+
+	status.Enabled = true
+	status.RecoveryCodesLeft, err = s.repo.CountRecoveryCodes(ctx, userID)
+	if err != nil {
+		return ports.MFAStatus{}, err
+	}
+	return status, nil
+}
+
+// BeginEnrollment gera o segredo pendente e a URI de provisionamento. Enquanto
+// a inscricao nao e confirmada o mesmo segredo e reaproveitado.
+func (s *MFAService) BeginEnrollment(ctx context.Context, userID string) (ports.MFAEnrollment, error) {
+	user, err := s.users.FindByID(ctx, userID)
+	if err != nil {
+		return ports.MFAEnrollment{}, err
+	}
+
+	mfa, err := s.repo.FindByUser(ctx, userID)
+	switch {
+	case err == nil && mfa.Enabled():
+		return ports.MFAEnrollment{}, ports.ErrConflict
+	case errors.Is(err, ports.ErrNotFound):
+		secret, genErr := generateTOTPSecret()
+		if genErr != nil {
+			return ports.MFAEnrollment{}, genErr
+		}
+		mfa, err = s.repo.SaveSecret(ctx, userID, secret)
+		if err != nil {
+			return ports.MFAEnrollment{}, err
+		}
+		recordAudit(ctx, s.audit, "user.mfa.enroll_start", "user", userID, nil)
+	case err != nil:
+		return ports.MFAEnrollment{}, err
+	}
+
+	return ports.MFAEnrollment{
+		Secret:          mfa.Secret,
+		ProvisioningURI: totpProvisioningURI(s.issuer, user.Email, mfa.Secret),
+	}, nil
+}
+
+// ConfirmEnrollment ativa o segundo fator com o primeiro codigo valido e
+// devolve os codigos de recuperacao, exibidos apenas nesse momento.
+func (s *MFAService) ConfirmEnrollment(ctx context.Context, userID, code string) ([]string, error) {
+	recordAuditAttempt(ctx, s.audit, "user.mfa.enroll", "user", userID, nil)
+
+	mfa, err := s.repo.FindByUser(ctx, userID)
+	if err != nil {
+		recordAuditFailure(ctx, s.audit, "user.mfa.enroll", "user", userID, nil, err)
+		return nil, err
+	}
+	if mfa.Enabled() {
+		recordAuditFailure(ctx, s.audit, "user.mfa.enroll", "user", userID, nil, ports.ErrConflict)
+		return nil, ports.ErrConflict
+	}
+
+	now := s.now()
+	step, ok := verifyTOTP(mfa.Secret, normalizeMFACode(code), now)
+	if !ok {
+		recordAuditFailure(ctx, s.audit, "user.mfa.enroll", "user", userID, nil, ports.ErrUnauthorized)
+		return nil, ports.ErrUnauthorized
+	}
+
+	if _, err := s.repo.Enable(ctx, userID, now, step); err != nil {
+		recordAuditFailure(ctx, s.audit, "user.mfa.enroll", "user", userID, nil, err)
+		return nil, err
+	}
+	codes, err := s.issueRecoveryCodes(ctx, userID)
+	if err != nil {
+		recordAuditFailure(ctx, s.audit, "user.mfa.enroll", "user", userID, nil, err)
+		return nil, err
+	}
+
+	recordAuditSuccess(ctx, s.audit, "user.mfa.enroll", "user", userID, nil)
+	return codes, nil
+}
+
+// Verify aceita um codigo TOTP ou um codigo de recuperacao. Cada intervalo
+// TOTP e cada codigo de recuperacao so podem ser usados uma vez.
+func (s *MFAService) Verify(ctx context.Context, userID, code string) error {
+	code = normalizeMFACode(code)
+	metadata := map[string]any{
+		"method": "totp",
+	}
+	if !isTOTPCode(code) {
+		metadata["method"] = "recovery_code"
+	}
+	recordAuditAttempt(ctx, s.audit, "user.mfa.verify", "user", userID, metadata)
+
+	mfa, err := s.repo.FindByUser(ctx, userID)
+	if err == nil && !mfa.Enabled() {
+		err = ports.ErrNotFound
+	}
+	if err != nil {
+		recordAuditFailure(ctx, s.audit, "user.mfa.verify", "user", userID, metadata, err)
+		return err
+	}
+
+	now := s.now()
+	if isTOTPCode(code) {
+		step, ok := verifyTOTP(mfa.Secret, code, now)
+		if ok {
+			ok, err = s.repo.AdvanceStep(ctx, userID, step)
+			if err != nil {
+				recordAuditFailure(ctx, s.audit, "user.mfa.verify", "user", userID, metadata, err)
+				return err
+			}
+		}
+		if !ok {
+			recordAuditFailure(ctx, s.audit, "user.mfa.verify", "user", userID, metadata, ports.ErrUnauthorized)
+			return ports.ErrUnauthorized
+		}
+	} else {
+		ok, err := s.repo.ConsumeRecoveryCode(ctx, userID, hashRecoveryCode(code), now)
+		if err != nil {
+			recordAuditFailure(ctx, s.audit, "user.mfa.verify", "user", userID, metadata, err)
+			return err
+		}
+		if !ok {
+			recordAuditFailure(ctx, s.audit, "user.mfa.verify", "user", userID, metadata, ports.ErrUnauthorized)
+			return ports.ErrUnauthorized
+		}
+		recordAudit(ctx, s.audit, "user.mfa.recovery_code_used", "user", userID, nil)
+	}
+
+	recordAuditSuccess(ctx, s.audit, "user.mfa.verify", "user", userID, metadata)
+	return nil
+}
+
+// Disable remove o segundo fator do usuario mediante um codigo valido. Perfis
+// obrigados pela politica nao podem desativar.
+func (s *MFAService) Disable(ctx context.Context, userID, code string) error {
+	recordAuditAttempt(ctx, s.audit, "user.mfa.disable", "user", userID, nil)
+
+	user, err := s.users.FindByID(ctx, userID)
+	if err != nil {
+		recordAuditFailure(ctx, s.audit, "user.mfa.disable", "user", userID, nil, err)
+		return err
+	}
+	if s.policy.Requires(user.Role) {
+		recordAuditFailure(ctx, s.audit, "user.mfa.disable", "user", userID, nil, errMFARequired)
+		return errMFARequired
+	}
+	if err := s.Verify(ctx, userID, code); err != nil {
+		recordAuditFailure(ctx, s.audit, "user.mfa.disable", "user", userID, nil, err)
+		return err
+	}
+	if err := s.repo.Delete(ctx, userID); err != nil {
+		recordAuditFailure(ctx, s.audit, "user.mfa.disable", "user", userID, nil, err)
+		return err
+	}
+
+	recordAuditSuccess(ctx, s.audit, "user.mfa.disable", "user", userID, nil)
+	return nil
+}
+
+// RegenerateRecoveryCodes troca todos os codigos de recuperacao mediante um
+// codigo valido; os anteriores deixam de funcionar.
+func (s *MFAService) RegenerateRecoveryCodes(ctx context.Context, userID, code string) ([]string, error) {
+	recordAuditAttempt(ctx, s.audit, "user.mfa.recovery_codes", "user", userID, nil)
+
+	if err := s.Verify(ctx, userID, code); err != nil {
+		recordAuditFailure(ctx, s.audit, "user.mfa.recovery_codes", "user", userID, nil, err)
+		return nil, err
+	}
+	codes, err := s.issueRecoveryCodes(ctx, userID)
+	if err != nil {
+		recordAuditFailure(ctx, s.audit, "user.mfa.recovery_codes", "user", userID, nil, err)
+		return nil, err
+	}
+
+	recordAuditSuccess(ctx, s.audit, "user.mfa.recovery_codes", "user", userID, nil)
+	return codes, nil
+}
+
+func (s *MFAService) enabled(ctx context.Context, userID string) (bool, error) {
+	mfa, err := s.repo.FindByUser(ctx, userID)
+	if err != nil {
+		if errors.Is(err, ports.ErrNotFound) {
+			return false, nil
+		}
+		return false, err
+	}
+	return mfa.Enabled(), nil
+}
+
+func (s *MFAService) issueRecoveryCodes(ctx context.Context, userID string) ([]string, error) {
+	codes := make([]string, 0, recoveryCodeCount)
+	hashes := make([]string, 0, recoveryCodeCount)
+	for len(codes) < recoveryCodeCount {
+		code, err := generateRecoveryCode()
+		if err != nil {
+			return nil, err
+		}
+		codes = append(codes, code)
+		hashes = append(hashes, hashRecoveryCode(code))
+	}
+
+	if err := s.repo.ReplaceRecoveryCodes(ctx, userID, hashes); err != nil {
+		return nil, err
+	}
+	return codes, nil
+}
+
+// generateRecoveryCode gera um codigo no formato xxxxx-xxxxx.
+func generateRecoveryCode() (string, error) {
+	buf := make([]byte, 10)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	raw := strings.ToLower(totpEncoding.EncodeToString(buf))[:10]
+	return raw[:5] + "-" + raw[5:], nil
+}
+
+func hashRecoveryCode(code string) string {
+	normalized := strings.ReplaceAll(strings.ToLower(code), "-", "")
+	sum := sha256.Sum256([]byte(normalized))
+	return hex.EncodeToString(sum[:])
+}
+
+func normalizeMFACode(code string) string {
+	return strings.ReplaceAll(strings.TrimSpace(code), " ", "")
+}
+
+func isTOTPCode(code string) bool {
+	if len(code) != totpDigits {
+		return false
+	}
+	for _, r := range code {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
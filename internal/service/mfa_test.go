@@ -0,0 +1,276 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/PabloPavan/jaiu/internal/domain"
+	"github.com/PabloPavan/jaiu/internal/ports"
+)
+
+func newMFATestService(now time.Time, users ...domain.User) (*MFAService, *mfaRepoFake, *loginChallengeStoreFake, *auditRepoFake) {
+	userRepo := &userRepoFake{users: map[string]domain.User{}}
+	for _, user := range users {
+		userRepo.users[user.Email] = user
+	}
+	repo := &mfaRepoFake{}
+	challenges := &loginChallengeStoreFake{}
+	audit := &auditRepoFake{}
+	service := NewMFAService(userRepo, repo, challenges, audit, "Jaiu")
+	service.now = func() time.Time { return now }
+	return service, repo, challenges, audit
+}
+
+func hasAuditAction(events []domain.AuditEvent, action string) bool {
+	for _, event := range events {
+		if event.Action == action {
+			return true
+		}
+	}
+	return false
+}
+
+// Testa a inscricao, a verificacao TOTP sem reutilizacao e o uso unico dos codigos de recuperacao.
+func TestMFAServiceEnrollAndVerify(t *testing.T) {
+	now := time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC)
+	user := domain.User{ID: "user-1", Email: "ana@example.com", Role: domain.RoleOperator, Active: true}
+	service, _, _, audit := newMFATestService(now, user)
+	ctx := context.Background()
+
+	enrollment, err := service.BeginEnrollment(ctx, user.ID)
+	if err != nil {
+		t.Fatalf("begin enrollment: %v", err)
+	}
+	again, err := service.BeginEnrollment(ctx, user.ID)
+	if err != nil || again.Secret != enrollment.Secret {
+		t.Fatalf("expected pending secret to be reused, got %q (%v)", again.Secret, err)
+	}
+
+	if _, err := service.ConfirmEnrollment(ctx, user.ID, "000000"); !errors.Is(err, ports.ErrUnauthorized) {
+		t.Fatalf("expected unauthorized for wrong code, got %v", err)
+	}
+
+	code, _ := totpCode(enrollment.Secret, totpStep(now))
+	recoveryCodes, err := service.ConfirmEnrollment(ctx, user.ID, code)
+	if err != nil {
+		t.Fatalf("confirm enrollment: %v", err)
+	}
+	if len(recoveryCodes) != recoveryCodeCount {
+		t.Fatalf("expected %d recovery codes, got %d", recoveryCodeCount, len(recoveryCodes))
+	}
+	if _, err := service.BeginEnrollment(ctx, user.ID); !errors.Is(err, ports.ErrConflict) {
+		t.Fatalf("expected conflict when already enrolled, got %v", err)
+	}
+
+	if err := service.Verify(ctx, user.ID, code); !errors.Is(err, ports.ErrUnauthorized) {
+		t.Fatalf("expected replayed code to be rejected, got %v", err)
+	}
+
+	service.now = func() time.Time { return now.Add(totpPeriod * time.Second) }
+	next, _ := totpCode(enrollment.Secret, totpStep(now)+1)
+	if err := service.Verify(ctx, user.ID, next); err != nil {
+		t.Fatalf("verify next code: %v", err)
+	}
+
+	if err := service.Verify(ctx, user.ID, recoveryCodes[0]); err != nil {
+		t.Fatalf("verify recovery code: %v", err)
+	}
+	if err := service.Verify(ctx, user.ID, recoveryCodes[0]); !errors.Is(err, ports.ErrUnauthorized) {
+		t.Fatalf("expected used recovery code to be rejected, got %v", err)
+	}
+
+	status, err := service.Status(ctx, user.ID, user.Role)
+	if err != nil {
+		t.Fatalf("status: %v", err)
+	}
+	if !status.Enabled || status.RecoveryCodesLeft != recoveryCodeCount-1 {
+		t.Fatalf("unexpected status: %#v", status)
+	}
+
+	for _, action := range []string{"user.mfa.enroll.success", "user.mfa.verify.failure", "user.mfa.recovery_code_used"} {
+		if !hasAuditAction(audit.events, action) {
+			t.Fatalf("expected audit action %q", action)
+		}
+	}
+}
+
+// Testa que o login so exige desafio quando ha inscricao ou a politica obriga o perfil.
+func TestMFAServiceStartLogin(t *testing.T) {
+	now := time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC)
+	admin := domain.User{ID: "user-1", Email: "admin@example.com", Role: domain.RoleAdmin, Active: true}
+	operator := domain.User{ID: "user-2", Email: "op@example.com", Role: domain.RoleOperator, Active: true}
+	service, _, challenges, _ := newMFATestService(now, admin, operator)
+	ctx := context.Background()
+
+	token, err := service.StartLogin(ctx, admin)
+	if err != nil || token != "" {
+		t.Fatalf("expected no challenge without policy, got %q (%v)", token, err)
+	}
+
+	service.SetPolicy(MFAPolicy{RequiredRoles: []domain.UserRole{domain.RoleAdmin}})
+	token, err = service.StartLogin(ctx, admin)
+	if err != nil || token == "" {
+		t.Fatalf("expected challenge for admin, got %q (%v)", token, err)
+	}
+	if challenge := challenges.challenges[token]; !challenge.Enroll || challenge.UserID != admin.ID {
+		t.Fatalf("expected enrollment challenge, got %#v", challenge)
+	}
+
+	token, err = service.StartLogin(ctx, operator)
+	if err != nil || token != "" {
+		t.Fatalf("expected no challenge for operator, got %q (%v)", token, err)
+	}
+
+	if err := service.Disable(ctx, admin.ID, "000000"); !errors.Is(err, errMFARequired) {
+		t.Fatalf("expected admin disable to be refused, got %v", err)
+	}
+}
+
+// Testa a conclusao do desafio com inscricao e o descarte apos falhas seguidas.
+func TestMFAServiceCompleteLogin(t *testing.T) {
+	now := time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC)
+	admin := domain.User{ID: "user-1", Name: "Admin", Email: "admin@example.com", Role: domain.RoleAdmin, Active: true}
+	service, _, challenges, audit := newMFATestService(now, admin)
+	service.SetPolicy(MFAPolicy{RequiredRoles: []domain.UserRole{domain.RoleAdmin}})
+	ctx := context.Background()
+
+	token, err := service.StartLogin(ctx, admin)
+	if err != nil {
+		t.Fatalf("start login: %v", err)
+	}
+	enrollment, err := service.BeginEnrollment(ctx, admin.ID)
+	if err != nil {
+		t.Fatalf("begin enrollment: %v", err)
+	}
+	code, _ := totpCode(enrollment.Secret, totpStep(now))
+	challenge, recoveryCodes, err := service.CompleteLogin(ctx, token, code)
+	if err != nil {
+		t.Fatalf("complete login: %v", err)
+	}
+	if challenge.UserID != admin.ID || challenge.Name != "Admin" || len(recoveryCodes) == 0 {
+		t.Fatalf("unexpected completion: %#v %v", challenge, recoveryCodes)
+	}
+	if _, ok := challenges.challenges[token]; ok {
+		t.Fatal("expected challenge to be removed")
+	}
+
+	token, err = service.StartLogin(ctx, admin)
+	if err != nil {
+		t.Fatalf("start login: %v", err)
+	}
+	for i := 1; i < loginChallengeMaxFailures; i++ {
+		if _, _, err := service.CompleteLogin(ctx, token, "000000"); !errors.Is(err, ports.ErrUnauthorized) {
+			t.Fatalf("attempt %d: expected unauthorized, got %v", i, err)
+		}
+	}
+	if _, _, err := service.CompleteLogin(ctx, token, "000000"); !errors.Is(err, ports.ErrTooManyAttempts) {
+		t.Fatalf("expected too many attempts, got %v", err)
+	}
+	if _, ok := challenges.challenges[token]; ok {
+		t.Fatal("expected locked challenge to be removed")
+	}
+	if !hasAuditAction(audit.events, "user.mfa.challenge_locked") {
+		t.Fatal("expected challenge locked audit")
+	}
+}
+
+// Testa que codigos invalidos em desafios diferentes somam no limitador do
+// email: digitar a senha de novo nao zera as falhas nem libera novas tentativas.
+func TestMFAServiceCompleteLoginLocksEmailAcrossChallenges(t *testing.T) {
+	now := time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC)
+	hash, err := HashPassword("correct")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	user := domain.User{ID: "user-1", Email: "ana@example.com", Role: domain.RoleOperator, Active: true, PasswordHash: hash}
+	mfa, _, _, audit := newMFATestService(now, user)
+	ctx := context.Background()
+
+	enrollment, err := mfa.BeginEnrollment(ctx, user.ID)
+	if err != nil {
+		t.Fatalf("begin enrollment: %v", err)
+	}
+	code, _ := totpCode(enrollment.Secret, totpStep(now))
+	if _, err := mfa.ConfirmEnrollment(ctx, user.ID, code); err != nil {
+		t.Fatalf("confirm enrollment: %v", err)
+	}
+
+	policy := DefaultLoginLimitPolicy()
+	limiter := NewLoginLimiter(&loginAttemptStoreFake{}, policy)
+	mfa.SetLoginLimiter(limiter)
+	auth := NewAuthService(&userRepoFake{users: map[string]domain.User{user.Email: user}}, audit)
+	auth.SetLoginLimiter(limiter)
+	auth.SetMFA(mfa)
+
+	failures := 0
+	var lockErr *ports.LockoutError
+	for attempt := 1; attempt <= policy.MaxEmailFailures; attempt++ {
+		logged, err := auth.Authenticate(ctx, user.Email, "correct")
+		if err != nil {
+			t.Fatalf("login %d: unexpected error: %v", attempt, err)
+		}
+		token, err := mfa.StartLogin(ctx, logged)
+		if err != nil || token == "" {
+			t.Fatalf("login %d: expected challenge, got %q (%v)", attempt, token, err)
+		}
+		for i := 0; i < 2 && lockErr == nil; i++ {
+			_, _, err = mfa.CompleteLogin(ctx, token, "000000")
+			failures++
+			if errors.As(err, &lockErr) {
+				break
+			}
+			if !errors.Is(err, ports.ErrUnauthorized) {
+				t.Fatalf("failure %d: expected unauthorized, got %v", failures, err)
+			}
+		}
+		if lockErr != nil {
+			break
+		}
+	}
+
+	if lockErr == nil || failures != policy.MaxEmailFailures {
+		t.Fatalf("expected lockout after %d bad codes, got %d (%v)", policy.MaxEmailFailures, failures, lockErr)
+	}
+	if _, err := auth.Authenticate(ctx, user.Email, "correct"); !errors.Is(err, ports.ErrTooManyAttempts) {
+		t.Fatalf("expected password login to stay locked, got %v", err)
+	}
+	if !hasAuditAction(audit.events, "user.login.lockout") {
+		t.Fatal("expected login lockout audit")
+	}
+}
+
+// Testa que so a confirmacao do segundo fator zera as falhas do email.
+func TestMFAServiceCompleteLoginResetsFailures(t *testing.T) {
+	now := time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC)
+	admin := domain.User{ID: "user-1", Email: "admin@example.com", Role: domain.RoleAdmin, Active: true}
+	mfa, _, _, _ := newMFATestService(now, admin)
+	mfa.SetPolicy(MFAPolicy{RequiredRoles: []domain.UserRole{domain.RoleAdmin}})
+	store := &loginAttemptStoreFake{}
+	mfa.SetLoginLimiter(NewLoginLimiter(store, DefaultLoginLimitPolicy()))
+	ctx := context.Background()
+
+	token, err := mfa.StartLogin(ctx, admin)
+	if err != nil {
+		t.Fatalf("start login: %v", err)
+	}
+	enrollment, err := mfa.BeginEnrollment(ctx, admin.ID)
+	if err != nil {
+		t.Fatalf("begin enrollment: %v", err)
+	}
+	if _, _, err := mfa.CompleteLogin(ctx, token, "000000"); !errors.Is(err, ports.ErrUnauthorized) {
+		t.Fatalf("expected unauthorized, got %v", err)
+	}
+	if store.attempts["email:admin@example.com"].Failures != 1 {
+		t.Fatalf("expected failure counted for the email, got %#v", store.attempts)
+	}
+
+	code, _ := totpCode(enrollment.Secret, totpStep(now))
+	if _, _, err := mfa.CompleteLogin(ctx, token, code); err != nil {
+		t.Fatalf("complete login: %v", err)
+	}
+	if _, ok := store.attempts["email:admin@example.com"]; ok {
+		t.Fatalf("expected email failures reset, got %#v", store.attempts)
+	}
+}
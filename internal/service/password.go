@@ -0,0 +1,223 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/PabloPavan/jaiu/internal/domain"
+	"github.com/PabloPavan/jaiu/internal/ports"
+	"golang.org/x/crypto/bcrypt"
+)
+
+const passwordResetTTL = time.Hour
+
+var errSamePassword = validationError("nova senha deve ser diferente da atual")
+
+type PasswordService struct {
+	users    ports.UserRepository
+	tokens   ports.PasswordResetRepository
+	sessions ports.SessionStore
+	notifier ports.Notifier
+	audit    ports.AuditRepository
+	baseURL  string
+	now      func() time.Time
+}
+
+// NewPasswordService cria o servico de troca e recuperacao de senha. baseURL e
+// usado para montar o link enviado ao usuario.
+func NewPasswordService(users ports.UserRepository, tokens ports.PasswordResetRepository, sessions ports.SessionStore, notifier ports.Notifier, audit ports.AuditRepository, baseURL string) *PasswordService {
+	return &PasswordService{
+		users:    users,
+		tokens:   tokens,
+		sessions: sessions,
+		notifier: notifier,
+		audit:    audit,
+		baseURL:  strings.TrimRight(baseURL, "/"),
+		now:      time.Now,
+	}
+}
+
+// ChangePassword troca a senha do proprio usuario, exigindo a senha atual, e
+// encerra todas as suas sessoes; quem chama abre uma nova sessao em seguida.
+func (s *PasswordService) ChangePassword(ctx context.Context, userID, current, next string) error {
+	recordAuditAttempt(ctx, s.audit, "user.password_change", "user", userID, nil)
+
+	user, err := s.users.FindByID(ctx, userID)
+	if err != nil {
+		recordAuditFailure(ctx, s.audit, "user.password_change", "user", userID, nil, err)
+		return err
+	}
+
+	if err := bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(current)); err != nil {
+		recordAuditFailure(ctx, s.audit, "user.password_change", "user", userID, nil, ports.ErrUnauthorized)
+		return ports.ErrUnauthorized
+	}
+	if err := validatePassword(next); err != nil {
+		recordAuditFailure(ctx, s.audit, "user.password_change", "user", userID, nil, err)
+		return err
+	}
+	if next == current {
+		recordAuditFailure(ctx, s.audit, "user.password_change", "user", userID, nil, errSamePassword)
+		return errSamePassword
+	}
+
+	hash, err := HashPassword(next)
+	if err != nil {
+		recordAuditFailure(ctx, s.audit, "user.password_change", "user", userID, nil, err)
+		return err
+	}
+	if err := s.users.UpdatePassword(ctx, userID, hash, false); err != nil {
+		recordAuditFailure(ctx, s.audit, "user.password_change", "user", userID, nil, err)
+		return err
+	}
+	if s.tokens != nil {
+		if err := s.tokens.InvalidateByUser(ctx, userID, s.now()); err != nil {
+			recordAuditFailure(ctx, s.audit, "user.password_change", "user", userID, nil, err)
+			return err
+		}
+	}
+	if s.sessions != nil {
+		if err := s.sessions.DeleteByUser(ctx, userID); err != nil {
+			recordAuditFailure(ctx, s.audit, "user.password_change", "user", userID, nil, err)
+			return err
+		}
+	}
+
+	recordAuditSuccess(ctx, s.audit, "user.password_change", "user", userID, nil)
+	return nil
+}
+
+// RequestReset envia um link de redefinicao para o email informado. Emails
+// desconhecidos ou inativos nao geram erro para nao revelar quem tem conta.
+func (s *PasswordService) RequestReset(ctx context.Context, email string) error {
+	email = strings.TrimSpace(email)
+	metadata := map[string]any{
+		"email": email,
+	}
+	recordAuditAttempt(ctx, s.audit, "user.password_reset_request", "user", "", metadata)
+
+	user, err := s.users.FindByEmail(ctx, email)
+	if err != nil {
+		recordAuditFailure(ctx, s.audit, "user.password_reset_request", "user", "", metadata, err)
+		if errors.Is(err, ports.ErrNotFound) {
+			return nil
+		}
+		return err
+	}
+	if !user.Active {
+		recordAuditFailure(ctx, s.audit, "user.password_reset_request", "user", user.ID, metadata, ports.ErrUnauthorized)
+		return nil
+	}
+
+	token, err := generateResetToken()
+	if err != nil {
+		recordAuditFailure(ctx, s.audit, "user.password_reset_request", "user", user.ID, metadata, err)
+		return err
+	}
+
+	now := s.now()
+	if err := s.tokens.InvalidateByUser(ctx, user.ID, now); err != nil {
+		recordAuditFailure(ctx, s.audit, "user.password_reset_request", "user", user.ID, metadata, err)
+		return err
+	}
+	created, err := s.tokens.Create(ctx, domain.PasswordResetToken{
+		UserID:    user.ID,
+		TokenHash: hashResetToken(token),
+		ExpiresAt: now.Add(passwordResetTTL),
+	})
+	if err != nil {
+		recordAuditFailure(ctx, s.audit, "user.password_reset_request", "user", user.ID, metadata, err)
+		return err
+	}
+
+	notification := ports.Notification{
+		To:      user.Email,
+		Subject: "Redefinicao de senha",
+		Body: fmt.Sprintf(
+			"Ola, %s.\n\nPara redefinir sua senha acesse o link abaixo:\n%s/auth/reset?token=%s\n\nO link expira em %d minutos e so pode ser usado uma vez. Se voce nao pediu a redefinicao, ignore esta mensagem.",
+			user.Name, s.baseURL, token, int(passwordResetTTL.Minutes()),
+		),
+	}
+	if err := s.notifier.Notify(ctx, notification); err != nil {
+		recordAuditFailure(ctx, s.audit, "user.password_reset_request", "user", user.ID, metadata, err)
+		return err
+	}
+
+	metadata["token_id"] = created.ID
+	recordAuditSuccess(ctx, s.audit, "user.password_reset_request", "user", user.ID, metadata)
+	return nil
+}
+
+// ResetPassword consome o token de redefinicao e grava a nova senha,
+// encerrando todas as sessoes do usuario. Usuarios desativados depois do
+// pedido recebem o mesmo erro de link invalido.
+func (s *PasswordService) ResetPassword(ctx context.Context, token, password string) error {
+	recordAuditAttempt(ctx, s.audit, "user.password_reset_confirm", "user", "", nil)
+
+	if err := validatePassword(password); err != nil {
+		recordAuditFailure(ctx, s.audit, "user.password_reset_confirm", "user", "", nil, err)
+		return err
+	}
+	if strings.TrimSpace(token) == "" {
+		recordAuditFailure(ctx, s.audit, "user.password_reset_confirm", "user", "", nil, ports.ErrNotFound)
+		return ports.ErrNotFound
+	}
+
+	hash, err := HashPassword(password)
+	if err != nil {
+		recordAuditFailure(ctx, s.audit, "user.password_reset_confirm", "user", "", nil, err)
+		return err
+	}
+
+	consumed, err := s.tokens.Consume(ctx, hashResetToken(token), s.now())
+	if err != nil {
+		recordAuditFailure(ctx, s.audit, "user.password_reset_confirm", "user", "", nil, err)
+		return err
+	}
+
+	metadata := map[string]any{
+		"token_id": consumed.ID,
+	}
+	user, err := s.users.FindByID(ctx, consumed.UserID)
+	if err != nil {
+		recordAuditFailure(ctx, s.audit, "user.password_reset_confirm", "user", consumed.UserID, metadata, err)
+		return err
+	}
+	if !user.Active {
+		recordAuditFailure(ctx, s.audit, "user.password_reset_confirm", "user", consumed.UserID, metadata, ports.ErrUnauthorized)
+		return ports.ErrNotFound
+	}
+	if err := s.users.UpdatePassword(ctx, consumed.UserID, hash, false); err != nil {
+		recordAuditFailure(ctx, s.audit, "user.password_reset_confirm", "user", consumed.UserID, metadata, err)
+		return err
+	}
+	if s.sessions != nil {
+		if err := s.sessions.DeleteByUser(ctx, consumed.UserID); err != nil {
+			recordAuditFailure(ctx, s.audit, "user.password_reset_confirm", "user", consumed.UserID, metadata, err)
+			return err
+		}
+	}
+
+	recordAuditSuccess(ctx, s.audit, "user.password_reset_confirm", "user", consumed.UserID, metadata)
+	return nil
+}
+
+func generateResetToken() (string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(buf), nil
+}
+
+func hashResetToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
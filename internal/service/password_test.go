@@ -0,0 +1,191 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/PabloPavan/jaiu/internal/domain"
+	"github.com/PabloPavan/jaiu/internal/ports"
+	"golang.org/x/crypto/bcrypt"
+)
+
+func newPasswordTestUser(t *testing.T, password string) domain.User {
+	t.Helper()
+	hash, err := HashPassword(password)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	return domain.User{ID: "user-1", Name: "Ana", Email: "ana@example.com", Active: true, PasswordHash: hash, MustChangePassword: true}
+}
+
+// Testa a troca de senha exigindo a senha atual e limpando a obrigatoriedade.
+func TestPasswordServiceChangePassword(t *testing.T) {
+	user := newPasswordTestUser(t, "old-secret")
+	repo := &userRepoFake{users: map[string]domain.User{user.Email: user}}
+	tokens := &passwordResetRepoFake{}
+	audit := &auditRepoFake{}
+	service := NewPasswordService(repo, tokens, nil, &notifierFake{}, audit, "http://localhost")
+	ctx := context.Background()
+
+	if err := service.ChangePassword(ctx, user.ID, "wrong", "new-secret"); err != ports.ErrUnauthorized {
+		t.Fatalf("expected unauthorized, got %v", err)
+	}
+	if err := service.ChangePassword(ctx, user.ID, "old-secret", "short"); err == nil {
+		t.Fatal("expected error for short password")
+	}
+	if err := service.ChangePassword(ctx, user.ID, "old-secret", "old-secret"); !errors.Is(err, errSamePassword) {
+		t.Fatalf("expected same password error, got %v", err)
+	}
+	if err := service.ChangePassword(ctx, user.ID, "old-secret", "new-secret"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	updated := repo.users[user.Email]
+	if err := bcrypt.CompareHashAndPassword([]byte(updated.PasswordHash), []byte("new-secret")); err != nil {
+		t.Fatalf("expected password updated, got %v", err)
+	}
+	if updated.MustChangePassword {
+		t.Fatal("expected must change flag cleared")
+	}
+	if len(tokens.invalidated) != 1 {
+		t.Fatalf("expected pending reset tokens invalidated, got %v", tokens.invalidated)
+	}
+	if last := audit.events[len(audit.events)-1]; last.Action != "user.password_change.success" {
+		t.Fatalf("expected success audit, got %q", last.Action)
+	}
+}
+
+// Testa que a troca de senha derruba as outras sessoes do usuario.
+func TestPasswordServiceChangePasswordRevokesSessions(t *testing.T) {
+	user := newPasswordTestUser(t, "old-secret")
+	repo := &userRepoFake{users: map[string]domain.User{user.Email: user}}
+	sessions := &sessionStoreFake{}
+	service := NewPasswordService(repo, &passwordResetRepoFake{}, sessions, &notifierFake{}, nil, "http://localhost")
+	ctx := context.Background()
+
+	current, _ := sessions.Create(ctx, ports.Session{UserID: user.ID})
+	other, _ := sessions.Create(ctx, ports.Session{UserID: user.ID})
+	someoneElse, _ := sessions.Create(ctx, ports.Session{UserID: "user-2"})
+
+	if err := service.ChangePassword(ctx, user.ID, "old-secret", "new-secret"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, token := range []string{current, other} {
+		if _, err := sessions.Get(ctx, token); !errors.Is(err, ports.ErrNotFound) {
+			t.Fatalf("expected session %s to be rejected, got %v", token, err)
+		}
+	}
+	if _, err := sessions.Get(ctx, someoneElse); err != nil {
+		t.Fatalf("expected other user's session to survive, got %v", err)
+	}
+}
+
+// Testa o fluxo completo de redefinicao por token de uso unico.
+func TestPasswordServiceResetFlow(t *testing.T) {
+	user := newPasswordTestUser(t, "old-secret")
+	repo := &userRepoFake{users: map[string]domain.User{user.Email: user}}
+	tokens := &passwordResetRepoFake{}
+	sessions := &sessionStoreFake{}
+	notifier := &notifierFake{}
+	audit := &auditRepoFake{}
+	service := NewPasswordService(repo, tokens, sessions, notifier, audit, "https://app.example.com/")
+	ctx := context.Background()
+
+	if err := service.RequestReset(ctx, "ana@example.com"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(notifier.notifications) != 1 || notifier.notifications[0].To != user.Email {
+		t.Fatalf("expected one notification to the user, got %#v", notifier.notifications)
+	}
+
+	body := notifier.notifications[0].Body
+	prefix := "https://app.example.com/auth/reset?token="
+	start := strings.Index(body, prefix)
+	if start < 0 {
+		t.Fatalf("expected reset link in body, got %q", body)
+	}
+	token := strings.Fields(body[start+len(prefix):])[0]
+	if len(tokens.tokens) != 1 || tokens.tokens[0].TokenHash == token || tokens.tokens[0].TokenHash != hashResetToken(token) {
+		t.Fatalf("expected hashed token stored, got %#v", tokens.tokens)
+	}
+	for _, event := range audit.events {
+		for _, value := range event.Metadata {
+			if value == token {
+				t.Fatal("expected token to be absent from audit metadata")
+			}
+		}
+	}
+
+	if err := service.ResetPassword(ctx, token, "brand-new-secret"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	updated := repo.users[user.Email]
+	if err := bcrypt.CompareHashAndPassword([]byte(updated.PasswordHash), []byte("brand-new-secret")); err != nil {
+		t.Fatalf("expected password updated, got %v", err)
+	}
+	if len(sessions.deletedUsers) != 1 || sessions.deletedUsers[0] != user.ID {
+		t.Fatalf("expected sessions revoked, got %v", sessions.deletedUsers)
+	}
+
+	if err := service.ResetPassword(ctx, token, "another-secret"); err != ports.ErrNotFound {
+		t.Fatalf("expected token to be single use, got %v", err)
+	}
+}
+
+// Testa que tokens expirados e emails desconhecidos nao redefinem senha.
+func TestPasswordServiceResetRejects(t *testing.T) {
+	user := newPasswordTestUser(t, "old-secret")
+	repo := &userRepoFake{users: map[string]domain.User{user.Email: user}}
+	tokens := &passwordResetRepoFake{}
+	notifier := &notifierFake{}
+	service := NewPasswordService(repo, tokens, nil, notifier, nil, "http://localhost")
+	ctx := context.Background()
+
+	if err := service.RequestReset(ctx, "unknown@example.com"); err != nil {
+		t.Fatalf("expected silent success for unknown email, got %v", err)
+	}
+	if len(notifier.notifications) != 0 {
+		t.Fatalf("expected no notification, got %#v", notifier.notifications)
+	}
+
+	if err := service.RequestReset(ctx, user.Email); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	body := notifier.notifications[0].Body
+	token := strings.Fields(body[strings.Index(body, "token=")+len("token="):])[0]
+
+	service.now = func() time.Time { return time.Now().Add(2 * passwordResetTTL) }
+	if err := service.ResetPassword(ctx, token, "brand-new-secret"); err != ports.ErrNotFound {
+		t.Fatalf("expected expired token rejected, got %v", err)
+	}
+}
+
+// Testa que usuario desativado depois do pedido nao consegue redefinir a senha.
+func TestPasswordServiceResetRejectsInactiveUser(t *testing.T) {
+	user := newPasswordTestUser(t, "old-secret")
+	repo := &userRepoFake{users: map[string]domain.User{user.Email: user}}
+	tokens := &passwordResetRepoFake{}
+	notifier := &notifierFake{}
+	service := NewPasswordService(repo, tokens, nil, notifier, nil, "http://localhost")
+	ctx := context.Background()
+
+	if err := service.RequestReset(ctx, user.Email); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	body := notifier.notifications[0].Body
+	token := strings.Fields(body[strings.Index(body, "token=")+len("token="):])[0]
+
+	inactive := repo.users[user.Email]
+	inactive.Active = false
+	repo.users[user.Email] = inactive
+
+	if err := service.ResetPassword(ctx, token, "brand-new-secret"); err != ports.ErrNotFound {
+		t.Fatalf("expected inactive user rejected, got %v", err)
+	}
+	if err := bcrypt.CompareHashAndPassword([]byte(repo.users[user.Email].PasswordHash), []byte("old-secret")); err != nil {
+		t.Fatalf("expected password unchanged, got %v", err)
+	}
+}
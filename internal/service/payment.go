@@ -3,6 +3,7 @@ package service
 import (
 	"context"
 	"errors"
+	"fmt"
 	"time"
 
 	"github.com/PabloPavan/jaiu/internal/domain"
@@ -16,6 +17,7 @@ type PaymentService struct {
 	periods       ports.BillingPeriodRepository
 	balances      ports.SubscriptionBalanceRepository
 	allocations   ports.PaymentAllocationRepository
+	transfers     ports.PaymentCreditTransferRepository
 	audit         ports.AuditRepository
 	txRunner      ports.PaymentTxRunner
 	now           func() time.Time
@@ -44,25 +46,23 @@ func NewPaymentService(
 	}
 }
 
+// SetCreditTransfers permite ao estorno desfazer o credito que uma troca de
+// plano levou do pagamento para outra assinatura.
+func (s *PaymentService) SetCreditTransfers(repo ports.PaymentCreditTransferRepository) {
+	s.transfers = repo
+}
+
 func (s *PaymentService) Register(ctx context.Context, payment domain.Payment) (domain.Payment, error) {
 	if s.txRunner != nil {
 		var result domain.Payment
-		err := s.txRunner.RunSerializable(ctx, func(ctx context.Context, deps ports.PaymentDependencies) error {
-			txService := &PaymentService{
-				repo:          deps.Payments,
-				subscriptions: deps.Subscriptions,
-				plans:         deps.Plans,
-				periods:       deps.BillingPeriods,
-				balances:      deps.Balances,
-				allocations:   deps.Allocations,
-				audit:         deps.Audit,
-				now:           s.now,
-			}
+		err := s.inTx(ctx, func(ctx context.Context, tx *PaymentService) error {
 			var err error
-			result, err = txService.register(ctx, payment)
+			result, err = tx.register(ctx, payment)
 			return err
 		})
 		if err != nil {
+			// Os eventos gravados na transacao foram desfeitos com ela.
+			recordAuditFailure(ctx, s.audit, "payment.create", "payment", payment.ID, paymentCreateMetadata(payment), err)
 			return domain.Payment{}, err
 		}
 		return result, nil
@@ -71,22 +71,47 @@ func (s *PaymentService) Register(ctx context.Context, payment domain.Payment) (
 	return s.register(ctx, payment)
 }
 
-func (s *PaymentService) register(ctx context.Context, payment domain.Payment) (domain.Payment, error) {
-	metadata := map[string]any{
+// inTx executa fn com os repositorios de uma transacao serializavel; sem
+// txRunner, fn usa o proprio servico.
+func (s *PaymentService) inTx(ctx context.Context, fn func(context.Context, *PaymentService) error) error {
+	if s.txRunner == nil {
+		return fn(ctx, s)
+	}
+	return s.txRunner.RunSerializable(ctx, func(ctx context.Context, deps ports.PaymentDependencies) error {
+		return fn(ctx, &PaymentService{
+			repo:          deps.Payments,
+			subscriptions: deps.Subscriptions,
+			plans:         deps.Plans,
+			periods:       deps.BillingPeriods,
+			balances:      deps.Balances,
+			allocations:   deps.Allocations,
+			transfers:     deps.Transfers,
+			audit:         deps.Audit,
+			now:           s.now,
+		})
+	})
+}
+
+func paymentCreateMetadata(payment domain.Payment) map[string]any {
+	return map[string]any{
 		"amount_cents":    payment.AmountCents,
 		"method":          string(payment.Method),
 		"subscription_id": payment.SubscriptionID,
 		"idempotency_key": payment.IdempotencyKey,
 	}
+}
+
+func (s *PaymentService) register(ctx context.Context, payment domain.Payment) (domain.Payment, error) {
+	metadata := paymentCreateMetadata(payment)
 	recordAuditAttempt(ctx, s.audit, "payment.create", "payment", payment.ID, metadata)
 
 	if payment.SubscriptionID == "" {
-		err := errors.New("assinatura e obrigatoria")
+		err := validationError("assinatura e obrigatoria")
 		recordAuditFailure(ctx, s.audit, "payment.create", "payment", payment.ID, metadata, err)
 		return domain.Payment{}, err
 	}
 	if payment.AmountCents <= 0 {
-		err := errors.New("valor deve ser maior que zero")
+		err := validationError("valor deve ser maior que zero")
 		recordAuditFailure(ctx, s.audit, "payment.create", "payment", payment.ID, metadata, err)
 		return domain.Payment{}, err
 	}
@@ -117,7 +142,7 @@ func (s *PaymentService) register(ctx context.Context, payment domain.Payment) (
 		return domain.Payment{}, err
 	}
 	if subscription.Status != domain.SubscriptionActive {
-		err := errors.New("assinatura inativa")
+		err := validationError("assinatura inativa")
 		recordAuditFailure(ctx, s.audit, "payment.create", "payment", payment.ID, metadata, err)
 		return domain.Payment{}, err
 	}
@@ -138,17 +163,17 @@ func (s *PaymentService) register(ctx context.Context, payment domain.Payment) (
 		payment.Kind = domain.PaymentFull
 	}
 	if !payment.Method.IsValid() {
-		err := errors.New("metodo de pagamento invalido")
+		err := validationError("metodo de pagamento invalido")
 		recordAuditFailure(ctx, s.audit, "payment.create", "payment", payment.ID, metadata, err)
 		return domain.Payment{}, err
 	}
 	if !payment.Status.IsValid() {
-		err := errors.New("status de pagamento invalido")
+		err := validationError("status de pagamento invalido")
 		recordAuditFailure(ctx, s.audit, "payment.create", "payment", payment.ID, metadata, err)
 		return domain.Payment{}, err
 	}
 	if !payment.Kind.IsValid() {
-		err := errors.New("tipo de pagamento invalido")
+		err := validationError("tipo de pagamento invalido")
 		recordAuditFailure(ctx, s.audit, "payment.create", "payment", payment.ID, metadata, err)
 		return domain.Payment{}, err
 	}
@@ -158,6 +183,11 @@ func (s *PaymentService) register(ctx context.Context, payment domain.Payment) (
 		recordAuditFailure(ctx, s.audit, "payment.create", "payment", payment.ID, metadata, err)
 		return domain.Payment{}, err
 	}
+	// Os encargos sao atualizados ate hoje antes de distribuir o pagamento.
+	if _, err := applyLateFees(ctx, s.periods, subscription, plan, today); err != nil {
+		recordAuditFailure(ctx, s.audit, "payment.create", "payment", payment.ID, metadata, err)
+		return domain.Payment{}, err
+	}
 
 	created, err := s.repo.Create(ctx, payment)
 	if err != nil {
@@ -169,7 +199,9 @@ func (s *PaymentService) register(ctx context.Context, payment domain.Payment) (
 				recordAuditSuccess(ctx, s.audit, "payment.create", "payment", existing.ID, idemMetadata)
 				return existing, nil
 			}
-			return domain.Payment{}, findErr
+			// Dentro da transacao o pagamento concorrente com a mesma chave ainda
+			// nao e visivel; uma nova tentativa o encontra logo no inicio.
+			return domain.Payment{}, fmt.Errorf("%w: %w", ports.ErrSerialization, err)
 		}
 		recordAuditFailure(ctx, s.audit, "payment.create", "payment", payment.ID, metadata, err)
 		return domain.Payment{}, err
@@ -199,7 +231,7 @@ func (s *PaymentService) register(ctx context.Context, payment domain.Payment) (
 
 func (s *PaymentService) Update(ctx context.Context, payment domain.Payment) (domain.Payment, error) {
 	if payment.ID == "" {
-		err := errors.New("pagamento invalido")
+		err := validationError("pagamento invalido")
 		recordAuditFailure(ctx, s.audit, "payment.update", "payment", payment.ID, nil, err)
 		return domain.Payment{}, err
 	}
@@ -230,28 +262,28 @@ func (s *PaymentService) Update(ctx context.Context, payment domain.Payment) (do
 	payment.CreditCents = current.CreditCents
 	payment.IdempotencyKey = current.IdempotencyKey
 	if !payment.Method.IsValid() {
-		err := errors.New("metodo de pagamento invalido")
+		err := validationError("metodo de pagamento invalido")
 		recordAuditFailure(ctx, s.audit, "payment.update", "payment", payment.ID, nil, err)
 		return domain.Payment{}, err
 	}
 	if !payment.Status.IsValid() {
-		err := errors.New("status de pagamento invalido")
+		err := validationError("status de pagamento invalido")
 		recordAuditFailure(ctx, s.audit, "payment.update", "payment", payment.ID, nil, err)
 		return domain.Payment{}, err
 	}
 	if !payment.Kind.IsValid() {
-		err := errors.New("tipo de pagamento invalido")
+		err := validationError("tipo de pagamento invalido")
 		recordAuditFailure(ctx, s.audit, "payment.update", "payment", payment.ID, nil, err)
 		return domain.Payment{}, err
 	}
 
 	if payment.SubscriptionID != current.SubscriptionID || payment.AmountCents != current.AmountCents || !sameDayTime(payment.PaidAt, current.PaidAt) {
-		err := errors.New("para alterar valor ou data, estorne e registre um novo pagamento")
+		err := validationError("para alterar valor ou data, estorne e registre um novo pagamento")
 		recordAuditFailure(ctx, s.audit, "payment.update", "payment", payment.ID, nil, err)
 		return domain.Payment{}, err
 	}
 	if payment.Status != current.Status {
-		err := errors.New("use o estorno para alterar o status")
+		err := validationError("use o estorno para alterar o status")
 		recordAuditFailure(ctx, s.audit, "payment.update", "payment", payment.ID, nil, err)
 		return domain.Payment{}, err
 	}
@@ -261,14 +293,14 @@ func (s *PaymentService) Update(ctx context.Context, payment domain.Payment) (do
 		recordAuditFailure(ctx, s.audit, "payment.update", "payment", payment.ID, nil, err)
 		return domain.Payment{}, err
 	}
-	recordAuditSuccess(ctx, s.audit, "payment.update", "payment", updated.ID, map[string]any{
+	recordAuditSuccess(ctx, s.audit, "payment.update", "payment", updated.ID, withAuditChanges(map[string]any{
 		"amount_cents":    updated.AmountCents,
 		"credit_cents":    updated.CreditCents,
 		"kind":            string(updated.Kind),
 		"method":          string(updated.Method),
 		"status":          string(updated.Status),
 		"subscription_id": updated.SubscriptionID,
-	})
+	}, paymentAuditFields(current), paymentAuditFields(updated)))
 	return updated, nil
 }
 
@@ -276,14 +308,29 @@ func (s *PaymentService) FindByID(ctx context.Context, paymentID string) (domain
 	return s.repo.FindByID(ctx, paymentID)
 }
 
+// Reverse desfaz as alocacoes, o credito e o status do pagamento na mesma
+// transacao.
 func (s *PaymentService) Reverse(ctx context.Context, paymentID string) (domain.Payment, error) {
 	recordAuditAttempt(ctx, s.audit, "payment.reverse", "payment", paymentID, nil)
 
-	payment, err := s.repo.FindByID(ctx, paymentID)
+	var updated domain.Payment
+	err := s.inTx(ctx, func(ctx context.Context, tx *PaymentService) error {
+		var err error
+		updated, err = tx.reverse(ctx, paymentID)
+		return err
+	})
 	if err != nil {
 		recordAuditFailure(ctx, s.audit, "payment.reverse", "payment", paymentID, nil, err)
 		return domain.Payment{}, err
 	}
+	return updated, nil
+}
+
+func (s *PaymentService) reverse(ctx context.Context, paymentID string) (domain.Payment, error) {
+	payment, err := s.repo.FindByID(ctx, paymentID)
+	if err != nil {
+		return domain.Payment{}, err
+	}
 	if payment.Status == domain.PaymentReversed {
 		recordAuditSuccess(ctx, s.audit, "payment.reverse", "payment", payment.ID, map[string]any{
 			"status": string(payment.Status),
@@ -293,24 +340,19 @@ func (s *PaymentService) Reverse(ctx context.Context, paymentID string) (domain.
 
 	if s.allocations != nil && s.periods != nil {
 		if s.subscriptions == nil {
-			err := errors.New("assinaturas indisponiveis")
-			recordAuditFailure(ctx, s.audit, "payment.reverse", "payment", payment.ID, nil, err)
-			return domain.Payment{}, err
+			return domain.Payment{}, errors.New("assinaturas indisponiveis")
 		}
 		subscription, err := s.subscriptions.FindByID(ctx, payment.SubscriptionID)
 		if err != nil {
-			recordAuditFailure(ctx, s.audit, "payment.reverse", "payment", payment.ID, nil, err)
 			return domain.Payment{}, err
 		}
 		if err := s.rollbackPayment(ctx, payment, subscription); err != nil {
-			recordAuditFailure(ctx, s.audit, "payment.reverse", "payment", payment.ID, nil, err)
 			return domain.Payment{}, err
 		}
 	}
 
 	if payment.CreditCents > 0 && s.balances != nil {
 		if _, err := s.balances.Add(ctx, payment.SubscriptionID, -payment.CreditCents); err != nil {
-			recordAuditFailure(ctx, s.audit, "payment.reverse", "payment", payment.ID, nil, err)
 			return domain.Payment{}, err
 		}
 		payment.CreditCents = 0
@@ -320,7 +362,6 @@ func (s *PaymentService) Reverse(ctx context.Context, paymentID string) (domain.
 	payment.Kind = domain.PaymentFull
 	updated, err := s.repo.Update(ctx, payment)
 	if err != nil {
-		recordAuditFailure(ctx, s.audit, "payment.reverse", "payment", payment.ID, nil, err)
 		return domain.Payment{}, err
 	}
 	recordAuditSuccess(ctx, s.audit, "payment.reverse", "payment", updated.ID, map[string]any{
@@ -334,10 +375,106 @@ func (s *PaymentService) ListBySubscription(ctx context.Context, subscriptionID
 	return s.repo.ListBySubscription(ctx, subscriptionID)
 }
 
+func (s *PaymentService) ListBySubscriptionPage(ctx context.Context, subscriptionID string, page ports.PageRequest) ([]domain.Payment, error) {
+	return s.repo.ListBySubscriptionPage(ctx, subscriptionID, page)
+}
+
 func (s *PaymentService) ListByPeriod(ctx context.Context, start, end time.Time) ([]domain.Payment, error) {
 	return s.repo.ListByPeriod(ctx, start, end)
 }
 
+func (s *PaymentService) ListByPeriodPage(ctx context.Context, start, end time.Time, page ports.PageRequest) ([]domain.Payment, error) {
+	return s.repo.ListByPeriodPage(ctx, start, end, page)
+}
+
+func (s *PaymentService) ListBillingPeriods(ctx context.Context, subscriptionID string) ([]domain.BillingPeriod, error) {
+	if s.periods == nil {
+		return nil, errors.New("periodos de cobranca indisponiveis")
+	}
+	return s.periods.ListBySubscription(ctx, subscriptionID)
+}
+
+func (s *PaymentService) ListBillingPeriodsPage(ctx context.Context, subscriptionID string, page ports.PageRequest) ([]domain.BillingPeriod, error) {
+	if s.periods == nil {
+		return nil, errors.New("periodos de cobranca indisponiveis")
+	}
+	return s.periods.ListBySubscriptionPage(ctx, subscriptionID, page)
+}
+
+// WaiveLateFees dispensa a multa e os juros ainda nao pagos da competencia.
+// O que ja foi pago de encargos continua registrado e a competencia deixa de
+// acumular novos encargos.
+func (s *PaymentService) WaiveLateFees(ctx context.Context, subscriptionID, periodID string) (domain.BillingPeriod, error) {
+	metadata := map[string]any{"subscription_id": subscriptionID}
+	recordAuditAttempt(ctx, s.audit, "billing_period.fee_waive", "billing_period", periodID, metadata)
+
+	var updated domain.BillingPeriod
+	err := s.inTx(ctx, func(ctx context.Context, tx *PaymentService) error {
+		var err error
+		updated, err = tx.waiveLateFees(ctx, subscriptionID, periodID, metadata)
+		return err
+	})
+	if err != nil {
+		recordAuditFailure(ctx, s.audit, "billing_period.fee_waive", "billing_period", periodID, metadata, err)
+		return domain.BillingPeriod{}, err
+	}
+	return updated, nil
+}
+
+// waiveLateFees le e grava a competencia na mesma transacao dos pagamentos,
+// para que um pagamento concorrente nao seja sobrescrito pela dispensa.
+func (s *PaymentService) waiveLateFees(ctx context.Context, subscriptionID, periodID string, metadata map[string]any) (domain.BillingPeriod, error) {
+	if s.periods == nil || s.subscriptions == nil {
+		return domain.BillingPeriod{}, errors.New("periodos de cobranca indisponiveis")
+	}
+
+	subscription, err := s.subscriptions.FindByID(ctx, subscriptionID)
+	if err != nil {
+		return domain.BillingPeriod{}, err
+	}
+	paymentDay, err := effectivePaymentDay(subscription)
+	if err != nil {
+		return domain.BillingPeriod{}, err
+	}
+
+	periods, err := s.periods.ListBySubscription(ctx, subscriptionID)
+	if err != nil {
+		return domain.BillingPeriod{}, err
+	}
+	var period domain.BillingPeriod
+	found := false
+	for _, candidate := range periods {
+		if candidate.ID == periodID {
+			period = candidate
+			found = true
+			break
+		}
+	}
+	if !found {
+		return domain.BillingPeriod{}, ports.ErrNotFound
+	}
+	if period.FeeWaivedAt != nil {
+		recordAuditSuccess(ctx, s.audit, "billing_period.fee_waive", "billing_period", period.ID, metadata)
+		return period, nil
+	}
+
+	waived := period.FeeCents - period.FeePaidCents()
+	waivedAt := s.now()
+	period.FeeCents = period.FeePaidCents()
+	period.FeeWaivedAt = &waivedAt
+	period = settlePeriod(period, dateOnly(waivedAt), paymentDay)
+	updated, err := s.periods.Update(ctx, period)
+	if err != nil {
+		return domain.BillingPeriod{}, err
+	}
+
+	successMetadata := copyMetadata(metadata)
+	successMetadata["waived_cents"] = waived
+	successMetadata["status"] = string(updated.Status)
+	recordAuditSuccess(ctx, s.audit, "billing_period.fee_waive", "billing_period", updated.ID, successMetadata)
+	return updated, nil
+}
+
 type paymentApplicationResult struct {
 	Kind        domain.PaymentKind
 	CreditCents int64
@@ -360,6 +497,8 @@ func (s *PaymentService) applyPayment(ctx context.Context, payment domain.Paymen
 		return paymentApplicationResult{}, err
 	}
 
+	// As competencias sao quitadas da mais antiga para a mais nova e, em cada
+	// uma, os encargos (multa e juros) vem antes do valor da competencia.
 	remaining := payment.AmountCents
 	partial := false
 
@@ -367,18 +506,15 @@ func (s *PaymentService) applyPayment(ctx context.Context, payment domain.Paymen
 		if remaining == 0 {
 			break
 		}
-		if period.AmountPaidCents >= period.AmountDueCents {
-			continue
-		}
-
-		applied := minInt64(remaining, period.AmountDueCents-period.AmountPaidCents)
-		if applied <= 0 {
+		outstanding := period.OutstandingCents()
+		if outstanding <= 0 {
 			continue
 		}
 
+		applied := minInt64(remaining, outstanding)
 		period.AmountPaidCents += applied
-		period.Status = resolvePeriodStatus(period, today, paymentDay)
-		if period.AmountPaidCents < period.AmountDueCents {
+		period = settlePeriod(period, today, paymentDay)
+		if period.OutstandingCents() > 0 {
 			partial = true
 		}
 
@@ -414,6 +550,9 @@ func (s *PaymentService) applyPayment(ctx context.Context, payment domain.Paymen
 	}, nil
 }
 
+// rollbackPayment desfaz as alocacoes do pagamento. Quando uma troca de plano
+// levou parte dele para outra assinatura, as alocacoes nas competencias dela e
+// o credito deixado no saldo dela tambem sao desfeitos.
 func (s *PaymentService) rollbackPayment(ctx context.Context, payment domain.Payment, subscription domain.Subscription) error {
 	if s.periods == nil || s.allocations == nil {
 		return errors.New("dependencias de estorno indisponiveis")
@@ -423,41 +562,80 @@ func (s *PaymentService) rollbackPayment(ctx context.Context, payment domain.Pay
 	if err != nil {
 		return err
 	}
+	var transfers []domain.PaymentCreditTransfer
+	if s.transfers != nil {
+		transfers, err = s.transfers.ListByPayment(ctx, payment.ID)
+		if err != nil {
+			return err
+		}
+	}
 
-	if len(allocations) == 0 {
+	if len(allocations) == 0 && len(transfers) == 0 {
 		return nil
 	}
 
-	periods, err := s.periods.ListBySubscription(ctx, payment.SubscriptionID)
-	if err != nil {
-		return err
+	subscriptions := []domain.Subscription{subscription}
+	for _, transfer := range transfers {
+		if s.subscriptions == nil {
+			return errors.New("assinaturas indisponiveis")
+		}
+		target, err := s.subscriptions.FindByID(ctx, transfer.SubscriptionID)
+		if err != nil {
+			return err
+		}
+		subscriptions = append(subscriptions, target)
 	}
 
-	periodMap := make(map[string]domain.BillingPeriod, len(periods))
-	for _, period := range periods {
-		periodMap[period.ID] = period
+	periodMap := make(map[string]domain.BillingPeriod)
+	paymentDays := make(map[string]int)
+	for _, sub := range subscriptions {
+		paymentDay, err := effectivePaymentDay(sub)
+		if err != nil {
+			return err
+		}
+		periods, err := s.periods.ListBySubscription(ctx, sub.ID)
+		if err != nil {
+			return err
+		}
+		for _, period := range periods {
+			periodMap[period.ID] = period
+			paymentDays[period.ID] = paymentDay
+		}
 	}
 
 	today := dateOnly(s.now())
-	paymentDay, err := effectivePaymentDay(subscription)
-	if err != nil {
-		return err
-	}
 	for _, allocation := range allocations {
 		period, ok := periodMap[allocation.BillingPeriodID]
-		if !ok {
+		if !ok || allocation.AmountCents == 0 {
 			continue
 		}
 		period.AmountPaidCents -= allocation.AmountCents
 		if period.AmountPaidCents < 0 {
 			period.AmountPaidCents = 0
 		}
-		period.Status = resolvePeriodStatus(period, today, paymentDay)
+		period = settlePeriod(period, today, paymentDays[period.ID])
 		if _, err := s.periods.Update(ctx, period); err != nil {
 			return err
 		}
 	}
 
+	for _, transfer := range transfers {
+		if transfer.CreditCents == 0 {
+			continue
+		}
+		if s.balances == nil {
+			return errors.New("saldo indisponivel para estornar credito")
+		}
+		if _, err := s.balances.Add(ctx, transfer.SubscriptionID, -transfer.CreditCents); err != nil {
+			return err
+		}
+	}
+	if len(transfers) > 0 {
+		if err := s.transfers.DeleteByPayment(ctx, payment.ID); err != nil {
+			return err
+		}
+	}
+
 	return s.allocations.DeleteByPayment(ctx, payment.ID)
 }
 
@@ -469,7 +647,7 @@ func (s *PaymentService) applyBalance(ctx context.Context, subscription domain.S
 	return applySubscriptionBalance(ctx, s.balances, s.periods, subscription, today)
 }
 
-func buildPeriod(subscriptionID string, start time.Time, durationDays int, amountDue int64) domain.BillingPeriod {
+func buildPeriod(subscriptionID string, start time.Time, durationDays int, priceCents, discountCents int64) domain.BillingPeriod {
 	startDate := dateOnly(start)
 	endDate := startDate.AddDate(0, 0, durationDays)
 
@@ -477,14 +655,17 @@ func buildPeriod(subscriptionID string, start time.Time, durationDays int, amoun
 		SubscriptionID: subscriptionID,
 		PeriodStart:    startDate,
 		PeriodEnd:      endDate,
-		AmountDueCents: amountDue,
+		AmountDueCents: priceCents - discountCents,
+		DiscountCents:  discountCents,
 		Status:         domain.BillingOpen,
+		Installment:    1,
+		Installments:   1,
 	}
 }
 
 func resolvePeriodStatus(period domain.BillingPeriod, today time.Time, paymentDay int) domain.BillingPeriodStatus {
-	dueDate := dueDateForPeriod(period.PeriodStart, paymentDay)
-	if period.AmountPaidCents >= period.AmountDueCents {
+	dueDate := periodDueDate(period, paymentDay)
+	if period.AmountPaidCents >= period.TotalDueCents() {
 		return domain.BillingPaid
 	}
 	if period.AmountPaidCents > 0 {
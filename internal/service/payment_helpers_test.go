@@ -10,7 +10,7 @@ import (
 // Testa buildPeriod criando datas e status corretos.
 func TestBuildPeriod(t *testing.T) {
 	start := time.Date(2024, 1, 10, 15, 0, 0, 0, time.UTC)
-	period := buildPeriod("sub-1", start, 30, 1000)
+	period := buildPeriod("sub-1", start, 30, 1000, 0)
 	if period.PeriodStart.Hour() != 0 || period.PeriodStart.Minute() != 0 {
 		t.Fatal("expected start date normalized to midnight")
 	}
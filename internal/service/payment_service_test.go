@@ -2,10 +2,12 @@ package service
 
 import (
 	"context"
+	"errors"
 	"testing"
 	"time"
 
 	"github.com/PabloPavan/jaiu/internal/domain"
+	"github.com/PabloPavan/jaiu/internal/ports"
 )
 
 // Testa Register validando assinatura obrigatoria.
@@ -58,6 +60,53 @@ func TestPaymentServiceRegisterIdempotent(t *testing.T) {
 	}
 }
 
+// Testa que um conflito de idempotencia ainda invisivel na transacao pede nova
+// tentativa e registra a falha fora dela.
+func TestPaymentServiceRegisterIdempotencyConflictRetries(t *testing.T) {
+	payments := &paymentRepoFake{createErr: ports.ErrConflict, findIdemErr: ports.ErrNotFound}
+	subscriptions := &subscriptionRepoFake{
+		subscriptions: map[string]domain.Subscription{
+			"sub-1": {
+				ID:         "sub-1",
+				PlanID:     "plan-1",
+				Status:     domain.SubscriptionActive,
+				StartDate:  time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+				PaymentDay: 1,
+			},
+		},
+	}
+	plans := &planRepoFake{
+		plans: map[string]domain.Plan{
+			"plan-1": {ID: "plan-1", DurationDays: 30, PriceCents: 1000},
+		},
+	}
+	deps := ports.PaymentDependencies{
+		Payments:       payments,
+		Subscriptions:  subscriptions,
+		Plans:          plans,
+		BillingPeriods: &billingPeriodRepoFake{},
+		Balances:       &balanceRepoFake{},
+		Allocations:    &paymentAllocationRepoFake{},
+		Audit:          &auditRepoFake{},
+	}
+	audit := &auditRepoFake{}
+	service := NewPaymentService(payments, subscriptions, plans, deps.BillingPeriods, deps.Balances, deps.Allocations, audit, &paymentTxRunnerFake{deps: deps})
+	service.now = func() time.Time { return time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC) }
+
+	_, err := service.Register(context.Background(), domain.Payment{
+		SubscriptionID: "sub-1",
+		AmountCents:    1000,
+		Method:         domain.PaymentCash,
+		IdempotencyKey: "idem",
+	})
+	if !errors.Is(err, ports.ErrSerialization) {
+		t.Fatalf("expected serialization error, got %v", err)
+	}
+	if len(audit.events) != 1 || audit.events[0].Action != "payment.create.failure" {
+		t.Fatalf("expected failure event outside the transaction, got %#v", audit.events)
+	}
+}
+
 // Testa Register bloqueando metodo invalido.
 func TestPaymentServiceRegisterInvalidMethod(t *testing.T) {
 	payments := &paymentRepoFake{}
@@ -124,15 +173,26 @@ func TestPaymentServiceUpdateSuccess(t *testing.T) {
 		Kind:           domain.PaymentFull,
 	}
 	repo := &paymentRepoFake{payments: map[string]domain.Payment{"payment-1": current}}
-	service := NewPaymentService(repo, nil, nil, nil, nil, nil, nil, nil)
+	audit := &auditRepoFake{}
+	service := NewPaymentService(repo, nil, nil, nil, nil, nil, audit, nil)
 
-	updated, err := service.Update(context.Background(), domain.Payment{ID: "payment-1"})
+	updated, err := service.Update(context.Background(), domain.Payment{ID: "payment-1", Reference: "PIX-123"})
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
 	if updated.ID != "payment-1" {
 		t.Fatalf("expected payment-1, got %q", updated.ID)
 	}
+
+	event := audit.events[len(audit.events)-1]
+	changes, ok := event.Metadata["changes"].(map[string]any)
+	if !ok || len(changes) != 1 {
+		t.Fatalf("expected only reference change, got %#v", event.Metadata["changes"])
+	}
+	reference := changes["reference"].(map[string]any)
+	if reference["from"] != "" || reference["to"] != "PIX-123" {
+		t.Fatalf("unexpected reference change: %#v", reference)
+	}
 }
 
 // Testa Reverse retornando imediatamente quando ja estornado.
@@ -156,6 +216,53 @@ func TestPaymentServiceReverseAlreadyReversed(t *testing.T) {
 	}
 }
 
+// Testa que Reverse roda em transacao: uma falha ao gravar o pagamento desfaz
+// o estorno das alocacoes e so deixa o evento de falha.
+func TestPaymentServiceReverseRollsBackOnFailure(t *testing.T) {
+	payments := &paymentRepoFake{
+		payments: map[string]domain.Payment{
+			"payment-1": {ID: "payment-1", SubscriptionID: "sub-1", AmountCents: 1000, Status: domain.PaymentConfirmed},
+		},
+		updateErr: errors.New("update failed"),
+	}
+	subscriptions := &subscriptionRepoFake{
+		subscriptions: map[string]domain.Subscription{
+			"sub-1": {ID: "sub-1", PlanID: "plan-1", Status: domain.SubscriptionActive, StartDate: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), PaymentDay: 1},
+		},
+	}
+	periods := &billingPeriodRepoFake{periods: map[string]domain.BillingPeriod{
+		"period-1": {ID: "period-1", SubscriptionID: "sub-1", PeriodStart: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), PeriodEnd: time.Date(2024, 1, 31, 0, 0, 0, 0, time.UTC), AmountDueCents: 1000, AmountPaidCents: 1000, Status: domain.BillingPaid},
+	}}
+	allocations := &paymentAllocationRepoFake{allocations: map[string][]domain.PaymentAllocation{
+		"payment-1": {{PaymentID: "payment-1", BillingPeriodID: "period-1", AmountCents: 1000}},
+	}}
+	txAudit := &auditRepoFake{}
+	runner := &paymentTxRunnerFake{deps: ports.PaymentDependencies{
+		Payments:       payments,
+		Subscriptions:  subscriptions,
+		BillingPeriods: periods,
+		Balances:       &balanceRepoFake{},
+		Allocations:    allocations,
+		Audit:          txAudit,
+	}}
+	audit := &auditRepoFake{}
+	service := NewPaymentService(&paymentRepoFake{}, nil, nil, nil, nil, nil, audit, runner)
+	service.now = func() time.Time { return time.Date(2024, 1, 10, 0, 0, 0, 0, time.UTC) }
+
+	if _, err := service.Reverse(context.Background(), "payment-1"); err == nil {
+		t.Fatal("expected error")
+	}
+	if runner.rollbacks != 1 {
+		t.Fatalf("expected rolled back transaction, got %d", runner.rollbacks)
+	}
+	if len(txAudit.events) != 0 {
+		t.Fatalf("expected no events inside the rolled back transaction, got %#v", txAudit.events)
+	}
+	if len(audit.events) != 2 || audit.events[0].Action != "payment.reverse.attempt" || audit.events[1].Action != "payment.reverse.failure" {
+		t.Fatalf("expected attempt and failure outside the transaction, got %#v", audit.events)
+	}
+}
+
 // Testa Reverse exigindo dependencias completas quando ha alocacoes.
 func TestPaymentServiceReverseMissingSubscriptions(t *testing.T) {
 	repo := &paymentRepoFake{
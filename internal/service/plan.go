@@ -13,6 +13,7 @@ type PlanService struct {
 	repo          ports.PlanRepository
 	subscriptions ports.SubscriptionRepository
 	audit         ports.AuditRepository
+	txRunner      ports.TxRunner
 	now           func() time.Time
 }
 
@@ -20,15 +21,25 @@ func NewPlanService(repo ports.PlanRepository, subscriptions ports.SubscriptionR
 	return &PlanService{repo: repo, subscriptions: subscriptions, audit: audit, now: time.Now}
 }
 
+// SetTxRunner faz a alteracao do plano, o encerramento das assinaturas e o
+// evento de sucesso serem gravados em uma unica transacao.
+func (s *PlanService) SetTxRunner(runner ports.TxRunner) {
+	s.txRunner = runner
+}
+
 func (s *PlanService) Create(ctx context.Context, plan domain.Plan) (domain.Plan, error) {
 	now := s.now()
 	plan.CreatedAt = now
 	plan.UpdatedAt = now
 
 	metadata := map[string]any{
-		"active":        plan.Active,
-		"duration_days": plan.DurationDays,
-		"price_cents":   plan.PriceCents,
+		"active":            plan.Active,
+		"duration_days":     plan.DurationDays,
+		"price_cents":       plan.PriceCents,
+		"late_fee_bps":      plan.LateFeeBasisPoints,
+		"late_interest_bps": plan.LateInterestBasisPoints,
+		"max_freeze_days":   plan.MaxFreezeDays,
+		"installments":      plan.InstallmentCount(),
 	}
 	recordAuditAttempt(ctx, s.audit, "plan.create", "plan", plan.ID, metadata)
 
@@ -51,45 +62,61 @@ func (s *PlanService) Update(ctx context.Context, plan domain.Plan) (domain.Plan
 	}
 	recordAuditAttempt(ctx, s.audit, "plan.update", "plan", plan.ID, metadata)
 
-	updated, err := s.repo.Update(ctx, plan)
+	var updated domain.Plan
+	err := s.inTx(ctx, func(ctx context.Context, tx *PlanService) error {
+		current, err := tx.repo.FindByID(ctx, plan.ID)
+		if err != nil {
+			return err
+		}
+
+		updated, err = tx.repo.Update(ctx, plan)
+		if err != nil {
+			return err
+		}
+		if !updated.Active {
+			if err := tx.endSubscriptionsForPlan(ctx, updated.ID); err != nil {
+				return err
+			}
+		}
+		recordAuditSuccess(ctx, tx.audit, "plan.update", "plan", updated.ID, withAuditChanges(metadata, planAuditFields(current), planAuditFields(updated)))
+		return nil
+	})
 	if err != nil {
 		recordAuditFailure(ctx, s.audit, "plan.update", "plan", plan.ID, metadata, err)
 		return domain.Plan{}, err
 	}
-	if !updated.Active {
-		if err := s.endSubscriptionsForPlan(ctx, updated.ID); err != nil {
-			recordAuditFailure(ctx, s.audit, "plan.update", "plan", updated.ID, metadata, err)
-			return updated, err
-		}
-	}
-	recordAuditSuccess(ctx, s.audit, "plan.update", "plan", updated.ID, metadata)
 	return updated, nil
 }
 
 func (s *PlanService) Deactivate(ctx context.Context, planID string) (domain.Plan, error) {
 	recordAuditAttempt(ctx, s.audit, "plan.deactivate", "plan", planID, nil)
 
-	plan, err := s.repo.FindByID(ctx, planID)
-	if err != nil {
-		recordAuditFailure(ctx, s.audit, "plan.deactivate", "plan", planID, nil, err)
-		return domain.Plan{}, err
-	}
+	var updated domain.Plan
+	err := s.inTx(ctx, func(ctx context.Context, tx *PlanService) error {
+		plan, err := tx.repo.FindByID(ctx, planID)
+		if err != nil {
+			return err
+		}
 
-	plan.Active = false
-	plan.UpdatedAt = s.now()
+		plan.Active = false
+		plan.UpdatedAt = s.now()
 
-	updated, err := s.repo.Update(ctx, plan)
+		updated, err = tx.repo.Update(ctx, plan)
+		if err != nil {
+			return err
+		}
+		if err := tx.endSubscriptionsForPlan(ctx, updated.ID); err != nil {
+			return err
+		}
+		recordAuditSuccess(ctx, tx.audit, "plan.deactivate", "plan", updated.ID, map[string]any{
+			"active": updated.Active,
+		})
+		return nil
+	})
 	if err != nil {
 		recordAuditFailure(ctx, s.audit, "plan.deactivate", "plan", planID, nil, err)
 		return domain.Plan{}, err
 	}
-	if err := s.endSubscriptionsForPlan(ctx, updated.ID); err != nil {
-		recordAuditFailure(ctx, s.audit, "plan.deactivate", "plan", updated.ID, nil, err)
-		return updated, err
-	}
-	recordAuditSuccess(ctx, s.audit, "plan.deactivate", "plan", updated.ID, map[string]any{
-		"active": updated.Active,
-	})
 	return updated, nil
 }
 
@@ -101,6 +128,11 @@ func (s *PlanService) ListActive(ctx context.Context) ([]domain.Plan, error) {
 	return s.repo.ListActive(ctx)
 }
 
+// ListActivePage pagina os planos ativos por data de criacao para a API.
+func (s *PlanService) ListActivePage(ctx context.Context, page ports.PageRequest) ([]domain.Plan, error) {
+	return s.repo.ListActivePage(ctx, page)
+}
+
 func (s *PlanService) endSubscriptionsForPlan(ctx context.Context, planID string) error {
 	if s.subscriptions == nil {
 		return errors.New("assinaturas indisponiveis")
@@ -111,3 +143,19 @@ func (s *PlanService) endSubscriptionsForPlan(ctx context.Context, planID string
 	}
 	return endSubscriptions(ctx, s.subscriptions, subscriptions, s.now())
 }
+
+// inTx executa fn com uma copia do servico ligada a transacao. Sem txRunner, fn
+// recebe o proprio servico.
+func (s *PlanService) inTx(ctx context.Context, fn func(context.Context, *PlanService) error) error {
+	if s.txRunner == nil {
+		return fn(ctx, s)
+	}
+	return s.txRunner.RunInTx(ctx, ports.TxOptions{Isolation: ports.TxReadCommitted}, func(ctx context.Context, deps ports.TxDependencies) error {
+		return fn(ctx, &PlanService{
+			repo:          deps.Plans,
+			subscriptions: deps.Subscriptions,
+			audit:         deps.Audit,
+			now:           s.now,
+		})
+	})
+}
@@ -2,10 +2,13 @@ package service
 
 import (
 	"context"
+	"errors"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/PabloPavan/jaiu/internal/domain"
+	"github.com/PabloPavan/jaiu/internal/ports"
 )
 
 // Testa Create setando timestamps e persistindo no repositorio.
@@ -75,3 +78,32 @@ func TestPlanServiceDeactivate(t *testing.T) {
 		t.Fatalf("expected subscription ended, got %q", subRepo.subscriptions["sub-1"].Status)
 	}
 }
+
+// Testa que Deactivate desfeito pela transacao registra apenas a falha.
+func TestPlanServiceDeactivateRollbackKeepsFailureOnly(t *testing.T) {
+	repo := &planRepoFake{
+		plans: map[string]domain.Plan{
+			"plan-1": {ID: "plan-1", Active: true},
+		},
+	}
+	subRepo := &subscriptionRepoFake{
+		subscriptions: map[string]domain.Subscription{
+			"sub-1": {ID: "sub-1", PlanID: "plan-1", Status: domain.SubscriptionActive},
+		},
+		updateErr: errors.New("update failed"),
+	}
+	audit := &auditRepoFake{}
+	runner := &txRunnerFake{deps: ports.TxDependencies{Plans: repo, Subscriptions: subRepo}, audit: audit}
+	service := NewPlanService(repo, subRepo, audit)
+	service.SetTxRunner(runner)
+
+	if _, err := service.Deactivate(context.Background(), "plan-1"); err == nil {
+		t.Fatal("expected error")
+	}
+	if runner.opts.Isolation != ports.TxReadCommitted {
+		t.Fatalf("unexpected isolation: %q", runner.opts.Isolation)
+	}
+	if got := auditActions(audit); strings.Join(got, ",") != "plan.deactivate.attempt,plan.deactivate.failure" {
+		t.Fatalf("unexpected audit events: %v", got)
+	}
+}
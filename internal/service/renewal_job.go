@@ -46,10 +46,27 @@ func (j *RenewalJob) Run(ctx context.Context) error {
 		return err
 	}
 
+	processed := make(map[string]bool, len(subscriptions))
 	for _, subscription := range subscriptions {
 		if err := j.runSubscription(ctx, subscription, today); err != nil {
 			return err
 		}
+		processed[subscription.ID] = true
+	}
+
+	// Assinaturas sem renovacao automatica tambem acumulam multa e juros
+	// enquanto houver competencias em aberto.
+	overdue, err := j.subscriptions.ListWithOpenPeriods(ctx)
+	if err != nil {
+		return err
+	}
+	for _, subscription := range overdue {
+		if processed[subscription.ID] {
+			continue
+		}
+		if err := j.runLateFees(ctx, subscription, today); err != nil {
+			return err
+		}
 	}
 
 	return nil
@@ -65,6 +82,16 @@ func (j *RenewalJob) runSubscription(ctx context.Context, subscription domain.Su
 	return j.processSubscription(ctx, subscription, today, j.plans, j.periods, j.balances)
 }
 
+func (j *RenewalJob) runLateFees(ctx context.Context, subscription domain.Subscription, today time.Time) error {
+	if j.txRunner != nil {
+		return j.txRunner.RunSerializable(ctx, func(ctx context.Context, deps ports.PaymentDependencies) error {
+			return j.processLateFees(ctx, subscription, today, deps.Plans, deps.BillingPeriods, deps.Balances)
+		})
+	}
+
+	return j.processLateFees(ctx, subscription, today, j.plans, j.periods, j.balances)
+}
+
 func (j *RenewalJob) processSubscription(
 	ctx context.Context,
 	subscription domain.Subscription,
@@ -80,8 +107,32 @@ func (j *RenewalJob) processSubscription(
 	if _, err := ensureBillingPeriods(ctx, periods, subscription, plan, today); err != nil {
 		return err
 	}
+	if _, err := applyLateFees(ctx, periods, subscription, plan, today); err != nil {
+		return err
+	}
 	if err := applySubscriptionBalance(ctx, balances, periods, subscription, today); err != nil {
 		return err
 	}
 	return nil
 }
+
+func (j *RenewalJob) processLateFees(
+	ctx context.Context,
+	subscription domain.Subscription,
+	today time.Time,
+	plans ports.PlanRepository,
+	periods ports.BillingPeriodRepository,
+	balances ports.SubscriptionBalanceRepository,
+) error {
+	plan, err := plans.FindByID(ctx, subscription.PlanID)
+	if err != nil {
+		return err
+	}
+	if !plan.ChargesLateFees() {
+		return nil
+	}
+	if _, err := applyLateFees(ctx, periods, subscription, plan, today); err != nil {
+		return err
+	}
+	return applySubscriptionBalance(ctx, balances, periods, subscription, today)
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"time"
 
+	"github.com/PabloPavan/jaiu/internal/domain"
 	"github.com/PabloPavan/jaiu/internal/ports"
 )
 
@@ -30,3 +31,56 @@ func (s *ReportService) DelinquentSubscriptions(ctx context.Context, now time.Ti
 func (s *ReportService) UpcomingDue(ctx context.Context, start, end time.Time) ([]ports.DueSubscription, error) {
 	return s.repo.UpcomingDue(ctx, start, end)
 }
+
+func (s *ReportService) RevenueSeries(ctx context.Context, period domain.ReportPeriod, now time.Time, buckets int) ([]ports.RevenueSummary, error) {
+	if !period.IsValid() {
+		return nil, validationError("periodo de relatorio invalido")
+	}
+	if buckets <= 0 {
+		buckets = 1
+	}
+
+	start := reportBucketStart(period, now)
+	for i := 1; i < buckets; i++ {
+		start = shiftReportBucket(period, start, -1)
+	}
+
+	series := make([]ports.RevenueSummary, 0, buckets)
+	for i := 0; i < buckets; i++ {
+		end := shiftReportBucket(period, start, 1)
+		summary, err := s.repo.RevenueByPeriod(ctx, start, end)
+		if err != nil {
+			return nil, err
+		}
+		summary.Start = start
+		summary.End = end
+		series = append(series, summary)
+		start = end
+	}
+
+	return series, nil
+}
+
+func reportBucketStart(period domain.ReportPeriod, now time.Time) time.Time {
+	today := dateOnly(now)
+	switch period {
+	case domain.ReportWeekly:
+		offset := (int(today.Weekday()) + 6) % 7
+		return today.AddDate(0, 0, -offset)
+	case domain.ReportMonthly:
+		return time.Date(today.Year(), today.Month(), 1, 0, 0, 0, 0, today.Location())
+	default:
+		return today
+	}
+}
+
+func shiftReportBucket(period domain.ReportPeriod, start time.Time, steps int) time.Time {
+	switch period {
+	case domain.ReportWeekly:
+		return start.AddDate(0, 0, 7*steps)
+	case domain.ReportMonthly:
+		return start.AddDate(0, steps, 0)
+	default:
+		return start.AddDate(0, 0, steps)
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/PabloPavan/jaiu/internal/domain"
 	"github.com/PabloPavan/jaiu/internal/ports"
 )
 
@@ -33,3 +34,44 @@ func TestReportServiceDelegates(t *testing.T) {
 		t.Fatalf("unexpected upcoming response: %#v err=%v", got, err)
 	}
 }
+
+// Testa a serie de receita por granularidade (diaria, semanal, mensal).
+func TestReportServiceRevenueSeries(t *testing.T) {
+	now := time.Date(2024, 3, 14, 15, 0, 0, 0, time.UTC) // quinta-feira
+	tests := []struct {
+		name      string
+		period    domain.ReportPeriod
+		wantFirst time.Time
+		wantLast  time.Time
+	}{
+		{"daily", domain.ReportDaily, time.Date(2024, 3, 12, 0, 0, 0, 0, time.UTC), time.Date(2024, 3, 14, 0, 0, 0, 0, time.UTC)},
+		{"weekly", domain.ReportWeekly, time.Date(2024, 2, 26, 0, 0, 0, 0, time.UTC), time.Date(2024, 3, 11, 0, 0, 0, 0, time.UTC)},
+		{"monthly", domain.ReportMonthly, time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)},
+	}
+
+	for _, tt := range tests {
+		repo := &reportRepoFake{revenue: ports.RevenueSummary{TotalCents: 500}}
+		service := NewReportService(repo)
+
+		series, err := service.RevenueSeries(context.Background(), tt.period, now, 3)
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", tt.name, err)
+		}
+		if len(series) != 3 {
+			t.Fatalf("%s: expected 3 buckets, got %d", tt.name, len(series))
+		}
+		if !series[0].Start.Equal(tt.wantFirst) {
+			t.Fatalf("%s: expected first bucket at %v, got %v", tt.name, tt.wantFirst, series[0].Start)
+		}
+		if !series[2].Start.Equal(tt.wantLast) {
+			t.Fatalf("%s: expected last bucket at %v, got %v", tt.name, tt.wantLast, series[2].Start)
+		}
+		if !series[0].End.Equal(series[1].Start) || series[2].TotalCents != 500 {
+			t.Fatalf("%s: expected contiguous buckets with totals, got %#v", tt.name, series)
+		}
+	}
+
+	if _, err := NewReportService(&reportRepoFake{}).RevenueSeries(context.Background(), domain.ReportPeriod("yearly"), now, 1); err == nil {
+		t.Fatal("expected error for invalid period")
+	}
+}
@@ -6,6 +6,7 @@ type Services struct {
 	Students      *StudentService
 	Plans         *PlanService
 	Subscriptions *SubscriptionService
+	Coupons       *CouponService
 	Payments      *PaymentService
 	Reports       *ReportService
 	Auth          *AuthService
@@ -15,10 +16,13 @@ type Dependencies struct {
 	Students       ports.StudentRepository
 	Plans          ports.PlanRepository
 	Subscriptions  ports.SubscriptionRepository
+	Coupons        ports.CouponRepository
 	Payments       ports.PaymentRepository
 	BillingPeriods ports.BillingPeriodRepository
 	Balances       ports.SubscriptionBalanceRepository
+	Freezes        ports.SubscriptionFreezeRepository
 	Allocations    ports.PaymentAllocationRepository
+	Transfers      ports.PaymentCreditTransferRepository
 	PaymentTx      ports.PaymentTxRunner
 	Reports        ports.ReportRepository
 	Users          ports.UserRepository
@@ -26,12 +30,26 @@ type Dependencies struct {
 }
 
 func New(deps Dependencies) *Services {
+	subscriptions := NewSubscriptionService(deps.Subscriptions, deps.Plans, deps.Students, deps.Audit)
+	subscriptions.SetCoupons(deps.Coupons)
+	subscriptions.SetBilling(deps.BillingPeriods, deps.Balances)
+	subscriptions.SetPaymentLedger(deps.Allocations, deps.Transfers)
+	subscriptions.SetFreezes(deps.Freezes)
+	payments := NewPaymentService(deps.Payments, deps.Subscriptions, deps.Plans, deps.BillingPeriods, deps.Balances, deps.Allocations, deps.Audit, deps.PaymentTx)
+	payments.SetCreditTransfers(deps.Transfers)
 	return &Services{
 		Students:      NewStudentService(deps.Students, deps.Subscriptions, deps.Audit),
 		Plans:         NewPlanService(deps.Plans, deps.Subscriptions, deps.Audit),
-		Subscriptions: NewSubscriptionService(deps.Subscriptions, deps.Plans, deps.Students, deps.Audit),
-		Payments:      NewPaymentService(deps.Payments, deps.Subscriptions, deps.Plans, deps.BillingPeriods, deps.Balances, deps.Allocations, deps.Audit, deps.PaymentTx),
+		Subscriptions: subscriptions,
+		Coupons:       NewCouponService(deps.Coupons, deps.Audit),
+		Payments:      payments,
 		Reports:       NewReportService(deps.Reports),
 		Auth:          NewAuthService(deps.Users, deps.Audit),
 	}
 }
+
+// validationError recusa dados de entrada com uma mensagem que pode ser
+// mostrada ao usuario.
+func validationError(message string) error {
+	return &ports.ValidationError{Message: message}
+}
@@ -0,0 +1,61 @@
+package service
+
+import (
+	"context"
+	"errors"
+
+	"github.com/PabloPavan/jaiu/internal/ports"
+)
+
+type SessionService struct {
+	sessions ports.SessionStore
+	audit    ports.AuditRepository
+}
+
+func NewSessionService(sessions ports.SessionStore, audit ports.AuditRepository) *SessionService {
+	return &SessionService{sessions: sessions, audit: audit}
+}
+
+func (s *SessionService) ListByUser(ctx context.Context, userID string) ([]ports.Session, error) {
+	if s.sessions == nil {
+		return nil, errors.New("sessoes indisponiveis")
+	}
+	return s.sessions.ListByUser(ctx, userID)
+}
+
+func (s *SessionService) Revoke(ctx context.Context, userID, sessionID string) error {
+	metadata := map[string]any{
+		"session_id": sessionID,
+	}
+	recordAuditAttempt(ctx, s.audit, "user.session.revoke", "user", userID, metadata)
+
+	if s.sessions == nil {
+		err := errors.New("sessoes indisponiveis")
+		recordAuditFailure(ctx, s.audit, "user.session.revoke", "user", userID, metadata, err)
+		return err
+	}
+	if err := s.sessions.DeleteByID(ctx, userID, sessionID); err != nil {
+		recordAuditFailure(ctx, s.audit, "user.session.revoke", "user", userID, metadata, err)
+		return err
+	}
+
+	recordAuditSuccess(ctx, s.audit, "user.session.revoke", "user", userID, metadata)
+	return nil
+}
+
+func (s *SessionService) RevokeAll(ctx context.Context, userID string) error {
+	recordAuditAttempt(ctx, s.audit, "user.session.revoke_all", "user", userID, nil)
+
+	if s.sessions == nil {
+		err := errors.New("sessoes indisponiveis")
+		recordAuditFailure(ctx, s.audit, "user.session.revoke_all", "user", userID, nil, err)
+		return err
+	}
+	if err := s.sessions.DeleteByUser(ctx, userID); err != nil {
+		recordAuditFailure(ctx, s.audit, "user.session.revoke_all", "user", userID, nil, err)
+		return err
+	}
+
+	recordAuditSuccess(ctx, s.audit, "user.session.revoke_all", "user", userID, nil)
+	return nil
+}
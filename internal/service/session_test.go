@@ -0,0 +1,46 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/PabloPavan/jaiu/internal/ports"
+)
+
+// Testa a revogacao de uma sessao especifica com auditoria.
+func TestSessionServiceRevoke(t *testing.T) {
+	store := &sessionStoreFake{sessions: []ports.Session{
+		{ID: "sess-1", UserID: "user-1"},
+		{ID: "sess-2", UserID: "user-1"},
+	}}
+	audit := &auditRepoFake{}
+	service := NewSessionService(store, audit)
+
+	if err := service.Revoke(context.Background(), "user-1", "sess-2"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(store.deletedIDs) != 1 || store.deletedIDs[0] != "sess-2" {
+		t.Fatalf("expected sess-2 revoked, got %v", store.deletedIDs)
+	}
+	if len(audit.events) != 2 || audit.events[1].Action != "user.session.revoke.success" {
+		t.Fatalf("unexpected audit events: %#v", audit.events)
+	}
+
+	if err := service.Revoke(context.Background(), "user-2", "sess-1"); !errors.Is(err, ports.ErrNotFound) {
+		t.Fatalf("expected not found for other user session, got %v", err)
+	}
+}
+
+// Testa o encerramento de todas as sessoes do usuario.
+func TestSessionServiceRevokeAll(t *testing.T) {
+	store := &sessionStoreFake{}
+	service := NewSessionService(store, nil)
+
+	if err := service.RevokeAll(context.Background(), "user-1"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(store.deletedUsers) != 1 || store.deletedUsers[0] != "user-1" {
+		t.Fatalf("expected sessions revoked for user-1, got %v", store.deletedUsers)
+	}
+}
@@ -6,31 +6,54 @@ import (
 	"strings"
 	"time"
 
+	"github.com/PabloPavan/jaiu/internal/auditctx"
 	"github.com/PabloPavan/jaiu/internal/domain"
 	"github.com/PabloPavan/jaiu/internal/ports"
 )
 
+// defaultStudentViewWindow e o intervalo em que acessos repetidos do mesmo
+// usuario ao mesmo aluno geram um unico evento student.view.
+const defaultStudentViewWindow = 30 * time.Minute
+
 type StudentService struct {
 	repo          ports.StudentRepository
 	subscriptions ports.SubscriptionRepository
 	audit         ports.AuditRepository
+	views         ports.AccessDedupStore
+	txRunner      ports.TxRunner
+	viewWindow    time.Duration
 	now           func() time.Time
 }
 
 func NewStudentService(repo ports.StudentRepository, subscriptions ports.SubscriptionRepository, audit ports.AuditRepository) *StudentService {
-	return &StudentService{repo: repo, subscriptions: subscriptions, audit: audit, now: time.Now}
+	return &StudentService{repo: repo, subscriptions: subscriptions, audit: audit, viewWindow: defaultStudentViewWindow, now: time.Now}
+}
+
+// SetViewDedup ativa a deduplicacao dos eventos student.view dentro da janela
+// informada.
+func (s *StudentService) SetViewDedup(store ports.AccessDedupStore, window time.Duration) {
+	s.views = store
+	if window > 0 {
+		s.viewWindow = window
+	}
+}
+
+// SetTxRunner faz as alteracoes de status, o encerramento das assinaturas e o
+// evento de sucesso serem gravados em uma unica transacao.
+func (s *StudentService) SetTxRunner(runner ports.TxRunner) {
+	s.txRunner = runner
 }
 
 func (s *StudentService) Register(ctx context.Context, student domain.Student) (domain.Student, error) {
 	student.FullName = strings.TrimSpace(student.FullName)
 	if student.FullName == "" {
-		return domain.Student{}, errors.New("nome completo e obrigatorio")
+		return domain.Student{}, validationError("nome completo e obrigatorio")
 	}
 	if student.Status == "" {
 		student.Status = domain.StudentActive
 	}
 	if !student.Status.IsValid() {
-		return domain.Student{}, errors.New("status do aluno invalido")
+		return domain.Student{}, validationError("status do aluno invalido")
 	}
 
 	now := s.now()
@@ -53,17 +76,17 @@ func (s *StudentService) Register(ctx context.Context, student domain.Student) (
 
 func (s *StudentService) Update(ctx context.Context, student domain.Student) (domain.Student, error) {
 	if student.ID == "" {
-		return domain.Student{}, errors.New("aluno invalido")
+		return domain.Student{}, validationError("aluno invalido")
 	}
 	student.FullName = strings.TrimSpace(student.FullName)
 	if student.FullName == "" {
-		return domain.Student{}, errors.New("nome completo e obrigatorio")
+		return domain.Student{}, validationError("nome completo e obrigatorio")
 	}
 	if student.Status == "" {
 		student.Status = domain.StudentActive
 	}
 	if !student.Status.IsValid() {
-		return domain.Student{}, errors.New("status do aluno invalido")
+		return domain.Student{}, validationError("status do aluno invalido")
 	}
 	student.UpdatedAt = s.now()
 	metadata := map[string]any{
@@ -71,18 +94,29 @@ func (s *StudentService) Update(ctx context.Context, student domain.Student) (do
 	}
 	recordAuditAttempt(ctx, s.audit, "student.update", "student", student.ID, metadata)
 
-	updated, err := s.repo.Update(ctx, student)
+	var updated domain.Student
+	err := s.inTx(ctx, func(ctx context.Context, tx *StudentService) error {
+		current, err := tx.repo.FindByID(ctx, student.ID)
+		if err != nil {
+			return err
+		}
+
+		updated, err = tx.repo.Update(ctx, student)
+		if err != nil {
+			return err
+		}
+		if updated.Status == domain.StudentInactive || updated.Status == domain.StudentSuspended {
+			if err := tx.endSubscriptionsForStudent(ctx, updated.ID); err != nil {
+				return err
+			}
+		}
+		recordAuditSuccess(ctx, tx.audit, "student.update", "student", updated.ID, withAuditChanges(metadata, studentAuditFields(current), studentAuditFields(updated)))
+		return nil
+	})
 	if err != nil {
 		recordAuditFailure(ctx, s.audit, "student.update", "student", student.ID, metadata, err)
 		return domain.Student{}, err
 	}
-	if updated.Status == domain.StudentInactive || updated.Status == domain.StudentSuspended {
-		if err := s.endSubscriptionsForStudent(ctx, updated.ID); err != nil {
-			recordAuditFailure(ctx, s.audit, "student.update", "student", updated.ID, metadata, err)
-			return updated, err
-		}
-	}
-	recordAuditSuccess(ctx, s.audit, "student.update", "student", updated.ID, metadata)
 	return updated, nil
 }
 
@@ -97,19 +131,87 @@ func (s *StudentService) SetStatus(ctx context.Context, studentID string, status
 }
 
 func (s *StudentService) FindByID(ctx context.Context, studentID string) (domain.Student, error) {
-	return s.repo.FindByID(ctx, studentID)
+	student, err := s.repo.FindByID(ctx, studentID)
+	if err != nil {
+		return domain.Student{}, err
+	}
+	s.recordView(ctx, student.ID, "detail")
+	return student, nil
+}
+
+// FindName busca apenas o nome do aluno para rotulos de outras telas, como a
+// lista de assinaturas, e por isso nao registra student.view.
+func (s *StudentService) FindName(ctx context.Context, studentID string) (string, error) {
+	student, err := s.repo.FindByID(ctx, studentID)
+	if err != nil {
+		return "", err
+	}
+	return student.FullName, nil
+}
+
+// RecordViews registra que os dados dos alunos foram exibidos, por exemplo na
+// busca rapida.
+func (s *StudentService) RecordViews(ctx context.Context, source string, studentIDs ...string) {
+	for _, studentID := range studentIDs {
+		s.recordView(ctx, studentID, source)
+	}
+}
+
+// RecordPhotoView registra o acesso a foto identificada pela chave do objeto.
+// Chaves que nao pertencem a nenhum aluno sao ignoradas.
+func (s *StudentService) RecordPhotoView(ctx context.Context, objectKey string) error {
+	objectKey = strings.TrimSpace(objectKey)
+	if objectKey == "" {
+		return nil
+	}
+	studentID, err := s.repo.FindIDByPhotoObjectKey(ctx, objectKey)
+	if err != nil {
+		if errors.Is(err, ports.ErrNotFound) {
+			return nil
+		}
+		return err
+	}
+	s.recordView(ctx, studentID, "photo")
+	return nil
+}
+
+// recordView grava student.view com o ator do contexto. Com a deduplicacao
+// ativa, apenas o primeiro acesso do usuario ao aluno dentro da janela e
+// registrado; se o store falhar o evento e gravado mesmo assim.
+func (s *StudentService) recordView(ctx context.Context, studentID, source string) {
+	if s.audit == nil || studentID == "" {
+		return
+	}
+	if s.views != nil {
+		actor := auditctx.FromContext(ctx).Actor
+		claimed, err := s.views.Claim(ctx, "student.view:"+actor.ID+":"+studentID, s.viewWindow)
+		if err == nil && !claimed {
+			return
+		}
+	}
+	recordAudit(ctx, s.audit, "student.view", "student", studentID, map[string]any{
+		"source": source,
+	})
 }
 
 func (s *StudentService) Deactivate(ctx context.Context, studentID string) (domain.Student, error) {
 	recordAuditAttempt(ctx, s.audit, "student.deactivate", "student", studentID, nil)
-	updated, err := s.SetStatus(ctx, studentID, domain.StudentInactive)
+	var updated domain.Student
+	err := s.inTx(ctx, func(ctx context.Context, tx *StudentService) error {
+		var err error
+		updated, err = tx.SetStatus(ctx, studentID, domain.StudentInactive)
+		if err != nil {
+			return err
+		}
+		recordAuditSuccess(ctx, tx.audit, "student.deactivate", "student", updated.ID, map[string]any{
+			"status": string(updated.Status),
+		})
+		return nil
+	})
 	if err != nil {
 		recordAuditFailure(ctx, s.audit, "student.deactivate", "student", studentID, nil, err)
 		return domain.Student{}, err
 	}
-	recordAuditSuccess(ctx, s.audit, "student.deactivate", "student", updated.ID, map[string]any{
-		"status": string(updated.Status),
-	})
 	return updated, nil
 }
 
@@ -117,6 +219,10 @@ func (s *StudentService) Search(ctx context.Context, filter ports.StudentFilter)
 	return s.repo.Search(ctx, filter)
 }
 
+func (s *StudentService) SearchPage(ctx context.Context, filter ports.StudentFilter, page ports.PageRequest) ([]domain.Student, error) {
+	return s.repo.SearchPage(ctx, filter, page)
+}
+
 func (s *StudentService) Count(ctx context.Context, filter ports.StudentFilter) (int, error) {
 	return s.repo.Count(ctx, filter)
 }
@@ -131,3 +237,21 @@ func (s *StudentService) endSubscriptionsForStudent(ctx context.Context, student
 	}
 	return endSubscriptions(ctx, s.subscriptions, subscriptions, s.now())
 }
+
+// inTx executa fn com uma copia do servico ligada a transacao. Sem txRunner, fn
+// recebe o proprio servico. Tentativas e falhas continuam sendo auditadas fora
+// da transacao para sobreviverem ao rollback.
+func (s *StudentService) inTx(ctx context.Context, fn func(context.Context, *StudentService) error) error {
+	if s.txRunner == nil {
+		return fn(ctx, s)
+	}
+	return s.txRunner.RunInTx(ctx, ports.TxOptions{Isolation: ports.TxReadCommitted}, func(ctx context.Context, deps ports.TxDependencies) error {
+		return fn(ctx, &StudentService{
+			repo:          deps.Students,
+			subscriptions: deps.Subscriptions,
+			audit:         deps.Audit,
+			viewWindow:    s.viewWindow,
+			now:           s.now,
+		})
+	})
+}
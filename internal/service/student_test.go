@@ -2,21 +2,25 @@ package service
 
 import (
 	"context"
+	"errors"
+	"strings"
 	"testing"
 	"time"
 
+	"github.com/PabloPavan/jaiu/internal/auditctx"
 	"github.com/PabloPavan/jaiu/internal/domain"
+	"github.com/PabloPavan/jaiu/internal/ports"
 )
 
 // Testa Register validando nome e status.
 func TestStudentServiceRegisterValidation(t *testing.T) {
 	service := NewStudentService(&studentRepoFake{}, nil, nil)
 
-	if _, err := service.Register(context.Background(), domain.Student{}); err == nil {
-		t.Fatal("expected error for empty name")
+	if _, err := service.Register(context.Background(), domain.Student{}); !errors.Is(err, ports.ErrValidation) {
+		t.Fatalf("expected validation error for empty name, got %v", err)
 	}
-	if _, err := service.Register(context.Background(), domain.Student{FullName: "Name", Status: domain.StudentStatus("x")}); err == nil {
-		t.Fatal("expected error for invalid status")
+	if _, err := service.Register(context.Background(), domain.Student{FullName: "Name", Status: domain.StudentStatus("x")}); !errors.Is(err, ports.ErrValidation) {
+		t.Fatalf("expected validation error for invalid status, got %v", err)
 	}
 }
 
@@ -41,7 +45,11 @@ func TestStudentServiceRegisterDefaults(t *testing.T) {
 
 // Testa Update encerrando assinaturas quando aluno fica inativo.
 func TestStudentServiceUpdateEndsSubscriptions(t *testing.T) {
-	studentRepo := &studentRepoFake{}
+	studentRepo := &studentRepoFake{
+		students: map[string]domain.Student{
+			"student-1": {ID: "student-1", FullName: "Name", Status: domain.StudentActive},
+		},
+	}
 	subRepo := &subscriptionRepoFake{
 		subscriptions: map[string]domain.Subscription{
 			"sub-1": {ID: "sub-1", StudentID: "student-1", Status: domain.SubscriptionActive},
@@ -77,3 +85,167 @@ func TestStudentServiceDeactivate(t *testing.T) {
 		t.Fatalf("expected inactive, got %q", student.Status)
 	}
 }
+
+// Testa que Update registra o diff dos campos alterados com o CPF mascarado.
+func TestStudentServiceUpdateRecordsChanges(t *testing.T) {
+	studentRepo := &studentRepoFake{
+		students: map[string]domain.Student{
+			"student-1": {ID: "student-1", FullName: "Ana", Phone: "1111", CPF: "11122233344", Status: domain.StudentActive},
+		},
+	}
+	audit := &auditRepoFake{}
+	service := NewStudentService(studentRepo, &subscriptionRepoFake{}, audit)
+
+	_, err := service.Update(context.Background(), domain.Student{ID: "student-1", FullName: "Ana Souza", Phone: "1111", CPF: "55566677788", Status: domain.StudentActive})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	event := audit.events[len(audit.events)-1]
+	if event.Action != "student.update.success" {
+		t.Fatalf("expected success event, got %q", event.Action)
+	}
+	changes, ok := event.Metadata["changes"].(map[string]any)
+	if !ok {
+		t.Fatalf("expected changes metadata, got %#v", event.Metadata)
+	}
+	if len(changes) != 2 {
+		t.Fatalf("expected 2 changed fields, got %#v", changes)
+	}
+	name := changes["full_name"].(map[string]any)
+	if name["from"] != "Ana" || name["to"] != "Ana Souza" {
+		t.Fatalf("unexpected full_name change: %#v", name)
+	}
+	cpf := changes["cpf"].(map[string]any)
+	if cpf["from"] != auditMaskedValue || cpf["to"] != auditMaskedValue {
+		t.Fatalf("expected masked cpf, got %#v", cpf)
+	}
+}
+
+// Testa que FindByID registra student.view uma unica vez por usuario na janela.
+func TestStudentServiceFindByIDRecordsDedupedView(t *testing.T) {
+	repo := &studentRepoFake{
+		students: map[string]domain.Student{
+			"student-1": {ID: "student-1", FullName: "Name", PhotoObjectKey: "photo-1"},
+		},
+	}
+	audit := &auditRepoFake{}
+	service := NewStudentService(repo, nil, audit)
+	service.SetViewDedup(&accessDedupFake{}, time.Hour)
+
+	admin := auditctx.WithActor(context.Background(), auditctx.Actor{ID: "user-1", Role: "admin"})
+	operator := auditctx.WithActor(context.Background(), auditctx.Actor{ID: "user-2", Role: "operator"})
+
+	for i := 0; i < 3; i++ {
+		if _, err := service.FindByID(admin, "student-1"); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	if err := service.RecordPhotoView(admin, "photo-1"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	service.RecordViews(operator, "preview", "student-1")
+
+	if len(audit.events) != 2 {
+		t.Fatalf("expected 2 view events, got %#v", audit.events)
+	}
+	first := audit.events[0]
+	if first.Action != "student.view" || first.EntityID != "student-1" || first.ActorID != "user-1" || first.Metadata["source"] != "detail" {
+		t.Fatalf("unexpected view event: %#v", first)
+	}
+	if audit.events[1].ActorID != "user-2" || audit.events[1].Metadata["source"] != "preview" {
+		t.Fatalf("unexpected preview event: %#v", audit.events[1])
+	}
+}
+
+// Testa que FindName devolve o nome sem registrar student.view.
+func TestStudentServiceFindNameDoesNotRecordView(t *testing.T) {
+	repo := &studentRepoFake{
+		students: map[string]domain.Student{
+			"student-1": {ID: "student-1", FullName: "Name"},
+		},
+	}
+	audit := &auditRepoFake{}
+	service := NewStudentService(repo, nil, audit)
+
+	ctx := auditctx.WithActor(context.Background(), auditctx.Actor{ID: "user-1", Role: "admin"})
+	name, err := service.FindName(ctx, "student-1")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if name != "Name" {
+		t.Fatalf("unexpected name: %q", name)
+	}
+	if len(audit.events) != 0 {
+		t.Fatalf("expected no view events, got %#v", audit.events)
+	}
+}
+
+// Testa que fotos sem aluno associado nao geram eventos.
+func TestStudentServiceRecordPhotoViewIgnoresUnknownKey(t *testing.T) {
+	audit := &auditRepoFake{}
+	service := NewStudentService(&studentRepoFake{}, nil, audit)
+
+	if err := service.RecordPhotoView(context.Background(), "unknown"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(audit.events) != 0 {
+		t.Fatalf("expected no events, got %#v", audit.events)
+	}
+}
+
+// Testa que Update via txRunner grava o sucesso dentro da transacao.
+func TestStudentServiceUpdateUsesTxRunner(t *testing.T) {
+	studentRepo := &studentRepoFake{
+		students: map[string]domain.Student{
+			"student-1": {ID: "student-1", FullName: "Name", Status: domain.StudentActive},
+		},
+	}
+	subRepo := &subscriptionRepoFake{
+		subscriptions: map[string]domain.Subscription{
+			"sub-1": {ID: "sub-1", StudentID: "student-1", Status: domain.SubscriptionActive},
+		},
+	}
+	audit := &auditRepoFake{}
+	runner := &txRunnerFake{deps: ports.TxDependencies{Students: studentRepo, Subscriptions: subRepo}, audit: audit}
+	service := NewStudentService(&studentRepoFake{}, nil, audit)
+	service.SetTxRunner(runner)
+
+	if _, err := service.Update(context.Background(), domain.Student{ID: "student-1", FullName: "Name", Status: domain.StudentInactive}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if runner.calls != 1 {
+		t.Fatalf("expected one transaction, got %d", runner.calls)
+	}
+	if subRepo.subscriptions["sub-1"].Status != domain.SubscriptionEnded {
+		t.Fatalf("expected subscription to be ended, got %q", subRepo.subscriptions["sub-1"].Status)
+	}
+	if got := auditActions(audit); strings.Join(got, ",") != "student.update.attempt,student.update.success" {
+		t.Fatalf("unexpected audit events: %v", got)
+	}
+}
+
+// Testa que uma falha ao encerrar assinaturas nao deixa evento de sucesso.
+func TestStudentServiceDeactivateRollbackKeepsFailureOnly(t *testing.T) {
+	studentRepo := &studentRepoFake{
+		students: map[string]domain.Student{
+			"student-1": {ID: "student-1", FullName: "Name", Status: domain.StudentActive},
+		},
+	}
+	subRepo := &subscriptionRepoFake{
+		subscriptions: map[string]domain.Subscription{
+			"sub-1": {ID: "sub-1", StudentID: "student-1", Status: domain.SubscriptionActive},
+		},
+		updateErr: errors.New("update failed"),
+	}
+	audit := &auditRepoFake{}
+	service := NewStudentService(studentRepo, subRepo, audit)
+	service.SetTxRunner(&txRunnerFake{deps: ports.TxDependencies{Students: studentRepo, Subscriptions: subRepo}, audit: audit})
+
+	if _, err := service.Deactivate(context.Background(), "student-1"); err == nil {
+		t.Fatal("expected error")
+	}
+	if got := auditActions(audit); strings.Join(got, ",") != "student.deactivate.attempt,student.deactivate.failure" {
+		t.Fatalf("unexpected audit events: %v", got)
+	}
+}
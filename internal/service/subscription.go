@@ -10,11 +10,18 @@ import (
 )
 
 type SubscriptionService struct {
-	repo     ports.SubscriptionRepository
-	plans    ports.PlanRepository
-	students ports.StudentRepository
-	audit    ports.AuditRepository
-	now      func() time.Time
+	repo        ports.SubscriptionRepository
+	plans       ports.PlanRepository
+	students    ports.StudentRepository
+	coupons     ports.CouponRepository
+	periods     ports.BillingPeriodRepository
+	balances    ports.SubscriptionBalanceRepository
+	allocations ports.PaymentAllocationRepository
+	transfers   ports.PaymentCreditTransferRepository
+	freezes     ports.SubscriptionFreezeRepository
+	audit       ports.AuditRepository
+	txRunner    ports.TxRunner
+	now         func() time.Time
 }
 
 func NewSubscriptionService(repo ports.SubscriptionRepository, plans ports.PlanRepository, students ports.StudentRepository, audit ports.AuditRepository) *SubscriptionService {
@@ -27,9 +34,19 @@ func NewSubscriptionService(repo ports.SubscriptionRepository, plans ports.PlanR
 	}
 }
 
+// SetCoupons habilita a aplicacao de cupons nas assinaturas. Sem o
+// repositorio, apenas assinaturas sem cupom podem ser gravadas.
+func (s *SubscriptionService) SetCoupons(repo ports.CouponRepository) {
+	s.coupons = repo
+}
+
+// Create grava a assinatura e gera as competencias iniciais na mesma
+// transacao, para que uma falha na cobranca nao deixe a assinatura sem
+// competencias.
 func (s *SubscriptionService) Create(ctx context.Context, subscription domain.Subscription) (domain.Subscription, error) {
 	metadata := map[string]any{
 		"auto_renew":  subscription.AutoRenew,
+		"coupon_id":   subscription.CouponID,
 		"payment_day": subscription.PaymentDay,
 		"plan_id":     subscription.PlanID,
 		"price_cents": subscription.PriceCents,
@@ -38,20 +55,33 @@ func (s *SubscriptionService) Create(ctx context.Context, subscription domain.Su
 	}
 	recordAuditAttempt(ctx, s.audit, "subscription.create", "subscription", subscription.ID, metadata)
 
-	if subscription.StudentID == "" {
-		err := errors.New("aluno e obrigatorio")
+	var created domain.Subscription
+	err := s.inTx(ctx, func(ctx context.Context, tx *SubscriptionService) error {
+		var err error
+		created, err = tx.create(ctx, subscription)
+		if err != nil {
+			return err
+		}
+		recordAuditSuccess(ctx, tx.audit, "subscription.create", "subscription", created.ID, metadata)
+		return nil
+	})
+	if err != nil {
 		recordAuditFailure(ctx, s.audit, "subscription.create", "subscription", subscription.ID, metadata, err)
 		return domain.Subscription{}, err
 	}
+	return created, nil
+}
+
+func (s *SubscriptionService) create(ctx context.Context, subscription domain.Subscription) (domain.Subscription, error) {
+	if subscription.StudentID == "" {
+		return domain.Subscription{}, validationError("aluno e obrigatorio")
+	}
 	if subscription.PlanID == "" {
-		err := errors.New("plano e obrigatorio")
-		recordAuditFailure(ctx, s.audit, "subscription.create", "subscription", subscription.ID, metadata, err)
-		return domain.Subscription{}, err
+		return domain.Subscription{}, validationError("plano e obrigatorio")
 	}
 
 	if s.students != nil {
 		if _, err := s.students.FindByID(ctx, subscription.StudentID); err != nil {
-			recordAuditFailure(ctx, s.audit, "subscription.create", "subscription", subscription.ID, metadata, err)
 			return domain.Subscription{}, err
 		}
 	}
@@ -60,7 +90,6 @@ func (s *SubscriptionService) Create(ctx context.Context, subscription domain.Su
 	if s.plans != nil {
 		loaded, err := s.plans.FindByID(ctx, subscription.PlanID)
 		if err != nil {
-			recordAuditFailure(ctx, s.audit, "subscription.create", "subscription", subscription.ID, metadata, err)
 			return domain.Subscription{}, err
 		}
 		plan = loaded
@@ -70,9 +99,7 @@ func (s *SubscriptionService) Create(ctx context.Context, subscription domain.Su
 		subscription.Status = domain.SubscriptionActive
 	}
 	if !subscription.Status.IsValid() {
-		err := errors.New("status invalido")
-		recordAuditFailure(ctx, s.audit, "subscription.create", "subscription", subscription.ID, metadata, err)
-		return domain.Subscription{}, err
+		return domain.Subscription{}, validationError("status invalido")
 	}
 
 	if subscription.StartDate.IsZero() {
@@ -83,9 +110,7 @@ func (s *SubscriptionService) Create(ctx context.Context, subscription domain.Su
 		subscription.PaymentDay = subscription.StartDate.Day()
 	}
 	if subscription.PaymentDay < 1 || subscription.PaymentDay > 31 {
-		err := errors.New("dia do pagamento invalido")
-		recordAuditFailure(ctx, s.audit, "subscription.create", "subscription", subscription.ID, metadata, err)
-		return domain.Subscription{}, err
+		return domain.Subscription{}, validationError("dia do pagamento invalido")
 	}
 
 	if subscription.EndDate.IsZero() && plan.DurationDays > 0 {
@@ -93,37 +118,43 @@ func (s *SubscriptionService) Create(ctx context.Context, subscription domain.Su
 	}
 
 	if subscription.EndDate.IsZero() {
-		err := errors.New("data de vencimento e obrigatoria")
-		recordAuditFailure(ctx, s.audit, "subscription.create", "subscription", subscription.ID, metadata, err)
-		return domain.Subscription{}, err
+		return domain.Subscription{}, validationError("data de vencimento e obrigatoria")
 	}
 
 	if subscription.EndDate.Before(subscription.StartDate) {
-		err := errors.New("data de vencimento deve ser depois da data de inicio")
-		recordAuditFailure(ctx, s.audit, "subscription.create", "subscription", subscription.ID, metadata, err)
-		return domain.Subscription{}, err
+		return domain.Subscription{}, validationError("data de vencimento deve ser depois da data de inicio")
 	}
 
 	if subscription.PriceCents <= 0 && plan.PriceCents > 0 {
 		subscription.PriceCents = plan.PriceCents
 	}
 
+	discount, err := s.resolveDiscount(ctx, subscription.CouponID, nil)
+	if err != nil {
+		return domain.Subscription{}, err
+	}
+	subscription.Discount = discount
+
 	now := s.now()
 	subscription.CreatedAt = now
 	subscription.UpdatedAt = now
 
 	created, err := s.repo.Create(ctx, subscription)
 	if err != nil {
-		recordAuditFailure(ctx, s.audit, "subscription.create", "subscription", subscription.ID, metadata, err)
 		return domain.Subscription{}, err
 	}
-	recordAuditSuccess(ctx, s.audit, "subscription.create", "subscription", created.ID, metadata)
+	if err := s.ensurePeriods(ctx, created, plan); err != nil {
+		return domain.Subscription{}, err
+	}
 	return created, nil
 }
 
+// Update grava a assinatura e gera as competencias que faltarem na mesma
+// transacao.
 func (s *SubscriptionService) Update(ctx context.Context, subscription domain.Subscription) (domain.Subscription, error) {
 	metadata := map[string]any{
 		"auto_renew":  subscription.AutoRenew,
+		"coupon_id":   subscription.CouponID,
 		"payment_day": subscription.PaymentDay,
 		"plan_id":     subscription.PlanID,
 		"price_cents": subscription.PriceCents,
@@ -132,37 +163,46 @@ func (s *SubscriptionService) Update(ctx context.Context, subscription domain.Su
 	}
 	recordAuditAttempt(ctx, s.audit, "subscription.update", "subscription", subscription.ID, metadata)
 
-	if subscription.StartDate.IsZero() {
-		err := errors.New("data de inicio e obrigatoria")
+	var updated domain.Subscription
+	err := s.inTx(ctx, func(ctx context.Context, tx *SubscriptionService) error {
+		current, saved, err := tx.update(ctx, subscription)
+		if err != nil {
+			return err
+		}
+		updated = saved
+		recordAuditSuccess(ctx, tx.audit, "subscription.update", "subscription", updated.ID, withAuditChanges(metadata, subscriptionAuditFields(current), subscriptionAuditFields(updated)))
+		return nil
+	})
+	if err != nil {
 		recordAuditFailure(ctx, s.audit, "subscription.update", "subscription", subscription.ID, metadata, err)
 		return domain.Subscription{}, err
 	}
+	return updated, nil
+}
+
+func (s *SubscriptionService) update(ctx context.Context, subscription domain.Subscription) (domain.Subscription, domain.Subscription, error) {
+	if subscription.StartDate.IsZero() {
+		return domain.Subscription{}, domain.Subscription{}, validationError("data de inicio e obrigatoria")
+	}
 	if subscription.Status == "" {
 		subscription.Status = domain.SubscriptionActive
 	}
 	if !subscription.Status.IsValid() {
-		err := errors.New("status invalido")
-		recordAuditFailure(ctx, s.audit, "subscription.update", "subscription", subscription.ID, metadata, err)
-		return domain.Subscription{}, err
+		return domain.Subscription{}, domain.Subscription{}, validationError("status invalido")
 	}
 
 	if subscription.PaymentDay < 1 || subscription.PaymentDay > 31 {
-		err := errors.New("dia do pagamento invalido")
-		recordAuditFailure(ctx, s.audit, "subscription.update", "subscription", subscription.ID, metadata, err)
-		return domain.Subscription{}, err
+		return domain.Subscription{}, domain.Subscription{}, validationError("dia do pagamento invalido")
 	}
 
 	var plan domain.Plan
-	if subscription.EndDate.IsZero() || subscription.PriceCents <= 0 {
+	if subscription.EndDate.IsZero() || subscription.PriceCents <= 0 || s.periods != nil {
 		if s.plans == nil {
-			err := errors.New("repositorio de planos nao configurado")
-			recordAuditFailure(ctx, s.audit, "subscription.update", "subscription", subscription.ID, metadata, err)
-			return domain.Subscription{}, err
+			return domain.Subscription{}, domain.Subscription{}, errors.New("repositorio de planos nao configurado")
 		}
 		loaded, err := s.plans.FindByID(ctx, subscription.PlanID)
 		if err != nil {
-			recordAuditFailure(ctx, s.audit, "subscription.update", "subscription", subscription.ID, metadata, err)
-			return domain.Subscription{}, err
+			return domain.Subscription{}, domain.Subscription{}, err
 		}
 		plan = loaded
 	}
@@ -172,29 +212,47 @@ func (s *SubscriptionService) Update(ctx context.Context, subscription domain.Su
 	}
 
 	if subscription.EndDate.IsZero() {
-		err := errors.New("data de vencimento e obrigatoria")
-		recordAuditFailure(ctx, s.audit, "subscription.update", "subscription", subscription.ID, metadata, err)
-		return domain.Subscription{}, err
+		return domain.Subscription{}, domain.Subscription{}, validationError("data de vencimento e obrigatoria")
 	}
 
 	if subscription.EndDate.Before(subscription.StartDate) {
-		err := errors.New("data de vencimento deve ser depois da data de inicio")
-		recordAuditFailure(ctx, s.audit, "subscription.update", "subscription", subscription.ID, metadata, err)
-		return domain.Subscription{}, err
+		return domain.Subscription{}, domain.Subscription{}, validationError("data de vencimento deve ser depois da data de inicio")
 	}
 
 	if subscription.PriceCents <= 0 && plan.PriceCents > 0 {
 		subscription.PriceCents = plan.PriceCents
 	}
 
+	current, err := s.repo.FindByID(ctx, subscription.ID)
+	if err != nil {
+		return domain.Subscription{}, domain.Subscription{}, err
+	}
+
+	discount, err := s.resolveDiscount(ctx, subscription.CouponID, &current)
+	if err != nil {
+		return domain.Subscription{}, domain.Subscription{}, err
+	}
+	subscription.Discount = discount
+
 	subscription.UpdatedAt = s.now()
 	updated, err := s.repo.Update(ctx, subscription)
 	if err != nil {
-		recordAuditFailure(ctx, s.audit, "subscription.update", "subscription", subscription.ID, metadata, err)
-		return domain.Subscription{}, err
+		return domain.Subscription{}, domain.Subscription{}, err
 	}
-	recordAuditSuccess(ctx, s.audit, "subscription.update", "subscription", updated.ID, metadata)
-	return updated, nil
+	if err := s.ensurePeriods(ctx, updated, plan); err != nil {
+		return domain.Subscription{}, domain.Subscription{}, err
+	}
+	return current, updated, nil
+}
+
+// ensurePeriods gera as competencias de uma assinatura ativa quando a
+// cobranca esta habilitada.
+func (s *SubscriptionService) ensurePeriods(ctx context.Context, subscription domain.Subscription, plan domain.Plan) error {
+	if s.periods == nil || subscription.Status != domain.SubscriptionActive || plan.DurationDays <= 0 {
+		return nil
+	}
+	_, err := ensureBillingPeriods(ctx, s.periods, subscription, plan, dateOnly(s.now()))
+	return err
 }
 
 func (s *SubscriptionService) FindByID(ctx context.Context, subscriptionID string) (domain.Subscription, error) {
@@ -204,23 +262,29 @@ func (s *SubscriptionService) FindByID(ctx context.Context, subscriptionID strin
 func (s *SubscriptionService) Cancel(ctx context.Context, subscriptionID string) (domain.Subscription, error) {
 	recordAuditAttempt(ctx, s.audit, "subscription.cancel", "subscription", subscriptionID, nil)
 
-	subscription, err := s.repo.FindByID(ctx, subscriptionID)
-	if err != nil {
-		recordAuditFailure(ctx, s.audit, "subscription.cancel", "subscription", subscriptionID, nil, err)
-		return domain.Subscription{}, err
-	}
+	var updated domain.Subscription
+	err := s.inTx(ctx, func(ctx context.Context, tx *SubscriptionService) error {
+		subscription, err := tx.repo.FindByID(ctx, subscriptionID)
+		if err != nil {
+			return err
+		}
 
-	subscription.Status = domain.SubscriptionCanceled
-	subscription.UpdatedAt = s.now()
+		subscription.Status = domain.SubscriptionCanceled
+		subscription.UpdatedAt = tx.now()
 
-	updated, err := s.repo.Update(ctx, subscription)
+		updated, err = tx.repo.Update(ctx, subscription)
+		if err != nil {
+			return err
+		}
+		recordAuditSuccess(ctx, tx.audit, "subscription.cancel", "subscription", updated.ID, map[string]any{
+			"status": string(updated.Status),
+		})
+		return nil
+	})
 	if err != nil {
 		recordAuditFailure(ctx, s.audit, "subscription.cancel", "subscription", subscriptionID, nil, err)
 		return domain.Subscription{}, err
 	}
-	recordAuditSuccess(ctx, s.audit, "subscription.cancel", "subscription", updated.ID, map[string]any{
-		"status": string(updated.Status),
-	})
 	return updated, nil
 }
 
@@ -228,10 +292,41 @@ func (s *SubscriptionService) ListByStudent(ctx context.Context, studentID strin
 	return s.repo.ListByStudent(ctx, studentID)
 }
 
+func (s *SubscriptionService) ListByStudentPage(ctx context.Context, studentID string, page ports.PageRequest) ([]domain.Subscription, error) {
+	return s.repo.ListByStudentPage(ctx, studentID, page)
+}
+
 func (s *SubscriptionService) DueBetween(ctx context.Context, start, end time.Time) ([]domain.Subscription, error) {
 	return s.repo.ListDueBetween(ctx, start, end)
 }
 
+func (s *SubscriptionService) DueBetweenPage(ctx context.Context, start, end time.Time, page ports.PageRequest) ([]domain.Subscription, error) {
+	return s.repo.ListDueBetweenPage(ctx, start, end, page)
+}
+
+// resolveDiscount copia o desconto do cupom para a assinatura. Se o cupom nao
+// mudou, o desconto ja copiado e mantido, mesmo que o cupom tenha sido
+// desativado depois.
+func (s *SubscriptionService) resolveDiscount(ctx context.Context, couponID string, current *domain.Subscription) (domain.Discount, error) {
+	if couponID == "" {
+		return domain.Discount{}, nil
+	}
+	if current != nil && current.CouponID == couponID {
+		return current.Discount, nil
+	}
+	if s.coupons == nil {
+		return domain.Discount{}, errors.New("repositorio de cupons nao configurado")
+	}
+	coupon, err := s.coupons.FindByID(ctx, couponID)
+	if err != nil {
+		return domain.Discount{}, err
+	}
+	if !coupon.Active {
+		return domain.Discount{}, validationError("cupom inativo")
+	}
+	return coupon.Discount, nil
+}
+
 func dateOnly(value time.Time) time.Time {
 	return time.Date(value.Year(), value.Month(), value.Day(), 0, 0, 0, 0, value.Location())
 }
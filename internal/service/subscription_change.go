@@ -0,0 +1,351 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/PabloPavan/jaiu/internal/domain"
+	"github.com/PabloPavan/jaiu/internal/ports"
+)
+
+// SetTxRunner faz a troca de plano acontecer em uma unica transacao.
+func (s *SubscriptionService) SetTxRunner(runner ports.TxRunner) {
+	s.txRunner = runner
+}
+
+// SetBilling habilita as operacoes que mexem nas competencias e no saldo da
+// assinatura, como a troca de plano.
+func (s *SubscriptionService) SetBilling(periods ports.BillingPeriodRepository, balances ports.SubscriptionBalanceRepository) {
+	s.periods = periods
+	s.balances = balances
+}
+
+// SetPaymentLedger liga a troca de plano as alocacoes dos pagamentos, para que
+// o credito levado para a nova assinatura continue preso ao pagamento de
+// origem e seja desfeito no estorno dele.
+func (s *SubscriptionService) SetPaymentLedger(allocations ports.PaymentAllocationRepository, transfers ports.PaymentCreditTransferRepository) {
+	s.allocations = allocations
+	s.transfers = transfers
+}
+
+// ChangePlan troca o plano de uma assinatura ativa sem perder o historico. A
+// assinatura atual e encerrada hoje, a competencia em curso passa a cobrar so
+// os dias usados e o valor pago a mais, junto com o saldo existente, vira
+// credito da nova assinatura. O credito que veio de pagamentos vira alocacoes
+// deles nas novas competencias, registradas em PaymentCreditTransfer. A nova
+// assinatura comeca hoje com o preco do novo plano, herda o que sobra do
+// desconto e aponta para a anterior.
+func (s *SubscriptionService) ChangePlan(ctx context.Context, subscriptionID, planID string) (domain.Subscription, error) {
+	metadata := map[string]any{
+		"plan_id": planID,
+	}
+	recordAuditAttempt(ctx, s.audit, "subscription.change_plan", "subscription", subscriptionID, metadata)
+
+	var created domain.Subscription
+	err := s.inTx(ctx, func(ctx context.Context, tx *SubscriptionService) error {
+		if tx.periods == nil || tx.balances == nil || tx.plans == nil || tx.allocations == nil || tx.transfers == nil {
+			return errors.New("dependencias da troca de plano indisponiveis")
+		}
+
+		current, err := tx.repo.FindByID(ctx, subscriptionID)
+		if err != nil {
+			return err
+		}
+		if current.Status != domain.SubscriptionActive {
+			return validationError("apenas assinaturas ativas podem trocar de plano")
+		}
+		if current.PlanID == planID {
+			return validationError("a assinatura ja usa este plano")
+		}
+
+		plan, err := tx.plans.FindByID(ctx, planID)
+		if err != nil {
+			return err
+		}
+		if !plan.Active {
+			return validationError("plano inativo")
+		}
+		if plan.DurationDays <= 0 || plan.PriceCents <= 0 {
+			return validationError("plano sem duracao ou preco")
+		}
+
+		today := dateOnly(tx.now())
+		closed, err := tx.closePeriodsAt(ctx, current, today)
+		if err != nil {
+			return err
+		}
+		discount := current.Discount.Remaining(discountedCycles(closed.periods))
+		couponID := current.CouponID
+		if discount.IsZero() {
+			discount = domain.Discount{}
+			couponID = ""
+		}
+
+		balance, err := tx.balances.Get(ctx, current.ID)
+		if err != nil {
+			return err
+		}
+		if balance.CreditCents != 0 {
+			if _, err := tx.balances.Set(ctx, domain.SubscriptionBalance{SubscriptionID: current.ID}); err != nil {
+				return err
+			}
+		}
+
+		now := tx.now()
+		autoRenew := current.AutoRenew
+		current.Status = domain.SubscriptionEnded
+		current.EndDate = today
+		current.AutoRenew = false
+		current.UpdatedAt = now
+		if _, err := tx.repo.Update(ctx, current); err != nil {
+			return err
+		}
+
+		created, err = tx.repo.Create(ctx, domain.Subscription{
+			StudentID:              current.StudentID,
+			PlanID:                 plan.ID,
+			StartDate:              today,
+			EndDate:                today.AddDate(0, 0, plan.DurationDays),
+			Status:                 domain.SubscriptionActive,
+			PriceCents:             plan.PriceCents,
+			PaymentDay:             current.PaymentDay,
+			AutoRenew:              autoRenew,
+			CouponID:               couponID,
+			Discount:               discount,
+			PreviousSubscriptionID: current.ID,
+			CreatedAt:              now,
+			UpdatedAt:              now,
+		})
+		if err != nil {
+			return err
+		}
+
+		if _, err := ensureBillingPeriods(ctx, tx.periods, created, plan, today); err != nil {
+			return err
+		}
+		leftover, err := tx.applyPaymentTransfers(ctx, created, closed.transfers, today)
+		if err != nil {
+			return err
+		}
+		credit := balance.CreditCents + closed.creditCents + leftover
+		if credit > 0 {
+			if _, err := tx.balances.Add(ctx, created.ID, credit); err != nil {
+				return err
+			}
+			if err := applySubscriptionBalance(ctx, tx.balances, tx.periods, created, today); err != nil {
+				return err
+			}
+		}
+
+		transferred := balance.CreditCents + closed.creditCents
+		for _, transfer := range closed.transfers {
+			transferred += transfer.AmountCents
+		}
+		recordAuditSuccess(ctx, tx.audit, "subscription.change_plan", "subscription", current.ID, map[string]any{
+			"coupon_id":           couponID,
+			"credit_cents":        transferred,
+			"new_subscription_id": created.ID,
+			"plan_id":             plan.ID,
+			"previous_plan_id":    current.PlanID,
+		})
+		return nil
+	})
+	if err != nil {
+		recordAuditFailure(ctx, s.audit, "subscription.change_plan", "subscription", subscriptionID, metadata, err)
+		return domain.Subscription{}, err
+	}
+	return created, nil
+}
+
+// closedPeriods e o resultado de encerrar as competencias numa troca de plano.
+type closedPeriods struct {
+	periods []domain.BillingPeriod
+	// transfers e o credito devolvido que saiu das alocacoes de cada
+	// pagamento, ainda sem a assinatura de destino.
+	transfers []domain.PaymentCreditTransfer
+	// creditCents e o credito devolvido sem pagamento de origem, como o saldo
+	// ja aplicado nas competencias.
+	creditCents int64
+}
+
+// closePeriodsAt encerra em today as competencias que ainda nao terminaram e
+// devolve o valor pago por dias que nao serao usados. O credito sai das
+// alocacoes mais recentes de cada competencia.
+func (s *SubscriptionService) closePeriodsAt(ctx context.Context, subscription domain.Subscription, today time.Time) (closedPeriods, error) {
+	paymentDay, err := effectivePaymentDay(subscription)
+	if err != nil {
+		return closedPeriods{}, err
+	}
+	periods, err := s.periods.ListBySubscription(ctx, subscription.ID)
+	if err != nil {
+		return closedPeriods{}, err
+	}
+
+	result := closedPeriods{periods: make([]domain.BillingPeriod, 0, len(periods))}
+	for _, period := range periods {
+		if !period.PeriodEnd.After(today) {
+			result.periods = append(result.periods, period)
+			continue
+		}
+		closed, credit := prorateBillingPeriod(period, today)
+		closed = settlePeriod(closed, today, paymentDay)
+		saved, err := s.periods.Update(ctx, closed)
+		if err != nil {
+			return closedPeriods{}, err
+		}
+		result.periods = append(result.periods, saved)
+
+		untied, err := s.releaseAllocations(ctx, period.ID, credit, &result.transfers)
+		if err != nil {
+			return closedPeriods{}, err
+		}
+		result.creditCents += untied
+	}
+	return result, nil
+}
+
+// releaseAllocations tira creditCents das alocacoes da competencia, da mais
+// recente para a mais antiga, e soma o valor tirado de cada pagamento em
+// transfers. Devolve a parte do credito sem alocacao de origem.
+func (s *SubscriptionService) releaseAllocations(ctx context.Context, periodID string, creditCents int64, transfers *[]domain.PaymentCreditTransfer) (int64, error) {
+	if creditCents <= 0 {
+		return 0, nil
+	}
+	allocations, err := s.allocations.ListByBillingPeriod(ctx, periodID)
+	if err != nil {
+		return 0, err
+	}
+
+	for i := len(allocations) - 1; i >= 0 && creditCents > 0; i-- {
+		allocation := allocations[i]
+		released := minInt64(allocation.AmountCents, creditCents)
+		if released <= 0 {
+			continue
+		}
+		allocation.AmountCents -= released
+		if err := s.allocations.Update(ctx, allocation); err != nil {
+			return 0, err
+		}
+		creditCents -= released
+		addPaymentTransfer(transfers, allocation.PaymentID, released)
+	}
+	return creditCents, nil
+}
+
+func addPaymentTransfer(transfers *[]domain.PaymentCreditTransfer, paymentID string, amountCents int64) {
+	for i := range *transfers {
+		if (*transfers)[i].PaymentID == paymentID {
+			(*transfers)[i].AmountCents += amountCents
+			return
+		}
+	}
+	*transfers = append(*transfers, domain.PaymentCreditTransfer{PaymentID: paymentID, AmountCents: amountCents})
+}
+
+// applyPaymentTransfers quita as competencias em aberto da nova assinatura com
+// o credito de cada pagamento, gravando alocacoes do proprio pagamento, e
+// registra a transferencia. Devolve o que sobrou para o saldo.
+func (s *SubscriptionService) applyPaymentTransfers(ctx context.Context, subscription domain.Subscription, transfers []domain.PaymentCreditTransfer, today time.Time) (int64, error) {
+	if len(transfers) == 0 {
+		return 0, nil
+	}
+	paymentDay, err := effectivePaymentDay(subscription)
+	if err != nil {
+		return 0, err
+	}
+	periods, err := s.periods.ListOpenBySubscription(ctx, subscription.ID)
+	if err != nil {
+		return 0, err
+	}
+
+	var leftover int64
+	for _, transfer := range transfers {
+		remaining := transfer.AmountCents
+		for i := range periods {
+			if remaining == 0 {
+				break
+			}
+			outstanding := periods[i].OutstandingCents()
+			if outstanding <= 0 {
+				continue
+			}
+
+			applied := minInt64(remaining, outstanding)
+			periods[i].AmountPaidCents += applied
+			periods[i] = settlePeriod(periods[i], today, paymentDay)
+			updated, err := s.periods.Update(ctx, periods[i])
+			if err != nil {
+				return 0, err
+			}
+			periods[i] = updated
+
+			if err := s.allocations.Create(ctx, domain.PaymentAllocation{
+				PaymentID:       transfer.PaymentID,
+				BillingPeriodID: updated.ID,
+				AmountCents:     applied,
+			}); err != nil {
+				return 0, err
+			}
+			remaining -= applied
+		}
+
+		transfer.SubscriptionID = subscription.ID
+		transfer.CreditCents = remaining
+		if err := s.transfers.Create(ctx, transfer); err != nil {
+			return 0, err
+		}
+		leftover += remaining
+	}
+	return leftover, nil
+}
+
+// prorateBillingPeriod reduz a competencia aos dias anteriores a cutoff. O
+// valor e o desconto sao proporcionais aos dias usados; o que ja foi pago
+// acima do novo valor sai da competencia e e devolvido como credito. Encargos
+// ja lancados nao mudam.
+func prorateBillingPeriod(period domain.BillingPeriod, cutoff time.Time) (domain.BillingPeriod, int64) {
+	start := dateOnly(period.PeriodStart)
+	if cutoff.Before(start) {
+		cutoff = start
+	}
+	total := int64(daysBetween(start, period.PeriodEnd))
+	used := int64(daysBetween(start, cutoff))
+	if total <= 0 {
+		return period, 0
+	}
+
+	period.AmountDueCents = (period.AmountDueCents*used + total/2) / total
+	period.DiscountCents = (period.DiscountCents*used + total/2) / total
+	period.PeriodEnd = cutoff
+
+	var credit int64
+	if principalPaid := period.AmountPaidCents - period.FeePaidCents(); principalPaid > period.AmountDueCents {
+		credit = principalPaid - period.AmountDueCents
+		period.AmountPaidCents -= credit
+	}
+	return period, credit
+}
+
+// inTx executa fn com uma copia do servico ligada a transacao. Sem txRunner, fn
+// recebe o proprio servico.
+func (s *SubscriptionService) inTx(ctx context.Context, fn func(context.Context, *SubscriptionService) error) error {
+	if s.txRunner == nil {
+		return fn(ctx, s)
+	}
+	return s.txRunner.RunInTx(ctx, ports.TxOptions{Isolation: ports.TxSerializable}, func(ctx context.Context, deps ports.TxDependencies) error {
+		return fn(ctx, &SubscriptionService{
+			repo:        deps.Subscriptions,
+			plans:       deps.Plans,
+			students:    deps.Students,
+			coupons:     s.coupons,
+			periods:     deps.BillingPeriods,
+			balances:    deps.Balances,
+			allocations: deps.Allocations,
+			transfers:   deps.Transfers,
+			freezes:     deps.Freezes,
+			audit:       deps.Audit,
+			now:         s.now,
+		})
+	})
+}
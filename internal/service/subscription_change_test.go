@@ -0,0 +1,305 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/PabloPavan/jaiu/internal/domain"
+	"github.com/PabloPavan/jaiu/internal/ports"
+)
+
+// Testa a cobranca proporcional aos dias usados e a devolucao do valor pago a mais.
+func TestProrateBillingPeriod(t *testing.T) {
+	period := domain.BillingPeriod{
+		PeriodStart:     time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+		PeriodEnd:       time.Date(2024, 1, 31, 0, 0, 0, 0, time.UTC),
+		AmountDueCents:  9000,
+		DiscountCents:   1000,
+		AmountPaidCents: 9200,
+		FeeCents:        200,
+	}
+
+	closed, credit := prorateBillingPeriod(period, time.Date(2024, 1, 11, 0, 0, 0, 0, time.UTC))
+	if closed.AmountDueCents != 3000 || closed.DiscountCents != 333 {
+		t.Fatalf("expected 10 of 30 days charged, got due=%d discount=%d", closed.AmountDueCents, closed.DiscountCents)
+	}
+	if credit != 6000 || closed.AmountPaidCents != 3200 {
+		t.Fatalf("expected 6000 of credit keeping the paid fee, got credit=%d paid=%d", credit, closed.AmountPaidCents)
+	}
+	if !closed.PeriodEnd.Equal(time.Date(2024, 1, 11, 0, 0, 0, 0, time.UTC)) {
+		t.Fatalf("unexpected period end: %v", closed.PeriodEnd)
+	}
+
+	future, credit := prorateBillingPeriod(domain.BillingPeriod{
+		PeriodStart:     time.Date(2024, 1, 31, 0, 0, 0, 0, time.UTC),
+		PeriodEnd:       time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC),
+		AmountDueCents:  9000,
+		AmountPaidCents: 9000,
+	}, time.Date(2024, 1, 11, 0, 0, 0, 0, time.UTC))
+	if future.AmountDueCents != 0 || future.AmountPaidCents != 0 || credit != 9000 {
+		t.Fatalf("expected a future period to be fully credited, got %#v credit=%d", future, credit)
+	}
+}
+
+// Testa a troca de plano encerrando a assinatura atual e levando o credito para a nova.
+func TestSubscriptionServiceChangePlan(t *testing.T) {
+	subscriptions := &subscriptionRepoFake{
+		subscriptions: map[string]domain.Subscription{
+			"sub-1": {
+				ID:         "sub-1",
+				StudentID:  "student-1",
+				PlanID:     "plan-monthly",
+				StartDate:  time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+				EndDate:    time.Date(2024, 1, 31, 0, 0, 0, 0, time.UTC),
+				Status:     domain.SubscriptionActive,
+				PriceCents: 9000,
+				PaymentDay: 1,
+				AutoRenew:  true,
+			},
+		},
+	}
+	plans := &planRepoFake{
+		plans: map[string]domain.Plan{
+			"plan-monthly":   {ID: "plan-monthly", DurationDays: 30, PriceCents: 9000, Active: true},
+			"plan-quarterly": {ID: "plan-quarterly", DurationDays: 90, PriceCents: 24000, Active: true},
+		},
+	}
+	periods := &billingPeriodRepoFake{
+		periods: map[string]domain.BillingPeriod{
+			"period-1": {
+				ID:              "period-1",
+				SubscriptionID:  "sub-1",
+				PeriodStart:     time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+				PeriodEnd:       time.Date(2024, 1, 31, 0, 0, 0, 0, time.UTC),
+				AmountDueCents:  9000,
+				AmountPaidCents: 9000,
+				Status:          domain.BillingPaid,
+			},
+		},
+	}
+	balances := &balanceRepoFake{
+		balances: map[string]domain.SubscriptionBalance{
+			"sub-1": {SubscriptionID: "sub-1", CreditCents: 500},
+		},
+	}
+	audit := &auditRepoFake{}
+	runner := &txRunnerFake{
+		deps: ports.TxDependencies{
+			Subscriptions:  subscriptions,
+			Plans:          plans,
+			BillingPeriods: periods,
+			Balances:       balances,
+			Allocations:    &paymentAllocationRepoFake{},
+			Transfers:      &paymentCreditTransferRepoFake{},
+		},
+		audit: audit,
+	}
+
+	service := NewSubscriptionService(subscriptions, plans, nil, audit)
+	service.SetBilling(periods, balances)
+	service.SetTxRunner(runner)
+	service.now = func() time.Time { return time.Date(2024, 1, 11, 9, 0, 0, 0, time.UTC) }
+
+	created, err := service.ChangePlan(context.Background(), "sub-1", "plan-quarterly")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if runner.opts.Isolation != ports.TxSerializable {
+		t.Fatalf("unexpected isolation: %q", runner.opts.Isolation)
+	}
+
+	if created.PreviousSubscriptionID != "sub-1" || created.PlanID != "plan-quarterly" || created.PriceCents != 24000 {
+		t.Fatalf("unexpected new subscription: %#v", created)
+	}
+	if !created.StartDate.Equal(time.Date(2024, 1, 11, 0, 0, 0, 0, time.UTC)) || !created.AutoRenew || created.PaymentDay != 1 {
+		t.Fatalf("expected the new subscription to start today keeping the billing settings, got %#v", created)
+	}
+
+	old := subscriptions.subscriptions["sub-1"]
+	if old.Status != domain.SubscriptionEnded || old.AutoRenew || !old.EndDate.Equal(time.Date(2024, 1, 11, 0, 0, 0, 0, time.UTC)) {
+		t.Fatalf("expected the old subscription to end today, got %#v", old)
+	}
+
+	closed := periods.periods["period-1"]
+	if closed.AmountDueCents != 3000 || closed.AmountPaidCents != 3000 || closed.Status != domain.BillingPaid {
+		t.Fatalf("expected the current period charged pro rata, got %#v", closed)
+	}
+
+	if balances.balances["sub-1"].CreditCents != 0 {
+		t.Fatalf("expected the old balance to be transferred, got %d", balances.balances["sub-1"].CreditCents)
+	}
+	newPeriods, _ := periods.ListBySubscription(context.Background(), created.ID)
+	if len(newPeriods) != 1 || newPeriods[0].AmountDueCents != 24000 || newPeriods[0].AmountPaidCents != 6500 {
+		t.Fatalf("expected the credit applied to the new period, got %#v", newPeriods)
+	}
+
+	last := audit.events[len(audit.events)-1]
+	if last.Action != "subscription.change_plan.success" || last.Metadata["credit_cents"] != int64(6500) || last.Metadata["new_subscription_id"] != created.ID {
+		t.Fatalf("unexpected audit event: %#v", last)
+	}
+}
+
+// Testa que a troca para o mesmo plano ou de assinatura encerrada e recusada.
+func TestSubscriptionServiceChangePlanRejects(t *testing.T) {
+	subscriptions := &subscriptionRepoFake{
+		subscriptions: map[string]domain.Subscription{
+			"sub-1": {ID: "sub-1", PlanID: "plan-1", Status: domain.SubscriptionActive, PaymentDay: 1},
+			"sub-2": {ID: "sub-2", PlanID: "plan-1", Status: domain.SubscriptionEnded, PaymentDay: 1},
+		},
+	}
+	plans := &planRepoFake{
+		plans: map[string]domain.Plan{
+			"plan-1": {ID: "plan-1", DurationDays: 30, PriceCents: 9000, Active: true},
+			"plan-2": {ID: "plan-2", DurationDays: 90, PriceCents: 24000},
+		},
+	}
+	audit := &auditRepoFake{}
+	service := NewSubscriptionService(subscriptions, plans, nil, audit)
+	service.SetBilling(&billingPeriodRepoFake{}, &balanceRepoFake{})
+	service.SetPaymentLedger(&paymentAllocationRepoFake{}, &paymentCreditTransferRepoFake{})
+
+	if _, err := service.ChangePlan(context.Background(), "sub-1", "plan-1"); err == nil {
+		t.Fatal("expected error for the same plan")
+	}
+	if _, err := service.ChangePlan(context.Background(), "sub-2", "plan-2"); err == nil {
+		t.Fatal("expected error for an ended subscription")
+	}
+	if _, err := service.ChangePlan(context.Background(), "sub-1", "plan-2"); err == nil {
+		t.Fatal("expected error for an inactive plan")
+	}
+	if len(subscriptions.subscriptions) != 2 {
+		t.Fatalf("expected no new subscription, got %d", len(subscriptions.subscriptions))
+	}
+	last := audit.events[len(audit.events)-1]
+	if last.Action != "subscription.change_plan.failure" {
+		t.Fatalf("unexpected audit event: %#v", last)
+	}
+}
+
+// Testa que o credito levado na troca de plano continua preso ao pagamento e
+// que o estorno dele desfaz tambem o que foi pago na nova assinatura.
+func TestSubscriptionServiceChangePlanCreditFollowsPaymentReversal(t *testing.T) {
+	tests := []struct {
+		name        string
+		priceCents  int64
+		wantPaid    int64
+		wantBalance int64
+	}{
+		{"upgrade", 24000, 6000, 0},
+		{"downgrade", 2000, 2000, 4000},
+	}
+
+	for _, tt := range tests {
+		subscriptions := &subscriptionRepoFake{
+			subscriptions: map[string]domain.Subscription{
+				"sub-1": {ID: "sub-1", PlanID: "plan-1", StartDate: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), Status: domain.SubscriptionActive, PriceCents: 9000, PaymentDay: 1},
+			},
+		}
+		plans := &planRepoFake{
+			plans: map[string]domain.Plan{
+				"plan-1": {ID: "plan-1", DurationDays: 30, PriceCents: 9000, Active: true},
+				"plan-2": {ID: "plan-2", DurationDays: 30, PriceCents: tt.priceCents, Active: true},
+			},
+		}
+		periods := &billingPeriodRepoFake{periods: map[string]domain.BillingPeriod{
+			"period-1": {ID: "period-1", SubscriptionID: "sub-1", PeriodStart: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), PeriodEnd: time.Date(2024, 1, 31, 0, 0, 0, 0, time.UTC), AmountDueCents: 9000, AmountPaidCents: 9000, Status: domain.BillingPaid},
+		}}
+		payments := &paymentRepoFake{payments: map[string]domain.Payment{
+			"payment-1": {ID: "payment-1", SubscriptionID: "sub-1", AmountCents: 9000, Status: domain.PaymentConfirmed},
+		}}
+		allocations := &paymentAllocationRepoFake{allocations: map[string][]domain.PaymentAllocation{
+			"payment-1": {{PaymentID: "payment-1", BillingPeriodID: "period-1", AmountCents: 9000}},
+		}}
+		transfers := &paymentCreditTransferRepoFake{}
+		balances := &balanceRepoFake{balances: map[string]domain.SubscriptionBalance{"sub-1": {SubscriptionID: "sub-1"}}}
+		now := func() time.Time { return time.Date(2024, 1, 11, 9, 0, 0, 0, time.UTC) }
+
+		subscriptionService := NewSubscriptionService(subscriptions, plans, nil, &auditRepoFake{})
+		subscriptionService.SetBilling(periods, balances)
+		subscriptionService.SetPaymentLedger(allocations, transfers)
+		subscriptionService.now = now
+
+		created, err := subscriptionService.ChangePlan(context.Background(), "sub-1", "plan-2")
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", tt.name, err)
+		}
+		newPeriods, _ := periods.ListBySubscription(context.Background(), created.ID)
+		if len(newPeriods) != 1 || newPeriods[0].AmountPaidCents != tt.wantPaid {
+			t.Fatalf("%s: expected %d paid on the new period, got %#v", tt.name, tt.wantPaid, newPeriods)
+		}
+		if balances.balances[created.ID].CreditCents != tt.wantBalance {
+			t.Fatalf("%s: expected %d of balance, got %d", tt.name, tt.wantBalance, balances.balances[created.ID].CreditCents)
+		}
+		ledger := transfers.transfers["payment-1"]
+		if len(ledger) != 1 || ledger[0].SubscriptionID != created.ID || ledger[0].AmountCents != 6000 || ledger[0].CreditCents != tt.wantBalance {
+			t.Fatalf("%s: unexpected credit transfer: %#v", tt.name, ledger)
+		}
+
+		paymentService := NewPaymentService(payments, subscriptions, plans, periods, balances, allocations, &auditRepoFake{}, nil)
+		paymentService.SetCreditTransfers(transfers)
+		paymentService.now = now
+		if _, err := paymentService.Reverse(context.Background(), "payment-1"); err != nil {
+			t.Fatalf("%s: unexpected reverse error: %v", tt.name, err)
+		}
+
+		var paid int64
+		for _, period := range periods.periods {
+			paid += period.AmountPaidCents
+		}
+		if paid != 0 || balances.balances[created.ID].CreditCents != 0 {
+			t.Fatalf("%s: expected the reversal to undo all of the payment, got paid=%d balance=%d", tt.name, paid, balances.balances[created.ID].CreditCents)
+		}
+		if len(allocations.allocations) != 0 || len(transfers.transfers) != 0 {
+			t.Fatalf("%s: expected allocations and transfers removed, got %#v %#v", tt.name, allocations.allocations, transfers.transfers)
+		}
+	}
+}
+
+// Testa que a nova assinatura herda so o que sobra do desconto do cupom.
+func TestSubscriptionServiceChangePlanCarriesRemainingDiscount(t *testing.T) {
+	tests := []struct {
+		name         string
+		discount     domain.Discount
+		wantCoupon   string
+		wantPeriods  int
+		wantDiscount int64
+	}{
+		{"repeating", domain.Discount{Kind: domain.DiscountPercent, Value: 1000, Duration: domain.DiscountRepeating, Periods: 3}, "coupon-1", 2, 2400},
+		{"once", domain.Discount{Kind: domain.DiscountFixed, Value: 1000, Duration: domain.DiscountOnce}, "", 0, 0},
+	}
+
+	for _, tt := range tests {
+		subscriptions := &subscriptionRepoFake{
+			subscriptions: map[string]domain.Subscription{
+				"sub-1": {ID: "sub-1", PlanID: "plan-1", StartDate: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), Status: domain.SubscriptionActive, PriceCents: 9000, PaymentDay: 1, CouponID: "coupon-1", Discount: tt.discount},
+			},
+		}
+		plans := &planRepoFake{
+			plans: map[string]domain.Plan{
+				"plan-1": {ID: "plan-1", DurationDays: 30, PriceCents: 9000, Active: true},
+				"plan-2": {ID: "plan-2", DurationDays: 30, PriceCents: 24000, Active: true},
+			},
+		}
+		periods := &billingPeriodRepoFake{periods: map[string]domain.BillingPeriod{
+			"period-1": {ID: "period-1", SubscriptionID: "sub-1", PeriodStart: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), PeriodEnd: time.Date(2024, 1, 31, 0, 0, 0, 0, time.UTC), AmountDueCents: 8000, DiscountCents: 1000, Status: domain.BillingOpen},
+		}}
+
+		service := NewSubscriptionService(subscriptions, plans, nil, &auditRepoFake{})
+		service.SetBilling(periods, &balanceRepoFake{balances: map[string]domain.SubscriptionBalance{"sub-1": {SubscriptionID: "sub-1"}}})
+		service.SetPaymentLedger(&paymentAllocationRepoFake{}, &paymentCreditTransferRepoFake{})
+		service.now = func() time.Time { return time.Date(2024, 1, 11, 9, 0, 0, 0, time.UTC) }
+
+		created, err := service.ChangePlan(context.Background(), "sub-1", "plan-2")
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", tt.name, err)
+		}
+		if created.CouponID != tt.wantCoupon || created.Discount.Periods != tt.wantPeriods {
+			t.Fatalf("%s: unexpected discount on the new subscription: coupon=%q %#v", tt.name, created.CouponID, created.Discount)
+		}
+		newPeriods, _ := periods.ListBySubscription(context.Background(), created.ID)
+		if len(newPeriods) != 1 || newPeriods[0].DiscountCents != tt.wantDiscount {
+			t.Fatalf("%s: expected %d of discount on the new period, got %#v", tt.name, tt.wantDiscount, newPeriods)
+		}
+	}
+}
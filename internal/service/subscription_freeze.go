@@ -0,0 +1,196 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/PabloPavan/jaiu/internal/domain"
+	"github.com/PabloPavan/jaiu/internal/ports"
+)
+
+// SetFreezes habilita o trancamento de assinaturas.
+func (s *SubscriptionService) SetFreezes(repo ports.SubscriptionFreezeRepository) {
+	s.freezes = repo
+}
+
+func (s *SubscriptionService) ListFreezes(ctx context.Context, subscriptionID string) ([]domain.SubscriptionFreeze, error) {
+	if s.freezes == nil {
+		return nil, nil
+	}
+	return s.freezes.ListBySubscription(ctx, subscriptionID)
+}
+
+func (s *SubscriptionService) ListFreezesPage(ctx context.Context, subscriptionID string, page ports.PageRequest) ([]domain.SubscriptionFreeze, error) {
+	if s.freezes == nil {
+		return nil, nil
+	}
+	return s.freezes.ListBySubscriptionPage(ctx, subscriptionID, page)
+}
+
+// Freeze tranca a assinatura entre as datas informadas. Os dias trancados
+// adiam o vencimento e o fim da competencia em curso, o inicio das seguintes e
+// o fim da assinatura; assim nenhuma competencia e gerada nem fica em atraso
+// durante o trancamento.
+func (s *SubscriptionService) Freeze(ctx context.Context, freeze domain.SubscriptionFreeze) (domain.SubscriptionFreeze, error) {
+	freeze.StartDate = dateOnly(freeze.StartDate)
+	freeze.EndDate = dateOnly(freeze.EndDate)
+	freeze.Reason = strings.TrimSpace(freeze.Reason)
+	metadata := map[string]any{
+		"end_date":   auditDate(freeze.EndDate),
+		"reason":     freeze.Reason,
+		"start_date": auditDate(freeze.StartDate),
+	}
+	recordAuditAttempt(ctx, s.audit, "subscription.freeze", "subscription", freeze.SubscriptionID, metadata)
+
+	var created domain.SubscriptionFreeze
+	err := s.inTx(ctx, func(ctx context.Context, tx *SubscriptionService) error {
+		if tx.freezes == nil || tx.periods == nil || tx.plans == nil {
+			return errors.New("dependencias do trancamento indisponiveis")
+		}
+
+		subscription, err := tx.repo.FindByID(ctx, freeze.SubscriptionID)
+		if err != nil {
+			return err
+		}
+		plan, err := tx.plans.FindByID(ctx, subscription.PlanID)
+		if err != nil {
+			return err
+		}
+		existing, err := tx.freezes.ListBySubscription(ctx, subscription.ID)
+		if err != nil {
+			return err
+		}
+
+		today := dateOnly(tx.now())
+		if err := validateFreeze(freeze, subscription, plan, existing, today); err != nil {
+			return err
+		}
+		if err := tx.shiftPeriodsForFreeze(ctx, subscription, plan, freeze, today); err != nil {
+			return err
+		}
+
+		days := freeze.Days()
+		subscription.EndDate = subscription.EndDate.AddDate(0, 0, days)
+		subscription.UpdatedAt = tx.now()
+		if _, err := tx.repo.Update(ctx, subscription); err != nil {
+			return err
+		}
+
+		created, err = tx.freezes.Create(ctx, freeze)
+		if err != nil {
+			return err
+		}
+
+		success := copyMetadata(metadata)
+		success["days"] = days
+		success["end_date_after"] = auditDate(subscription.EndDate)
+		recordAuditSuccess(ctx, tx.audit, "subscription.freeze", "subscription", subscription.ID, success)
+		return nil
+	})
+	if err != nil {
+		recordAuditFailure(ctx, s.audit, "subscription.freeze", "subscription", freeze.SubscriptionID, metadata, err)
+		return domain.SubscriptionFreeze{}, err
+	}
+	return created, nil
+}
+
+// shiftPeriodsForFreeze empurra o calendario de cobranca pelos dias trancados.
+// A competencia em curso no inicio do trancamento ganha os dias em FrozenDays;
+// as ja geradas depois dela comecam mais tarde. Com renovacao automatica o
+// trancamento precisa comecar ate o vencimento da ultima competencia gerada.
+func (s *SubscriptionService) shiftPeriodsForFreeze(ctx context.Context, subscription domain.Subscription, plan domain.Plan, freeze domain.SubscriptionFreeze, today time.Time) error {
+	paymentDay, err := effectivePaymentDay(subscription)
+	if err != nil {
+		return err
+	}
+	periods, err := ensureBillingPeriods(ctx, s.periods, subscription, plan, today)
+	if err != nil {
+		return err
+	}
+	for _, period := range periods {
+		if period.Status == domain.BillingOverdue {
+			return validationError("assinatura com competencias em atraso nao pode ser trancada")
+		}
+	}
+
+	currentID := ""
+	for _, period := range periods {
+		if !dateOnly(period.PeriodStart).After(freeze.StartDate) {
+			currentID = period.ID
+			if !freeze.StartDate.Before(renewalDateForPeriod(period, paymentDay)) {
+				currentID = ""
+				if subscription.AutoRenew {
+					return validationError(fmt.Sprintf("o trancamento deve comecar ate %s", periodDueDate(period, paymentDay).Format("02/01/2006")))
+				}
+			}
+		}
+	}
+
+	// As competencias seguintes sao adiadas da ultima para a primeira para nao
+	// colidir com o inicio de outra competencia da mesma assinatura.
+	days := freeze.Days()
+	sort.Slice(periods, func(i, j int) bool {
+		return periods[i].PeriodStart.After(periods[j].PeriodStart)
+	})
+	for _, period := range periods {
+		switch {
+		case period.ID == currentID:
+			period.FrozenDays += days
+		case dateOnly(period.PeriodStart).After(freeze.StartDate):
+			period.PeriodStart = period.PeriodStart.AddDate(0, 0, days)
+			period.ShiftDays += days
+		default:
+			continue
+		}
+		period.PeriodEnd = period.PeriodEnd.AddDate(0, 0, days)
+		period = settlePeriod(period, today, paymentDay)
+		if _, err := s.periods.Update(ctx, period); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func validateFreeze(freeze domain.SubscriptionFreeze, subscription domain.Subscription, plan domain.Plan, existing []domain.SubscriptionFreeze, today time.Time) error {
+	if freeze.Reason == "" {
+		return validationError("motivo do trancamento e obrigatorio")
+	}
+	if freeze.StartDate.IsZero() || freeze.EndDate.IsZero() {
+		return validationError("datas do trancamento sao obrigatorias")
+	}
+	if freeze.EndDate.Before(freeze.StartDate) {
+		return validationError("fim do trancamento deve ser depois do inicio")
+	}
+	if subscription.Status != domain.SubscriptionActive {
+		return validationError("apenas assinaturas ativas podem ser trancadas")
+	}
+	if freeze.StartDate.Before(today) || freeze.StartDate.Before(dateOnly(subscription.StartDate)) {
+		return validationError("o trancamento nao pode comecar no passado nem antes da assinatura")
+	}
+	if !subscription.AutoRenew && freeze.StartDate.After(dateOnly(subscription.EndDate)) {
+		return validationError("o trancamento deve comecar antes do fim da assinatura")
+	}
+	if plan.MaxFreezeDays <= 0 {
+		return validationError("o plano nao permite trancamento")
+	}
+
+	for _, other := range existing {
+		if freeze.Overlaps(other) {
+			return validationError("ja existe um trancamento nesse periodo")
+		}
+	}
+	for year := freeze.StartDate.Year(); year <= freeze.EndDate.Year(); year++ {
+		used := freeze.DaysInYear(year)
+		for _, other := range existing {
+			used += other.DaysInYear(year)
+		}
+		if used > plan.MaxFreezeDays {
+			return validationError(fmt.Sprintf("limite de %d dias de trancamento em %d excedido", plan.MaxFreezeDays, year))
+		}
+	}
+	return nil
+}
@@ -0,0 +1,247 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/PabloPavan/jaiu/internal/domain"
+	"github.com/PabloPavan/jaiu/internal/ports"
+)
+
+// Testa que o trancamento adia vencimento, renovacao e fim da assinatura pelos dias trancados.
+func TestSubscriptionServiceFreeze(t *testing.T) {
+	subscriptions := &subscriptionRepoFake{
+		subscriptions: map[string]domain.Subscription{
+			"sub-1": {
+				ID:         "sub-1",
+				PlanID:     "plan-1",
+				StartDate:  time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+				EndDate:    time.Date(2024, 1, 31, 0, 0, 0, 0, time.UTC),
+				Status:     domain.SubscriptionActive,
+				PriceCents: 9000,
+				PaymentDay: 10,
+				AutoRenew:  true,
+			},
+		},
+	}
+	plan := domain.Plan{ID: "plan-1", DurationDays: 30, PriceCents: 9000, MaxFreezeDays: 30, Active: true}
+	plans := &planRepoFake{plans: map[string]domain.Plan{"plan-1": plan}}
+	periods := &billingPeriodRepoFake{
+		periods: map[string]domain.BillingPeriod{
+			"period-1": {
+				ID:             "period-1",
+				SubscriptionID: "sub-1",
+				PeriodStart:    time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+				PeriodEnd:      time.Date(2024, 1, 31, 0, 0, 0, 0, time.UTC),
+				AmountDueCents: 9000,
+				Status:         domain.BillingOpen,
+			},
+		},
+	}
+	freezes := &freezeRepoFake{}
+	audit := &auditRepoFake{}
+	runner := &txRunnerFake{
+		deps: ports.TxDependencies{
+			Subscriptions:  subscriptions,
+			Plans:          plans,
+			BillingPeriods: periods,
+			Freezes:        freezes,
+		},
+		audit: audit,
+	}
+
+	service := NewSubscriptionService(subscriptions, plans, nil, audit)
+	service.SetBilling(periods, &balanceRepoFake{})
+	service.SetFreezes(freezes)
+	service.SetTxRunner(runner)
+	service.now = func() time.Time { return time.Date(2024, 1, 5, 9, 0, 0, 0, time.UTC) }
+
+	created, err := service.Freeze(context.Background(), domain.SubscriptionFreeze{
+		SubscriptionID: "sub-1",
+		StartDate:      time.Date(2024, 1, 5, 0, 0, 0, 0, time.UTC),
+		EndDate:        time.Date(2024, 1, 14, 0, 0, 0, 0, time.UTC),
+		Reason:         " viagem ",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if created.ID == "" || created.Reason != "viagem" || len(freezes.freezes) != 1 {
+		t.Fatalf("unexpected freeze: %#v", created)
+	}
+
+	subscription := subscriptions.subscriptions["sub-1"]
+	if !subscription.EndDate.Equal(time.Date(2024, 2, 10, 0, 0, 0, 0, time.UTC)) || subscription.Status != domain.SubscriptionActive {
+		t.Fatalf("expected the end date shifted by 10 days, got %#v", subscription)
+	}
+	current := periods.periods["period-1"]
+	if current.FrozenDays != 10 || !current.PeriodEnd.Equal(time.Date(2024, 2, 10, 0, 0, 0, 0, time.UTC)) {
+		t.Fatalf("expected the current period frozen for 10 days, got %#v", current)
+	}
+	if due := periodDueDate(current, 10); !due.Equal(time.Date(2024, 1, 20, 0, 0, 0, 0, time.UTC)) {
+		t.Fatalf("expected the due date shifted to 2024-01-20, got %v", due)
+	}
+
+	during, err := ensureBillingPeriods(context.Background(), periods, subscription, plan, time.Date(2024, 1, 18, 0, 0, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(during) != 1 || during[0].Status == domain.BillingOverdue {
+		t.Fatalf("expected no renewal and nothing overdue during the freeze, got %#v", during)
+	}
+
+	after, err := ensureBillingPeriods(context.Background(), periods, subscription, plan, time.Date(2024, 1, 22, 0, 0, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(after) != 2 || after[0].Status != domain.BillingOverdue {
+		t.Fatalf("expected the renewal after the shifted due date, got %#v", after)
+	}
+	next := after[1]
+	if !next.PeriodStart.Equal(time.Date(2024, 1, 21, 0, 0, 0, 0, time.UTC)) || next.ShiftDays != 10 {
+		t.Fatalf("expected the next period shifted by 10 days, got %#v", next)
+	}
+	if due := periodDueDate(next, 10); !due.Equal(time.Date(2024, 2, 20, 0, 0, 0, 0, time.UTC)) {
+		t.Fatalf("expected the next due date shifted to 2024-02-20, got %v", due)
+	}
+
+	last := audit.events[len(audit.events)-1]
+	if last.Action != "subscription.freeze.success" || last.Metadata["days"] != 10 {
+		t.Fatalf("unexpected audit event: %#v", last)
+	}
+}
+
+// Testa que as competencias ja geradas depois do inicio do trancamento sao adiadas.
+func TestSubscriptionServiceFreezeShiftsLaterPeriods(t *testing.T) {
+	subscriptions := &subscriptionRepoFake{
+		subscriptions: map[string]domain.Subscription{
+			"sub-1": {
+				ID:         "sub-1",
+				PlanID:     "plan-1",
+				StartDate:  time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+				EndDate:    time.Date(2024, 3, 31, 0, 0, 0, 0, time.UTC),
+				Status:     domain.SubscriptionActive,
+				PriceCents: 9000,
+				PaymentDay: 1,
+			},
+		},
+	}
+	plans := &planRepoFake{plans: map[string]domain.Plan{
+		"plan-1": {ID: "plan-1", DurationDays: 30, PriceCents: 9000, MaxFreezeDays: 15, Active: true},
+	}}
+	periods := &billingPeriodRepoFake{
+		periods: map[string]domain.BillingPeriod{
+			"period-1": {
+				ID:              "period-1",
+				SubscriptionID:  "sub-1",
+				PeriodStart:     time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+				PeriodEnd:       time.Date(2024, 1, 31, 0, 0, 0, 0, time.UTC),
+				AmountDueCents:  9000,
+				AmountPaidCents: 9000,
+				Status:          domain.BillingPaid,
+			},
+			"period-2": {
+				ID:             "period-2",
+				SubscriptionID: "sub-1",
+				PeriodStart:    time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC),
+				PeriodEnd:      time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC),
+				AmountDueCents: 9000,
+				Status:         domain.BillingOpen,
+			},
+			"period-3": {
+				ID:             "period-3",
+				SubscriptionID: "sub-1",
+				PeriodStart:    time.Date(2024, 2, 2, 0, 0, 0, 0, time.UTC),
+				PeriodEnd:      time.Date(2024, 3, 3, 0, 0, 0, 0, time.UTC),
+				AmountDueCents: 9000,
+				Status:         domain.BillingOpen,
+			},
+		},
+	}
+	service := NewSubscriptionService(subscriptions, plans, nil, &auditRepoFake{})
+	service.SetBilling(periods, &balanceRepoFake{})
+	service.SetFreezes(&freezeRepoFake{})
+	service.now = func() time.Time { return time.Date(2024, 1, 10, 9, 0, 0, 0, time.UTC) }
+
+	_, err := service.Freeze(context.Background(), domain.SubscriptionFreeze{
+		SubscriptionID: "sub-1",
+		StartDate:      time.Date(2024, 1, 20, 0, 0, 0, 0, time.UTC),
+		EndDate:        time.Date(2024, 1, 24, 0, 0, 0, 0, time.UTC),
+		Reason:         "lesao",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if paid := periods.periods["period-1"]; paid.FrozenDays != 0 || paid.ShiftDays != 0 {
+		t.Fatalf("expected the finished period untouched, got %#v", paid)
+	}
+	current := periods.periods["period-2"]
+	if current.FrozenDays != 5 || !current.PeriodEnd.Equal(time.Date(2024, 2, 6, 0, 0, 0, 0, time.UTC)) {
+		t.Fatalf("expected the current period frozen for 5 days, got %#v", current)
+	}
+	later := periods.periods["period-3"]
+	if !later.PeriodStart.Equal(time.Date(2024, 2, 7, 0, 0, 0, 0, time.UTC)) || later.ShiftDays != 5 || later.FrozenDays != 0 {
+		t.Fatalf("expected the later period to start 5 days later, got %#v", later)
+	}
+	if due := periodDueDate(later, 1); !due.Equal(time.Date(2024, 3, 6, 0, 0, 0, 0, time.UTC)) {
+		t.Fatalf("expected the later due date shifted to 2024-03-06, got %v", due)
+	}
+}
+
+// Testa o limite anual de dias do plano, a sobreposicao e planos sem trancamento.
+func TestSubscriptionServiceFreezeRejects(t *testing.T) {
+	subscriptions := &subscriptionRepoFake{
+		subscriptions: map[string]domain.Subscription{
+			"sub-1": {ID: "sub-1", PlanID: "plan-1", StartDate: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), EndDate: time.Date(2024, 12, 31, 0, 0, 0, 0, time.UTC), Status: domain.SubscriptionActive, PriceCents: 9000, PaymentDay: 15},
+			"sub-2": {ID: "sub-2", PlanID: "plan-2", StartDate: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), EndDate: time.Date(2024, 12, 31, 0, 0, 0, 0, time.UTC), Status: domain.SubscriptionActive, PriceCents: 9000, PaymentDay: 1},
+		},
+	}
+	plans := &planRepoFake{plans: map[string]domain.Plan{
+		"plan-1": {ID: "plan-1", DurationDays: 365, PriceCents: 9000, MaxFreezeDays: 20, Active: true},
+		"plan-2": {ID: "plan-2", DurationDays: 365, PriceCents: 9000, Active: true},
+	}}
+	freezes := &freezeRepoFake{
+		freezes: []domain.SubscriptionFreeze{{
+			ID:             "freeze-1",
+			SubscriptionID: "sub-1",
+			StartDate:      time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC),
+			EndDate:        time.Date(2024, 2, 15, 0, 0, 0, 0, time.UTC),
+			Reason:         "viagem",
+		}},
+	}
+	audit := &auditRepoFake{}
+	service := NewSubscriptionService(subscriptions, plans, nil, audit)
+	service.SetBilling(&billingPeriodRepoFake{}, &balanceRepoFake{})
+	service.SetFreezes(freezes)
+	service.now = func() time.Time { return time.Date(2024, 1, 10, 9, 0, 0, 0, time.UTC) }
+
+	cases := []domain.SubscriptionFreeze{
+		{SubscriptionID: "sub-1", StartDate: time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC), EndDate: time.Date(2024, 3, 10, 0, 0, 0, 0, time.UTC), Reason: "ferias"},
+		{SubscriptionID: "sub-1", StartDate: time.Date(2024, 2, 10, 0, 0, 0, 0, time.UTC), EndDate: time.Date(2024, 2, 12, 0, 0, 0, 0, time.UTC), Reason: "ferias"},
+		{SubscriptionID: "sub-1", StartDate: time.Date(2024, 1, 5, 0, 0, 0, 0, time.UTC), EndDate: time.Date(2024, 1, 6, 0, 0, 0, 0, time.UTC), Reason: "ferias"},
+		{SubscriptionID: "sub-1", StartDate: time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC), EndDate: time.Date(2024, 3, 2, 0, 0, 0, 0, time.UTC)},
+		{SubscriptionID: "sub-2", StartDate: time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC), EndDate: time.Date(2024, 3, 2, 0, 0, 0, 0, time.UTC), Reason: "ferias"},
+	}
+	for i, freeze := range cases {
+		if _, err := service.Freeze(context.Background(), freeze); err == nil {
+			t.Fatalf("case %d: expected error", i)
+		}
+	}
+	if len(freezes.freezes) != 1 {
+		t.Fatalf("expected no new freeze, got %d", len(freezes.freezes))
+	}
+	last := audit.events[len(audit.events)-1]
+	if last.Action != "subscription.freeze.failure" {
+		t.Fatalf("unexpected audit event: %#v", last)
+	}
+
+	if _, err := service.Freeze(context.Background(), domain.SubscriptionFreeze{
+		SubscriptionID: "sub-1",
+		StartDate:      time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC),
+		EndDate:        time.Date(2024, 3, 5, 0, 0, 0, 0, time.UTC),
+		Reason:         "ferias",
+	}); err != nil {
+		t.Fatalf("expected the remaining days to be accepted, got %v", err)
+	}
+}
@@ -2,10 +2,13 @@ package service
 
 import (
 	"context"
+	"errors"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/PabloPavan/jaiu/internal/domain"
+	"github.com/PabloPavan/jaiu/internal/ports"
 )
 
 // Testa Create validando campos obrigatorios.
@@ -97,3 +100,87 @@ func TestSubscriptionServiceCancel(t *testing.T) {
 		t.Fatalf("expected canceled, got %q", updated.Status)
 	}
 }
+
+// Testa que Create grava a assinatura e as competencias na mesma transacao e
+// que uma falha nas competencias desfaz tudo, sem evento de sucesso.
+func TestSubscriptionServiceCreateRollsBackWhenPeriodsFail(t *testing.T) {
+	outerRepo := &subscriptionRepoFake{}
+	txRepo := &subscriptionRepoFake{}
+	planRepo := &planRepoFake{
+		plans: map[string]domain.Plan{
+			"plan-1": {ID: "plan-1", DurationDays: 30, PriceCents: 1000},
+		},
+	}
+	periods := &billingPeriodRepoFake{createErr: errors.New("insert failed")}
+	audit := &auditRepoFake{}
+	runner := &txRunnerFake{deps: ports.TxDependencies{Subscriptions: txRepo, Plans: planRepo, BillingPeriods: periods}, audit: audit}
+
+	service := NewSubscriptionService(outerRepo, planRepo, nil, audit)
+	service.SetBilling(&billingPeriodRepoFake{}, &balanceRepoFake{})
+	service.SetTxRunner(runner)
+	service.now = func() time.Time { return time.Date(2024, 1, 10, 12, 0, 0, 0, time.UTC) }
+
+	if _, err := service.Create(context.Background(), domain.Subscription{StudentID: "student-1", PlanID: "plan-1"}); err == nil {
+		t.Fatal("expected error")
+	}
+	if runner.calls != 1 || runner.rollbacks != 1 {
+		t.Fatalf("expected one rolled back transaction, got calls=%d rollbacks=%d", runner.calls, runner.rollbacks)
+	}
+	if len(outerRepo.subscriptions) != 0 {
+		t.Fatalf("expected no write outside the transaction, got %#v", outerRepo.subscriptions)
+	}
+	if got := auditActions(audit); strings.Join(got, ",") != "subscription.create.attempt,subscription.create.failure" {
+		t.Fatalf("unexpected audit events: %v", got)
+	}
+}
+
+// Testa que Create gera as competencias da assinatura ativa.
+func TestSubscriptionServiceCreateEnsuresPeriods(t *testing.T) {
+	subRepo := &subscriptionRepoFake{}
+	planRepo := &planRepoFake{
+		plans: map[string]domain.Plan{
+			"plan-1": {ID: "plan-1", DurationDays: 30, PriceCents: 1000},
+		},
+	}
+	periods := &billingPeriodRepoFake{}
+	service := NewSubscriptionService(subRepo, planRepo, nil, nil)
+	service.SetBilling(periods, &balanceRepoFake{})
+	service.now = func() time.Time { return time.Date(2024, 1, 10, 12, 0, 0, 0, time.UTC) }
+
+	created, err := service.Create(context.Background(), domain.Subscription{StudentID: "student-1", PlanID: "plan-1"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(periods.periods) != 1 {
+		t.Fatalf("expected one billing period, got %#v", periods.periods)
+	}
+	for _, period := range periods.periods {
+		if period.SubscriptionID != created.ID || period.AmountDueCents != 1000 {
+			t.Fatalf("unexpected period: %#v", period)
+		}
+	}
+}
+
+// Testa que Cancel roda em transacao e que a falha nao grava sucesso.
+func TestSubscriptionServiceCancelRollsBackOnFailure(t *testing.T) {
+	txRepo := &subscriptionRepoFake{
+		subscriptions: map[string]domain.Subscription{
+			"sub-1": {ID: "sub-1", Status: domain.SubscriptionActive},
+		},
+		updateErr: errors.New("update failed"),
+	}
+	audit := &auditRepoFake{}
+	runner := &txRunnerFake{deps: ports.TxDependencies{Subscriptions: txRepo}, audit: audit}
+	service := NewSubscriptionService(&subscriptionRepoFake{}, nil, nil, audit)
+	service.SetTxRunner(runner)
+
+	if _, err := service.Cancel(context.Background(), "sub-1"); err == nil {
+		t.Fatal("expected error")
+	}
+	if runner.rollbacks != 1 {
+		t.Fatalf("expected rolled back transaction, got %d", runner.rollbacks)
+	}
+	if got := auditActions(audit); strings.Join(got, ",") != "subscription.cancel.attempt,subscription.cancel.failure" {
+		t.Fatalf("unexpected audit events: %v", got)
+	}
+}
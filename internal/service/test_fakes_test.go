@@ -4,6 +4,8 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"io"
+	"sort"
 	"strings"
 	"time"
 
@@ -12,8 +14,9 @@ import (
 )
 
 type auditRepoFake struct {
-	events []domain.AuditEvent
-	err    error
+	events     []domain.AuditEvent
+	err        error
+	lastFilter ports.AuditFilter
 }
 
 func (f *auditRepoFake) Record(ctx context.Context, event domain.AuditEvent) error {
@@ -21,10 +24,160 @@ func (f *auditRepoFake) Record(ctx context.Context, event domain.AuditEvent) err
 	return f.err
 }
 
+func (f *auditRepoFake) Search(ctx context.Context, filter ports.AuditFilter) ([]domain.AuditEvent, error) {
+	f.lastFilter = filter
+	var result []domain.AuditEvent
+	for _, event := range f.events {
+		if filter.ActorID != "" && event.ActorID != filter.ActorID {
+			continue
+		}
+		if filter.EntityType != "" && event.EntityType != filter.EntityType {
+			continue
+		}
+		if filter.EntityID != "" && event.EntityID != filter.EntityID {
+			continue
+		}
+		if !strings.HasPrefix(event.Action, filter.ActionPrefix) {
+			continue
+		}
+		result = append(result, event)
+	}
+	return result, f.err
+}
+
+func (f *auditRepoFake) Count(ctx context.Context, filter ports.AuditFilter) (int, error) {
+	events, err := f.Search(ctx, filter)
+	return len(events), err
+}
+
+func (f *auditRepoFake) AccessSummary(ctx context.Context, action, entityType, entityID string) ([]ports.AuditAccessSummary, error) {
+	byActor := map[string]*ports.AuditAccessSummary{}
+	var result []ports.AuditAccessSummary
+	var order []string
+	for _, event := range f.events {
+		if event.Action != action || event.EntityType != entityType || event.EntityID != entityID {
+			continue
+		}
+		summary, ok := byActor[event.ActorID]
+		if !ok {
+			summary = &ports.AuditAccessSummary{ActorID: event.ActorID, ActorRole: event.ActorRole}
+			byActor[event.ActorID] = summary
+			order = append(order, event.ActorID)
+		}
+		summary.Views++
+		if source, ok := event.Metadata["source"].(string); ok {
+			summary.Sources = append(summary.Sources, source)
+		}
+	}
+	for _, actorID := range order {
+		result = append(result, *byActor[actorID])
+	}
+	return result, f.err
+}
+
+func (f *auditRepoFake) OldestCreatedAt(ctx context.Context) (time.Time, bool, error) {
+	var oldest time.Time
+	for _, event := range f.events {
+		if event.CreatedAt.IsZero() {
+			continue
+		}
+		if oldest.IsZero() || event.CreatedAt.Before(oldest) {
+			oldest = event.CreatedAt
+		}
+	}
+	return oldest, !oldest.IsZero(), f.err
+}
+
+func (f *auditRepoFake) ListRange(ctx context.Context, from, to time.Time, after ports.AuditCursor, limit int) ([]domain.AuditEvent, error) {
+	var result []domain.AuditEvent
+	for _, event := range f.events {
+		if event.CreatedAt.Before(from) || !event.CreatedAt.Before(to) {
+			continue
+		}
+		if !after.CreatedAt.IsZero() && (event.CreatedAt.Before(after.CreatedAt) || (event.CreatedAt.Equal(after.CreatedAt) && event.ID <= after.ID)) {
+			continue
+		}
+		result = append(result, event)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if !result[i].CreatedAt.Equal(result[j].CreatedAt) {
+			return result[i].CreatedAt.Before(result[j].CreatedAt)
+		}
+		return result[i].ID < result[j].ID
+	})
+	if len(result) > limit {
+		result = result[:limit]
+	}
+	return result, f.err
+}
+
+// DeleteRange remove os eventos sem Seq pelo intervalo e os encadeados como
+// prefixo continuo, como o repositorio do banco.
+func (f *auditRepoFake) DeleteRange(ctx context.Context, from, to time.Time, maxSeq int64) (int64, error) {
+	end := maxSeq
+	for _, event := range f.events {
+		if event.Seq > 0 && !event.CreatedAt.Before(to) && event.Seq <= end {
+			end = event.Seq - 1
+		}
+	}
+	kept := f.events[:0]
+	var deleted int64
+	for _, event := range f.events {
+		legacy := event.Seq == 0 && !event.CreatedAt.Before(from) && event.CreatedAt.Before(to)
+		if legacy || (event.Seq > 0 && event.Seq <= end) {
+			deleted++
+			continue
+		}
+		kept = append(kept, event)
+	}
+	f.events = kept
+	return deleted, f.err
+}
+
+type accessDedupFake struct {
+	claimed map[string]bool
+}
+
+func (f *accessDedupFake) Claim(ctx context.Context, key string, window time.Duration) (bool, error) {
+	if f.claimed == nil {
+		f.claimed = make(map[string]bool)
+	}
+	if f.claimed[key] {
+		return false, nil
+	}
+	f.claimed[key] = true
+	return true, nil
+}
+
+type archiveStorageFake struct {
+	objects  map[string][]byte
+	err      error
+	afterPut func()
+}
+
+func (f *archiveStorageFake) Put(ctx context.Context, key string, body io.Reader, contentType string) error {
+	if f.err != nil {
+		return f.err
+	}
+	payload, err := io.ReadAll(body)
+	if err != nil {
+		return err
+	}
+	if f.objects == nil {
+		f.objects = make(map[string][]byte)
+	}
+	f.objects[key] = payload
+	if f.afterPut != nil {
+		f.afterPut()
+	}
+	return nil
+}
+
 type userRepoFake struct {
 	users     map[string]domain.User
 	createErr error
 	findErr   error
+	updateErr error
 }
 
 func (f *userRepoFake) Create(ctx context.Context, user domain.User) (domain.User, error) {
@@ -55,6 +208,380 @@ func (f *userRepoFake) FindByEmail(ctx context.Context, email string) (domain.Us
 	return user, nil
 }
 
+func (f *userRepoFake) FindByID(ctx context.Context, id string) (domain.User, error) {
+	if f.findErr != nil {
+		return domain.User{}, f.findErr
+	}
+	for _, user := range f.users {
+		if user.ID == id {
+			return user, nil
+		}
+	}
+	return domain.User{}, ports.ErrNotFound
+}
+
+func (f *userRepoFake) List(ctx context.Context) ([]domain.User, error) {
+	if f.findErr != nil {
+		return nil, f.findErr
+	}
+	users := make([]domain.User, 0, len(f.users))
+	for _, user := range f.users {
+		users = append(users, user)
+	}
+	sort.Slice(users, func(i, j int) bool { return users[i].Name < users[j].Name })
+	return users, nil
+}
+
+func (f *userRepoFake) Update(ctx context.Context, user domain.User) (domain.User, error) {
+	if f.updateErr != nil {
+		return domain.User{}, f.updateErr
+	}
+	current, err := f.FindByID(ctx, user.ID)
+	if err != nil {
+		return domain.User{}, err
+	}
+	delete(f.users, strings.ToLower(current.Email))
+	user.PasswordHash = current.PasswordHash
+	f.users[strings.ToLower(user.Email)] = user
+	return user, nil
+}
+
+func (f *userRepoFake) SetActive(ctx context.Context, id string, active bool) (domain.User, error) {
+	if f.updateErr != nil {
+		return domain.User{}, f.updateErr
+	}
+	user, err := f.FindByID(ctx, id)
+	if err != nil {
+		return domain.User{}, err
+	}
+	user.Active = active
+	f.users[strings.ToLower(user.Email)] = user
+	return user, nil
+}
+
+func (f *userRepoFake) UpdatePassword(ctx context.Context, id, passwordHash string, mustChange bool) error {
+	if f.updateErr != nil {
+		return f.updateErr
+	}
+	user, err := f.FindByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	user.PasswordHash = passwordHash
+	user.MustChangePassword = mustChange
+	f.users[strings.ToLower(user.Email)] = user
+	return nil
+}
+
+type passwordResetRepoFake struct {
+	tokens      []domain.PasswordResetToken
+	invalidated []string
+}
+
+func (f *passwordResetRepoFake) Create(ctx context.Context, token domain.PasswordResetToken) (domain.PasswordResetToken, error) {
+	token.ID = fmt.Sprintf("token-%d", len(f.tokens)+1)
+	f.tokens = append(f.tokens, token)
+	return token, nil
+}
+
+func (f *passwordResetRepoFake) Consume(ctx context.Context, tokenHash string, now time.Time) (domain.PasswordResetToken, error) {
+	for i, token := range f.tokens {
+		if token.TokenHash != tokenHash || token.UsedAt != nil || !token.ExpiresAt.After(now) {
+			continue
+		}
+		usedAt := now
+		f.tokens[i].UsedAt = &usedAt
+		return f.tokens[i], nil
+	}
+	return domain.PasswordResetToken{}, ports.ErrNotFound
+}
+
+func (f *passwordResetRepoFake) InvalidateByUser(ctx context.Context, userID string, now time.Time) error {
+	f.invalidated = append(f.invalidated, userID)
+	for i, token := range f.tokens {
+		if token.UserID == userID && token.UsedAt == nil {
+			usedAt := now
+			f.tokens[i].UsedAt = &usedAt
+		}
+	}
+	return nil
+}
+
+type apiTokenRepoFake struct {
+	tokens  []domain.APIToken
+	touched []string
+}
+
+func (f *apiTokenRepoFake) Create(ctx context.Context, token domain.APIToken) (domain.APIToken, error) {
+	token.ID = fmt.Sprintf("api-token-%d", len(f.tokens)+1)
+	f.tokens = append(f.tokens, token)
+	return token, nil
+}
+
+func (f *apiTokenRepoFake) FindByHash(ctx context.Context, tokenHash string) (domain.APIToken, error) {
+	for _, token := range f.tokens {
+		if token.TokenHash == tokenHash {
+			return token, nil
+		}
+	}
+	return domain.APIToken{}, ports.ErrNotFound
+}
+
+func (f *apiTokenRepoFake) List(ctx context.Context) ([]domain.APIToken, error) {
+	return f.tokens, nil
+}
+
+func (f *apiTokenRepoFake) Revoke(ctx context.Context, id string, revokedAt time.Time) error {
+	for i, token := range f.tokens {
+		if token.ID == id && token.RevokedAt == nil {
+			f.tokens[i].RevokedAt = &revokedAt
+			return nil
+		}
+	}
+	return ports.ErrNotFound
+}
+
+func (f *apiTokenRepoFake) Touch(ctx context.Context, id string, usedAt time.Time) error {
+	f.touched = append(f.touched, id)
+	for i, token := range f.tokens {
+		if token.ID == id {
+			f.tokens[i].LastUsedAt = &usedAt
+		}
+	}
+	return nil
+}
+
+type mfaRepoFake struct {
+	entries map[string]domain.UserMFA
+	codes   map[string]map[string]bool
+}
+
+func (f *mfaRepoFake) FindByUser(ctx context.Context, userID string) (domain.UserMFA, error) {
+	mfa, ok := f.entries[userID]
+	if !ok {
+		return domain.UserMFA{}, ports.ErrNotFound
+	}
+	return mfa, nil
+}
+
+func (f *mfaRepoFake) SaveSecret(ctx context.Context, userID, secret string) (domain.UserMFA, error) {
+	if f.entries == nil {
+		f.entries = make(map[string]domain.UserMFA)
+	}
+	mfa := domain.UserMFA{UserID: userID, Secret: secret}
+	f.entries[userID] = mfa
+	return mfa, nil
+}
+
+func (f *mfaRepoFake) Enable(ctx context.Context, userID string, enabledAt time.Time, step int64) (domain.UserMFA, error) {
+	mfa, ok := f.entries[userID]
+	if !ok || mfa.Enabled() {
+		return domain.UserMFA{}, ports.ErrNotFound
+	}
+	mfa.EnabledAt = &enabledAt
+	mfa.LastUsedStep = step
+	f.entries[userID] = mfa
+	return mfa, nil
+}
+
+func (f *mfaRepoFake) AdvanceStep(ctx context.Context, userID string, step int64) (bool, error) {
+	mfa, ok := f.entries[userID]
+	if !ok {
+		return false, ports.ErrNotFound
+	}
+	if mfa.LastUsedStep >= step {
+		return false, nil
+	}
+	mfa.LastUsedStep = step
+	f.entries[userID] = mfa
+	return true, nil
+}
+
+func (f *mfaRepoFake) Delete(ctx context.Context, userID string) error {
+	delete(f.entries, userID)
+	delete(f.codes, userID)
+	return nil
+}
+
+func (f *mfaRepoFake) ReplaceRecoveryCodes(ctx context.Context, userID string, codeHashes []string) error {
+	if f.codes == nil {
+		f.codes = make(map[string]map[string]bool)
+	}
+	codes := make(map[string]bool, len(codeHashes))
+	for _, hash := range codeHashes {
+		codes[hash] = false
+	}
+	f.codes[userID] = codes
+	return nil
+}
+
+func (f *mfaRepoFake) ConsumeRecoveryCode(ctx context.Context, userID, codeHash string, usedAt time.Time) (bool, error) {
+	used, ok := f.codes[userID][codeHash]
+	if !ok || used {
+		return false, nil
+	}
+	f.codes[userID][codeHash] = true
+	return true, nil
+}
+
+func (f *mfaRepoFake) CountRecoveryCodes(ctx context.Context, userID string) (int, error) {
+	count := 0
+	for _, used := range f.codes[userID] {
+		if !used {
+			count++
+		}
+	}
+	return count, nil
+}
+
+type loginChallengeStoreFake struct {
+	challenges map[string]ports.LoginChallenge
+}
+
+func (f *loginChallengeStoreFake) Create(ctx context.Context, challenge ports.LoginChallenge) (string, error) {
+	if f.challenges == nil {
+		f.challenges = make(map[string]ports.LoginChallenge)
+	}
+	token := fmt.Sprintf("challenge-%d", len(f.challenges)+1)
+	f.challenges[token] = challenge
+	return token, nil
+}
+
+func (f *loginChallengeStoreFake) Get(ctx context.Context, token string) (ports.LoginChallenge, error) {
+	challenge, ok := f.challenges[token]
+	if !ok {
+		return ports.LoginChallenge{}, ports.ErrNotFound
+	}
+	return challenge, nil
+}
+
+func (f *loginChallengeStoreFake) RegisterFailure(ctx context.Context, token string) (int, error) {
+	challenge, ok := f.challenges[token]
+	if !ok {
+		return 0, ports.ErrNotFound
+	}
+	challenge.Failures++
+	f.challenges[token] = challenge
+	return challenge.Failures, nil
+}
+
+func (f *loginChallengeStoreFake) Delete(ctx context.Context, token string) error {
+	delete(f.challenges, token)
+	return nil
+}
+
+type notifierFake struct {
+	notifications []ports.Notification
+	err           error
+}
+
+func (f *notifierFake) Notify(ctx context.Context, notification ports.Notification) error {
+	f.notifications = append(f.notifications, notification)
+	return f.err
+}
+
+type sessionStoreFake struct {
+	sessions     []ports.Session
+	tokens       map[string]ports.Session
+	deletedUsers []string
+	deletedIDs   []string
+	deleteErr    error
+}
+
+func (f *sessionStoreFake) Create(ctx context.Context, session ports.Session) (string, error) {
+	if f.tokens == nil {
+		f.tokens = make(map[string]ports.Session)
+	}
+	token := fmt.Sprintf("token-%d", len(f.tokens)+1)
+	f.tokens[token] = session
+	return token, nil
+}
+
+func (f *sessionStoreFake) Get(ctx context.Context, token string) (ports.Session, error) {
+	session, ok := f.tokens[token]
+	if !ok {
+		return ports.Session{}, ports.ErrNotFound
+	}
+	return session, nil
+}
+
+func (f *sessionStoreFake) Delete(ctx context.Context, token string) error {
+	delete(f.tokens, token)
+	return nil
+}
+
+func (f *sessionStoreFake) DeleteByUser(ctx context.Context, userID string) error {
+	f.deletedUsers = append(f.deletedUsers, userID)
+	if f.deleteErr != nil {
+		return f.deleteErr
+	}
+	for token, session := range f.tokens {
+		if session.UserID == userID {
+			delete(f.tokens, token)
+		}
+	}
+	return nil
+}
+
+func (f *sessionStoreFake) Touch(ctx context.Context, token string, seenAt, expiresAt time.Time) error {
+	return nil
+}
+
+func (f *sessionStoreFake) ListByUser(ctx context.Context, userID string) ([]ports.Session, error) {
+	return f.sessions, nil
+}
+
+func (f *sessionStoreFake) DeleteByID(ctx context.Context, userID, sessionID string) error {
+	for _, session := range f.sessions {
+		if session.UserID == userID && session.ID == sessionID {
+			f.deletedIDs = append(f.deletedIDs, sessionID)
+			return f.deleteErr
+		}
+	}
+	return ports.ErrNotFound
+}
+
+type loginAttemptStoreFake struct {
+	attempts map[string]ports.LoginAttempts
+	memories []time.Duration
+}
+
+func (f *loginAttemptStoreFake) Get(ctx context.Context, key string) (ports.LoginAttempts, error) {
+	attempts := f.attempts[key]
+	if !attempts.LockedUntil.After(time.Now()) {
+		attempts.LockedUntil = time.Time{}
+	}
+	return attempts, nil
+}
+
+func (f *loginAttemptStoreFake) RegisterFailure(ctx context.Context, key string, window time.Duration) (int, error) {
+	if f.attempts == nil {
+		f.attempts = make(map[string]ports.LoginAttempts)
+	}
+	attempts := f.attempts[key]
+	attempts.Failures++
+	f.attempts[key] = attempts
+	return attempts.Failures, nil
+}
+
+func (f *loginAttemptStoreFake) Lock(ctx context.Context, key string, until time.Time, memory time.Duration) error {
+	if f.attempts == nil {
+		f.attempts = make(map[string]ports.LoginAttempts)
+	}
+	attempts := f.attempts[key]
+	attempts.Failures = 0
+	attempts.Lockouts++
+	attempts.LockedUntil = until
+	f.attempts[key] = attempts
+	f.memories = append(f.memories, memory)
+	return nil
+}
+
+func (f *loginAttemptStoreFake) Reset(ctx context.Context, key string) error {
+	delete(f.attempts, key)
+	return nil
+}
+
 type studentRepoFake struct {
 	students   map[string]domain.Student
 	createErr  error
@@ -92,6 +619,15 @@ func (f *studentRepoFake) Update(ctx context.Context, student domain.Student) (d
 	return student, nil
 }
 
+func (f *studentRepoFake) FindIDByPhotoObjectKey(ctx context.Context, objectKey string) (string, error) {
+	for id, student := range f.students {
+		if student.PhotoObjectKey == objectKey {
+			return id, nil
+		}
+	}
+	return "", ports.ErrNotFound
+}
+
 func (f *studentRepoFake) FindByID(ctx context.Context, id string) (domain.Student, error) {
 	if f.findErr != nil {
 		return domain.Student{}, f.findErr
@@ -137,6 +673,18 @@ func (f *studentRepoFake) Search(ctx context.Context, filter ports.StudentFilter
 	return results, nil
 }
 
+func (f *studentRepoFake) SearchPage(ctx context.Context, filter ports.StudentFilter, page ports.PageRequest) ([]domain.Student, error) {
+	filter.Limit = 0
+	filter.Offset = 0
+	students, err := f.Search(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	return fakePage(students, page, func(student domain.Student) ports.PageCursor {
+		return ports.PageCursor{CreatedAt: student.CreatedAt, ID: student.ID}
+	}), nil
+}
+
 func (f *studentRepoFake) Count(ctx context.Context, filter ports.StudentFilter) (int, error) {
 	countFilter := filter
 	countFilter.Limit = 0
@@ -214,6 +762,78 @@ func (f *planRepoFake) ListActive(ctx context.Context) ([]domain.Plan, error) {
 	return results, nil
 }
 
+func (f *planRepoFake) ListActivePage(ctx context.Context, page ports.PageRequest) ([]domain.Plan, error) {
+	plans, err := f.ListActive(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return fakePage(plans, page, func(plan domain.Plan) ports.PageCursor {
+		return ports.PageCursor{CreatedAt: plan.CreatedAt, ID: plan.ID}
+	}), nil
+}
+
+type couponRepoFake struct {
+	coupons   map[string]domain.Coupon
+	createErr error
+}
+
+func (f *couponRepoFake) Create(ctx context.Context, coupon domain.Coupon) (domain.Coupon, error) {
+	if f.createErr != nil {
+		return domain.Coupon{}, f.createErr
+	}
+	if f.coupons == nil {
+		f.coupons = make(map[string]domain.Coupon)
+	}
+	for _, existing := range f.coupons {
+		if strings.EqualFold(existing.Code, coupon.Code) {
+			return domain.Coupon{}, ports.ErrConflict
+		}
+	}
+	if coupon.ID == "" {
+		coupon.ID = fmt.Sprintf("coupon-%d", len(f.coupons)+1)
+	}
+	f.coupons[coupon.ID] = coupon
+	return coupon, nil
+}
+
+func (f *couponRepoFake) SetActive(ctx context.Context, id string, active bool) (domain.Coupon, error) {
+	coupon, ok := f.coupons[id]
+	if !ok {
+		return domain.Coupon{}, ports.ErrNotFound
+	}
+	coupon.Active = active
+	f.coupons[id] = coupon
+	return coupon, nil
+}
+
+func (f *couponRepoFake) FindByID(ctx context.Context, id string) (domain.Coupon, error) {
+	coupon, ok := f.coupons[id]
+	if !ok {
+		return domain.Coupon{}, ports.ErrNotFound
+	}
+	return coupon, nil
+}
+
+func (f *couponRepoFake) FindByCode(ctx context.Context, code string) (domain.Coupon, error) {
+	for _, coupon := range f.coupons {
+		if strings.EqualFold(coupon.Code, code) {
+			return coupon, nil
+		}
+	}
+	return domain.Coupon{}, ports.ErrNotFound
+}
+
+func (f *couponRepoFake) List(ctx context.Context) ([]domain.Coupon, error) {
+	results := make([]domain.Coupon, 0, len(f.coupons))
+	for _, coupon := range f.coupons {
+		results = append(results, coupon)
+	}
+	sort.Slice(results, func(i, j int) bool {
+		return results[i].Code < results[j].Code
+	})
+	return results, nil
+}
+
 type subscriptionRepoFake struct {
 	subscriptions  map[string]domain.Subscription
 	createErr      error
@@ -276,6 +896,14 @@ func (f *subscriptionRepoFake) ListByStudent(ctx context.Context, studentID stri
 	}), nil
 }
 
+func (f *subscriptionRepoFake) ListByStudentPage(ctx context.Context, studentID string, page ports.PageRequest) ([]domain.Subscription, error) {
+	subscriptions, err := f.ListByStudent(ctx, studentID)
+	if err != nil {
+		return nil, err
+	}
+	return fakePage(subscriptions, page, fakeSubscriptionCursor), nil
+}
+
 func (f *subscriptionRepoFake) ListByPlan(ctx context.Context, planID string) ([]domain.Subscription, error) {
 	if f.listPlanErr != nil {
 		return nil, f.listPlanErr
@@ -294,6 +922,14 @@ func (f *subscriptionRepoFake) ListDueBetween(ctx context.Context, start, end ti
 	}), nil
 }
 
+func (f *subscriptionRepoFake) ListDueBetweenPage(ctx context.Context, start, end time.Time, page ports.PageRequest) ([]domain.Subscription, error) {
+	subscriptions, err := f.ListDueBetween(ctx, start, end)
+	if err != nil {
+		return nil, err
+	}
+	return fakePage(subscriptions, page, fakeSubscriptionCursor), nil
+}
+
 func (f *subscriptionRepoFake) ListAutoRenew(ctx context.Context) ([]domain.Subscription, error) {
 	if f.listAutoErr != nil {
 		return nil, f.listAutoErr
@@ -303,6 +939,12 @@ func (f *subscriptionRepoFake) ListAutoRenew(ctx context.Context) ([]domain.Subs
 	}), nil
 }
 
+func (f *subscriptionRepoFake) ListWithOpenPeriods(ctx context.Context) ([]domain.Subscription, error) {
+	return f.filter(func(sub domain.Subscription) bool {
+		return sub.Status == domain.SubscriptionActive
+	}), nil
+}
+
 func (f *subscriptionRepoFake) filter(fn func(domain.Subscription) bool) []domain.Subscription {
 	if f.subscriptions == nil {
 		return nil
@@ -361,6 +1003,16 @@ func (f *billingPeriodRepoFake) ListBySubscription(ctx context.Context, subscrip
 	}), nil
 }
 
+func (f *billingPeriodRepoFake) ListBySubscriptionPage(ctx context.Context, subscriptionID string, page ports.PageRequest) ([]domain.BillingPeriod, error) {
+	periods, err := f.ListBySubscription(ctx, subscriptionID)
+	if err != nil {
+		return nil, err
+	}
+	return fakePage(periods, page, func(period domain.BillingPeriod) ports.PageCursor {
+		return ports.PageCursor{CreatedAt: period.CreatedAt, ID: period.ID}
+	}), nil
+}
+
 func (f *billingPeriodRepoFake) ListOpenBySubscription(ctx context.Context, subscriptionID string) ([]domain.BillingPeriod, error) {
 	if f.openErr != nil {
 		return nil, f.openErr
@@ -387,6 +1039,9 @@ func (f *billingPeriodRepoFake) filter(fn func(domain.BillingPeriod) bool) []dom
 			results = append(results, period)
 		}
 	}
+	sort.Slice(results, func(i, j int) bool {
+		return results[i].PeriodStart.Before(results[j].PeriodStart)
+	})
 	return results
 }
 
@@ -395,6 +1050,7 @@ type paymentAllocationRepoFake struct {
 	createErr   error
 	listErr     error
 	deleteErr   error
+	created     int
 }
 
 func (f *paymentAllocationRepoFake) Create(ctx context.Context, allocation domain.PaymentAllocation) error {
@@ -404,10 +1060,41 @@ func (f *paymentAllocationRepoFake) Create(ctx context.Context, allocation domai
 	if f.allocations == nil {
 		f.allocations = make(map[string][]domain.PaymentAllocation)
 	}
+	f.created++
+	if allocation.CreatedAt.IsZero() {
+		allocation.CreatedAt = time.Date(2024, 1, 1, 0, 0, f.created, 0, time.UTC)
+	}
 	f.allocations[allocation.PaymentID] = append(f.allocations[allocation.PaymentID], allocation)
 	return nil
 }
 
+func (f *paymentAllocationRepoFake) Update(ctx context.Context, allocation domain.PaymentAllocation) error {
+	items := f.allocations[allocation.PaymentID]
+	for i := range items {
+		if items[i].BillingPeriodID == allocation.BillingPeriodID {
+			items[i].AmountCents = allocation.AmountCents
+			return nil
+		}
+	}
+	return ports.ErrNotFound
+}
+
+func (f *paymentAllocationRepoFake) ListByBillingPeriod(ctx context.Context, periodID string) ([]domain.PaymentAllocation, error) {
+	if f.listErr != nil {
+		return nil, f.listErr
+	}
+	var result []domain.PaymentAllocation
+	for _, items := range f.allocations {
+		for _, allocation := range items {
+			if allocation.BillingPeriodID == periodID {
+				result = append(result, allocation)
+			}
+		}
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].CreatedAt.Before(result[j].CreatedAt) })
+	return result, nil
+}
+
 func (f *paymentAllocationRepoFake) ListByPayment(ctx context.Context, paymentID string) ([]domain.PaymentAllocation, error) {
 	if f.listErr != nil {
 		return nil, f.listErr
@@ -426,6 +1113,27 @@ func (f *paymentAllocationRepoFake) DeleteByPayment(ctx context.Context, payment
 	return nil
 }
 
+type paymentCreditTransferRepoFake struct {
+	transfers map[string][]domain.PaymentCreditTransfer
+}
+
+func (f *paymentCreditTransferRepoFake) Create(ctx context.Context, transfer domain.PaymentCreditTransfer) error {
+	if f.transfers == nil {
+		f.transfers = make(map[string][]domain.PaymentCreditTransfer)
+	}
+	f.transfers[transfer.PaymentID] = append(f.transfers[transfer.PaymentID], transfer)
+	return nil
+}
+
+func (f *paymentCreditTransferRepoFake) ListByPayment(ctx context.Context, paymentID string) ([]domain.PaymentCreditTransfer, error) {
+	return append([]domain.PaymentCreditTransfer(nil), f.transfers[paymentID]...), nil
+}
+
+func (f *paymentCreditTransferRepoFake) DeleteByPayment(ctx context.Context, paymentID string) error {
+	delete(f.transfers, paymentID)
+	return nil
+}
+
 type balanceRepoFake struct {
 	balances map[string]domain.SubscriptionBalance
 	getErr   error
@@ -472,6 +1180,42 @@ func (f *balanceRepoFake) Add(ctx context.Context, subscriptionID string, delta
 	return balance, nil
 }
 
+type freezeRepoFake struct {
+	freezes   []domain.SubscriptionFreeze
+	createErr error
+}
+
+func (f *freezeRepoFake) Create(ctx context.Context, freeze domain.SubscriptionFreeze) (domain.SubscriptionFreeze, error) {
+	if f.createErr != nil {
+		return domain.SubscriptionFreeze{}, f.createErr
+	}
+	if freeze.ID == "" {
+		freeze.ID = fmt.Sprintf("freeze-%d", len(f.freezes)+1)
+	}
+	f.freezes = append(f.freezes, freeze)
+	return freeze, nil
+}
+
+func (f *freezeRepoFake) ListBySubscription(ctx context.Context, subscriptionID string) ([]domain.SubscriptionFreeze, error) {
+	results := make([]domain.SubscriptionFreeze, 0, len(f.freezes))
+	for _, freeze := range f.freezes {
+		if freeze.SubscriptionID == subscriptionID {
+			results = append(results, freeze)
+		}
+	}
+	return results, nil
+}
+
+func (f *freezeRepoFake) ListBySubscriptionPage(ctx context.Context, subscriptionID string, page ports.PageRequest) ([]domain.SubscriptionFreeze, error) {
+	freezes, err := f.ListBySubscription(ctx, subscriptionID)
+	if err != nil {
+		return nil, err
+	}
+	return fakePage(freezes, page, func(freeze domain.SubscriptionFreeze) ports.PageCursor {
+		return ports.PageCursor{CreatedAt: freeze.CreatedAt, ID: freeze.ID}
+	}), nil
+}
+
 type paymentRepoFake struct {
 	payments      map[string]domain.Payment
 	byIdempotency map[string]string
@@ -565,6 +1309,14 @@ func (f *paymentRepoFake) ListBySubscription(ctx context.Context, subscriptionID
 	return results, nil
 }
 
+func (f *paymentRepoFake) ListBySubscriptionPage(ctx context.Context, subscriptionID string, page ports.PageRequest) ([]domain.Payment, error) {
+	payments, err := f.ListBySubscription(ctx, subscriptionID)
+	if err != nil {
+		return nil, err
+	}
+	return fakePage(payments, page, fakePaymentCursor), nil
+}
+
 func (f *paymentRepoFake) ListByPeriod(ctx context.Context, start, end time.Time) ([]domain.Payment, error) {
 	if f.listPeriodErr != nil {
 		return nil, f.listPeriodErr
@@ -584,9 +1336,18 @@ func (f *paymentRepoFake) ListByPeriod(ctx context.Context, start, end time.Time
 	return results, nil
 }
 
+func (f *paymentRepoFake) ListByPeriodPage(ctx context.Context, start, end time.Time, page ports.PageRequest) ([]domain.Payment, error) {
+	payments, err := f.ListByPeriod(ctx, start, end)
+	if err != nil {
+		return nil, err
+	}
+	return fakePage(payments, page, fakePaymentCursor), nil
+}
+
 type reportRepoFake struct {
 	revenue        ports.RevenueSummary
 	revenueErr     error
+	revenueStarts  []time.Time
 	statuses       []ports.StudentStatusSummary
 	statusesErr    error
 	delinquents    []ports.DelinquentSubscription
@@ -596,6 +1357,7 @@ type reportRepoFake struct {
 }
 
 func (f *reportRepoFake) RevenueByPeriod(ctx context.Context, start, end time.Time) (ports.RevenueSummary, error) {
+	f.revenueStarts = append(f.revenueStarts, start)
 	return f.revenue, f.revenueErr
 }
 
@@ -611,14 +1373,111 @@ func (f *reportRepoFake) UpcomingDue(ctx context.Context, start, end time.Time)
 	return f.upcoming, f.upcomingErr
 }
 
+type dashboardRepoFake struct {
+	activeStudents   int64
+	overdue          int64
+	outstanding      int64
+	outstandingStart time.Time
+	outstandingEnd   time.Time
+	err              error
+}
+
+func (f *dashboardRepoFake) CountActiveStudents(ctx context.Context) (int64, error) {
+	return f.activeStudents, f.err
+}
+
+func (f *dashboardRepoFake) CountOverdueSubscriptions(ctx context.Context) (int64, error) {
+	return f.overdue, f.err
+}
+
+func (f *dashboardRepoFake) OutstandingDue(ctx context.Context, start, end time.Time) (int64, error) {
+	f.outstandingStart = start
+	f.outstandingEnd = end
+	return f.outstanding, f.err
+}
+
 type paymentTxRunnerFake struct {
-	deps ports.PaymentDependencies
-	err  error
+	deps      ports.PaymentDependencies
+	err       error
+	rollbacks int
 }
 
 func (f *paymentTxRunnerFake) RunSerializable(ctx context.Context, fn func(context.Context, ports.PaymentDependencies) error) error {
 	if f.err != nil {
 		return f.err
 	}
-	return fn(ctx, f.deps)
+	if err := fn(ctx, f.deps); err != nil {
+		f.rollbacks++
+		return err
+	}
+	return nil
+}
+
+// txRunnerFake simula a transacao: os eventos de auditoria gravados dentro de
+// fn so chegam a audit quando fn termina sem erro.
+type txRunnerFake struct {
+	deps      ports.TxDependencies
+	audit     *auditRepoFake
+	opts      ports.TxOptions
+	calls     int
+	rollbacks int
+}
+
+func (f *txRunnerFake) RunInTx(ctx context.Context, opts ports.TxOptions, fn func(context.Context, ports.TxDependencies) error) error {
+	f.calls++
+	f.opts = opts
+	pending := &auditRepoFake{}
+	deps := f.deps
+	deps.Audit = pending
+	if err := fn(ctx, deps); err != nil {
+		f.rollbacks++
+		return err
+	}
+	if f.audit != nil {
+		f.audit.events = append(f.audit.events, pending.events...)
+	}
+	return nil
+}
+
+func auditActions(repo *auditRepoFake) []string {
+	actions := make([]string, 0, len(repo.events))
+	for _, event := range repo.events {
+		actions = append(actions, event.Action)
+	}
+	return actions
+}
+
+// fakePage simula a paginacao do banco: ordena por CreatedAt e ID e devolve
+// ate page.Limit itens depois de page.After.
+func fakePage[T any](items []T, page ports.PageRequest, cursor func(T) ports.PageCursor) []T {
+	sorted := append([]T(nil), items...)
+	sort.Slice(sorted, func(i, j int) bool {
+		return fakeCursorBefore(cursor(sorted[i]), cursor(sorted[j]))
+	})
+	result := make([]T, 0, len(sorted))
+	for _, item := range sorted {
+		if page.After != (ports.PageCursor{}) && !fakeCursorBefore(page.After, cursor(item)) {
+			continue
+		}
+		if len(result) == page.Limit {
+			break
+		}
+		result = append(result, item)
+	}
+	return result
+}
+
+func fakeCursorBefore(a, b ports.PageCursor) bool {
+	if !a.CreatedAt.Equal(b.CreatedAt) {
+		return a.CreatedAt.Before(b.CreatedAt)
+	}
+	return a.ID < b.ID
+}
+
+func fakeSubscriptionCursor(subscription domain.Subscription) ports.PageCursor {
+	return ports.PageCursor{CreatedAt: subscription.CreatedAt, ID: subscription.ID}
+}
+
+func fakePaymentCursor(payment domain.Payment) ports.PageCursor {
+	return ports.PageCursor{CreatedAt: payment.CreatedAt, ID: payment.ID}
 }
@@ -0,0 +1,68 @@
+package service
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+// Testa os codigos TOTP contra os vetores da RFC 6238 (SHA1, ultimos 6 digitos).
+func TestTOTPCodeRFCVectors(t *testing.T) {
+	secret := totpEncoding.EncodeToString([]byte("12345678901234567890"))
+	cases := []struct {
+		unix int64
+		code string
+	}{
+		{unix: 59, code: "287082"},
+		{unix: 1111111109, code: "081804"},
+		{unix: 1111111111, code: "050471"},
+		{unix: 1234567890, code: "005924"},
+		{unix: 2000000000, code: "279037"},
+	}
+
+	for _, tc := range cases {
+		got, err := totpCode(secret, totpStep(time.Unix(tc.unix, 0)))
+		if err != nil {
+			t.Fatalf("totp code: %v", err)
+		}
+		if got != tc.code {
+			t.Fatalf("at %d expected %s, got %s", tc.unix, tc.code, got)
+		}
+	}
+}
+
+// Testa a tolerancia de um intervalo na validacao do codigo.
+func TestVerifyTOTPSkew(t *testing.T) {
+	secret, err := generateTOTPSecret()
+	if err != nil {
+		t.Fatalf("generate secret: %v", err)
+	}
+	now := time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC)
+	step := totpStep(now)
+
+	previous, _ := totpCode(secret, step-1)
+	if got, ok := verifyTOTP(secret, previous, now); !ok || got != step-1 {
+		t.Fatalf("expected previous step accepted, got %d %v", got, ok)
+	}
+
+	old, _ := totpCode(secret, step-3)
+	if _, ok := verifyTOTP(secret, old, now); ok {
+		t.Fatal("expected old code to be rejected")
+	}
+	if _, ok := verifyTOTP(secret, "12345", now); ok {
+		t.Fatal("expected short code to be rejected")
+	}
+}
+
+// Testa o formato da URI de provisionamento.
+func TestTOTPProvisioningURI(t *testing.T) {
+	uri := totpProvisioningURI("Jaiu", "admin@example.com", "ABC")
+	if !strings.HasPrefix(uri, "otpauth://totp/Jaiu:admin@example.com?") {
+		t.Fatalf("unexpected uri prefix: %s", uri)
+	}
+	for _, part := range []string{"secret=ABC", "issuer=Jaiu", "digits=6", "period=30"} {
+		if !strings.Contains(uri, part) {
+			t.Fatalf("expected %q in %s", part, uri)
+		}
+	}
+}
@@ -0,0 +1,265 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/PabloPavan/jaiu/internal/auditctx"
+	"github.com/PabloPavan/jaiu/internal/domain"
+	"github.com/PabloPavan/jaiu/internal/ports"
+)
+
+const minPasswordLength = 8
+
+type UserService struct {
+	repo     ports.UserRepository
+	sessions ports.SessionStore
+	audit    ports.AuditRepository
+	txRunner ports.TxRunner
+	now      func() time.Time
+}
+
+func NewUserService(repo ports.UserRepository, sessions ports.SessionStore, audit ports.AuditRepository) *UserService {
+	return &UserService{repo: repo, sessions: sessions, audit: audit, now: time.Now}
+}
+
+// SetTxRunner faz a verificacao do ultimo administrador e a gravacao
+// acontecerem na mesma transacao serializavel.
+func (s *UserService) SetTxRunner(runner ports.TxRunner) {
+	s.txRunner = runner
+}
+
+func (s *UserService) List(ctx context.Context) ([]domain.User, error) {
+	return s.repo.List(ctx)
+}
+
+func (s *UserService) FindByID(ctx context.Context, userID string) (domain.User, error) {
+	return s.repo.FindByID(ctx, userID)
+}
+
+func (s *UserService) Create(ctx context.Context, user domain.User, password string) (domain.User, error) {
+	metadata := userAuditMetadata(user)
+	recordAuditAttempt(ctx, s.audit, "user.create", "user", "", metadata)
+
+	if err := validateUser(user); err != nil {
+		recordAuditFailure(ctx, s.audit, "user.create", "user", "", metadata, err)
+		return domain.User{}, err
+	}
+	if err := validatePassword(password); err != nil {
+		recordAuditFailure(ctx, s.audit, "user.create", "user", "", metadata, err)
+		return domain.User{}, err
+	}
+
+	hash, err := HashPassword(password)
+	if err != nil {
+		recordAuditFailure(ctx, s.audit, "user.create", "user", "", metadata, err)
+		return domain.User{}, err
+	}
+
+	now := s.now()
+	user.PasswordHash = hash
+	user.CreatedAt = now
+	user.UpdatedAt = now
+
+	created, err := s.repo.Create(ctx, user)
+	if err != nil {
+		recordAuditFailure(ctx, s.audit, "user.create", "user", "", metadata, err)
+		return domain.User{}, err
+	}
+
+	recordAuditSuccess(ctx, s.audit, "user.create", "user", created.ID, metadata)
+	return created, nil
+}
+
+func (s *UserService) Update(ctx context.Context, user domain.User) (domain.User, error) {
+	metadata := userAuditMetadata(user)
+	recordAuditAttempt(ctx, s.audit, "user.update", "user", user.ID, metadata)
+
+	if err := validateUser(user); err != nil {
+		recordAuditFailure(ctx, s.audit, "user.update", "user", user.ID, metadata, err)
+		return domain.User{}, err
+	}
+
+	var current, updated domain.User
+	err := s.inTx(ctx, func(ctx context.Context, tx *UserService) error {
+		var err error
+		current, err = tx.repo.FindByID(ctx, user.ID)
+		if err != nil {
+			return err
+		}
+		if err := tx.ensureAdminAccess(ctx, current, user); err != nil {
+			return err
+		}
+
+		user.UpdatedAt = tx.now()
+		updated, err = tx.repo.Update(ctx, user)
+		if err != nil {
+			return err
+		}
+		recordAuditSuccess(ctx, tx.audit, "user.update", "user", updated.ID, withAuditChanges(metadata, userAuditFields(current), userAuditFields(updated)))
+		return nil
+	})
+	if err != nil {
+		recordAuditFailure(ctx, s.audit, "user.update", "user", user.ID, metadata, err)
+		return domain.User{}, err
+	}
+
+	if !updated.Active || updated.Role != current.Role || (updated.MustChangePassword && !current.MustChangePassword) {
+		if err := s.revokeSessions(ctx, updated.ID); err != nil {
+			recordAuditFailure(ctx, s.audit, "user.update", "user", updated.ID, metadata, err)
+			return updated, err
+		}
+	}
+	return updated, nil
+}
+
+func (s *UserService) SetActive(ctx context.Context, userID string, active bool) (domain.User, error) {
+	action := "user.deactivate"
+	if active {
+		action = "user.activate"
+	}
+	metadata := map[string]any{
+		"active": active,
+	}
+	recordAuditAttempt(ctx, s.audit, action, "user", userID, metadata)
+
+	var updated domain.User
+	err := s.inTx(ctx, func(ctx context.Context, tx *UserService) error {
+		current, err := tx.repo.FindByID(ctx, userID)
+		if err != nil {
+			return err
+		}
+		next := current
+		next.Active = active
+		if err := tx.ensureAdminAccess(ctx, current, next); err != nil {
+			return err
+		}
+
+		updated, err = tx.repo.SetActive(ctx, userID, active)
+		if err != nil {
+			return err
+		}
+		recordAuditSuccess(ctx, tx.audit, action, "user", updated.ID, metadata)
+		return nil
+	})
+	if err != nil {
+		recordAuditFailure(ctx, s.audit, action, "user", userID, metadata, err)
+		return domain.User{}, err
+	}
+
+	if !active {
+		if err := s.revokeSessions(ctx, updated.ID); err != nil {
+			recordAuditFailure(ctx, s.audit, action, "user", updated.ID, metadata, err)
+			return updated, err
+		}
+	}
+	return updated, nil
+}
+
+// ResetPassword define uma senha escolhida pelo administrador. Como a senha e
+// conhecida por outra pessoa, o usuario deve troca-la no proximo login.
+func (s *UserService) ResetPassword(ctx context.Context, userID, password string) error {
+	recordAuditAttempt(ctx, s.audit, "user.password_reset", "user", userID, nil)
+
+	if err := validatePassword(password); err != nil {
+		recordAuditFailure(ctx, s.audit, "user.password_reset", "user", userID, nil, err)
+		return err
+	}
+
+	hash, err := HashPassword(password)
+	if err != nil {
+		recordAuditFailure(ctx, s.audit, "user.password_reset", "user", userID, nil, err)
+		return err
+	}
+
+	if err := s.repo.UpdatePassword(ctx, userID, hash, true); err != nil {
+		recordAuditFailure(ctx, s.audit, "user.password_reset", "user", userID, nil, err)
+		return err
+	}
+
+	if err := s.revokeSessions(ctx, userID); err != nil {
+		recordAuditFailure(ctx, s.audit, "user.password_reset", "user", userID, nil, err)
+		return err
+	}
+
+	recordAuditSuccess(ctx, s.audit, "user.password_reset", "user", userID, nil)
+	return nil
+}
+
+// ensureAdminAccess impede que uma alteracao tire o acesso de administrador de
+// quem a faz ou deixe o sistema sem administradores ativos, o que bloquearia a
+// tela de usuarios para todos. Deve rodar dentro de inTx: a transacao
+// serializavel faz uma de duas remocoes concorrentes falhar e ser repetida, e
+// na repeticao ela ja enxerga a outra.
+func (s *UserService) ensureAdminAccess(ctx context.Context, current, next domain.User) error {
+	if !current.IsActiveAdmin() || next.IsActiveAdmin() {
+		return nil
+	}
+	if current.ID == auditctx.FromContext(ctx).Actor.ID {
+		return domain.ErrSelfLockout
+	}
+
+	users, err := s.repo.List(ctx)
+	if err != nil {
+		return err
+	}
+	for _, user := range users {
+		if user.ID != current.ID && user.IsActiveAdmin() {
+			return nil
+		}
+	}
+	return domain.ErrLastAdmin
+}
+
+// inTx executa fn com os repositorios de uma transacao serializavel; sem
+// txRunner, fn usa o proprio servico.
+func (s *UserService) inTx(ctx context.Context, fn func(context.Context, *UserService) error) error {
+	if s.txRunner == nil {
+		return fn(ctx, s)
+	}
+	return s.txRunner.RunInTx(ctx, ports.TxOptions{Isolation: ports.TxSerializable}, func(ctx context.Context, deps ports.TxDependencies) error {
+		return fn(ctx, &UserService{
+			repo:     deps.Users,
+			sessions: s.sessions,
+			audit:    deps.Audit,
+			now:      s.now,
+		})
+	})
+}
+
+func (s *UserService) revokeSessions(ctx context.Context, userID string) error {
+	if s.sessions == nil {
+		return nil
+	}
+	return s.sessions.DeleteByUser(ctx, userID)
+}
+
+func validateUser(user domain.User) error {
+	if strings.TrimSpace(user.Name) == "" {
+		return validationError("nome do usuario e obrigatorio")
+	}
+	if !strings.Contains(user.Email, "@") {
+		return validationError("email do usuario invalido")
+	}
+	if !user.Role.IsValid() {
+		return validationError("papel do usuario invalido")
+	}
+	return nil
+}
+
+func validatePassword(password string) error {
+	if len(password) < minPasswordLength {
+		return validationError("senha deve ter pelo menos 8 caracteres")
+	}
+	return nil
+}
+
+func userAuditMetadata(user domain.User) map[string]any {
+	return map[string]any{
+		"email":                user.Email,
+		"role":                 string(user.Role),
+		"active":               user.Active,
+		"must_change_password": user.MustChangePassword,
+	}
+}
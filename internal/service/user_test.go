@@ -0,0 +1,228 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/PabloPavan/jaiu/internal/auditctx"
+	"github.com/PabloPavan/jaiu/internal/domain"
+	"github.com/PabloPavan/jaiu/internal/ports"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// Testa Create validando dados, gerando hash e auditando.
+func TestUserServiceCreate(t *testing.T) {
+	repo := &userRepoFake{}
+	audit := &auditRepoFake{}
+	service := NewUserService(repo, nil, audit)
+
+	created, err := service.Create(context.Background(), domain.User{Name: "Ana", Email: "ana@example.com", Role: domain.RoleOperator, Active: true}, "segredo123")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if created.ID == "" {
+		t.Fatal("expected created user id")
+	}
+	if err := bcrypt.CompareHashAndPassword([]byte(created.PasswordHash), []byte("segredo123")); err != nil {
+		t.Fatalf("expected hashed password, got %v", err)
+	}
+	if len(audit.events) != 2 || audit.events[1].Action != "user.create.success" {
+		t.Fatalf("expected attempt and success audit events, got %#v", audit.events)
+	}
+	for _, event := range audit.events {
+		if _, ok := event.Metadata["password"]; ok {
+			t.Fatal("expected password to be absent from audit metadata")
+		}
+	}
+}
+
+// Testa validacoes do Create.
+func TestUserServiceCreateValidation(t *testing.T) {
+	tests := []struct {
+		name     string
+		user     domain.User
+		password string
+	}{
+		{"missing-name", domain.User{Email: "a@example.com", Role: domain.RoleAdmin}, "segredo123"},
+		{"invalid-email", domain.User{Name: "A", Email: "invalid", Role: domain.RoleAdmin}, "segredo123"},
+		{"invalid-role", domain.User{Name: "A", Email: "a@example.com", Role: "guest"}, "segredo123"},
+		{"short-password", domain.User{Name: "A", Email: "a@example.com", Role: domain.RoleAdmin}, "123"},
+	}
+
+	for _, tt := range tests {
+		service := NewUserService(&userRepoFake{}, nil, nil)
+		if _, err := service.Create(context.Background(), tt.user, tt.password); err == nil {
+			t.Fatalf("%s: expected error", tt.name)
+		}
+	}
+}
+
+// Testa que desativar usuario remove as sessoes ativas.
+func TestUserServiceSetActiveRevokesSessions(t *testing.T) {
+	repo := &userRepoFake{users: map[string]domain.User{
+		"ana@example.com": {ID: "user-1", Name: "Ana", Email: "ana@example.com", Role: domain.RoleOperator, Active: true},
+	}}
+	sessions := &sessionStoreFake{}
+	service := NewUserService(repo, sessions, nil)
+
+	updated, err := service.SetActive(context.Background(), "user-1", false)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if updated.Active {
+		t.Fatal("expected user to be inactive")
+	}
+	if len(sessions.deletedUsers) != 1 || sessions.deletedUsers[0] != "user-1" {
+		t.Fatalf("expected sessions revoked for user-1, got %v", sessions.deletedUsers)
+	}
+
+	if _, err := service.SetActive(context.Background(), "user-1", true); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(sessions.deletedUsers) != 1 {
+		t.Fatalf("expected no revocation on activation, got %v", sessions.deletedUsers)
+	}
+}
+
+// Testa Update revogando sessoes quando o papel muda.
+func TestUserServiceUpdateRoleRevokesSessions(t *testing.T) {
+	repo := &userRepoFake{users: map[string]domain.User{
+		"ana@example.com": {ID: "user-1", Name: "Ana", Email: "ana@example.com", Role: domain.RoleAdmin, Active: true},
+		"bia@example.com": {ID: "user-2", Name: "Bia", Email: "bia@example.com", Role: domain.RoleAdmin, Active: true},
+	}}
+	sessions := &sessionStoreFake{}
+	service := NewUserService(repo, sessions, nil)
+
+	updated, err := service.Update(context.Background(), domain.User{ID: "user-1", Name: "Ana", Email: "ana@example.com", Role: domain.RoleAdmin, Active: true})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(sessions.deletedUsers) != 0 {
+		t.Fatalf("expected no revocation without changes, got %v", sessions.deletedUsers)
+	}
+
+	updated.Role = domain.RoleOperator
+	if _, err := service.Update(context.Background(), updated); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(sessions.deletedUsers) != 1 {
+		t.Fatalf("expected revocation after role change, got %v", sessions.deletedUsers)
+	}
+}
+
+// Testa que o administrador nao pode desativar nem rebaixar o proprio usuario.
+func TestUserServiceRejectsSelfLockout(t *testing.T) {
+	repo := &userRepoFake{users: map[string]domain.User{
+		"ana@example.com": {ID: "user-1", Name: "Ana", Email: "ana@example.com", Role: domain.RoleAdmin, Active: true},
+		"bia@example.com": {ID: "user-2", Name: "Bia", Email: "bia@example.com", Role: domain.RoleAdmin, Active: true},
+	}}
+	audit := &auditRepoFake{}
+	service := NewUserService(repo, &sessionStoreFake{}, audit)
+	ctx := auditctx.WithActor(context.Background(), auditctx.Actor{ID: "user-1", Role: "admin"})
+
+	if _, err := service.SetActive(ctx, "user-1", false); !errors.Is(err, domain.ErrSelfLockout) {
+		t.Fatalf("expected self lockout on deactivation, got %v", err)
+	}
+	demoted := domain.User{ID: "user-1", Name: "Ana", Email: "ana@example.com", Role: domain.RoleOperator, Active: true}
+	if _, err := service.Update(ctx, demoted); !errors.Is(err, domain.ErrSelfLockout) {
+		t.Fatalf("expected self lockout on demotion, got %v", err)
+	}
+	if user, _ := repo.FindByID(ctx, "user-1"); !user.IsActiveAdmin() {
+		t.Fatalf("expected user-1 to remain an active admin, got %#v", user)
+	}
+	if last := audit.events[len(audit.events)-1]; last.Action != "user.update.failure" {
+		t.Fatalf("expected failure audit event, got %s", last.Action)
+	}
+
+	if _, err := service.SetActive(ctx, "user-2", false); err != nil {
+		t.Fatalf("expected other admin to be deactivated, got %v", err)
+	}
+}
+
+// Testa que nenhuma alteracao pode deixar o sistema sem administrador ativo.
+func TestUserServiceRejectsRemovingLastAdmin(t *testing.T) {
+	repo := &userRepoFake{users: map[string]domain.User{
+		"ana@example.com":  {ID: "user-1", Name: "Ana", Email: "ana@example.com", Role: domain.RoleAdmin, Active: true},
+		"bia@example.com":  {ID: "user-2", Name: "Bia", Email: "bia@example.com", Role: domain.RoleAdmin, Active: false},
+		"caio@example.com": {ID: "user-3", Name: "Caio", Email: "caio@example.com", Role: domain.RoleOperator, Active: true},
+	}}
+	service := NewUserService(repo, &sessionStoreFake{}, nil)
+	ctx := auditctx.WithActor(context.Background(), auditctx.Actor{ID: "token-owner", Role: "admin"})
+
+	if _, err := service.SetActive(ctx, "user-1", false); !errors.Is(err, domain.ErrLastAdmin) {
+		t.Fatalf("expected last admin error on deactivation, got %v", err)
+	}
+	demoted := domain.User{ID: "user-1", Name: "Ana", Email: "ana@example.com", Role: domain.RoleOperator, Active: true}
+	if _, err := service.Update(ctx, demoted); !errors.Is(err, domain.ErrLastAdmin) {
+		t.Fatalf("expected last admin error on demotion, got %v", err)
+	}
+
+	if _, err := service.SetActive(ctx, "user-2", true); err != nil {
+		t.Fatalf("unexpected error activating admin: %v", err)
+	}
+	if _, err := service.Update(ctx, demoted); err != nil {
+		t.Fatalf("expected demotion with another active admin, got %v", err)
+	}
+}
+
+// Testa a verificacao do ultimo administrador rodando na mesma transacao
+// serializavel da gravacao, com rollback e sem auditoria de sucesso quando falha.
+func TestUserServiceChecksLastAdminInsideSerializableTx(t *testing.T) {
+	repo := &userRepoFake{users: map[string]domain.User{
+		"ana@example.com": {ID: "user-1", Name: "Ana", Email: "ana@example.com", Role: domain.RoleAdmin, Active: true},
+		"bia@example.com": {ID: "user-2", Name: "Bia", Email: "bia@example.com", Role: domain.RoleAdmin, Active: true},
+	}}
+	audit := &auditRepoFake{}
+	runner := &txRunnerFake{deps: ports.TxDependencies{Users: repo}, audit: audit}
+	service := NewUserService(repo, &sessionStoreFake{}, audit)
+	service.SetTxRunner(runner)
+	ctx := auditctx.WithActor(context.Background(), auditctx.Actor{ID: "token-owner", Role: "admin"})
+
+	if _, err := service.SetActive(ctx, "user-2", false); err != nil {
+		t.Fatalf("unexpected error deactivating admin: %v", err)
+	}
+	if runner.calls != 1 || runner.opts.Isolation != ports.TxSerializable {
+		t.Fatalf("expected one serializable tx, got %d calls with %+v", runner.calls, runner.opts)
+	}
+
+	events := len(audit.events)
+	demoted := domain.User{ID: "user-1", Name: "Ana", Email: "ana@example.com", Role: domain.RoleOperator, Active: true}
+	if _, err := service.Update(ctx, demoted); !errors.Is(err, domain.ErrLastAdmin) {
+		t.Fatalf("expected last admin error, got %v", err)
+	}
+	if runner.calls != 2 || runner.rollbacks != 1 {
+		t.Fatalf("expected rolled back tx, got %d calls and %d rollbacks", runner.calls, runner.rollbacks)
+	}
+	for _, action := range auditActions(audit)[events:] {
+		if action == "user.update.success" {
+			t.Fatal("unexpected success audit after rollback")
+		}
+	}
+	if repo.users["ana@example.com"].Role != domain.RoleAdmin {
+		t.Fatalf("expected role unchanged, got %s", repo.users["ana@example.com"].Role)
+	}
+}
+
+// Testa ResetPassword atualizando hash e revogando sessoes.
+func TestUserServiceResetPassword(t *testing.T) {
+	repo := &userRepoFake{users: map[string]domain.User{
+		"ana@example.com": {ID: "user-1", Name: "Ana", Email: "ana@example.com", Role: domain.RoleOperator, Active: true},
+	}}
+	sessions := &sessionStoreFake{}
+	service := NewUserService(repo, sessions, nil)
+
+	if err := service.ResetPassword(context.Background(), "user-1", "123"); err == nil {
+		t.Fatal("expected error for short password")
+	}
+	if err := service.ResetPassword(context.Background(), "user-1", "novasenha1"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	user := repo.users["ana@example.com"]
+	if err := bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte("novasenha1")); err != nil {
+		t.Fatalf("expected password updated, got %v", err)
+	}
+	if len(sessions.deletedUsers) != 1 {
+		t.Fatalf("expected sessions revoked, got %v", sessions.deletedUsers)
+	}
+}
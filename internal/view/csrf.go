@@ -0,0 +1,24 @@
+package view
+
+import (
+	"context"
+	"encoding/json"
+)
+
+type csrfTokenKey struct{}
+
+func WithCSRFToken(ctx context.Context, token string) context.Context {
+	return context.WithValue(ctx, csrfTokenKey{}, token)
+}
+
+func CSRFToken(ctx context.Context) string {
+	token, _ := ctx.Value(csrfTokenKey{}).(string)
+	return token
+}
+
+// csrfHeaders monta o valor de hx-headers para que o HTMX envie o token em
+// todas as requisicoes.
+func csrfHeaders(token string) string {
+	payload, _ := json.Marshal(map[string]string{"X-CSRF-Token": token})
+	return string(payload)
+}
@@ -14,9 +14,13 @@ type Page struct {
 }
 
 type UserInfo struct {
-	Name        string
-	DisplayName string
-	Role        string
+	Name               string
+	DisplayName        string
+	Role               string
+	CanManageUsers     bool
+	CanViewAudit       bool
+	CanManageAPITokens bool
+	CanManageCoupons   bool
 }
 
 type PlanItem struct {
@@ -24,11 +28,15 @@ type PlanItem struct {
 	Name         string
 	DurationDays int
 	Price        string
+	Installments string
 	Description  string
 }
 
 type PlansPageData struct {
-	Items []PlanItem
+	Items     []PlanItem
+	CanCreate bool
+	CanUpdate bool
+	CanDelete bool
 }
 
 type PlanFormData struct {
@@ -41,6 +49,10 @@ type PlanFormData struct {
 	DurationDays string
 	Price        string
 	Description  string
+	LateFee      string
+	LateInterest string
+	MaxFreeze    string
+	Installments string
 	Active       bool
 	Error        string
 }
@@ -83,6 +95,7 @@ type StudentFormData struct {
 	Action         string
 	SubmitLabel    string
 	DeleteAction   string
+	HistoryURL     string
 	ShowDelete     bool
 	FullName       string
 	BirthDate      string
@@ -134,6 +147,7 @@ type SubscriptionFormData struct {
 	Action         string
 	SubmitLabel    string
 	DeleteAction   string
+	HistoryURL     string
 	ShowDelete     bool
 	DisableSelects bool
 	StudentID      string
@@ -144,11 +158,67 @@ type SubscriptionFormData struct {
 	AutoRenew      bool
 	Status         string
 	Price          string
+	CouponCode     string
+	Discount       string
+	PreviousURL    string
 	Students       []StudentOption
 	Plans          []PlanOption
+	BillingPeriods SubscriptionBillingPeriodsData
+	ChangePlan     SubscriptionChangePlanData
+	Freezes        SubscriptionFreezesData
+	Error          string
+}
+
+// SubscriptionChangePlanData alimenta o formulario de troca de plano, exibido
+// apenas para assinaturas ativas.
+type SubscriptionChangePlanData struct {
+	SubscriptionID string
+	CurrentPlanID  string
+	Plans          []PlanOption
+	Error          string
+}
+
+type SubscriptionFreezeItem struct {
+	Period string
+	Days   int
+	Reason string
+}
+
+// SubscriptionFreezesData alimenta a lista de trancamentos da assinatura. O
+// formulario aparece apenas quando CanFreeze e verdadeiro.
+type SubscriptionFreezesData struct {
+	SubscriptionID string
+	MaxDays        int
+	UsedDays       int
+	Year           int
+	CanFreeze      bool
+	Items          []SubscriptionFreezeItem
+	StartDate      string
+	EndDate        string
+	Reason         string
 	Error          string
 }
 
+type BillingPeriodItem struct {
+	ID          string
+	Period      string
+	Installment string
+	AmountDue   string
+	Discount    string
+	Fee         string
+	AmountPaid  string
+	Outstanding string
+	StatusLabel string
+	StatusClass string
+	FeeWaived   bool
+	CanWaive    bool
+}
+
+type SubscriptionBillingPeriodsData struct {
+	SubscriptionID string
+	Items          []BillingPeriodItem
+}
+
 type SubscriptionOption struct {
 	ID    string
 	Label string
@@ -175,6 +245,7 @@ type PaymentsPageData struct {
 	Status         string
 	Subscriptions  []SubscriptionOption
 	Items          []PaymentItem
+	CanReverse     bool
 }
 
 type PaymentFormData struct {
@@ -182,6 +253,7 @@ type PaymentFormData struct {
 	Action         string
 	SubmitLabel    string
 	DeleteAction   string
+	HistoryURL     string
 	ShowDelete     bool
 	SubscriptionID string
 	PaidAt         string
@@ -199,7 +271,261 @@ type StudentsPreviewData struct {
 	Items []StudentItem
 }
 
+type ReportRevenueItem struct {
+	Label       string
+	Amount      string
+	GrossBilled string
+	Discount    string
+	NetBilled   string
+}
+
+type ReportStatusItem struct {
+	Status  string
+	Label   string
+	Total   int64
+	Percent string
+}
+
+type ReportSubscriptionItem struct {
+	ID          string
+	StudentName string
+	PlanName    string
+	EndDate     string
+	DaysOverdue int
+}
+
+type ReportsPageData struct {
+	Period          string
+	DueDays         int
+	Revenue         string
+	RevenueLabel    string
+	RevenueSeries   []ReportRevenueItem
+	ActiveRate      string
+	DelinquencyRate string
+	StatusItems     []ReportStatusItem
+	Delinquents     []ReportSubscriptionItem
+	Upcoming        []ReportSubscriptionItem
+}
+
+type HomePageData struct {
+	ActiveStudents          int64
+	DelinquentSubscriptions int64
+	RevenueMonth            string
+	ProjectedRevenue        string
+	Error                   string
+}
+
+type UserItem struct {
+	ID          string
+	Name        string
+	Email       string
+	RoleLabel   string
+	Active      bool
+	StatusLabel string
+	StatusClass string
+}
+
+type UsersPageData struct {
+	Items []UserItem
+	Error string
+}
+
+type UserFormData struct {
+	Title              string
+	Action             string
+	SubmitLabel        string
+	IsNew              bool
+	Name               string
+	Email              string
+	Role               string
+	Active             bool
+	MustChangePassword bool
+	Error              string
+}
+
+type APITokenItem struct {
+	ID          string
+	Name        string
+	Prefix      string
+	User        string
+	Scopes      []string
+	CreatedAt   string
+	ExpiresAt   string
+	LastUsedAt  string
+	Active      bool
+	StatusLabel string
+	StatusClass string
+}
+
+type APITokensPageData struct {
+	Items []APITokenItem
+	Error string
+}
+
+type APITokenUserOption struct {
+	ID    string
+	Label string
+}
+
+type APITokenScopeField struct {
+	Resource string
+	Label    string
+	Access   string
+}
+
+type APITokenFormData struct {
+	Name      string
+	UserID    string
+	ExpiresAt string
+	Users     []APITokenUserOption
+	Scopes    []APITokenScopeField
+	Error     string
+}
+
+type APITokenCreatedData struct {
+	Name   string
+	User   string
+	Secret string
+}
+
+type SessionItem struct {
+	ID        string
+	Device    string
+	IP        string
+	CreatedAt string
+	LastSeen  string
+	Current   bool
+}
+
+type SessionsPageData struct {
+	Items []SessionItem
+	Error string
+}
+
 type LoginData struct {
+	Email  string
+	Error  string
+	Notice string
+}
+
+type PasswordChangeData struct {
+	Forced  bool
+	Error   string
+	Success string
+}
+
+type ForgotPasswordData struct {
 	Email string
 	Error string
+	Sent  bool
+}
+
+type ResetPasswordData struct {
+	Token string
+	Error string
+}
+
+type MFAChallengeData struct {
+	Enroll          bool
+	Secret          string
+	ProvisioningURI string
+	Error           string
+}
+
+type MFARecoveryCodesData struct {
+	Codes       []string
+	ContinueURL string
+}
+
+type MFASettingsData struct {
+	Enabled           bool
+	Required          bool
+	RecoveryCodesLeft int
+	Secret            string
+	ProvisioningURI   string
+	RecoveryCodes     []string
+	Error             string
+	Success           string
+}
+
+type AuditOption struct {
+	ID    string
+	Label string
+}
+
+type AuditMetadataItem struct {
+	Key   string
+	Value string
+}
+
+type AuditEventItem struct {
+	ID          string
+	Action      string
+	ActionClass string
+	Actor       string
+	ActorRole   string
+	EntityType  string
+	EntityID    string
+	CreatedAt   string
+	IP          string
+	Metadata    []AuditMetadataItem
+}
+
+type AuditPageData struct {
+	ActorID     string
+	EntityType  string
+	EntityID    string
+	Action      string
+	From        string
+	To          string
+	Actors      []AuditOption
+	EntityTypes []AuditOption
+	Items       []AuditEventItem
+	Page        int
+	TotalPages  int
+	TotalItems  int
+	Error       string
+}
+
+type AuditTimelineData struct {
+	Items []AuditEventItem
+	Error string
+}
+
+type StudentAccessItem struct {
+	Actor     string
+	ActorRole string
+	Views     int
+	Sources   string
+	FirstAt   string
+	LastAt    string
+}
+
+type StudentAccessData struct {
+	Items []StudentAccessItem
+	Error string
+}
+
+type CouponItem struct {
+	ID          string
+	Code        string
+	Description string
+	Discount    string
+	CreatedAt   string
+	Active      bool
+}
+
+type CouponsPageData struct {
+	Items []CouponItem
+	Error string
+}
+
+type CouponFormData struct {
+	Code        string
+	Description string
+	Kind        string
+	Value       string
+	Duration    string
+	Periods     string
+	Error       string
 }
@@ -1,6 +1,10 @@
 package view
 
 func studentsFilterChipClass(current, value string) string {
+	return filterChipClass(current, value)
+}
+
+func filterChipClass(current, value string) string {
 	base := "status-chip inline-flex items-center gap-2 rounded-lg border px-3 py-1 text-xs font-semibold transition focus-visible:outline-none focus-visible:ring-2 focus-visible:ring-blue-500/40"
 	active := "border-blue-400/80 bg-blue-500/15 text-blue-100 shadow-[0_0_0_1px_rgba(59,130,246,0.35)]"
 	neutral := "border-slate-800 bg-slate-950/60 text-slate-300 hover:border-blue-400/50 hover:text-white"
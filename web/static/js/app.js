@@ -149,10 +149,12 @@
 
     const topics = new Set();
     document.querySelectorAll("[data-sse-topic]").forEach((el) => {
-      const topic = el.dataset ? el.dataset.sseTopic : "";
-      if (topic) {
-        topics.add(topic);
-      }
+      const value = el.dataset ? el.dataset.sseTopic : "";
+      value.split(/\s+/).forEach((topic) => {
+        if (topic) {
+          topics.add(topic);
+        }
+      });
     });
 
     if (topics.size === 0) {
@@ -175,14 +177,27 @@
         if (data && data.origin_id && data.origin_id === originId) {
           return;
         }
-        document.querySelectorAll(`[data-sse-topic="${topic}"]`).forEach((el) => {
+        document.querySelectorAll(`[data-sse-topic~="${topic}"]`).forEach((el) => {
           refreshElement(el);
         });
       });
     });
   }
 
+  function renderQRCode(el) {
+    const value = el && el.dataset ? el.dataset.qrValue : "";
+    if (!value || typeof window.qrcode !== "function") {
+      return;
+    }
+    const qr = window.qrcode(0, "M");
+    qr.addData(value);
+    qr.make();
+    el.innerHTML = qr.createSvgTag({ cellSize: 4, margin: 0 });
+  }
+
   window.photoUpload = photoUpload;
+  window.renderQRCode = renderQRCode;
+  document.querySelectorAll("[data-qr-value]").forEach(renderQRCode);
   const originId = getOriginId();
   setupOriginId(originId);
   setupSSE(originId);